@@ -3,19 +3,23 @@ package main
 import (
 	"fmt"
 	"log"
+	"net/http"
+	"time"
 
 	// Added by instruction
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin" // Added by instruction
 	"golang.org/x/time/rate"
 
-	// 	"github.com/lorengraff/crypto-tower-defense/internal/blockchain"
+	"github.com/lorengraff/crypto-tower-defense/internal/blockchain"
 	"github.com/lorengraff/crypto-tower-defense/internal/db"
 	"github.com/lorengraff/crypto-tower-defense/internal/handlers"
 	"github.com/lorengraff/crypto-tower-defense/internal/middleware"
+	"github.com/lorengraff/crypto-tower-defense/internal/repository"
 	"github.com/lorengraff/crypto-tower-defense/internal/services"
 	"github.com/lorengraff/crypto-tower-defense/pkg/config"
 	"github.com/lorengraff/crypto-tower-defense/pkg/logger"
+	"github.com/lorengraff/crypto-tower-defense/pkg/metrics"
 )
 
 func main() {
@@ -37,13 +41,20 @@ func main() {
 	defer db.Close()
 
 	// Run migrations
-	if err := db.Migrate(); err != nil {
+	if err := db.Migrate(cfg.MigrateOnStart); err != nil {
 		logger.Error(fmt.Sprintf("Failed to run migrations: %v", err))
 		log.Fatal(err)
 	}
 
+	// Seed game content (abilities, missions, islands, shop, ...)
+	if err := db.SeedContent(cfg.ContentProfile); err != nil {
+		logger.Warning(fmt.Sprintf("Failed to seed %s content: %v", cfg.ContentProfile, err))
+	}
+
 	// Initialize Gin router
-	router := gin.Default()
+	router := gin.New()
+	router.Use(gin.Recovery())
+	router.Use(middleware.RequestID())
 
 	// CORS configuration
 	corsConfig := cors.DefaultConfig()
@@ -70,12 +81,32 @@ func main() {
 		})
 	})
 
+	// Prometheus scrape endpoint, served on its own internal listener rather than the public router
+	metrics.MustRegister(services.NewLedgerCollector(30 * time.Second))
+	go func() {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Handler())
+		logger.Info(fmt.Sprintf("Metrics listening on %s", cfg.MetricsAddr))
+		if err := http.ListenAndServe(cfg.MetricsAddr, mux); err != nil {
+			logger.Error(fmt.Sprintf("Metrics listener stopped: %v", err))
+		}
+	}()
+
+	// Enterprise Services (wired once; handlers and services receive their dependencies)
+	store := repository.NewGormStore(db.DB)
+	ledgerService := services.NewLedgerService(store)
+	configService := services.GetConfigService()
+	adminService := services.NewAdminService(ledgerService)
+	referralService := services.NewReferralService(ledgerService, adminService)
+	questService := services.NewDailyQuestService(ledgerService)
+	battleService := services.NewBattleService(store, ledgerService, services.NewSkillActivationService(), services.NewStatusEffectService())
+
 	// Initialize Handlers
-	authHandler := handlers.NewAuthHandler(cfg)
+	authHandler := handlers.NewAuthHandler(cfg, referralService)
 	characterHandler := handlers.NewCharacterHandler()
 	missionHandler := handlers.NewMissionHandler(db.DB) // Fixed Signature
 	storyHandler := handlers.NewStoryHandler()
-	itemHandler := handlers.NewItemHandler()
+	itemHandler := handlers.NewItemHandler(services.NewEquipmentService(store, ledgerService, configService))
 	// battleHandler := handlers.NewBattleHandler() // Legacy, using battleEngine in other handlers
 	progressionHandler := handlers.NewProgressionHandler()
 
@@ -84,22 +115,11 @@ func main() {
 
 	// Initialize Revenue service
 	sqlDB, _ := db.DB.DB()
-	revenueService := services.NewRevenueService(sqlDB)
+	revenueService := services.NewRevenueService(sqlDB, ledgerService)
 	revenueHandler := handlers.NewRevenueHandler(revenueService)
 
-	// Enterprise Services
-	ledgerService := services.NewLedgerService()
-	configService := services.GetConfigService()
-	adminService := services.NewAdminService()
-
 	adminHandler := handlers.NewAdminHandler(adminService, configService)
 
-	// Inject Ledger into Revenue (TODO: refactor RevenueService to accept Ledger)
-	_ = ledgerService
-	_ = configService
-	_ = adminService
-	_ = adminHandler // Prevent unused error
-
 	// Blockchain Service (Fail safe)
 	blockchainService, err := services.NewBlockchainService(cfg)
 	if err != nil {
@@ -110,13 +130,60 @@ func main() {
 	}
 
 	// Initialize ShopService with Blockchain dependencies
-	shopService := services.NewShopService(blockchainService)
+	shopService := services.NewShopService(blockchainService, ledgerService)
+
+	// Tournament Service (scheduler closes registration, applies walkovers, advances rounds)
+	tournamentService := services.NewTournamentService(ledgerService, battleService, adminService)
+	tournamentService.StartScheduler(1 * time.Minute)
+	tournamentHandler := handlers.NewTournamentHandler(tournamentService)
+
+	// Guild Service (scheduler settles expired guild raids)
+	guildService := services.NewGuildService(ledgerService, battleService)
+	guildService.StartScheduler(5 * time.Minute)
+	guildHandler := handlers.NewGuildHandler(guildService)
+
+	// Withdrawal worker: signs TOWER payouts with the minter key (MINTER_PRIVATE_KEY, TOWER_TOKEN_ADDRESS)
+	var withdrawalChain services.WithdrawalChain
+	if chainClient, err := blockchain.NewClient(cfg.OpBNBTestnetRPC); err != nil {
+		log.Printf("⚠️ WARNING: Withdrawal signer unavailable: %v (withdrawals will stay queued)", err)
+	} else if transfers, err := chainClient.TransferManager(); err != nil {
+		log.Printf("⚠️ WARNING: Withdrawal signer unavailable: %v (withdrawals will stay queued)", err)
+	} else {
+		withdrawalChain = transfers
+	}
+	withdrawalService := services.NewWithdrawalService(store, withdrawalChain, configService, &services.NotificationService{}, adminService)
+	withdrawalService.StartScheduler(30 * time.Second)
+
+	// Marketplace Service (scheduler expires listings/offers and settles auctions)
+	marketplaceService := services.NewMarketplaceService(store, ledgerService, configService, &services.NotificationService{})
+	marketplaceService.StartScheduler(1 * time.Minute)
+
+	// Rental Service (scheduler returns rented characters when their term ends)
+	rentalService := services.NewRentalService(store, ledgerService, configService, &services.NotificationService{})
+	rentalService.StartScheduler(1 * time.Minute)
+
+	// Trade Service (direct player trades; settled trades go through the anti-cheat RMT review)
+	tradeService := services.NewTradeService(store, ledgerService, configService, &services.NotificationService{}, services.NewAntiCheatService(sqlDB))
+	tradeService.StartScheduler(1 * time.Minute)
 
-	// Inject Ledger into Revenue (TODO: refactor RevenueService to accept Ledger)
-	_ = ledgerService
-	_ = configService
-	_ = adminService
-	_ = adminHandler // Prevent unused error
+	// Login Reward Service (daily calendar claims; admin edits go to the audit log)
+	loginRewardService := services.NewLoginRewardService(store, ledgerService, configService, &services.NotificationService{}, adminService)
+	loginRewardHandler := handlers.NewLoginRewardHandler(loginRewardService)
+
+	// Friend Service (scheduler expires unanswered challenges and refunds stakes)
+	friendService := services.NewFriendService(ledgerService, battleService)
+	friendService.StartScheduler(1 * time.Minute)
+	friendHandler := handlers.NewFriendHandler(friendService)
+
+	// Sprite Worker (renders queued character sprite sheets, served from /sprites)
+	spriteProvider, err := services.NewSpriteProvider(cfg.SpriteProvider)
+	if err != nil {
+		log.Printf("⚠️ WARNING: %v", err)
+	}
+	spriteService := services.NewSpriteJobService(spriteProvider, cfg.SpriteOutputDir, cfg.SpriteBaseURL)
+	spriteService.StartScheduler(15 * time.Second)
+	spriteHandler := handlers.NewSpriteHandler(spriteService)
+	router.Static("/sprites", cfg.SpriteOutputDir)
 
 	// API v1 routes group
 	v1 := router.Group("/api/v1")
@@ -131,12 +198,17 @@ func main() {
 			c.JSON(200, gin.H{"status": "pong", "v1": "active"})
 		})
 
+		// NFT metadata is public: marketplaces and wallets read it without a session
+		provenanceHandler := handlers.NewProvenanceHandler(services.NewProvenanceService(store))
+		v1.GET("/nft/metadata/:id", provenanceHandler.GetMetadata)
+
 		// Public auth routes with strict rate limiting
 		authRoutes := v1.Group("/auth")
 		authRoutes.Use(middleware.StrictRateLimiter()) // 10 req/min
 		{
 			authRoutes.POST("/nonce", authHandler.GetNonce)
 			authRoutes.POST("/verify", authHandler.VerifySignature)
+			authRoutes.POST("/refresh", authHandler.RefreshToken)
 		}
 
 		// Protected routes (require authentication)
@@ -147,13 +219,22 @@ func main() {
 		{
 			// Auth
 			protected.GET("/auth/profile", authHandler.GetProfile)
+			protected.POST("/auth/logout", authHandler.Logout)
+			protected.POST("/auth/logout-all", authHandler.LogoutAll)
+			protected.GET("/auth/sessions", authHandler.ListSessions)
+			protected.DELETE("/auth/sessions/:id", authHandler.RevokeSession)
 
 			// Character routes
 			protected.GET("/characters", characterHandler.ListCharacters)
 			protected.GET("/characters/:id", characterHandler.GetCharacter)
 			protected.POST("/characters", characterHandler.CreateCharacter)
 			protected.POST("/characters/:id/hatch", characterHandler.HatchEgg)
-			protected.POST("/characters/:id/recover", characterHandler.RecoverFatigue)
+
+			// Durability, fatigue and revives
+			conditionHandler := handlers.NewConditionHandler(services.NewConditionService(store, ledgerService, services.GetConfigService()))
+			protected.GET("/characters/:id/condition", conditionHandler.GetCondition)
+			protected.POST("/characters/:id/repair", conditionHandler.Repair)
+			protected.POST("/characters/:id/revive", conditionHandler.Revive)
 
 			// Progression routes
 			protected.POST("/characters/:id/gain-xp", progressionHandler.GainXP)
@@ -182,6 +263,8 @@ func main() {
 			// Status Effect routes
 			statusEffectHandler := handlers.NewStatusEffectHandler()
 			protected.GET("/characters/:id/effects", statusEffectHandler.GetCharacterEffects)
+			protected.GET("/characters/:id/sprites", spriteHandler.GetSpriteStatus)
+			protected.GET("/characters/:id/provenance", provenanceHandler.GetTimeline)
 			protected.GET("/effects/definitions", statusEffectHandler.GetAllEffectDefinitions)
 
 			// Team routes
@@ -194,7 +277,7 @@ func main() {
 
 			// Raid routes - NEW secure implementation
 			// Raid routes - NEW secure implementation
-			raidService := services.NewRaidService()
+			raidService := services.NewRaidService(ledgerService)
 			raidHandler := handlers.NewRaidHandler(raidService)
 			raidTurnHandler := handlers.NewRaidTurnHandler(raidService)
 
@@ -220,7 +303,7 @@ func main() {
 			protected.GET("/leaderboard", leaderboardHandler.GetLeaderboard)
 
 			// Wager Battle Endpoints (NEW - Real GTK)
-			wagerHandler := handlers.NewWagerHandler()
+			wagerHandler := handlers.NewWagerHandler(battleService)
 			wager := protected.Group("/battle")
 			wager.GET("/wager-preview", handlers.GetWagerPreview)
 
@@ -256,9 +339,22 @@ func main() {
 			protected.POST("/items/:id/equip", itemHandler.EquipItem)
 			protected.POST("/items/:id/unequip", itemHandler.UnequipItem)
 			protected.POST("/items/:id/use", itemHandler.UseItem)
+			protected.POST("/items/:id/enhance", itemHandler.EnhanceItem)
+			protected.POST("/items/:id/socket", itemHandler.SocketRune)
+			protected.POST("/items/:id/unsocket", itemHandler.UnsocketRune)
+			protected.GET("/characters/:id/equipment", itemHandler.GetLoadout)
+
+			// Crafting routes (unlocked by the level 10 mission)
+			craftingService := services.NewCraftingService(store, ledgerService, configService, services.NewMissionService(db.DB))
+			craftingHandler := handlers.NewCraftingHandler(craftingService)
+			protected.GET("/crafting/recipes", craftingHandler.ListRecipes)
+			protected.GET("/crafting/jobs", craftingHandler.ListJobs)
+			protected.POST("/crafting/recipes/:id/craft", craftingHandler.StartCraft)
+			protected.POST("/crafting/jobs/:id/claim", craftingHandler.ClaimCraft)
+			protected.POST("/items/:id/salvage", craftingHandler.Salvage)
 
 			// Battle routes
-			battleHandler := handlers.NewBattleHandler() // NEW
+			battleHandler := handlers.NewBattleHandler(battleService) // NEW
 			// Standard Battle Management
 			protected.POST("/battles/matchmaking", battleHandler.FindMatch)
 			protected.POST("/battles", battleHandler.CreateBattle)
@@ -282,19 +378,53 @@ func main() {
 			// protected.POST("/game-modes/wager/create", gameModeHandler.CreateWagerBattle)
 
 			// Breeding Routes
-			breedingService := services.NewBreedingService(blockchainService)
+			breedingService := services.NewBreedingService(store, blockchainService, ledgerService, configService)
 			breedingHandler := handlers.NewBreedingHandler(breedingService)
 			protected.POST("/breeding/start", breedingHandler.StartBreeding)
 			protected.GET("/breeding/eggs", breedingHandler.GetUserEggs)
 			protected.POST("/breeding/incubate/:id", breedingHandler.StartIncubation)
 			protected.POST("/breeding/hatch/:id", breedingHandler.HatchEgg)
+			protected.GET("/breeding/preview", breedingHandler.PreviewBreeding)
+			protected.GET("/breeding/lineage/:id", breedingHandler.GetLineage)
+
+			// Rentals: lend characters for a fee and/or a share of what they earn
+			rentalHandler := handlers.NewRentalHandler(rentalService)
+			protected.GET("/rentals", rentalHandler.ListRentals)
+			protected.GET("/rentals/offers", rentalHandler.ListOffers)
+			protected.POST("/rentals/offers", rentalHandler.OfferRental)
+			protected.POST("/rentals/offers/:id/accept", rentalHandler.AcceptRental)
+			protected.POST("/rentals/offers/:id/cancel", rentalHandler.CancelOffer)
+			protected.POST("/rentals/:id/return", rentalHandler.ReturnRental)
+
+			// Trades: two-sided swaps of characters, items, eggs and GTK
+			tradeHandler := handlers.NewTradeHandler(tradeService)
+			protected.GET("/trades", tradeHandler.ListTrades)
+			protected.GET("/trades/:id", tradeHandler.GetTrade)
+			protected.POST("/trades", tradeHandler.OpenTrade)
+			protected.PUT("/trades/:id/offer", tradeHandler.SetOffer)
+			protected.POST("/trades/:id/confirm", tradeHandler.Confirm)
+			protected.POST("/trades/:id/cancel", tradeHandler.Cancel)
+
+			// Daily login calendar
+			protected.GET("/login-rewards", loginRewardHandler.GetCalendar)
+			protected.POST("/login-rewards/claim", loginRewardHandler.Claim)
+			protected.PUT("/login-rewards/timezone", loginRewardHandler.SetTimezone)
 
 			// Marketplace (Phase 19)
-			marketplaceHandler := handlers.NewMarketplaceHandler()
+			marketplaceHandler := handlers.NewMarketplaceHandler(marketplaceService)
 			protected.GET("/marketplace", marketplaceHandler.GetListings)
 			protected.POST("/marketplace/list", marketplaceHandler.CreateListing)
+			protected.POST("/marketplace/auctions", marketplaceHandler.CreateAuction)
+			protected.POST("/marketplace/bundles", marketplaceHandler.CreateBundle)
 			protected.POST("/marketplace/:id/buy", marketplaceHandler.BuyListing)
+			protected.POST("/marketplace/:id/bid", marketplaceHandler.PlaceBid)
+			protected.GET("/marketplace/:id/bids", marketplaceHandler.GetBids)
 			protected.DELETE("/marketplace/:id", marketplaceHandler.CancelListing)
+			protected.GET("/marketplace/offers", marketplaceHandler.ListOffers)
+			protected.POST("/marketplace/offers", marketplaceHandler.MakeOffer)
+			protected.POST("/marketplace/offers/:id/accept", marketplaceHandler.AcceptOffer)
+			protected.POST("/marketplace/offers/:id/reject", marketplaceHandler.RejectOffer)
+			protected.POST("/marketplace/offers/:id/cancel", marketplaceHandler.CancelOffer)
 
 			// Inventory (Phase 16)
 			inventoryHandler := handlers.NewInventoryHandler()
@@ -310,6 +440,8 @@ func main() {
 			protected.POST("/economy/deposit", economyHandler.DepositTower)
 			protected.GET("/economy/balance", economyHandler.GetBalance)
 			protected.GET("/economy/history", economyHandler.GetTransactionHistory)
+			withdrawalHandler := handlers.NewWithdrawalHandler(withdrawalService)
+			protected.GET("/economy/withdrawals", withdrawalHandler.GetMyWithdrawals)
 
 			// NFT routes (Character minting)
 			nftHandler, err := handlers.NewNFTHandler(cfg)
@@ -326,8 +458,9 @@ func main() {
 
 			// Gacha routes
 			// blockchainService is passed (may be nil if init failed, handled gracefully in service)
-			gachaHandler := handlers.NewGachaHandler(blockchainService, nil)
+			gachaHandler := handlers.NewGachaHandler(blockchainService, services.NewGachaService(blockchainService, ledgerService), questService)
 			protected.POST("/gacha/mint", gachaHandler.MintEgg)
+			protected.POST("/gacha/redeem-ticket", gachaHandler.RedeemEggTicket)
 			protected.GET("/gacha/odds/:amount", gachaHandler.GetOddsPreview)
 			protected.GET("/gacha/my-eggs", gachaHandler.GetMyEggs)
 			protected.POST("/gacha/start-incubation/:id", gachaHandler.StartIncubation)
@@ -335,6 +468,11 @@ func main() {
 			protected.POST("/gacha/scan-egg/:id", gachaHandler.ScanEgg)
 			protected.POST("/gacha/apply-accelerator", gachaHandler.ApplyAccelerator)
 
+			// Egg care (owner or friends, during each window of incubation)
+			eggCareHandler := handlers.NewEggCareHandler(services.NewEggCareService(store, ledgerService, configService, friendService, &services.NotificationService{}))
+			protected.GET("/eggs/:id/care", eggCareHandler.GetCare)
+			protected.POST("/eggs/:id/care", eggCareHandler.PerformCare)
+
 			// Shop routes (Item shop system)
 			shopHandler := handlers.NewShopHandler(shopService) // Modified constructor
 			protected.GET("/shop/items", shopHandler.GetShopItems)
@@ -343,8 +481,77 @@ func main() {
 			protected.GET("/shop/inventory", shopHandler.GetInventory)
 			protected.POST("/shop/use", shopHandler.UseItem)
 
+			// Tournaments
+			protected.GET("/tournaments", tournamentHandler.ListTournaments)
+			protected.GET("/tournaments/:id", tournamentHandler.GetTournament)
+			protected.GET("/tournaments/:id/standings", tournamentHandler.GetStandings)
+			protected.POST("/tournaments/:id/register", tournamentHandler.Register)
+			protected.DELETE("/tournaments/:id/register", tournamentHandler.Unregister)
+
+			// Guilds
+			guilds := protected.Group("/guilds")
+			{
+				guilds.POST("", guildHandler.CreateGuild)
+				guilds.GET("/me", guildHandler.GetMyGuild)
+				guilds.GET("/leaderboard", guildHandler.GetLeaderboard)
+				guilds.GET("/:id", guildHandler.GetGuild)
+				guilds.POST("/leave", guildHandler.LeaveGuild)
+
+				guilds.GET("/invites", guildHandler.GetInvites)
+				guilds.POST("/invites", guildHandler.InviteMember)
+				guilds.POST("/invites/:id/respond", guildHandler.RespondInvite)
+
+				guilds.DELETE("/members/:userId", guildHandler.KickMember)
+				guilds.PUT("/members/:userId/role", guildHandler.SetMemberRole)
+
+				guilds.GET("/chat", guildHandler.GetChat)
+				guilds.POST("/chat", guildHandler.PostChat)
+
+				guilds.POST("/treasury/deposit", guildHandler.Deposit)
+				guilds.GET("/treasury/withdrawals", guildHandler.ListWithdrawals)
+				guilds.POST("/treasury/withdrawals", guildHandler.RequestWithdrawal)
+				guilds.POST("/treasury/withdrawals/:id/approve", guildHandler.ApproveWithdrawal)
+				guilds.POST("/treasury/withdrawals/:id/reject", guildHandler.RejectWithdrawal)
+				guilds.PUT("/treasury/policy", guildHandler.UpdateTreasuryPolicy)
+
+				guilds.POST("/raids", guildHandler.StartRaid)
+				guilds.GET("/raids/current", guildHandler.GetRaid)
+				guilds.POST("/raids/attack", guildHandler.AttackRaid)
+			}
+
+			// Friends & Presence
+			protected.PUT("/user/display-name", friendHandler.SetDisplayName)
+			friends := protected.Group("/friends")
+			{
+				friends.GET("", friendHandler.ListFriends)
+				friends.GET("/search", friendHandler.SearchUsers)
+				friends.POST("/requests", friendHandler.SendRequest)
+				friends.POST("/requests/:id/accept", friendHandler.AcceptRequest)
+				friends.POST("/requests/:id/decline", friendHandler.DeclineRequest)
+				friends.DELETE("/:userId", friendHandler.RemoveFriend)
+				friends.POST("/:userId/block", friendHandler.BlockUser)
+				friends.DELETE("/:userId/block", friendHandler.UnblockUser)
+			}
+
+			// Direct Challenges
+			protected.GET("/challenges", friendHandler.ListChallenges)
+			protected.POST("/challenges", friendHandler.SendChallenge)
+			protected.POST("/challenges/:id/accept", friendHandler.AcceptChallenge)
+			protected.POST("/challenges/:id/decline", friendHandler.DeclineChallenge)
+			protected.POST("/challenges/:id/cancel", friendHandler.CancelChallenge)
+
+			// Referrals
+			referralHandler := handlers.NewReferralHandler(referralService)
+			protected.GET("/referrals", referralHandler.GetDashboard)
+			protected.GET("/referrals/code", referralHandler.GetCode)
+			protected.POST("/referrals/claim", referralHandler.ClaimRewards)
+
+			// Notifications
+			protected.GET("/notifications", friendHandler.GetNotifications)
+			protected.POST("/notifications/:id/read", friendHandler.MarkNotificationRead)
+
 			// Daily Quests
-			questHandler := handlers.NewDailyQuestHandler()
+			questHandler := handlers.NewDailyQuestHandler(questService)
 			protected.GET("/daily-quests", questHandler.GetDailyQuests)
 			protected.POST("/daily-quests/claim/:id", questHandler.ClaimQuestReward)
 			protected.POST("/daily-quests/refresh", questHandler.RefreshQuests) // Admin only
@@ -362,6 +569,7 @@ func main() {
 				adminGroup.POST("/freeze-funds", adminHandler.FreezeFunds)
 				adminGroup.GET("/users", adminHandler.ListUsers)
 				adminGroup.GET("/audit-logs", adminHandler.GetAuditLogs)
+				adminGroup.POST("/characters/:id/repair", progressionHandler.RepairCharacter)
 
 				// System Configuration
 				adminGroup.GET("/settings", adminHandler.GetSettings)
@@ -390,6 +598,29 @@ func main() {
 				adminGroup.GET("/battles/active", adminHandler.GetActiveBattles)
 				adminGroup.GET("/battles/history", adminHandler.GetBattleHistory)
 				adminGroup.POST("/battles/:id/terminate", adminHandler.TerminateBattle)
+
+				// Tournament Management
+				adminGroup.POST("/admin-tournaments", tournamentHandler.CreateTournament)
+				adminGroup.POST("/admin-tournaments/:id/start", tournamentHandler.StartTournament)
+				adminGroup.POST("/admin-tournaments/:id/advance", tournamentHandler.AdvanceTournament)
+				adminGroup.POST("/admin-tournaments/:id/cancel", tournamentHandler.CancelTournament)
+
+				// Referral Review
+				adminReferralHandler := handlers.NewReferralHandler(referralService)
+				adminGroup.GET("/admin-referrals/flagged", adminReferralHandler.ListFlagged)
+				adminGroup.POST("/admin-referrals/:id/review", adminReferralHandler.ReviewReferral)
+
+				// On-chain withdrawals (second approval above withdrawal_approval_threshold)
+				adminGroup.GET("/admin-withdrawals", withdrawalHandler.ListWithdrawals)
+				adminGroup.POST("/admin-withdrawals/:id/approve", withdrawalHandler.ApproveWithdrawal)
+				adminGroup.POST("/admin-withdrawals/:id/reject", withdrawalHandler.RejectWithdrawal)
+
+				// Login calendar rewards
+				adminGroup.GET("/admin-login-calendar", loginRewardHandler.GetAdminCalendar)
+				adminGroup.PUT("/admin-login-calendar", loginRewardHandler.SetCalendarDay)
+
+				// Sprite generation
+				adminGroup.POST("/admin-sprites/:id/regenerate", spriteHandler.RegenerateSprites)
 			}
 		}
 	}
@@ -0,0 +1,119 @@
+// Command balance-sim fights thousands of seeded AI-vs-AI battles between
+// generated teams and reports win rates by class, element, type and rarity,
+// turns to knockout and outlier abilities. It needs no database: abilities and
+// battle settings come from the embedded content (backend/content).
+//
+//	go run ./cmd/balance-sim                          # 5000 battles of 3v3, uniform rarity
+//	go run ./cmd/balance-sim -battles 20000 -seed 7   # bigger run, another seed
+//	go run ./cmd/balance-sim -rarity gacha -tower 500 # rarity from the mint odds at 500 TOWER
+//	go run ./cmd/balance-sim -out /tmp/before         # CSV + JSON into a directory
+//
+// Run it before and after a balance change with the same seed and diff the output.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/lorengraff/crypto-tower-defense/content"
+	"github.com/lorengraff/crypto-tower-defense/internal/balance"
+	"github.com/lorengraff/crypto-tower-defense/internal/seed"
+)
+
+func main() {
+	cfg := balance.DefaultConfig()
+	flag.IntVar(&cfg.Battles, "battles", cfg.Battles, "number of battles")
+	flag.Int64Var(&cfg.Seed, "seed", cfg.Seed, "seed of the first battle; battle i uses seed+i")
+	flag.IntVar(&cfg.TeamSize, "team-size", cfg.TeamSize, "characters per team")
+	flag.IntVar(&cfg.Level, "level", cfg.Level, "character level (stats and unlocked abilities)")
+	flag.StringVar(&cfg.Rarity, "rarity", cfg.Rarity, "rarity distribution: uniform or gacha")
+	flag.Int64Var(&cfg.TowerAmount, "tower", cfg.TowerAmount, "TOWER spent per mint when -rarity gacha")
+	flag.Float64Var(&cfg.OutlierZ, "outlier-z", cfg.OutlierZ, "flag abilities whose damage or win rate z-score reaches this")
+	flag.IntVar(&cfg.MinUses, "min-uses", cfg.MinUses, "ignore abilities used fewer times when scoring outliers")
+	profile := flag.String("profile", "prod", "content profile: "+strings.Join(seed.Profiles, ", "))
+	out := flag.String("out", "balance-report", "directory for the CSV and JSON reports")
+	format := flag.String("format", "csv,json", "report formats to write: csv, json or both")
+	flag.Parse()
+
+	bundle, err := seed.Load(content.FS, *profile)
+	if err != nil {
+		log.Fatalf("Failed to load content: %v", err)
+	}
+	sim, err := balance.New(bundle, cfg)
+	if err != nil {
+		log.Fatalf("Invalid simulation: %v", err)
+	}
+	report := sim.Run()
+
+	for _, f := range strings.Split(*format, ",") {
+		switch strings.TrimSpace(f) {
+		case "csv":
+			if err := report.WriteCSV(*out); err != nil {
+				log.Fatalf("Failed to write CSV: %v", err)
+			}
+		case "json":
+			if err := writeJSON(report, filepath.Join(*out, "report.json")); err != nil {
+				log.Fatalf("Failed to write JSON: %v", err)
+			}
+		default:
+			log.Fatalf("Unknown format %q", f)
+		}
+	}
+
+	printSummary(report)
+	fmt.Printf("\nReports written to %s\n", *out)
+}
+
+func writeJSON(report *balance.Report, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := report.WriteJSON(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// printSummary shows the class matrix and the flagged abilities
+func printSummary(r *balance.Report) {
+	fmt.Printf("%d battles, %d draws, %.1f turns on average\n\n", r.Battles, r.Draws, r.AvgTurns)
+
+	m := r.Matrix("class")
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprint(w, "CLASS vs\t")
+	for _, v := range m.Values {
+		fmt.Fprintf(w, "%s\t", v)
+	}
+	fmt.Fprintln(w)
+	for i, row := range m.Values {
+		fmt.Fprintf(w, "%s\t", row)
+		for j := range m.Values {
+			fmt.Fprintf(w, "%.1f%%\t", m.Cells[i*len(m.Values)+j].WinRate*100)
+		}
+		fmt.Fprintln(w)
+	}
+	w.Flush()
+
+	fmt.Println("\nOutlier abilities:")
+	found := false
+	for _, a := range r.Abilities {
+		if a.Outlier {
+			found = true
+			fmt.Printf("  %-24s %-8s avg damage %6.1f (z %+.1f)  win rate %5.1f%% (z %+.1f)\n",
+				a.Name, a.Class, a.AvgDamage, a.DamageZ, a.WinRate*100, a.WinRateZ)
+		}
+	}
+	if !found {
+		fmt.Println("  none")
+	}
+}
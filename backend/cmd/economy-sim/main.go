@@ -0,0 +1,161 @@
+// Command economy-sim projects GTK and TOWER supply, treasury balances and
+// revenue buckets over a number of days by simulating player cohorts playing
+// every day. Missions, quest templates, shop items and islands come from the
+// embedded content; tunables (breeding_cost, gacha_daily_mint_limit,
+// wager_tax_percent, ...) from the content settings or the live database.
+//
+//	go run ./cmd/economy-sim                              # 90 days of the default cohorts
+//	go run ./cmd/economy-sim -days 365 -players 5000      # a bigger, longer run
+//	go run ./cmd/economy-sim -scenario whales.yaml        # cohorts from a scenario file
+//	go run ./cmd/economy-sim -settings db                 # use the settings admins have changed
+//
+// Run it before and after changing a tunable with the same seed and diff the output.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/lorengraff/crypto-tower-defense/content"
+	"github.com/lorengraff/crypto-tower-defense/internal/db"
+	"github.com/lorengraff/crypto-tower-defense/internal/economy"
+	"github.com/lorengraff/crypto-tower-defense/internal/seed"
+	"github.com/lorengraff/crypto-tower-defense/internal/services"
+	"github.com/lorengraff/crypto-tower-defense/pkg/config"
+	"github.com/lorengraff/crypto-tower-defense/pkg/logger"
+)
+
+func main() {
+	scenarioFile := flag.String("scenario", "", "YAML scenario file (default: the built-in casual/core/whale cohorts)")
+	days := flag.Int("days", 0, "days to simulate (overrides the scenario)")
+	players := flag.Int("players", 0, "players on day 1 (overrides the scenario)")
+	seedFlag := flag.Int64("seed", 0, "random seed (overrides the scenario)")
+	profile := flag.String("profile", "prod", "content profile: "+strings.Join(seed.Profiles, ", "))
+	settingsFrom := flag.String("settings", "content", "where settings come from: content or db")
+	out := flag.String("out", "economy-report", "directory for the CSV and JSON reports")
+	format := flag.String("format", "csv,json", "report formats to write: csv, json or both")
+	flag.Parse()
+
+	sc := economy.DefaultScenario()
+	if *scenarioFile != "" {
+		var err error
+		if sc, err = economy.LoadScenario(*scenarioFile); err != nil {
+			log.Fatalf("Failed to load scenario: %v", err)
+		}
+	}
+	if *days > 0 {
+		sc.Days = *days
+	}
+	if *players > 0 {
+		sc.StartPlayers = *players
+	}
+	if *seedFlag != 0 {
+		sc.Seed = *seedFlag
+	}
+
+	bundle, err := seed.Load(content.FS, *profile)
+	if err != nil {
+		log.Fatalf("Failed to load content: %v", err)
+	}
+
+	var settings economy.Settings
+	switch *settingsFrom {
+	case "content":
+		settings = bundle.SettingValues()
+	case "db":
+		logger.Init()
+		cfg, err := config.Load()
+		if err != nil {
+			log.Fatalf("Failed to load configuration: %v", err)
+		}
+		if err := db.Connect(cfg); err != nil {
+			log.Fatalf("Failed to connect to database: %v", err)
+		}
+		defer db.Close()
+		settings = services.GetConfigService()
+	default:
+		log.Fatalf("Unknown settings source %q", *settingsFrom)
+	}
+
+	sim, err := economy.New(bundle, settings, sc)
+	if err != nil {
+		log.Fatalf("Invalid simulation: %v", err)
+	}
+	report := sim.Run()
+
+	for _, f := range strings.Split(*format, ",") {
+		switch strings.TrimSpace(f) {
+		case "csv":
+			if err := report.WriteCSV(*out); err != nil {
+				log.Fatalf("Failed to write CSV: %v", err)
+			}
+		case "json":
+			if err := writeJSON(report, filepath.Join(*out, "report.json")); err != nil {
+				log.Fatalf("Failed to write JSON: %v", err)
+			}
+		default:
+			log.Fatalf("Unknown format %q", f)
+		}
+	}
+
+	printSummary(report)
+	fmt.Printf("\nReports written to %s\n", *out)
+}
+
+func writeJSON(report *economy.Report, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := report.WriteJSON(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// printSummary shows the end state and where the tokens came from and went
+func printSummary(r *economy.Report) {
+	s := r.Summary
+	fmt.Printf("%d days: %d players joined, %d churned, %d still playing\n\n",
+		len(r.Days), s.Joined, s.Churned, s.Players)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "GTK\t\t")
+	for _, k := range economy.GTKFaucets {
+		fmt.Fprintf(w, "  + %s\t%d\t\n", k, s.GTKFaucets[k])
+	}
+	for _, k := range economy.GTKSinks {
+		fmt.Fprintf(w, "  - %s\t%d\t\n", k, s.GTKSinks[k])
+	}
+	fmt.Fprintf(w, "  supply\t%d\t\n", s.GTKSupply)
+	fmt.Fprintf(w, "  treasury\t%d\t\n", s.GTKTreasury)
+	fmt.Fprintf(w, "  sink ratio\t%.2f\t\n", s.SinkRatio)
+	fmt.Fprintln(w, "TOWER\t\t")
+	for _, k := range economy.TowerFaucets {
+		fmt.Fprintf(w, "  + %s\t%d\t\n", k, s.TowerFaucets[k])
+	}
+	for _, k := range economy.TowerSinks {
+		fmt.Fprintf(w, "  - %s\t%d\t\n", k, s.TowerSinks[k])
+	}
+	fmt.Fprintf(w, "  supply\t%d\t\n", s.TowerSupply)
+	fmt.Fprintf(w, "  treasury\t%d\t\n", s.TowerTreasury)
+	b := s.Revenue
+	fmt.Fprintln(w, "GTK revenue\t\t")
+	fmt.Fprintf(w, "  growth fund\t%.0f\t\n", b.GrowthFund)
+	fmt.Fprintf(w, "  security fund\t%.0f\t\n", b.SecurityFund)
+	fmt.Fprintf(w, "  operations\t%.0f\t\n", b.Operations)
+	fmt.Fprintf(w, "  rewards pool\t%.0f\t\n", b.RewardsPool)
+	fmt.Fprintf(w, "  dev team\t%.0f\t\n", b.DevTeam)
+	fmt.Fprintf(w, "  tower liquidity\t%.0f\t\n", b.TowerLiquidity)
+	fmt.Fprintf(w, "  total\t%.0f\t\n", b.Total)
+	w.Flush()
+}
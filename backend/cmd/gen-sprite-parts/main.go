@@ -0,0 +1,350 @@
+// gen-sprite-parts draws the built-in layered sprite parts used by internal/sprites.
+//
+// Body, head, back, legs and aura parts are drawn in grayscale so the compositor can
+// palette-swap them per element; outfits, weapons, trims and the shadow keep their colors.
+// Run from backend/: go run ./cmd/gen-sprite-parts
+package main
+
+import (
+	"flag"
+	"image"
+	"image/color"
+	"image/png"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/sprites"
+)
+
+type mask func(x, y float64) bool
+
+type shape struct {
+	in   mask
+	tone color.NRGBA
+}
+
+// part is a stack of shapes; later shapes paint over earlier ones
+type part struct {
+	shapes  []shape
+	outline bool // Dark 1px outline and top/bottom shading (off for glows and shadows)
+}
+
+func gray(v uint8) color.NRGBA          { return color.NRGBA{v, v, v, 0xFF} }
+func grayA(v, a uint8) color.NRGBA      { return color.NRGBA{v, v, v, a} }
+func rgb(r, g, b uint8) color.NRGBA     { return color.NRGBA{r, g, b, 0xFF} }
+func solid(shapes ...shape) part        { return part{shapes: shapes, outline: true} }
+func glow(shapes ...shape) part         { return part{shapes: shapes} }
+func s(in mask, tone color.NRGBA) shape { return shape{in: in, tone: tone} }
+
+func ellipse(cx, cy, rx, ry float64) mask {
+	return func(x, y float64) bool {
+		dx, dy := (x-cx)/rx, (y-cy)/ry
+		return dx*dx+dy*dy <= 1
+	}
+}
+
+func ring(cx, cy, rx, ry, width float64) mask {
+	outer, inner := ellipse(cx, cy, rx, ry), ellipse(cx, cy, rx-width, ry-width)
+	return func(x, y float64) bool { return outer(x, y) && !inner(x, y) }
+}
+
+func rect(x0, y0, x1, y1 float64) mask {
+	return func(x, y float64) bool { return x >= x0 && x < x1 && y >= y0 && y < y1 }
+}
+
+func capsule(ax, ay, bx, by, r float64) mask {
+	return func(x, y float64) bool {
+		dx, dy := bx-ax, by-ay
+		t := 0.0
+		if l := dx*dx + dy*dy; l > 0 {
+			t = math.Max(0, math.Min(1, ((x-ax)*dx+(y-ay)*dy)/l))
+		}
+		qx, qy := ax+t*dx-x, ay+t*dy-y
+		return qx*qx+qy*qy <= r*r
+	}
+}
+
+// poly is an even-odd polygon test over x,y pairs
+func poly(pts ...float64) mask {
+	return func(x, y float64) bool {
+		in := false
+		n := len(pts) / 2
+		for i, j := 0, n-1; i < n; j, i = i, i+1 {
+			xi, yi, xj, yj := pts[2*i], pts[2*i+1], pts[2*j], pts[2*j+1]
+			if (yi > y) != (yj > y) && x < (xj-xi)*(y-yi)/(yj-yi)+xi {
+				in = !in
+			}
+		}
+		return in
+	}
+}
+
+func and(a, b mask) mask { return func(x, y float64) bool { return a(x, y) && b(x, y) } }
+
+// eye is shared by most heads
+func eye(x, y float64) shape { return s(rect(x, y, x+2, y+3), gray(12)) }
+
+var parts = map[string]map[string]part{
+	"shadow": {
+		"default": glow(s(ellipse(32, 58, 12, 2.8), color.NRGBA{0, 0, 0, 80})),
+	},
+	"legs": {
+		"default": solid(
+			s(capsule(29, 42, 28, 55, 2.5), gray(95)),
+			s(ellipse(29.5, 56, 3.5, 1.8), gray(80)),
+			s(capsule(35, 42, 36, 55, 2.5), gray(140)),
+			s(ellipse(37.5, 56, 3.5, 1.8), gray(120)),
+		),
+	},
+	"body": {
+		"default": solid(s(ellipse(32, 36, 9, 9.5), gray(150)), s(ellipse(34, 38, 5, 5), gray(200))),
+		"beast":   solid(s(ellipse(31, 36, 11, 9), gray(150)), s(ellipse(33, 39, 6, 4.5), gray(200))),
+		"dragon":  solid(s(ellipse(32, 36, 10, 10), gray(140)), s(ellipse(35, 38, 5, 7), gray(210))),
+		"bird":    solid(s(ellipse(32, 35, 8, 10.5), gray(160)), s(ellipse(34, 37, 5, 7), gray(220))),
+		"avian":   solid(s(ellipse(32, 35, 8.5, 10), gray(150)), s(ellipse(34.5, 37, 4.5, 6.5), gray(215))),
+		"insect":  solid(s(ellipse(29.5, 39, 8, 6), gray(130)), s(ellipse(34, 31, 6, 5), gray(165))),
+		"aquatic": solid(s(ellipse(32, 36, 10, 8.5), gray(150)), s(ellipse(34, 38.5, 6, 4.5), gray(210))),
+		"mineral": solid(
+			s(poly(24, 30, 32, 26, 40, 30, 41, 42, 32, 46, 23, 42), gray(140)),
+			s(poly(32, 26, 40, 30, 36, 38, 30, 34), gray(205)),
+		),
+		"spirit": solid(s(ellipse(32, 38, 9, 8), gray(190)), s(poly(24, 36, 40, 36, 30, 22), gray(190))),
+		"plant":  solid(s(ellipse(32, 37, 9, 9), gray(150)), s(poly(33, 32, 38.5, 38, 33, 44, 29.5, 38), gray(210))),
+		"machine": solid(
+			s(rect(23, 27, 41, 45), gray(150)),
+			s(rect(29, 32, 38, 40), gray(210)),
+			s(rect(31, 34, 33, 36), gray(40)),
+		),
+	},
+	"head": {
+		"default": solid(s(ellipse(33, 20, 7.5, 7), gray(170)), eye(36, 18)),
+		"beast": solid(
+			s(poly(27, 15, 28.5, 7, 32, 13), gray(140)), s(poly(32, 13, 35, 7, 37, 14), gray(140)),
+			s(ellipse(32.5, 20, 7.5, 7), gray(165)), s(ellipse(39, 22, 3.5, 2.5), gray(205)), eye(35, 17),
+		),
+		"dragon": solid(
+			s(poly(28, 15, 23, 6, 31, 13), gray(225)), s(poly(32, 14, 30, 5, 35, 13), gray(225)),
+			s(ellipse(32, 20, 7, 6.5), gray(150)), s(ellipse(38.5, 22, 5, 3), gray(160)), eye(34, 17),
+		),
+		"bird": solid(
+			s(poly(29, 13, 26, 6, 33, 12), gray(120)),
+			s(ellipse(33, 19, 7, 7), gray(170)), s(poly(39, 17.5, 46, 20.5, 39, 23), gray(235)), eye(35, 17),
+		),
+		"avian": solid(
+			s(poly(28, 14, 22, 9, 31, 12), gray(110)), s(poly(29, 12, 25, 5, 33, 11), gray(130)),
+			s(ellipse(33, 19, 7, 7), gray(165)), s(poly(39, 18, 45, 20, 44, 22, 39, 23), gray(230)), eye(35, 17),
+		),
+		"insect": solid(
+			s(capsule(34.5, 16, 38, 7, 0.8), gray(90)), s(capsule(32, 16, 30.5, 7, 0.8), gray(90)),
+			s(ellipse(34, 21, 6, 5.5), gray(160)), s(ellipse(37, 20, 2.5, 2.5), gray(12)),
+		),
+		"aquatic": solid(
+			s(poly(28, 14, 33, 6, 37, 14), gray(120)),
+			s(ellipse(33, 20, 7.5, 6.5), gray(165)), s(rect(28, 21, 29, 25), gray(110)), eye(36, 18),
+		),
+		"mineral": solid(
+			s(poly(30, 13, 27.5, 3, 34, 11), gray(230)),
+			s(poly(33, 11, 41, 19, 37, 27, 27, 25, 25, 17), gray(150)), eye(35, 17),
+		),
+		"spirit": solid(
+			s(poly(27.5, 16, 30.5, 5, 34, 14), gray(235)), s(poly(32, 14, 36.5, 7, 38, 16), gray(235)),
+			s(ellipse(33, 20, 7, 6.5), gray(205)), s(ellipse(36.5, 19.5, 1.5, 2), gray(12)),
+		),
+		"plant": solid(
+			s(poly(32, 13.5, 26, 4, 37, 9), gray(220)),
+			s(ellipse(33, 20, 7.5, 7), gray(160)), eye(36, 18),
+		),
+		"machine": solid(
+			s(capsule(30, 13, 29, 6, 0.8), gray(90)), s(ellipse(29, 5, 1.6, 1.6), gray(235)),
+			s(rect(26, 13, 40, 27), gray(160)), s(rect(34, 17, 40, 21), gray(12)),
+		),
+	},
+	"back": {
+		"dragon": solid(
+			s(poly(28, 30, 13, 14, 17, 27, 10, 30, 19, 36, 26, 38), gray(110)),
+			s(capsule(28, 30, 13, 14, 0.8), gray(200)),
+		),
+		"bird":    solid(s(poly(30, 29, 14, 31, 17, 41, 30, 40), gray(120))),
+		"avian":   solid(s(poly(30, 28, 11, 26, 15, 36, 13, 42, 30, 40), gray(115))),
+		"beast":   solid(s(capsule(22, 40, 14, 31, 2.5), gray(120)), s(ellipse(13, 30, 3, 3), gray(205))),
+		"insect":  glow(s(ellipse(24, 27, 8, 4), grayA(235, 170)), s(ellipse(22, 32, 7, 3.5), grayA(215, 170))),
+		"aquatic": solid(s(poly(23, 40, 11, 33, 14, 47), gray(120))),
+		"plant": solid(
+			s(capsule(25, 34, 16, 25, 1.4), gray(100)), s(ellipse(15, 24, 3.5, 2), gray(210)),
+			s(capsule(24, 38, 15, 38, 1.2), gray(100)), s(ellipse(14, 38, 3, 2), gray(200)),
+		),
+		"spirit":  glow(s(ellipse(20, 40, 5, 2.5), grayA(225, 150)), s(ellipse(17, 34, 4, 2), grayA(225, 130))),
+		"machine": solid(s(rect(18, 28, 25, 42), gray(100)), s(rect(19, 42, 24, 45), gray(55))),
+		"mineral": solid(
+			s(poly(24, 30, 18, 17, 27.5, 28), gray(215)),
+			s(poly(22, 36, 12, 30, 23, 40), gray(180)),
+		),
+	},
+	"outfit": {
+		"warrior": solid(
+			s(poly(25, 30, 39, 30, 40, 40, 24, 40), rgb(150, 160, 175)),
+			s(rect(24, 40, 40, 43), rgb(100, 65, 35)),
+		),
+		"mage": solid(
+			s(poly(26, 29, 38, 29, 43, 56, 21, 56), rgb(90, 50, 150)),
+			s(rect(21, 53, 43, 56), rgb(220, 180, 60)),
+		),
+		"archer": solid(
+			s(poly(25, 34, 39, 34, 40, 44, 24, 44), rgb(60, 110, 50)),
+			s(capsule(27, 29, 38, 42, 1.4), rgb(110, 70, 35)),
+		),
+		"tank": solid(
+			s(rect(25, 31, 39, 42), rgb(140, 145, 160)),
+			s(ellipse(26, 30, 5, 4), rgb(120, 125, 140)), s(ellipse(38, 30, 5, 4), rgb(120, 125, 140)),
+		),
+		"support": solid(
+			s(capsule(28, 29, 37, 43, 2), rgb(235, 235, 240)),
+			s(rect(24, 40, 40, 42.5), rgb(200, 170, 80)),
+		),
+		"rogue": solid(
+			s(poly(25, 27, 39, 27, 38, 31, 26, 31), rgb(120, 30, 40)),
+			s(capsule(25, 29, 18, 33, 1.4), rgb(120, 30, 40)),
+			s(rect(24, 40, 40, 42.5), rgb(50, 40, 40)),
+		),
+		"paladin": solid(
+			s(rect(28, 30, 36, 50), rgb(230, 230, 235)),
+			s(rect(31, 33, 33, 42), rgb(220, 180, 60)), s(rect(29, 36, 35, 38), rgb(220, 180, 60)),
+			s(ellipse(26, 30, 4, 3), rgb(170, 175, 190)), s(ellipse(38, 30, 4, 3), rgb(170, 175, 190)),
+		),
+		"berserker": solid(
+			s(ellipse(30, 29, 9, 3.5), rgb(130, 90, 55)),
+			s(rect(24, 40, 40, 43), rgb(80, 50, 30)),
+		),
+	},
+	"weapon": {
+		"warrior": solid(
+			s(poly(39, 34, 41, 34, 41.5, 14, 40, 10.5, 38.5, 14), rgb(210, 215, 225)),
+			s(rect(37, 34, 44, 36), rgb(220, 180, 60)),
+			s(rect(39, 36, 41, 41), rgb(100, 65, 35)),
+		),
+		"mage": solid(
+			s(capsule(40, 48, 40, 16, 1.2), rgb(120, 80, 40)),
+			s(ellipse(40, 13, 3, 3), rgb(120, 220, 255)),
+		),
+		"archer": solid(
+			s(and(ring(38, 38, 6, 14, 1.6), func(x, y float64) bool { return x >= 38 }), rgb(120, 80, 40)),
+			s(rect(38, 24, 39, 52), rgb(230, 225, 210)),
+		),
+		"tank": solid(
+			s(ellipse(43, 38, 6, 9), rgb(150, 155, 170)),
+			s(ring(43, 38, 6, 9, 1.2), rgb(110, 115, 130)),
+			s(ellipse(44, 38, 2, 2), rgb(220, 180, 60)),
+		),
+		"support": solid(
+			s(rect(40, 34, 46, 41), rgb(160, 40, 40)),
+			s(rect(42.5, 35, 43.5, 40), rgb(220, 180, 60)), s(rect(41, 36.5, 45, 37.5), rgb(220, 180, 60)),
+		),
+		"rogue": solid(
+			s(poly(40, 36.5, 46, 32, 47, 33.5, 41.5, 39), rgb(210, 215, 225)),
+			s(rect(38.5, 37, 41, 41), rgb(60, 45, 40)),
+		),
+		"paladin": solid(
+			s(capsule(40, 46, 40, 22, 1), rgb(120, 80, 40)),
+			s(rect(35, 16, 45, 23), rgb(180, 185, 200)),
+		),
+		"berserker": solid(
+			s(capsule(40, 48, 40, 18, 1.1), rgb(110, 70, 35)),
+			s(poly(40, 18, 48, 13.5, 50.5, 22, 47, 29, 40, 26), rgb(190, 195, 205)),
+		),
+	},
+	"aura": {
+		"s":  glow(s(ring(32, 36, 17, 20, 1.5), grayA(235, 140))),
+		"ss": glow(s(ellipse(32, 36, 16, 19), grayA(220, 50)), s(ring(32, 36, 17, 20, 1.5), grayA(240, 170))),
+		"sss": glow(
+			s(ellipse(32, 36, 17, 20), grayA(230, 60)),
+			s(ring(32, 36, 17, 20, 1.5), grayA(250, 190)), s(ring(32, 36, 20, 23, 1), grayA(250, 120)),
+			s(poly(12, 20, 13.5, 17, 15, 20, 13.5, 23), grayA(255, 230)),
+			s(poly(49, 46, 50.5, 43, 52, 46, 50.5, 49), grayA(255, 230)),
+		),
+	},
+	"trim": {
+		"ss": solid(
+			s(capsule(27, 13.5, 39, 13.5, 1), rgb(225, 185, 60)),
+			s(ellipse(33, 13.5, 1.4, 1.4), rgb(200, 40, 60)),
+		),
+		"sss": solid(
+			s(poly(26, 14, 27, 6, 30, 10.5, 33, 3.5, 36, 10.5, 39, 6, 40, 14), rgb(240, 200, 70)),
+			s(ellipse(33, 11, 1.4, 1.4), rgb(200, 40, 60)),
+			s(ellipse(29, 12, 1, 1), rgb(60, 140, 220)), s(ellipse(37, 12, 1, 1), rgb(60, 140, 220)),
+		),
+	},
+}
+
+func main() {
+	out := flag.String("out", "internal/sprites/parts", "output directory")
+	flag.Parse()
+
+	for layer, keys := range parts {
+		dir := filepath.Join(*out, layer)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			log.Fatal(err)
+		}
+		for key, p := range keys {
+			if err := writePNG(filepath.Join(dir, key+".png"), rasterize(p)); err != nil {
+				log.Fatal(err)
+			}
+		}
+		log.Printf("%s: %d parts", layer, len(keys))
+	}
+}
+
+func rasterize(p part) *image.NRGBA {
+	const size = sprites.PartSize
+	img := image.NewNRGBA(image.Rect(0, 0, size, size))
+	filled := func(x, y int) bool {
+		return x >= 0 && y >= 0 && x < size && y < size && img.NRGBAAt(x, y).A > 0
+	}
+
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			for i := len(p.shapes) - 1; i >= 0; i-- {
+				if p.shapes[i].in(float64(x)+0.5, float64(y)+0.5) {
+					img.SetNRGBA(x, y, p.shapes[i].tone)
+					break
+				}
+			}
+		}
+	}
+	if !p.outline {
+		return img
+	}
+
+	// Light from above: brighten top edges, darken bottom edges, then outline the silhouette
+	shaded := image.NewNRGBA(img.Bounds())
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			c := img.NRGBAAt(x, y)
+			switch {
+			case c.A > 0 && !filled(x, y-1):
+				c = adjust(c, 1.25)
+			case c.A > 0 && !filled(x, y+1):
+				c = adjust(c, 0.75)
+			case c.A == 0 && (filled(x-1, y) || filled(x+1, y) || filled(x, y-1) || filled(x, y+1)):
+				c = color.NRGBA{24, 22, 26, 0xFF}
+			}
+			shaded.SetNRGBA(x, y, c)
+		}
+	}
+	return shaded
+}
+
+func adjust(c color.NRGBA, f float64) color.NRGBA {
+	scale := func(v uint8) uint8 { return uint8(math.Min(255, float64(v)*f)) }
+	return color.NRGBA{scale(c.R), scale(c.G), scale(c.B), c.A}
+}
+
+func writePNG(path string, img image.Image) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return png.Encode(f, img)
+}
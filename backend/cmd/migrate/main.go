@@ -0,0 +1,141 @@
+// Command migrate applies the SQL migrations embedded in the binary.
+//
+//	go run ./cmd/migrate status
+//	go run ./cmd/migrate [-dry-run] up [VERSION]
+//	go run ./cmd/migrate [-dry-run] down [STEPS]
+//	go run ./cmd/migrate [-dry-run] baseline VERSION
+//
+// Databases created before the runner existed (psql < 001_initial_schema.sql plus
+// the old cmd/migrate-* tools) should run `baseline 43` once, then `up`.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/db"
+	"github.com/lorengraff/crypto-tower-defense/internal/migrate"
+	"github.com/lorengraff/crypto-tower-defense/migrations"
+	"github.com/lorengraff/crypto-tower-defense/pkg/config"
+	"github.com/lorengraff/crypto-tower-defense/pkg/logger"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: migrate [-dry-run] up [VERSION] | down [STEPS] | status | baseline VERSION")
+	flag.PrintDefaults()
+}
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "print the SQL that would run without changing the database")
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	logger.Init()
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	if err := db.Connect(cfg); err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	sqlDB, err := db.DB.DB()
+	if err != nil {
+		log.Fatalf("Failed to get database handle: %v", err)
+	}
+	runner, err := migrate.New(sqlDB, migrations.FS)
+	if err != nil {
+		log.Fatalf("Invalid migrations: %v", err)
+	}
+	runner.DryRun = *dryRun
+	runner.Out = os.Stdout
+
+	ctx := context.Background()
+	cmd, arg := flag.Arg(0), flag.Arg(1)
+	switch cmd {
+	case "up":
+		done, err := runner.Up(ctx, intArg(arg, 0))
+		report(done, err, *dryRun, "Applied", "Would apply")
+	case "down":
+		done, err := runner.Down(ctx, int(intArg(arg, 1)))
+		report(done, err, *dryRun, "Reverted", "Would revert")
+	case "baseline":
+		if arg == "" {
+			usage()
+			os.Exit(2)
+		}
+		done, err := runner.Baseline(ctx, intArg(arg, 0))
+		report(done, err, *dryRun, "Marked as applied", "Would mark as applied")
+	case "status":
+		printStatus(ctx, runner)
+	default:
+		usage()
+		os.Exit(2)
+	}
+}
+
+func intArg(s string, def int64) int64 {
+	if s == "" {
+		return def
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n < 0 {
+		log.Fatalf("Invalid number %q", s)
+	}
+	return n
+}
+
+func report(done []migrate.Migration, err error, dryRun bool, verb, dryVerb string) {
+	if dryRun {
+		verb = dryVerb
+	}
+	for _, m := range done {
+		fmt.Printf("%s %s\n", verb, m)
+	}
+	if err != nil {
+		log.Fatalf("Migration stopped: %v", err)
+	}
+	if len(done) == 0 {
+		fmt.Println("Nothing to do")
+	}
+}
+
+func printStatus(ctx context.Context, runner *migrate.Runner) {
+	statuses, err := runner.Status(ctx)
+	if err != nil {
+		log.Fatalf("Failed to read migration status: %v", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "MIGRATION\tSTATE\tAPPLIED AT\tDOWN")
+	for _, s := range statuses {
+		state, appliedAt := "pending", ""
+		switch {
+		case s.Missing:
+			state = "applied, not in build"
+		case s.Modified:
+			state = "applied, file modified"
+		case s.Applied:
+			state = "applied"
+		}
+		if s.Applied {
+			appliedAt = s.AppliedAt.UTC().Format("2006-01-02 15:04:05")
+		}
+		down := "no"
+		if s.Reversible() {
+			down = "yes"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", s.Migration, state, appliedAt, down)
+	}
+	w.Flush()
+}
@@ -0,0 +1,8 @@
+// Package content embeds the declarative game content loaded by internal/seed:
+// base/ for every profile, plus the dev/ and test/ overlays.
+package content
+
+import "embed"
+
+//go:embed base dev test
+var FS embed.FS
@@ -0,0 +1,121 @@
+package balance
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lorengraff/crypto-tower-defense/content"
+	"github.com/lorengraff/crypto-tower-defense/internal/seed"
+)
+
+func newSim(t *testing.T, cfg Config) *Sim {
+	t.Helper()
+	b, err := seed.Load(content.FS, "prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := New(b, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func smallConfig() Config {
+	cfg := DefaultConfig()
+	cfg.Battles = 300
+	return cfg
+}
+
+func TestRunIsReproducible(t *testing.T) {
+	cfg := smallConfig()
+	a := newSim(t, cfg).Run()
+	b := newSim(t, cfg).Run()
+	if !reflect.DeepEqual(a, b) {
+		t.Fatal("two runs with the same seed differ")
+	}
+
+	cfg.Seed = 2
+	if c := newSim(t, cfg).Run(); reflect.DeepEqual(a.Matrices, c.Matrices) {
+		t.Fatal("a different seed produced the same matrices")
+	}
+}
+
+func TestMatricesAreZeroSum(t *testing.T) {
+	r := newSim(t, smallConfig()).Run()
+	if r.Battles != 300 {
+		t.Fatalf("battles = %d, want 300", r.Battles)
+	}
+	for _, m := range r.Matrices {
+		n := len(m.Values)
+		if len(m.Cells) != n*n {
+			t.Fatalf("%s: %d cells for %d values", m.Dimension, len(m.Cells), n)
+		}
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				ab, ba := m.Cells[i*n+j], m.Cells[j*n+i]
+				if ab.Pairs != ba.Pairs || ab.Draws != ba.Draws {
+					t.Fatalf("%s %s/%s: pairs or draws not symmetric", m.Dimension, ab.Row, ab.Col)
+				}
+				if i != j && ab.Wins+ba.Wins+ab.Draws != ab.Pairs {
+					t.Fatalf("%s %s vs %s: %d + %d wins + %d draws != %d pairs",
+						m.Dimension, ab.Row, ab.Col, ab.Wins, ba.Wins, ab.Draws, ab.Pairs)
+				}
+			}
+		}
+	}
+}
+
+func TestGachaRarityFollowsMintOdds(t *testing.T) {
+	cfg := smallConfig()
+	cfg.Rarity = "gacha" // Free mint: 99.9% C
+	r := newSim(t, cfg).Run()
+	total, common := 0, 0
+	for _, k := range r.Knockouts {
+		if k.Dimension == "rarity" {
+			total += k.Units
+			if k.Value == "C" {
+				common = k.Units
+			}
+		}
+	}
+	if total != 2*cfg.Battles*cfg.TeamSize || float64(common) < 0.99*float64(total) {
+		t.Fatalf("%d of %d units are C, want 99.9%%", common, total)
+	}
+}
+
+func TestEffectiveness(t *testing.T) {
+	cases := []struct {
+		charType, element string
+		want              float64
+	}{
+		{"FLORA", "FIRE", 0.5},  // Chart score 2.0
+		{"FLORA", "WIND", 2.0},  // Chart score 0.5
+		{"BEAST", "WATER", 1.0}, // Neutral
+		{"BEAST", "NORMAL", 1.0},
+		{"UNKNOWN", "FIRE", 1.0},
+	}
+	for _, c := range cases {
+		if got := Effectiveness(c.charType, c.element); got != c.want {
+			t.Errorf("Effectiveness(%s, %s) = %v, want %v", c.charType, c.element, got, c.want)
+		}
+	}
+}
+
+func TestNewRejectsBadConfig(t *testing.T) {
+	b, err := seed.Load(content.FS, "prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, mutate := range map[string]func(*Config){
+		"no battles":     func(c *Config) { c.Battles = 0 },
+		"empty teams":    func(c *Config) { c.TeamSize = 0 },
+		"unknown rarity": func(c *Config) { c.Rarity = "weighted" },
+	} {
+		cfg := DefaultConfig()
+		mutate(&cfg)
+		if _, err := New(b, cfg); err == nil {
+			t.Errorf("%s: New succeeded", name)
+		}
+	}
+}
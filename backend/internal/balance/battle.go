@@ -0,0 +1,203 @@
+package balance
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/internal/seed"
+	"github.com/lorengraff/crypto-tower-defense/internal/services"
+	"github.com/lorengraff/crypto-tower-defense/pkg/formulas"
+)
+
+// basicAttack is the free action BattleService.ProcessTurn falls back to
+var basicAttack = seed.Ability{Name: "Attack", Damage: 10, DamageType: "physical", TargetType: "SINGLE_ENEMY"}
+
+// fight runs turns until one team is knocked out or battle_max_turns passes.
+// It returns the winning side (0 or 1, -1 for a draw) and the turns played.
+func (s *Sim) fight(rng *rand.Rand, teams [2][]*unit, rec *recorder) (int, int) {
+	maxTurns := s.settings.GetInt("battle_max_turns", 50)
+	manaGain := s.settings.GetInt("battle_mana_gain_per_turn", 10)
+
+	for turn := 1; turn <= maxTurns; turn++ {
+		for _, u := range s.turnOrder(rng, teams) {
+			if u.hp == 0 {
+				continue // Knocked out earlier this turn
+			}
+			u.hp = services.ApplyHealerRegeneration(u.class, u.hp, u.maxHP)
+			u.mana = min(u.maxMana, u.mana+manaGain)
+			for name, cd := range u.cooldowns {
+				if cd > 0 {
+					u.cooldowns[name] = cd - 1
+				}
+			}
+
+			s.act(rng, u, teams[u.team], teams[1-u.team], turn, rec)
+			if standing(teams[1-u.team]) == 0 {
+				return u.team, turn
+			}
+		}
+	}
+	return -1, maxTurns
+}
+
+// turnOrder sorts the standing units by speed, breaking ties at random like
+// BattleEngine.CalculateTurnOrder
+func (s *Sim) turnOrder(rng *rand.Rand, teams [2][]*unit) []*unit {
+	var order []*unit
+	for _, team := range teams {
+		for _, u := range team {
+			if u.hp > 0 {
+				order = append(order, u)
+			}
+		}
+	}
+	rng.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+	sort.SliceStable(order, func(i, j int) bool { return order[i].speed > order[j].speed })
+	return order
+}
+
+// act mirrors BattleService.executeAITurn: a random usable skill 70% of the
+// time, the basic attack otherwise
+func (s *Sim) act(rng *rand.Rand, u *unit, allies, enemies []*unit, turn int, rec *recorder) {
+	hurt := false
+	for _, a := range allies {
+		hurt = hurt || (a.hp > 0 && a.hp < a.maxHP)
+	}
+	var usable []*seed.Ability
+	for i := range u.abilities {
+		a := &u.abilities[i]
+		if a.ManaCost > u.mana || u.cooldowns[a.Name] > 0 || (a.BaseHeal > 0 && !hurt) {
+			continue
+		}
+		usable = append(usable, a)
+	}
+
+	ability := &basicAttack
+	if len(usable) > 0 && rng.Float32() > 0.3 {
+		ability = usable[rng.Intn(len(usable))]
+	}
+	u.mana -= ability.ManaCost
+	if ability.Cooldown > 0 {
+		u.cooldowns[ability.Name] = ability.Cooldown
+	}
+	u.used[ability.Name] = true
+
+	if ability.BaseHeal > 0 {
+		healing := services.NewSkillActivationService().CalculateHealing(
+			&models.Character{Level: s.cfg.Level}, &models.Ability{BaseHeal: ability.BaseHeal})
+		total := 0
+		for _, t := range healTargets(u, allies, ability.TargetType) {
+			before := t.hp
+			t.hp = min(t.maxHP, t.hp+healing)
+			total += t.hp - before
+		}
+		rec.use(ability, 0, total, 0)
+		return
+	}
+
+	damage, kills := 0, 0
+	for _, t := range damageTargets(rng, enemies, ability.TargetType) {
+		hit := s.damage(rng, u, t, ability)
+		damage += min(hit, t.hp)
+		t.hp = max(0, t.hp-hit)
+		if t.hp == 0 {
+			t.knockedOutAt = turn
+			kills++
+		}
+	}
+	rec.use(ability, damage, 0, kills)
+}
+
+func healTargets(u *unit, allies []*unit, targetType string) []*unit {
+	switch targetType {
+	case "SELF":
+		return []*unit{u}
+	case "AOE", "ALL_ALLIES":
+		var all []*unit
+		for _, a := range allies {
+			if a.hp > 0 {
+				all = append(all, a)
+			}
+		}
+		return all
+	}
+	// Single ally: the one missing the most HP
+	var target *unit
+	for _, a := range allies {
+		if a.hp > 0 && (target == nil || a.maxHP-a.hp > target.maxHP-target.hp) {
+			target = a
+		}
+	}
+	return []*unit{target}
+}
+
+func damageTargets(rng *rand.Rand, enemies []*unit, targetType string) []*unit {
+	var alive []*unit
+	for _, e := range enemies {
+		if e.hp > 0 {
+			alive = append(alive, e)
+		}
+	}
+	if targetType == "AOE" {
+		return alive
+	}
+	return []*unit{alive[rng.Intn(len(alive))]}
+}
+
+// damage follows BattleEngine.CalculateDamage (attack scaling, capped defense
+// reduction, crits, randomness) with the raid modifiers on top: the
+// TypeElementMatrix chart, GetClassAdvantage and the class passives
+func (s *Sim) damage(rng *rand.Rand, attacker, defender *unit, ability *seed.Ability) int {
+	power := ability.Damage
+	if power == 0 {
+		power = ability.BaseDamage
+	}
+	damage := float64(power) * float64(attacker.atk) / 100.0
+	damage *= 1.0 - math.Min(float64(defender.def)/200.0, s.settings.GetFloat("battle_def_reduction_cap", 0.75))
+
+	element := attacker.element
+	if ability.Element != "" {
+		element = strings.ToUpper(ability.Element)
+	}
+	damage *= Effectiveness(defender.charType, element)
+	damage *= services.GetClassAdvantage(attacker.class, defender.class)
+
+	critChance := s.settings.GetFloat("battle_crit_chance", 0.10) + services.GetArcherCritBonus(attacker.class)
+	crit := rng.Float64() < critChance
+	if crit {
+		damage *= s.settings.GetFloat("battle_crit_multiplier", 1.5)
+	}
+	damage *= services.ApplyPassiveAbility(attacker.class, attacker.hp, attacker.maxHP, crit, true)
+	damage *= services.ApplyPassiveAbility(defender.class, defender.hp, defender.maxHP, crit, false)
+
+	randFactor := s.settings.GetFloat("battle_randomness_factor", 0.10)
+	damage *= (1.0 - randFactor) + rng.Float64()*randFactor*2.0
+
+	return max(1, int(damage))
+}
+
+// Effectiveness turns a TypeElementMatrix resistance score into a damage
+// multiplier the way BattleEngine.CalculateDamage reads its chart: 0.5 or less
+// is super effective (2x), 1.5 or more is not very effective (0.5x)
+func Effectiveness(charType, element string) float64 {
+	switch r := formulas.GetTypeResistance(charType, element); {
+	case r <= 0.5:
+		return 2.0
+	case r >= 1.5:
+		return 0.5
+	}
+	return 1.0
+}
+
+func standing(team []*unit) int {
+	n := 0
+	for _, u := range team {
+		if u.hp > 0 {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,403 @@
+package balance
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/seed"
+)
+
+// Dimensions the win-rate matrices and knockout stats are broken down by
+var Dimensions = []string{"class", "element", "type", "rarity"}
+
+func (u *unit) attr(dimension string) string {
+	switch dimension {
+	case "class":
+		return u.class
+	case "element":
+		return u.element
+	case "type":
+		return u.charType
+	}
+	return u.rank
+}
+
+// Cell is one row-vs-column matchup. Every unit of the winning team scores a
+// win against every unit of the losing team, so with teams larger than one a
+// cell measures how a trait fares alongside random teammates.
+type Cell struct {
+	Row     string  `json:"row"`
+	Col     string  `json:"col"`
+	Pairs   int     `json:"pairs"`
+	Wins    int     `json:"wins"`
+	Draws   int     `json:"draws"`
+	WinRate float64 `json:"win_rate"`
+}
+
+// Matrix is the win rate of every value of a dimension against every other
+type Matrix struct {
+	Dimension string   `json:"dimension"`
+	Values    []string `json:"values"`
+	Cells     []Cell   `json:"cells"` // Row-major over Values
+}
+
+// KnockoutStat is how long units with one trait value survive
+type KnockoutStat struct {
+	Dimension string  `json:"dimension"`
+	Value     string  `json:"value"`
+	Units     int     `json:"units"`
+	Knockouts int     `json:"knockouts"`
+	AvgTurns  float64 `json:"avg_turns_to_ko"`
+}
+
+// AbilityStat is how an ability performed over the run
+type AbilityStat struct {
+	Name          string  `json:"name"`
+	Class         string  `json:"class"`
+	Uses          int     `json:"uses"`
+	Damage        int     `json:"damage"`
+	Healing       int     `json:"healing"`
+	Knockouts     int     `json:"knockouts"`
+	AvgDamage     float64 `json:"avg_damage"`
+	AvgHealing    float64 `json:"avg_healing"`
+	DamagePerMana float64 `json:"damage_per_mana"`
+	WinRate       float64 `json:"win_rate"` // Of the teams it was used by
+	DamageZ       float64 `json:"damage_z"`
+	WinRateZ      float64 `json:"win_rate_z"`
+	Outlier       bool    `json:"outlier"`
+}
+
+// Report is the result of a run
+type Report struct {
+	Config    Config         `json:"config"`
+	Battles   int            `json:"battles"`
+	Draws     int            `json:"draws"`
+	AvgTurns  float64        `json:"avg_turns"`
+	Matrices  []Matrix       `json:"matrices"`
+	Knockouts []KnockoutStat `json:"knockouts"`
+	Abilities []AbilityStat  `json:"abilities"`
+}
+
+// Matrix returns the matrix for dimension, or nil
+func (r *Report) Matrix(dimension string) *Matrix {
+	for i := range r.Matrices {
+		if r.Matrices[i].Dimension == dimension {
+			return &r.Matrices[i]
+		}
+	}
+	return nil
+}
+
+type pair struct{ row, col string }
+
+type koTally struct{ units, knockouts, turns int }
+
+type abilityTally struct {
+	class                              string
+	uses, damage, healing, kills, mana int
+	battles, wins                      int
+}
+
+// recorder accumulates battle results until report builds the Report
+type recorder struct {
+	sim       *Sim
+	battles   int
+	draws     int
+	turns     int
+	cells     map[string]map[pair]*Cell
+	knockouts map[string]map[string]*koTally
+	abilities map[string]*abilityTally
+}
+
+func newRecorder(s *Sim) *recorder {
+	r := &recorder{
+		sim:       s,
+		cells:     map[string]map[pair]*Cell{},
+		knockouts: map[string]map[string]*koTally{},
+		abilities: map[string]*abilityTally{},
+	}
+	for _, d := range Dimensions {
+		r.cells[d] = map[pair]*Cell{}
+		r.knockouts[d] = map[string]*koTally{}
+	}
+	return r
+}
+
+func (r *recorder) use(a *seed.Ability, damage, healing, kills int) {
+	t := r.abilities[a.Name]
+	if t == nil {
+		t = &abilityTally{class: a.Class}
+		r.abilities[a.Name] = t
+	}
+	t.uses++
+	t.damage += damage
+	t.healing += healing
+	t.kills += kills
+	t.mana += a.ManaCost
+}
+
+func (r *recorder) battle(teams [2][]*unit, winner, turns int) {
+	r.battles++
+	r.turns += turns
+	if winner < 0 {
+		r.draws++
+	}
+
+	for _, d := range Dimensions {
+		for _, a := range teams[0] {
+			for _, b := range teams[1] {
+				for _, side := range [2][2]*unit{{a, b}, {b, a}} {
+					c := r.cell(d, side[0].attr(d), side[1].attr(d))
+					c.Pairs++
+					switch {
+					case winner < 0:
+						c.Draws++
+					case side[0].team == winner:
+						c.Wins++
+					}
+				}
+			}
+		}
+		for _, team := range teams {
+			for _, u := range team {
+				t := r.knockouts[d][u.attr(d)]
+				if t == nil {
+					t = &koTally{}
+					r.knockouts[d][u.attr(d)] = t
+				}
+				t.units++
+				if u.knockedOutAt > 0 {
+					t.knockouts++
+					t.turns += u.knockedOutAt
+				}
+			}
+		}
+	}
+
+	// An ability counts once per team that used it
+	for side, team := range teams {
+		used := map[string]bool{}
+		for _, u := range team {
+			for name := range u.used {
+				used[name] = true
+			}
+		}
+		for name := range used {
+			if t := r.abilities[name]; t != nil {
+				t.battles++
+				if side == winner {
+					t.wins++
+				}
+			}
+		}
+	}
+}
+
+func (r *recorder) cell(dimension, row, col string) *Cell {
+	k := pair{row, col}
+	c := r.cells[dimension][k]
+	if c == nil {
+		c = &Cell{Row: row, Col: col}
+		r.cells[dimension][k] = c
+	}
+	return c
+}
+
+func (r *recorder) report() *Report {
+	rep := &Report{
+		Config:  r.sim.cfg,
+		Battles: r.battles,
+		Draws:   r.draws,
+	}
+	if r.battles > 0 {
+		rep.AvgTurns = float64(r.turns) / float64(r.battles)
+	}
+
+	for _, d := range Dimensions {
+		m := Matrix{Dimension: d, Values: r.sim.values(d)}
+		for _, row := range m.Values {
+			for _, col := range m.Values {
+				c := Cell{Row: row, Col: col}
+				if got := r.cells[d][pair{row, col}]; got != nil {
+					c = *got
+				}
+				if c.Pairs > 0 {
+					c.WinRate = float64(c.Wins) / float64(c.Pairs)
+				}
+				m.Cells = append(m.Cells, c)
+			}
+		}
+		rep.Matrices = append(rep.Matrices, m)
+
+		for _, v := range m.Values {
+			t := r.knockouts[d][v]
+			if t == nil {
+				continue
+			}
+			ko := KnockoutStat{Dimension: d, Value: v, Units: t.units, Knockouts: t.knockouts}
+			if t.knockouts > 0 {
+				ko.AvgTurns = float64(t.turns) / float64(t.knockouts)
+			}
+			rep.Knockouts = append(rep.Knockouts, ko)
+		}
+	}
+
+	for name, t := range r.abilities {
+		a := AbilityStat{
+			Name:      name,
+			Class:     t.class,
+			Uses:      t.uses,
+			Damage:    t.damage,
+			Healing:   t.healing,
+			Knockouts: t.kills,
+		}
+		if t.uses > 0 {
+			a.AvgDamage = float64(t.damage) / float64(t.uses)
+			a.AvgHealing = float64(t.healing) / float64(t.uses)
+		}
+		if t.mana > 0 {
+			a.DamagePerMana = float64(t.damage) / float64(t.mana)
+		}
+		if t.battles > 0 {
+			a.WinRate = float64(t.wins) / float64(t.battles)
+		}
+		rep.Abilities = append(rep.Abilities, a)
+	}
+	sort.Slice(rep.Abilities, func(i, j int) bool {
+		a, b := rep.Abilities[i], rep.Abilities[j]
+		if a.Class != b.Class {
+			return a.Class < b.Class
+		}
+		return a.Name < b.Name
+	})
+	r.scoreAbilities(rep.Abilities)
+
+	return rep
+}
+
+// scoreAbilities z-scores average damage (among damaging abilities) and win
+// rate (among all) over the abilities used at least MinUses times, and flags
+// the ones past OutlierZ
+func (r *recorder) scoreAbilities(abilities []AbilityStat) {
+	cfg := r.sim.cfg
+	var damage, winRate []float64
+	for _, a := range abilities {
+		if a.Uses < cfg.MinUses {
+			continue
+		}
+		if a.Damage > 0 {
+			damage = append(damage, a.AvgDamage)
+		}
+		winRate = append(winRate, a.WinRate)
+	}
+	dMean, dStd := meanStd(damage)
+	wMean, wStd := meanStd(winRate)
+
+	for i := range abilities {
+		a := &abilities[i]
+		if a.Uses < cfg.MinUses {
+			continue
+		}
+		if a.Damage > 0 && dStd > 0 {
+			a.DamageZ = (a.AvgDamage - dMean) / dStd
+		}
+		if wStd > 0 {
+			a.WinRateZ = (a.WinRate - wMean) / wStd
+		}
+		a.Outlier = math.Abs(a.DamageZ) >= cfg.OutlierZ || math.Abs(a.WinRateZ) >= cfg.OutlierZ
+	}
+}
+
+func meanStd(xs []float64) (float64, float64) {
+	if len(xs) == 0 {
+		return 0, 0
+	}
+	sum := 0.0
+	for _, x := range xs {
+		sum += x
+	}
+	mean := sum / float64(len(xs))
+	v := 0.0
+	for _, x := range xs {
+		v += (x - mean) * (x - mean)
+	}
+	return mean, math.Sqrt(v / float64(len(xs)))
+}
+
+// values lists a dimension's possible values in display order
+func (s *Sim) values(dimension string) []string {
+	switch dimension {
+	case "class":
+		return s.classes
+	case "element":
+		return s.elements
+	case "type":
+		return s.types
+	}
+	return Ranks
+}
+
+// WriteJSON writes the whole report as indented JSON
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteCSV writes matrix_<dimension>.csv, knockouts.csv and abilities.csv to dir
+func (r *Report) WriteCSV(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	for _, m := range r.Matrices {
+		rows := [][]string{{m.Dimension, "vs", "pairs", "wins", "draws", "win_rate"}}
+		for _, c := range m.Cells {
+			rows = append(rows, []string{c.Row, c.Col, itoa(c.Pairs), itoa(c.Wins), itoa(c.Draws), ftoa(c.WinRate)})
+		}
+		if err := writeCSV(filepath.Join(dir, "matrix_"+m.Dimension+".csv"), rows); err != nil {
+			return err
+		}
+	}
+
+	rows := [][]string{{"dimension", "value", "units", "knockouts", "avg_turns_to_ko"}}
+	for _, k := range r.Knockouts {
+		rows = append(rows, []string{k.Dimension, k.Value, itoa(k.Units), itoa(k.Knockouts), ftoa(k.AvgTurns)})
+	}
+	if err := writeCSV(filepath.Join(dir, "knockouts.csv"), rows); err != nil {
+		return err
+	}
+
+	rows = [][]string{{"name", "class", "uses", "damage", "healing", "knockouts", "avg_damage", "avg_healing",
+		"damage_per_mana", "win_rate", "damage_z", "win_rate_z", "outlier"}}
+	for _, a := range r.Abilities {
+		rows = append(rows, []string{a.Name, a.Class, itoa(a.Uses), itoa(a.Damage), itoa(a.Healing), itoa(a.Knockouts),
+			ftoa(a.AvgDamage), ftoa(a.AvgHealing), ftoa(a.DamagePerMana), ftoa(a.WinRate),
+			ftoa(a.DamageZ), ftoa(a.WinRateZ), strconv.FormatBool(a.Outlier)})
+	}
+	return writeCSV(filepath.Join(dir, "abilities.csv"), rows)
+}
+
+func writeCSV(path string, rows [][]string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := csv.NewWriter(f)
+	if err := w.WriteAll(rows); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return f.Close()
+}
+
+func itoa(n int) string { return strconv.Itoa(n) }
+
+func ftoa(f float64) string { return strconv.FormatFloat(f, 'f', 4, 64) }
@@ -0,0 +1,205 @@
+// Package balance runs seeded AI-vs-AI battles between generated teams, with no
+// database, so changes to the rarity odds, the type/element chart, class
+// advantages, passives and abilities can be measured before they ship.
+package balance
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/internal/seed"
+	"github.com/lorengraff/crypto-tower-defense/internal/services"
+	"github.com/lorengraff/crypto-tower-defense/pkg/formulas"
+)
+
+// Ranks in ascending order
+var Ranks = []string{"C", "B", "A", "S", "SS", "SSS"}
+
+// Config controls a simulation run
+type Config struct {
+	Battles  int   `json:"battles"`
+	Seed     int64 `json:"seed"`
+	TeamSize int   `json:"team_size"`
+	Level    int   `json:"level"`
+	// Rarity is "uniform" (every rank equally likely) or "gacha" (the mint
+	// odds for TowerAmount)
+	Rarity      string  `json:"rarity"`
+	TowerAmount int64   `json:"tower_amount"`
+	OutlierZ    float64 `json:"outlier_z"` // |z-score| at which an ability is flagged
+	MinUses     int     `json:"min_uses"`  // Abilities used less are not scored
+}
+
+// DefaultConfig is a run that fills every matrix cell in a few seconds
+func DefaultConfig() Config {
+	return Config{
+		Battles:  5000,
+		Seed:     1,
+		TeamSize: 3,
+		Level:    1,
+		Rarity:   "uniform",
+		OutlierZ: 2,
+		MinUses:  30,
+	}
+}
+
+// Sim generates teams from the content bundle and fights them
+type Sim struct {
+	cfg       Config
+	settings  seed.SettingValues
+	classes   []string
+	elements  []string
+	types     []string
+	abilities map[string][]seed.Ability // Usable abilities by class
+	odds      []float64                 // Cumulative, indexed like Ranks
+}
+
+// New prepares a run over the abilities and settings of b
+func New(b *seed.Bundle, cfg Config) (*Sim, error) {
+	if cfg.Battles < 1 || cfg.TeamSize < 1 || cfg.Level < 1 {
+		return nil, errors.New("battles, team size and level must be at least 1")
+	}
+
+	s := &Sim{
+		cfg:       cfg,
+		settings:  b.SettingValues(),
+		abilities: map[string][]seed.Ability{},
+	}
+	for _, a := range b.Abilities {
+		if a.UnlockLevel > cfg.Level || a.AbilityType == "PASSIVE" {
+			continue
+		}
+		if a.Damage == 0 && a.BaseDamage == 0 && a.BaseHeal == 0 {
+			continue // Pure buffs: the battle loop does not model them
+		}
+		if _, ok := s.abilities[a.Class]; !ok {
+			s.classes = append(s.classes, a.Class)
+		}
+		s.abilities[a.Class] = append(s.abilities[a.Class], a)
+	}
+	if len(s.classes) == 0 {
+		return nil, fmt.Errorf("no usable abilities at level %d", cfg.Level)
+	}
+	sort.Strings(s.classes)
+
+	// Types and elements come from the chart so a new row or column is picked up
+	seen := map[string]bool{}
+	for charType, row := range formulas.TypeElementMatrix {
+		s.types = append(s.types, charType)
+		for element := range row {
+			if !seen[element] {
+				seen[element] = true
+				s.elements = append(s.elements, element)
+			}
+		}
+	}
+	sort.Strings(s.types)
+	sort.Strings(s.elements)
+
+	var weights map[string]float64
+	switch cfg.Rarity {
+	case "uniform":
+		weights = map[string]float64{}
+		for _, r := range Ranks {
+			weights[r] = 1
+		}
+	case "gacha":
+		weights = services.NewGachaService(nil, nil).GetOddsPreview(cfg.TowerAmount)
+	default:
+		return nil, fmt.Errorf("unknown rarity distribution %q (want uniform or gacha)", cfg.Rarity)
+	}
+	total := 0.0
+	for _, r := range Ranks {
+		total += weights[r]
+		s.odds = append(s.odds, total)
+	}
+	for i := range s.odds {
+		s.odds[i] /= total
+	}
+
+	return s, nil
+}
+
+// Run fights cfg.Battles battles. Battle i is seeded with Seed+i, so a run is
+// reproducible and any single battle can be replayed.
+func (s *Sim) Run() *Report {
+	rec := newRecorder(s)
+	for i := 0; i < s.cfg.Battles; i++ {
+		rng := rand.New(rand.NewSource(s.cfg.Seed + int64(i)))
+		teams := [2][]*unit{s.team(rng, 0), s.team(rng, 1)}
+		winner, turns := s.fight(rng, teams, rec)
+		rec.battle(teams, winner, turns)
+	}
+	return rec.report()
+}
+
+// unit is one character in a simulated battle
+type unit struct {
+	team                           int
+	class, element, charType, rank string
+	hp, maxHP, atk, def, speed     int
+	mana, maxMana                  int
+	abilities                      []seed.Ability
+	cooldowns                      map[string]int
+	used                           map[string]bool
+	knockedOutAt                   int // Turn the unit fell on, 0 while standing
+}
+
+func (s *Sim) team(rng *rand.Rand, side int) []*unit {
+	team := make([]*unit, s.cfg.TeamSize)
+	for i := range team {
+		team[i] = s.newUnit(rng, side)
+	}
+	return team
+}
+
+// newUnit rolls a character the way a hatch does, then runs it through the
+// stat pipeline at the configured level
+func (s *Sim) newUnit(rng *rand.Rand, side int) *unit {
+	rank := s.rollRank(rng)
+	class := s.classes[rng.Intn(len(s.classes))]
+	level := s.cfg.Level
+
+	atk, def, hp, speed := services.NewCharacterService().BaseStats(rank)
+	stats := services.ComputeStats(services.StatInput{Character: models.Character{
+		BaseAttack:     atk,
+		BaseDefense:    def,
+		BaseHP:         hp,
+		BaseSpeed:      speed,
+		Level:          level,
+		Rarity:         rank,
+		EvolutionStage: formulas.GetEvolutionStage(level),
+	}}).Final
+	atk, def, hp, speed = stats.Attack, stats.Defense, stats.HP, stats.Speed
+	maxMana := services.NewManaService().CalculateMaxMana(rank, level)
+
+	return &unit{
+		team:      side,
+		class:     class,
+		element:   s.elements[rng.Intn(len(s.elements))],
+		charType:  s.types[rng.Intn(len(s.types))],
+		rank:      rank,
+		hp:        hp,
+		maxHP:     hp,
+		atk:       atk,
+		def:       def,
+		speed:     speed,
+		mana:      maxMana,
+		maxMana:   maxMana,
+		abilities: s.abilities[class],
+		cooldowns: map[string]int{},
+		used:      map[string]bool{},
+	}
+}
+
+func (s *Sim) rollRank(rng *rand.Rand) string {
+	roll := rng.Float64()
+	for i, p := range s.odds {
+		if roll < p {
+			return Ranks[i]
+		}
+	}
+	return Ranks[len(Ranks)-1]
+}
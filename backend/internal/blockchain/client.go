@@ -16,6 +16,7 @@ import (
 	"github.com/ethereum/go-ethereum/ethclient"
 
 	"github.com/lorengraff/crypto-tower-defense/internal/blockchain/contracts"
+	"github.com/lorengraff/crypto-tower-defense/pkg/tracing"
 )
 
 // Client wraps Ethereum client with contract instances
@@ -28,6 +29,7 @@ type Client struct {
 	// Contract instances
 	gameToken    *contracts.GameToken
 	towerToken   *contracts.TowerToken
+	towerAddress common.Address
 	characterNFT *contracts.CharacterNFT
 	itemNFT      *contracts.ItemNFT
 	
@@ -125,6 +127,7 @@ func NewClient(rpcURL string) (*Client, error) {
 		fromAddress:  fromAddress,
 		gameToken:    gameToken,
 		towerToken:   towerToken,
+		towerAddress: towerAddress,
 		characterNFT: characterNFT,
 		itemNFT:      itemNFT,
 		gasLimit:     gasLimit,
@@ -164,13 +167,16 @@ func (c *Client) getTransactor(ctx context.Context) (*bind.TransactOpts, error)
 }
 
 // MintGTK mints GTK tokens to specified address
-func (c *Client) MintGTK(ctx context.Context, to common.Address, amount *big.Int) (*types.Transaction, error) {
+func (c *Client) MintGTK(ctx context.Context, to common.Address, amount *big.Int) (tx *types.Transaction, err error) {
+	ctx, span := tracing.Start(ctx, "chain.mint_gtk", "to", to.Hex())
+	defer func() { span.End(err) }()
+
 	auth, err := c.getTransactor(ctx)
 	if err != nil {
 		return nil, err
 	}
 	
-	tx, err := c.gameToken.Mint(auth, to, amount)
+	tx, err = c.gameToken.Mint(auth, to, amount)
 	if err != nil {
 		return nil, fmt.Errorf("failed to mint GTK: %w", err)
 	}
@@ -180,13 +186,16 @@ func (c *Client) MintGTK(ctx context.Context, to common.Address, amount *big.Int
 }
 
 // MintTower mints TOWER tokens to specified address
-func (c *Client) MintTower(ctx context.Context, to common.Address, amount *big.Int) (*types.Transaction, error) {
+func (c *Client) MintTower(ctx context.Context, to common.Address, amount *big.Int) (tx *types.Transaction, err error) {
+	ctx, span := tracing.Start(ctx, "chain.mint_tower", "to", to.Hex())
+	defer func() { span.End(err) }()
+
 	auth, err := c.getTransactor(ctx)
 	if err != nil {
 		return nil, err
 	}
 	
-	tx, err := c.towerToken.Mint(auth, to, amount)
+	tx, err = c.towerToken.Mint(auth, to, amount)
 	if err != nil {
 		return nil, fmt.Errorf("failed to mint TOWER: %w", err)
 	}
@@ -205,13 +214,16 @@ func (c *Client) MintCharacterNFT(
 	rarity string,
 	level *big.Int,
 	tokenURI string,
-) (*types.Transaction, error) {
+) (tx *types.Transaction, err error) {
+	ctx, span := tracing.Start(ctx, "chain.mint_character_nft", "to", to.Hex())
+	defer func() { span.End(err) }()
+
 	auth, err := c.getTransactor(ctx)
 	if err != nil {
 		return nil, err
 	}
 	
-	tx, err := c.characterNFT.MintCharacter(auth, to, gameCharacterID, characterType, element, rarity, level, tokenURI)
+	tx, err = c.characterNFT.MintCharacter(auth, to, gameCharacterID, characterType, element, rarity, level, tokenURI)
 	if err != nil {
 		return nil, fmt.Errorf("failed to mint character NFT: %w", err)
 	}
@@ -221,13 +233,16 @@ func (c *Client) MintCharacterNFT(
 }
 
 // MintItemNFT mints item NFT(s)
-func (c *Client) MintItemNFT(ctx context.Context, to common.Address, itemID *big.Int, amount *big.Int) (*types.Transaction, error) {
+func (c *Client) MintItemNFT(ctx context.Context, to common.Address, itemID *big.Int, amount *big.Int) (tx *types.Transaction, err error) {
+	ctx, span := tracing.Start(ctx, "chain.mint_item_nft", "to", to.Hex())
+	defer func() { span.End(err) }()
+
 	auth, err := c.getTransactor(ctx)
 	if err != nil {
 		return nil, err
 	}
 	
-	tx, err := c.itemNFT.Mint(auth, to, itemID, amount)
+	tx, err = c.itemNFT.Mint(auth, to, itemID, amount)
 	if err != nil {
 		return nil, fmt.Errorf("failed to mint item NFT: %w", err)
 	}
@@ -268,6 +283,11 @@ func (c *Client) WaitForTransaction(ctx context.Context, tx *types.Transaction)
 	return receipt, nil
 }
 
+// TransferManager returns a nonce-managed TOWER transfer signer sharing this client's key and gas settings
+func (c *Client) TransferManager() (*TransferManager, error) {
+	return NewTransferManager(c.ethClient, c.chainID, c.privateKey, c.towerAddress, c.gasLimit, c.maxGasPrice)
+}
+
 // Close closes the blockchain client
 func (c *Client) Close() {
 	c.ethClient.Close()
@@ -0,0 +1,223 @@
+package blockchain
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/blockchain/contracts"
+	"github.com/lorengraff/crypto-tower-defense/pkg/tracing"
+)
+
+// TowerDecimals is the ERC-20 precision of the TOWER token
+const TowerDecimals = 18
+
+// ErrGasPriceCap is returned when a replacement would exceed the configured max gas price
+var ErrGasPriceCap = errors.New("replacement gas price exceeds max gas price")
+
+// Backend is the chain access the transfer manager needs.
+// Both *ethclient.Client and the go-ethereum simulated backend client satisfy it.
+type Backend interface {
+	bind.ContractBackend
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+	BlockNumber(ctx context.Context) (uint64, error)
+}
+
+// TransferManager signs TOWER transfers from the hot wallet.
+// It hands out nonces locally so a batch can be broadcast without waiting for each transaction to be mined.
+type TransferManager struct {
+	backend     Backend
+	chainID     *big.Int
+	privateKey  *ecdsa.PrivateKey
+	fromAddress common.Address
+	token       *contracts.TowerTokenTransactor
+
+	gasLimit    uint64
+	maxGasPrice *big.Int
+
+	mu        sync.Mutex
+	nextNonce uint64
+}
+
+// NewTransferManager creates a transfer manager for the TOWER token at tokenAddress
+func NewTransferManager(backend Backend, chainID *big.Int, privateKey *ecdsa.PrivateKey, tokenAddress common.Address, gasLimit uint64, maxGasPrice *big.Int) (*TransferManager, error) {
+	token, err := contracts.NewTowerTokenTransactor(tokenAddress, backend)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind TowerToken: %w", err)
+	}
+	return &TransferManager{
+		backend:     backend,
+		chainID:     chainID,
+		privateKey:  privateKey,
+		fromAddress: crypto.PubkeyToAddress(privateKey.PublicKey),
+		token:       token,
+		gasLimit:    gasLimit,
+		maxGasPrice: maxGasPrice,
+	}, nil
+}
+
+// ChainID returns the chain the manager signs for
+func (m *TransferManager) ChainID() *big.Int {
+	return m.chainID
+}
+
+// From returns the hot wallet address
+func (m *TransferManager) From() common.Address {
+	return m.fromAddress
+}
+
+// Transfer sends a TOWER transfer with the next free nonce.
+// The local nonce only advances when the node accepts the transaction.
+func (m *TransferManager) Transfer(ctx context.Context, to common.Address, amount *big.Int) (_ *types.Transaction, err error) {
+	ctx, span := tracing.Start(ctx, "chain.transfer", "to", to.Hex())
+	defer func() { span.End(err) }()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// The node's pending nonce wins if it is ahead (e.g. transactions sent by another process)
+	pending, err := m.backend.PendingNonceAt(ctx, m.fromAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nonce: %w", err)
+	}
+	if pending > m.nextNonce {
+		m.nextNonce = pending
+	}
+
+	gasPrice, err := m.backend.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gas price: %w", err)
+	}
+	if gasPrice.Cmp(m.maxGasPrice) > 0 {
+		gasPrice = new(big.Int).Set(m.maxGasPrice)
+	}
+
+	tx, err := m.send(ctx, m.nextNonce, to, amount, gasPrice)
+	if err != nil {
+		return nil, err
+	}
+	m.nextNonce++
+	return tx, nil
+}
+
+// PendingNonce returns the hot wallet's next nonce as the node sees it, pending transactions included
+func (m *TransferManager) PendingNonce(ctx context.Context) (uint64, error) {
+	nonce, err := m.backend.PendingNonceAt(ctx, m.fromAddress)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get nonce: %w", err)
+	}
+	return nonce, nil
+}
+
+// Sign builds and signs a TOWER transfer with an explicit nonce at the current gas price (capped
+// at the max gas price) without broadcasting it, so the caller can record its hash first
+func (m *TransferManager) Sign(ctx context.Context, nonce uint64, to common.Address, amount *big.Int) (_ *types.Transaction, err error) {
+	ctx, span := tracing.Start(ctx, "chain.sign", "to", to.Hex(), "nonce", nonce)
+	defer func() { span.End(err) }()
+
+	gasPrice, err := m.backend.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gas price: %w", err)
+	}
+	if gasPrice.Cmp(m.maxGasPrice) > 0 {
+		gasPrice = new(big.Int).Set(m.maxGasPrice)
+	}
+	return m.transact(ctx, nonce, to, amount, gasPrice, true)
+}
+
+// Broadcast sends a transaction signed by Sign
+func (m *TransferManager) Broadcast(ctx context.Context, tx *types.Transaction) (err error) {
+	ctx, span := tracing.Start(ctx, "chain.broadcast", "tx_hash", tx.Hash().Hex(), "nonce", tx.Nonce())
+	defer func() { span.End(err) }()
+
+	if err := m.backend.SendTransaction(ctx, tx); err != nil {
+		return fmt.Errorf("failed to broadcast TOWER transfer: %w", err)
+	}
+	return nil
+}
+
+// Replace re-sends a stuck transfer with the same nonce and a gas price bumped by bumpPercent.
+// Nodes reject replacements below a 10% bump.
+func (m *TransferManager) Replace(ctx context.Context, nonce uint64, to common.Address, amount, prevGasPrice *big.Int, bumpPercent int) (_ *types.Transaction, err error) {
+	ctx, span := tracing.Start(ctx, "chain.replace", "to", to.Hex(), "nonce", nonce)
+	defer func() { span.End(err) }()
+
+	gasPrice := new(big.Int).Mul(prevGasPrice, big.NewInt(int64(100+bumpPercent)))
+	gasPrice.Div(gasPrice, big.NewInt(100))
+	if gasPrice.Cmp(m.maxGasPrice) > 0 {
+		return nil, ErrGasPriceCap
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.send(ctx, nonce, to, amount, gasPrice)
+}
+
+// Status returns the receipt of a mined transaction and its confirmation count.
+// A transaction that is not mined yet returns a nil receipt and no error.
+func (m *TransferManager) Status(ctx context.Context, txHash common.Hash) (_ *types.Receipt, _ uint64, err error) {
+	ctx, span := tracing.Start(ctx, "chain.status", "tx_hash", txHash.Hex())
+	defer func() { span.End(err) }()
+
+	receipt, err := m.backend.TransactionReceipt(ctx, txHash)
+	if err != nil {
+		// Nodes still building their tx index report unknown hashes this way too
+		if errors.Is(err, ethereum.NotFound) || strings.Contains(err.Error(), "indexing is in progress") {
+			return nil, 0, nil
+		}
+		return nil, 0, fmt.Errorf("failed to get receipt: %w", err)
+	}
+
+	head, err := m.backend.BlockNumber(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get block number: %w", err)
+	}
+	if head < receipt.BlockNumber.Uint64() {
+		return receipt, 0, nil
+	}
+	return receipt, head - receipt.BlockNumber.Uint64() + 1, nil
+}
+
+// send signs and broadcasts a legacy-priced transfer with an explicit nonce
+func (m *TransferManager) send(ctx context.Context, nonce uint64, to common.Address, amount, gasPrice *big.Int) (*types.Transaction, error) {
+	return m.transact(ctx, nonce, to, amount, gasPrice, false)
+}
+
+// transact signs a legacy-priced transfer with an explicit nonce and broadcasts it unless noSend
+func (m *TransferManager) transact(ctx context.Context, nonce uint64, to common.Address, amount, gasPrice *big.Int, noSend bool) (*types.Transaction, error) {
+	auth, err := bind.NewKeyedTransactorWithChainID(m.privateKey, m.chainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transactor: %w", err)
+	}
+	auth.Context = ctx
+	auth.Nonce = new(big.Int).SetUint64(nonce)
+	auth.Value = big.NewInt(0)
+	auth.GasLimit = m.gasLimit
+	auth.GasPrice = gasPrice
+	auth.NoSend = noSend
+
+	tx, err := m.token.Transfer(auth, to, amount)
+	if err != nil {
+		if noSend {
+			return nil, fmt.Errorf("failed to sign TOWER transfer: %w", err)
+		}
+		return nil, fmt.Errorf("failed to send TOWER transfer: %w", err)
+	}
+	return tx, nil
+}
+
+// TowerToWei converts whole TOWER to the token's base unit
+func TowerToWei(amount int64) *big.Int {
+	wei := new(big.Int).Exp(big.NewInt(10), big.NewInt(TowerDecimals), nil)
+	return wei.Mul(wei, big.NewInt(amount))
+}
@@ -0,0 +1,187 @@
+package blockchain
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+)
+
+// The simulated chain has no TOWER bytecode; a transfer to a code-less token address still
+// exercises signing, nonce assignment, replacement and receipts end to end.
+var testToken = common.HexToAddress("0x00000000000000000000000000000000000070E5")
+
+func newTestManager(t *testing.T, maxGasPrice *big.Int) (*TransferManager, *simulated.Backend, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	balance := new(big.Int).Mul(big.NewInt(1000), big.NewInt(1e18))
+	sim := simulated.NewBackend(types.GenesisAlloc{from: {Balance: balance}})
+	t.Cleanup(func() { sim.Close() })
+
+	chainID, err := sim.Client().ChainID(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := NewTransferManager(sim.Client(), chainID, key, testToken, 100000, maxGasPrice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m, sim, key
+}
+
+func gwei(n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), big.NewInt(1e9))
+}
+
+func TestTransferAssignsSequentialNonces(t *testing.T) {
+	ctx := context.Background()
+	m, sim, _ := newTestManager(t, gwei(100))
+	to := common.HexToAddress("0x1111111111111111111111111111111111111111")
+
+	var sent []*types.Transaction
+	for i := 0; i < 3; i++ {
+		tx, err := m.Transfer(ctx, to, TowerToWei(100))
+		if err != nil {
+			t.Fatalf("transfer %d: %v", i, err)
+		}
+		if tx.Nonce() != uint64(i) {
+			t.Fatalf("transfer %d got nonce %d", i, tx.Nonce())
+		}
+		sent = append(sent, tx)
+	}
+	sim.Commit()
+
+	for _, tx := range sent {
+		receipt, confirmations, err := m.Status(ctx, tx.Hash())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if receipt == nil || receipt.Status != types.ReceiptStatusSuccessful {
+			t.Fatalf("tx %s not mined successfully", tx.Hash().Hex())
+		}
+		if confirmations != 1 {
+			t.Fatalf("expected 1 confirmation, got %d", confirmations)
+		}
+	}
+}
+
+func TestTransferPicksUpNodeNonce(t *testing.T) {
+	ctx := context.Background()
+	m, sim, key := newTestManager(t, gwei(100))
+	to := common.HexToAddress("0x2222222222222222222222222222222222222222")
+
+	// Another signer using the same key (e.g. the minting client) spends nonce 0
+	chainID, _ := sim.Client().ChainID(ctx)
+	other, err := NewTransferManager(sim.Client(), chainID, key, testToken, 100000, gwei(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.Transfer(ctx, to, TowerToWei(1)); err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := m.Transfer(ctx, to, TowerToWei(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Nonce() != 1 {
+		t.Fatalf("expected nonce 1 after external send, got %d", tx.Nonce())
+	}
+}
+
+func TestReplaceBumpsStuckTransfer(t *testing.T) {
+	ctx := context.Background()
+	m, sim, _ := newTestManager(t, gwei(100))
+	to := common.HexToAddress("0x3333333333333333333333333333333333333333")
+	amount := TowerToWei(250)
+
+	original, err := m.Transfer(ctx, to, amount)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Not mined yet: replace with the same nonce and a 15% higher gas price
+	replacement, err := m.Replace(ctx, original.Nonce(), to, amount, original.GasPrice(), 15)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replacement.Nonce() != original.Nonce() {
+		t.Fatalf("replacement nonce %d != original %d", replacement.Nonce(), original.Nonce())
+	}
+	if replacement.GasPrice().Cmp(original.GasPrice()) <= 0 {
+		t.Fatalf("replacement gas price %s not above %s", replacement.GasPrice(), original.GasPrice())
+	}
+	sim.Commit()
+
+	receipt, _, err := m.Status(ctx, replacement.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if receipt == nil || receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatal("replacement was not mined")
+	}
+	if receipt, _, _ := m.Status(ctx, original.Hash()); receipt != nil {
+		t.Fatal("original transfer should have been replaced")
+	}
+
+	// The next transfer continues after the replaced nonce
+	next, err := m.Transfer(ctx, to, amount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.Nonce() != original.Nonce()+1 {
+		t.Fatalf("expected nonce %d, got %d", original.Nonce()+1, next.Nonce())
+	}
+}
+
+func TestReplaceRespectsGasCap(t *testing.T) {
+	ctx := context.Background()
+	m, _, _ := newTestManager(t, gwei(100))
+	to := common.HexToAddress("0x4444444444444444444444444444444444444444")
+
+	tx, err := m.Transfer(ctx, to, TowerToWei(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.maxGasPrice = tx.GasPrice()
+
+	if _, err := m.Replace(ctx, tx.Nonce(), to, TowerToWei(1), tx.GasPrice(), 15); !errors.Is(err, ErrGasPriceCap) {
+		t.Fatalf("expected ErrGasPriceCap, got %v", err)
+	}
+}
+
+func TestStatusCountsConfirmations(t *testing.T) {
+	ctx := context.Background()
+	m, sim, _ := newTestManager(t, gwei(100))
+	to := common.HexToAddress("0x5555555555555555555555555555555555555555")
+
+	tx, err := m.Transfer(ctx, to, TowerToWei(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if receipt, _, err := m.Status(ctx, tx.Hash()); err != nil || receipt != nil {
+		t.Fatalf("pending transfer should have no receipt (err=%v)", err)
+	}
+
+	sim.Commit()
+	sim.Commit()
+	sim.Commit()
+
+	_, confirmations, err := m.Status(ctx, tx.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if confirmations != 3 {
+		t.Fatalf("expected 3 confirmations, got %d", confirmations)
+	}
+}
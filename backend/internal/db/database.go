@@ -1,11 +1,16 @@
 package db
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"time"
 
+	"github.com/lorengraff/crypto-tower-defense/content"
+	"github.com/lorengraff/crypto-tower-defense/internal/migrate"
 	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/internal/seed"
+	"github.com/lorengraff/crypto-tower-defense/migrations"
 	"github.com/lorengraff/crypto-tower-defense/pkg/config"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -27,7 +32,7 @@ func Connect(cfg *config.Config) error {
 
 	var err error
 	DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+		Logger: logger.Default.LogMode(logger.Warn), // Per-query timing is covered by tracing spans
 		NowFunc: func() time.Time {
 			return time.Now().UTC()
 		},
@@ -37,69 +42,43 @@ func Connect(cfg *config.Config) error {
 		return fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	if err := registerTracing(DB); err != nil {
+		return fmt.Errorf("failed to register query tracing: %w", err)
+	}
+
 	log.Println("Database connection established successfully")
 	return nil
 }
 
-// Migrate runs all database migrations
-// NOTE: AutoMigrate is DISABLED to prevent data loss
-// Use SQL migrations in backend/migrations/ instead
-func Migrate() error {
-	log.Println("⚠️  AutoMigrate is DISABLED - Using SQL migrations")
-	log.Println("📁 To apply fresh schema: psql -d tower_defense_dev < backend/migrations/001_initial_schema.sql")
+// Migrate checks the schema against the SQL migrations embedded from
+// backend/migrations. With apply set (MIGRATE_ON_START=true) pending migrations
+// are run; otherwise they are reported and left to `go run ./cmd/migrate up`.
+func Migrate(apply bool) error {
+	ctx := context.Background()
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get database handle: %w", err)
+	}
+	runner, err := migrate.New(sqlDB, migrations.FS)
+	if err != nil {
+		return fmt.Errorf("invalid migrations: %w", err)
+	}
 
-	// COMMENTED OUT - AutoMigrate wipes data on restart
-	/*
-		err := DB.AutoMigrate(
-			&models.User{},
-			&models.Character{},
-			&models.CharacterMove{},
-			&models.Battle{},
-			&models.BattleState{},
-			&models.MarketplaceListing{},
-			&models.Transaction{},
-			&models.Egg{},
-			&models.Equipment{},
-			&models.DailyQuest{},
-			&models.ShopItem{},
-			&models.Item{},
-			&models.UserInventory{},
-			&models.Mission{},
-			&models.Team{},
-			&models.TeamMember{},
-			&models.Island{},
-			&models.RaidBoss{},
-			&models.RaidSession{},
-			&models.Ability{},
-			&models.AbilityLearning{},
-			&models.CharacterLearnedAbility{},
-			&models.CharacterAbility{},
-			&models.CharacterActiveSkill{},
-			&models.CharacterSkillCooldown{},
-			&models.AbilityUsage{},
-			&models.CharacterStatusEffect{},
-			&models.CharacterBuff{},
-			&models.SpriteGenerationJob{},
-			&models.RevenueTransaction{},
-			&models.RevenueDistribution{},
-			&models.AdminAction{},
-			&models.AntiCheatFlag{},
-			&models.GameConfig{},
-			&models.AuditLog{},
-			&models.SystemSetting{},
-			&models.AdminAuditLog{},
-			&models.UserReport{},
-			&models.LedgerAccount{},
-			&models.LedgerTransaction{},
-			&models.LedgerEntry{},
-			&models.QuestTemplate{},
-		)
-		if err != nil {
+	if apply {
+		if _, err := runner.Up(ctx, 0); err != nil {
 			return fmt.Errorf("migration failed: %w", err)
 		}
-	*/
+	} else {
+		pending, err := runner.Pending(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to check migrations: %w", err)
+		}
+		if len(pending) > 0 {
+			log.Printf("⚠️  %d pending migrations (%s..%s) - run: go run ./cmd/migrate up", len(pending), pending[0], pending[len(pending)-1])
+		}
+	}
 
-	log.Println("Database migrations completed successfully")
+	log.Println("Database migrations checked")
 
 	// Emergency: Ensure at least one SUPER_ADMIN exists for development
 	var adminCount int64
@@ -112,51 +91,6 @@ func Migrate() error {
 		DB.Model(&models.User{}).Where("id = 1 AND role = 'PLAYER'").Update("role", "SUPER_ADMIN")
 	}
 
-	// Seed system settings
-	SeedSystemSettings(DB)
-
-	// Seed quest templates
-	if err := SeedQuestTemplates(DB); err != nil {
-		log.Printf("Warning: Failed to seed quest templates: %v", err)
-	} else {
-		log.Println("Quest templates seeded successfully")
-	}
-
-	// Seed missions (tutorial + progression)
-	if err := SeedMissions(DB); err != nil {
-		log.Printf("Warning: Failed to seed missions: %v", err)
-	} else {
-		log.Println("Missions seeded successfully")
-	}
-
-	// Seed abilities FIRST (required for ability_learning foreign keys)
-	if err := SeedAbilities(DB); err != nil {
-		log.Printf("Warning: Failed to seed abilities: %v", err)
-	} else {
-		log.Println("Abilities seeded successfully")
-	}
-
-	// Seed ability learning requirements
-	if err := SeedAbilityLearning(DB); err != nil {
-		log.Printf("Warning: Failed to seed ability learning: %v", err)
-	} else {
-		log.Println("Ability learning seeded successfully")
-	}
-
-	// Seed story dialogues
-	if err := SeedDialogues(DB); err != nil {
-		log.Printf("Warning: Failed to seed dialogues: %v", err)
-	} else {
-		log.Println("Story dialogues seeded successfully")
-	}
-
-	// Seed story fragments
-	if err := SeedStoryFragments(DB); err != nil {
-		log.Printf("Warning: Failed to seed story fragments: %v", err)
-	} else {
-		log.Println("Story fragments seeded successfully")
-	}
-
 	// Add indexes for performance
 	addIndexes()
 
@@ -197,69 +131,19 @@ func Close() error {
 	return sqlDB.Close()
 }
 
-// SeedSystemSettings populates the system_settings table with defaults
-func SeedSystemSettings(db *gorm.DB) {
-	settings := []models.SystemSetting{
-		{Key: "xp_multiplier", Value: "1.0", Type: "float", Description: "Global XP gain multiplier"},
-		{Key: "gacha_cost_gtk", Value: "1000", Type: "int", Description: "Cost to mint a new egg (in GTK)"},
-		{Key: "wager_tax_percent", Value: "5", Type: "int", Description: "Platform tax on wager battles (%)"},
-		{Key: "marketplace_fee_percent", Value: "3", Type: "int", Description: "Fee for selling items in marketplace (%)"},
-		{Key: "energy_recovery_per_hour", Value: "10", Type: "int", Description: "Passive energy recovery rate"},
-		{Key: "maintenance_mode", Value: "false", Type: "bool", Description: "If true, blocks all game access"},
-		{Key: "min_withdrawal_gtk", Value: "5000", Type: "int", Description: "Minimum GTK required for withdrawal"},
-		{Key: "daily_login_reward", Value: "50", Type: "int", Description: "GTK reward for daily login"},
-		{Key: "base_catch_rate", Value: "0.25", Type: "float", Description: "Base rate for catching characters"},
-
-		// Battle Constants
-		{Key: "battle_crit_chance", Value: "0.10", Type: "float", Description: "Base critical hit chance (0.0-1.0)"},
-		{Key: "battle_crit_multiplier", Value: "1.5", Type: "float", Description: "Damage multiplier for critical hits"},
-		{Key: "battle_randomness_factor", Value: "0.10", Type: "float", Description: "Damage variation factor (±10%)"},
-		{Key: "battle_def_reduction_cap", Value: "0.75", Type: "float", Description: "Maximum damage reduction from defense (0.0-1.0)"},
-		{Key: "battle_mana_gain_per_turn", Value: "10", Type: "int", Description: "Mana gained at start of each turn"},
-		{Key: "battle_max_turns", Value: "50", Type: "int", Description: "Maximum turns before battle timeout"},
-
-		// Gacha & Incubation Constants
-		{Key: "gacha_daily_mint_limit", Value: "10", Type: "int", Description: "Maximum egg mints per day"},
-		{Key: "gacha_incubation_c", Value: "6", Type: "int", Description: "Incubation time for C rank (hours)"},
-		{Key: "gacha_incubation_b", Value: "12", Type: "int", Description: "Incubation time for B rank (hours)"},
-		{Key: "gacha_incubation_a", Value: "24", Type: "int", Description: "Incubation time for A rank (hours)"},
-		{Key: "gacha_incubation_s", Value: "48", Type: "int", Description: "Incubation time for S rank (hours)"},
-		{Key: "gacha_incubation_ss", Value: "72", Type: "int", Description: "Incubation time for SS rank (hours)"},
-		{Key: "gacha_incubation_sss", Value: "96", Type: "int", Description: "Incubation time for SSS rank (hours)"},
-		{Key: "gacha_shiny_rate", Value: "0.01", Type: "float", Description: "Base rate for shiny character generation"},
-		{Key: "gacha_egg_rarity_weights", Value: "{\"C\": 50, \"B\": 30, \"A\": 15, \"S\": 4, \"SS\": 0.9, \"SSS\": 0.1}", Type: "json", Description: "Rarity weight distribution for gacha"},
-	}
-
-	for _, s := range settings {
-		var existing models.SystemSetting
-		if err := db.Where("key = ?", s.Key).First(&existing).Error; err != nil {
-			log.Printf("Seeding system setting: %s = %s", s.Key, s.Value)
-			db.Create(&s)
-		}
+// SeedContent upserts the embedded game content (backend/content) for profile.
+// It runs on every start, so content edits ship with the build.
+func SeedContent(profile string) error {
+	bundle, err := seed.Load(content.FS, profile)
+	if err != nil {
+		return err
 	}
-}
-
-// SeedQuestTemplates populates initial quest templates
-func SeedQuestTemplates(db *gorm.DB) error {
-	templates := []models.QuestTemplate{
-		{Type: "combat", Name: "Guardian of the Realm", Description: "Win %d battles using a Mono-Element team", BaseTarget: 3, ScaleFactor: 0.2, Difficulty: "rare", ActionType: "element_win", RewardGTK: 100},
-		{Type: "combat", Name: "Warlord's Path", Description: "Achieve a win streak of %d battles", BaseTarget: 5, ScaleFactor: 0.3, Difficulty: "epic", ActionType: "win_streak", RewardGTK: 200, RewardTOWER: 5},
-		{Type: "combat", Name: "Flawless Victory", Description: "Win %d battles with full HP remaining", BaseTarget: 2, ScaleFactor: 0.1, Difficulty: "rare", ActionType: "perfect_win", RewardGTK: 100},
-		{Type: "combat", Name: "Class Supremacy", Description: "Win %d battles using only one Class type", BaseTarget: 3, ScaleFactor: 0.2, Difficulty: "uncommon", ActionType: "class_win", RewardGTK: 50},
-		{Type: "combat", Name: "Battle Hardened", Description: "Participate in %d battles", BaseTarget: 10, ScaleFactor: 0.5, Difficulty: "common", ActionType: "battle_participation", RewardGTK: 25},
-		{Type: "collection", Name: "Genetic Mastery", Description: "Hatch %d Eggs", BaseTarget: 1, ScaleFactor: 0.1, Difficulty: "uncommon", ActionType: "egg_hatched", RewardGTK: 50},
-		{Type: "collection", Name: "Life Bringer", Description: "Start incubation for %d eggs", BaseTarget: 3, ScaleFactor: 0.2, Difficulty: "common", ActionType: "incubation_started", RewardGTK: 25},
-		{Type: "progression", Name: "Titan's Strength", Description: "Level up characters %d times", BaseTarget: 10, ScaleFactor: 1.0, Difficulty: "uncommon", ActionType: "level_up_count", RewardGTK: 50},
-		{Type: "progression", Name: "Resource Hoarder", Description: "Accumulate %d GTK from battles", BaseTarget: 2000, ScaleFactor: 200, Difficulty: "uncommon", ActionType: "gtk_earned", RewardGTK: 50},
+	counts, err := seed.Apply(context.Background(), DB, bundle, false)
+	if err != nil {
+		return err
 	}
-
-	for _, t := range templates {
-		var existing models.QuestTemplate
-		if err := db.Where("name = ?", t.Name).First(&existing).Error; err != nil {
-			if err := db.Create(&t).Error; err != nil {
-				return err
-			}
-		}
+	for _, c := range counts {
+		log.Printf("Content %s: %d created, %d updated, %d kept", c.Section, c.Created, c.Updated, c.Kept)
 	}
 	return nil
 }
@@ -0,0 +1,67 @@
+package db
+
+import (
+	"errors"
+
+	"github.com/lorengraff/crypto-tower-defense/pkg/tracing"
+	"gorm.io/gorm"
+)
+
+const spanInstanceKey = "tracing:span"
+
+// registerTracing wraps every GORM operation in a span. Queries issued through
+// DB.WithContext(ctx) inherit the request ID and parent span carried by ctx.
+func registerTracing(g *gorm.DB) error {
+	cb := g.Callback()
+	// The callback processor type is unexported, so each operation is registered explicitly
+	regs := []func() error{
+		func() error {
+			return cb.Create().Before("gorm:create").Register("tracing:before_create", startSpan("db.create"))
+		},
+		func() error { return cb.Create().After("gorm:create").Register("tracing:after_create", endSpan) },
+		func() error {
+			return cb.Query().Before("gorm:query").Register("tracing:before_query", startSpan("db.query"))
+		},
+		func() error { return cb.Query().After("gorm:query").Register("tracing:after_query", endSpan) },
+		func() error {
+			return cb.Update().Before("gorm:update").Register("tracing:before_update", startSpan("db.update"))
+		},
+		func() error { return cb.Update().After("gorm:update").Register("tracing:after_update", endSpan) },
+		func() error {
+			return cb.Delete().Before("gorm:delete").Register("tracing:before_delete", startSpan("db.delete"))
+		},
+		func() error { return cb.Delete().After("gorm:delete").Register("tracing:after_delete", endSpan) },
+		func() error { return cb.Row().Before("gorm:row").Register("tracing:before_row", startSpan("db.row")) },
+		func() error { return cb.Row().After("gorm:row").Register("tracing:after_row", endSpan) },
+		func() error { return cb.Raw().Before("gorm:raw").Register("tracing:before_raw", startSpan("db.raw")) },
+		func() error { return cb.Raw().After("gorm:raw").Register("tracing:after_raw", endSpan) },
+	}
+	for _, register := range regs {
+		if err := register(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func startSpan(name string) func(*gorm.DB) {
+	return func(tx *gorm.DB) {
+		_, span := tracing.Start(tx.Statement.Context, name, "table", tx.Statement.Table)
+		tx.InstanceSet(spanInstanceKey, span)
+	}
+}
+
+func endSpan(tx *gorm.DB) {
+	v, ok := tx.InstanceGet(spanInstanceKey)
+	if !ok {
+		return
+	}
+	span := v.(*tracing.Span)
+	span.SetAttributes("rows", tx.Statement.RowsAffected)
+
+	err := tx.Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = nil // Expected outcome of lookups, not a failure
+	}
+	span.End(err)
+}
@@ -0,0 +1,127 @@
+package economy
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lorengraff/crypto-tower-defense/content"
+	"github.com/lorengraff/crypto-tower-defense/internal/seed"
+)
+
+func run(t *testing.T, sc Scenario, settings map[string]string) *Report {
+	t.Helper()
+	b, err := seed.Load(content.FS, "prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := b.SettingValues()
+	for k, v := range settings {
+		values[k] = v
+	}
+	s, err := New(b, values, sc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s.Run()
+}
+
+func smallScenario() Scenario {
+	sc := DefaultScenario()
+	sc.Days = 30
+	sc.StartPlayers = 200
+	sc.NewPlayers = 10
+	return sc
+}
+
+func TestRunIsReproducible(t *testing.T) {
+	sc := smallScenario()
+	a := run(t, sc, nil)
+	if b := run(t, sc, nil); !reflect.DeepEqual(a, b) {
+		t.Fatal("two runs with the same seed differ")
+	}
+	sc.Seed = 2
+	if c := run(t, sc, nil); reflect.DeepEqual(a.Days, c.Days) {
+		t.Fatal("a different seed produced the same days")
+	}
+}
+
+func TestTokensAreConserved(t *testing.T) {
+	r := run(t, smallScenario(), nil)
+	var minted, burned, bought, towerBurned, towerFaucets int64
+	for _, d := range r.Days {
+		var faucets, sinks int64
+		for _, v := range d.GTKFaucets {
+			faucets += v
+		}
+		for _, v := range d.GTKSinks {
+			sinks += v
+		}
+		if faucets != d.GTKMinted {
+			t.Fatalf("day %d: faucets %d != minted %d", d.Day, faucets, d.GTKMinted)
+		}
+		minted += faucets
+		burned += d.GTKBurned
+		if supply := minted - burned - d.GTKTreasury; supply != d.GTKSupply {
+			t.Fatalf("day %d: GTK supply %d, want %d", d.Day, d.GTKSupply, supply)
+		}
+		if d.Revenue.Total != float64(burned+d.GTKTreasury) {
+			t.Fatalf("day %d: revenue %.0f, want %d", d.Day, d.Revenue.Total, burned+d.GTKTreasury)
+		}
+
+		bought += d.TowerPurchased
+		towerBurned += d.TowerBurned
+		for _, v := range d.TowerFaucets {
+			towerFaucets += v
+		}
+		if supply := towerFaucets - towerBurned - d.TowerTreasury; supply != d.TowerSupply {
+			t.Fatalf("day %d: TOWER supply %d, want %d", d.Day, d.TowerSupply, supply)
+		}
+	}
+	if r.Summary.GTKMinted == 0 || r.Summary.TowerBought != bought || bought == 0 {
+		t.Fatalf("summary %+v does not match the days", r.Summary)
+	}
+}
+
+func TestSettingsDriveTheSinks(t *testing.T) {
+	sc := smallScenario()
+	base := run(t, sc, nil)
+	capped := run(t, sc, map[string]string{"gacha_daily_mint_limit": "0", "breeding_cost": "50"})
+
+	if base.Summary.TowerSinks[SinkGacha] == 0 {
+		t.Fatal("no gacha mints with the default limit")
+	}
+	if got := capped.Summary.TowerSinks[SinkGacha]; got != 0 {
+		t.Fatalf("gacha burned %d TOWER with gacha_daily_mint_limit 0", got)
+	}
+	if capped.Summary.TowerSinks[SinkBreeding]%50 != 0 || capped.Summary.TowerSinks[SinkBreeding] == 0 {
+		t.Fatalf("breeding spent %d TOWER, want a multiple of breeding_cost 50", capped.Summary.TowerSinks[SinkBreeding])
+	}
+}
+
+func TestLoadScenario(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.yaml")
+	os.WriteFile(good, []byte("days: 7\ncohorts:\n  - name: bots\n    share: 1\n    active_rate: 1\n"), 0o644)
+	sc, err := LoadScenario(good)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sc.Days != 7 || len(sc.Cohorts) != 1 || sc.StartPlayers != DefaultScenario().StartPlayers {
+		t.Fatalf("scenario = %+v", sc)
+	}
+
+	bad := filepath.Join(dir, "bad.yaml")
+	os.WriteFile(bad, []byte("days: 7\nplayers: 10\n"), 0o644)
+	if _, err := LoadScenario(bad); err == nil || !strings.Contains(err.Error(), "players") {
+		t.Fatalf("unknown field: err = %v", err)
+	}
+
+	empty := filepath.Join(dir, "empty.yaml")
+	os.WriteFile(empty, []byte("cohorts: []\n"), 0o644)
+	if _, err := LoadScenario(empty); err == nil {
+		t.Fatal("a scenario without cohorts loaded")
+	}
+}
@@ -0,0 +1,279 @@
+package economy
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/services"
+)
+
+// Faucet and sink columns, in report order
+var (
+	GTKFaucets   = []string{SourceMissions, SourceQuests, SourceRaids, SourceReferrals}
+	GTKSinks     = []string{SinkShop, SinkWagerFees, SinkMarketFees}
+	TowerFaucets = []string{SourceMissions, SourceQuests, SourcePurchases}
+	TowerSinks   = []string{SinkGacha, SinkBreeding}
+)
+
+// Buckets is the cumulative GTK revenue split by services.SplitRevenue
+type Buckets struct {
+	Total          float64 `json:"total"`
+	GrowthFund     float64 `json:"growth_fund"`
+	SecurityFund   float64 `json:"security_fund"`
+	Operations     float64 `json:"operations"`
+	RewardsPool    float64 `json:"rewards_pool"`
+	DevTeam        float64 `json:"dev_team"`
+	TowerLiquidity float64 `json:"tower_liquidity"`
+}
+
+func (b *Buckets) add(amount float64) {
+	d := services.SplitRevenue("economy_sim", amount)
+	b.Total += d.TotalAmount
+	b.GrowthFund += d.GrowthFund
+	b.SecurityFund += d.SecurityFund
+	b.Operations += d.Operations
+	b.RewardsPool += d.RewardsPool
+	b.DevTeam += d.DevTeam
+	b.TowerLiquidity += d.TowerLiquidity
+}
+
+// DayStats is the state of the economy at the end of a day. Faucet and sink
+// amounts are for that day; supply, treasury and revenue are running totals.
+type DayStats struct {
+	Day     int `json:"day"`
+	Players int `json:"players"` // Not churned
+	Active  int `json:"active"`
+	New     int `json:"new"`
+	Churned int `json:"churned"`
+
+	GTKSupply    int64            `json:"gtk_supply"` // Held by players
+	GTKMinted    int64            `json:"gtk_minted"` // Paid out by faucets today
+	GTKBurned    int64            `json:"gtk_burned"` // Spent in the shop today
+	GTKTreasury  int64            `json:"gtk_treasury"`
+	GTKInflation float64          `json:"gtk_inflation_percent"` // Supply change against the day before
+	GTKFaucets   map[string]int64 `json:"gtk_faucets"`
+	GTKSinks     map[string]int64 `json:"gtk_sinks"`
+
+	TowerSupply    int64            `json:"tower_supply"` // Held by players
+	TowerPurchased int64            `json:"tower_purchased"`
+	TowerBurned    int64            `json:"tower_burned"` // Spent on gacha today
+	TowerTreasury  int64            `json:"tower_treasury"`
+	TowerFaucets   map[string]int64 `json:"tower_faucets"`
+	TowerSinks     map[string]int64 `json:"tower_sinks"`
+
+	Revenue Buckets `json:"revenue"`
+}
+
+func newDay(d int, prev *DayStats) *DayStats {
+	return &DayStats{
+		Day:           d,
+		GTKSupply:     prev.GTKSupply,
+		GTKTreasury:   prev.GTKTreasury,
+		TowerSupply:   prev.TowerSupply,
+		TowerTreasury: prev.TowerTreasury,
+		GTKFaucets:    map[string]int64{},
+		GTKSinks:      map[string]int64{},
+		TowerFaucets:  map[string]int64{},
+		TowerSinks:    map[string]int64{},
+		Revenue:       prev.Revenue,
+	}
+}
+
+func (d *DayStats) earnGTK(p *player, source string, amount int64) {
+	if amount <= 0 {
+		return
+	}
+	p.gtk += amount
+	d.GTKSupply += amount
+	d.GTKMinted += amount
+	d.GTKFaucets[source] += amount
+}
+
+func (d *DayStats) earnTower(p *player, source string, amount int64) {
+	if amount <= 0 {
+		return
+	}
+	p.tower += amount
+	d.TowerSupply += amount
+	d.TowerFaucets[source] += amount
+	if source == SourcePurchases {
+		d.TowerPurchased += amount
+	}
+}
+
+// spendGTK takes amount from a player into the treasury or, if toTreasury is
+// false, out of circulation. Both count as revenue.
+func (d *DayStats) spendGTK(p *player, sink string, amount int64, toTreasury bool) {
+	if amount <= 0 {
+		return
+	}
+	p.gtk -= amount
+	d.GTKSupply -= amount
+	d.GTKSinks[sink] += amount
+	if toTreasury {
+		d.GTKTreasury += amount
+	} else {
+		d.GTKBurned += amount
+	}
+	d.Revenue.add(float64(amount))
+}
+
+func (d *DayStats) spendTower(p *player, sink string, amount int64, toTreasury bool) {
+	p.tower -= amount
+	d.TowerSupply -= amount
+	d.TowerSinks[sink] += amount
+	if toTreasury {
+		d.TowerTreasury += amount
+	} else {
+		d.TowerBurned += amount
+	}
+}
+
+// close works out the day's inflation
+func (d *DayStats) close(prev *DayStats) {
+	if prev.GTKSupply > 0 {
+		d.GTKInflation = float64(d.GTKSupply-prev.GTKSupply) / float64(prev.GTKSupply) * 100
+	}
+}
+
+// Summary totals a run
+type Summary struct {
+	Players       int              `json:"players"` // Still playing on the last day
+	Joined        int              `json:"joined"`
+	Churned       int              `json:"churned"`
+	GTKMinted     int64            `json:"gtk_minted"`
+	GTKBurned     int64            `json:"gtk_burned"`
+	GTKSupply     int64            `json:"gtk_supply"`
+	GTKTreasury   int64            `json:"gtk_treasury"`
+	GTKFaucets    map[string]int64 `json:"gtk_faucets"`
+	GTKSinks      map[string]int64 `json:"gtk_sinks"`
+	SinkRatio     float64          `json:"sink_ratio"` // GTK leaving circulation per GTK paid out
+	TowerBought   int64            `json:"tower_purchased"`
+	TowerBurned   int64            `json:"tower_burned"`
+	TowerSupply   int64            `json:"tower_supply"`
+	TowerTreasury int64            `json:"tower_treasury"`
+	TowerFaucets  map[string]int64 `json:"tower_faucets"`
+	TowerSinks    map[string]int64 `json:"tower_sinks"`
+	Revenue       Buckets          `json:"revenue"`
+}
+
+// Report is the result of a run
+type Report struct {
+	Scenario Scenario   `json:"scenario"`
+	Days     []DayStats `json:"days"`
+	Summary  Summary    `json:"summary"`
+}
+
+func (r *Report) summarize() {
+	s := Summary{
+		GTKFaucets:   map[string]int64{},
+		GTKSinks:     map[string]int64{},
+		TowerFaucets: map[string]int64{},
+		TowerSinks:   map[string]int64{},
+	}
+	for _, d := range r.Days {
+		s.Joined += d.New
+		s.Churned += d.Churned
+		s.GTKMinted += d.GTKMinted
+		s.GTKBurned += d.GTKBurned
+		s.TowerBought += d.TowerPurchased
+		s.TowerBurned += d.TowerBurned
+		for k, v := range d.GTKFaucets {
+			s.GTKFaucets[k] += v
+		}
+		for k, v := range d.GTKSinks {
+			s.GTKSinks[k] += v
+		}
+		for k, v := range d.TowerFaucets {
+			s.TowerFaucets[k] += v
+		}
+		for k, v := range d.TowerSinks {
+			s.TowerSinks[k] += v
+		}
+	}
+	if n := len(r.Days); n > 0 {
+		last := r.Days[n-1]
+		s.Players = last.Players
+		s.GTKSupply = last.GTKSupply
+		s.GTKTreasury = last.GTKTreasury
+		s.TowerSupply = last.TowerSupply
+		s.TowerTreasury = last.TowerTreasury
+		s.Revenue = last.Revenue
+	}
+	if s.GTKMinted > 0 {
+		s.SinkRatio = float64(s.GTKBurned+s.GTKTreasury) / float64(s.GTKMinted)
+	}
+	r.Summary = s
+}
+
+// WriteJSON writes the whole report as indented JSON
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteCSV writes days.csv, one row per day, into dir
+func (r *Report) WriteCSV(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(filepath.Join(dir, "days.csv"))
+	if err != nil {
+		return err
+	}
+	w := csv.NewWriter(f)
+
+	header := []string{"day", "players", "active", "new", "churned",
+		"gtk_supply", "gtk_minted", "gtk_burned", "gtk_treasury", "gtk_inflation_percent"}
+	header = appendNames(header, "gtk_faucet_", GTKFaucets)
+	header = appendNames(header, "gtk_sink_", GTKSinks)
+	header = append(header, "tower_supply", "tower_purchased", "tower_burned", "tower_treasury")
+	header = appendNames(header, "tower_faucet_", TowerFaucets)
+	header = appendNames(header, "tower_sink_", TowerSinks)
+	header = append(header, "revenue_total", "growth_fund", "security_fund", "operations",
+		"rewards_pool", "dev_team", "tower_liquidity")
+	w.Write(header)
+
+	for _, d := range r.Days {
+		row := []string{itoa(int64(d.Day)), itoa(int64(d.Players)), itoa(int64(d.Active)), itoa(int64(d.New)), itoa(int64(d.Churned)),
+			itoa(d.GTKSupply), itoa(d.GTKMinted), itoa(d.GTKBurned), itoa(d.GTKTreasury), ftoa(d.GTKInflation)}
+		row = appendValues(row, d.GTKFaucets, GTKFaucets)
+		row = appendValues(row, d.GTKSinks, GTKSinks)
+		row = append(row, itoa(d.TowerSupply), itoa(d.TowerPurchased), itoa(d.TowerBurned), itoa(d.TowerTreasury))
+		row = appendValues(row, d.TowerFaucets, TowerFaucets)
+		row = appendValues(row, d.TowerSinks, TowerSinks)
+		b := d.Revenue
+		row = append(row, ftoa(b.Total), ftoa(b.GrowthFund), ftoa(b.SecurityFund), ftoa(b.Operations),
+			ftoa(b.RewardsPool), ftoa(b.DevTeam), ftoa(b.TowerLiquidity))
+		w.Write(row)
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func appendNames(row []string, prefix string, names []string) []string {
+	for _, n := range names {
+		row = append(row, prefix+n)
+	}
+	return row
+}
+
+func appendValues(row []string, values map[string]int64, names []string) []string {
+	for _, n := range names {
+		row = append(row, itoa(values[n]))
+	}
+	return row
+}
+
+func itoa(v int64) string { return strconv.FormatInt(v, 10) }
+
+func ftoa(v float64) string { return strconv.FormatFloat(v, 'f', 2, 64) }
@@ -0,0 +1,125 @@
+// Package economy is an agent-based simulation of the GTK and TOWER economy:
+// player cohorts play every day, earning from the faucets (missions, quests,
+// raids, referrals) and paying into the sinks (shop, gacha, breeding,
+// marketplace and wager fees), so supply, treasury and revenue can be
+// projected before a tunable changes.
+package economy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Cohort is a group of players that behave alike. Rates are per active day;
+// a fractional rate is rounded up with probability equal to its fraction.
+type Cohort struct {
+	Name            string  `yaml:"name" json:"name"`
+	Share           float64 `yaml:"share" json:"share"`                       // Fraction of players in the cohort
+	ActiveRate      float64 `yaml:"active_rate" json:"active_rate"`           // Chance to play on a given day
+	ChurnRate       float64 `yaml:"churn_rate" json:"churn_rate"`             // Chance to quit for good each day
+	LevelsPerDay    float64 `yaml:"levels_per_day" json:"levels_per_day"`     // Account levels gained per active day
+	QuestCompletion float64 `yaml:"quest_completion" json:"quest_completion"` // Share of the daily quests finished
+	Raids           float64 `yaml:"raids" json:"raids"`                       // Raid missions cleared
+	RaidGrade       string  `yaml:"raid_grade" json:"raid_grade"`             // Typical performance grade, S-D
+	RankedGames     float64 `yaml:"ranked_games" json:"ranked_games"`
+	Wagers          float64 `yaml:"wagers" json:"wagers"` // Wager battles entered
+	ShopItems       float64 `yaml:"shop_items" json:"shop_items"`
+	Mints           float64 `yaml:"mints" json:"mints"` // Gacha mints, capped by gacha_daily_mint_limit
+	TowerPerMint    int64   `yaml:"tower_per_mint" json:"tower_per_mint"`
+	Breeds          float64 `yaml:"breeds" json:"breeds"`             // Chance to breed (24h cooldown)
+	MarketSales     float64 `yaml:"market_sales" json:"market_sales"` // Marketplace sales made
+	MarketPrice     int64   `yaml:"market_price" json:"market_price"` // Average GTK per sale
+	StartTower      int64   `yaml:"start_tower" json:"start_tower"`
+	TowerBought     int64   `yaml:"tower_bought" json:"tower_bought"` // TOWER bought outside the game per active day
+}
+
+// Scenario is the population and its behaviour over a run
+type Scenario struct {
+	Days         int      `yaml:"days" json:"days"`
+	Seed         int64    `yaml:"seed" json:"seed"`
+	StartPlayers int      `yaml:"start_players" json:"start_players"`
+	NewPlayers   int      `yaml:"new_players" json:"new_players"`     // Joining on day 1
+	Growth       float64  `yaml:"growth" json:"growth"`               // Daily growth of NewPlayers, 0.01 = 1%
+	ReferralRate float64  `yaml:"referral_rate" json:"referral_rate"` // Share of new players referred by an existing one
+	Cohorts      []Cohort `yaml:"cohorts" json:"cohorts"`
+}
+
+// DefaultScenario is a small, growing player base with casual, core and whale
+// cohorts. The behaviour numbers are assumptions: replace them with analytics
+// through a scenario file when they are available.
+func DefaultScenario() Scenario {
+	return Scenario{
+		Days:         90,
+		Seed:         1,
+		StartPlayers: 1000,
+		NewPlayers:   50,
+		Growth:       0.01,
+		ReferralRate: 0.2,
+		Cohorts: []Cohort{
+			{
+				Name: "casual", Share: 0.6, ActiveRate: 0.4, ChurnRate: 0.02,
+				LevelsPerDay: 0.5, QuestCompletion: 0.4, Raids: 1, RaidGrade: "C", RankedGames: 1,
+				Wagers: 0.1, ShopItems: 0.3, Mints: 0.05, TowerPerMint: 100,
+				MarketSales: 0.05, MarketPrice: 300,
+			},
+			{
+				Name: "core", Share: 0.3, ActiveRate: 0.8, ChurnRate: 0.005,
+				LevelsPerDay: 1.5, QuestCompletion: 0.8, Raids: 3, RaidGrade: "B", RankedGames: 3,
+				Wagers: 1, ShopItems: 1, Mints: 0.3, TowerPerMint: 200, Breeds: 0.2,
+				MarketSales: 0.3, MarketPrice: 500, StartTower: 500, TowerBought: 20,
+			},
+			{
+				Name: "whale", Share: 0.1, ActiveRate: 0.9, ChurnRate: 0.003,
+				LevelsPerDay: 2, QuestCompletion: 0.9, Raids: 4, RaidGrade: "A", RankedGames: 4,
+				Wagers: 2, ShopItems: 2, Mints: 2, TowerPerMint: 1000, Breeds: 0.8,
+				MarketSales: 1, MarketPrice: 2000, StartTower: 5000, TowerBought: 500,
+			},
+		},
+	}
+}
+
+// LoadScenario reads a YAML scenario; fields it leaves out keep their defaults
+func LoadScenario(path string) (Scenario, error) {
+	sc := DefaultScenario()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return sc, err
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&sc); err != nil {
+		return sc, fmt.Errorf("%s: %w", path, err)
+	}
+	return sc, sc.Validate()
+}
+
+// Validate checks the scenario can be run
+func (sc Scenario) Validate() error {
+	if sc.Days < 1 {
+		return errors.New("days must be at least 1")
+	}
+	if sc.StartPlayers < 0 || sc.NewPlayers < 0 {
+		return errors.New("player counts cannot be negative")
+	}
+	if len(sc.Cohorts) == 0 {
+		return errors.New("at least one cohort is required")
+	}
+	total := 0.0
+	for _, c := range sc.Cohorts {
+		if c.Name == "" || c.Share <= 0 {
+			return fmt.Errorf("cohort %q needs a name and a positive share", c.Name)
+		}
+		if c.ActiveRate < 0 || c.ActiveRate > 1 || c.ChurnRate < 0 || c.ChurnRate > 1 {
+			return fmt.Errorf("cohort %s: active_rate and churn_rate must be between 0 and 1", c.Name)
+		}
+		total += c.Share
+	}
+	if total <= 0 {
+		return errors.New("cohort shares must add up to more than 0")
+	}
+	return nil
+}
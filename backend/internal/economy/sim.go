@@ -0,0 +1,281 @@
+package economy
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/seed"
+	"github.com/lorengraff/crypto-tower-defense/internal/services"
+)
+
+// Settings is the slice of ConfigService the simulation reads. Both
+// *services.ConfigService (live values) and seed.SettingValues (the content
+// files) satisfy it.
+type Settings interface {
+	GetValue(key, defaultValue string) string
+	GetInt(key string, defaultVal int) int
+	GetFloat(key string, defaultVal float64) float64
+}
+
+// Faucet and sink names used in DayStats
+const (
+	SourceMissions  = "missions"
+	SourceQuests    = "quests"
+	SourceRaids     = "raids"
+	SourceReferrals = "referrals"
+	SourcePurchases = "purchases" // TOWER bought outside the game
+
+	SinkShop       = "shop"
+	SinkWagerFees  = "wager_fees"
+	SinkMarketFees = "market_fees"
+	SinkGacha      = "gacha"
+	SinkBreeding   = "breeding"
+)
+
+// questsPerDay matches DailyQuestService.GenerateDailyQuests
+const questsPerDay = 5
+
+// wagerStake is the base stake of BattleService.dynamicStakes
+const wagerStake = int64(100)
+
+// breedingMinLevel stands in for BreedingService's parent level requirement,
+// using the account level as a proxy for the best character's level
+const breedingMinLevel = 10
+
+type player struct {
+	cohort      int
+	level       int
+	gtk         int64
+	tower       int64
+	referrer    int // Index into Sim.players, -1 if not referred
+	rankedGames int
+	paid        []bool // Referral milestones already paid to the referrer
+	gone        bool
+}
+
+// Sim runs a Scenario against one set of settings and content
+type Sim struct {
+	sc         Scenario
+	settings   Settings
+	missions   map[int]seed.Rewards // Story mission rewards by level
+	quests     []seed.QuestTemplate
+	shop       []seed.ShopItem
+	maxRaid    int // Highest raid mission sequence
+	milestones []services.ReferralMilestone
+
+	rng     *rand.Rand
+	players []*player
+	shares  []float64 // Cumulative cohort shares
+}
+
+// New prepares a simulation. Story missions, quest templates, shop items and
+// islands come from the content bundle; tunables come from settings.
+func New(b *seed.Bundle, settings Settings, sc Scenario) (*Sim, error) {
+	if err := sc.Validate(); err != nil {
+		return nil, err
+	}
+	s := &Sim{
+		sc:         sc,
+		settings:   settings,
+		missions:   map[int]seed.Rewards{},
+		quests:     b.QuestTemplates,
+		milestones: services.ParseReferralMilestones(settings.GetValue("referral_milestones", "")),
+		rng:        rand.New(rand.NewSource(sc.Seed)),
+	}
+	for _, m := range b.Missions {
+		if !m.Inactive {
+			s.missions[m.Level] = m.Rewards
+		}
+	}
+	for _, item := range b.ShopItems {
+		if !item.Unavailable && item.GTKCost > 0 {
+			s.shop = append(s.shop, item)
+		}
+	}
+	for _, island := range b.Islands {
+		for _, m := range island.Missions {
+			s.maxRaid = max(s.maxRaid, m.Sequence)
+		}
+	}
+	if s.maxRaid == 0 {
+		return nil, errors.New("content has no raid missions")
+	}
+
+	total := 0.0
+	for _, c := range sc.Cohorts {
+		total += c.Share
+		s.shares = append(s.shares, total)
+	}
+	for i := range s.shares {
+		s.shares[i] /= total
+	}
+	return s, nil
+}
+
+// Run simulates every day of the scenario
+func (s *Sim) Run() *Report {
+	r := &Report{Scenario: s.sc}
+	day := newDay(0, &DayStats{})
+	for i := 0; i < s.sc.StartPlayers; i++ {
+		s.join(day, -1)
+	}
+
+	for d := 1; d <= s.sc.Days; d++ {
+		prev := day
+		day = newDay(d, prev)
+		if d == 1 {
+			// The starting players join on day 1 with their starting TOWER
+			day.New = prev.New
+			day.TowerPurchased = prev.TowerPurchased
+			day.TowerFaucets[SourcePurchases] = prev.TowerPurchased
+		}
+		s.grow(day)
+		s.play(day)
+		s.payReferrals(day)
+		day.close(prev)
+		r.Days = append(r.Days, *day)
+	}
+	r.summarize()
+	return r
+}
+
+// grow adds the day's new players, some of them referred by existing ones
+func (s *Sim) grow(day *DayStats) {
+	joining := s.count(float64(s.sc.NewPlayers) * math.Pow(1+s.sc.Growth, float64(day.Day-1)))
+	existing := len(s.players)
+	for i := 0; i < joining; i++ {
+		referrer := -1
+		if existing > 0 && s.rng.Float64() < s.sc.ReferralRate {
+			referrer = s.rng.Intn(existing)
+		}
+		s.join(day, referrer)
+	}
+}
+
+func (s *Sim) join(day *DayStats, referrer int) {
+	cohort := 0
+	roll := s.rng.Float64()
+	for cohort < len(s.shares)-1 && roll >= s.shares[cohort] {
+		cohort++
+	}
+	p := &player{
+		cohort:   cohort,
+		level:    1,
+		referrer: referrer,
+		paid:     make([]bool, len(s.milestones)),
+	}
+	s.players = append(s.players, p)
+	day.New++
+	day.earnTower(p, SourcePurchases, s.sc.Cohorts[cohort].StartTower)
+}
+
+// play runs one day for every remaining player in join order
+func (s *Sim) play(day *DayStats) {
+	mintLimit := s.settings.GetInt("gacha_daily_mint_limit", 10)
+	breedingCost := int64(s.settings.GetInt("breeding_cost", 500))
+	wagerFee := int64(float64(wagerStake) * float64(s.settings.GetInt("wager_tax_percent", 5)) / 100)
+	marketFee := int64(s.settings.GetInt("marketplace_fee_percent", 3))
+
+	for _, p := range s.players {
+		if p.gone {
+			continue
+		}
+		c := &s.sc.Cohorts[p.cohort]
+		if s.rng.Float64() < c.ChurnRate {
+			p.gone = true
+			day.Churned++
+			continue
+		}
+		day.Players++
+		if s.rng.Float64() >= c.ActiveRate {
+			continue
+		}
+		day.Active++
+
+		// Faucets
+		for n := s.count(c.LevelsPerDay); n > 0; n-- {
+			p.level++
+			if rewards, ok := s.missions[p.level]; ok {
+				day.earnGTK(p, SourceMissions, rewards.GTK)
+				day.earnTower(p, SourceMissions, rewards.Tower)
+			}
+		}
+		for _, i := range s.rng.Perm(len(s.quests))[:min(questsPerDay, len(s.quests))] {
+			if s.rng.Float64() < c.QuestCompletion {
+				day.earnGTK(p, SourceQuests, int64(s.quests[i].RewardGTK))
+				day.earnTower(p, SourceQuests, int64(s.quests[i].RewardTOWER))
+			}
+		}
+		sequence := min(s.maxRaid, 1+p.level/10)
+		for n := s.count(c.Raids); n > 0; n-- {
+			tokens, _ := services.RaidReward(sequence, c.RaidGrade)
+			day.earnGTK(p, SourceRaids, int64(tokens))
+		}
+		p.rankedGames += s.count(c.RankedGames)
+		day.earnTower(p, SourcePurchases, c.TowerBought)
+
+		// GTK sinks. Wager pots and marketplace prices move between players;
+		// only the fees leave circulation, so each player carries half of the
+		// wager fee and the seller the marketplace fee.
+		for n := s.count(c.Wagers); n > 0 && p.gtk >= services.WagerMinBalance; n-- {
+			day.spendGTK(p, SinkWagerFees, wagerFee/2, true)
+		}
+		for n := s.count(c.MarketSales); n > 0; n-- {
+			if fee := c.MarketPrice * marketFee / 100; p.gtk >= fee {
+				day.spendGTK(p, SinkMarketFees, fee, true)
+			}
+		}
+		for n := s.count(c.ShopItems); n > 0 && len(s.shop) > 0; n-- {
+			if item := s.shop[s.rng.Intn(len(s.shop))]; p.gtk >= item.GTKCost {
+				day.spendGTK(p, SinkShop, item.GTKCost, false)
+			}
+		}
+
+		// TOWER sinks
+		for n := min(s.count(c.Mints), mintLimit); n > 0 && c.TowerPerMint > 0 && p.tower >= c.TowerPerMint; n-- {
+			day.spendTower(p, SinkGacha, c.TowerPerMint, false)
+		}
+		if p.level >= breedingMinLevel && p.tower >= breedingCost && s.rng.Float64() < c.Breeds {
+			day.spendTower(p, SinkBreeding, breedingCost, true)
+		}
+	}
+}
+
+// payReferrals pays referrers for the milestones their referred players
+// reached, up to referral_daily_reward_cap per referrer and day
+func (s *Sim) payReferrals(day *DayStats) {
+	dailyCap := int64(s.settings.GetInt("referral_daily_reward_cap", 2500))
+	paidToday := map[int]int64{}
+	for _, p := range s.players {
+		if p.referrer < 0 || s.players[p.referrer].gone {
+			continue
+		}
+		for i, m := range s.milestones {
+			if p.paid[i] {
+				continue
+			}
+			reached := (m.Type == "level" && p.level >= m.Threshold) ||
+				(m.Type == "ranked_games" && p.rankedGames >= m.Threshold)
+			if !reached || (dailyCap > 0 && paidToday[p.referrer]+m.Reward > dailyCap) {
+				continue
+			}
+			p.paid[i] = true
+			paidToday[p.referrer] += m.Reward
+			day.earnGTK(s.players[p.referrer], SourceReferrals, m.Reward)
+		}
+	}
+}
+
+// count turns a rate into a whole number: the integer part always happens,
+// the fraction with that probability
+func (s *Sim) count(rate float64) int {
+	if rate <= 0 {
+		return 0
+	}
+	n := int(rate)
+	if s.rng.Float64() < rate-float64(n) {
+		n++
+	}
+	return n
+}
@@ -1,9 +1,10 @@
 package handlers
 
 import (
+	"log"
 	"net/http"
+	"strconv"
 
-	"github.com/ethereum/go-ethereum/common"
 	"github.com/gin-gonic/gin"
 	"github.com/lorengraff/crypto-tower-defense/internal/db"
 	"github.com/lorengraff/crypto-tower-defense/internal/models"
@@ -13,13 +14,15 @@ import (
 
 // AuthHandler handles authentication HTTP requests
 type AuthHandler struct {
-	authService *services.AuthService
+	authService     *services.AuthService
+	referralService *services.ReferralService
 }
 
 // NewAuthHandler creates a new auth handler
-func NewAuthHandler(cfg *config.Config) *AuthHandler {
+func NewAuthHandler(cfg *config.Config, referrals *services.ReferralService) *AuthHandler {
 	return &AuthHandler{
-		authService: services.NewAuthService(cfg),
+		authService:     services.NewAuthService(cfg),
+		referralService: referrals,
 	}
 }
 
@@ -46,18 +49,22 @@ func (h *AuthHandler) GetNonce(c *gin.Context) {
 		return
 	}
 
+	msg := h.authService.BuildSIWEMessage(user)
 	c.JSON(http.StatusOK, gin.H{
-		"nonce":  user.Nonce,
-		"message": "Sign this message to authenticate: " + user.Nonce,
+		"nonce":      user.Nonce,
+		"message":    msg.String(), // EIP-4361; sign this exact text
+		"expires_at": msg.ExpirationTime,
 	})
 }
 
-// VerifySignature verifies wallet signature and returns JWT
+// VerifySignature verifies a signed SIWE message and opens a session
 // POST /api/v1/auth/verify
 func (h *AuthHandler) VerifySignature(c *gin.Context) {
 	var req struct {
 		WalletAddress string `json:"wallet_address" binding:"required"`
 		Signature     string `json:"signature" binding:"required"`
+		Message       string `json:"message"`       // Signed SIWE message; defaults to the one issued by /auth/nonce
+		ReferralCode  string `json:"referral_code"` // Only honoured on the first login
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -67,33 +74,21 @@ func (h *AuthHandler) VerifySignature(c *gin.Context) {
 		return
 	}
 
-	// CRITICAL: Normalize wallet address (same as GetOrCreateUser does)
-	normalizedAddress := common.HexToAddress(req.WalletAddress).Hex()
-
-	// Get user WITHOUT regenerating nonce (just fetch from DB)
-	var user models.User
-	result := db.DB.Where("wallet_address = ?", normalizedAddress).First(&user)
-	if result.Error != nil {
+	user, err := h.authService.VerifySIWE(req.WalletAddress, req.Message, req.Signature)
+	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "User not found or nonce expired",
+			"error": err.Error(),
 		})
 		return
 	}
 
-	// Build message that was signed (must match the nonce from GET /nonce)
-	message := "Sign this message to authenticate: " + user.Nonce
-
-	// Verify signature
-	valid, err := h.authService.VerifySignature(req.WalletAddress, req.Signature, message)
-	if err != nil || !valid {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "Invalid signature",
-		})
+	if user.IsBanned || user.Status == "BANNED" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Account suspended. Contact support."})
 		return
 	}
 
-	// Generate JWT
-	token, err := h.authService.GenerateJWT(&user)
+	// Open a session (short-lived access token + rotating refresh token)
+	tokens, err := h.authService.CreateSession(user, c.Request.UserAgent(), c.ClientIP())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to generate token",
@@ -101,11 +96,22 @@ func (h *AuthHandler) VerifySignature(c *gin.Context) {
 		return
 	}
 
-	// Update last login
-	_ = h.authService.UpdateLastLogin(user.ID)
+	// Update last login (records the IP before referral checks use it)
+	firstLogin := user.LastLoginAt == nil
+	_ = h.authService.UpdateLastLogin(user.ID, c.ClientIP())
+
+	// Referral attribution happens once, on the first successful verify
+	if firstLogin && req.ReferralCode != "" {
+		if _, err := h.referralService.AttributeReferral(user.ID, req.ReferralCode); err != nil {
+			log.Printf("Referral attribution failed for user %d: %v", user.ID, err)
+		}
+	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"token": token,
+		"token":         tokens.AccessToken, // Kept for older clients
+		"access_token":  tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
 		"user": gin.H{
 			"id":             user.ID,
 			"wallet_address": user.WalletAddress,
@@ -117,6 +123,96 @@ func (h *AuthHandler) VerifySignature(c *gin.Context) {
 	})
 }
 
+// RefreshToken exchanges a refresh token for a new token pair (the old refresh token stops working)
+// POST /api/v1/auth/refresh
+func (h *AuthHandler) RefreshToken(c *gin.Context) {
+	var req struct {
+		RefreshToken string `json:"refresh_token" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	tokens, err := h.authService.RefreshSession(req.RefreshToken, c.Request.UserAgent(), c.ClientIP())
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"token":         tokens.AccessToken,
+		"access_token":  tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
+	})
+}
+
+// Logout revokes the current session
+// POST /api/v1/auth/logout
+func (h *AuthHandler) Logout(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	sessionID := c.GetUint("session_id")
+
+	if err := h.authService.RevokeSession(userID, sessionID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// LogoutAll revokes every session of the user, including the current one
+// POST /api/v1/auth/logout-all
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	if err := services.RevokeUserSessions(userID, services.SessionRevokedLogoutAll); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// ListSessions returns the user's active sessions (devices)
+// GET /api/v1/auth/sessions
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	current := c.GetUint("session_id")
+
+	sessions, err := h.authService.ListSessions(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	list := make([]gin.H, 0, len(sessions))
+	for _, s := range sessions {
+		list = append(list, gin.H{
+			"id":           s.ID,
+			"created_at":   s.CreatedAt,
+			"last_used_at": s.LastUsedAt,
+			"expires_at":   s.ExpiresAt,
+			"user_agent":   s.UserAgent,
+			"ip_address":   s.IPAddress,
+			"current":      s.ID == current,
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"sessions": list})
+}
+
+// RevokeSession logs out one of the user's sessions
+// DELETE /api/v1/auth/sessions/:id
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	sessionID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	if err := h.authService.RevokeSession(userID, uint(sessionID)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
 // GetProfile returns authenticated user's profile
 // GET /api/v1/auth/profile
 func (h *AuthHandler) GetProfile(c *gin.Context) {
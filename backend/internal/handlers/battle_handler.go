@@ -14,9 +14,9 @@ type BattleHandler struct {
 }
 
 // NewBattleHandler creates a new battle handler
-func NewBattleHandler() *BattleHandler {
+func NewBattleHandler(battleService *services.BattleService) *BattleHandler {
 	return &BattleHandler{
-		battleService: services.NewBattleService(),
+		battleService: battleService,
 	}
 }
 
@@ -127,7 +127,7 @@ func (h *BattleHandler) CompleteBattle(c *gin.Context) {
 		return
 	}
 
-	if err := h.battleService.CompleteBattle(uint(battleID), req.WinnerID, req.ReplayData); err != nil {
+	if err := h.battleService.CompleteBattle(c.Request.Context(), uint(battleID), req.WinnerID, req.ReplayData); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -273,7 +273,7 @@ func (h *BattleHandler) ProcessTurn(c *gin.Context) {
 		actionData["skill_id"] = float64(*req.SkillID)
 	}
 
-	result, err := h.battleService.ProcessTurn(uint(battleID), userID.(uint), actionData)
+	result, err := h.battleService.ProcessTurn(c.Request.Context(), uint(battleID), userID.(uint), actionData)
 
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
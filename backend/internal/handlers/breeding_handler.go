@@ -91,3 +91,38 @@ func (h *BreedingHandler) HatchEgg(c *gin.Context) {
 		"character": character,
 	})
 }
+
+// PreviewBreeding shows the cost and offspring odds of breeding two characters
+func (h *BreedingHandler) PreviewBreeding(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req struct {
+		Parent1ID uint `form:"parent1_id" binding:"required"`
+		Parent2ID uint `form:"parent2_id" binding:"required"`
+	}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	preview, err := h.breedingService.PreviewBreeding(userID, req.Parent1ID, req.Parent2ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
+}
+
+// GetLineage returns a character's family tree
+func (h *BreedingHandler) GetLineage(c *gin.Context) {
+	characterID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	lineage, err := h.breedingService.GetLineage(uint(characterID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, lineage)
+}
@@ -143,44 +143,3 @@ func (h *CharacterHandler) HatchEgg(c *gin.Context) {
 		"character": character,
 	})
 }
-
-// RecoverFatigue recovers character fatigue
-// POST /api/v1/characters/:id/recover
-func (h *CharacterHandler) RecoverFatigue(c *gin.Context) {
-	userID, exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
-		return
-	}
-
-	characterID, err := strconv.ParseUint(c.Param("id"), 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid character ID"})
-		return
-	}
-
-	var req struct {
-		Amount int `json:"amount" binding:"required,min=1,max=100"`
-	}
-
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
-		return
-	}
-
-	// Verify ownership first
-	_, err = h.characterService.GetCharacterByID(uint(characterID), userID.(uint))
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Character not found"})
-		return
-	}
-
-	if err := h.characterService.RecoverFatigue(uint(characterID), req.Amount); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to recover fatigue"})
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Fatigue recovered successfully",
-	})
-}
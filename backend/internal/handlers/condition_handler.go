@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lorengraff/crypto-tower-defense/internal/services"
+)
+
+type ConditionHandler struct {
+	conditionService *services.ConditionService
+}
+
+func NewConditionHandler(conditionService *services.ConditionService) *ConditionHandler {
+	return &ConditionHandler{
+		conditionService: conditionService,
+	}
+}
+
+// GetCondition returns a character's durability, fatigue and what repairs or revives cost
+func (h *ConditionHandler) GetCondition(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	characterID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	condition, err := h.conditionService.Condition(userID, uint(characterID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"condition": condition})
+}
+
+// Repair restores a character's durability for GTK
+func (h *ConditionHandler) Repair(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	characterID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	character, err := h.conditionService.Repair(userID, uint(characterID))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"character": character,
+	})
+}
+
+// Revive brings a dead character back for GTK
+func (h *ConditionHandler) Revive(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	characterID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	character, err := h.conditionService.Revive(userID, uint(characterID))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"character": character,
+	})
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lorengraff/crypto-tower-defense/internal/services"
+)
+
+type CraftingHandler struct {
+	craftingService *services.CraftingService
+}
+
+func NewCraftingHandler(craftingService *services.CraftingService) *CraftingHandler {
+	return &CraftingHandler{
+		craftingService: craftingService,
+	}
+}
+
+// ListRecipes returns the active crafting recipes
+func (h *CraftingHandler) ListRecipes(c *gin.Context) {
+	recipes, err := h.craftingService.ListRecipes()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"recipes": recipes})
+}
+
+// ListJobs returns the player's crafting queue and recent crafts
+func (h *CraftingHandler) ListJobs(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	jobs, err := h.craftingService.ListJobs(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"jobs": jobs})
+}
+
+// StartCraft queues a craft, taking the recipe's materials and GTK
+func (h *CraftingHandler) StartCraft(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	recipeID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	job, err := h.craftingService.StartCraft(userID, uint(recipeID))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"job":     job,
+	})
+}
+
+// ClaimCraft rolls a finished craft and hands over the item
+func (h *CraftingHandler) ClaimCraft(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	jobID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	job, item, err := h.craftingService.ClaimCraft(c.Request.Context(), userID, uint(jobID))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": item != nil,
+		"job":     job,
+		"item":    item,
+	})
+}
+
+// Salvage breaks a piece of equipment into crafting materials
+func (h *CraftingHandler) Salvage(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	itemID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	materials, err := h.craftingService.Salvage(userID, uint(itemID))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"materials": materials,
+	})
+}
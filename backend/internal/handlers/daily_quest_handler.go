@@ -14,9 +14,9 @@ type DailyQuestHandler struct {
 	questService *services.DailyQuestService
 }
 
-func NewDailyQuestHandler() *DailyQuestHandler {
+func NewDailyQuestHandler(quests *services.DailyQuestService) *DailyQuestHandler {
 	return &DailyQuestHandler{
-		questService: services.NewDailyQuestService(),
+		questService: quests,
 	}
 }
 
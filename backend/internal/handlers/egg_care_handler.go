@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lorengraff/crypto-tower-defense/internal/services"
+)
+
+type EggCareHandler struct {
+	eggCareService *services.EggCareService
+}
+
+func NewEggCareHandler(eggCareService *services.EggCareService) *EggCareHandler {
+	return &EggCareHandler{
+		eggCareService: eggCareService,
+	}
+}
+
+// GetCare returns an egg's care windows and what was done in each
+func (h *EggCareHandler) GetCare(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	eggID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	windows, err := h.eggCareService.GetCare(userID, uint(eggID))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"windows": windows})
+}
+
+// PerformCare does a care action on the player's or a friend's egg
+func (h *EggCareHandler) PerformCare(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	eggID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	var req struct {
+		Action string `json:"action" binding:"required"`
+		ItemID uint   `json:"item_id"` // Egg care consumable; pays GTK when omitted
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	care, err := h.eggCareService.PerformCare(userID, uint(eggID), req.Action, req.ItemID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"care":    care,
+	})
+}
@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lorengraff/crypto-tower-defense/internal/services"
+)
+
+// FriendHandler exposes friends, presence, notifications and direct challenge endpoints
+type FriendHandler struct {
+	friendService *services.FriendService
+	notifications *services.NotificationService
+}
+
+func NewFriendHandler(friendService *services.FriendService) *FriendHandler {
+	return &FriendHandler{
+		friendService: friendService,
+		notifications: &services.NotificationService{},
+	}
+}
+
+// ListFriends returns friends with presence, or ?status=pending|blocked
+// GET /api/v1/friends
+func (h *FriendHandler) ListFriends(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	friends, err := h.friendService.ListFriends(userID, c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"friends": friends})
+}
+
+// SearchUsers finds players by wallet address or display name
+// GET /api/v1/friends/search?q=
+func (h *FriendHandler) SearchUsers(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	users, err := h.friendService.SearchUsers(userID, c.Query("q"), limit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"users": users})
+}
+
+// SendRequest sends a friend request
+// POST /api/v1/friends/requests
+func (h *FriendHandler) SendRequest(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req struct {
+		UserID uint `json:"user_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	friendship, err := h.friendService.SendFriendRequest(userID, req.UserID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "friendship": friendship})
+}
+
+// AcceptRequest accepts an incoming friend request
+// POST /api/v1/friends/requests/:id/accept
+func (h *FriendHandler) AcceptRequest(c *gin.Context) {
+	h.respondRequest(c, true)
+}
+
+// DeclineRequest declines an incoming friend request
+// POST /api/v1/friends/requests/:id/decline
+func (h *FriendHandler) DeclineRequest(c *gin.Context) {
+	h.respondRequest(c, false)
+}
+
+func (h *FriendHandler) respondRequest(c *gin.Context, accept bool) {
+	userID := c.GetUint("user_id")
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	if err := h.friendService.RespondFriendRequest(userID, uint(id), accept); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// RemoveFriend removes a friend or cancels an outgoing request
+// DELETE /api/v1/friends/:userId
+func (h *FriendHandler) RemoveFriend(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	otherID, _ := strconv.ParseUint(c.Param("userId"), 10, 32)
+
+	if err := h.friendService.RemoveFriend(userID, uint(otherID)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// BlockUser blocks a player
+// POST /api/v1/friends/:userId/block
+func (h *FriendHandler) BlockUser(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	targetID, _ := strconv.ParseUint(c.Param("userId"), 10, 32)
+
+	if err := h.friendService.BlockUser(userID, uint(targetID)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// UnblockUser lifts a block
+// DELETE /api/v1/friends/:userId/block
+func (h *FriendHandler) UnblockUser(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	targetID, _ := strconv.ParseUint(c.Param("userId"), 10, 32)
+
+	if err := h.friendService.UnblockUser(userID, uint(targetID)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// SetDisplayName sets the caller's searchable display name
+// PUT /api/v1/user/display-name
+func (h *FriendHandler) SetDisplayName(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req struct {
+		DisplayName string `json:"display_name" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.friendService.SetDisplayName(userID, req.DisplayName); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// ListChallenges returns incoming and outgoing challenges, optionally filtered by ?status=
+// GET /api/v1/challenges
+func (h *FriendHandler) ListChallenges(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	challenges, err := h.friendService.ListChallenges(userID, c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"challenges": challenges})
+}
+
+// SendChallenge challenges a friend to a PvP battle with an optional stake
+// POST /api/v1/challenges
+func (h *FriendHandler) SendChallenge(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req struct {
+		OpponentID uint   `json:"opponent_id" binding:"required"`
+		Stake      int64  `json:"stake"`
+		Message    string `json:"message"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	challenge, err := h.friendService.SendChallenge(userID, req.OpponentID, req.Stake, req.Message)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "challenge": challenge})
+}
+
+// AcceptChallenge accepts a challenge and starts the battle
+// POST /api/v1/challenges/:id/accept
+func (h *FriendHandler) AcceptChallenge(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	battle, err := h.friendService.AcceptChallenge(userID, uint(id))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "battle_id": battle.ID, "battle": battle})
+}
+
+// DeclineChallenge declines a challenge
+// POST /api/v1/challenges/:id/decline
+func (h *FriendHandler) DeclineChallenge(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	if err := h.friendService.DeclineChallenge(userID, uint(id)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// CancelChallenge withdraws a challenge the caller sent
+// POST /api/v1/challenges/:id/cancel
+func (h *FriendHandler) CancelChallenge(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	if err := h.friendService.CancelChallenge(userID, uint(id)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// GetNotifications returns the caller's unread notifications (friend requests, challenges, ...)
+// GET /api/v1/notifications
+func (h *FriendHandler) GetNotifications(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	notifications, err := h.notifications.GetUnreadNotifications(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"notifications": notifications})
+}
+
+// MarkNotificationRead marks a notification as read
+// POST /api/v1/notifications/:id/read
+func (h *FriendHandler) MarkNotificationRead(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	if err := h.notifications.MarkAsRead(userID, uint(id)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
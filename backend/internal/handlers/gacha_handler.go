@@ -19,11 +19,11 @@ type GachaHandler struct {
 }
 
 // NewGachaHandler creates a new gacha handler
-func NewGachaHandler(blockchainService *services.BlockchainService, db interface{}) *GachaHandler {
+func NewGachaHandler(blockchainService *services.BlockchainService, gacha *services.GachaService, quests *services.DailyQuestService) *GachaHandler {
 	return &GachaHandler{
-		gachaService:      services.NewGachaService(blockchainService),
+		gachaService:      gacha,
 		blockchainService: blockchainService,
-		questService:      services.NewDailyQuestService(),
+		questService:      quests,
 	}
 }
 
@@ -101,6 +101,33 @@ func (h *GachaHandler) MintEgg(c *gin.Context) {
 	})
 }
 
+// RedeemEggTicket spends an Egg Ticket on a free egg mint
+// POST /api/v1/gacha/redeem-ticket
+func (h *GachaHandler) RedeemEggTicket(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req struct {
+		ItemID uint `json:"item_id" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	egg, err := h.gachaService.RedeemEggTicket(userID, req.ItemID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Egg ticket redeemed",
+		"egg":     egg,
+	})
+}
+
 // GetOddsPreview returns probability preview for a given TOWER amount
 // GET /api/v1/gacha/odds/:amount
 func (h *GachaHandler) GetOddsPreview(c *gin.Context) {
@@ -0,0 +1,370 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lorengraff/crypto-tower-defense/internal/services"
+)
+
+// GuildHandler exposes guild membership, chat, treasury and guild raid endpoints
+type GuildHandler struct {
+	guildService *services.GuildService
+}
+
+func NewGuildHandler(guildService *services.GuildService) *GuildHandler {
+	return &GuildHandler{
+		guildService: guildService,
+	}
+}
+
+// CreateGuild creates a guild led by the caller
+// POST /api/v1/guilds
+func (h *GuildHandler) CreateGuild(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req struct {
+		Name        string `json:"name" binding:"required"`
+		Tag         string `json:"tag" binding:"required"`
+		Description string `json:"description"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	guild, err := h.guildService.CreateGuild(userID, req.Name, req.Tag, req.Description)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "guild": guild})
+}
+
+// GetMyGuild returns the caller's guild and treasury balance
+// GET /api/v1/guilds/me
+func (h *GuildHandler) GetMyGuild(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	guild, treasury, err := h.guildService.GetMyGuild(userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"guild": guild, "treasury_balance": treasury})
+}
+
+// GetGuild returns a guild by ID
+// GET /api/v1/guilds/:id
+func (h *GuildHandler) GetGuild(c *gin.Context) {
+	guildID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	guild, treasury, err := h.guildService.GetGuild(uint(guildID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"guild": guild, "treasury_balance": treasury})
+}
+
+// GetLeaderboard ranks guilds by ?category=elo|wins|raid_damage|treasury
+// GET /api/v1/guilds/leaderboard
+func (h *GuildHandler) GetLeaderboard(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	entries, err := h.guildService.GetGuildLeaderboard(c.Query("category"), limit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"leaderboard": entries})
+}
+
+// InviteMember invites a player to the caller's guild
+// POST /api/v1/guilds/invites
+func (h *GuildHandler) InviteMember(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req struct {
+		UserID uint `json:"user_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	invite, err := h.guildService.InviteMember(userID, req.UserID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "invite": invite})
+}
+
+// GetInvites lists pending invites for the caller
+// GET /api/v1/guilds/invites
+func (h *GuildHandler) GetInvites(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	invites, err := h.guildService.GetPendingInvites(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"invites": invites})
+}
+
+// RespondInvite accepts or declines an invite
+// POST /api/v1/guilds/invites/:id/respond
+func (h *GuildHandler) RespondInvite(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	inviteID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	var req struct {
+		Accept bool `json:"accept"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.guildService.RespondInvite(uint(inviteID), userID, req.Accept); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// LeaveGuild leaves (or disbands, if last member) the caller's guild
+// POST /api/v1/guilds/leave
+func (h *GuildHandler) LeaveGuild(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	if err := h.guildService.LeaveGuild(userID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// KickMember removes a member from the guild
+// DELETE /api/v1/guilds/members/:userId
+func (h *GuildHandler) KickMember(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	targetID, _ := strconv.ParseUint(c.Param("userId"), 10, 32)
+
+	if err := h.guildService.KickMember(userID, uint(targetID)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// SetMemberRole changes a member's role (leader only)
+// PUT /api/v1/guilds/members/:userId/role
+func (h *GuildHandler) SetMemberRole(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	targetID, _ := strconv.ParseUint(c.Param("userId"), 10, 32)
+
+	var req struct {
+		Role string `json:"role" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.guildService.SetMemberRole(userID, uint(targetID), req.Role); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// GetChat returns the guild chat log (?before=<id>&limit=)
+// GET /api/v1/guilds/chat
+func (h *GuildHandler) GetChat(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	before, _ := strconv.ParseUint(c.DefaultQuery("before", "0"), 10, 32)
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+
+	messages, err := h.guildService.GetMessages(userID, uint(before), limit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"messages": messages})
+}
+
+// PostChat posts a message to the guild chat
+// POST /api/v1/guilds/chat
+func (h *GuildHandler) PostChat(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req struct {
+		Content string `json:"content" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	msg, err := h.guildService.PostMessage(userID, req.Content)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": msg})
+}
+
+// Deposit moves GTK from the caller's wallet into the guild treasury
+// POST /api/v1/guilds/treasury/deposit
+func (h *GuildHandler) Deposit(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req struct {
+		Amount int64 `json:"amount" binding:"required,min=1"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.guildService.DepositToTreasury(userID, req.Amount); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// RequestWithdrawal opens a treasury withdrawal request
+// POST /api/v1/guilds/treasury/withdrawals
+func (h *GuildHandler) RequestWithdrawal(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req struct {
+		RecipientID uint   `json:"recipient_id" binding:"required"`
+		Amount      int64  `json:"amount" binding:"required,min=1"`
+		Reason      string `json:"reason"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	withdrawal, err := h.guildService.RequestWithdrawal(userID, req.RecipientID, req.Amount, req.Reason)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "withdrawal": withdrawal})
+}
+
+// ListWithdrawals lists treasury withdrawal requests (?status=PENDING)
+// GET /api/v1/guilds/treasury/withdrawals
+func (h *GuildHandler) ListWithdrawals(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	requests, err := h.guildService.ListWithdrawals(userID, c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"withdrawals": requests})
+}
+
+// ApproveWithdrawal adds the caller's approval to a pending request
+// POST /api/v1/guilds/treasury/withdrawals/:id/approve
+func (h *GuildHandler) ApproveWithdrawal(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	requestID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	withdrawal, err := h.guildService.ApproveWithdrawal(uint(requestID), userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "withdrawal": withdrawal})
+}
+
+// RejectWithdrawal rejects a pending request
+// POST /api/v1/guilds/treasury/withdrawals/:id/reject
+func (h *GuildHandler) RejectWithdrawal(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	requestID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	if err := h.guildService.RejectWithdrawal(uint(requestID), userID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// UpdateTreasuryPolicy sets the withdrawal approval policy (leader only)
+// PUT /api/v1/guilds/treasury/policy
+func (h *GuildHandler) UpdateTreasuryPolicy(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req struct {
+		Approvals int   `json:"approvals" binding:"required"`
+		AutoLimit int64 `json:"auto_limit"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.guildService.UpdateTreasuryPolicy(userID, req.Approvals, req.AutoLimit); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// StartRaid starts a guild raid against a boss
+// POST /api/v1/guilds/raids
+func (h *GuildHandler) StartRaid(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req struct {
+		BossID uint `json:"boss_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	raid, err := h.guildService.StartGuildRaid(userID, req.BossID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "raid": raid})
+}
+
+// GetRaid returns the guild's latest raid with contributions
+// GET /api/v1/guilds/raids/current
+func (h *GuildHandler) GetRaid(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	raid, err := h.guildService.GetActiveGuildRaid(userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"raid": raid})
+}
+
+// AttackRaid hits the guild boss with the caller's active team
+// POST /api/v1/guilds/raids/attack
+func (h *GuildHandler) AttackRaid(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	result, err := h.guildService.AttackGuildRaid(userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -10,13 +10,15 @@ import (
 
 // ItemHandler handles item HTTP requests
 type ItemHandler struct {
-	itemService *services.ItemService
+	itemService      *services.ItemService
+	equipmentService *services.EquipmentService
 }
 
 // NewItemHandler creates a new item handler
-func NewItemHandler() *ItemHandler {
+func NewItemHandler(equipmentService *services.EquipmentService) *ItemHandler {
 	return &ItemHandler{
-		itemService: services.NewItemService(),
+		itemService:      services.NewItemService(),
+		equipmentService: equipmentService,
 	}
 }
 
@@ -132,7 +134,7 @@ func (h *ItemHandler) EquipItem(c *gin.Context) {
 		return
 	}
 
-	if err := h.itemService.EquipItem(uint(itemID), req.CharacterID, userID.(uint)); err != nil {
+	if err := h.equipmentService.Equip(userID.(uint), req.CharacterID, uint(itemID)); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -157,7 +159,7 @@ func (h *ItemHandler) UnequipItem(c *gin.Context) {
 		return
 	}
 
-	if err := h.itemService.UnequipItem(uint(itemID), userID.(uint)); err != nil {
+	if err := h.equipmentService.Unequip(userID.(uint), uint(itemID)); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -200,3 +202,86 @@ func (h *ItemHandler) UseItem(c *gin.Context) {
 		"message": "Item used successfully",
 	})
 }
+
+// EnhanceItem tries to raise a piece of gear by +1
+// POST /api/v1/items/:id/enhance
+func (h *ItemHandler) EnhanceItem(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	itemID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid item ID"})
+		return
+	}
+
+	result, err := h.equipmentService.Enhance(userID, uint(itemID))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// SocketRune sets a rune into a piece of gear
+// POST /api/v1/items/:id/socket
+func (h *ItemHandler) SocketRune(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	itemID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid item ID"})
+		return
+	}
+
+	var req struct {
+		RuneID uint `json:"rune_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	gear, err := h.equipmentService.SocketRune(userID, uint(itemID), req.RuneID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"equipment": gear})
+}
+
+// UnsocketRune takes a rune (the :id item) out of its gear
+// POST /api/v1/items/:id/unsocket
+func (h *ItemHandler) UnsocketRune(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	runeID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid item ID"})
+		return
+	}
+
+	if err := h.equipmentService.UnsocketRune(userID, uint(runeID)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Rune unsocketed"})
+}
+
+// GetLoadout returns a character's equipment and effective stats
+// GET /api/v1/characters/:id/equipment
+func (h *ItemHandler) GetLoadout(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	characterID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid character ID"})
+		return
+	}
+
+	loadout, err := h.equipmentService.GetLoadout(userID, uint(characterID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, loadout)
+}
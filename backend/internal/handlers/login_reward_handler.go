@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/internal/services"
+)
+
+type LoginRewardHandler struct {
+	loginRewardService *services.LoginRewardService
+}
+
+func NewLoginRewardHandler(loginRewardService *services.LoginRewardService) *LoginRewardHandler {
+	return &LoginRewardHandler{
+		loginRewardService: loginRewardService,
+	}
+}
+
+// GetCalendar returns the login calendar with the player's streak
+// GET /api/v1/login-rewards
+func (h *LoginRewardHandler) GetCalendar(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	calendar, err := h.loginRewardService.Calendar(userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"calendar": calendar})
+}
+
+// Claim pays today's login reward
+// POST /api/v1/login-rewards/claim
+func (h *LoginRewardHandler) Claim(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	claim, err := h.loginRewardService.Claim(userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"claim":   claim,
+	})
+}
+
+// SetTimezone changes the timezone login days are counted in
+// PUT /api/v1/login-rewards/timezone
+func (h *LoginRewardHandler) SetTimezone(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req struct {
+		Timezone string `json:"timezone" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.loginRewardService.SetTimezone(userID, req.Timezone)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"timezone": user.Timezone,
+	})
+}
+
+// GetAdminCalendar returns every configured calendar day
+// GET /api/v1/admin-login-calendar
+func (h *LoginRewardHandler) GetAdminCalendar(c *gin.Context) {
+	days, err := h.loginRewardService.AdminCalendar()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"days": days})
+}
+
+// SetCalendarDay sets what one calendar day pays
+// PUT /api/v1/admin-login-calendar
+func (h *LoginRewardHandler) SetCalendarDay(c *gin.Context) {
+	adminID := c.GetUint("user_id")
+
+	var day models.LoginCalendarDay
+	if err := c.ShouldBindJSON(&day); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	saved, err := h.loginRewardService.SetCalendarDay(&day, adminID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"day":     saved,
+	})
+}
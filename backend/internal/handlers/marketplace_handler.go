@@ -12,9 +12,9 @@ type MarketplaceHandler struct {
 	marketplaceService *services.MarketplaceService
 }
 
-func NewMarketplaceHandler() *MarketplaceHandler {
+func NewMarketplaceHandler(marketplaceService *services.MarketplaceService) *MarketplaceHandler {
 	return &MarketplaceHandler{
-		marketplaceService: &services.MarketplaceService{},
+		marketplaceService: marketplaceService,
 	}
 }
 
@@ -53,10 +53,11 @@ func (h *MarketplaceHandler) CreateListing(c *gin.Context) {
 // GetListings returns active marketplace listings
 func (h *MarketplaceHandler) GetListings(c *gin.Context) {
 	itemType := c.Query("type")
+	listingType := c.Query("listing_type")
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
 	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
 
-	listings, err := h.marketplaceService.GetActiveListings(itemType, limit, offset)
+	listings, err := h.marketplaceService.GetActiveListings(itemType, listingType, limit, offset)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -94,3 +95,161 @@ func (h *MarketplaceHandler) CancelListing(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"success": true})
 }
+
+// CreateAuction lists a character or item as an English auction
+// POST /api/v1/marketplace/auctions
+func (h *MarketplaceHandler) CreateAuction(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req struct {
+		services.AssetRef
+		StartingBid   int64 `json:"starting_bid" binding:"required"`
+		ReservePrice  int64 `json:"reserve_price"`
+		DurationHours int   `json:"duration_hours" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	listing, err := h.marketplaceService.CreateAuction(userID, req.AssetRef, req.StartingBid, req.ReservePrice, req.DurationHours)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "listing": listing})
+}
+
+// PlaceBid bids on an auction; the amount is held in escrow until outbid or settled
+// POST /api/v1/marketplace/:id/bid
+func (h *MarketplaceHandler) PlaceBid(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	listingID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	var req struct {
+		Amount int64 `json:"amount" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	listing, err := h.marketplaceService.PlaceBid(userID, uint(listingID), req.Amount)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "current_bid": listing.CurrentBid, "ends_at": listing.ExpiresAt})
+}
+
+// GetBids returns the bid history of an auction
+// GET /api/v1/marketplace/:id/bids
+func (h *MarketplaceHandler) GetBids(c *gin.Context) {
+	listingID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	bids, err := h.marketplaceService.GetBids(uint(listingID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"bids": bids})
+}
+
+// CreateBundle lists several characters/items for a single price
+// POST /api/v1/marketplace/bundles
+func (h *MarketplaceHandler) CreateBundle(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req struct {
+		Assets []services.AssetRef `json:"assets" binding:"required,dive"`
+		Price  int64               `json:"price" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	listing, err := h.marketplaceService.CreateBundleListing(userID, req.Assets, req.Price)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "listing": listing})
+}
+
+// ListOffers returns offers received (default) or ?direction=sent
+// GET /api/v1/marketplace/offers
+func (h *MarketplaceHandler) ListOffers(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	offers, err := h.marketplaceService.ListOffers(userID, c.Query("direction"), c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"offers": offers})
+}
+
+// MakeOffer offers GTK for another player's character or item
+// POST /api/v1/marketplace/offers
+func (h *MarketplaceHandler) MakeOffer(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req struct {
+		services.AssetRef
+		Amount       int64  `json:"amount" binding:"required"`
+		Message      string `json:"message"`
+		ExpiresHours int    `json:"expires_hours"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	offer, err := h.marketplaceService.MakeOffer(userID, req.AssetRef, req.Amount, req.Message, req.ExpiresHours)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "offer": offer})
+}
+
+// AcceptOffer sells the asset to the offering player
+// POST /api/v1/marketplace/offers/:id/accept
+func (h *MarketplaceHandler) AcceptOffer(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	offerID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	offer, err := h.marketplaceService.AcceptOffer(userID, uint(offerID))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "offer": offer})
+}
+
+// RejectOffer declines an offer and refunds the buyer
+// POST /api/v1/marketplace/offers/:id/reject
+func (h *MarketplaceHandler) RejectOffer(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	offerID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	if err := h.marketplaceService.RejectOffer(userID, uint(offerID)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// CancelOffer withdraws the caller's offer and refunds it
+// POST /api/v1/marketplace/offers/:id/cancel
+func (h *MarketplaceHandler) CancelOffer(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	offerID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	if err := h.marketplaceService.CancelOffer(userID, uint(offerID)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
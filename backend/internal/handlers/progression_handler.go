@@ -123,11 +123,16 @@ func (h *ProgressionHandler) ValidateIntegrity(c *gin.Context) {
 		return
 	}
 
-	err = h.progressionService.ValidateCharacterIntegrity(uint(characterID))
+	report, err := h.progressionService.ValidateCharacterIntegrity(uint(characterID), false)
 	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !report.Valid() {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"valid": false,
-			"error": err.Error(),
+			"valid":  false,
+			"error":  report.Problems[0],
+			"report": report,
 		})
 		return
 	}
@@ -135,5 +140,24 @@ func (h *ProgressionHandler) ValidateIntegrity(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"valid":   true,
 		"message": "Character integrity validated",
+		"report":  report,
 	})
 }
+
+// RepairCharacter recomputes a character's level and stats and saves them (admin)
+// POST /api/v1/characters/:id/repair
+func (h *ProgressionHandler) RepairCharacter(c *gin.Context) {
+	characterID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid character ID"})
+		return
+	}
+
+	report, err := h.progressionService.ValidateCharacterIntegrity(uint(characterID), true)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Character not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
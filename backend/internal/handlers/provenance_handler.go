@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lorengraff/crypto-tower-defense/internal/services"
+)
+
+type ProvenanceHandler struct {
+	provenanceService *services.ProvenanceService
+}
+
+func NewProvenanceHandler(provenanceService *services.ProvenanceService) *ProvenanceHandler {
+	return &ProvenanceHandler{
+		provenanceService: provenanceService,
+	}
+}
+
+// GetTimeline returns a character's history, oldest first
+func (h *ProvenanceHandler) GetTimeline(c *gin.Context) {
+	characterID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	events, err := h.provenanceService.Timeline(uint(characterID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"events": events, "count": len(events)})
+}
+
+// GetMetadata serves a character's NFT metadata (the token URI document)
+func (h *ProvenanceHandler) GetMetadata(c *gin.Context) {
+	characterID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	metadata, err := h.provenanceService.Metadata(uint(characterID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, metadata)
+}
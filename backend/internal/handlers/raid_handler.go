@@ -32,7 +32,7 @@ type StartRaidRequest struct {
 
 // StartRaid initiates a new raid battle
 // POST /api/v1/raids/start
-// Security: Validates user ownership, team validity, character durability
+// Security: Validates user ownership, team validity, character condition
 func (h *RaidHandler) StartRaid(c *gin.Context) {
 	// 1. SECURITY: Get authenticated user
 	userID, exists := c.Get("userID")
@@ -72,22 +72,24 @@ func (h *RaidHandler) StartRaid(c *gin.Context) {
 		return
 	}
 
-	// 4. SECURITY: Check character durability (anti-exploit)
+	// 4. SECURITY: Check every active character can fight (wear is applied when the raid ends)
 	for _, member := range team.Members {
-		if !member.IsBackup && member.Character.Durability < 10 {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error":        "Character has too low durability",
+		if member.Character.Controller() != userID.(uint) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":        "Character is rented out",
 				"character_id": member.Character.ID,
-				"durability":   member.Character.Durability,
-				"message":      "Characters need at least 10 durability to battle. Use revival items or rest.",
 			})
 			return
 		}
-		if member.Character.IsDead {
+		if member.IsBackup {
+			continue
+		}
+		if err := services.CheckBattleReady(&member.Character, services.GetConfigService()); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{
-				"error":        "Cannot use dead character",
+				"error":        err.Error(),
 				"character_id": member.Character.ID,
-				"message":      "Revive character first using Sacred Ash or Revival Herb",
+				"durability":   member.Character.Durability,
+				"message":      "Repair, revive or rest the character before it battles again",
 			})
 			return
 		}
@@ -125,21 +127,7 @@ func (h *RaidHandler) StartRaid(c *gin.Context) {
 		return
 	}
 
-	// 8. Deduct durability immediately (anti-cheat)
-	tx := db.DB.Begin()
-	for _, member := range team.Members {
-		if !member.IsBackup {
-			member.Character.Durability -= 5
-			if err := tx.Save(&member.Character).Error; err != nil {
-				tx.Rollback()
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update durability"})
-				return
-			}
-		}
-	}
-	tx.Commit()
-
-	// 9. Return success
+	// 8. Return success
 	c.JSON(http.StatusOK, gin.H{
 		"message":        "Raid started successfully",
 		"session_id":     raidSession.ID,
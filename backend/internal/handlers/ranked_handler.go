@@ -3,6 +3,7 @@ package handlers
 import (
 	"crypto/rand"
 	"encoding/hex"
+	"encoding/json"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -53,27 +54,29 @@ func (h *RankedHandler) StartRanked(c *gin.Context) {
 		return
 	}
 
-	// 2. SECURITY: Validate team (3v3, durability, alive)
+	// 2. SECURITY: Validate team (3v3, battle-ready)
 	activeCount := 0
 	teamCP := 0
+	var fighters []models.BattleParticipant
 	for _, member := range team.Members {
+		if member.Character.Controller() != userID.(uint) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":        "Character is rented out",
+				"character_id": member.Character.ID,
+			})
+			return
+		}
 		if !member.IsBackup {
 			activeCount++
-			if member.Character.Durability < 10 {
-				c.JSON(http.StatusBadRequest, gin.H{
-					"error":        "Character durability too low",
-					"character_id": member.Character.ID,
-				})
-				return
-			}
-			if member.Character.IsDead {
+			if err := services.CheckBattleReady(&member.Character, services.GetConfigService()); err != nil {
 				c.JSON(http.StatusBadRequest, gin.H{
-					"error":        "Cannot use dead character",
+					"error":        err.Error(),
 					"character_id": member.Character.ID,
 				})
 				return
 			}
 			teamCP += member.Character.CombatPower
+			fighters = append(fighters, models.BattleParticipant{CharacterID: member.Character.ID, CharacterName: member.Character.Name})
 		}
 	}
 
@@ -124,14 +127,16 @@ func (h *RankedHandler) StartRanked(c *gin.Context) {
 		return
 	}
 
-	// 7. Create battle
+	// 7. Create battle (the chosen team is recorded so it takes the battle's wear)
 	battleSeed := generateBattleSeed()
+	p1State, _ := json.Marshal(fighters)
 	battle := &models.Battle{
-		Player1ID:  userID.(uint),
-		Player2ID:  opponent.ID,
-		Status:     "active",
-		BattleType: "ranked",
-		Seed:       battleSeed,
+		Player1ID:     userID.(uint),
+		Player2ID:     opponent.ID,
+		Status:        "active",
+		BattleType:    "ranked",
+		Seed:          battleSeed,
+		PlayerStateP1: string(p1State),
 		// Store ELO for calculation at end
 	}
 
@@ -140,20 +145,6 @@ func (h *RankedHandler) StartRanked(c *gin.Context) {
 		return
 	}
 
-	// 8. Deduct durability (atomic)
-	tx := db.DB.Begin()
-	for _, member := range team.Members {
-		if !member.IsBackup {
-			member.Character.Durability -= 3 // Ranked costs less than Raid
-			if err := tx.Save(&member.Character).Error; err != nil {
-				tx.Rollback()
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update durability"})
-				return
-			}
-		}
-	}
-	tx.Commit()
-
 	c.JSON(http.StatusOK, gin.H{
 		"message":   "Ranked battle started",
 		"battle_id": battle.ID,
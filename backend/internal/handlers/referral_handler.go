@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lorengraff/crypto-tower-defense/internal/services"
+)
+
+// ReferralHandler exposes the referral dashboard and reward claims
+type ReferralHandler struct {
+	referralService *services.ReferralService
+}
+
+func NewReferralHandler(referrals *services.ReferralService) *ReferralHandler {
+	return &ReferralHandler{
+		referralService: referrals,
+	}
+}
+
+// GetDashboard returns the caller's referral code, referred players and pending rewards
+// GET /api/v1/referrals
+func (h *ReferralHandler) GetDashboard(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	dashboard, err := h.referralService.GetDashboard(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, dashboard)
+}
+
+// GetCode returns (and lazily creates) the caller's referral code
+// GET /api/v1/referrals/code
+func (h *ReferralHandler) GetCode(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	code, err := h.referralService.GetOrCreateReferralCode(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": code})
+}
+
+// ClaimRewards pays out all reached milestones to the caller's wallet
+// POST /api/v1/referrals/claim
+func (h *ReferralHandler) ClaimRewards(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	amount, err := h.referralService.ClaimRewards(userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "amount": amount})
+}
+
+// ListFlagged returns referrals held by the anti-abuse checks
+// GET /api/v1/admin-referrals/flagged
+func (h *ReferralHandler) ListFlagged(c *gin.Context) {
+	referrals, err := h.referralService.ListFlaggedReferrals()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"referrals": referrals})
+}
+
+// ReviewReferral approves (releases held rewards) or rejects a flagged referral
+// POST /api/v1/admin-referrals/:id/review
+func (h *ReferralHandler) ReviewReferral(c *gin.Context) {
+	referralID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	var req struct {
+		Approve bool   `json:"approve"`
+		Notes   string `json:"notes"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	adminID := c.GetUint("user_id")
+	if err := h.referralService.ReviewReferral(uint(referralID), adminID, req.Approve, req.Notes); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
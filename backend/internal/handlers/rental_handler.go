@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lorengraff/crypto-tower-defense/internal/services"
+)
+
+type RentalHandler struct {
+	rentalService *services.RentalService
+}
+
+func NewRentalHandler(rentalService *services.RentalService) *RentalHandler {
+	return &RentalHandler{
+		rentalService: rentalService,
+	}
+}
+
+// OfferRentalRequest puts a character up for rent
+type OfferRentalRequest struct {
+	CharacterID uint `json:"character_id" binding:"required"`
+	services.RentalTerms
+}
+
+// ListOffers returns the rental offers the player can accept
+func (h *RentalHandler) ListOffers(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	offers, err := h.rentalService.ListOffers(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"offers": offers})
+}
+
+// ListRentals returns the characters the player lent out or borrowed
+func (h *RentalHandler) ListRentals(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	rentals, err := h.rentalService.ListRentals(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"rentals": rentals})
+}
+
+// OfferRental puts one of the player's characters up for rent
+func (h *RentalHandler) OfferRental(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req OfferRentalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rental, err := h.rentalService.OfferRental(userID, req.CharacterID, req.RentalTerms)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"rental":  rental,
+	})
+}
+
+// AcceptRental rents a character, paying its fee
+func (h *RentalHandler) AcceptRental(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	rentalID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	rental, err := h.rentalService.AcceptRental(userID, uint(rentalID))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"rental":  rental,
+	})
+}
+
+// CancelOffer withdraws a rental offer nobody accepted yet
+func (h *RentalHandler) CancelOffer(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	rentalID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	if err := h.rentalService.CancelOffer(userID, uint(rentalID)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// ReturnRental hands a borrowed character back before its term ends
+func (h *RentalHandler) ReturnRental(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	rentalID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	if err := h.rentalService.ReturnRental(userID, uint(rentalID)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
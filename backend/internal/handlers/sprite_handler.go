@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lorengraff/crypto-tower-defense/internal/services"
+)
+
+// SpriteHandler exposes sprite generation progress and admin regeneration
+type SpriteHandler struct {
+	spriteService *services.SpriteJobService
+}
+
+// NewSpriteHandler creates a new sprite handler
+func NewSpriteHandler(spriteService *services.SpriteJobService) *SpriteHandler {
+	return &SpriteHandler{spriteService: spriteService}
+}
+
+// GetSpriteStatus returns the character's sprite job (status, progress) and its sheet URLs
+// GET /api/v1/characters/:id/sprites
+func (h *SpriteHandler) GetSpriteStatus(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	characterID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid character ID"})
+		return
+	}
+
+	job, char, err := h.spriteService.GetJob(uint(characterID), userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status": char.SpriteGenStatus,
+		"job":    job,
+		"sheets": gin.H{
+			"idle":    char.SpriteIdle,
+			"walk":    char.SpriteWalk,
+			"run":     char.SpriteRun,
+			"attack":  char.SpriteAttack,
+			"skill":   char.SpriteSkill,
+			"hit":     char.SpriteHit,
+			"block":   char.SpriteBlock,
+			"dodge":   char.SpriteDodge,
+			"death":   char.SpriteDeath,
+			"victory": char.SpriteVictory,
+		},
+	})
+}
+
+// RegenerateSprites queues a fresh sprite job for a character
+// POST /api/v1/admin-sprites/:id/regenerate
+func (h *SpriteHandler) RegenerateSprites(c *gin.Context) {
+	characterID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid character ID"})
+		return
+	}
+
+	job, err := h.spriteService.Enqueue(uint(characterID))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"job": job})
+}
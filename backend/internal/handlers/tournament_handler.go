@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lorengraff/crypto-tower-defense/internal/services"
+)
+
+// TournamentHandler exposes tournament registration, brackets and admin controls
+type TournamentHandler struct {
+	tournamentService *services.TournamentService
+}
+
+func NewTournamentHandler(tournamentService *services.TournamentService) *TournamentHandler {
+	return &TournamentHandler{
+		tournamentService: tournamentService,
+	}
+}
+
+// ListTournaments returns tournaments, filtered by ?status=
+// GET /api/v1/tournaments
+func (h *TournamentHandler) ListTournaments(c *gin.Context) {
+	tournaments, err := h.tournamentService.ListTournaments(c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"tournaments": tournaments})
+}
+
+// GetTournament returns a tournament with participants and bracket
+// GET /api/v1/tournaments/:id
+func (h *TournamentHandler) GetTournament(c *gin.Context) {
+	tournamentID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	tournament, err := h.tournamentService.GetTournament(uint(tournamentID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, tournament)
+}
+
+// GetStandings returns the current standings
+// GET /api/v1/tournaments/:id/standings
+func (h *TournamentHandler) GetStandings(c *gin.Context) {
+	tournamentID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	standings, err := h.tournamentService.GetStandings(uint(tournamentID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"standings": standings})
+}
+
+// Register enters the tournament and locks the entry fee in escrow
+// POST /api/v1/tournaments/:id/register
+func (h *TournamentHandler) Register(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	tournamentID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	participant, err := h.tournamentService.Register(uint(tournamentID), userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"participant": participant,
+	})
+}
+
+// Unregister leaves the tournament before it starts and refunds the entry fee
+// DELETE /api/v1/tournaments/:id/register
+func (h *TournamentHandler) Unregister(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	tournamentID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	if err := h.tournamentService.Unregister(uint(tournamentID), userID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// CreateTournament (Admin) opens a new tournament for registration
+// POST /api/v1/admin-tournaments
+func (h *TournamentHandler) CreateTournament(c *gin.Context) {
+	var req services.CreateTournamentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	adminID := c.GetUint("user_id")
+	tournament, err := h.tournamentService.CreateTournament(req, adminID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, tournament)
+}
+
+// StartTournament (Admin) closes registration early and schedules round 1
+// POST /api/v1/admin-tournaments/:id/start
+func (h *TournamentHandler) StartTournament(c *gin.Context) {
+	tournamentID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	if err := h.tournamentService.StartTournament(uint(tournamentID)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tournament, err := h.tournamentService.GetTournament(uint(tournamentID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, tournament)
+}
+
+// AdvanceTournament (Admin) collects results, applies walkovers and advances the round now
+// POST /api/v1/admin-tournaments/:id/advance
+func (h *TournamentHandler) AdvanceTournament(c *gin.Context) {
+	tournamentID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	tournament, err := h.tournamentService.AdvanceTournament(uint(tournamentID))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, tournament)
+}
+
+// CancelTournament (Admin) cancels and refunds all entry fees
+// POST /api/v1/admin-tournaments/:id/cancel
+func (h *TournamentHandler) CancelTournament(c *gin.Context) {
+	tournamentID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	var req struct {
+		Reason string `json:"reason" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	adminID := c.GetUint("user_id")
+	if err := h.tournamentService.CancelTournament(uint(tournamentID), adminID, req.Reason); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Tournament cancelled and entry fees refunded"})
+}
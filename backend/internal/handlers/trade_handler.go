@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lorengraff/crypto-tower-defense/internal/services"
+)
+
+type TradeHandler struct {
+	tradeService *services.TradeService
+}
+
+func NewTradeHandler(tradeService *services.TradeService) *TradeHandler {
+	return &TradeHandler{
+		tradeService: tradeService,
+	}
+}
+
+// OpenTradeRequest opens a trade window with another player
+type OpenTradeRequest struct {
+	CounterpartyID uint `json:"counterparty_id" binding:"required"`
+}
+
+// ListTrades returns the player's trades (optionally ?status=OPEN)
+func (h *TradeHandler) ListTrades(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	trades, err := h.tradeService.ListTrades(userID, c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"trades": trades})
+}
+
+// GetTrade returns one of the player's trades with both offers
+func (h *TradeHandler) GetTrade(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	tradeID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	trade, err := h.tradeService.GetTrade(userID, uint(tradeID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"trade": trade})
+}
+
+// OpenTrade opens an empty trade window
+func (h *TradeHandler) OpenTrade(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req OpenTradeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	trade, err := h.tradeService.OpenTrade(userID, req.CounterpartyID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"trade":   trade,
+	})
+}
+
+// SetOffer replaces the player's side of a trade
+func (h *TradeHandler) SetOffer(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	tradeID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	var req services.TradeOffer
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	trade, err := h.tradeService.SetOffer(userID, uint(tradeID), req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"trade":   trade,
+	})
+}
+
+// Confirm accepts both current offers; the second confirmation settles the trade
+func (h *TradeHandler) Confirm(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	tradeID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	trade, err := h.tradeService.Confirm(userID, uint(tradeID))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"trade":   trade,
+	})
+}
+
+// Cancel walks away from an open trade
+func (h *TradeHandler) Cancel(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	tradeID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	if err := h.tradeService.Cancel(userID, uint(tradeID)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
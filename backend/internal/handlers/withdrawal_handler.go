@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lorengraff/crypto-tower-defense/internal/services"
+)
+
+// WithdrawalHandler exposes on-chain withdrawal status and admin approvals
+type WithdrawalHandler struct {
+	withdrawalService *services.WithdrawalService
+}
+
+// NewWithdrawalHandler creates a new withdrawal handler
+func NewWithdrawalHandler(withdrawalService *services.WithdrawalService) *WithdrawalHandler {
+	return &WithdrawalHandler{withdrawalService: withdrawalService}
+}
+
+// GetMyWithdrawals returns the caller's withdrawals and their on-chain status
+// GET /api/v1/economy/withdrawals
+func (h *WithdrawalHandler) GetMyWithdrawals(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	withdrawals, err := h.withdrawalService.GetUserWithdrawals(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"withdrawals": withdrawals})
+}
+
+// ListWithdrawals returns withdrawals filtered by ?status= (e.g. PENDING_APPROVAL)
+// GET /api/v1/admin-withdrawals
+func (h *WithdrawalHandler) ListWithdrawals(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+
+	withdrawals, err := h.withdrawalService.ListWithdrawals(c.Query("status"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"withdrawals": withdrawals})
+}
+
+// ApproveWithdrawal adds an admin approval; large withdrawals need two different admins
+// POST /api/v1/admin-withdrawals/:id/approve
+func (h *WithdrawalHandler) ApproveWithdrawal(c *gin.Context) {
+	adminID := c.GetUint("user_id")
+	withdrawalID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	withdrawal, err := h.withdrawalService.Approve(uint(withdrawalID), adminID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "withdrawal": withdrawal})
+}
+
+// RejectWithdrawal rejects a withdrawal awaiting approval and refunds the player
+// POST /api/v1/admin-withdrawals/:id/reject
+func (h *WithdrawalHandler) RejectWithdrawal(c *gin.Context) {
+	adminID := c.GetUint("user_id")
+	withdrawalID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	c.ShouldBindJSON(&req)
+
+	if err := h.withdrawalService.Reject(uint(withdrawalID), adminID, req.Reason); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
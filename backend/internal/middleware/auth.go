@@ -2,9 +2,9 @@ package middleware
 
 import (
 	"fmt"
-	"log"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
@@ -16,13 +16,13 @@ import (
 type Claims struct {
 	UserID        uint   `json:"user_id"`
 	WalletAddress string `json:"wallet_address"`
+	SessionID     uint   `json:"sid"`
 	jwt.RegisteredClaims
 }
 
 // AuthMiddleware validates JWT tokens
 func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		log.Printf("[DEBUG] AuthMiddleware: %s %s", c.Request.Method, c.Request.URL.Path)
 		// Get token from Authorization header
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -70,13 +70,18 @@ func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
 			return
 		}
 
-		// Security: Check Status in DB (BAN check)
+		// Security: Check Status in DB (BAN check) and that the session has not been revoked
 		var user struct {
-			Status   string
-			IsBanned bool
+			Status           string
+			IsBanned         bool
+			SessionID        *uint
+			SessionRevokedAt *time.Time
 		}
 		// Optimized query: only select status fields
-		if err := db.DB.Table("users").Select("status, is_banned").Where("id = ?", claims.UserID).Scan(&user).Error; err != nil {
+		if err := db.DB.Table("users").
+			Select("users.status, users.is_banned, user_sessions.id AS session_id, user_sessions.revoked_at AS session_revoked_at").
+			Joins("LEFT JOIN user_sessions ON user_sessions.id = ? AND user_sessions.user_id = users.id", claims.SessionID).
+			Where("users.id = ?", claims.UserID).Scan(&user).Error; err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
 			c.Abort()
 			return
@@ -88,9 +93,19 @@ func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
 			return
 		}
 
+		if user.SessionID == nil || user.SessionRevokedAt != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Session expired or revoked. Please sign in again."})
+			c.Abort()
+			return
+		}
+
+		// Presence heartbeat (throttled to one write per minute per user)
+		db.DB.Exec("UPDATE users SET last_seen_at = NOW() WHERE id = ? AND (last_seen_at IS NULL OR last_seen_at < NOW() - INTERVAL '1 minute')", claims.UserID)
+
 		// Set user info in context
 		c.Set("user_id", claims.UserID)
 		c.Set("wallet_address", claims.WalletAddress)
+		c.Set("session_id", claims.SessionID)
 
 		c.Next()
 	}
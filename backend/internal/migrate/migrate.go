@@ -0,0 +1,155 @@
+// Package migrate applies versioned SQL migrations and records them in the
+// schema_migrations table.
+package migrate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrChecksumMismatch = errors.New("migration file changed after it was applied")
+	ErrIrreversible     = errors.New("migration has no down script")
+	ErrNotEmbedded      = errors.New("applied migration is not in this build")
+)
+
+// Migration is one versioned schema change
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string // Empty when the migration cannot be reversed
+	Checksum string // Hex SHA-256 of Up
+}
+
+func (m Migration) String() string {
+	return fmt.Sprintf("%03d_%s", m.Version, m.Name)
+}
+
+// Reversible reports whether the migration ships a down script
+func (m Migration) Reversible() bool {
+	return strings.TrimSpace(m.Down) != ""
+}
+
+var fileName = regexp.MustCompile(`^(\d+)_([A-Za-z0-9_]+?)(\.down)?\.sql$`)
+
+// Load reads NNN_name.sql files (and their optional NNN_name.down.sql
+// counterparts) from the root of fsys, ordered by version.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list migrations: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	hasUp := make(map[int64]bool)
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".sql") {
+			continue
+		}
+		parts := fileName.FindStringSubmatch(e.Name())
+		if parts == nil {
+			return nil, fmt.Errorf("migration %s: file name must look like 001_description.sql", e.Name())
+		}
+		version, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration %s: invalid version", e.Name())
+		}
+		body, err := fs.ReadFile(fsys, e.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", e.Name(), err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: parts[2]}
+			byVersion[version] = m
+		}
+		if m.Name != parts[2] {
+			return nil, fmt.Errorf("migrations %s and %s share version %d", m, e.Name(), version)
+		}
+
+		if parts[3] == ".down" {
+			m.Down = string(body)
+			continue
+		}
+		if hasUp[version] {
+			return nil, fmt.Errorf("migration %s is defined twice", m)
+		}
+		hasUp[version] = true
+		m.Up = string(body)
+		m.Checksum = checksum(body)
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for version, m := range byVersion {
+		if !hasUp[version] {
+			return nil, fmt.Errorf("migration %s has a down script but no up script", m)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+func checksum(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// planUp returns the unapplied migrations up to target (0 = all), in order.
+// A file edited after it was applied stops the plan: the recorded schema no
+// longer matches what the file would build.
+func planUp(migrations []Migration, applied map[int64]Record, target int64) ([]Migration, error) {
+	var pending []Migration
+	for _, m := range migrations {
+		if rec, ok := applied[m.Version]; ok {
+			if rec.Checksum != m.Checksum {
+				return nil, fmt.Errorf("%w: %s", ErrChecksumMismatch, m)
+			}
+			continue
+		}
+		if target > 0 && m.Version > target {
+			break
+		}
+		pending = append(pending, m)
+	}
+	return pending, nil
+}
+
+// planDown returns the last steps applied migrations, newest first. The whole
+// plan is rejected up front if any of them cannot be reversed.
+func planDown(migrations []Migration, applied map[int64]Record, steps int) ([]Migration, error) {
+	byVersion := make(map[int64]Migration, len(migrations))
+	for _, m := range migrations {
+		byVersion[m.Version] = m
+	}
+	versions := make([]int64, 0, len(applied))
+	for v := range applied {
+		versions = append(versions, v)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+	if steps < len(versions) {
+		versions = versions[:steps]
+	}
+
+	plan := make([]Migration, 0, len(versions))
+	for _, v := range versions {
+		m, ok := byVersion[v]
+		if !ok {
+			return nil, fmt.Errorf("%w: %03d_%s", ErrNotEmbedded, v, applied[v].Name)
+		}
+		if !m.Reversible() {
+			return nil, fmt.Errorf("%w: %s", ErrIrreversible, m)
+		}
+		plan = append(plan, m)
+	}
+	return plan, nil
+}
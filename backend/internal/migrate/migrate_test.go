@@ -0,0 +1,113 @@
+package migrate
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/lorengraff/crypto-tower-defense/migrations"
+)
+
+func file(body string) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte(body)}
+}
+
+func TestLoadOrdersAndPairsMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"010_add_index.sql":      file("CREATE INDEX a ON t(c);"),
+		"002_create_t.sql":       file("CREATE TABLE t (c INT);"),
+		"002_create_t.down.sql":  file("DROP TABLE t;"),
+		"README.md":              file("not a migration"),
+		"archive/old_script.sql": file("SELECT 1;"),
+	}
+	got, err := Load(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].String() != "002_create_t" || got[1].String() != "010_add_index" {
+		t.Fatalf("loaded %v", got)
+	}
+	if !got[0].Reversible() || got[1].Reversible() {
+		t.Fatalf("reversible = %v/%v, want true/false", got[0].Reversible(), got[1].Reversible())
+	}
+	if got[0].Checksum == "" || got[0].Checksum == got[1].Checksum {
+		t.Fatalf("checksums %q/%q", got[0].Checksum, got[1].Checksum)
+	}
+}
+
+func TestLoadRejectsAmbiguousFiles(t *testing.T) {
+	cases := map[string]fstest.MapFS{
+		"shared version": {"001_a.sql": file("SELECT 1;"), "001_b.sql": file("SELECT 2;")},
+		"unnumbered":     {"cleanup.sql": file("SELECT 1;")},
+		"orphan down":    {"003_a.down.sql": file("SELECT 1;")},
+		"zero version":   {"000_a.sql": file("SELECT 1;")},
+	}
+	for name, fsys := range cases {
+		if _, err := Load(fsys); err == nil {
+			t.Errorf("%s: loaded without error", name)
+		}
+	}
+}
+
+func TestEmbeddedMigrationsLoad(t *testing.T) {
+	got, err := Load(migrations.FS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) == 0 || got[0].Version != 1 {
+		t.Fatalf("embedded set starts at %v", got)
+	}
+	// down walks back past the baseline (43) to the first migration the runner knew about
+	for _, m := range got {
+		if m.Version >= 34 && !m.Reversible() {
+			t.Fatalf("migration %s has no down script", m)
+		}
+	}
+}
+
+func TestPlanUpSkipsAppliedAndStopsAtTarget(t *testing.T) {
+	all := []Migration{
+		{Version: 1, Name: "a", Checksum: "x1"},
+		{Version: 2, Name: "b", Checksum: "x2"},
+		{Version: 3, Name: "c", Checksum: "x3"},
+	}
+	applied := map[int64]Record{1: {Version: 1, Checksum: "x1"}}
+
+	plan, err := planUp(all, applied, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan) != 1 || plan[0].Version != 2 {
+		t.Fatalf("plan = %v, want only 002", plan)
+	}
+
+	applied[1] = Record{Version: 1, Checksum: "edited"}
+	if _, err := planUp(all, applied, 0); !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("err = %v, want ErrChecksumMismatch", err)
+	}
+}
+
+func TestPlanDownRevertsNewestFirst(t *testing.T) {
+	all := []Migration{
+		{Version: 1, Name: "a", Down: "DROP a;"},
+		{Version: 2, Name: "b"},
+		{Version: 3, Name: "c", Down: "DROP c;"},
+	}
+	applied := map[int64]Record{1: {Version: 1}, 2: {Version: 2}, 3: {Version: 3}}
+
+	plan, err := planDown(all, applied, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan) != 1 || plan[0].Version != 3 {
+		t.Fatalf("plan = %v, want 003", plan)
+	}
+	if _, err := planDown(all, applied, 2); !errors.Is(err, ErrIrreversible) {
+		t.Fatalf("err = %v, want ErrIrreversible", err)
+	}
+
+	applied[4] = Record{Version: 4, Name: "gone"}
+	if _, err := planDown(all, applied, 1); !errors.Is(err, ErrNotEmbedded) {
+		t.Fatalf("err = %v, want ErrNotEmbedded", err)
+	}
+}
@@ -0,0 +1,282 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"io/fs"
+	"sort"
+	"time"
+
+	"github.com/lorengraff/crypto-tower-defense/pkg/logger"
+)
+
+// lockKey is the pg_advisory_lock key serializing migration runs across
+// processes (several API replicas may start with MIGRATE_ON_START at once)
+const lockKey int64 = 0x6374645f6d6967 // "ctd_mig"
+
+const createTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version BIGINT PRIMARY KEY,
+	name TEXT NOT NULL,
+	checksum CHAR(64) NOT NULL,
+	applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+)`
+
+// Record is a row of schema_migrations
+type Record struct {
+	Version   int64
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+// Status describes one migration for `migrate status`
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+	Modified  bool // The file no longer matches the checksum recorded when it was applied
+	Missing   bool // Recorded as applied, but not embedded in this build
+}
+
+// Runner applies migrations to a PostgreSQL database
+type Runner struct {
+	db         *sql.DB
+	migrations []Migration
+
+	// DryRun reports what would run, writing the SQL to Out, without changing the database
+	DryRun bool
+	Out    io.Writer
+}
+
+// New loads the migrations in fsys and returns a runner for db
+func New(db *sql.DB, fsys fs.FS) (*Runner, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Runner{db: db, migrations: migrations, Out: io.Discard}, nil
+}
+
+// Migrations returns every embedded migration, oldest first
+func (r *Runner) Migrations() []Migration {
+	return r.migrations
+}
+
+// Up applies pending migrations up to and including target (0 = latest),
+// each in its own transaction, and returns those it applied.
+func (r *Runner) Up(ctx context.Context, target int64) ([]Migration, error) {
+	var done []Migration
+	err := r.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := r.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		plan, err := planUp(r.migrations, applied, target)
+		if err != nil {
+			return err
+		}
+		for _, m := range plan {
+			if err := r.run(ctx, conn, m, true); err != nil {
+				return err
+			}
+			done = append(done, m)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down reverts the last steps applied migrations, newest first
+func (r *Runner) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var done []Migration
+	err := r.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := r.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		plan, err := planDown(r.migrations, applied, steps)
+		if err != nil {
+			return err
+		}
+		for _, m := range plan {
+			if err := r.run(ctx, conn, m, false); err != nil {
+				return err
+			}
+			done = append(done, m)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Baseline records every migration up to version as applied without running
+// it. Databases built before the runner existed start from here.
+func (r *Runner) Baseline(ctx context.Context, version int64) ([]Migration, error) {
+	var done []Migration
+	err := r.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := r.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, m := range r.migrations {
+			if m.Version > version {
+				break
+			}
+			if _, ok := applied[m.Version]; ok {
+				continue
+			}
+			if r.DryRun {
+				fmt.Fprintf(r.Out, "-- baseline %s (dry run)\n", m)
+			} else if _, err := conn.ExecContext(ctx,
+				`INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)`,
+				m.Version, m.Name, m.Checksum); err != nil {
+				return fmt.Errorf("failed to record %s: %w", m, err)
+			}
+			done = append(done, m)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Pending returns the migrations Up would apply
+func (r *Runner) Pending(ctx context.Context) ([]Migration, error) {
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	applied, err := r.applied(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	return planUp(r.migrations, applied, 0)
+}
+
+// Status lists every embedded migration plus any applied one missing from this build
+func (r *Runner) Status(ctx context.Context) ([]Status, error) {
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	applied, err := r.applied(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(r.migrations))
+	for _, m := range r.migrations {
+		s := Status{Migration: m}
+		if rec, ok := applied[m.Version]; ok {
+			s.Applied = true
+			s.AppliedAt = rec.AppliedAt
+			s.Modified = rec.Checksum != m.Checksum
+			delete(applied, m.Version)
+		}
+		statuses = append(statuses, s)
+	}
+	for _, rec := range applied {
+		statuses = append(statuses, Status{
+			Migration: Migration{Version: rec.Version, Name: rec.Name, Checksum: rec.Checksum},
+			Applied:   true,
+			AppliedAt: rec.AppliedAt,
+			Missing:   true,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
+
+// withLock runs fn on a single connection holding the migration advisory lock
+func (r *Runner) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, lockKey); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	// Unlock even if ctx was cancelled; the lock is tied to this connection
+	defer conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, lockKey)
+
+	if !r.DryRun {
+		if _, err := conn.ExecContext(ctx, createTable); err != nil {
+			return fmt.Errorf("failed to create schema_migrations: %w", err)
+		}
+	}
+	return fn(conn)
+}
+
+// applied reads schema_migrations; a missing table means nothing is applied yet
+func (r *Runner) applied(ctx context.Context, conn *sql.Conn) (map[int64]Record, error) {
+	var exists bool
+	if err := conn.QueryRowContext(ctx, `SELECT to_regclass('schema_migrations') IS NOT NULL`).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to inspect schema_migrations: %w", err)
+	}
+	applied := make(map[int64]Record)
+	if !exists {
+		return applied, nil
+	}
+
+	rows, err := conn.QueryContext(ctx, `SELECT version, name, checksum, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var rec Record
+		if err := rows.Scan(&rec.Version, &rec.Name, &rec.Checksum, &rec.AppliedAt); err != nil {
+			return nil, err
+		}
+		applied[rec.Version] = rec
+	}
+	return applied, rows.Err()
+}
+
+// run executes one migration in either direction and updates schema_migrations
+// in the same transaction
+func (r *Runner) run(ctx context.Context, conn *sql.Conn, m Migration, up bool) error {
+	direction, script := "up", m.Up
+	if !up {
+		direction, script = "down", m.Down
+	}
+	if r.DryRun {
+		fmt.Fprintf(r.Out, "-- %s %s (dry run)\n%s\n", direction, m, script)
+		return nil
+	}
+
+	start := time.Now()
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return fmt.Errorf("migration %s %s failed: %w", m, direction, err)
+	}
+	if up {
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)`,
+			m.Version, m.Name, m.Checksum)
+	} else {
+		_, err = tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = $1`, m.Version)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record %s: %w", m, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx).Info("migration applied",
+		"migration", m.String(), "direction", direction, "duration", time.Since(start))
+	return nil
+}
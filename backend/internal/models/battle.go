@@ -1,8 +1,10 @@
 package models
 
 import (
+	"strings"
 	"time"
 
+	"github.com/lorengraff/crypto-tower-defense/pkg/metrics"
 	"gorm.io/gorm"
 )
 
@@ -47,3 +49,9 @@ type Battle struct {
 	// Performance Metrics
 	DurationSeconds int `gorm:"default:0" json:"duration_seconds"`
 }
+
+// AfterCreate hook: every battle creation path counts towards ctd_battles_started_total
+func (b *Battle) AfterCreate(tx *gorm.DB) error {
+	metrics.BattlesStarted.WithLabelValues(strings.ToLower(b.BattleType)).Inc()
+	return nil
+}
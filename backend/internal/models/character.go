@@ -20,6 +20,9 @@ type Character struct {
 	IsMinted   bool       `gorm:"default:false;index" json:"is_minted"`
 	MintedAt   *time.Time `json:"minted_at,omitempty"`
 
+	// Rental: the borrower plays the character until the term ends; ownership does not move
+	ControllerID *uint `gorm:"index" json:"controller_id,omitempty"`
+
 	// NFT/Blockchain fields
 	OnChainTokenID *uint64 `gorm:"uniqueIndex" json:"on_chain_token_id,omitempty"`
 	MetadataURI    string  `gorm:"type:varchar(200)" json:"metadata_uri,omitempty"`
@@ -72,6 +75,8 @@ type Character struct {
 	// Breeding (Phase 17)
 	BreedCount int        `gorm:"default:0" json:"breed_count"`
 	LastBredAt *time.Time `json:"last_bred_at"`
+	Generation int        `gorm:"default:0" json:"generation"` // 0 for minted characters, parents' highest + 1 for bred ones
+	Genome     string     `gorm:"type:jsonb" json:"-"`         // Dominant and recessive genes; recessives are hidden
 
 	// Durability & Fatigue
 	Durability   int        `gorm:"default:100;not null" json:"durability"` // 0-100
@@ -79,6 +84,7 @@ type Character struct {
 	LastBattleAt *time.Time `json:"last_battle_at,omitempty"`
 	IsDead       bool       `gorm:"default:false;index" json:"is_dead"` // Durability reached 0
 	CanBeRevived bool       `gorm:"default:true" json:"can_be_revived"`
+	ReviveCount  int        `gorm:"default:0;not null" json:"revive_count"` // Each revive costs more; the last one used up means permadeath
 
 	// Fainted status (for battle)
 	IsFainted bool       `gorm:"default:false;index" json:"is_fainted"`
@@ -126,6 +132,15 @@ type Character struct {
 	EquippedItems []Item `gorm:"many2many:character_equipped_items;" json:"-"`
 }
 
+// Controller returns the player who fields the character: the borrower while it is
+// rented out, otherwise its owner
+func (c *Character) Controller() uint {
+	if c.ControllerID != nil {
+		return *c.ControllerID
+	}
+	return c.OwnerID
+}
+
 // BeforeCreate hook
 func (c *Character) BeforeCreate(tx *gorm.DB) error {
 	// Set current stats equal to base stats at creation
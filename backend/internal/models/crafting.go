@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+)
+
+// Crafting job statuses
+const (
+	CraftingStatusCrafting  = "CRAFTING"
+	CraftingStatusSucceeded = "SUCCEEDED"
+	CraftingStatusFailed    = "FAILED"
+)
+
+// MaterialAmount is a quantity of a MATERIAL item, identified by name
+type MaterialAmount struct {
+	Name     string `json:"name"`
+	Quantity int    `json:"quantity"`
+}
+
+// CraftingRecipe turns MATERIAL items and GTK into an item (loaded from content/)
+type CraftingRecipe struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	Name          string    `gorm:"size:50;not null;uniqueIndex" json:"name"`
+	Description   string    `gorm:"type:text" json:"description"`
+	ItemType      string    `gorm:"size:20;not null" json:"item_type"` // WEAPON, ARMOR, ACCESSORY, RUNE, CONSUMABLE
+	ItemName      string    `gorm:"size:50;not null" json:"item_name"`
+	ConsumeEffect string    `gorm:"size:50" json:"consume_effect,omitempty"` // Consumables: REVIVE, REDUCE_FATIGUE, XP_BOOST, REPAIR
+	GTKCost       int64     `gorm:"not null;default:0" json:"gtk_cost"`
+	SuccessChance float64   `gorm:"not null" json:"success_chance"` // 0-1
+	CraftMinutes  int       `gorm:"not null;default:0" json:"craft_minutes"`
+	RarityWeights string    `gorm:"type:jsonb" json:"rarity_weights"` // JSONB object, rarity -> weight
+	Materials     string    `gorm:"type:jsonb" json:"materials"`      // JSONB array of MaterialAmount
+	IsActive      bool      `gorm:"default:true" json:"is_active"`
+}
+
+// SalvageYield is what salvaging equipment of one type gives back at rarity C
+type SalvageYield struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	ItemType  string    `gorm:"size:20;not null;uniqueIndex" json:"item_type"`
+	Materials string    `gorm:"type:jsonb" json:"materials"` // JSONB array of MaterialAmount
+}
+
+// CraftingJob is one craft in a player's queue. Materials and GTK are taken when
+// it starts; success and rarity are rolled when it is claimed after CompletesAt.
+type CraftingJob struct {
+	ID           uint           `gorm:"primaryKey" json:"id"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	UserID       uint           `gorm:"not null;index" json:"user_id"`
+	RecipeID     uint           `gorm:"not null" json:"recipe_id"`
+	Recipe       CraftingRecipe `gorm:"foreignKey:RecipeID" json:"recipe,omitempty"`
+	Status       string         `gorm:"size:20;not null;default:'CRAFTING'" json:"status"` // CRAFTING, SUCCEEDED, FAILED
+	GTKCost      int64          `gorm:"not null;default:0" json:"gtk_cost"`
+	CompletesAt  time.Time      `gorm:"not null" json:"completes_at"`
+	ClaimedAt    *time.Time     `json:"claimed_at,omitempty"`
+	ResultItemID *uint          `json:"result_item_id,omitempty"`
+	ResultRarity string         `gorm:"size:5" json:"result_rarity,omitempty"`
+}
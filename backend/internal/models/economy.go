@@ -42,6 +42,47 @@ type Transaction struct {
 	IsOnChain        bool    `gorm:"default:false;index" json:"is_on_chain"`
 }
 
+// Withdrawal tracks an on-chain TOWER payout for a WITHDRAWAL transaction.
+// Lifecycle: PENDING_APPROVAL -> APPROVED -> SENDING -> BROADCAST -> CONFIRMED, or FAILED/REJECTED (refunded).
+// SENDING rows hold a reserved nonce and the hash of a signed transfer the node may not have accepted yet.
+type Withdrawal struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	UserID        uint   `gorm:"not null;index" json:"user_id"`
+	TransactionID uint   `gorm:"not null;uniqueIndex" json:"transaction_id"`
+	ToAddress     string `gorm:"type:varchar(42);not null" json:"to_address"`
+	Amount        int64  `gorm:"not null" json:"amount"` // Whole TOWER
+	Status        string `gorm:"type:varchar(20);not null;index" json:"status"`
+
+	// Approvals (amounts above withdrawal_approval_threshold need two distinct admins)
+	RequiredApprovals int   `gorm:"default:0" json:"required_approvals"`
+	FirstApproverID   *uint `json:"first_approver_id,omitempty"`
+	SecondApproverID  *uint `json:"second_approver_id,omitempty"`
+
+	// Broadcast state
+	Nonce       *uint64    `json:"nonce,omitempty"`
+	TxHash      *string    `gorm:"type:varchar(66)" json:"tx_hash,omitempty"` // Latest broadcast (replacement) hash
+	TxHashes    string     `gorm:"type:text" json:"-"`                        // JSON array of every hash sent for this nonce
+	GasPriceWei string     `gorm:"type:varchar(40)" json:"gas_price_wei,omitempty"`
+	Attempts    int        `gorm:"default:0" json:"attempts"`
+	BroadcastAt *time.Time `json:"broadcast_at,omitempty"`
+	ConfirmedAt *time.Time `json:"confirmed_at,omitempty"`
+	LastError   string     `gorm:"type:varchar(255)" json:"last_error,omitempty"`
+}
+
+// Withdrawal statuses
+const (
+	WithdrawalPendingApproval = "PENDING_APPROVAL"
+	WithdrawalApproved        = "APPROVED"
+	WithdrawalSending         = "SENDING"
+	WithdrawalBroadcast       = "BROADCAST"
+	WithdrawalConfirmed       = "CONFIRMED"
+	WithdrawalFailed          = "FAILED"
+	WithdrawalRejected        = "REJECTED"
+)
+
 // DailyLimit tracks daily emission caps per user
 type DailyLimit struct {
 	ID        uint      `gorm:"primaryKey" json:"id"`
@@ -80,10 +121,20 @@ type MarketplaceListing struct {
 	ItemID      *uint      `gorm:"index" json:"item_id,omitempty"`
 	Item        *Item      `gorm:"foreignKey:ItemID" json:"item,omitempty"`
 
+	// Listing Format
+	ListingType string             `gorm:"type:varchar(20);not null;default:'FIXED';index" json:"listing_type"` // FIXED, AUCTION, BUNDLE
+	BundleItems []MarketplaceAsset `gorm:"foreignKey:ListingID" json:"bundle_items,omitempty"`                  // BUNDLE only
+
 	// Pricing
-	Price      int64 `gorm:"not null" json:"price"`         // In TOWER tokens
+	Price      int64 `gorm:"not null" json:"price"`         // In TOWER tokens (AUCTION: starting bid)
 	ListingFee int64 `gorm:"default:10" json:"listing_fee"` // Flat fee in GTK
 
+	// Auction (English, ascending)
+	ReservePrice    int64 `gorm:"default:0" json:"-"` // Hidden minimum; unmet reserve returns the asset
+	CurrentBid      int64 `gorm:"default:0" json:"current_bid,omitempty"`
+	HighestBidderID *uint `json:"highest_bidder_id,omitempty"`
+	BidCount        int   `gorm:"default:0" json:"bid_count,omitempty"`
+
 	// Status
 	Status    string     `gorm:"type:varchar(20);not null;index;default:'ACTIVE'" json:"status"` // ACTIVE, SOLD, CANCELLED, EXPIRED
 	ExpiresAt time.Time  `gorm:"index" json:"expires_at"`                                        // Max 7 days
@@ -94,22 +145,55 @@ type MarketplaceListing struct {
 	// Featured (paid promotion)
 }
 
-// LoginReward tracks daily login rewards (Phase 21)
-type LoginReward struct {
-	ID           uint      `gorm:"primaryKey" json:"id"`
-	UserID       uint      `gorm:"not null;index" json:"user_id"`
-	DayNumber    int       `gorm:"not null" json:"day_number"`
-	ClaimedAt    time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"claimed_at"`
-	RewardTokens int       `json:"reward_tokens"`
-	RewardItems  string    `gorm:"type:text" json:"reward_items"`
+// Marketplace listing formats
+const (
+	ListingTypeFixed   = "FIXED"
+	ListingTypeAuction = "AUCTION"
+	ListingTypeBundle  = "BUNDLE"
+)
+
+// MarketplaceAsset is one character or item inside a bundle listing
+type MarketplaceAsset struct {
+	ID        uint   `gorm:"primaryKey" json:"id"`
+	ListingID uint   `gorm:"not null;index" json:"listing_id"`
+	AssetType string `gorm:"type:varchar(20);not null" json:"asset_type"` // character, item, equipment
+	AssetID   uint   `gorm:"not null" json:"asset_id"`
+}
+
+// MarketplaceBid is a bid on an auction listing. Only the highest bid holds funds in escrow.
+type MarketplaceBid struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	ListingID uint      `gorm:"not null;index" json:"listing_id"`
+	BidderID  uint      `gorm:"not null;index" json:"bidder_id"`
+	Amount    int64     `gorm:"not null" json:"amount"`
+	Status    string    `gorm:"type:varchar(20);not null;default:'ACTIVE'" json:"status"` // ACTIVE, OUTBID, WON, REFUNDED
+}
 
-	User User `gorm:"foreignKey:UserID" json:"-"`
+// MarketplaceOffer is a best offer on any owned character or item, listed or not.
+// The offered GTK sits in ESCROW until the owner accepts, or the offer is rejected, cancelled or expires.
+type MarketplaceOffer struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	BuyerID     uint       `gorm:"not null;index" json:"buyer_id"`
+	SellerID    uint       `gorm:"not null;index" json:"seller_id"` // Owner at the time of the offer
+	AssetType   string     `gorm:"type:varchar(20);not null" json:"asset_type"`
+	AssetID     uint       `gorm:"not null;index" json:"asset_id"`
+	Amount      int64      `gorm:"not null" json:"amount"`
+	Message     string     `gorm:"size:140" json:"message,omitempty"`
+	Status      string     `gorm:"type:varchar(20);not null;default:'PENDING';index" json:"status"` // PENDING, ACCEPTED, REJECTED, CANCELLED, EXPIRED
+	ExpiresAt   time.Time  `gorm:"index" json:"expires_at"`
+	RespondedAt *time.Time `json:"responded_at,omitempty"`
+
+	Buyer User `gorm:"foreignKey:BuyerID" json:"buyer,omitempty"`
 }
 
 // TradeHistory tracks completed trades (Phase 19)
 type TradeHistory struct {
 	ID          uint      `gorm:"primaryKey" json:"id"`
 	ListingID   *uint     `json:"listing_id"`
+	TradeID     *uint     `gorm:"index" json:"trade_id,omitempty"` // Direct player trade the asset moved in
 	SellerID    uint      `gorm:"not null" json:"seller_id"`
 	BuyerID     uint      `gorm:"not null" json:"buyer_id"`
 	ItemType    string    `gorm:"size:30;not null" json:"item_type"`
@@ -22,6 +22,8 @@ type Egg struct {
 	// Predetermined traits (hidden until hatch or scanned)
 	PredeterminedStats     string `gorm:"type:jsonb" json:"predetermined_stats,omitempty"`     // JSON: {hp, atk, def, spd}
 	PredeterminedAbilities string `gorm:"type:jsonb" json:"predetermined_abilities,omitempty"` // JSON: [ability_ids]
+	Genome                 string `gorm:"type:jsonb" json:"-"`                                 // Offspring genome of bred eggs
+	GeneSeed               int64  `json:"-"`                                                   // Seed the genome was inherited with
 
 	// Stats reveal
 	IsStatsRevealed bool       `gorm:"default:false" json:"is_stats_revealed"`
@@ -70,3 +72,29 @@ type BreedingSession struct {
 	Parent2 Character `gorm:"foreignKey:Parent2ID" json:"parent2"`
 	Egg     *Egg      `gorm:"foreignKey:EggID" json:"egg,omitempty"`
 }
+
+// Egg care actions, one window each during incubation
+const (
+	EggCareCalibrate = "CALIBRATE"
+	EggCareNurture   = "NURTURE"
+	EggCareStabilize = "STABILIZE"
+)
+
+// Egg care outcomes
+const (
+	EggCareDone   = "DONE"
+	EggCareMissed = "MISSED"
+)
+
+// EggCare records how one care window of an egg ended: performed (by the owner or a
+// friend) or missed. Either way it has already been applied to PredeterminedStats.
+type EggCare struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt     time.Time `json:"created_at"`
+	EggID         uint      `gorm:"not null;uniqueIndex:idx_egg_care_action" json:"egg_id"`
+	Action        string    `gorm:"size:20;not null;uniqueIndex:idx_egg_care_action" json:"action"`
+	Status        string    `gorm:"size:10;not null" json:"status"`
+	PerformedByID *uint     `json:"performed_by_id,omitempty"`
+	PaidWith      string    `gorm:"size:50" json:"paid_with,omitempty"` // GTK or the consumable's name
+	StatChange    string    `gorm:"type:jsonb" json:"stat_change"`      // JSON: {hp, atk, def, spd} deltas
+}
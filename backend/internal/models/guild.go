@@ -0,0 +1,120 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Guild member roles
+const (
+	GuildRoleLeader  = "LEADER"
+	GuildRoleOfficer = "OFFICER"
+	GuildRoleMember  = "MEMBER"
+)
+
+// Guild is a player group with a shared treasury, chat and cooperative raids
+type Guild struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	Name        string `gorm:"size:50;not null;uniqueIndex" json:"name"`
+	Tag         string `gorm:"size:5;not null;uniqueIndex" json:"tag"`
+	Description string `gorm:"size:255" json:"description"`
+	LeaderID    uint   `gorm:"not null;index" json:"leader_id"`
+	MaxMembers  int    `gorm:"default:30" json:"max_members"`
+
+	// Treasury withdrawal policy
+	// Withdrawals up to WithdrawalAutoLimit are executed immediately when the leader requests them.
+	// Anything larger needs WithdrawalApprovals distinct officer/leader approvals (requester excluded),
+	// or approvals from all of them when the guild has fewer, but always at least one.
+	WithdrawalApprovals int   `gorm:"default:2" json:"withdrawal_approvals"`
+	WithdrawalAutoLimit int64 `gorm:"default:0" json:"withdrawal_auto_limit"`
+
+	Members []GuildMember `gorm:"foreignKey:GuildID" json:"members,omitempty"`
+}
+
+// GuildMember links a user to a guild. A user belongs to at most one guild.
+type GuildMember struct {
+	ID       uint      `gorm:"primaryKey" json:"id"`
+	GuildID  uint      `gorm:"not null;index" json:"guild_id"`
+	UserID   uint      `gorm:"not null;uniqueIndex" json:"user_id"`
+	User     User      `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Role     string    `gorm:"type:varchar(20);not null;default:'MEMBER'" json:"role"` // LEADER, OFFICER, MEMBER
+	JoinedAt time.Time `json:"joined_at"`
+
+	Contribution int64 `gorm:"default:0" json:"contribution"` // Lifetime GTK deposited to the treasury
+}
+
+// GuildInvite is a pending invitation to join a guild
+type GuildInvite struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	CreatedAt   time.Time  `json:"created_at"`
+	GuildID     uint       `gorm:"not null;index" json:"guild_id"`
+	Guild       Guild      `gorm:"foreignKey:GuildID" json:"guild,omitempty"`
+	InviterID   uint       `gorm:"not null" json:"inviter_id"`
+	InviteeID   uint       `gorm:"not null;index" json:"invitee_id"`
+	Status      string     `gorm:"type:varchar(20);not null;default:'PENDING';index" json:"status"` // PENDING, ACCEPTED, DECLINED, EXPIRED
+	ExpiresAt   time.Time  `json:"expires_at"`
+	RespondedAt *time.Time `json:"responded_at,omitempty"`
+}
+
+// GuildChatMessage is one line of the guild chat log
+type GuildChatMessage struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+	GuildID   uint      `gorm:"not null;index" json:"guild_id"`
+	UserID    uint      `gorm:"not null" json:"user_id"`
+	Content   string    `gorm:"type:text;not null" json:"content"`
+	IsSystem  bool      `gorm:"default:false" json:"is_system"` // Joins, raid kills, treasury events
+}
+
+// GuildWithdrawalRequest is a treasury payout waiting for approvals
+type GuildWithdrawalRequest struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	GuildID     uint       `gorm:"not null;index" json:"guild_id"`
+	RequesterID uint       `gorm:"not null" json:"requester_id"`
+	RecipientID uint       `gorm:"not null" json:"recipient_id"`
+	Amount      int64      `gorm:"not null" json:"amount"`
+	Reason      string     `gorm:"size:255" json:"reason"`
+	Status      string     `gorm:"type:varchar(20);not null;default:'PENDING';index" json:"status"` // PENDING, EXECUTED, REJECTED
+	Approvals   string     `gorm:"type:text" json:"approvals"`                                      // JSON array of approving user IDs
+	ExecutedAt  *time.Time `json:"executed_at,omitempty"`
+}
+
+// GuildRaid is a cooperative boss fight shared by the whole guild over several days
+type GuildRaid struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	GuildID    uint     `gorm:"not null;index" json:"guild_id"`
+	RaidBossID uint     `gorm:"not null" json:"raid_boss_id"`
+	RaidBoss   RaidBoss `gorm:"foreignKey:RaidBossID" json:"raid_boss"`
+	StartedBy  uint     `json:"started_by"`
+
+	TotalHP     int64  `gorm:"not null" json:"total_hp"` // Copied from RaidBoss.TotalHP at start
+	RemainingHP int64  `gorm:"not null" json:"remaining_hp"`
+	RewardPool  int64  `gorm:"not null" json:"reward_pool"`                                    // GTK split by contribution
+	Status      string `gorm:"type:varchar(20);not null;default:'ACTIVE';index" json:"status"` // ACTIVE, DEFEATED, EXPIRED
+
+	EndsAt    time.Time  `gorm:"index" json:"ends_at"`
+	SettledAt *time.Time `json:"settled_at,omitempty"`
+
+	Contributions []GuildRaidContribution `gorm:"foreignKey:GuildRaidID" json:"contributions,omitempty"`
+}
+
+// GuildRaidContribution tracks one member's damage and payout in a guild raid
+type GuildRaidContribution struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	GuildRaidID  uint       `gorm:"not null;uniqueIndex:idx_guild_raid_user" json:"guild_raid_id"`
+	UserID       uint       `gorm:"not null;uniqueIndex:idx_guild_raid_user" json:"user_id"`
+	Damage       int64      `gorm:"default:0" json:"damage"`
+	Attacks      int        `gorm:"default:0" json:"attacks"`
+	LastAttackAt *time.Time `json:"last_attack_at,omitempty"`
+	Reward       int64      `gorm:"default:0" json:"reward"`
+}
@@ -93,7 +93,7 @@ type ShopItem struct {
 type LootTable struct {
 	ID        uint      `gorm:"primaryKey" json:"id"`
 	Name      string    `gorm:"size:100;not null" json:"name"`
-	MissionID *uint     `json:"mission_id"`
+	MissionID *uint     `json:"mission_id"` // IslandMission the table drops from
 	DropType  string    `gorm:"size:20;default:'random'" json:"drop_type"`
 	CreatedAt time.Time `json:"created_at"`
 
@@ -111,7 +111,7 @@ type LootEntry struct {
 	RarityWeight int     `gorm:"default:1" json:"rarity_weight"`
 
 	LootTable LootTable `gorm:"foreignKey:LootTableID" json:"-"`
-	Item      *Item     `gorm:"foreignKey:ItemID" json:"item"`
+	Item      *ShopItem `gorm:"foreignKey:ItemID" json:"item"`
 }
 
 // BattleReward tracks rewards earned from battles (Phase 16)
@@ -15,13 +15,16 @@ const (
 	AccountTypeEscrow   AccountType = "ESCROW"   // Temporary hold (e.g., during battle)
 	AccountTypeReward   AccountType = "REWARD"   // Source of rewards (inflationary)
 	AccountTypeSink     AccountType = "SINK"     // Destination for burnt tokens
+
+	AccountTypeGuildTreasury AccountType = "GUILD_TREASURY" // Shared guild funds (GuildID set, UserID null)
 )
 
 // LedgerAccount represents a logical account in the double-entry system
 // A User can have multiple accounts (e.g., Wallet, Escrow)
 type LedgerAccount struct {
 	ID        uint           `gorm:"primaryKey" json:"id"`
-	UserID    *uint          `gorm:"index" json:"user_id,omitempty"` // Null for system accounts
+	UserID    *uint          `gorm:"index" json:"user_id,omitempty"`  // Null for system accounts
+	GuildID   *uint          `gorm:"index" json:"guild_id,omitempty"` // Set for guild treasuries
 	Type      AccountType    `gorm:"type:varchar(20);not null" json:"type"`
 	Currency  string         `gorm:"type:varchar(10);default:'GTK'" json:"currency"`
 	Balance   int64          `gorm:"default:0" json:"balance"`
@@ -52,6 +55,30 @@ const (
 	TxTypeRaidReward   TransactionType = "RAID_REWARD"   // Added
 	TxTypeRankedReward TransactionType = "RANKED_REWARD" // Added
 	TxTypeReward       TransactionType = "REWARD"        // Generic reward
+
+	TxTypeTournamentEntry  TransactionType = "TOURNAMENT_ENTRY"
+	TxTypeTournamentRefund TransactionType = "TOURNAMENT_REFUND"
+	TxTypeTournamentPrize  TransactionType = "TOURNAMENT_PRIZE"
+
+	TxTypeGuildDeposit  TransactionType = "GUILD_DEPOSIT"
+	TxTypeGuildWithdraw TransactionType = "GUILD_WITHDRAWAL"
+
+	TxTypeReferralReward TransactionType = "REFERRAL_REWARD"
+
+	TxTypeMarketEscrow TransactionType = "MARKET_ESCROW" // Bid / offer funds locked
+	TxTypeMarketRefund TransactionType = "MARKET_REFUND" // Outbid, rejected or expired funds returned
+
+	TxTypeCraftFee   TransactionType = "CRAFT_FEE"
+	TxTypeEnhanceFee TransactionType = "ENHANCE_FEE"
+	TxTypeEggCare    TransactionType = "EGG_CARE"
+
+	TxTypeRentalFee TransactionType = "RENTAL_FEE" // Up-front fee paid to a character's owner
+	TxTypeTrade     TransactionType = "TRADE"      // GTK legs and fees of a direct player trade
+
+	TxTypeRepair TransactionType = "REPAIR" // Durability restored for GTK
+	TxTypeRevive TransactionType = "REVIVE" // Dead character brought back for GTK
+
+	TxTypeLoginReward TransactionType = "LOGIN_REWARD" // Daily login calendar payout
 )
 
 // LedgerTransaction groups entries required to balance (Sum Debits = Sum Credits)
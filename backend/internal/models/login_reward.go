@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// LoginCalendarDay is what one day of the login calendar pays: GTK, a shop item (egg
+// tickets are shop items too), or both. Milestone days are the big ones of the cycle.
+type LoginCalendarDay struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	DayNumber    int       `gorm:"not null;uniqueIndex" json:"day_number"` // 1 to login_calendar_length
+	RewardGTK    int64     `gorm:"not null;default:0" json:"reward_gtk"`
+	ItemID       *uint     `json:"item_id,omitempty"` // ShopItem granted into the inventory
+	ItemQuantity int       `gorm:"not null;default:0" json:"item_quantity"`
+	IsMilestone  bool      `gorm:"default:false" json:"is_milestone"`
+	UpdatedBy    uint      `gorm:"default:0" json:"updated_by"` // Admin who last changed the day; content seeding leaves it alone
+
+	Item *ShopItem `gorm:"foreignKey:ItemID" json:"item,omitempty"`
+}
+
+// LoginReward is one daily login claim. ClaimDate is the day in the player's timezone,
+// and the (user_id, claim_date) index is what stops two devices claiming the same day.
+type LoginReward struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	UserID       uint      `gorm:"not null;uniqueIndex:idx_login_rewards_user_date" json:"user_id"`
+	ClaimDate    string    `gorm:"size:10;not null;uniqueIndex:idx_login_rewards_user_date" json:"claim_date"` // YYYY-MM-DD
+	DayNumber    int       `gorm:"not null" json:"day_number"`                                                 // Calendar day paid out
+	Streak       int       `gorm:"not null;default:1" json:"streak"`                                           // Consecutive days, this one included
+	GraceUsed    bool      `gorm:"default:false" json:"grace_used"`                                            // The cycle's grace day is spent
+	ClaimedAt    time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"claimed_at"`
+	RewardTokens int       `json:"reward_tokens"`
+	RewardItems  string    `gorm:"type:text" json:"reward_items"` // JSON [{item_id, name, quantity}]
+
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
@@ -10,10 +10,18 @@ type LootDrop struct {
 	Rarity   string `json:"rarity"`
 }
 
-// Equipment represents equippable items (Phase 18)
+// Equipment slots
+const (
+	SlotWeapon    = "weapon"
+	SlotArmor     = "armor"
+	SlotAccessory = "accessory"
+)
+
+// Equipment represents equippable items (Phase 18): the slot, requirements, enhancement
+// and rune sockets of one WEAPON, ARMOR or ACCESSORY item
 type Equipment struct {
 	ID            uint   `gorm:"primaryKey" json:"id"`
-	ItemID        uint   `gorm:"not null" json:"item_id"`
+	ItemID        uint   `gorm:"not null;uniqueIndex" json:"item_id"`
 	Slot          string `gorm:"size:20;not null" json:"slot"` // weapon, armor, accessory
 	RequiredLevel int    `gorm:"default:1" json:"required_level"`
 	RequiredClass string `gorm:"size:20" json:"required_class"`
@@ -32,10 +40,24 @@ type Equipment struct {
 	UpgradeLevel    int `gorm:"default:0" json:"upgrade_level"`
 	MaxUpgradeLevel int `gorm:"default:5" json:"max_upgrade_level"`
 
+	// Rune Sockets
+	RuneSockets int `gorm:"default:0" json:"rune_sockets"`
+
 	CreatedAt time.Time `json:"created_at"`
 
 	// Relationships
-	Item Item `gorm:"foreignKey:ItemID" json:"item"`
+	Item  Item            `gorm:"foreignKey:ItemID" json:"item"`
+	Runes []EquipmentRune `gorm:"foreignKey:EquipmentID" json:"runes,omitempty"`
+}
+
+// EquipmentRune is a RUNE item socketed into a piece of equipment
+type EquipmentRune struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	EquipmentID uint      `gorm:"not null;index" json:"equipment_id"`
+	RuneItemID  uint      `gorm:"not null;uniqueIndex" json:"rune_item_id"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	RuneItem Item `gorm:"foreignKey:RuneItemID" json:"rune_item"`
 }
 
 // CharacterEquipment tracks equipped items
@@ -2,7 +2,9 @@ package models
 
 import (
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"math"
 )
 
 // PoseTemplate represents a predefined pose skeleton for ControlNet
@@ -124,20 +126,226 @@ func GetPoseTemplates() map[SpriteAnimationType][]PoseTemplate {
 	}
 }
 
-// GenerateOpenPoseSkeleton generates an OpenPose skeleton JSON for a given pose
-// This is a simplified version - in production, you'd have pre-rendered pose images
+// Keypoint is an OpenPose body keypoint in normalized canvas coordinates (0-1, y down)
+type Keypoint struct {
+	X          float64 `json:"x"`
+	Y          float64 `json:"y"`
+	Confidence float64 `json:"c"`
+}
+
+// OpenPose COCO-18 keypoint order
+const (
+	KeypointNose = iota
+	KeypointNeck
+	KeypointRShoulder
+	KeypointRElbow
+	KeypointRWrist
+	KeypointLShoulder
+	KeypointLElbow
+	KeypointLWrist
+	KeypointRHip
+	KeypointRKnee
+	KeypointRAnkle
+	KeypointLHip
+	KeypointLKnee
+	KeypointLAnkle
+	KeypointREye
+	KeypointLEye
+	KeypointREar
+	KeypointLEar
+	KeypointCount
+)
+
+// Limb lengths as a fraction of canvas height
+const (
+	poseTorso    = 0.22
+	poseNeck     = 0.06
+	poseShoulder = 0.07
+	poseHip      = 0.045
+	poseUpperArm = 0.11
+	poseForearm  = 0.10
+	poseThigh    = 0.13
+	poseShin     = 0.13
+)
+
+// poseAngles drives the forward kinematics of one frame. Angles are in degrees;
+// limbs hang straight down at 0 and positive values swing towards the facing direction (right).
+type poseAngles struct {
+	RootX, RootY float64 // Hip center
+	Lean         float64 // Torso tilt
+	Body         float64 // Whole-body rotation around the hips (death fall)
+	RShoulder    float64
+	RElbow       float64
+	LShoulder    float64
+	LElbow       float64
+	RHip, RKnee  float64
+	LHip, LKnee  float64
+	HeadTilt     float64
+}
+
+// Keypoints computes the 18 OpenPose keypoints of a pose frame, deterministically from its
+// animation type and frame index
+func (t PoseTemplate) Keypoints() []Keypoint {
+	frames := 1
+	for _, spec := range GetAnimationSpecs() {
+		if spec.Type == t.AnimationType {
+			frames = spec.FrameCount
+		}
+	}
+	return solvePose(posePhase(t.AnimationType, float64(t.FrameIndex)/float64(frames)))
+}
+
+// posePhase maps an animation and its normalized time (0-1) to joint angles
+func posePhase(anim SpriteAnimationType, p float64) poseAngles {
+	wave := math.Sin(2 * math.Pi * p)
+	a := poseAngles{RootX: 0.5, RootY: 0.55, RElbow: 10, LElbow: 10}
+
+	switch anim {
+	case SpriteIdle:
+		a.RootY += 0.008 * wave
+		a.RShoulder, a.LShoulder = 8+3*wave, -8-3*wave
+	case SpriteWalk:
+		a.RHip, a.LHip = 25*wave, -25*wave
+		a.RKnee, a.LKnee = -15*math.Max(0, -wave), -15*math.Max(0, wave)
+		a.RShoulder, a.LShoulder = -20*wave, 20*wave
+		a.RootY -= 0.01 * math.Abs(wave)
+	case SpriteRun:
+		a.Lean = 15
+		a.RHip, a.LHip = 45*wave, -45*wave
+		a.RKnee, a.LKnee = -50*math.Max(0, -wave), -50*math.Max(0, wave)
+		a.RShoulder, a.LShoulder = -40*wave, 40*wave
+		a.RElbow, a.LElbow = 70, 70
+		a.RootY -= 0.025 * math.Abs(wave)
+	case SpriteAttack:
+		// Wind-up until 0.2, strike until 0.7, recover to guard
+		switch {
+		case p < 0.2:
+			a.RShoulder, a.RElbow, a.Lean = -60*p/0.2, 90, -10*p/0.2
+		case p < 0.7:
+			k := (p - 0.2) / 0.5
+			a.RShoulder, a.RElbow, a.Lean = -60+160*k, 90*(1-k), -10+25*k
+		default:
+			k := (p - 0.7) / 0.3
+			a.RShoulder, a.RElbow, a.Lean = 100*(1-k), 10, 15*(1-k)
+		}
+		a.RHip, a.LHip = 15, -15
+	case SpriteSkill:
+		if p < 0.6 {
+			k := p / 0.6
+			a.RShoulder, a.LShoulder = 150*k, 150*k
+			a.RElbow, a.LElbow = 40*(1-k), 40*(1-k)
+		} else {
+			k := (p - 0.6) / 0.4
+			a.RShoulder, a.LShoulder = 150-60*k, 150-60*k
+			a.Lean = 10 * k
+		}
+		a.RHip, a.LHip = 12, -12
+	case SpriteHit:
+		k := math.Sin(math.Pi * p)
+		a.Lean, a.RootX, a.HeadTilt = -25*k, 0.5-0.05*k, -15*k
+		a.RShoulder, a.LShoulder = -30*k, 30*k
+	case SpriteBlock:
+		a.RShoulder, a.LShoulder = 80, 70
+		a.RElbow, a.LElbow = 100, 110
+		a.RHip, a.LHip, a.RKnee, a.LKnee = 20, -20, -20, -20
+		a.RootX -= 0.006 * math.Abs(wave)
+	case SpriteDodge:
+		k := math.Sin(math.Pi * p)
+		a.RootX, a.Lean = 0.5-0.2*k, -30*k
+		a.RootY += 0.04 * k
+		a.RHip, a.LHip, a.RKnee, a.LKnee = 40*k, -20*k, -60*k, -30*k
+	case SpriteDeath:
+		k := math.Min(1, p*1.15)
+		a.Body = -90 * k * k
+		a.RootY += 0.2 * k
+		a.RKnee, a.LKnee = -60*math.Sin(math.Pi*k), -60*math.Sin(math.Pi*k)
+		a.RShoulder, a.LShoulder = 20*k, -20*k
+	case SpriteVictory:
+		a.RShoulder, a.RElbow = 170, 10+20*math.Abs(wave)
+		a.LShoulder, a.LElbow = -20, 60
+		a.RootY -= 0.02 * math.Max(0, wave)
+	}
+	return a
+}
+
+// solvePose runs forward kinematics from the hips outwards
+func solvePose(a poseAngles) []Keypoint {
+	kp := make([]Keypoint, KeypointCount)
+	root := [2]float64{a.RootX, a.RootY}
+
+	// rotate places a point relative to root, rotated by the whole-body angle
+	rotate := func(x, y float64) [2]float64 {
+		r := a.Body * math.Pi / 180
+		dx, dy := x-root[0], y-root[1]
+		return [2]float64{root[0] + dx*math.Cos(r) - dy*math.Sin(r), root[1] + dx*math.Sin(r) + dy*math.Cos(r)}
+	}
+	// segment walks length units from p at angle deg (0 = straight down, positive = forward)
+	segment := func(p [2]float64, length, deg float64) [2]float64 {
+		r := deg * math.Pi / 180
+		return [2]float64{p[0] + length*math.Sin(r), p[1] + length*math.Cos(r)}
+	}
+	set := func(i int, p [2]float64) {
+		q := rotate(p[0], p[1])
+		kp[i] = Keypoint{X: q[0], Y: q[1], Confidence: 1}
+	}
+
+	neck := segment(root, poseTorso, 180+a.Lean)
+	set(KeypointNeck, neck)
+	head := segment(neck, poseNeck, 180+a.Lean+a.HeadTilt)
+	set(KeypointNose, [2]float64{head[0] + 0.02, head[1]})
+	set(KeypointREye, [2]float64{head[0] + 0.015, head[1] - 0.012})
+	set(KeypointLEye, [2]float64{head[0] + 0.025, head[1] - 0.012})
+	set(KeypointREar, [2]float64{head[0] - 0.015, head[1] - 0.005})
+	set(KeypointLEar, [2]float64{head[0] - 0.005, head[1] - 0.005})
+
+	for _, arm := range []struct {
+		shoulder, elbow, wrist int
+		side, angle, bend      float64
+	}{
+		{KeypointRShoulder, KeypointRElbow, KeypointRWrist, 1, a.RShoulder, a.RElbow},
+		{KeypointLShoulder, KeypointLElbow, KeypointLWrist, -1, a.LShoulder, a.LElbow},
+	} {
+		sh := [2]float64{neck[0] + arm.side*poseShoulder*0.5, neck[1] + 0.01}
+		el := segment(sh, poseUpperArm, arm.angle+a.Lean)
+		set(arm.shoulder, sh)
+		set(arm.elbow, el)
+		set(arm.wrist, segment(el, poseForearm, arm.angle+arm.bend+a.Lean))
+	}
+
+	for _, leg := range []struct {
+		hip, knee, ankle  int
+		side, angle, bend float64
+	}{
+		{KeypointRHip, KeypointRKnee, KeypointRAnkle, 1, a.RHip, a.RKnee},
+		{KeypointLHip, KeypointLKnee, KeypointLAnkle, -1, a.LHip, a.LKnee},
+	} {
+		hp := [2]float64{root[0] + leg.side*poseHip*0.5, root[1]}
+		kn := segment(hp, poseThigh, leg.angle)
+		set(leg.hip, hp)
+		set(leg.knee, kn)
+		set(leg.ankle, segment(kn, poseShin, leg.angle+leg.bend))
+	}
+	return kp
+}
+
+// GenerateOpenPoseSkeleton returns the pose as base64-encoded OpenPose JSON
+// ({"people":[{"pose_keypoints_2d":[x,y,c,...]}]}) on a 512x512 canvas, ready for ControlNet
 func GenerateOpenPoseSkeleton(template PoseTemplate) (string, error) {
-	// This would contain actual OpenPose keypoint data
-	// For now, return a placeholder that indicates the pose
-	skeleton := map[string]interface{}{
-		"animation": template.AnimationType,
-		"frame":     template.FrameIndex,
-		"pose":      template.Description,
-		// In production, add actual keypoints:
-		// "keypoints": [...] // 18 or 25 body keypoints with x, y, confidence
+	const canvas = 512
+	flat := make([]float64, 0, KeypointCount*3)
+	for _, k := range template.Keypoints() {
+		flat = append(flat, math.Round(k.X*canvas*10)/10, math.Round(k.Y*canvas*10)/10, k.Confidence)
 	}
 
-	// Convert to JSON and base64 encode
-	// Simplified for now
-	return base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%v", skeleton))), nil
+	doc := map[string]interface{}{
+		"version":       1.3,
+		"canvas_width":  canvas,
+		"canvas_height": canvas,
+		"people":        []map[string]interface{}{{"pose_keypoints_2d": flat}},
+	}
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode pose %s/%d: %w", template.AnimationType, template.FrameIndex, err)
+	}
+	return base64.StdEncoding.EncodeToString(raw), nil
 }
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// Provenance events
+const (
+	ProvenanceMinted      = "MINTED"      // Rolled from a gacha egg or created directly
+	ProvenanceBred        = "BRED"        // Inherited from two parents
+	ProvenanceHatched     = "HATCHED"     // Came out of its egg
+	ProvenanceTransferred = "TRANSFERRED" // Changed owner; Data.via says how
+	ProvenanceNFTMinted   = "NFT_MINTED"  // Minted on-chain
+	ProvenanceLevelUp     = "LEVEL_UP"
+	ProvenanceEvolved     = "EVOLVED"
+	ProvenanceAchievement = "ACHIEVEMENT" // Notable battle result; Data.achievement names it
+	ProvenanceDied        = "DIED"        // Durability ran out; Data.permanent when no revive is left
+	ProvenanceRevived     = "REVIVED"
+)
+
+// CharacterProvenance is one entry of a character's history. Entries are only ever
+// appended; the table rejects updates and deletes.
+type CharacterProvenance struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt   time.Time `gorm:"index" json:"created_at"`
+	CharacterID uint      `gorm:"not null;index" json:"character_id"`
+	Event       string    `gorm:"size:20;not null" json:"event"`
+	FromUserID  *uint     `json:"from_user_id,omitempty"` // Previous owner of a transfer
+	ToUserID    *uint     `json:"to_user_id,omitempty"`   // Owner after the event
+	Summary     string    `gorm:"size:255" json:"summary"`
+	Data        string    `gorm:"type:jsonb" json:"data,omitempty"` // Event specifics
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// Rental statuses
+const (
+	RentalStatusOffered   = "OFFERED"   // Waiting for a borrower to accept
+	RentalStatusActive    = "ACTIVE"    // The borrower controls the character
+	RentalStatusEnded     = "ENDED"     // Term over or returned early; the owner controls it again
+	RentalStatusCancelled = "CANCELLED" // Withdrawn by the owner before anyone accepted
+)
+
+// Rental lends a character to another player for a fixed term. Ownership stays with the
+// owner; the borrower becomes the character's controller and may field it in raids, PvE
+// and ranked, but cannot list, breed or transfer it. The borrower pays Fee up front and
+// hands SharePercent of what the character earns to the owner.
+type Rental struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	CharacterID   uint       `gorm:"not null;index" json:"character_id"`
+	Character     Character  `gorm:"foreignKey:CharacterID" json:"character,omitempty"`
+	OwnerID       uint       `gorm:"not null;index" json:"owner_id"`
+	BorrowerID    *uint      `gorm:"index" json:"borrower_id,omitempty"` // Set on direct offers and once accepted; nil offers are open to anyone
+	Fee           int64      `gorm:"not null;default:0" json:"fee"`
+	SharePercent  int        `gorm:"not null;default:0" json:"share_percent"` // Owner's cut of the borrower's earnings
+	DurationHours int        `gorm:"not null" json:"duration_hours"`
+	Status        string     `gorm:"size:20;not null;default:'OFFERED';index" json:"status"`
+	StartedAt     *time.Time `json:"started_at,omitempty"`
+	EndsAt        *time.Time `gorm:"index" json:"ends_at,omitempty"`
+	EndedAt       *time.Time `json:"ended_at,omitempty"`
+	OwnerEarnings int64      `gorm:"not null;default:0" json:"owner_earnings"` // Fee plus shares paid so far
+}
@@ -11,9 +11,10 @@ type SpriteGenerationJob struct {
 	Status      string     `gorm:"size:20;not null;index" json:"status"` // pending, processing, completed, failed
 	Progress    int        `gorm:"default:0" json:"progress"`            // 0-100
 	ErrorMsg    string     `gorm:"type:text" json:"error_msg,omitempty"`
-	Provider    string     `gorm:"size:50" json:"provider"` // openai, mock, etc.
-	RetryCount  int        `gorm:"default:0" json:"retry_count"`
-	MaxRetries  int        `gorm:"default:3" json:"max_retries"`
+	Provider    string     `gorm:"size:50" json:"provider"`            // openai, mock, etc.
+	RetryCount  int        `gorm:"default:0" json:"retry_count"`       // Failed or abandoned attempts so far
+	MaxRetries  int        `gorm:"default:3" json:"max_retries"`       // Retries after the first attempt
+	NextRunAt   *time.Time `gorm:"index" json:"next_run_at,omitempty"` // Backoff: not claimed before this time
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at"`
 	CompletedAt *time.Time `json:"completed_at,omitempty"`
@@ -22,6 +23,14 @@ type SpriteGenerationJob struct {
 	Character Character `gorm:"foreignKey:CharacterID" json:"character,omitempty"`
 }
 
+// Sprite job statuses (also used for Character.SpriteGenStatus)
+const (
+	SpriteJobPending    = "pending"
+	SpriteJobProcessing = "processing"
+	SpriteJobCompleted  = "completed"
+	SpriteJobFailed     = "failed"
+)
+
 // SpriteAnimationType represents the type of animation sprite
 type SpriteAnimationType string
 
@@ -52,7 +52,15 @@ type Leaderboard struct {
 	User User `gorm:"foreignKey:UserID" json:"user,omitempty"`
 }
 
+// Friendship statuses
+const (
+	FriendshipPending  = "pending"
+	FriendshipAccepted = "accepted"
+	FriendshipBlocked  = "blocked"
+)
+
 // Friendship for social features
+// UserID is the requester (or blocker), FriendID the target. Accepted friendships are a single row.
 type Friendship struct {
 	ID        uint      `gorm:"primaryKey" json:"id"`
 	UserID    uint      `gorm:"not null;index" json:"user_id"`
@@ -64,18 +72,74 @@ type Friendship struct {
 	Friend User `gorm:"foreignKey:FriendID" json:"friend,omitempty"`
 }
 
+// Challenge statuses
+const (
+	ChallengePending   = "PENDING"
+	ChallengeAccepted  = "ACCEPTED"
+	ChallengeDeclined  = "DECLINED"
+	ChallengeCancelled = "CANCELLED"
+	ChallengeExpired   = "EXPIRED"
+)
+
+// Challenge is a direct PvP invitation between friends, optionally with a GTK stake.
+// The challenger's stake is held in escrow until the opponent accepts or the challenge closes.
+type Challenge struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	ChallengerID uint       `gorm:"not null;index" json:"challenger_id"`
+	OpponentID   uint       `gorm:"not null;index" json:"opponent_id"`
+	Stake        int64      `gorm:"default:0" json:"stake"`                                          // Per player; 0 = friendly match
+	Status       string     `gorm:"type:varchar(20);not null;default:'PENDING';index" json:"status"` // PENDING, ACCEPTED, DECLINED, CANCELLED, EXPIRED
+	Message      string     `gorm:"size:140" json:"message"`
+	BattleID     *uint      `json:"battle_id,omitempty"`
+	ExpiresAt    time.Time  `gorm:"index" json:"expires_at"`
+	RespondedAt  *time.Time `json:"responded_at,omitempty"`
+
+	Challenger User `gorm:"foreignKey:ChallengerID" json:"challenger,omitempty"`
+	Opponent   User `gorm:"foreignKey:OpponentID" json:"opponent,omitempty"`
+}
+
+// Referral statuses
+const (
+	ReferralActive   = "ACTIVE"
+	ReferralFlagged  = "FLAGGED"  // Anti-abuse check tripped; rewards are held for review
+	ReferralApproved = "APPROVED" // Flag cleared by an admin; skipped by later abuse rechecks
+)
+
 // Referral for referral system
 type Referral struct {
 	ID            uint      `gorm:"primaryKey" json:"id"`
-	ReferrerID    uint      `gorm:"not null" json:"referrer_id"`
-	ReferredID    uint      `gorm:"not null" json:"referred_id"`
-	RewardClaimed bool      `gorm:"default:false" json:"reward_claimed"`
+	ReferrerID    uint      `gorm:"not null;index" json:"referrer_id"`
+	ReferredID    uint      `gorm:"not null;uniqueIndex" json:"referred_id"` // A player can only be referred once
+	RewardClaimed bool      `gorm:"default:false" json:"reward_claimed"`     // All milestones paid out
+	Status        string    `gorm:"size:20;default:'ACTIVE';index" json:"status"`
+	FlagReason    string    `gorm:"size:100" json:"flag_reason,omitempty"`
 	CreatedAt     time.Time `json:"created_at"`
 
 	Referrer User `gorm:"foreignKey:ReferrerID" json:"referrer,omitempty"`
 	Referred User `gorm:"foreignKey:ReferredID" json:"referred,omitempty"`
 }
 
+// Referral reward statuses
+const (
+	ReferralRewardPending = "PENDING" // Milestone reached, waiting to be claimed
+	ReferralRewardHeld    = "HELD"    // Milestone reached on a flagged referral
+	ReferralRewardPaid    = "PAID"
+)
+
+// ReferralReward is one milestone payout owed to a referrer
+type ReferralReward struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ReferralID uint       `gorm:"not null;uniqueIndex:idx_referral_milestone" json:"referral_id"`
+	ReferrerID uint       `gorm:"not null;index" json:"referrer_id"`
+	Milestone  string     `gorm:"size:50;not null;uniqueIndex:idx_referral_milestone" json:"milestone"` // e.g. level_10, ranked_25
+	Amount     int64      `gorm:"not null" json:"amount"`
+	Status     string     `gorm:"size:20;default:'PENDING';index" json:"status"` // PENDING, HELD, PAID
+	PaidAt     *time.Time `json:"paid_at,omitempty"`
+}
+
 // BattleReplay stores battle data for replay
 type BattleReplay struct {
 	ID            uint      `gorm:"primaryKey" json:"id"`
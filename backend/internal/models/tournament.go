@@ -0,0 +1,118 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Tournament formats
+const (
+	TournamentFormatSingleElim = "SINGLE_ELIMINATION"
+	TournamentFormatDoubleElim = "DOUBLE_ELIMINATION"
+	TournamentFormatSwiss      = "SWISS"
+)
+
+// Tournament statuses
+const (
+	TournamentStatusRegistration = "REGISTRATION"
+	TournamentStatusInProgress   = "IN_PROGRESS"
+	TournamentStatusCompleted    = "COMPLETED"
+	TournamentStatusCancelled    = "CANCELLED"
+)
+
+// Tournament match statuses
+const (
+	TournamentMatchPending   = "PENDING"   // Paired, battle not created yet
+	TournamentMatchScheduled = "SCHEDULED" // Battle row created, waiting for result
+	TournamentMatchCompleted = "COMPLETED" // Decided by a finished battle
+	TournamentMatchWalkover  = "WALKOVER"  // Decided by timeout or missing team
+	TournamentMatchBye       = "BYE"       // Free win, no opponent
+)
+
+// Tournament is a structured competition with a registration window and a prize pool
+type Tournament struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	Name        string `gorm:"size:100;not null" json:"name"`
+	Description string `gorm:"type:text" json:"description"`
+	Format      string `gorm:"type:varchar(30);not null" json:"format"`                              // SINGLE_ELIMINATION, DOUBLE_ELIMINATION, SWISS
+	Status      string `gorm:"type:varchar(20);not null;index;default:'REGISTRATION'" json:"status"` // REGISTRATION, IN_PROGRESS, COMPLETED, CANCELLED
+
+	// Registration
+	EntryFee             int64     `gorm:"default:0;not null" json:"entry_fee"` // GTK, held in ESCROW until payout
+	MinPlayers           int       `gorm:"default:4;not null" json:"min_players"`
+	MaxPlayers           int       `gorm:"default:16;not null" json:"max_players"`
+	RegistrationOpensAt  time.Time `json:"registration_opens_at"`
+	RegistrationClosesAt time.Time `gorm:"index" json:"registration_closes_at"`
+
+	// Scheduling
+	MatchTimeoutMinutes int `gorm:"default:30;not null" json:"match_timeout_minutes"` // Walkover after this long
+	SwissRounds         int `gorm:"default:0" json:"swiss_rounds"`                    // 0 = ceil(log2(players))
+	CurrentRound        int `gorm:"default:0" json:"current_round"`
+
+	// Prizes
+	PrizePool   int64  `gorm:"default:0;not null" json:"prize_pool"`
+	PayoutTable string `gorm:"type:text;not null" json:"payout_table"` // JSON array of percentages by placement, e.g. [50,30,20]
+
+	WinnerID    *uint      `gorm:"index" json:"winner_id,omitempty"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	CreatedBy   uint       `json:"created_by"`
+
+	Participants []TournamentParticipant `gorm:"foreignKey:TournamentID" json:"participants,omitempty"`
+	Matches      []TournamentMatch       `gorm:"foreignKey:TournamentID" json:"matches,omitempty"`
+}
+
+// TournamentParticipant is a registered player and their standing
+type TournamentParticipant struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	TournamentID uint      `gorm:"not null;uniqueIndex:idx_tournament_user" json:"tournament_id"`
+	UserID       uint      `gorm:"not null;uniqueIndex:idx_tournament_user;index" json:"user_id"`
+	User         User      `gorm:"foreignKey:UserID" json:"-"`
+
+	Seed       int   `gorm:"default:0" json:"seed"`   // 1 = top seed, assigned from rating at start
+	Rating     int   `gorm:"default:0" json:"rating"` // ELO snapshot at registration
+	EntryPaid  int64 `gorm:"default:0" json:"entry_paid"`
+	Wins       int   `gorm:"default:0" json:"wins"`
+	Losses     int   `gorm:"default:0" json:"losses"`
+	Points     int   `gorm:"default:0" json:"points"` // Swiss: 1 per win (byes included)
+	HadBye     bool  `gorm:"default:false" json:"had_bye"`
+	Eliminated bool  `gorm:"default:false" json:"eliminated"`
+
+	EliminatedRound int   `gorm:"default:0" json:"eliminated_round"`
+	FinalPlacement  int   `gorm:"default:0" json:"final_placement"`
+	PrizeAwarded    int64 `gorm:"default:0" json:"prize_awarded"`
+}
+
+// TournamentMatch is a single pairing inside a round
+type TournamentMatch struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	TournamentID uint      `gorm:"not null;index" json:"tournament_id"`
+
+	Round       int    `gorm:"not null" json:"round"`
+	Bracket     string `gorm:"type:varchar(20);default:'MAIN'" json:"bracket"` // MAIN, WINNERS, LOSERS, GRAND_FINAL, SWISS
+	MatchNumber int    `gorm:"not null" json:"match_number"`                   // Order within the round, drives next-round pairing
+
+	Player1ID *uint   `json:"player1_id"`
+	Player2ID *uint   `json:"player2_id"`
+	BattleID  *uint   `gorm:"index" json:"battle_id,omitempty"`
+	Battle    *Battle `gorm:"foreignKey:BattleID" json:"-"`
+	WinnerID  *uint   `json:"winner_id,omitempty"`
+	Status    string  `gorm:"type:varchar(20);not null;default:'PENDING';index" json:"status"`
+
+	DeadlineAt  *time.Time `json:"deadline_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// IsDecided reports whether the match already has a winner
+func (m *TournamentMatch) IsDecided() bool {
+	return m.Status == TournamentMatchCompleted || m.Status == TournamentMatchWalkover || m.Status == TournamentMatchBye
+}
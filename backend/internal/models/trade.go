@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// Trade statuses
+const (
+	TradeStatusOpen      = "OPEN"      // Sides are still putting their offers together
+	TradeStatusCompleted = "COMPLETED" // Both confirmed; everything changed hands at once
+	TradeStatusCancelled = "CANCELLED"
+	TradeStatusExpired   = "EXPIRED"
+)
+
+// Trade is a direct two-sided swap between players. Each side offers characters, items,
+// eggs and GTK; changing either offer clears both confirmations, and the trade settles
+// atomically once both sides have confirmed the current offers.
+type Trade struct {
+	ID                    uint         `gorm:"primaryKey" json:"id"`
+	CreatedAt             time.Time    `json:"created_at"`
+	UpdatedAt             time.Time    `json:"updated_at"`
+	InitiatorID           uint         `gorm:"not null;index" json:"initiator_id"`
+	CounterpartyID        uint         `gorm:"not null;index" json:"counterparty_id"`
+	InitiatorGTK          int64        `gorm:"not null;default:0" json:"initiator_gtk"`
+	CounterpartyGTK       int64        `gorm:"not null;default:0" json:"counterparty_gtk"`
+	InitiatorConfirmed    bool         `gorm:"default:false" json:"initiator_confirmed"`
+	CounterpartyConfirmed bool         `gorm:"default:false" json:"counterparty_confirmed"`
+	Status                string       `gorm:"size:20;not null;default:'OPEN';index" json:"status"`
+	Fee                   int64        `gorm:"not null;default:0" json:"fee"` // Paid to the treasury by both sides together
+	ExpiresAt             time.Time    `gorm:"not null" json:"expires_at"`
+	CompletedAt           *time.Time   `json:"completed_at,omitempty"`
+	Assets                []TradeAsset `gorm:"foreignKey:TradeID" json:"assets"`
+}
+
+// TradeAsset is one character, item or egg a side puts into a trade
+type TradeAsset struct {
+	ID         uint   `gorm:"primaryKey" json:"id"`
+	TradeID    uint   `gorm:"not null;index" json:"trade_id"`
+	FromUserID uint   `gorm:"not null" json:"from_user_id"`
+	AssetType  string `gorm:"size:20;not null" json:"asset_type"` // character, item, egg
+	AssetID    uint   `gorm:"not null" json:"asset_id"`
+}
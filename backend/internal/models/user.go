@@ -23,8 +23,21 @@ type User struct {
 	// Wallet & Authentication
 	WalletAddress string     `gorm:"uniqueIndex;not null" json:"wallet_address"`
 	Nonce         string     `gorm:"not null" json:"-"` // For signature verification
+	NonceIssuedAt *time.Time `json:"-"`                 // SIWE Issued At of the pending nonce
 	LastLoginAt   *time.Time `json:"last_login_at"`
 
+	// Social
+	DisplayName string     `gorm:"size:32;index" json:"display_name"`
+	LastSeenAt  *time.Time `json:"last_seen_at"` // Refreshed by AuthMiddleware; drives online presence
+
+	// Timezone the daily login calendar counts days in (IANA name)
+	Timezone          string     `gorm:"size:64;default:'UTC';not null" json:"timezone"`
+	TimezoneChangedAt *time.Time `json:"-"`
+
+	// Referrals
+	ReferralCode *string `gorm:"size:20;uniqueIndex" json:"referral_code,omitempty"`
+	ReferredBy   *uint   `json:"referred_by,omitempty"`
+
 	// Player Stats
 	Level      int    `gorm:"default:1;not null" json:"level"`
 	Experience int    `gorm:"default:0;not null" json:"experience"`
@@ -56,3 +69,22 @@ type User struct {
 	LastKnownIP string `gorm:"type:varchar(45)" json:"-"` // For anti-cheat
 	IsBanned    bool   `gorm:"default:false" json:"-"`
 }
+
+// UserSession is a server-side login session backing a rotating refresh token.
+// Access tokens carry the session ID, so revoking the session logs the device out immediately.
+type UserSession struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	UserID           uint   `gorm:"not null;index" json:"user_id"`
+	RefreshTokenHash string `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"` // SHA-256 of the current refresh token
+	PrevTokenHash    string `gorm:"type:varchar(64);index" json:"-"`                // Last rotated-out token, for reuse detection
+
+	UserAgent  string     `gorm:"type:varchar(255)" json:"user_agent"`
+	IPAddress  string     `gorm:"type:varchar(45)" json:"ip_address"`
+	LastUsedAt time.Time  `json:"last_used_at"`
+	ExpiresAt  time.Time  `gorm:"index" json:"expires_at"`
+	RevokedAt  *time.Time `gorm:"index" json:"revoked_at,omitempty"`
+	RevokedBy  string     `gorm:"type:varchar(30)" json:"revoked_by,omitempty"` // LOGOUT, LOGOUT_ALL, BANNED, TOKEN_REUSE
+}
@@ -0,0 +1,332 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// gormStore implements Store on a *gorm.DB (the connection pool or an open transaction)
+type gormStore struct {
+	db *gorm.DB
+}
+
+// NewGormStore wraps a GORM handle. Passing a transaction handle lets legacy code that
+// still works with *gorm.DB share its transaction with repository-based services.
+func NewGormStore(db *gorm.DB) Store {
+	return &gormStore{db: db}
+}
+
+func (s *gormStore) Characters() CharacterRepository {
+	return gormCharacters{gormAssets{s.db, "characters"}}
+}
+func (s *gormStore) Items() ItemRepository               { return gormItems{gormAssets{s.db, "items"}} }
+func (s *gormStore) Eggs() EggRepository                 { return gormEggs{s.db} }
+func (s *gormStore) Users() UserRepository               { return gormUsers{s.db} }
+func (s *gormStore) Inventory() InventoryRepository      { return gormInventory{s.db} }
+func (s *gormStore) Battles() BattleRepository           { return gormBattles{s.db} }
+func (s *gormStore) Ledger() LedgerRepository            { return gormLedger{s.db} }
+func (s *gormStore) Listings() ListingRepository         { return gormListings{s.db} }
+func (s *gormStore) Crafting() CraftingRepository        { return gormCrafting{s.db} }
+func (s *gormStore) Equipment() EquipmentRepository      { return gormEquipment{s.db} }
+func (s *gormStore) Provenance() ProvenanceRepository    { return gormProvenance{s.db} }
+func (s *gormStore) Rentals() RentalRepository           { return gormRentals{s.db} }
+func (s *gormStore) Trades() TradeRepository             { return gormTrades{s.db} }
+func (s *gormStore) LoginRewards() LoginRewardRepository { return gormLoginRewards{s.db} }
+func (s *gormStore) Withdrawals() WithdrawalRepository   { return gormWithdrawals{s.db} }
+
+func (s *gormStore) WithContext(ctx context.Context) Store {
+	return &gormStore{db: s.db.WithContext(ctx)}
+}
+
+func (s *gormStore) Transaction(fn func(tx Store) error) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&gormStore{db: tx})
+	})
+}
+
+// forUpdate adds a row lock to a query
+func forUpdate(db *gorm.DB) *gorm.DB {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"})
+}
+
+// notFound maps GORM's sentinel to ErrNotFound
+func notFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// affected turns a conditional UPDATE that matched nothing into ErrNotFound
+func affected(res *gorm.DB) error {
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// first loads one row into dest and maps not-found
+func first[T any](db *gorm.DB, conds ...interface{}) (*T, error) {
+	var out T
+	if err := db.First(&out, conds...).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &out, nil
+}
+
+// gormAssets implements AssetRepository for the characters and items tables
+type gormAssets struct {
+	db    *gorm.DB
+	table string
+}
+
+func (r gormAssets) Owner(id uint) (uint, error) {
+	var owner struct{ OwnerID uint }
+	res := r.db.Table(r.table).Select("owner_id").Where("id = ? AND deleted_at IS NULL", id).Scan(&owner)
+	if err := affected(res); err != nil {
+		return 0, err
+	}
+	return owner.OwnerID, nil
+}
+
+func (r gormAssets) MarkListed(id, ownerID uint, at time.Time) error {
+	query := "UPDATE " + r.table + " SET is_listed = true, listed_at = ? WHERE id = ? AND owner_id = ? AND is_listed = false"
+	if r.table == "items" {
+		query += " AND is_equipped = false"
+	}
+	if r.table == "characters" {
+		query += " AND controller_id IS NULL"
+	}
+	return affected(r.db.Exec(query, at, id, ownerID))
+}
+
+func (r gormAssets) SetListed(id uint, listed bool) error {
+	return r.db.Exec("UPDATE "+r.table+" SET is_listed = ? WHERE id = ?", listed, id).Error
+}
+
+func (r gormAssets) Transfer(id, fromUserID, toUserID uint) error {
+	query := "UPDATE " + r.table + " SET owner_id = ?, is_listed = false WHERE id = ? AND owner_id = ?"
+	if r.table == "characters" {
+		query += " AND controller_id IS NULL"
+	}
+	return affected(r.db.Exec(query, toUserID, id, fromUserID))
+}
+
+type gormCharacters struct{ gormAssets }
+
+func (r gormCharacters) Get(id uint) (*models.Character, error) {
+	return first[models.Character](r.db, id)
+}
+
+func (r gormCharacters) Lock(id uint) (*models.Character, error) {
+	return first[models.Character](forUpdate(r.db), id)
+}
+
+func (r gormCharacters) Create(c *models.Character) error { return r.db.Create(c).Error }
+func (r gormCharacters) Save(c *models.Character) error   { return r.db.Save(c).Error }
+
+func (r gormCharacters) FirstAlive(ownerID uint) (*models.Character, error) {
+	return first[models.Character](r.db.Where("owner_id = ? AND is_fainted = false", ownerID))
+}
+
+func (r gormCharacters) CountAlive(ownerID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Character{}).Where("owner_id = ? AND is_fainted = false", ownerID).Count(&count).Error
+	return count, err
+}
+
+func (r gormCharacters) ActiveTeam(userID uint) ([]models.Character, error) {
+	team, err := first[models.Team](r.db.
+		Preload("Members", func(db *gorm.DB) *gorm.DB { return db.Order("slot") }).
+		Preload("Members.Character").
+		Where("user_id = ? AND is_active = true", userID))
+	if err != nil {
+		return nil, err
+	}
+	var chars []models.Character
+	for _, m := range team.Members {
+		if m.Character.ID != 0 { // Deleted characters leave an empty preload
+			chars = append(chars, m.Character)
+		}
+	}
+	return chars, nil
+}
+
+func (r gormCharacters) RemoveFromTeams(characterID, userID uint) error {
+	teams := r.db.Model(&models.Team{}).Select("id").Where("user_id = ?", userID)
+	return r.db.Where("character_id = ? AND team_id IN (?)", characterID, teams).Delete(&models.TeamMember{}).Error
+}
+
+type gormItems struct{ gormAssets }
+
+func (r gormItems) Get(id uint) (*models.Item, error) { return first[models.Item](r.db, id) }
+func (r gormItems) Create(item *models.Item) error    { return r.db.Create(item).Error }
+func (r gormItems) Save(item *models.Item) error      { return r.db.Save(item).Error }
+func (r gormItems) Delete(id uint) error              { return r.db.Delete(&models.Item{}, id).Error }
+
+func (r gormItems) Lock(id uint) (*models.Item, error) {
+	return first[models.Item](forUpdate(r.db), id)
+}
+
+func (r gormItems) LockMaterials(ownerID uint, name string) ([]models.Item, error) {
+	var items []models.Item
+	err := forUpdate(r.db).
+		Where("owner_id = ? AND item_type = 'MATERIAL' AND name = ? AND is_listed = false", ownerID, name).
+		Order("id").Find(&items).Error
+	return items, err
+}
+
+type gormEggs struct{ db *gorm.DB }
+
+func (r gormEggs) Get(id uint) (*models.Egg, error) { return first[models.Egg](r.db, id) }
+func (r gormEggs) Create(egg *models.Egg) error     { return r.db.Create(egg).Error }
+func (r gormEggs) Save(egg *models.Egg) error       { return r.db.Save(egg).Error }
+
+func (r gormEggs) Lock(id uint) (*models.Egg, error) {
+	return first[models.Egg](forUpdate(r.db), id)
+}
+
+func (r gormEggs) ListUnhatched(userID uint) ([]models.Egg, error) {
+	var eggs []models.Egg
+	err := r.db.Preload("Parent1").Preload("Parent2").
+		Where("user_id = ? AND hatched_at IS NULL", userID).
+		Order("created_at DESC").Find(&eggs).Error
+	return eggs, err
+}
+
+func (r gormEggs) HatchedInto(characterID uint) (*models.Egg, error) {
+	return first[models.Egg](r.db.Where("character_id = ?", characterID))
+}
+
+func (r gormEggs) Transfer(id, fromUserID, toUserID uint) error {
+	return affected(r.db.Exec("UPDATE eggs SET user_id = ? WHERE id = ? AND user_id = ?", toUserID, id, fromUserID))
+}
+
+func (r gormEggs) Care(eggID uint) ([]models.EggCare, error) {
+	var care []models.EggCare
+	err := r.db.Where("egg_id = ?", eggID).Order("id").Find(&care).Error
+	return care, err
+}
+
+func (r gormEggs) AddCare(c *models.EggCare) error { return r.db.Create(c).Error }
+
+type gormUsers struct{ db *gorm.DB }
+
+func (r gormUsers) Get(id uint) (*models.User, error) { return first[models.User](r.db, id) }
+func (r gormUsers) Create(u *models.User) error       { return r.db.Create(u).Error }
+func (r gormUsers) Save(u *models.User) error         { return r.db.Save(u).Error }
+
+func (r gormUsers) Lock(id uint) (*models.User, error) {
+	return first[models.User](forUpdate(r.db), id)
+}
+
+func (r gormUsers) RandomOpponent(excludeID uint, minELO, maxELO int) (*models.User, error) {
+	return first[models.User](r.db.
+		Where("id != ? AND elo_rating BETWEEN ? AND ?", excludeID, minELO, maxELO).
+		Order("RANDOM()"))
+}
+
+func (r gormUsers) AdjustLegacyTokens(id uint, delta int64) error {
+	return r.db.Exec("UPDATE users SET tokens = tokens + ? WHERE id = ?", delta, id).Error
+}
+
+type gormInventory struct{ db *gorm.DB }
+
+func (r gormInventory) Find(userID, shopItemID uint) (*models.UserInventory, error) {
+	return first[models.UserInventory](r.db.Where("user_id = ? AND item_id = ?", userID, shopItemID))
+}
+
+func (r gormInventory) Save(inv *models.UserInventory) error   { return r.db.Save(inv).Error }
+func (r gormInventory) Delete(inv *models.UserInventory) error { return r.db.Delete(inv).Error }
+
+func (r gormInventory) ShopItem(id uint) (*models.ShopItem, error) {
+	return first[models.ShopItem](r.db, id)
+}
+
+type gormBattles struct{ db *gorm.DB }
+
+func (r gormBattles) Get(id uint) (*models.Battle, error) {
+	return first[models.Battle](r.db.Preload("Player1").Preload("Player2"), id)
+}
+
+func (r gormBattles) Create(b *models.Battle) error { return r.db.Create(b).Error }
+func (r gormBattles) Save(b *models.Battle) error   { return r.db.Save(b).Error }
+func (r gormBattles) Delete(b *models.Battle) error { return r.db.Delete(b).Error }
+
+func (r gormBattles) FindInProgress(userID uint) (*models.Battle, error) {
+	return first[models.Battle](r.db.Where("(player1_id = ? OR player2_id = ?) AND status IN ?", userID, userID, []string{"SEARCHING", "active"}))
+}
+
+func (r gormBattles) LockOpenWager(userID uint) (*models.Battle, error) {
+	return first[models.Battle](forUpdate(r.db).
+		Where("battle_type = ? AND status = ? AND player1_id != ?", "wager", "SEARCHING", userID).
+		Order("created_at"))
+}
+
+func (r gormBattles) LockWagerSearch(userID uint) (*models.Battle, error) {
+	return first[models.Battle](forUpdate(r.db).
+		Where("player1_id = ? AND status = ? AND battle_type = ?", userID, "SEARCHING", "wager"))
+}
+
+func (r gormBattles) ListStale(before time.Time) ([]models.Battle, error) {
+	var battles []models.Battle
+	err := r.db.Where("status = ? AND updated_at < ?", "active", before).Find(&battles).Error
+	return battles, err
+}
+
+func (r gormBattles) History(userID uint, limit int) ([]models.Battle, error) {
+	var battles []models.Battle
+	err := r.db.Where("(player1_id = ? OR player2_id = ?) AND status = ?", userID, userID, "completed").
+		Order("created_at desc").
+		Limit(limit).
+		Find(&battles).Error
+	return battles, err
+}
+
+type gormLedger struct{ db *gorm.DB }
+
+func (r gormLedger) FindAccount(userID *uint, accType models.AccountType, currency string) (*models.LedgerAccount, error) {
+	query := r.db.Where("type = ? AND currency = ?", accType, currency)
+	if userID != nil {
+		query = query.Where("user_id = ?", *userID)
+	} else {
+		query = query.Where("user_id IS NULL")
+	}
+	return first[models.LedgerAccount](query)
+}
+
+func (r gormLedger) FindGuildAccount(guildID uint, currency string) (*models.LedgerAccount, error) {
+	return first[models.LedgerAccount](r.db.Where("type = ? AND currency = ? AND guild_id = ?", models.AccountTypeGuildTreasury, currency, guildID))
+}
+
+func (r gormLedger) CreateAccount(acc *models.LedgerAccount) error { return r.db.Create(acc).Error }
+
+func (r gormLedger) LockAccount(id uint) (*models.LedgerAccount, error) {
+	return first[models.LedgerAccount](forUpdate(r.db), id)
+}
+
+func (r gormLedger) SaveAccount(acc *models.LedgerAccount) error { return r.db.Save(acc).Error }
+
+func (r gormLedger) CreateTransaction(tx *models.LedgerTransaction) error {
+	return r.db.Create(tx).Error
+}
+
+func (r gormLedger) CreateEntry(e *models.LedgerEntry) error { return r.db.Create(e).Error }
+
+type gormProvenance struct{ db *gorm.DB }
+
+func (r gormProvenance) Add(e *models.CharacterProvenance) error { return r.db.Create(e).Error }
+
+func (r gormProvenance) List(characterID uint) ([]models.CharacterProvenance, error) {
+	var out []models.CharacterProvenance
+	err := r.db.Where("character_id = ?", characterID).Order("id").Find(&out).Error
+	return out, err
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"gorm.io/gorm"
+)
+
+type gormCrafting struct{ db *gorm.DB }
+
+func (r gormCrafting) Recipe(id uint) (*models.CraftingRecipe, error) {
+	return first[models.CraftingRecipe](r.db, id)
+}
+
+func (r gormCrafting) ListRecipes() ([]models.CraftingRecipe, error) {
+	var recipes []models.CraftingRecipe
+	err := r.db.Where("is_active = true").Order("name").Find(&recipes).Error
+	return recipes, err
+}
+
+func (r gormCrafting) SalvageYield(itemType string) (*models.SalvageYield, error) {
+	return first[models.SalvageYield](r.db.Where("item_type = ?", itemType))
+}
+
+func (r gormCrafting) CreateJob(j *models.CraftingJob) error {
+	return r.db.Omit("Recipe").Create(j).Error
+}
+func (r gormCrafting) SaveJob(j *models.CraftingJob) error { return r.db.Omit("Recipe").Save(j).Error }
+
+func (r gormCrafting) LockJob(id uint) (*models.CraftingJob, error) {
+	return first[models.CraftingJob](forUpdate(r.db), id)
+}
+
+func (r gormCrafting) CountActiveJobs(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.CraftingJob{}).
+		Where("user_id = ? AND status = ?", userID, models.CraftingStatusCrafting).
+		Count(&count).Error
+	return count, err
+}
+
+func (r gormCrafting) ListJobs(userID uint, limit int) ([]models.CraftingJob, error) {
+	var jobs []models.CraftingJob
+	err := r.db.Preload("Recipe").Where("user_id = ?", userID).
+		Order("created_at DESC").Limit(limit).Find(&jobs).Error
+	return jobs, err
+}
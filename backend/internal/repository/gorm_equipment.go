@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"gorm.io/gorm"
+)
+
+type gormEquipment struct{ db *gorm.DB }
+
+func (r gormEquipment) Lock(id uint) (*models.Equipment, error) {
+	return first[models.Equipment](forUpdate(r.db), id)
+}
+
+func (r gormEquipment) LockForItem(itemID uint) (*models.Equipment, error) {
+	return first[models.Equipment](forUpdate(r.db).Where("item_id = ?", itemID))
+}
+
+func (r gormEquipment) Create(e *models.Equipment) error {
+	return r.db.Omit("Item", "Runes").Create(e).Error
+}
+
+func (r gormEquipment) Save(e *models.Equipment) error {
+	return r.db.Omit("Item", "Runes").Save(e).Error
+}
+
+func (r gormEquipment) LockLoadout(characterID uint) (*models.CharacterEquipment, error) {
+	return first[models.CharacterEquipment](forUpdate(r.db).Where("character_id = ?", characterID))
+}
+
+func (r gormEquipment) SaveLoadout(l *models.CharacterEquipment) error {
+	return r.db.Omit("Character", "Weapon", "Armor", "Accessory").Save(l).Error
+}
+
+func (r gormEquipment) Gear(characterID uint) ([]models.Equipment, error) {
+	loadout, err := first[models.CharacterEquipment](r.db.Where("character_id = ?", characterID))
+	if errors.Is(err, ErrNotFound) {
+		return []models.Equipment{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var ids []uint
+	for _, id := range []*uint{loadout.WeaponID, loadout.ArmorID, loadout.AccessoryID} {
+		if id != nil {
+			ids = append(ids, *id)
+		}
+	}
+	gear := []models.Equipment{}
+	if len(ids) == 0 {
+		return gear, nil
+	}
+	err = r.db.Preload("Item").Preload("Runes.RuneItem").Where("id IN ?", ids).Order("id").Find(&gear).Error
+	return gear, err
+}
+
+func (r gormEquipment) Runes(equipmentID uint) ([]models.EquipmentRune, error) {
+	var runes []models.EquipmentRune
+	err := r.db.Preload("RuneItem").Where("equipment_id = ?", equipmentID).Order("id").Find(&runes).Error
+	return runes, err
+}
+
+func (r gormEquipment) RuneByItem(runeItemID uint) (*models.EquipmentRune, error) {
+	return first[models.EquipmentRune](r.db.Where("rune_item_id = ?", runeItemID))
+}
+
+func (r gormEquipment) AddRune(er *models.EquipmentRune) error {
+	return r.db.Omit("RuneItem").Create(er).Error
+}
+
+func (r gormEquipment) RemoveRune(id uint) error {
+	return r.db.Delete(&models.EquipmentRune{}, id).Error
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"gorm.io/gorm"
+)
+
+type gormListings struct{ db *gorm.DB }
+
+func (r gormListings) Get(id uint) (*models.MarketplaceListing, error) {
+	return first[models.MarketplaceListing](r.db, id)
+}
+
+func (r gormListings) Lock(id uint) (*models.MarketplaceListing, error) {
+	return first[models.MarketplaceListing](forUpdate(r.db), id)
+}
+
+func (r gormListings) Create(l *models.MarketplaceListing) error { return r.db.Create(l).Error }
+func (r gormListings) Save(l *models.MarketplaceListing) error   { return r.db.Save(l).Error }
+
+func (r gormListings) ActiveForAsset(assetType string, assetID uint) ([]models.MarketplaceListing, error) {
+	column := "item_id"
+	if assetType == "character" {
+		column = "character_id"
+	}
+	var listings []models.MarketplaceListing
+	err := forUpdate(r.db).Where("status = ? AND "+column+" = ?", "ACTIVE", assetID).Find(&listings).Error
+	return listings, err
+}
+
+func (r gormListings) ListActive(f ListingFilter) ([]models.MarketplaceListing, error) {
+	unscoped := func(db *gorm.DB) *gorm.DB { return db.Unscoped() }
+	query := r.db.Preload("Character", unscoped).Preload("Item").Preload("BundleItems").Preload("Seller", unscoped).
+		Where("status = ? AND expires_at > ?", "ACTIVE", time.Now())
+	if f.AssetType != "" {
+		query = query.Where("asset_type = ?", f.AssetType)
+	}
+	if f.ListingType != "" {
+		query = query.Where("listing_type = ?", f.ListingType)
+	}
+
+	var listings []models.MarketplaceListing
+	if err := query.Limit(f.Limit).Offset(f.Offset).Order("created_at DESC").Find(&listings).Error; err != nil {
+		return nil, err
+	}
+
+	// Unscoped preloads still miss rows in some GORM versions; recover soft-deleted
+	// characters and sellers by hand so listings never show 'Unknown' assets
+	for i := range listings {
+		l := &listings[i]
+		if (l.AssetType == "character" || l.AssetType == "Character") && l.Character == nil && l.CharacterID != nil {
+			var char models.Character
+			if err := r.db.Unscoped().First(&char, *l.CharacterID).Error; err == nil {
+				l.Character = &char
+				slog.Debug("recovered soft-deleted character for listing", "listing_id", l.ID, "character_id", char.ID)
+			} else {
+				slog.Warn("failed to recover character for listing", "listing_id", l.ID, "character_id", *l.CharacterID, "error", err)
+			}
+		}
+		if l.Seller.ID == 0 && l.SellerID > 0 {
+			var seller models.User
+			if err := r.db.Unscoped().First(&seller, l.SellerID).Error; err == nil {
+				l.Seller = seller
+			}
+		}
+	}
+	return listings, nil
+}
+
+func (r gormListings) ListExpired(now time.Time) ([]models.MarketplaceListing, error) {
+	var listings []models.MarketplaceListing
+	err := r.db.Where("status = ? AND expires_at <= ?", "ACTIVE", now).Find(&listings).Error
+	return listings, err
+}
+
+func (r gormListings) AddBundleAsset(a *models.MarketplaceAsset) error { return r.db.Create(a).Error }
+
+func (r gormListings) BundleAssets(listingID uint) ([]models.MarketplaceAsset, error) {
+	var assets []models.MarketplaceAsset
+	err := r.db.Where("listing_id = ?", listingID).Find(&assets).Error
+	return assets, err
+}
+
+func (r gormListings) InActiveBundle(assetTypes []string, assetID uint) (bool, error) {
+	var count int64
+	err := r.db.Table("marketplace_assets").
+		Joins("JOIN marketplace_listings ON marketplace_listings.id = marketplace_assets.listing_id").
+		Where("marketplace_listings.status = ? AND marketplace_assets.asset_type IN ? AND marketplace_assets.asset_id = ?",
+			"ACTIVE", assetTypes, assetID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r gormListings) CreateBid(b *models.MarketplaceBid) error { return r.db.Create(b).Error }
+
+func (r gormListings) SetBidStatus(listingID uint, from, to string) error {
+	return r.db.Model(&models.MarketplaceBid{}).
+		Where("listing_id = ? AND status = ?", listingID, from).
+		Update("status", to).Error
+}
+
+func (r gormListings) Bids(listingID uint, limit int) ([]models.MarketplaceBid, error) {
+	var bids []models.MarketplaceBid
+	err := r.db.Where("listing_id = ?", listingID).Order("amount DESC").Limit(limit).Find(&bids).Error
+	return bids, err
+}
+
+func (r gormListings) LockOffer(id uint) (*models.MarketplaceOffer, error) {
+	return first[models.MarketplaceOffer](forUpdate(r.db), id)
+}
+
+func (r gormListings) CreateOffer(o *models.MarketplaceOffer) error { return r.db.Create(o).Error }
+func (r gormListings) SaveOffer(o *models.MarketplaceOffer) error   { return r.db.Save(o).Error }
+
+func (r gormListings) LockPendingOffers(assetType string, assetID, excludeID uint) ([]models.MarketplaceOffer, error) {
+	var offers []models.MarketplaceOffer
+	err := forUpdate(r.db).
+		Where("asset_type = ? AND asset_id = ? AND status = ? AND id != ?", assetType, assetID, "PENDING", excludeID).
+		Find(&offers).Error
+	return offers, err
+}
+
+func (r gormListings) CountPendingOffers(buyerID uint, assetType string, assetID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.MarketplaceOffer{}).
+		Where("buyer_id = ? AND asset_type = ? AND asset_id = ? AND status = ?", buyerID, assetType, assetID, "PENDING").
+		Count(&count).Error
+	return count, err
+}
+
+func (r gormListings) ListOffers(userID uint, sent bool, status string) ([]models.MarketplaceOffer, error) {
+	query := r.db.Preload("Buyer")
+	if sent {
+		query = query.Where("buyer_id = ?", userID)
+	} else {
+		query = query.Where("seller_id = ?", userID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var offers []models.MarketplaceOffer
+	err := query.Order("created_at DESC").Limit(100).Find(&offers).Error
+	return offers, err
+}
+
+func (r gormListings) ListExpiredOffers(now time.Time) ([]models.MarketplaceOffer, error) {
+	var offers []models.MarketplaceOffer
+	err := r.db.Where("status = ? AND expires_at <= ?", "PENDING", now).Find(&offers).Error
+	return offers, err
+}
+
+func (r gormListings) CreateTrade(t *models.TradeHistory) error { return r.db.Create(t).Error }
+
+func (r gormListings) TradedSince(assetType string, assetID uint, since time.Time) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.TradeHistory{}).
+		Where("trade_id IS NOT NULL AND item_type = ? AND item_id = ? AND completed_at >= ?", assetType, assetID, since).
+		Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormLoginRewards struct{ db *gorm.DB }
+
+func (r gormLoginRewards) Calendar() ([]models.LoginCalendarDay, error) {
+	var days []models.LoginCalendarDay
+	err := r.db.Preload("Item").Order("day_number").Find(&days).Error
+	return days, err
+}
+
+func (r gormLoginRewards) CalendarDay(dayNumber int) (*models.LoginCalendarDay, error) {
+	return first[models.LoginCalendarDay](r.db.Preload("Item").Where("day_number = ?", dayNumber))
+}
+
+func (r gormLoginRewards) SaveCalendarDay(d *models.LoginCalendarDay) error {
+	return r.db.Omit("Item").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "day_number"}},
+		DoUpdates: clause.AssignmentColumns([]string{"updated_at", "reward_gtk", "item_id", "item_quantity", "is_milestone", "updated_by"}),
+	}).Create(d).Error
+}
+
+func (r gormLoginRewards) LastClaim(userID uint) (*models.LoginReward, error) {
+	return first[models.LoginReward](r.db.Where("user_id = ?", userID).Order("claim_date DESC"))
+}
+
+func (r gormLoginRewards) CreateClaim(reward *models.LoginReward) error {
+	res := r.db.Omit("User").Clauses(clause.OnConflict{DoNothing: true}).Create(reward)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrDuplicate
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"gorm.io/gorm"
+)
+
+type gormRentals struct{ db *gorm.DB }
+
+func (r gormRentals) Get(id uint) (*models.Rental, error) { return first[models.Rental](r.db, id) }
+
+func (r gormRentals) Lock(id uint) (*models.Rental, error) {
+	return first[models.Rental](forUpdate(r.db), id)
+}
+
+func (r gormRentals) Create(rental *models.Rental) error {
+	return r.db.Omit("Character").Create(rental).Error
+}
+
+func (r gormRentals) Save(rental *models.Rental) error {
+	return r.db.Omit("Character").Save(rental).Error
+}
+
+func (r gormRentals) Open(characterID uint) (*models.Rental, error) {
+	return first[models.Rental](r.db.Where("character_id = ? AND status IN ?", characterID,
+		[]string{models.RentalStatusOffered, models.RentalStatusActive}))
+}
+
+func (r gormRentals) ListOffers(userID uint) ([]models.Rental, error) {
+	var rentals []models.Rental
+	err := r.db.Preload("Character").
+		Where("status = ? AND owner_id <> ? AND (borrower_id IS NULL OR borrower_id = ?)", models.RentalStatusOffered, userID, userID).
+		Order("created_at DESC").Find(&rentals).Error
+	return rentals, err
+}
+
+func (r gormRentals) ListForUser(userID uint) ([]models.Rental, error) {
+	var rentals []models.Rental
+	err := r.db.Preload("Character").Where("owner_id = ? OR borrower_id = ?", userID, userID).
+		Order("created_at DESC").Find(&rentals).Error
+	return rentals, err
+}
+
+func (r gormRentals) ListDue(now time.Time) ([]models.Rental, error) {
+	var rentals []models.Rental
+	err := r.db.Where("status = ? AND ends_at <= ?", models.RentalStatusActive, now).Find(&rentals).Error
+	return rentals, err
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"gorm.io/gorm"
+)
+
+type gormTrades struct{ db *gorm.DB }
+
+func (r gormTrades) Get(id uint) (*models.Trade, error) {
+	return first[models.Trade](r.db.Preload("Assets"), id)
+}
+
+func (r gormTrades) Lock(id uint) (*models.Trade, error) {
+	return first[models.Trade](forUpdate(r.db).Preload("Assets"), id)
+}
+
+func (r gormTrades) Create(t *models.Trade) error { return r.db.Omit("Assets").Create(t).Error }
+func (r gormTrades) Save(t *models.Trade) error   { return r.db.Omit("Assets").Save(t).Error }
+
+func (r gormTrades) SetAssets(tradeID, fromUserID uint, assets []models.TradeAsset) error {
+	if err := r.db.Where("trade_id = ? AND from_user_id = ?", tradeID, fromUserID).Delete(&models.TradeAsset{}).Error; err != nil {
+		return err
+	}
+	if len(assets) == 0 {
+		return nil
+	}
+	for i := range assets {
+		assets[i].TradeID, assets[i].FromUserID = tradeID, fromUserID
+	}
+	return r.db.Create(&assets).Error
+}
+
+func (r gormTrades) ListForUser(userID uint, status string) ([]models.Trade, error) {
+	var trades []models.Trade
+	q := r.db.Preload("Assets").Where("initiator_id = ? OR counterparty_id = ?", userID, userID)
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+	err := q.Order("created_at DESC").Find(&trades).Error
+	return trades, err
+}
+
+func (r gormTrades) ListExpired(now time.Time) ([]models.Trade, error) {
+	var trades []models.Trade
+	err := r.db.Where("status = ? AND expires_at <= ?", models.TradeStatusOpen, now).Find(&trades).Error
+	return trades, err
+}
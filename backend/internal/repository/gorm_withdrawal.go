@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// withdrawalNonceLock serializes hot wallet nonce reservation across API instances
+const withdrawalNonceLock int64 = 0x6374645f6e6f6e // "ctd_non"
+
+type gormWithdrawals struct{ db *gorm.DB }
+
+func (r gormWithdrawals) Get(id uint) (*models.Withdrawal, error) {
+	return first[models.Withdrawal](r.db, id)
+}
+
+func (r gormWithdrawals) Lock(id uint) (*models.Withdrawal, error) {
+	return first[models.Withdrawal](forUpdate(r.db), id)
+}
+
+func (r gormWithdrawals) Create(w *models.Withdrawal) error { return r.db.Create(w).Error }
+
+func (r gormWithdrawals) Save(w *models.Withdrawal) error { return r.db.Save(w).Error }
+
+func (r gormWithdrawals) ClaimApproved(limit int) ([]models.Withdrawal, error) {
+	if err := r.db.Exec("SELECT pg_advisory_xact_lock(?)", withdrawalNonceLock).Error; err != nil {
+		return nil, err
+	}
+	var queue []models.Withdrawal
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ?", models.WithdrawalApproved).Order("id ASC").Limit(limit).Find(&queue).Error
+	return queue, err
+}
+
+func (r gormWithdrawals) NextNonce(pending uint64) (uint64, error) {
+	var highest *uint64
+	if err := r.db.Model(&models.Withdrawal{}).Where("nonce IS NOT NULL").
+		Select("MAX(nonce)").Scan(&highest).Error; err != nil {
+		return 0, err
+	}
+	if highest != nil && *highest >= pending {
+		return *highest + 1, nil
+	}
+	return pending, nil
+}
+
+func (r gormWithdrawals) ListByStatus(statuses ...string) ([]models.Withdrawal, error) {
+	var withdrawals []models.Withdrawal
+	err := r.db.Where("status IN ?", statuses).Order("nonce ASC, id ASC").Find(&withdrawals).Error
+	return withdrawals, err
+}
+
+func (r gormWithdrawals) List(status string, limit int) ([]models.Withdrawal, error) {
+	query := r.db.Order("id DESC").Limit(limit)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var withdrawals []models.Withdrawal
+	err := query.Find(&withdrawals).Error
+	return withdrawals, err
+}
+
+func (r gormWithdrawals) ListForUser(userID uint, limit int) ([]models.Withdrawal, error) {
+	var withdrawals []models.Withdrawal
+	err := r.db.Where("user_id = ?", userID).Order("id DESC").Limit(limit).Find(&withdrawals).Error
+	return withdrawals, err
+}
+
+func (r gormWithdrawals) MarkOnChain(transactionID uint, txHash string, chainID int) error {
+	return r.db.Model(&models.Transaction{}).Where("id = ?", transactionID).Updates(map[string]interface{}{
+		"blockchain_tx_hash": txHash,
+		"chain_id":           chainID,
+		"is_on_chain":        true,
+	}).Error
+}
+
+func (r gormWithdrawals) Refund(w *models.Withdrawal, reason string) error {
+	user, err := first[models.User](forUpdate(r.db), w.UserID)
+	if err != nil {
+		return err
+	}
+	if err := r.db.Model(user).Update("tower_balance", gorm.Expr("tower_balance + ?", w.Amount)).Error; err != nil {
+		return err
+	}
+	if err := r.db.Create(refundTransaction(w, user.TOWERBalance, reason)).Error; err != nil {
+		return err
+	}
+	return r.db.Create(&models.AuditLog{
+		UserID:     &w.UserID,
+		Action:     "WITHDRAWAL_REFUNDED",
+		EntityType: "withdrawal",
+		EntityID:   &w.ID,
+		NewValues:  fmt.Sprintf("status:%s,reason:%s", w.Status, reason),
+	}).Error
+}
+
+// refundTransaction is the WITHDRAWAL_REFUND row crediting w back to a balance of before
+func refundTransaction(w *models.Withdrawal, before int64, reason string) *models.Transaction {
+	if len(reason) > 180 {
+		reason = reason[:180]
+	}
+	return &models.Transaction{
+		UserID:          w.UserID,
+		TransactionType: "WITHDRAWAL_REFUND",
+		TokenType:       "TOWER",
+		Amount:          w.Amount,
+		BalanceBefore:   before,
+		BalanceAfter:    before + w.Amount,
+		Description:     fmt.Sprintf("Refund of withdrawal #%d: %s", w.ID, reason),
+	}
+}
@@ -0,0 +1,706 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+)
+
+// MemoryStore is an in-memory Store for tests. Transactions are serialized and roll back by
+// restoring a snapshot, so a failed service call leaves no partial writes behind, just like Postgres.
+// Row locks are implied by the serialization. Lookups return copies; changes need a Save.
+// Touching the outer store from inside a transaction callback deadlocks, which flags service
+// code that escapes its transaction.
+type MemoryStore struct {
+	mu   *sync.Mutex
+	data *memData
+	inTx bool // The caller already holds mu
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+type memData struct {
+	nextID uint
+
+	characters map[uint]models.Character
+	items      map[uint]models.Item
+	eggs       map[uint]models.Egg
+	eggCare    map[uint]models.EggCare
+	users      map[uint]models.User
+	teams      map[uint][]uint // Active team character IDs by user
+	inventory  map[uint]models.UserInventory
+	shopItems  map[uint]models.ShopItem
+	battles    map[uint]models.Battle
+
+	accounts     map[uint]models.LedgerAccount
+	transactions map[uint]models.LedgerTransaction
+	entries      map[uint]models.LedgerEntry
+
+	listings     map[uint]models.MarketplaceListing
+	bundleAssets map[uint]models.MarketplaceAsset
+	bids         map[uint]models.MarketplaceBid
+	offers       map[uint]models.MarketplaceOffer
+	trades       map[uint]models.TradeHistory
+
+	recipes map[uint]models.CraftingRecipe
+	salvage map[uint]models.SalvageYield
+	jobs    map[uint]models.CraftingJob
+
+	equipment map[uint]models.Equipment
+	loadouts  map[uint]models.CharacterEquipment
+	runes     map[uint]models.EquipmentRune
+
+	provenance map[uint]models.CharacterProvenance
+	rentals    map[uint]models.Rental
+
+	playerTrades map[uint]models.Trade
+	tradeAssets  map[uint]models.TradeAsset
+
+	calendarDays map[uint]models.LoginCalendarDay
+	loginClaims  map[uint]models.LoginReward
+
+	withdrawals map[uint]models.Withdrawal
+	walletTxs   map[uint]models.Transaction // The pre-ledger transactions table
+}
+
+// NewMemoryStore returns an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		mu: &sync.Mutex{},
+		data: &memData{
+			characters:   map[uint]models.Character{},
+			items:        map[uint]models.Item{},
+			eggs:         map[uint]models.Egg{},
+			eggCare:      map[uint]models.EggCare{},
+			users:        map[uint]models.User{},
+			teams:        map[uint][]uint{},
+			inventory:    map[uint]models.UserInventory{},
+			shopItems:    map[uint]models.ShopItem{},
+			battles:      map[uint]models.Battle{},
+			accounts:     map[uint]models.LedgerAccount{},
+			transactions: map[uint]models.LedgerTransaction{},
+			entries:      map[uint]models.LedgerEntry{},
+			listings:     map[uint]models.MarketplaceListing{},
+			bundleAssets: map[uint]models.MarketplaceAsset{},
+			bids:         map[uint]models.MarketplaceBid{},
+			offers:       map[uint]models.MarketplaceOffer{},
+			trades:       map[uint]models.TradeHistory{},
+			recipes:      map[uint]models.CraftingRecipe{},
+			salvage:      map[uint]models.SalvageYield{},
+			jobs:         map[uint]models.CraftingJob{},
+			equipment:    map[uint]models.Equipment{},
+			loadouts:     map[uint]models.CharacterEquipment{},
+			runes:        map[uint]models.EquipmentRune{},
+			provenance:   map[uint]models.CharacterProvenance{},
+			rentals:      map[uint]models.Rental{},
+			playerTrades: map[uint]models.Trade{},
+			tradeAssets:  map[uint]models.TradeAsset{},
+			calendarDays: map[uint]models.LoginCalendarDay{},
+			loginClaims:  map[uint]models.LoginReward{},
+			withdrawals:  map[uint]models.Withdrawal{},
+			walletTxs:    map[uint]models.Transaction{},
+		},
+	}
+}
+
+func (d *memData) clone() *memData {
+	return &memData{
+		nextID:       d.nextID,
+		characters:   cloneMap(d.characters),
+		items:        cloneMap(d.items),
+		eggs:         cloneMap(d.eggs),
+		eggCare:      cloneMap(d.eggCare),
+		users:        cloneMap(d.users),
+		teams:        cloneMap(d.teams),
+		inventory:    cloneMap(d.inventory),
+		shopItems:    cloneMap(d.shopItems),
+		battles:      cloneMap(d.battles),
+		accounts:     cloneMap(d.accounts),
+		transactions: cloneMap(d.transactions),
+		entries:      cloneMap(d.entries),
+		listings:     cloneMap(d.listings),
+		bundleAssets: cloneMap(d.bundleAssets),
+		bids:         cloneMap(d.bids),
+		offers:       cloneMap(d.offers),
+		trades:       cloneMap(d.trades),
+		recipes:      cloneMap(d.recipes),
+		salvage:      cloneMap(d.salvage),
+		jobs:         cloneMap(d.jobs),
+		equipment:    cloneMap(d.equipment),
+		loadouts:     cloneMap(d.loadouts),
+		runes:        cloneMap(d.runes),
+		provenance:   cloneMap(d.provenance),
+		rentals:      cloneMap(d.rentals),
+		playerTrades: cloneMap(d.playerTrades),
+		tradeAssets:  cloneMap(d.tradeAssets),
+		calendarDays: cloneMap(d.calendarDays),
+		loginClaims:  cloneMap(d.loginClaims),
+		withdrawals:  cloneMap(d.withdrawals),
+		walletTxs:    cloneMap(d.walletTxs),
+	}
+}
+
+func cloneMap[T any](m map[uint]T) map[uint]T {
+	out := make(map[uint]T, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+// sorted returns the values of m matching keep, in ID order
+func sorted[T any](m map[uint]T, keep func(*T) bool) []T {
+	ids := make([]uint, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	out := []T{}
+	for _, id := range ids {
+		v := m[id]
+		if keep(&v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// lookup returns a copy of m[id]
+func lookup[T any](m map[uint]T, id uint) (*T, error) {
+	v, ok := m[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &v, nil
+}
+
+// firstOf returns the lowest-ID value of m matching keep
+func firstOf[T any](m map[uint]T, keep func(*T) bool) (*T, error) {
+	all := sorted(m, keep)
+	if len(all) == 0 {
+		return nil, ErrNotFound
+	}
+	return &all[0], nil
+}
+
+// lock takes the store mutex unless this Store belongs to a running transaction
+func (s *MemoryStore) lock() func() {
+	if s.inTx {
+		return func() {}
+	}
+	s.mu.Lock()
+	return s.mu.Unlock
+}
+
+// id allocates the next primary key (shared across tables; only uniqueness matters)
+func (s *MemoryStore) id(current *uint) {
+	if *current == 0 {
+		s.data.nextID++
+		*current = s.data.nextID
+	}
+}
+
+func (s *MemoryStore) Characters() CharacterRepository     { return memCharacters{s} }
+func (s *MemoryStore) Items() ItemRepository               { return memItems{s} }
+func (s *MemoryStore) Eggs() EggRepository                 { return memEggs{s} }
+func (s *MemoryStore) Users() UserRepository               { return memUsers{s} }
+func (s *MemoryStore) Inventory() InventoryRepository      { return memInventory{s} }
+func (s *MemoryStore) Battles() BattleRepository           { return memBattles{s} }
+func (s *MemoryStore) Ledger() LedgerRepository            { return memLedger{s} }
+func (s *MemoryStore) Listings() ListingRepository         { return memListings{s} }
+func (s *MemoryStore) Crafting() CraftingRepository        { return memCrafting{s} }
+func (s *MemoryStore) Equipment() EquipmentRepository      { return memEquipment{s} }
+func (s *MemoryStore) Provenance() ProvenanceRepository    { return memProvenance{s} }
+func (s *MemoryStore) Rentals() RentalRepository           { return memRentals{s} }
+func (s *MemoryStore) Trades() TradeRepository             { return memTrades{s} }
+func (s *MemoryStore) LoginRewards() LoginRewardRepository { return memLoginRewards{s} }
+func (s *MemoryStore) Withdrawals() WithdrawalRepository   { return memWithdrawals{s} }
+
+func (s *MemoryStore) WithContext(context.Context) Store { return s }
+
+func (s *MemoryStore) Transaction(fn func(tx Store) error) error {
+	unlock := s.lock()
+	defer unlock()
+
+	snapshot := s.data.clone()
+	if err := fn(&MemoryStore{mu: s.mu, data: s.data, inTx: true}); err != nil {
+		*s.data = *snapshot
+		return err
+	}
+	return nil
+}
+
+// SetActiveTeam makes characterIDs the user's active battle team (test setup)
+func (s *MemoryStore) SetActiveTeam(userID uint, characterIDs ...uint) {
+	defer s.lock()()
+	s.data.teams[userID] = characterIDs
+}
+
+// PutShopItem adds a shop catalogue entry (test setup)
+func (s *MemoryStore) PutShopItem(item models.ShopItem) *models.ShopItem {
+	defer s.lock()()
+	s.id(&item.ID)
+	s.data.shopItems[item.ID] = item
+	return &item
+}
+
+type memCharacters struct{ s *MemoryStore }
+
+func (r memCharacters) Get(id uint) (*models.Character, error) {
+	defer r.s.lock()()
+	return lookup(r.s.data.characters, id)
+}
+
+func (r memCharacters) Lock(id uint) (*models.Character, error) { return r.Get(id) }
+
+func (r memCharacters) Create(c *models.Character) error {
+	defer r.s.lock()()
+	r.s.id(&c.ID)
+	c.CreatedAt, c.UpdatedAt = time.Now(), time.Now()
+	r.s.data.characters[c.ID] = *c
+	return nil
+}
+
+func (r memCharacters) Save(c *models.Character) error {
+	defer r.s.lock()()
+	r.s.id(&c.ID)
+	c.UpdatedAt = time.Now()
+	r.s.data.characters[c.ID] = *c
+	return nil
+}
+
+func (r memCharacters) FirstAlive(ownerID uint) (*models.Character, error) {
+	defer r.s.lock()()
+	return firstOf(r.s.data.characters, func(c *models.Character) bool { return c.OwnerID == ownerID && !c.IsFainted })
+}
+
+func (r memCharacters) CountAlive(ownerID uint) (int64, error) {
+	defer r.s.lock()()
+	return int64(len(sorted(r.s.data.characters, func(c *models.Character) bool { return c.OwnerID == ownerID && !c.IsFainted }))), nil
+}
+
+func (r memCharacters) ActiveTeam(userID uint) ([]models.Character, error) {
+	defer r.s.lock()()
+	ids, ok := r.s.data.teams[userID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	var chars []models.Character
+	for _, id := range ids {
+		if c, ok := r.s.data.characters[id]; ok {
+			chars = append(chars, c)
+		}
+	}
+	return chars, nil
+}
+
+func (r memCharacters) RemoveFromTeams(characterID, userID uint) error {
+	defer r.s.lock()()
+	var kept []uint
+	for _, id := range r.s.data.teams[userID] {
+		if id != characterID {
+			kept = append(kept, id)
+		}
+	}
+	if _, ok := r.s.data.teams[userID]; ok {
+		r.s.data.teams[userID] = kept
+	}
+	return nil
+}
+
+func (r memCharacters) Owner(id uint) (uint, error) {
+	defer r.s.lock()()
+	c, ok := r.s.data.characters[id]
+	if !ok {
+		return 0, ErrNotFound
+	}
+	return c.OwnerID, nil
+}
+
+func (r memCharacters) MarkListed(id, ownerID uint, at time.Time) error {
+	defer r.s.lock()()
+	c, ok := r.s.data.characters[id]
+	if !ok || c.OwnerID != ownerID || c.IsListed || c.ControllerID != nil {
+		return ErrNotFound
+	}
+	c.IsListed, c.ListedAt = true, &at
+	r.s.data.characters[id] = c
+	return nil
+}
+
+func (r memCharacters) SetListed(id uint, listed bool) error {
+	defer r.s.lock()()
+	if c, ok := r.s.data.characters[id]; ok {
+		c.IsListed = listed
+		r.s.data.characters[id] = c
+	}
+	return nil
+}
+
+func (r memCharacters) Transfer(id, fromUserID, toUserID uint) error {
+	defer r.s.lock()()
+	c, ok := r.s.data.characters[id]
+	if !ok || c.OwnerID != fromUserID || c.ControllerID != nil {
+		return ErrNotFound
+	}
+	c.OwnerID, c.IsListed = toUserID, false
+	r.s.data.characters[id] = c
+	return nil
+}
+
+type memItems struct{ s *MemoryStore }
+
+func (r memItems) Get(id uint) (*models.Item, error) {
+	defer r.s.lock()()
+	return lookup(r.s.data.items, id)
+}
+
+func (r memItems) Lock(id uint) (*models.Item, error) {
+	return r.Get(id)
+}
+
+func (r memItems) Create(item *models.Item) error {
+	defer r.s.lock()()
+	r.s.id(&item.ID)
+	item.CreatedAt, item.UpdatedAt = time.Now(), time.Now()
+	r.s.data.items[item.ID] = *item
+	return nil
+}
+
+func (r memItems) Save(item *models.Item) error {
+	defer r.s.lock()()
+	r.s.id(&item.ID)
+	item.UpdatedAt = time.Now()
+	r.s.data.items[item.ID] = *item
+	return nil
+}
+
+func (r memItems) Delete(id uint) error {
+	defer r.s.lock()()
+	delete(r.s.data.items, id)
+	return nil
+}
+
+func (r memItems) LockMaterials(ownerID uint, name string) ([]models.Item, error) {
+	defer r.s.lock()()
+	return sorted(r.s.data.items, func(item *models.Item) bool {
+		return item.OwnerID == ownerID && item.ItemType == "MATERIAL" && item.Name == name && !item.IsListed
+	}), nil
+}
+
+func (r memItems) Owner(id uint) (uint, error) {
+	defer r.s.lock()()
+	item, ok := r.s.data.items[id]
+	if !ok {
+		return 0, ErrNotFound
+	}
+	return item.OwnerID, nil
+}
+
+func (r memItems) MarkListed(id, ownerID uint, at time.Time) error {
+	defer r.s.lock()()
+	item, ok := r.s.data.items[id]
+	if !ok || item.OwnerID != ownerID || item.IsListed || item.IsEquipped {
+		return ErrNotFound
+	}
+	item.IsListed, item.ListedAt = true, &at
+	r.s.data.items[id] = item
+	return nil
+}
+
+func (r memItems) SetListed(id uint, listed bool) error {
+	defer r.s.lock()()
+	if item, ok := r.s.data.items[id]; ok {
+		item.IsListed = listed
+		r.s.data.items[id] = item
+	}
+	return nil
+}
+
+func (r memItems) Transfer(id, fromUserID, toUserID uint) error {
+	defer r.s.lock()()
+	item, ok := r.s.data.items[id]
+	if !ok || item.OwnerID != fromUserID {
+		return ErrNotFound
+	}
+	item.OwnerID, item.IsListed = toUserID, false
+	r.s.data.items[id] = item
+	return nil
+}
+
+type memEggs struct{ s *MemoryStore }
+
+func (r memEggs) Get(id uint) (*models.Egg, error) {
+	defer r.s.lock()()
+	return lookup(r.s.data.eggs, id)
+}
+
+func (r memEggs) Create(egg *models.Egg) error {
+	defer r.s.lock()()
+	r.s.id(&egg.ID)
+	egg.CreatedAt, egg.UpdatedAt = time.Now(), time.Now()
+	r.s.data.eggs[egg.ID] = *egg
+	return nil
+}
+
+func (r memEggs) Save(egg *models.Egg) error {
+	defer r.s.lock()()
+	r.s.id(&egg.ID)
+	egg.UpdatedAt = time.Now()
+	r.s.data.eggs[egg.ID] = *egg
+	return nil
+}
+
+func (r memEggs) Lock(id uint) (*models.Egg, error) {
+	return r.Get(id)
+}
+
+func (r memEggs) ListUnhatched(userID uint) ([]models.Egg, error) {
+	defer r.s.lock()()
+	eggs := sorted(r.s.data.eggs, func(e *models.Egg) bool { return e.UserID == userID && e.HatchedAt == nil })
+	sort.SliceStable(eggs, func(i, j int) bool { return eggs[i].ID > eggs[j].ID })
+	return eggs, nil
+}
+
+func (r memEggs) HatchedInto(characterID uint) (*models.Egg, error) {
+	defer r.s.lock()()
+	return firstOf(r.s.data.eggs, func(e *models.Egg) bool { return e.CharacterID != nil && *e.CharacterID == characterID })
+}
+
+func (r memEggs) Transfer(id, fromUserID, toUserID uint) error {
+	defer r.s.lock()()
+	egg, ok := r.s.data.eggs[id]
+	if !ok || egg.UserID != fromUserID {
+		return ErrNotFound
+	}
+	egg.UserID = toUserID
+	r.s.data.eggs[id] = egg
+	return nil
+}
+
+func (r memEggs) Care(eggID uint) ([]models.EggCare, error) {
+	defer r.s.lock()()
+	return sorted(r.s.data.eggCare, func(c *models.EggCare) bool { return c.EggID == eggID }), nil
+}
+
+func (r memEggs) AddCare(c *models.EggCare) error {
+	defer r.s.lock()()
+	r.s.id(&c.ID)
+	c.CreatedAt = time.Now()
+	r.s.data.eggCare[c.ID] = *c
+	return nil
+}
+
+type memUsers struct{ s *MemoryStore }
+
+func (r memUsers) Get(id uint) (*models.User, error) {
+	defer r.s.lock()()
+	return lookup(r.s.data.users, id)
+}
+
+func (r memUsers) Lock(id uint) (*models.User, error) { return r.Get(id) }
+
+func (r memUsers) Create(u *models.User) error {
+	defer r.s.lock()()
+	r.s.id(&u.ID)
+	u.CreatedAt, u.UpdatedAt = time.Now(), time.Now()
+	r.s.data.users[u.ID] = *u
+	return nil
+}
+
+func (r memUsers) Save(u *models.User) error {
+	defer r.s.lock()()
+	r.s.id(&u.ID)
+	u.UpdatedAt = time.Now()
+	r.s.data.users[u.ID] = *u
+	return nil
+}
+
+// RandomOpponent picks the lowest matching ID so tests stay deterministic
+func (r memUsers) RandomOpponent(excludeID uint, minELO, maxELO int) (*models.User, error) {
+	defer r.s.lock()()
+	return firstOf(r.s.data.users, func(u *models.User) bool {
+		return u.ID != excludeID && u.ELO >= minELO && u.ELO <= maxELO
+	})
+}
+
+// AdjustLegacyTokens is a no-op: the legacy column is not part of the model
+func (r memUsers) AdjustLegacyTokens(uint, int64) error { return nil }
+
+type memInventory struct{ s *MemoryStore }
+
+func (r memInventory) Find(userID, shopItemID uint) (*models.UserInventory, error) {
+	defer r.s.lock()()
+	return firstOf(r.s.data.inventory, func(inv *models.UserInventory) bool {
+		return inv.UserID == userID && inv.ItemID == shopItemID
+	})
+}
+
+func (r memInventory) Save(inv *models.UserInventory) error {
+	defer r.s.lock()()
+	r.s.id(&inv.ID)
+	r.s.data.inventory[inv.ID] = *inv
+	return nil
+}
+
+func (r memInventory) Delete(inv *models.UserInventory) error {
+	defer r.s.lock()()
+	delete(r.s.data.inventory, inv.ID)
+	return nil
+}
+
+func (r memInventory) ShopItem(id uint) (*models.ShopItem, error) {
+	defer r.s.lock()()
+	return lookup(r.s.data.shopItems, id)
+}
+
+type memBattles struct{ s *MemoryStore }
+
+func (r memBattles) Get(id uint) (*models.Battle, error) {
+	defer r.s.lock()()
+	b, err := lookup(r.s.data.battles, id)
+	if err != nil {
+		return nil, err
+	}
+	b.Player1, b.Player2 = r.s.data.users[b.Player1ID], r.s.data.users[b.Player2ID]
+	return b, nil
+}
+
+func (r memBattles) Create(b *models.Battle) error {
+	defer r.s.lock()()
+	r.s.id(&b.ID)
+	if b.CreatedAt.IsZero() {
+		b.CreatedAt = time.Now()
+	}
+	b.UpdatedAt = time.Now()
+	r.s.data.battles[b.ID] = *b
+	return nil
+}
+
+func (r memBattles) Save(b *models.Battle) error {
+	defer r.s.lock()()
+	r.s.id(&b.ID)
+	b.UpdatedAt = time.Now()
+	r.s.data.battles[b.ID] = *b
+	return nil
+}
+
+func (r memBattles) Delete(b *models.Battle) error {
+	defer r.s.lock()()
+	delete(r.s.data.battles, b.ID)
+	return nil
+}
+
+func (r memBattles) FindInProgress(userID uint) (*models.Battle, error) {
+	defer r.s.lock()()
+	return firstOf(r.s.data.battles, func(b *models.Battle) bool {
+		return (b.Player1ID == userID || b.Player2ID == userID) && (b.Status == "SEARCHING" || b.Status == "active")
+	})
+}
+
+func (r memBattles) LockOpenWager(userID uint) (*models.Battle, error) {
+	defer r.s.lock()()
+	return firstOf(r.s.data.battles, func(b *models.Battle) bool {
+		return b.BattleType == "wager" && b.Status == "SEARCHING" && b.Player1ID != userID
+	})
+}
+
+func (r memBattles) LockWagerSearch(userID uint) (*models.Battle, error) {
+	defer r.s.lock()()
+	return firstOf(r.s.data.battles, func(b *models.Battle) bool {
+		return b.BattleType == "wager" && b.Status == "SEARCHING" && b.Player1ID == userID
+	})
+}
+
+func (r memBattles) ListStale(before time.Time) ([]models.Battle, error) {
+	defer r.s.lock()()
+	return sorted(r.s.data.battles, func(b *models.Battle) bool { return b.Status == "active" && b.UpdatedAt.Before(before) }), nil
+}
+
+func (r memBattles) History(userID uint, limit int) ([]models.Battle, error) {
+	defer r.s.lock()()
+	battles := sorted(r.s.data.battles, func(b *models.Battle) bool {
+		return (b.Player1ID == userID || b.Player2ID == userID) && b.Status == "completed"
+	})
+	sort.SliceStable(battles, func(i, j int) bool { return battles[i].ID > battles[j].ID })
+	if limit > 0 && len(battles) > limit {
+		battles = battles[:limit]
+	}
+	return battles, nil
+}
+
+type memLedger struct{ s *MemoryStore }
+
+func (r memLedger) FindAccount(userID *uint, accType models.AccountType, currency string) (*models.LedgerAccount, error) {
+	defer r.s.lock()()
+	return firstOf(r.s.data.accounts, func(a *models.LedgerAccount) bool {
+		if a.Type != accType || a.Currency != currency || a.GuildID != nil {
+			return false
+		}
+		if userID == nil {
+			return a.UserID == nil
+		}
+		return a.UserID != nil && *a.UserID == *userID
+	})
+}
+
+func (r memLedger) FindGuildAccount(guildID uint, currency string) (*models.LedgerAccount, error) {
+	defer r.s.lock()()
+	return firstOf(r.s.data.accounts, func(a *models.LedgerAccount) bool {
+		return a.Type == models.AccountTypeGuildTreasury && a.Currency == currency && a.GuildID != nil && *a.GuildID == guildID
+	})
+}
+
+func (r memLedger) CreateAccount(acc *models.LedgerAccount) error {
+	defer r.s.lock()()
+	r.s.id(&acc.ID)
+	acc.CreatedAt, acc.UpdatedAt = time.Now(), time.Now()
+	r.s.data.accounts[acc.ID] = *acc
+	return nil
+}
+
+func (r memLedger) LockAccount(id uint) (*models.LedgerAccount, error) {
+	defer r.s.lock()()
+	return lookup(r.s.data.accounts, id)
+}
+
+func (r memLedger) SaveAccount(acc *models.LedgerAccount) error {
+	defer r.s.lock()()
+	r.s.id(&acc.ID)
+	acc.UpdatedAt = time.Now()
+	r.s.data.accounts[acc.ID] = *acc
+	return nil
+}
+
+func (r memLedger) CreateTransaction(tx *models.LedgerTransaction) error {
+	defer r.s.lock()()
+	r.s.id(&tx.ID)
+	r.s.data.transactions[tx.ID] = *tx
+	return nil
+}
+
+func (r memLedger) CreateEntry(e *models.LedgerEntry) error {
+	defer r.s.lock()()
+	r.s.id(&e.ID)
+	e.CreatedAt = time.Now()
+	r.s.data.entries[e.ID] = *e
+	return nil
+}
+
+type memProvenance struct{ s *MemoryStore }
+
+func (r memProvenance) Add(e *models.CharacterProvenance) error {
+	defer r.s.lock()()
+	r.s.id(&e.ID)
+	e.CreatedAt = time.Now()
+	r.s.data.provenance[e.ID] = *e
+	return nil
+}
+
+func (r memProvenance) List(characterID uint) ([]models.CharacterProvenance, error) {
+	defer r.s.lock()()
+	return sorted(r.s.data.provenance, func(e *models.CharacterProvenance) bool { return e.CharacterID == characterID }), nil
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"sort"
+	"time"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+)
+
+// PutRecipe adds a crafting recipe (test setup)
+func (s *MemoryStore) PutRecipe(recipe models.CraftingRecipe) *models.CraftingRecipe {
+	defer s.lock()()
+	s.id(&recipe.ID)
+	s.data.recipes[recipe.ID] = recipe
+	return &recipe
+}
+
+// PutSalvageYield adds a salvage yield (test setup)
+func (s *MemoryStore) PutSalvageYield(y models.SalvageYield) *models.SalvageYield {
+	defer s.lock()()
+	s.id(&y.ID)
+	s.data.salvage[y.ID] = y
+	return &y
+}
+
+type memCrafting struct{ s *MemoryStore }
+
+func (r memCrafting) Recipe(id uint) (*models.CraftingRecipe, error) {
+	defer r.s.lock()()
+	return lookup(r.s.data.recipes, id)
+}
+
+func (r memCrafting) ListRecipes() ([]models.CraftingRecipe, error) {
+	defer r.s.lock()()
+	recipes := sorted(r.s.data.recipes, func(rc *models.CraftingRecipe) bool { return rc.IsActive })
+	sort.SliceStable(recipes, func(i, j int) bool { return recipes[i].Name < recipes[j].Name })
+	return recipes, nil
+}
+
+func (r memCrafting) SalvageYield(itemType string) (*models.SalvageYield, error) {
+	defer r.s.lock()()
+	return firstOf(r.s.data.salvage, func(y *models.SalvageYield) bool { return y.ItemType == itemType })
+}
+
+func (r memCrafting) CreateJob(j *models.CraftingJob) error {
+	defer r.s.lock()()
+	r.s.id(&j.ID)
+	j.CreatedAt, j.UpdatedAt = time.Now(), time.Now()
+	r.s.data.jobs[j.ID] = *j
+	return nil
+}
+
+func (r memCrafting) SaveJob(j *models.CraftingJob) error {
+	defer r.s.lock()()
+	r.s.id(&j.ID)
+	j.UpdatedAt = time.Now()
+	r.s.data.jobs[j.ID] = *j
+	return nil
+}
+
+func (r memCrafting) LockJob(id uint) (*models.CraftingJob, error) {
+	defer r.s.lock()()
+	return lookup(r.s.data.jobs, id)
+}
+
+func (r memCrafting) CountActiveJobs(userID uint) (int64, error) {
+	defer r.s.lock()()
+	return int64(len(sorted(r.s.data.jobs, func(j *models.CraftingJob) bool {
+		return j.UserID == userID && j.Status == models.CraftingStatusCrafting
+	}))), nil
+}
+
+func (r memCrafting) ListJobs(userID uint, limit int) ([]models.CraftingJob, error) {
+	defer r.s.lock()()
+	jobs := sorted(r.s.data.jobs, func(j *models.CraftingJob) bool { return j.UserID == userID })
+	sort.SliceStable(jobs, func(i, j int) bool { return jobs[i].ID > jobs[j].ID })
+	if limit > 0 && len(jobs) > limit {
+		jobs = jobs[:limit]
+	}
+	for i := range jobs {
+		jobs[i].Recipe = r.s.data.recipes[jobs[i].RecipeID]
+	}
+	return jobs, nil
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+)
+
+type memEquipment struct{ s *MemoryStore }
+
+func (r memEquipment) Lock(id uint) (*models.Equipment, error) {
+	defer r.s.lock()()
+	return lookup(r.s.data.equipment, id)
+}
+
+func (r memEquipment) LockForItem(itemID uint) (*models.Equipment, error) {
+	defer r.s.lock()()
+	return firstOf(r.s.data.equipment, func(e *models.Equipment) bool { return e.ItemID == itemID })
+}
+
+func (r memEquipment) Create(e *models.Equipment) error {
+	defer r.s.lock()()
+	r.s.id(&e.ID)
+	e.CreatedAt = time.Now()
+	r.s.data.equipment[e.ID] = *e
+	return nil
+}
+
+func (r memEquipment) Save(e *models.Equipment) error {
+	defer r.s.lock()()
+	r.s.id(&e.ID)
+	r.s.data.equipment[e.ID] = *e
+	return nil
+}
+
+func (r memEquipment) LockLoadout(characterID uint) (*models.CharacterEquipment, error) {
+	defer r.s.lock()()
+	return firstOf(r.s.data.loadouts, func(l *models.CharacterEquipment) bool { return l.CharacterID == characterID })
+}
+
+func (r memEquipment) SaveLoadout(l *models.CharacterEquipment) error {
+	defer r.s.lock()()
+	r.s.id(&l.ID)
+	r.s.data.loadouts[l.ID] = *l
+	return nil
+}
+
+func (r memEquipment) Gear(characterID uint) ([]models.Equipment, error) {
+	defer r.s.lock()()
+	gear := []models.Equipment{}
+	loadout, err := firstOf(r.s.data.loadouts, func(l *models.CharacterEquipment) bool { return l.CharacterID == characterID })
+	if err != nil {
+		return gear, nil
+	}
+	for _, id := range []*uint{loadout.WeaponID, loadout.ArmorID, loadout.AccessoryID} {
+		if id == nil {
+			continue
+		}
+		e, ok := r.s.data.equipment[*id]
+		if !ok {
+			continue
+		}
+		e.Item = r.s.data.items[e.ItemID]
+		e.Runes = r.runes(e.ID)
+		gear = append(gear, e)
+	}
+	return gear, nil
+}
+
+func (r memEquipment) Runes(equipmentID uint) ([]models.EquipmentRune, error) {
+	defer r.s.lock()()
+	return r.runes(equipmentID), nil
+}
+
+func (r memEquipment) runes(equipmentID uint) []models.EquipmentRune {
+	runes := sorted(r.s.data.runes, func(er *models.EquipmentRune) bool { return er.EquipmentID == equipmentID })
+	for i := range runes {
+		runes[i].RuneItem = r.s.data.items[runes[i].RuneItemID]
+	}
+	return runes
+}
+
+func (r memEquipment) RuneByItem(runeItemID uint) (*models.EquipmentRune, error) {
+	defer r.s.lock()()
+	return firstOf(r.s.data.runes, func(er *models.EquipmentRune) bool { return er.RuneItemID == runeItemID })
+}
+
+func (r memEquipment) AddRune(er *models.EquipmentRune) error {
+	defer r.s.lock()()
+	r.s.id(&er.ID)
+	er.CreatedAt = time.Now()
+	r.s.data.runes[er.ID] = *er
+	return nil
+}
+
+func (r memEquipment) RemoveRune(id uint) error {
+	defer r.s.lock()()
+	delete(r.s.data.runes, id)
+	return nil
+}
@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"sort"
+	"time"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+)
+
+type memListings struct{ s *MemoryStore }
+
+func (r memListings) Get(id uint) (*models.MarketplaceListing, error) {
+	defer r.s.lock()()
+	return lookup(r.s.data.listings, id)
+}
+
+func (r memListings) Lock(id uint) (*models.MarketplaceListing, error) {
+	return r.Get(id)
+}
+
+func (r memListings) Create(l *models.MarketplaceListing) error {
+	defer r.s.lock()()
+	r.s.id(&l.ID)
+	l.CreatedAt, l.UpdatedAt = time.Now(), time.Now()
+	r.s.data.listings[l.ID] = *l
+	return nil
+}
+
+func (r memListings) Save(l *models.MarketplaceListing) error {
+	defer r.s.lock()()
+	r.s.id(&l.ID)
+	l.UpdatedAt = time.Now()
+	r.s.data.listings[l.ID] = *l
+	return nil
+}
+
+func (r memListings) ActiveForAsset(assetType string, assetID uint) ([]models.MarketplaceListing, error) {
+	defer r.s.lock()()
+	return sorted(r.s.data.listings, func(l *models.MarketplaceListing) bool {
+		if l.Status != "ACTIVE" {
+			return false
+		}
+		if assetType == "character" {
+			return l.CharacterID != nil && *l.CharacterID == assetID
+		}
+		return l.ItemID != nil && *l.ItemID == assetID
+	}), nil
+}
+
+func (r memListings) ListActive(f ListingFilter) ([]models.MarketplaceListing, error) {
+	defer r.s.lock()()
+	now := time.Now()
+	listings := sorted(r.s.data.listings, func(l *models.MarketplaceListing) bool {
+		return l.Status == "ACTIVE" && l.ExpiresAt.After(now) &&
+			(f.AssetType == "" || l.AssetType == f.AssetType) &&
+			(f.ListingType == "" || l.ListingType == f.ListingType)
+	})
+	sort.SliceStable(listings, func(i, j int) bool { return listings[i].ID > listings[j].ID })
+	if f.Offset >= len(listings) {
+		return []models.MarketplaceListing{}, nil
+	}
+	listings = listings[f.Offset:]
+	if f.Limit > 0 && len(listings) > f.Limit {
+		listings = listings[:f.Limit]
+	}
+
+	for i := range listings {
+		l := &listings[i]
+		l.Seller = r.s.data.users[l.SellerID]
+		if l.CharacterID != nil {
+			if c, ok := r.s.data.characters[*l.CharacterID]; ok {
+				l.Character = &c
+			}
+		}
+		if l.ItemID != nil {
+			if item, ok := r.s.data.items[*l.ItemID]; ok {
+				l.Item = &item
+			}
+		}
+		l.BundleItems = sorted(r.s.data.bundleAssets, func(a *models.MarketplaceAsset) bool { return a.ListingID == l.ID })
+	}
+	return listings, nil
+}
+
+func (r memListings) ListExpired(now time.Time) ([]models.MarketplaceListing, error) {
+	defer r.s.lock()()
+	return sorted(r.s.data.listings, func(l *models.MarketplaceListing) bool {
+		return l.Status == "ACTIVE" && !l.ExpiresAt.After(now)
+	}), nil
+}
+
+func (r memListings) AddBundleAsset(a *models.MarketplaceAsset) error {
+	defer r.s.lock()()
+	r.s.id(&a.ID)
+	r.s.data.bundleAssets[a.ID] = *a
+	return nil
+}
+
+func (r memListings) BundleAssets(listingID uint) ([]models.MarketplaceAsset, error) {
+	defer r.s.lock()()
+	return sorted(r.s.data.bundleAssets, func(a *models.MarketplaceAsset) bool { return a.ListingID == listingID }), nil
+}
+
+func (r memListings) InActiveBundle(assetTypes []string, assetID uint) (bool, error) {
+	defer r.s.lock()()
+	matches := sorted(r.s.data.bundleAssets, func(a *models.MarketplaceAsset) bool {
+		if a.AssetID != assetID || r.s.data.listings[a.ListingID].Status != "ACTIVE" {
+			return false
+		}
+		for _, t := range assetTypes {
+			if a.AssetType == t {
+				return true
+			}
+		}
+		return false
+	})
+	return len(matches) > 0, nil
+}
+
+func (r memListings) CreateBid(b *models.MarketplaceBid) error {
+	defer r.s.lock()()
+	r.s.id(&b.ID)
+	b.CreatedAt = time.Now()
+	r.s.data.bids[b.ID] = *b
+	return nil
+}
+
+func (r memListings) SetBidStatus(listingID uint, from, to string) error {
+	defer r.s.lock()()
+	for id, b := range r.s.data.bids {
+		if b.ListingID == listingID && b.Status == from {
+			b.Status = to
+			r.s.data.bids[id] = b
+		}
+	}
+	return nil
+}
+
+func (r memListings) Bids(listingID uint, limit int) ([]models.MarketplaceBid, error) {
+	defer r.s.lock()()
+	bids := sorted(r.s.data.bids, func(b *models.MarketplaceBid) bool { return b.ListingID == listingID })
+	sort.SliceStable(bids, func(i, j int) bool { return bids[i].Amount > bids[j].Amount })
+	if limit > 0 && len(bids) > limit {
+		bids = bids[:limit]
+	}
+	return bids, nil
+}
+
+func (r memListings) LockOffer(id uint) (*models.MarketplaceOffer, error) {
+	defer r.s.lock()()
+	return lookup(r.s.data.offers, id)
+}
+
+func (r memListings) CreateOffer(o *models.MarketplaceOffer) error {
+	defer r.s.lock()()
+	r.s.id(&o.ID)
+	o.CreatedAt, o.UpdatedAt = time.Now(), time.Now()
+	r.s.data.offers[o.ID] = *o
+	return nil
+}
+
+func (r memListings) SaveOffer(o *models.MarketplaceOffer) error {
+	defer r.s.lock()()
+	r.s.id(&o.ID)
+	o.UpdatedAt = time.Now()
+	r.s.data.offers[o.ID] = *o
+	return nil
+}
+
+func (r memListings) LockPendingOffers(assetType string, assetID, excludeID uint) ([]models.MarketplaceOffer, error) {
+	defer r.s.lock()()
+	return sorted(r.s.data.offers, func(o *models.MarketplaceOffer) bool {
+		return o.AssetType == assetType && o.AssetID == assetID && o.Status == "PENDING" && o.ID != excludeID
+	}), nil
+}
+
+func (r memListings) CountPendingOffers(buyerID uint, assetType string, assetID uint) (int64, error) {
+	defer r.s.lock()()
+	return int64(len(sorted(r.s.data.offers, func(o *models.MarketplaceOffer) bool {
+		return o.BuyerID == buyerID && o.AssetType == assetType && o.AssetID == assetID && o.Status == "PENDING"
+	}))), nil
+}
+
+func (r memListings) ListOffers(userID uint, sent bool, status string) ([]models.MarketplaceOffer, error) {
+	defer r.s.lock()()
+	offers := sorted(r.s.data.offers, func(o *models.MarketplaceOffer) bool {
+		party := o.SellerID
+		if sent {
+			party = o.BuyerID
+		}
+		return party == userID && (status == "" || o.Status == status)
+	})
+	sort.SliceStable(offers, func(i, j int) bool { return offers[i].ID > offers[j].ID })
+	for i := range offers {
+		offers[i].Buyer = r.s.data.users[offers[i].BuyerID]
+	}
+	return offers, nil
+}
+
+func (r memListings) ListExpiredOffers(now time.Time) ([]models.MarketplaceOffer, error) {
+	defer r.s.lock()()
+	return sorted(r.s.data.offers, func(o *models.MarketplaceOffer) bool {
+		return o.Status == "PENDING" && !o.ExpiresAt.After(now)
+	}), nil
+}
+
+func (r memListings) CreateTrade(t *models.TradeHistory) error {
+	defer r.s.lock()()
+	r.s.id(&t.ID)
+	t.CompletedAt = time.Now()
+	r.s.data.trades[t.ID] = *t
+	return nil
+}
+
+func (r memListings) TradedSince(assetType string, assetID uint, since time.Time) (bool, error) {
+	defer r.s.lock()()
+	_, err := firstOf(r.s.data.trades, func(t *models.TradeHistory) bool {
+		return t.TradeID != nil && t.ItemType == assetType && t.ItemID == assetID && !t.CompletedAt.Before(since)
+	})
+	return err == nil, nil
+}
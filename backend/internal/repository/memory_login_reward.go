@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+)
+
+type memLoginRewards struct{ s *MemoryStore }
+
+func (r memLoginRewards) Calendar() ([]models.LoginCalendarDay, error) {
+	defer r.s.lock()()
+	days := sorted(r.s.data.calendarDays, func(*models.LoginCalendarDay) bool { return true })
+	slices.SortFunc(days, func(a, b models.LoginCalendarDay) int { return a.DayNumber - b.DayNumber })
+	for i := range days {
+		r.withItem(&days[i])
+	}
+	return days, nil
+}
+
+func (r memLoginRewards) CalendarDay(dayNumber int) (*models.LoginCalendarDay, error) {
+	defer r.s.lock()()
+	d, err := firstOf(r.s.data.calendarDays, func(d *models.LoginCalendarDay) bool { return d.DayNumber == dayNumber })
+	if err != nil {
+		return nil, err
+	}
+	r.withItem(d)
+	return d, nil
+}
+
+// withItem loads a day's shop item, as the GORM store preloads it
+func (r memLoginRewards) withItem(d *models.LoginCalendarDay) {
+	d.Item = nil
+	if d.ItemID != nil {
+		if item, ok := r.s.data.shopItems[*d.ItemID]; ok {
+			d.Item = &item
+		}
+	}
+}
+
+func (r memLoginRewards) SaveCalendarDay(d *models.LoginCalendarDay) error {
+	defer r.s.lock()()
+	if existing, err := firstOf(r.s.data.calendarDays, func(e *models.LoginCalendarDay) bool { return e.DayNumber == d.DayNumber }); err == nil {
+		d.ID, d.CreatedAt = existing.ID, existing.CreatedAt
+	} else {
+		r.s.id(&d.ID)
+		d.CreatedAt = time.Now()
+	}
+	d.UpdatedAt = time.Now()
+	stored := *d
+	stored.Item = nil
+	r.s.data.calendarDays[d.ID] = stored
+	return nil
+}
+
+func (r memLoginRewards) LastClaim(userID uint) (*models.LoginReward, error) {
+	defer r.s.lock()()
+	claims := sorted(r.s.data.loginClaims, func(c *models.LoginReward) bool { return c.UserID == userID })
+	if len(claims) == 0 {
+		return nil, ErrNotFound
+	}
+	last := slices.MaxFunc(claims, func(a, b models.LoginReward) int { return strings.Compare(a.ClaimDate, b.ClaimDate) })
+	return &last, nil
+}
+
+func (r memLoginRewards) CreateClaim(reward *models.LoginReward) error {
+	defer r.s.lock()()
+	if _, err := firstOf(r.s.data.loginClaims, func(c *models.LoginReward) bool {
+		return c.UserID == reward.UserID && c.ClaimDate == reward.ClaimDate
+	}); err == nil {
+		return ErrDuplicate
+	}
+	r.s.id(&reward.ID)
+	if reward.ClaimedAt.IsZero() {
+		reward.ClaimedAt = time.Now()
+	}
+	r.s.data.loginClaims[reward.ID] = *reward
+	return nil
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"slices"
+	"time"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+)
+
+type memRentals struct{ s *MemoryStore }
+
+func (r memRentals) Get(id uint) (*models.Rental, error) {
+	defer r.s.lock()()
+	return lookup(r.s.data.rentals, id)
+}
+
+func (r memRentals) Lock(id uint) (*models.Rental, error) { return r.Get(id) }
+
+func (r memRentals) Create(rental *models.Rental) error {
+	defer r.s.lock()()
+	r.s.id(&rental.ID)
+	rental.CreatedAt, rental.UpdatedAt = time.Now(), time.Now()
+	r.s.data.rentals[rental.ID] = *rental
+	return nil
+}
+
+func (r memRentals) Save(rental *models.Rental) error {
+	defer r.s.lock()()
+	r.s.id(&rental.ID)
+	rental.UpdatedAt = time.Now()
+	r.s.data.rentals[rental.ID] = *rental
+	return nil
+}
+
+func (r memRentals) Open(characterID uint) (*models.Rental, error) {
+	defer r.s.lock()()
+	return firstOf(r.s.data.rentals, func(rental *models.Rental) bool {
+		return rental.CharacterID == characterID &&
+			(rental.Status == models.RentalStatusOffered || rental.Status == models.RentalStatusActive)
+	})
+}
+
+func (r memRentals) ListOffers(userID uint) ([]models.Rental, error) {
+	defer r.s.lock()()
+	return r.withCharacters(sorted(r.s.data.rentals, func(rental *models.Rental) bool {
+		return rental.Status == models.RentalStatusOffered && rental.OwnerID != userID &&
+			(rental.BorrowerID == nil || *rental.BorrowerID == userID)
+	})), nil
+}
+
+func (r memRentals) ListForUser(userID uint) ([]models.Rental, error) {
+	defer r.s.lock()()
+	return r.withCharacters(sorted(r.s.data.rentals, func(rental *models.Rental) bool {
+		return rental.OwnerID == userID || (rental.BorrowerID != nil && *rental.BorrowerID == userID)
+	})), nil
+}
+
+func (r memRentals) ListDue(now time.Time) ([]models.Rental, error) {
+	defer r.s.lock()()
+	return sorted(r.s.data.rentals, func(rental *models.Rental) bool {
+		return rental.Status == models.RentalStatusActive && rental.EndsAt != nil && !rental.EndsAt.After(now)
+	}), nil
+}
+
+// withCharacters fills in the characters and puts the newest rental first
+func (r memRentals) withCharacters(rentals []models.Rental) []models.Rental {
+	for i := range rentals {
+		rentals[i].Character = r.s.data.characters[rentals[i].CharacterID]
+	}
+	slices.Reverse(rentals)
+	return rentals
+}
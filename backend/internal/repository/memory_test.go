@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+)
+
+func TestMemoryTransactionRollsBack(t *testing.T) {
+	st := NewMemoryStore()
+	user := &models.User{ELO: 1000}
+	if err := st.Users().Create(user); err != nil {
+		t.Fatal(err)
+	}
+
+	boom := errors.New("boom")
+	err := st.Transaction(func(tx Store) error {
+		u, err := tx.Users().Get(user.ID)
+		if err != nil {
+			return err
+		}
+		u.ELO = 2000
+		if err := tx.Users().Save(u); err != nil {
+			return err
+		}
+		if err := tx.Characters().Create(&models.Character{OwnerID: user.ID}); err != nil {
+			return err
+		}
+		return boom
+	})
+	if !errors.Is(err, boom) {
+		t.Fatalf("err = %v, want boom", err)
+	}
+
+	got, _ := st.Users().Get(user.ID)
+	if got.ELO != 1000 {
+		t.Fatalf("ELO = %d, want rollback to 1000", got.ELO)
+	}
+	if n, _ := st.Characters().CountAlive(user.ID); n != 0 {
+		t.Fatalf("%d characters survived the rollback", n)
+	}
+}
+
+func TestMemoryNestedTransactionActsAsSavepoint(t *testing.T) {
+	st := NewMemoryStore()
+	err := st.Transaction(func(tx Store) error {
+		if err := tx.Users().Create(&models.User{ELO: 1}); err != nil {
+			return err
+		}
+		_ = tx.Transaction(func(inner Store) error {
+			_ = inner.Users().Create(&models.User{ELO: 2})
+			return errors.New("inner failure")
+		})
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.Users().RandomOpponent(0, 1, 1); err != nil {
+		t.Fatalf("outer write lost: %v", err)
+	}
+	if _, err := st.Users().RandomOpponent(0, 2, 2); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("inner write kept after its rollback: %v", err)
+	}
+}
+
+func TestMemoryAssetListingGuards(t *testing.T) {
+	st := NewMemoryStore()
+	item := &models.Item{OwnerID: 7, IsEquipped: true}
+	if err := st.Items().Create(item); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.Items().MarkListed(item.ID, 7, time.Now()); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("equipped item listed: err = %v", err)
+	}
+	if err := st.Items().Transfer(item.ID, 8, 9); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("transfer by non-owner: err = %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"slices"
+	"time"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+)
+
+type memTrades struct{ s *MemoryStore }
+
+func (r memTrades) Get(id uint) (*models.Trade, error) {
+	defer r.s.lock()()
+	t, err := lookup(r.s.data.playerTrades, id)
+	if err != nil {
+		return nil, err
+	}
+	r.withAssets(t)
+	return t, nil
+}
+
+func (r memTrades) Lock(id uint) (*models.Trade, error) { return r.Get(id) }
+
+func (r memTrades) Create(t *models.Trade) error {
+	defer r.s.lock()()
+	r.s.id(&t.ID)
+	t.CreatedAt, t.UpdatedAt = time.Now(), time.Now()
+	stored := *t
+	stored.Assets = nil
+	r.s.data.playerTrades[t.ID] = stored
+	return nil
+}
+
+func (r memTrades) Save(t *models.Trade) error {
+	defer r.s.lock()()
+	r.s.id(&t.ID)
+	t.UpdatedAt = time.Now()
+	stored := *t
+	stored.Assets = nil
+	r.s.data.playerTrades[t.ID] = stored
+	return nil
+}
+
+func (r memTrades) SetAssets(tradeID, fromUserID uint, assets []models.TradeAsset) error {
+	defer r.s.lock()()
+	for id, a := range r.s.data.tradeAssets {
+		if a.TradeID == tradeID && a.FromUserID == fromUserID {
+			delete(r.s.data.tradeAssets, id)
+		}
+	}
+	for i := range assets {
+		assets[i].TradeID, assets[i].FromUserID = tradeID, fromUserID
+		r.s.id(&assets[i].ID)
+		r.s.data.tradeAssets[assets[i].ID] = assets[i]
+	}
+	return nil
+}
+
+func (r memTrades) ListForUser(userID uint, status string) ([]models.Trade, error) {
+	defer r.s.lock()()
+	trades := sorted(r.s.data.playerTrades, func(t *models.Trade) bool {
+		return (t.InitiatorID == userID || t.CounterpartyID == userID) && (status == "" || t.Status == status)
+	})
+	for i := range trades {
+		r.withAssets(&trades[i])
+	}
+	slices.Reverse(trades)
+	return trades, nil
+}
+
+func (r memTrades) ListExpired(now time.Time) ([]models.Trade, error) {
+	defer r.s.lock()()
+	return sorted(r.s.data.playerTrades, func(t *models.Trade) bool {
+		return t.Status == models.TradeStatusOpen && !t.ExpiresAt.After(now)
+	}), nil
+}
+
+// withAssets fills in the assets of a trade
+func (r memTrades) withAssets(t *models.Trade) {
+	t.Assets = sorted(r.s.data.tradeAssets, func(a *models.TradeAsset) bool { return a.TradeID == t.ID })
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"slices"
+	"time"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+)
+
+type memWithdrawals struct{ s *MemoryStore }
+
+func (r memWithdrawals) Get(id uint) (*models.Withdrawal, error) {
+	defer r.s.lock()()
+	return lookup(r.s.data.withdrawals, id)
+}
+
+func (r memWithdrawals) Lock(id uint) (*models.Withdrawal, error) { return r.Get(id) }
+
+func (r memWithdrawals) Create(w *models.Withdrawal) error {
+	defer r.s.lock()()
+	r.s.id(&w.ID)
+	w.CreatedAt, w.UpdatedAt = time.Now(), time.Now()
+	r.s.data.withdrawals[w.ID] = *w
+	return nil
+}
+
+func (r memWithdrawals) Save(w *models.Withdrawal) error {
+	defer r.s.lock()()
+	r.s.id(&w.ID)
+	w.UpdatedAt = time.Now()
+	r.s.data.withdrawals[w.ID] = *w
+	return nil
+}
+
+func (r memWithdrawals) ClaimApproved(limit int) ([]models.Withdrawal, error) {
+	defer r.s.lock()()
+	queue := sorted(r.s.data.withdrawals, func(w *models.Withdrawal) bool { return w.Status == models.WithdrawalApproved })
+	return queue[:min(limit, len(queue))], nil
+}
+
+func (r memWithdrawals) NextNonce(pending uint64) (uint64, error) {
+	defer r.s.lock()()
+	next := pending
+	for _, w := range r.s.data.withdrawals {
+		if w.Nonce != nil && *w.Nonce >= next {
+			next = *w.Nonce + 1
+		}
+	}
+	return next, nil
+}
+
+func (r memWithdrawals) ListByStatus(statuses ...string) ([]models.Withdrawal, error) {
+	defer r.s.lock()()
+	out := sorted(r.s.data.withdrawals, func(w *models.Withdrawal) bool { return slices.Contains(statuses, w.Status) })
+	slices.SortStableFunc(out, func(a, b models.Withdrawal) int {
+		switch {
+		case a.Nonce == nil || b.Nonce == nil || *a.Nonce == *b.Nonce:
+			return 0
+		case *a.Nonce < *b.Nonce:
+			return -1
+		}
+		return 1
+	})
+	return out, nil
+}
+
+func (r memWithdrawals) List(status string, limit int) ([]models.Withdrawal, error) {
+	defer r.s.lock()()
+	return newest(sorted(r.s.data.withdrawals, func(w *models.Withdrawal) bool {
+		return status == "" || w.Status == status
+	}), limit), nil
+}
+
+func (r memWithdrawals) ListForUser(userID uint, limit int) ([]models.Withdrawal, error) {
+	defer r.s.lock()()
+	return newest(sorted(r.s.data.withdrawals, func(w *models.Withdrawal) bool { return w.UserID == userID }), limit), nil
+}
+
+func (r memWithdrawals) MarkOnChain(transactionID uint, txHash string, chainID int) error {
+	defer r.s.lock()()
+	t, ok := r.s.data.walletTxs[transactionID]
+	if !ok {
+		return nil
+	}
+	t.BlockchainTxHash, t.ChainID, t.IsOnChain = &txHash, chainID, true
+	r.s.data.walletTxs[transactionID] = t
+	return nil
+}
+
+func (r memWithdrawals) Refund(w *models.Withdrawal, reason string) error {
+	defer r.s.lock()()
+	user, err := lookup(r.s.data.users, w.UserID)
+	if err != nil {
+		return err
+	}
+	refund := refundTransaction(w, user.TOWERBalance, reason)
+	user.TOWERBalance += w.Amount
+	r.s.data.users[user.ID] = *user
+	r.s.id(&refund.ID)
+	r.s.data.walletTxs[refund.ID] = *refund
+	return nil
+}
+
+// newest puts the highest IDs first and keeps at most limit of them
+func newest(withdrawals []models.Withdrawal, limit int) []models.Withdrawal {
+	slices.Reverse(withdrawals)
+	return withdrawals[:min(limit, len(withdrawals))]
+}
@@ -0,0 +1,329 @@
+// Package repository is the persistence boundary of the game services.
+//
+// Each aggregate gets an interface with a GORM implementation (NewGormStore) for production and
+// an in-memory implementation (NewMemoryStore) for tests. Services hold a Store and open
+// transactions through it instead of reaching for the global db.DB.
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+)
+
+// ErrNotFound is returned when a lookup or conditional update matches no row
+var ErrNotFound = errors.New("record not found")
+
+// ErrDuplicate is returned when a create would break a uniqueness rule
+var ErrDuplicate = errors.New("duplicate record")
+
+// Store groups the repositories of one database scope. The Store passed to a Transaction
+// callback is bound to that transaction; reads of lockable rows take row locks there.
+type Store interface {
+	Characters() CharacterRepository
+	Items() ItemRepository
+	Eggs() EggRepository
+	Users() UserRepository
+	Inventory() InventoryRepository
+	Battles() BattleRepository
+	Ledger() LedgerRepository
+	Listings() ListingRepository
+	Crafting() CraftingRepository
+	Equipment() EquipmentRepository
+	Provenance() ProvenanceRepository
+	Rentals() RentalRepository
+	Trades() TradeRepository
+	LoginRewards() LoginRewardRepository
+	Withdrawals() WithdrawalRepository
+
+	// WithContext returns a Store whose queries carry ctx (tracing, cancellation)
+	WithContext(ctx context.Context) Store
+	// Transaction runs fn atomically; returning an error rolls everything back
+	Transaction(fn func(tx Store) error) error
+}
+
+// AssetRepository covers the ownership and listing operations shared by tradable assets
+type AssetRepository interface {
+	// Owner returns the owner of a live asset
+	Owner(id uint) (uint, error)
+	// MarkListed flags an asset owned by ownerID as listed; ErrNotFound if it is not theirs,
+	// already listed, (items) equipped or (characters) rented out
+	MarkListed(id, ownerID uint, at time.Time) error
+	// SetListed sets the listed flag unconditionally
+	SetListed(id uint, listed bool) error
+	// Transfer hands the asset to toUserID and clears the listed flag; ErrNotFound if fromUserID
+	// no longer owns it or (characters) it is rented out
+	Transfer(id, fromUserID, toUserID uint) error
+}
+
+// CharacterRepository persists characters and answers the team queries battles need
+type CharacterRepository interface {
+	AssetRepository
+	Get(id uint) (*models.Character, error)
+	// Lock reads a character for update
+	Lock(id uint) (*models.Character, error)
+	Create(c *models.Character) error
+	Save(c *models.Character) error
+	// FirstAlive returns the first non-fainted character of a player
+	FirstAlive(ownerID uint) (*models.Character, error)
+	// CountAlive counts a player's non-fainted characters
+	CountAlive(ownerID uint) (int64, error)
+	// ActiveTeam returns the characters of the player's active team, in slot order
+	ActiveTeam(userID uint) ([]models.Character, error)
+	// RemoveFromTeams takes a character out of every team of a player
+	RemoveFromTeams(characterID, userID uint) error
+}
+
+// ItemRepository persists equipment, consumables and material stacks
+type ItemRepository interface {
+	AssetRepository
+	Get(id uint) (*models.Item, error)
+	// Lock reads an item for update
+	Lock(id uint) (*models.Item, error)
+	Create(item *models.Item) error
+	Save(item *models.Item) error
+	Delete(id uint) error
+	// LockMaterials locks a player's unlisted MATERIAL stacks of one name, oldest first
+	LockMaterials(ownerID uint, name string) ([]models.Item, error)
+}
+
+// EggRepository persists eggs
+type EggRepository interface {
+	Get(id uint) (*models.Egg, error)
+	Create(egg *models.Egg) error
+	Save(egg *models.Egg) error
+	// Lock reads an egg for update
+	Lock(id uint) (*models.Egg, error)
+	// ListUnhatched returns a player's eggs that have not hatched yet, newest first
+	ListUnhatched(userID uint) ([]models.Egg, error)
+	// HatchedInto returns the egg a character hatched from
+	HatchedInto(characterID uint) (*models.Egg, error)
+	// Transfer hands the egg to toUserID; ErrNotFound if fromUserID no longer owns it
+	Transfer(id, fromUserID, toUserID uint) error
+	// Care returns the settled care windows of an egg, in the order they were settled
+	Care(eggID uint) ([]models.EggCare, error)
+	// AddCare records how a care window ended
+	AddCare(c *models.EggCare) error
+}
+
+// UserRepository persists player profiles
+type UserRepository interface {
+	Get(id uint) (*models.User, error)
+	// Lock reads a user for update
+	Lock(id uint) (*models.User, error)
+	Create(u *models.User) error
+	Save(u *models.User) error
+	// RandomOpponent picks a random player other than excludeID with an ELO in [minELO, maxELO]
+	RandomOpponent(excludeID uint, minELO, maxELO int) (*models.User, error)
+	// AdjustLegacyTokens keeps the pre-ledger users.tokens column in sync
+	AdjustLegacyTokens(id uint, delta int64) error
+}
+
+// InventoryRepository persists consumable stacks and the shop catalogue
+type InventoryRepository interface {
+	Find(userID, shopItemID uint) (*models.UserInventory, error)
+	Save(inv *models.UserInventory) error
+	Delete(inv *models.UserInventory) error
+	ShopItem(id uint) (*models.ShopItem, error)
+}
+
+// BattleRepository persists battles
+type BattleRepository interface {
+	Get(id uint) (*models.Battle, error)
+	Create(b *models.Battle) error
+	Save(b *models.Battle) error
+	Delete(b *models.Battle) error
+	// FindInProgress returns a battle the player is queued for or fighting in
+	FindInProgress(userID uint) (*models.Battle, error)
+	// LockOpenWager locks the oldest SEARCHING wager created by someone other than userID
+	LockOpenWager(userID uint) (*models.Battle, error)
+	// LockWagerSearch locks the player's own SEARCHING wager
+	LockWagerSearch(userID uint) (*models.Battle, error)
+	// ListStale returns active battles not updated since before
+	ListStale(before time.Time) ([]models.Battle, error)
+	// History returns a player's completed battles, newest first
+	History(userID uint, limit int) ([]models.Battle, error)
+}
+
+// LedgerRepository persists double-entry accounts, transactions and entries
+type LedgerRepository interface {
+	// FindAccount looks up a user account (userID set) or a system account (userID nil)
+	FindAccount(userID *uint, accType models.AccountType, currency string) (*models.LedgerAccount, error)
+	FindGuildAccount(guildID uint, currency string) (*models.LedgerAccount, error)
+	CreateAccount(acc *models.LedgerAccount) error
+	// LockAccount reads an account for update
+	LockAccount(id uint) (*models.LedgerAccount, error)
+	SaveAccount(acc *models.LedgerAccount) error
+	CreateTransaction(tx *models.LedgerTransaction) error
+	CreateEntry(e *models.LedgerEntry) error
+}
+
+// ListingFilter narrows the public marketplace feed
+type ListingFilter struct {
+	AssetType   string
+	ListingType string
+	Limit       int
+	Offset      int
+}
+
+// ListingRepository persists the marketplace: listings and their bundle assets, bids, offers and trade history
+type ListingRepository interface {
+	Get(id uint) (*models.MarketplaceListing, error)
+	// Lock reads a listing for update
+	Lock(id uint) (*models.MarketplaceListing, error)
+	Create(l *models.MarketplaceListing) error
+	Save(l *models.MarketplaceListing) error
+	// ActiveForAsset locks the ACTIVE fixed or auction listings of one character or item
+	ActiveForAsset(assetType string, assetID uint) ([]models.MarketplaceListing, error)
+	// ListActive returns unexpired ACTIVE listings with their assets and seller, newest first
+	ListActive(f ListingFilter) ([]models.MarketplaceListing, error)
+	ListExpired(now time.Time) ([]models.MarketplaceListing, error)
+
+	AddBundleAsset(a *models.MarketplaceAsset) error
+	BundleAssets(listingID uint) ([]models.MarketplaceAsset, error)
+	// InActiveBundle reports whether an asset sits in an ACTIVE bundle listing
+	InActiveBundle(assetTypes []string, assetID uint) (bool, error)
+
+	CreateBid(b *models.MarketplaceBid) error
+	// SetBidStatus moves every bid of a listing in status from to status to
+	SetBidStatus(listingID uint, from, to string) error
+	// Bids returns up to limit bids of a listing, highest first
+	Bids(listingID uint, limit int) ([]models.MarketplaceBid, error)
+
+	// LockOffer reads an offer for update
+	LockOffer(id uint) (*models.MarketplaceOffer, error)
+	CreateOffer(o *models.MarketplaceOffer) error
+	SaveOffer(o *models.MarketplaceOffer) error
+	// LockPendingOffers locks the PENDING offers on an asset, except excludeID
+	LockPendingOffers(assetType string, assetID, excludeID uint) ([]models.MarketplaceOffer, error)
+	CountPendingOffers(buyerID uint, assetType string, assetID uint) (int64, error)
+	// ListOffers returns offers received (sent=false) or made (sent=true) by a player, newest first
+	ListOffers(userID uint, sent bool, status string) ([]models.MarketplaceOffer, error)
+	ListExpiredOffers(now time.Time) ([]models.MarketplaceOffer, error)
+
+	CreateTrade(t *models.TradeHistory) error
+	// TradedSince reports whether an asset changed hands in a direct player trade at or after since
+	TradedSince(assetType string, assetID uint, since time.Time) (bool, error)
+}
+
+// CraftingRepository persists recipes, salvage yields and crafting queues
+type CraftingRepository interface {
+	Recipe(id uint) (*models.CraftingRecipe, error)
+	// ListRecipes returns the active recipes by name
+	ListRecipes() ([]models.CraftingRecipe, error)
+	SalvageYield(itemType string) (*models.SalvageYield, error)
+
+	CreateJob(j *models.CraftingJob) error
+	SaveJob(j *models.CraftingJob) error
+	// LockJob reads a job for update
+	LockJob(id uint) (*models.CraftingJob, error)
+	// CountActiveJobs counts a player's jobs still in the CRAFTING state
+	CountActiveJobs(userID uint) (int64, error)
+	// ListJobs returns a player's jobs with their recipes, newest first
+	ListJobs(userID uint, limit int) ([]models.CraftingJob, error)
+}
+
+// EquipmentRepository persists gear profiles, character loadouts and socketed runes
+type EquipmentRepository interface {
+	// Lock reads an equipment row for update
+	Lock(id uint) (*models.Equipment, error)
+	// LockForItem reads the equipment row of an item for update
+	LockForItem(itemID uint) (*models.Equipment, error)
+	Create(e *models.Equipment) error
+	Save(e *models.Equipment) error
+
+	// LockLoadout reads a character's slots for update
+	LockLoadout(characterID uint) (*models.CharacterEquipment, error)
+	SaveLoadout(l *models.CharacterEquipment) error
+	// Gear returns the equipment in a character's slots with their items and socketed runes
+	Gear(characterID uint) ([]models.Equipment, error)
+
+	Runes(equipmentID uint) ([]models.EquipmentRune, error)
+	// RuneByItem returns the socket a rune item sits in
+	RuneByItem(runeItemID uint) (*models.EquipmentRune, error)
+	AddRune(r *models.EquipmentRune) error
+	RemoveRune(id uint) error
+}
+
+// ProvenanceRepository appends to and reads character histories. There is deliberately
+// no way to change or remove an entry.
+type ProvenanceRepository interface {
+	Add(e *models.CharacterProvenance) error
+	// List returns a character's history, oldest first
+	List(characterID uint) ([]models.CharacterProvenance, error)
+}
+
+// RentalRepository persists character rentals
+type RentalRepository interface {
+	Get(id uint) (*models.Rental, error)
+	// Lock reads a rental for update
+	Lock(id uint) (*models.Rental, error)
+	Create(r *models.Rental) error
+	Save(r *models.Rental) error
+	// Open returns the offered or active rental of a character
+	Open(characterID uint) (*models.Rental, error)
+	// ListOffers returns the offers a player may accept (open ones and those made to them)
+	// with their characters, newest first
+	ListOffers(userID uint) ([]models.Rental, error)
+	// ListForUser returns the rentals a player lent out or borrowed, newest first
+	ListForUser(userID uint) ([]models.Rental, error)
+	// ListDue returns active rentals whose term is over at now
+	ListDue(now time.Time) ([]models.Rental, error)
+}
+
+// TradeRepository persists direct player trades and the assets each side puts in
+type TradeRepository interface {
+	// Get returns a trade with its assets
+	Get(id uint) (*models.Trade, error)
+	// Lock reads a trade with its assets for update
+	Lock(id uint) (*models.Trade, error)
+	Create(t *models.Trade) error
+	// Save updates a trade; its assets are only changed through SetAssets
+	Save(t *models.Trade) error
+	// SetAssets replaces the assets one side offers in a trade
+	SetAssets(tradeID, fromUserID uint, assets []models.TradeAsset) error
+	// ListForUser returns the trades a player is part of with their assets, newest first
+	ListForUser(userID uint, status string) ([]models.Trade, error)
+	// ListExpired returns OPEN trades whose window closed at now
+	ListExpired(now time.Time) ([]models.Trade, error)
+}
+
+// LoginRewardRepository persists the login calendar and players' daily claims
+type LoginRewardRepository interface {
+	// Calendar returns the calendar days with their items, in day order
+	Calendar() ([]models.LoginCalendarDay, error)
+	CalendarDay(dayNumber int) (*models.LoginCalendarDay, error)
+	// SaveCalendarDay creates or replaces the reward of a calendar day
+	SaveCalendarDay(d *models.LoginCalendarDay) error
+	// LastClaim returns a player's latest claim
+	LastClaim(userID uint) (*models.LoginReward, error)
+	// CreateClaim records a claim; a second claim for the same player and day is ErrDuplicate
+	CreateClaim(r *models.LoginReward) error
+}
+
+// WithdrawalRepository persists on-chain TOWER payouts
+type WithdrawalRepository interface {
+	Get(id uint) (*models.Withdrawal, error)
+	// Lock reads a withdrawal for update
+	Lock(id uint) (*models.Withdrawal, error)
+	Create(w *models.Withdrawal) error
+	Save(w *models.Withdrawal) error
+	// ClaimApproved locks up to limit APPROVED withdrawals in ID order, skipping rows another
+	// worker holds. It also serializes nonce reservation until the transaction ends.
+	ClaimApproved(limit int) ([]models.Withdrawal, error)
+	// NextNonce returns the first hot wallet nonce at or above pending that no withdrawal holds
+	NextNonce(pending uint64) (uint64, error)
+	// ListByStatus returns the withdrawals in any of statuses, in nonce order
+	ListByStatus(statuses ...string) ([]models.Withdrawal, error)
+	// List returns the newest withdrawals, all of them or those of one status
+	List(status string, limit int) ([]models.Withdrawal, error)
+	// ListForUser returns a player's newest withdrawals
+	ListForUser(userID uint, limit int) ([]models.Withdrawal, error)
+	// MarkOnChain records the final hash on the WITHDRAWAL transaction a payout belongs to
+	MarkOnChain(transactionID uint, txHash string, chainID int) error
+	// Refund credits a withdrawal's TOWER back to the player with a WITHDRAWAL_REFUND
+	// transaction. w already carries the status it closes with; saving it is left to the caller.
+	Refund(w *models.Withdrawal, reason string) error
+}
@@ -0,0 +1,458 @@
+package seed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/pkg/logger"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Counts tallies what Apply did with one section
+type Counts struct {
+	Section string
+	Created int
+	Updated int
+	Kept    int // Rows left alone, e.g. settings an admin has changed
+}
+
+var errDryRun = errors.New("dry run")
+
+// Apply upserts every entry of b by its natural key in one transaction, in
+// dependency order. Running it twice with the same bundle changes nothing the
+// second time. With dryRun set the transaction is rolled back, so the counts
+// report what would have happened.
+func Apply(ctx context.Context, db *gorm.DB, b *Bundle, dryRun bool) ([]Counts, error) {
+	if err := Validate(b); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	var counts []Counts
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		a := &applier{tx: tx}
+		steps := []struct {
+			section string
+			run     func(*Bundle, *Counts) error
+		}{
+			{"settings", a.settings},
+			{"abilities", a.abilities},
+			{"ability_learning", a.abilityLearning},
+			{"missions", a.missions},
+			{"dialogues", a.dialogues},
+			{"story_fragments", a.storyFragments},
+			{"quest_templates", a.questTemplates},
+			{"shop_items", a.shopItems},
+			{"login_calendar", a.loginCalendar},
+			{"islands", a.islands},
+			{"loot_tables", a.lootTables},
+			{"salvage", a.salvage},
+			{"recipes", a.recipes},
+		}
+		for _, step := range steps {
+			c := Counts{Section: step.section}
+			if err := step.run(b, &c); err != nil {
+				return fmt.Errorf("%s: %w", step.section, err)
+			}
+			counts = append(counts, c)
+		}
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).Info("content applied", "dry_run", dryRun, "duration", time.Since(start))
+	return counts, nil
+}
+
+type applier struct {
+	tx *gorm.DB
+}
+
+// upsert saves row over the oldest existing row matching the key, or creates it.
+// Every column is written, so a field removed from the content is reset too.
+func upsert[M any](tx *gorm.DB, row *M, id *uint, c *Counts, query string, args ...any) error {
+	var ids []uint
+	if err := tx.Model(new(M)).Where(query, args...).Order("id").Limit(1).Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		// Create skips zero values of fields with a column default (e.g. a
+		// false is_consumable would become true), so write them explicitly after
+		if err := tx.Omit(clause.Associations).Create(row).Error; err != nil {
+			return err
+		}
+		c.Created++
+	} else {
+		*id = ids[0]
+		c.Updated++
+	}
+	return tx.Model(row).Select("*").Omit("id", "created_at", clause.Associations).Updates(row).Error
+}
+
+// lookupID returns the id of the oldest row of M matching the key
+func lookupID[M any](tx *gorm.DB, query string, args ...any) (uint, error) {
+	var ids []uint
+	if err := tx.Model(new(M)).Where(query, args...).Order("id").Limit(1).Pluck("id", &ids).Error; err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, fmt.Errorf("no %T matches %s %v", *new(M), query, args)
+	}
+	return ids[0], nil
+}
+
+// jsonOr encodes v, falling back to empty for nil maps and slices; jsonb
+// columns reject the empty string
+func jsonOr(v any, empty string) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	if s := string(data); s != "null" {
+		return s, nil
+	}
+	return empty, nil
+}
+
+func orDefault(v, def int) int {
+	if v == 0 {
+		return def
+	}
+	return v
+}
+
+func (a *applier) settings(b *Bundle, c *Counts) error {
+	for _, s := range b.Settings {
+		var existing models.SystemSetting
+		err := a.tx.Where("key = ?", s.Key).Take(&existing).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			row := models.SystemSetting{Key: s.Key, Value: s.Value, Type: s.Type, Description: s.Description}
+			if err := a.tx.Create(&row).Error; err != nil {
+				return err
+			}
+			c.Created++
+		case err != nil:
+			return err
+		case existing.UpdatedBy != 0:
+			c.Kept++
+		default:
+			if err := a.tx.Model(&existing).Updates(map[string]any{
+				"value": s.Value, "type": s.Type, "description": s.Description,
+			}).Error; err != nil {
+				return err
+			}
+			c.Updated++
+		}
+	}
+	return nil
+}
+
+func (a *applier) abilities(b *Bundle, c *Counts) error {
+	for _, s := range b.Abilities {
+		bonuses, err := jsonOr(s.ElementBonuses, "{}")
+		if err != nil {
+			return err
+		}
+		damageType := s.DamageType
+		if damageType == "" {
+			damageType = "physical"
+		}
+		row := models.Ability{
+			Name: s.Name, Description: s.Description, Class: s.Class, Rarity: s.Rarity,
+			UnlockLevel: s.UnlockLevel, AbilityType: s.AbilityType, TargetType: s.TargetType,
+			Element: s.Element, Category: s.Category, DamageType: damageType,
+			Damage: s.Damage, BaseDamage: s.BaseDamage, BaseHeal: s.BaseHeal,
+			Accuracy: orDefault(s.Accuracy, 100), Priority: s.Priority,
+			ManaCost: s.ManaCost, Cooldown: s.Cooldown, MaxPP: orDefault(s.MaxPP, 10),
+			DurationSecs: s.DurationSecs, EffectPower: s.EffectPower,
+			AppliesBuff: s.AppliesBuff, AppliesDebuff: s.AppliesDebuff,
+			BuffDuration: s.BuffDuration, DebuffDuration: s.DebuffDuration,
+			StatusEffectChance: s.StatusEffectChance, AOERadius: s.AOERadius,
+			IsUltimate:      s.IsUltimate,
+			SynergyTags:     pq.StringArray(s.SynergyTags),
+			RequiredElement: pq.StringArray(s.RequiredElement),
+			RequiredClass:   pq.StringArray(s.RequiredClass),
+			ElementBonuses:  bonuses,
+			IconURL:         s.IconURL, AnimationName: s.AnimationName, SoundEffect: s.SoundEffect,
+		}
+		if err := upsert(a.tx, &row, &row.ID, c, "name = ?", s.Name); err != nil {
+			return fmt.Errorf("%s: %w", s.Name, err)
+		}
+	}
+	return nil
+}
+
+func (a *applier) abilityLearning(b *Bundle, c *Counts) error {
+	for _, s := range b.AbilityLearning {
+		abilityID, err := lookupID[models.Ability](a.tx, "name = ?", s.Ability)
+		if err != nil {
+			return err
+		}
+		row := models.AbilityLearning{
+			AbilityID: abilityID, MinRank: s.MinRank, LearnLevel: s.LearnLevel,
+			IsStarting: s.IsStarting, IsUltimate: s.IsUltimate,
+		}
+		if s.Prerequisite != "" {
+			prereq, err := lookupID[models.Ability](a.tx, "name = ?", s.Prerequisite)
+			if err != nil {
+				return err
+			}
+			row.PrerequisiteAbilityID = &prereq
+		}
+		if err := upsert(a.tx, &row, &row.ID, c, "ability_id = ?", abilityID); err != nil {
+			return fmt.Errorf("%s: %w", s.Ability, err)
+		}
+	}
+	return nil
+}
+
+func (a *applier) missions(b *Bundle, c *Counts) error {
+	for _, s := range b.Missions {
+		objectives := make([]models.Objective, len(s.Objectives))
+		for i, o := range s.Objectives {
+			objectives[i] = models.Objective{Type: o.Type, Target: o.Target, Description: o.Description}
+		}
+		objectivesJSON, err := jsonOr(objectives, "[]")
+		if err != nil {
+			return err
+		}
+		rewardsJSON, err := jsonOr(s.Rewards, "{}")
+		if err != nil {
+			return err
+		}
+		row := models.Mission{
+			Level: s.Level, Name: s.Name, Description: s.Description, Story: s.Story,
+			MissionType: s.MissionType, UnlockFeature: s.UnlockFeature,
+			Objectives: objectivesJSON, Rewards: rewardsJSON,
+			RequiredLevel: s.RequiredLevel, IsActive: !s.Inactive,
+		}
+		if err := upsert(a.tx, &row, &row.ID, c, "level = ?", s.Level); err != nil {
+			return fmt.Errorf("level %d: %w", s.Level, err)
+		}
+	}
+	return nil
+}
+
+func (a *applier) dialogues(b *Bundle, c *Counts) error {
+	for _, s := range b.Dialogues {
+		row := models.StoryDialogue{
+			MissionLevel: s.MissionLevel, DialogueType: s.DialogueType, Character: s.Character,
+			DialogueText: s.Text, AudioFile: s.AudioFile, SortOrder: s.SortOrder,
+		}
+		if err := upsert(a.tx, &row, &row.ID, c,
+			`mission_level = ? AND dialogue_type = ? AND "character" = ? AND sort_order = ?`,
+			s.MissionLevel, s.DialogueType, s.Character, s.SortOrder); err != nil {
+			return fmt.Errorf("%s: %w", s.key(), err)
+		}
+	}
+	return nil
+}
+
+func (a *applier) storyFragments(b *Bundle, c *Counts) error {
+	for _, s := range b.StoryFragments {
+		condition, err := jsonOr(s.UnlockCondition, "{}")
+		if err != nil {
+			return err
+		}
+		row := models.StoryFragment{
+			FragmentID: s.FragmentID, Title: s.Title, Content: s.Content, FragmentType: s.FragmentType,
+			UnlockLevel: s.UnlockLevel, UnlockCondition: condition, Rarity: s.Rarity,
+		}
+		if err := upsert(a.tx, &row, &row.ID, c, "fragment_id = ?", s.FragmentID); err != nil {
+			return fmt.Errorf("%s: %w", s.FragmentID, err)
+		}
+	}
+	return nil
+}
+
+func (a *applier) questTemplates(b *Bundle, c *Counts) error {
+	for _, s := range b.QuestTemplates {
+		row := models.QuestTemplate{
+			Type: s.Type, Name: s.Name, Description: s.Description,
+			BaseTarget: s.BaseTarget, ScaleFactor: s.ScaleFactor, Difficulty: s.Difficulty,
+			ActionType: s.ActionType, RewardGTK: s.RewardGTK, RewardTOWER: s.RewardTOWER,
+		}
+		if err := upsert(a.tx, &row, &row.ID, c, "name = ?", s.Name); err != nil {
+			return fmt.Errorf("%s: %w", s.Name, err)
+		}
+	}
+	return nil
+}
+
+func (a *applier) shopItems(b *Bundle, c *Counts) error {
+	for _, s := range b.ShopItems {
+		row := models.ShopItem{
+			Name: s.Name, Description: s.Description, Category: s.Category,
+			EffectType: s.EffectType, EffectValue: s.EffectValue, GTKCost: s.GTKCost,
+			IsConsumable: s.Consumable, MaxStack: orDefault(s.MaxStack, 99),
+			IsAvailable: !s.Unavailable, IconURL: s.IconURL,
+		}
+		if err := upsert(a.tx, &row, &row.ID, c, "name = ?", s.Name); err != nil {
+			return fmt.Errorf("%s: %w", s.Name, err)
+		}
+	}
+	return nil
+}
+
+func (a *applier) loginCalendar(b *Bundle, c *Counts) error {
+	for _, s := range b.LoginCalendar {
+		var existing models.LoginCalendarDay
+		err := a.tx.Where("day_number = ?", s.Day).Take(&existing).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if err == nil && existing.UpdatedBy != 0 {
+			c.Kept++
+			continue
+		}
+
+		row := models.LoginCalendarDay{DayNumber: s.Day, RewardGTK: s.GTK, IsMilestone: s.Milestone}
+		if s.Item != "" {
+			itemID, err := lookupID[models.ShopItem](a.tx, "name = ?", s.Item)
+			if err != nil {
+				return fmt.Errorf("day %d: %w", s.Day, err)
+			}
+			row.ItemID, row.ItemQuantity = &itemID, orDefault(s.Quantity, 1)
+		}
+		if err := upsert(a.tx, &row, &row.ID, c, "day_number = ?", s.Day); err != nil {
+			return fmt.Errorf("day %d: %w", s.Day, err)
+		}
+	}
+	return nil
+}
+
+func (a *applier) islands(b *Bundle, c *Counts) error {
+	for _, s := range b.Islands {
+		island := models.Island{
+			Name: s.Name, Description: s.Description, Difficulty: s.Difficulty,
+			MinLevelReq: orDefault(s.MinLevelReq, 1), ImageURL: s.ImageURL,
+		}
+		if err := upsert(a.tx, &island, &island.ID, c, "name = ?", s.Name); err != nil {
+			return fmt.Errorf("%s: %w", s.Name, err)
+		}
+
+		for _, bs := range s.Bosses {
+			rewards, err := jsonOr(bs.Rewards, "{}")
+			if err != nil {
+				return err
+			}
+			boss := models.RaidBoss{
+				IslandID: island.ID, Name: bs.Name, Element: bs.Element, CharacterType: bs.CharacterType,
+				TotalHP: bs.TotalHP, BaseAttack: bs.BaseAttack, BaseDefense: bs.BaseDefense, Speed: bs.Speed,
+				RewardsPool: rewards, ImageURL: bs.ImageURL,
+			}
+			if err := upsert(a.tx, &boss, &boss.ID, &Counts{}, "island_id = ? AND name = ?", island.ID, bs.Name); err != nil {
+				return fmt.Errorf("%s boss %s: %w", s.Name, bs.Name, err)
+			}
+		}
+
+		for _, ms := range s.Missions {
+			rewards, err := jsonOr(ms.Rewards, "{}")
+			if err != nil {
+				return err
+			}
+			mission := models.IslandMission{
+				IslandID: island.ID, Sequence: ms.Sequence, Name: ms.Name, Description: ms.Description,
+				EnemyName: ms.EnemyName, EnemyType: ms.EnemyType, EnemyHP: ms.EnemyHP,
+				EnemyAtk: ms.EnemyAtk, EnemyDef: ms.EnemyDef, EnemySpeed: ms.EnemySpeed,
+				EnemyImage: ms.EnemyImage, RewardsPool: rewards,
+			}
+			if err := upsert(a.tx, &mission, &mission.ID, &Counts{}, "island_id = ? AND sequence = ?", island.ID, ms.Sequence); err != nil {
+				return fmt.Errorf("%s mission %d: %w", s.Name, ms.Sequence, err)
+			}
+		}
+	}
+	return nil
+}
+
+// lootTables upserts each table and replaces its entries wholesale, since
+// entries have no key of their own
+func (a *applier) lootTables(b *Bundle, c *Counts) error {
+	for _, s := range b.LootTables {
+		islandID, err := lookupID[models.Island](a.tx, "name = ?", s.Island)
+		if err != nil {
+			return err
+		}
+		missionID, err := lookupID[models.IslandMission](a.tx, "island_id = ? AND sequence = ?", islandID, s.Sequence)
+		if err != nil {
+			return err
+		}
+		table := models.LootTable{Name: s.Name, MissionID: &missionID, DropType: s.DropType}
+		if err := upsert(a.tx, &table, &table.ID, c, "name = ?", s.Name); err != nil {
+			return fmt.Errorf("%s: %w", s.Name, err)
+		}
+
+		if err := a.tx.Where("loot_table_id = ?", table.ID).Delete(&models.LootEntry{}).Error; err != nil {
+			return err
+		}
+		for _, es := range s.Entries {
+			itemID, err := lookupID[models.ShopItem](a.tx, "name = ?", es.Item)
+			if err != nil {
+				return err
+			}
+			minQty := orDefault(es.MinQuantity, 1)
+			entry := models.LootEntry{
+				LootTableID: table.ID, ItemID: &itemID, DropChance: es.DropChance,
+				MinQuantity: minQty, MaxQuantity: max(es.MaxQuantity, minQty),
+				RarityWeight: orDefault(es.RarityWeight, 1),
+			}
+			if err := a.tx.Create(&entry).Error; err != nil {
+				return fmt.Errorf("%s entry %s: %w", s.Name, es.Item, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (a *applier) salvage(b *Bundle, c *Counts) error {
+	for _, s := range b.Salvage {
+		materials, err := jsonOr(s.Materials, "[]")
+		if err != nil {
+			return err
+		}
+		row := models.SalvageYield{ItemType: s.ItemType, Materials: materials}
+		if err := upsert(a.tx, &row, &row.ID, c, "item_type = ?", s.ItemType); err != nil {
+			return fmt.Errorf("%s: %w", s.ItemType, err)
+		}
+	}
+	return nil
+}
+
+func (a *applier) recipes(b *Bundle, c *Counts) error {
+	for _, s := range b.Recipes {
+		weights, err := jsonOr(s.RarityWeights, "{}")
+		if err != nil {
+			return err
+		}
+		materials, err := jsonOr(s.Materials, "[]")
+		if err != nil {
+			return err
+		}
+		itemName := s.ItemName
+		if itemName == "" {
+			itemName = s.Name
+		}
+		row := models.CraftingRecipe{
+			Name: s.Name, Description: s.Description, ItemType: s.ItemType, ItemName: itemName,
+			ConsumeEffect: s.ConsumeEffect, GTKCost: s.GTKCost, SuccessChance: s.SuccessChance,
+			CraftMinutes: s.CraftMinutes, RarityWeights: weights, Materials: materials,
+			IsActive: !s.Inactive,
+		}
+		if err := upsert(a.tx, &row, &row.ID, c, "name = ?", s.Name); err != nil {
+			return fmt.Errorf("%s: %w", s.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package seed
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Profiles are the content profiles. Every profile loads base/; dev and test
+// then overlay their own directory, replacing entries with the same key.
+var Profiles = []string{"dev", "test", "prod"}
+
+// Load reads base/*.yaml from fsys and applies the profile overlay
+func Load(fsys fs.FS, profile string) (*Bundle, error) {
+	known := false
+	for _, p := range Profiles {
+		known = known || p == profile
+	}
+	if !known {
+		return nil, fmt.Errorf("unknown content profile %q (want one of %s)", profile, strings.Join(Profiles, ", "))
+	}
+
+	b := &Bundle{}
+	if err := readDir(fsys, "base", b.append); err != nil {
+		return nil, err
+	}
+	if profile == "prod" {
+		return b, nil
+	}
+	if err := readDir(fsys, profile, b.overlay); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// readDir decodes every .yaml file in dir, in name order, and hands it to add.
+// A missing directory is an empty one.
+func readDir(fsys fs.FS, dir string, add func(*Bundle)) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		if dir != "base" && errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("failed to list content %s: %w", dir, err)
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if !e.IsDir() && (strings.HasSuffix(e.Name(), ".yaml") || strings.HasSuffix(e.Name(), ".yml")) {
+			names = append(names, path.Join(dir, e.Name()))
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		f, err := decode(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		add(&f.Bundle)
+	}
+	return nil
+}
+
+// decode parses one content file, rejecting unknown fields so a typo in a key
+// fails loudly instead of silently seeding a zero value
+func decode(data []byte) (*File, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	var f File
+	if err := dec.Decode(&f); err != nil {
+		return nil, err
+	}
+	if f.Version != FormatVersion {
+		return nil, fmt.Errorf("unsupported content version %d (want %d)", f.Version, FormatVersion)
+	}
+	return &f, nil
+}
+
+// append adds every entry of o; duplicates are left for Validate to report
+func (b *Bundle) append(o *Bundle) {
+	b.Abilities = append(b.Abilities, o.Abilities...)
+	b.AbilityLearning = append(b.AbilityLearning, o.AbilityLearning...)
+	b.Missions = append(b.Missions, o.Missions...)
+	b.Dialogues = append(b.Dialogues, o.Dialogues...)
+	b.StoryFragments = append(b.StoryFragments, o.StoryFragments...)
+	b.QuestTemplates = append(b.QuestTemplates, o.QuestTemplates...)
+	b.ShopItems = append(b.ShopItems, o.ShopItems...)
+	b.Settings = append(b.Settings, o.Settings...)
+	b.Islands = append(b.Islands, o.Islands...)
+	b.LootTables = append(b.LootTables, o.LootTables...)
+	b.Recipes = append(b.Recipes, o.Recipes...)
+	b.Salvage = append(b.Salvage, o.Salvage...)
+	b.LoginCalendar = append(b.LoginCalendar, o.LoginCalendar...)
+}
+
+// overlay replaces entries of b that share a key with an entry of o and
+// appends the rest
+func (b *Bundle) overlay(o *Bundle) {
+	b.Abilities = merge(b.Abilities, o.Abilities, func(a Ability) string { return a.Name })
+	b.AbilityLearning = merge(b.AbilityLearning, o.AbilityLearning, func(l AbilityLearning) string { return l.Ability })
+	b.Missions = merge(b.Missions, o.Missions, func(m Mission) string { return strconv.Itoa(m.Level) })
+	b.Dialogues = merge(b.Dialogues, o.Dialogues, Dialogue.key)
+	b.StoryFragments = merge(b.StoryFragments, o.StoryFragments, func(f StoryFragment) string { return f.FragmentID })
+	b.QuestTemplates = merge(b.QuestTemplates, o.QuestTemplates, func(q QuestTemplate) string { return q.Name })
+	b.ShopItems = merge(b.ShopItems, o.ShopItems, func(s ShopItem) string { return s.Name })
+	b.Settings = merge(b.Settings, o.Settings, func(s Setting) string { return s.Key })
+	b.Islands = merge(b.Islands, o.Islands, func(i Island) string { return i.Name })
+	b.LootTables = merge(b.LootTables, o.LootTables, func(t LootTable) string { return t.Name })
+	b.Recipes = merge(b.Recipes, o.Recipes, func(r Recipe) string { return r.Name })
+	b.Salvage = merge(b.Salvage, o.Salvage, func(s SalvageYield) string { return s.ItemType })
+	b.LoginCalendar = merge(b.LoginCalendar, o.LoginCalendar, func(d LoginDay) string { return strconv.Itoa(d.Day) })
+}
+
+func merge[T any](dst, src []T, key func(T) string) []T {
+	index := make(map[string]int, len(dst))
+	for i, v := range dst {
+		index[key(v)] = i
+	}
+	for _, v := range src {
+		if i, ok := index[key(v)]; ok {
+			dst[i] = v
+			continue
+		}
+		index[key(v)] = len(dst)
+		dst = append(dst, v)
+	}
+	return dst
+}
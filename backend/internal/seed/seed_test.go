@@ -0,0 +1,199 @@
+package seed
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/lorengraff/crypto-tower-defense/content"
+)
+
+func file(body string) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte(body)}
+}
+
+const baseAbilities = `version: 1
+abilities:
+  - name: Slash
+    class: Warrior
+    rarity: C
+    unlock_level: 1
+    ability_type: ACTIVE
+    target_type: SINGLE_ENEMY
+    mana_cost: 0
+    cooldown: 0
+  - name: Fireball
+    class: Mage
+    rarity: C
+    unlock_level: 1
+    ability_type: ACTIVE
+    target_type: SINGLE_ENEMY
+    mana_cost: 10
+    cooldown: 1
+`
+
+func TestEmbeddedContentIsValid(t *testing.T) {
+	for _, profile := range Profiles {
+		b, err := Load(content.FS, profile)
+		if err != nil {
+			t.Fatalf("%s: %v", profile, err)
+		}
+		if err := Validate(b); err != nil {
+			t.Fatalf("%s: %v", profile, err)
+		}
+		if len(b.Abilities) == 0 || len(b.Missions) == 0 || len(b.Islands) == 0 || len(b.ShopItems) == 0 {
+			t.Fatalf("%s: bundle is missing sections", profile)
+		}
+	}
+}
+
+func TestOverlayReplacesByKeyAndAppends(t *testing.T) {
+	fsys := fstest.MapFS{
+		"base/abilities.yaml": file(baseAbilities),
+		"dev/abilities.yaml": file(`version: 1
+abilities:
+  - name: Fireball
+    class: Mage
+    rarity: B
+    unlock_level: 3
+    ability_type: ACTIVE
+    target_type: AOE
+    mana_cost: 20
+    cooldown: 2
+  - name: Guard
+    class: Tank
+    rarity: C
+    unlock_level: 1
+    ability_type: PASSIVE
+    target_type: SELF
+    mana_cost: 0
+    cooldown: 0
+`),
+	}
+
+	dev, err := Load(fsys, "dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dev.Abilities) != 3 {
+		t.Fatalf("dev has %d abilities, want 3", len(dev.Abilities))
+	}
+	if a := dev.Abilities[1]; a.Name != "Fireball" || a.Rarity != "B" || a.TargetType != "AOE" {
+		t.Fatalf("Fireball not replaced in place: %+v", a)
+	}
+	if dev.Abilities[2].Name != "Guard" {
+		t.Fatalf("Guard not appended: %+v", dev.Abilities[2])
+	}
+
+	prod, err := Load(fsys, "prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prod.Abilities) != 2 || prod.Abilities[1].Rarity != "C" {
+		t.Fatalf("prod picked up the dev overlay: %+v", prod.Abilities)
+	}
+
+	// test has no overlay directory, so it is base as-is
+	if _, err := Load(fsys, "test"); err != nil {
+		t.Fatalf("missing overlay: %v", err)
+	}
+}
+
+func TestValidateReportsBrokenReferences(t *testing.T) {
+	fsys := fstest.MapFS{
+		"base/abilities.yaml": file(baseAbilities),
+		"base/learning.yaml": file(`version: 1
+ability_learning:
+  - ability: Meteor
+    min_rank: C
+    learn_level: 1
+  - ability: Slash
+    min_rank: Z
+    learn_level: 1
+    prerequisite: Slash
+`),
+		"base/loot.yaml": file(`version: 1
+islands:
+  - name: Rock
+    difficulty: 1
+    missions:
+      - sequence: 1
+        name: First
+        enemy_name: Crab
+        enemy_type: beast
+        enemy_hp: 100
+        enemy_atk: 5
+        enemy_def: 5
+        enemy_speed: 5
+loot_tables:
+  - name: Rock drops
+    island: Rock
+    sequence: 1
+    drop_type: random
+    entries:
+      - item: Golden Shovel
+        drop_chance: 50
+`),
+		"base/login_calendar.yaml": file(`version: 1
+login_calendar:
+  - day: 1
+    item: Silver Ticket
+`),
+		"base/recipes.yaml": file(`version: 1
+salvage:
+  - item_type: WEAPON
+    materials:
+      - name: Iron Scrap
+        quantity: 2
+recipes:
+  - name: Blade
+    item_type: WEAPON
+    success_chance: 0.5
+    rarity_weights: {C: 1}
+    materials:
+      - name: Moon Silver
+        quantity: 1
+`),
+	}
+	b, err := Load(fsys, "prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = Validate(b)
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Validate = %v, want *ValidationError", err)
+	}
+	for _, want := range []string{`"Meteor"`, `"Z"`, "prerequisite", `"Golden Shovel"`, `"Moon Silver"`, `"Silver Ticket"`} {
+		found := false
+		for _, p := range verr.Problems {
+			found = found || strings.Contains(p, want)
+		}
+		if !found {
+			t.Errorf("no problem mentions %s in:\n%v", want, err)
+		}
+	}
+}
+
+func TestLoadRejectsBadFiles(t *testing.T) {
+	cases := map[string]string{
+		"unknown field": "version: 1\nabilities:\n  - name: Slash\n    damge: 10\n",
+		"wrong version": "version: 2\nabilities: []\n",
+		"no version":    "abilities: []\n",
+	}
+	for name, body := range cases {
+		fsys := fstest.MapFS{"base/abilities.yaml": file(body)}
+		if _, err := Load(fsys, "prod"); err == nil {
+			t.Errorf("%s: Load succeeded", name)
+		}
+	}
+}
+
+func TestLoadRejectsUnknownProfile(t *testing.T) {
+	fsys := fstest.MapFS{"base/abilities.yaml": file(baseAbilities)}
+	if _, err := Load(fsys, "staging"); err == nil {
+		t.Fatal("Load accepted an unknown profile")
+	}
+}
@@ -0,0 +1,37 @@
+package seed
+
+import "strconv"
+
+// SettingValues serves the content system_settings defaults through the same
+// getters as services.ConfigService, for tools that run without a database
+type SettingValues map[string]string
+
+// SettingValues returns the bundle's settings by key
+func (b *Bundle) SettingValues() SettingValues {
+	values := make(SettingValues, len(b.Settings))
+	for _, s := range b.Settings {
+		values[s.Key] = s.Value
+	}
+	return values
+}
+
+func (v SettingValues) GetValue(key, defaultValue string) string {
+	if value, ok := v[key]; ok {
+		return value
+	}
+	return defaultValue
+}
+
+func (v SettingValues) GetInt(key string, defaultVal int) int {
+	if n, err := strconv.Atoi(v[key]); err == nil {
+		return n
+	}
+	return defaultVal
+}
+
+func (v SettingValues) GetFloat(key string, defaultVal float64) float64 {
+	if f, err := strconv.ParseFloat(v[key], 64); err == nil {
+		return f
+	}
+	return defaultVal
+}
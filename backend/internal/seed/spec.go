@@ -0,0 +1,276 @@
+// Package seed loads the declarative game content under content/ (abilities,
+// missions, islands, shop items, ...) for a profile, validates it and upserts
+// it into the database.
+package seed
+
+import "fmt"
+
+// FormatVersion is the only content file version this build understands
+const FormatVersion = 1
+
+// Bundle is the complete content set for one profile
+type Bundle struct {
+	Abilities       []Ability         `yaml:"abilities,omitempty"`
+	AbilityLearning []AbilityLearning `yaml:"ability_learning,omitempty"`
+	Missions        []Mission         `yaml:"missions,omitempty"`
+	Dialogues       []Dialogue        `yaml:"dialogues,omitempty"`
+	StoryFragments  []StoryFragment   `yaml:"story_fragments,omitempty"`
+	QuestTemplates  []QuestTemplate   `yaml:"quest_templates,omitempty"`
+	ShopItems       []ShopItem        `yaml:"shop_items,omitempty"`
+	Settings        []Setting         `yaml:"settings,omitempty"`
+	Islands         []Island          `yaml:"islands,omitempty"`
+	LootTables      []LootTable       `yaml:"loot_tables,omitempty"`
+	Recipes         []Recipe          `yaml:"recipes,omitempty"`
+	Salvage         []SalvageYield    `yaml:"salvage,omitempty"`
+	LoginCalendar   []LoginDay        `yaml:"login_calendar,omitempty"`
+}
+
+// File is the on-disk shape of a content file: a version plus any sections
+type File struct {
+	Version int `yaml:"version"`
+	Bundle  `yaml:",inline"`
+}
+
+// Ability is an abilities row, keyed by name
+type Ability struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description,omitempty"`
+	Class       string `yaml:"class"`        // Warrior, Mage, Tank, Archer
+	Rarity      string `yaml:"rarity"`       // C, B, A, S, SS, SSS
+	UnlockLevel int    `yaml:"unlock_level"` // Level the ability unlocks at
+	AbilityType string `yaml:"ability_type"` // ACTIVE, PASSIVE, ULTIMATE
+	TargetType  string `yaml:"target_type"`  // SINGLE_ENEMY, SINGLE_ALLY, SELF, AOE, ALL_ALLIES, CHAIN
+	Element     string `yaml:"element,omitempty"`
+	Category    string `yaml:"category,omitempty"`    // physical, special, status
+	DamageType  string `yaml:"damage_type,omitempty"` // physical (default), magical, true
+
+	Damage     int `yaml:"damage,omitempty"`
+	BaseDamage int `yaml:"base_damage,omitempty"`
+	BaseHeal   int `yaml:"base_heal,omitempty"`
+	Accuracy   int `yaml:"accuracy,omitempty"` // Defaults to 100
+	Priority   int `yaml:"priority,omitempty"`
+	ManaCost   int `yaml:"mana_cost"`
+	Cooldown   int `yaml:"cooldown"`
+	MaxPP      int `yaml:"max_pp,omitempty"` // Defaults to 10
+
+	DurationSecs       int    `yaml:"duration_secs,omitempty"`
+	EffectPower        int    `yaml:"effect_power,omitempty"`
+	AppliesBuff        string `yaml:"applies_buff,omitempty"`
+	AppliesDebuff      string `yaml:"applies_debuff,omitempty"`
+	BuffDuration       int    `yaml:"buff_duration,omitempty"`
+	DebuffDuration     int    `yaml:"debuff_duration,omitempty"`
+	StatusEffectChance int    `yaml:"status_effect_chance,omitempty"`
+	AOERadius          int    `yaml:"aoe_radius,omitempty"`
+
+	IsUltimate      bool               `yaml:"is_ultimate,omitempty"`
+	SynergyTags     []string           `yaml:"synergy_tags,omitempty"`
+	RequiredElement []string           `yaml:"required_element,omitempty"`
+	RequiredClass   []string           `yaml:"required_class,omitempty"`
+	ElementBonuses  map[string]float64 `yaml:"element_bonuses,omitempty"`
+
+	IconURL       string `yaml:"icon_url,omitempty"`
+	AnimationName string `yaml:"animation_name,omitempty"`
+	SoundEffect   string `yaml:"sound_effect,omitempty"`
+}
+
+// AbilityLearning says when a rank can learn an ability, keyed by ability name
+type AbilityLearning struct {
+	Ability      string `yaml:"ability"`
+	MinRank      string `yaml:"min_rank"`
+	LearnLevel   int    `yaml:"learn_level"`
+	IsStarting   bool   `yaml:"is_starting,omitempty"`
+	IsUltimate   bool   `yaml:"is_ultimate,omitempty"`
+	Prerequisite string `yaml:"prerequisite,omitempty"` // Ability name
+}
+
+// Mission is a story mission, keyed by level
+type Mission struct {
+	Level         int         `yaml:"level"`
+	Name          string      `yaml:"name"`
+	Description   string      `yaml:"description,omitempty"`
+	Story         string      `yaml:"story,omitempty"`
+	MissionType   string      `yaml:"mission_type"` // tutorial, progression, daily, weekly
+	UnlockFeature string      `yaml:"unlock_feature,omitempty"`
+	RequiredLevel int         `yaml:"required_level"`
+	Inactive      bool        `yaml:"inactive,omitempty"`
+	Objectives    []Objective `yaml:"objectives"`
+	Rewards       Rewards     `yaml:"rewards"`
+}
+
+// Objective is one goal of a mission
+type Objective struct {
+	Type        string `yaml:"type" json:"type"`
+	Target      int    `yaml:"target" json:"target"`
+	Description string `yaml:"description" json:"description"`
+}
+
+// Rewards is what a mission pays out
+type Rewards struct {
+	GTK   int64        `yaml:"gtk" json:"gtk"`
+	Tower int64        `yaml:"tower,omitempty" json:"tower,omitempty"`
+	Items []RewardItem `yaml:"items,omitempty" json:"items,omitempty"`
+}
+
+// RewardItem is an item granted by a mission
+type RewardItem struct {
+	Type     string `yaml:"type" json:"type"`
+	Name     string `yaml:"name" json:"name"`
+	Quantity int    `yaml:"quantity" json:"quantity"`
+	Rarity   string `yaml:"rarity,omitempty" json:"rarity,omitempty"`
+}
+
+// Dialogue is a line of mission dialogue, keyed by (mission_level, dialogue_type, character, sort_order)
+type Dialogue struct {
+	MissionLevel int    `yaml:"mission_level"`
+	DialogueType string `yaml:"dialogue_type"` // briefing, post_mission, cutscene
+	Character    string `yaml:"character"`
+	SortOrder    int    `yaml:"sort_order"`
+	Text         string `yaml:"text"`
+	AudioFile    string `yaml:"audio_file,omitempty"`
+}
+
+func (d Dialogue) key() string {
+	return fmt.Sprintf("%d/%s/%s/%d", d.MissionLevel, d.DialogueType, d.Character, d.SortOrder)
+}
+
+// StoryFragment is a collectible lore entry, keyed by fragment_id
+type StoryFragment struct {
+	FragmentID      string         `yaml:"fragment_id"`
+	Title           string         `yaml:"title"`
+	Content         string         `yaml:"content"`
+	FragmentType    string         `yaml:"fragment_type,omitempty"`
+	UnlockLevel     int            `yaml:"unlock_level"`
+	UnlockCondition map[string]any `yaml:"unlock_condition,omitempty"`
+	Rarity          string         `yaml:"rarity,omitempty"`
+}
+
+// QuestTemplate is a daily quest template, keyed by name
+type QuestTemplate struct {
+	Name        string  `yaml:"name"`
+	Type        string  `yaml:"type"` // combat, collection, progression, special
+	Description string  `yaml:"description"`
+	ActionType  string  `yaml:"action_type"`
+	BaseTarget  int     `yaml:"base_target"`
+	ScaleFactor float64 `yaml:"scale_factor"`
+	Difficulty  string  `yaml:"difficulty"` // common, uncommon, rare, epic
+	RewardGTK   int     `yaml:"reward_gtk,omitempty"`
+	RewardTOWER int     `yaml:"reward_tower,omitempty"`
+}
+
+// ShopItem is a shop catalogue entry, keyed by name
+type ShopItem struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description,omitempty"`
+	Category    string `yaml:"category"`
+	EffectType  string `yaml:"effect_type,omitempty"`
+	EffectValue int    `yaml:"effect_value,omitempty"`
+	GTKCost     int64  `yaml:"gtk_cost"`
+	Consumable  bool   `yaml:"consumable"`
+	MaxStack    int    `yaml:"max_stack,omitempty"` // Defaults to 99
+	Unavailable bool   `yaml:"unavailable,omitempty"`
+	IconURL     string `yaml:"icon_url,omitempty"`
+}
+
+// Setting is a system_settings default, keyed by key. Values an admin has
+// changed since are left alone.
+type Setting struct {
+	Key         string `yaml:"key"`
+	Value       string `yaml:"value"`
+	Type        string `yaml:"type"` // string, int, float, bool, json
+	Description string `yaml:"description,omitempty"`
+}
+
+// Island is a raid campaign island with its bosses and missions, keyed by name
+type Island struct {
+	Name        string          `yaml:"name"`
+	Description string          `yaml:"description,omitempty"`
+	Difficulty  int             `yaml:"difficulty"`
+	MinLevelReq int             `yaml:"min_level_req,omitempty"`
+	ImageURL    string          `yaml:"image_url,omitempty"`
+	Bosses      []RaidBoss      `yaml:"bosses,omitempty"`
+	Missions    []IslandMission `yaml:"missions"`
+}
+
+// RaidBoss is keyed by name within its island
+type RaidBoss struct {
+	Name          string         `yaml:"name"`
+	Element       string         `yaml:"element"`
+	CharacterType string         `yaml:"character_type"`
+	TotalHP       int64          `yaml:"total_hp"`
+	BaseAttack    int            `yaml:"base_attack"`
+	BaseDefense   int            `yaml:"base_defense"`
+	Speed         int            `yaml:"speed"`
+	Rewards       map[string]any `yaml:"rewards,omitempty"`
+	ImageURL      string         `yaml:"image_url,omitempty"`
+}
+
+// IslandMission is keyed by sequence within its island
+type IslandMission struct {
+	Sequence    int            `yaml:"sequence"`
+	Name        string         `yaml:"name"`
+	Description string         `yaml:"description,omitempty"`
+	EnemyName   string         `yaml:"enemy_name"`
+	EnemyType   string         `yaml:"enemy_type"`
+	EnemyHP     int64          `yaml:"enemy_hp"`
+	EnemyAtk    int            `yaml:"enemy_atk"`
+	EnemyDef    int            `yaml:"enemy_def"`
+	EnemySpeed  int            `yaml:"enemy_speed"`
+	EnemyImage  string         `yaml:"enemy_image,omitempty"`
+	Rewards     map[string]any `yaml:"rewards,omitempty"`
+}
+
+// LootTable is the drop table of one island mission, keyed by name
+type LootTable struct {
+	Name     string      `yaml:"name"`
+	Island   string      `yaml:"island"`
+	Sequence int         `yaml:"sequence"`
+	DropType string      `yaml:"drop_type"` // guaranteed, random, bonus
+	Entries  []LootEntry `yaml:"entries"`
+}
+
+// LootEntry is one possible drop, referencing a shop item by name
+type LootEntry struct {
+	Item         string  `yaml:"item"`
+	DropChance   float64 `yaml:"drop_chance"`            // Percent, 0-100
+	MinQuantity  int     `yaml:"min_quantity,omitempty"` // Defaults to 1
+	MaxQuantity  int     `yaml:"max_quantity,omitempty"` // Defaults to min_quantity
+	RarityWeight int     `yaml:"rarity_weight,omitempty"`
+}
+
+// Recipe turns MATERIAL items and GTK into an item, keyed by name
+type Recipe struct {
+	Name          string           `yaml:"name"`
+	Description   string           `yaml:"description,omitempty"`
+	ItemType      string           `yaml:"item_type"`                // WEAPON, ARMOR, ACCESSORY, RUNE, CONSUMABLE
+	ItemName      string           `yaml:"item_name,omitempty"`      // Defaults to name
+	ConsumeEffect string           `yaml:"consume_effect,omitempty"` // Consumables only
+	GTKCost       int64            `yaml:"gtk_cost"`
+	SuccessChance float64          `yaml:"success_chance"` // 0-1
+	CraftMinutes  int              `yaml:"craft_minutes"`
+	RarityWeights map[string]int   `yaml:"rarity_weights"` // Rarity roll of the crafted item
+	Materials     []MaterialAmount `yaml:"materials"`
+	Inactive      bool             `yaml:"inactive,omitempty"`
+}
+
+// MaterialAmount is a quantity of a MATERIAL item, referenced by name
+type MaterialAmount struct {
+	Name     string `yaml:"name" json:"name"`
+	Quantity int    `yaml:"quantity" json:"quantity"`
+}
+
+// SalvageYield is what salvaging equipment of one type gives back, keyed by
+// item_type. Quantities are for rarity C; higher rarities yield more.
+type SalvageYield struct {
+	ItemType  string           `yaml:"item_type"`
+	Materials []MaterialAmount `yaml:"materials"`
+}
+
+// LoginDay is what one day of the login calendar pays, keyed by day. Days an
+// admin has changed since are left alone.
+type LoginDay struct {
+	Day       int    `yaml:"day"`
+	GTK       int64  `yaml:"gtk,omitempty"`
+	Item      string `yaml:"item,omitempty"`     // Shop item name
+	Quantity  int    `yaml:"quantity,omitempty"` // Defaults to 1 with an item
+	Milestone bool   `yaml:"milestone,omitempty"`
+}
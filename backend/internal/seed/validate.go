@@ -0,0 +1,412 @@
+package seed
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+func set(values ...string) map[string]bool {
+	m := make(map[string]bool, len(values))
+	for _, v := range values {
+		m[v] = true
+	}
+	return m
+}
+
+var (
+	ranks             = set("C", "B", "A", "S", "SS", "SSS")
+	classes           = set("Warrior", "Mage", "Tank", "Archer")
+	abilityTypes      = set("ACTIVE", "PASSIVE", "ULTIMATE")
+	targetTypes       = set("SINGLE_ENEMY", "SINGLE_ALLY", "SELF", "AOE", "ALL_ALLIES", "CHAIN")
+	damageTypes       = set("", "physical", "magical", "true")
+	abilityCategories = set("", "physical", "special", "status")
+	missionTypes      = set("tutorial", "progression", "daily", "weekly")
+	dialogueTypes     = set("briefing", "post_mission", "cutscene")
+	questTypes        = set("combat", "collection", "progression", "special")
+	questDifficulties = set("common", "uncommon", "rare", "epic")
+	settingTypes      = set("string", "int", "float", "bool", "json")
+	dropTypes         = set("guaranteed", "random", "bonus")
+	craftedTypes      = set("WEAPON", "ARMOR", "ACCESSORY", "RUNE", "CONSUMABLE")
+	equipmentTypes    = set("WEAPON", "ARMOR", "ACCESSORY", "RUNE")
+	consumeEffects    = set("REVIVE", "REDUCE_FATIGUE", "XP_BOOST", "REPAIR")
+)
+
+// ValidationError lists every problem found in a bundle
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%d content problems:\n  %s", len(e.Problems), strings.Join(e.Problems, "\n  "))
+}
+
+type checker struct {
+	problems []string
+}
+
+func (c *checker) addf(format string, args ...any) {
+	c.problems = append(c.problems, fmt.Sprintf(format, args...))
+}
+
+// require reports an empty field or a value outside allowed (when given)
+func (c *checker) require(where, field, value string, allowed map[string]bool) {
+	switch {
+	case value == "" && (allowed == nil || !allowed[""]):
+		c.addf("%s: %s is required", where, field)
+	case allowed != nil && !allowed[value]:
+		c.addf("%s: unknown %s %q", where, field, value)
+	}
+}
+
+func (c *checker) maxLen(where, field, value string, n int) {
+	if len(value) > n {
+		c.addf("%s: %s is longer than %d characters", where, field, n)
+	}
+}
+
+func (c *checker) atLeast(where, field string, value, min int64) {
+	if value < min {
+		c.addf("%s: %s must be at least %d, got %d", where, field, min, value)
+	}
+}
+
+// unique reports a key seen before in the same section
+func (c *checker) unique(seen map[string]bool, where, key string) {
+	if seen[key] {
+		c.addf("%s: duplicate key %q", where, key)
+	}
+	seen[key] = true
+}
+
+// Validate checks field values and every cross-reference in b, returning a
+// *ValidationError listing all problems found
+func Validate(b *Bundle) error {
+	c := &checker{}
+
+	abilities := make(map[string]bool)
+	for i, a := range b.Abilities {
+		where := fmt.Sprintf("abilities[%d] (%s)", i, a.Name)
+		c.require(where, "name", a.Name, nil)
+		c.maxLen(where, "name", a.Name, 50)
+		c.unique(abilities, where, a.Name)
+		c.require(where, "class", a.Class, classes)
+		c.require(where, "rarity", a.Rarity, ranks)
+		c.require(where, "ability_type", a.AbilityType, abilityTypes)
+		c.require(where, "target_type", a.TargetType, targetTypes)
+		c.require(where, "damage_type", a.DamageType, damageTypes)
+		c.require(where, "category", a.Category, abilityCategories)
+		c.atLeast(where, "unlock_level", int64(a.UnlockLevel), 1)
+		for _, f := range []struct {
+			name  string
+			value int
+		}{
+			{"damage", a.Damage}, {"base_damage", a.BaseDamage}, {"base_heal", a.BaseHeal},
+			{"mana_cost", a.ManaCost}, {"cooldown", a.Cooldown}, {"max_pp", a.MaxPP},
+			{"duration_secs", a.DurationSecs}, {"buff_duration", a.BuffDuration},
+			{"debuff_duration", a.DebuffDuration}, {"aoe_radius", a.AOERadius},
+		} {
+			c.atLeast(where, f.name, int64(f.value), 0)
+		}
+		if a.Accuracy < 0 || a.Accuracy > 100 {
+			c.addf("%s: accuracy must be between 0 and 100, got %d", where, a.Accuracy)
+		}
+		if a.StatusEffectChance < 0 || a.StatusEffectChance > 100 {
+			c.addf("%s: status_effect_chance must be between 0 and 100, got %d", where, a.StatusEffectChance)
+		}
+	}
+
+	learned := make(map[string]bool)
+	for i, l := range b.AbilityLearning {
+		where := fmt.Sprintf("ability_learning[%d] (%s)", i, l.Ability)
+		c.require(where, "ability", l.Ability, nil)
+		c.unique(learned, where, l.Ability)
+		if l.Ability != "" && !abilities[l.Ability] {
+			c.addf("%s: ability %q is not defined", where, l.Ability)
+		}
+		c.require(where, "min_rank", l.MinRank, ranks)
+		c.atLeast(where, "learn_level", int64(l.LearnLevel), 1)
+		if l.Prerequisite != "" {
+			if !abilities[l.Prerequisite] {
+				c.addf("%s: prerequisite %q is not defined", where, l.Prerequisite)
+			}
+			if l.Prerequisite == l.Ability {
+				c.addf("%s: ability cannot be its own prerequisite", where)
+			}
+		}
+	}
+
+	missions := make(map[string]bool)
+	for i, m := range b.Missions {
+		where := fmt.Sprintf("missions[%d] (level %d)", i, m.Level)
+		c.atLeast(where, "level", int64(m.Level), 1)
+		c.unique(missions, where, strconv.Itoa(m.Level))
+		c.require(where, "name", m.Name, nil)
+		c.maxLen(where, "name", m.Name, 100)
+		c.require(where, "mission_type", m.MissionType, missionTypes)
+		c.atLeast(where, "required_level", int64(m.RequiredLevel), 0)
+		if len(m.Objectives) == 0 {
+			c.addf("%s: at least one objective is required", where)
+		}
+		for j, o := range m.Objectives {
+			ow := fmt.Sprintf("%s objectives[%d]", where, j)
+			c.require(ow, "type", o.Type, nil)
+			c.atLeast(ow, "target", int64(o.Target), 1)
+		}
+		c.atLeast(where, "rewards.gtk", m.Rewards.GTK, 0)
+		c.atLeast(where, "rewards.tower", m.Rewards.Tower, 0)
+		for j, r := range m.Rewards.Items {
+			rw := fmt.Sprintf("%s rewards.items[%d]", where, j)
+			c.require(rw, "name", r.Name, nil)
+			c.require(rw, "type", r.Type, nil)
+			c.atLeast(rw, "quantity", int64(r.Quantity), 1)
+			if r.Rarity != "" && !ranks[r.Rarity] {
+				c.addf("%s: unknown rarity %q", rw, r.Rarity)
+			}
+		}
+	}
+
+	dialogues := make(map[string]bool)
+	for i, d := range b.Dialogues {
+		where := fmt.Sprintf("dialogues[%d] (%s)", i, d.key())
+		c.unique(dialogues, where, d.key())
+		if !missions[strconv.Itoa(d.MissionLevel)] {
+			c.addf("%s: mission level %d is not defined", where, d.MissionLevel)
+		}
+		c.require(where, "dialogue_type", d.DialogueType, dialogueTypes)
+		c.require(where, "character", d.Character, nil)
+		c.require(where, "text", d.Text, nil)
+	}
+
+	fragments := make(map[string]bool)
+	for i, f := range b.StoryFragments {
+		where := fmt.Sprintf("story_fragments[%d] (%s)", i, f.FragmentID)
+		c.require(where, "fragment_id", f.FragmentID, nil)
+		c.unique(fragments, where, f.FragmentID)
+		c.require(where, "title", f.Title, nil)
+		c.require(where, "content", f.Content, nil)
+		c.atLeast(where, "unlock_level", int64(f.UnlockLevel), 1)
+	}
+
+	quests := make(map[string]bool)
+	for i, q := range b.QuestTemplates {
+		where := fmt.Sprintf("quest_templates[%d] (%s)", i, q.Name)
+		c.require(where, "name", q.Name, nil)
+		c.unique(quests, where, q.Name)
+		c.require(where, "type", q.Type, questTypes)
+		c.require(where, "difficulty", q.Difficulty, questDifficulties)
+		c.require(where, "action_type", q.ActionType, nil)
+		c.require(where, "description", q.Description, nil)
+		c.atLeast(where, "base_target", int64(q.BaseTarget), 1)
+		c.atLeast(where, "reward_gtk", int64(q.RewardGTK), 0)
+		c.atLeast(where, "reward_tower", int64(q.RewardTOWER), 0)
+	}
+
+	items := make(map[string]bool)
+	for i, s := range b.ShopItems {
+		where := fmt.Sprintf("shop_items[%d] (%s)", i, s.Name)
+		c.require(where, "name", s.Name, nil)
+		c.maxLen(where, "name", s.Name, 50)
+		c.unique(items, where, s.Name)
+		c.require(where, "category", s.Category, nil)
+		c.maxLen(where, "category", s.Category, 20)
+		c.maxLen(where, "effect_type", s.EffectType, 20)
+		c.atLeast(where, "gtk_cost", s.GTKCost, 0)
+		c.atLeast(where, "max_stack", int64(s.MaxStack), 0)
+	}
+
+	settings := make(map[string]bool)
+	for i, s := range b.Settings {
+		where := fmt.Sprintf("settings[%d] (%s)", i, s.Key)
+		c.require(where, "key", s.Key, nil)
+		c.maxLen(where, "key", s.Key, 50)
+		c.unique(settings, where, s.Key)
+		c.require(where, "type", s.Type, settingTypes)
+		if err := checkSettingValue(s.Type, s.Value); err != nil {
+			c.addf("%s: value %q is not a valid %s: %v", where, s.Value, s.Type, err)
+		}
+	}
+
+	islands := make(map[string]map[int]bool)
+	for i, is := range b.Islands {
+		where := fmt.Sprintf("islands[%d] (%s)", i, is.Name)
+		c.require(where, "name", is.Name, nil)
+		c.maxLen(where, "name", is.Name, 50)
+		if _, dup := islands[is.Name]; dup {
+			c.addf("%s: duplicate key %q", where, is.Name)
+		}
+		c.atLeast(where, "difficulty", int64(is.Difficulty), 1)
+		bosses := make(map[string]bool)
+		for j, boss := range is.Bosses {
+			bw := fmt.Sprintf("%s bosses[%d] (%s)", where, j, boss.Name)
+			c.require(bw, "name", boss.Name, nil)
+			c.unique(bosses, bw, boss.Name)
+			c.require(bw, "element", boss.Element, nil)
+			c.require(bw, "character_type", boss.CharacterType, nil)
+			c.atLeast(bw, "total_hp", boss.TotalHP, 1)
+		}
+		if len(is.Missions) == 0 {
+			c.addf("%s: at least one mission is required", where)
+		}
+		sequences := make(map[int]bool)
+		for j, m := range is.Missions {
+			mw := fmt.Sprintf("%s missions[%d] (%d)", where, j, m.Sequence)
+			c.atLeast(mw, "sequence", int64(m.Sequence), 1)
+			if sequences[m.Sequence] {
+				c.addf("%s: duplicate sequence %d", mw, m.Sequence)
+			}
+			sequences[m.Sequence] = true
+			c.require(mw, "name", m.Name, nil)
+			c.require(mw, "enemy_name", m.EnemyName, nil)
+			c.require(mw, "enemy_type", m.EnemyType, nil)
+			c.atLeast(mw, "enemy_hp", m.EnemyHP, 1)
+		}
+		islands[is.Name] = sequences
+	}
+
+	tables := make(map[string]bool)
+	for i, t := range b.LootTables {
+		where := fmt.Sprintf("loot_tables[%d] (%s)", i, t.Name)
+		c.require(where, "name", t.Name, nil)
+		c.unique(tables, where, t.Name)
+		if sequences, ok := islands[t.Island]; !ok {
+			c.addf("%s: island %q is not defined", where, t.Island)
+		} else if !sequences[t.Sequence] {
+			c.addf("%s: island %q has no mission %d", where, t.Island, t.Sequence)
+		}
+		c.require(where, "drop_type", t.DropType, dropTypes)
+		if len(t.Entries) == 0 {
+			c.addf("%s: at least one entry is required", where)
+		}
+		dropped := make(map[string]bool)
+		for j, e := range t.Entries {
+			ew := fmt.Sprintf("%s entries[%d] (%s)", where, j, e.Item)
+			c.unique(dropped, ew, e.Item)
+			if !items[e.Item] {
+				c.addf("%s: shop item %q is not defined", ew, e.Item)
+			}
+			if e.DropChance <= 0 || e.DropChance > 100 {
+				c.addf("%s: drop_chance must be in (0, 100], got %g", ew, e.DropChance)
+			}
+			c.atLeast(ew, "min_quantity", int64(e.MinQuantity), 0)
+			if e.MaxQuantity != 0 && e.MaxQuantity < e.MinQuantity {
+				c.addf("%s: max_quantity %d is below min_quantity %d", ew, e.MaxQuantity, e.MinQuantity)
+			}
+		}
+	}
+
+	salvaged := make(map[string]bool) // Material names salvage can produce
+	yields := make(map[string]bool)
+	for i, y := range b.Salvage {
+		where := fmt.Sprintf("salvage[%d] (%s)", i, y.ItemType)
+		c.require(where, "item_type", y.ItemType, equipmentTypes)
+		c.unique(yields, where, y.ItemType)
+		if len(y.Materials) == 0 {
+			c.addf("%s: at least one material is required", where)
+		}
+		c.materials(where, y.Materials)
+		for _, m := range y.Materials {
+			salvaged[m.Name] = true
+		}
+	}
+
+	recipes := make(map[string]bool)
+	for i, r := range b.Recipes {
+		where := fmt.Sprintf("recipes[%d] (%s)", i, r.Name)
+		c.require(where, "name", r.Name, nil)
+		c.maxLen(where, "name", r.Name, 50)
+		c.maxLen(where, "item_name", r.ItemName, 50)
+		c.unique(recipes, where, r.Name)
+		c.require(where, "item_type", r.ItemType, craftedTypes)
+		if r.ItemType == "CONSUMABLE" {
+			c.require(where, "consume_effect", r.ConsumeEffect, consumeEffects)
+		} else if r.ConsumeEffect != "" {
+			c.addf("%s: consume_effect is only allowed on CONSUMABLE recipes", where)
+		}
+		c.atLeast(where, "gtk_cost", r.GTKCost, 0)
+		c.atLeast(where, "craft_minutes", int64(r.CraftMinutes), 0)
+		if r.SuccessChance <= 0 || r.SuccessChance > 1 {
+			c.addf("%s: success_chance must be in (0, 1], got %g", where, r.SuccessChance)
+		}
+		total := 0
+		for rarity, w := range r.RarityWeights {
+			if !ranks[rarity] {
+				c.addf("%s: unknown rarity %q in rarity_weights", where, rarity)
+			}
+			c.atLeast(where, "rarity_weights."+rarity, int64(w), 0)
+			total += w
+		}
+		if total <= 0 {
+			c.addf("%s: rarity_weights must give at least one rarity a positive weight", where)
+		}
+		if len(r.Materials) == 0 {
+			c.addf("%s: at least one material is required", where)
+		}
+		c.materials(where, r.Materials)
+		for _, m := range r.Materials {
+			if m.Name != "" && !salvaged[m.Name] {
+				c.addf("%s: material %q is not produced by any salvage yield", where, m.Name)
+			}
+		}
+	}
+
+	calendarLength := 28 // login_calendar_length, unless the bundle sets it
+	for _, s := range b.Settings {
+		if n, err := strconv.Atoi(s.Value); s.Key == "login_calendar_length" && err == nil {
+			calendarLength = n
+		}
+	}
+	days := make(map[string]bool)
+	for i, d := range b.LoginCalendar {
+		where := fmt.Sprintf("login_calendar[%d] (day %d)", i, d.Day)
+		c.unique(days, where, strconv.Itoa(d.Day))
+		if d.Day < 1 || d.Day > calendarLength {
+			c.addf("%s: day must be in [1, %d], got %d", where, calendarLength, d.Day)
+		}
+		c.atLeast(where, "gtk", d.GTK, 0)
+		c.atLeast(where, "quantity", int64(d.Quantity), 0)
+		if d.Item != "" && !items[d.Item] {
+			c.addf("%s: shop item %q is not defined", where, d.Item)
+		}
+		if d.GTK == 0 && d.Item == "" {
+			c.addf("%s: a day must pay gtk or an item", where)
+		}
+	}
+
+	if len(c.problems) > 0 {
+		return &ValidationError{Problems: c.problems}
+	}
+	return nil
+}
+
+// materials checks a list of material amounts
+func (c *checker) materials(where string, list []MaterialAmount) {
+	seen := make(map[string]bool)
+	for j, m := range list {
+		mw := fmt.Sprintf("%s materials[%d] (%s)", where, j, m.Name)
+		c.require(mw, "name", m.Name, nil)
+		c.maxLen(mw, "name", m.Name, 50)
+		c.unique(seen, mw, m.Name)
+		c.atLeast(mw, "quantity", int64(m.Quantity), 1)
+	}
+}
+
+// checkSettingValue parses value the way the settings service will read it
+func checkSettingValue(typ, value string) error {
+	var err error
+	switch typ {
+	case "int":
+		_, err = strconv.ParseInt(value, 10, 64)
+	case "float":
+		_, err = strconv.ParseFloat(value, 64)
+	case "bool":
+		_, err = strconv.ParseBool(value)
+	case "json":
+		if !json.Valid([]byte(value)) {
+			err = errors.New("invalid JSON")
+		}
+	}
+	return err
+}
@@ -44,7 +44,7 @@ func (s *AcceleratorService) ApplyAccelerator(userID, eggID, itemID uint) error
 	}
 
 	// SECURITY CHECK 3: Verify item is accelerator
-	if item.Category != "egg" {
+	if item.Category != "egg" || item.EffectType == "care" || item.EffectType == "egg_ticket" {
 		return errors.New("item is not an egg accelerator")
 	}
 
@@ -14,10 +14,10 @@ type AdminService struct {
 	ls            *LedgerService
 }
 
-func NewAdminService() *AdminService {
+func NewAdminService(ledger *LedgerService) *AdminService {
 	return &AdminService{
 		configService: GetConfigService(),
-		ls:            NewLedgerService(),
+		ls:            ledger,
 	}
 }
 
@@ -42,6 +42,11 @@ func (s *AdminService) BanUser(targetUserID uint, reason string, durationHours i
 		return err
 	}
 
+	// Kill every session so refresh tokens and in-flight access tokens stop working now
+	if err := RevokeUserSessions(targetUserID, SessionRevokedBanned); err != nil {
+		return err
+	}
+
 	// Audit
 	s.CreateAuditLog(adminID, "BAN_USER", strconv.Itoa(int(targetUserID)), oldStatus, "BANNED: "+reason)
 	return nil
@@ -4,8 +4,11 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"time"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/pkg/metrics"
 )
 
 // AntiCheatFlag represents a detected cheat flag
@@ -76,7 +79,7 @@ func (s *AntiCheatService) ValidateBattle(battleID int, player1ID, player2ID int
 		flags[i].BattleID = battleID
 		err = s.saveFlag(&flags[i])
 		if err != nil {
-			log.Printf("Failed to save anti-cheat flag: %v", err)
+			slog.Error("failed to save anti-cheat flag", "battle_id", battleID, "error", err)
 		}
 	}
 
@@ -254,39 +257,99 @@ func (s *AntiCheatService) hasImpossibleReactions(actions []GameAction) bool {
 	return false
 }
 
-// sameIP checks if two players are using the same IP address
+// sameIP checks if two players last connected from the same IP address
 func (s *AntiCheatService) sameIP(player1ID, player2ID int) bool {
-	// TODO: Implement actual IP checking from session logs
-	// Query session_logs or user_sessions table for recent IP addresses
-	// Example query:
-	// SELECT ip_address FROM session_logs WHERE user_id IN ($1, $2)
-	// ORDER BY created_at DESC LIMIT 2
+	query := `
+		SELECT COUNT(*)
+		FROM users u1
+		JOIN users u2 ON u2.id = $2
+		WHERE u1.id = $1
+		  AND u1.last_known_ip IS NOT NULL AND u1.last_known_ip != ''
+		  AND u1.last_known_ip = u2.last_known_ip
+	`
 
-	// Log for future implementation tracking
-	if player1ID > 0 && player2ID > 0 {
-		// Placeholder: When implemented, this will check IP match
-		// log.Printf("Checking IP match for players %d and %d", player1ID, player2ID)
+	var matches int
+	if err := s.db.QueryRow(query, player1ID, player2ID).Scan(&matches); err != nil {
+		return false
 	}
-
-	return false
+	return matches > 0
 }
 
-// connectedWallets checks if wallets have transaction history
+// connectedWallets checks if the players' wallets have moved GTK directly to each other
+// (both wallet accounts appear in the same ledger transaction, e.g. marketplace or player trades)
 func (s *AntiCheatService) connectedWallets(player1ID, player2ID int) bool {
-	// TODO: Implement blockchain wallet analysis
-	// Check if wallets have sent tokens to each other
-	// This requires:
-	// 1. Get wallet addresses for both players
-	// 2. Query blockchain for transactions between these addresses
-	// 3. Check transaction history for suspicious patterns
+	return s.sharedTransactions(player1ID, player2ID) > 0
+}
 
-	// Log for future implementation tracking
-	if player1ID > 0 && player2ID > 0 {
-		// Placeholder: When implemented, this will analyze wallet connections
-		// log.Printf("Checking wallet connections for players %d and %d", player1ID, player2ID)
+// sharedTransactions counts the ledger transactions both players' wallets took part in
+func (s *AntiCheatService) sharedTransactions(player1ID, player2ID int) int {
+	query := `
+		SELECT COUNT(DISTINCT e1.transaction_id)
+		FROM ledger_entries e1
+		JOIN ledger_accounts a1 ON a1.id = e1.account_id
+		JOIN ledger_entries e2 ON e2.transaction_id = e1.transaction_id
+		JOIN ledger_accounts a2 ON a2.id = e2.account_id
+		WHERE a1.user_id = $1 AND a1.type = 'WALLET'
+		  AND a2.user_id = $2 AND a2.type = 'WALLET'
+	`
+
+	var shared int
+	if err := s.db.QueryRow(query, player1ID, player2ID).Scan(&shared); err != nil {
+		return 0
 	}
+	return shared
+}
 
-	return false
+// ReviewTrade screens a settled player trade for real-money trading. Value flowing one way
+// between two accounts is how bought gold and characters usually arrive; it is flagged on the
+// receiving side, more severely when the pair shares an IP or their wallets were already
+// connected before this trade (whose own ledger transaction links them once).
+func (s *AntiCheatService) ReviewTrade(trade *models.Trade) {
+	initiator, counterparty := int(trade.InitiatorID), int(trade.CounterpartyID)
+	gives := map[uint]int{}
+	for _, a := range trade.Assets {
+		gives[a.FromUserID]++
+	}
+	initiatorGives := gives[trade.InitiatorID] > 0 || trade.InitiatorGTK > 0
+	counterpartyGives := gives[trade.CounterpartyID] > 0 || trade.CounterpartyGTK > 0
+	oneSided := initiatorGives != counterpartyGives
+	sameIP := s.sameIP(initiator, counterparty)
+	if !oneSided && !sameIP {
+		return
+	}
+
+	severity := "low"
+	if s.sharedTransactions(initiator, counterparty) > 1 {
+		severity = "medium"
+	}
+	if sameIP {
+		severity = "high"
+	}
+	receiver := initiator
+	if initiatorGives {
+		receiver = counterparty
+	}
+	details, _ := json.Marshal(map[string]interface{}{
+		"reason":           "suspicious_trade",
+		"trade_id":         trade.ID,
+		"one_sided":        oneSided,
+		"same_ip":          sameIP,
+		"initiator":        initiator,
+		"counterparty":     counterparty,
+		"initiator_gtk":    trade.InitiatorGTK,
+		"counterparty_gtk": trade.CounterpartyGTK,
+		"assets":           len(trade.Assets),
+	})
+	flag := AntiCheatFlag{
+		UserID:      receiver,
+		FlagType:    "rmt",
+		Severity:    severity,
+		Details:     details,
+		AutoFlagged: true,
+	}
+	if err := s.saveFlag(&flag); err != nil {
+		slog.Error("failed to save trade flag", "trade_id", trade.ID, "user_id", receiver, "error", err)
+	}
 }
 
 // suspiciousWinRate checks for unnatural win/loss patterns
@@ -326,20 +389,26 @@ func (s *AntiCheatService) saveFlag(flag *AntiCheatFlag) error {
 		RETURNING id, created_at
 	`
 
-	return s.db.QueryRow(query,
-		flag.BattleID,
+	// Flags raised outside a battle (trade reviews) carry no battle
+	battleID := sql.NullInt64{Int64: int64(flag.BattleID), Valid: flag.BattleID != 0}
+	err := s.db.QueryRow(query,
+		battleID,
 		flag.UserID,
 		flag.FlagType,
 		flag.Severity,
 		flag.Details,
 		flag.AutoFlagged,
 	).Scan(&flag.ID, &flag.CreatedAt)
+	if err == nil {
+		metrics.AntiCheatFlags.WithLabelValues(flag.Severity).Inc()
+	}
+	return err
 }
 
 // GetFlagsByBattle retrieves all flags for a battle
 func (s *AntiCheatService) GetFlagsByBattle(battleID int) ([]AntiCheatFlag, error) {
 	query := `
-		SELECT id, battle_id, user_id, flag_type, severity, details, 
+		SELECT id, COALESCE(battle_id, 0), user_id, flag_type, severity, details, 
 		       auto_flagged, reviewed, created_at
 		FROM anti_cheat_flags
 		WHERE battle_id = $1
@@ -378,7 +447,7 @@ func (s *AntiCheatService) GetFlagsByBattle(battleID int) ([]AntiCheatFlag, erro
 // GetFlagsByUser retrieves all flags for a user
 func (s *AntiCheatService) GetFlagsByUser(userID int) ([]AntiCheatFlag, error) {
 	query := `
-		SELECT id, battle_id, user_id, flag_type, severity, details, 
+		SELECT id, COALESCE(battle_id, 0), user_id, flag_type, severity, details, 
 		       auto_flagged, reviewed, created_at
 		FROM anti_cheat_flags
 		WHERE user_id = $1
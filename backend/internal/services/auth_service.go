@@ -3,7 +3,9 @@ package services
 import (
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -15,6 +17,11 @@ import (
 	"github.com/lorengraff/crypto-tower-defense/pkg/config"
 )
 
+const (
+	siweNonceTTL  = 10 * time.Minute // How long a GetNonce message can be signed
+	siweClockSkew = 1 * time.Minute
+)
+
 // AuthService handles authentication logic
 type AuthService struct {
 	cfg *config.Config
@@ -41,6 +48,7 @@ func (s *AuthService) GetOrCreateUser(walletAddress string) (*models.User, error
 	var user models.User
 	result := db.DB.Where("wallet_address = ?", walletAddress).First(&user)
 
+	now := time.Now()
 	if result.Error == nil {
 		// User exists, generate new nonce
 		nonce, err := s.GenerateNonce()
@@ -48,6 +56,7 @@ func (s *AuthService) GetOrCreateUser(walletAddress string) (*models.User, error
 			return nil, err
 		}
 		user.Nonce = nonce
+		user.NonceIssuedAt = &now
 		db.DB.Save(&user)
 		return &user, nil
 	}
@@ -69,6 +78,7 @@ func (s *AuthService) GetOrCreateUser(walletAddress string) (*models.User, error
 	user = models.User{
 		WalletAddress: walletAddress,
 		Nonce:         nonce,
+		NonceIssuedAt: &now,
 		Role:          role, // Set assigned role
 		Level:         1,
 		Rank:          "Cadete",
@@ -91,10 +101,13 @@ func (s *AuthService) VerifySignature(walletAddress, signature, message string)
 	walletAddress = common.HexToAddress(walletAddress).Hex()
 
 	// Decode signature
-	sig, err := hex.DecodeString(signature[2:]) // Remove "0x" prefix
+	sig, err := hex.DecodeString(strings.TrimPrefix(signature, "0x"))
 	if err != nil {
 		return false, fmt.Errorf("invalid signature format: %w", err)
 	}
+	if len(sig) != 65 {
+		return false, errors.New("invalid signature length")
+	}
 
 	// Adjust signature format (Ethereum uses v = 27/28, we need 0/1)
 	if sig[64] >= 27 {
@@ -116,13 +129,102 @@ func (s *AuthService) VerifySignature(walletAddress, signature, message string)
 	return recoveredAddress == walletAddress, nil
 }
 
-// GenerateJWT creates a JWT token for authenticated user
-func (s *AuthService) GenerateJWT(user *models.User) (string, error) {
+// BuildSIWEMessage renders the EIP-4361 message for the user's pending nonce
+func (s *AuthService) BuildSIWEMessage(user *models.User) *SIWEMessage {
+	issuedAt := time.Now()
+	if user.NonceIssuedAt != nil {
+		issuedAt = *user.NonceIssuedAt
+	}
+	issuedAt = issuedAt.UTC().Truncate(time.Second)
+	expires := issuedAt.Add(siweNonceTTL)
+
+	return &SIWEMessage{
+		Domain:         s.cfg.SIWEDomain,
+		Address:        user.WalletAddress,
+		Statement:      "Sign in to Crypto Tower Defense.",
+		URI:            s.cfg.SIWEURI,
+		Version:        "1",
+		ChainID:        s.cfg.SIWEChainID,
+		Nonce:          user.Nonce,
+		IssuedAt:       issuedAt,
+		ExpirationTime: &expires,
+	}
+}
+
+// VerifySIWE validates a signed EIP-4361 message (domain, URI, chain ID, nonce, issued-at, expiry and signer)
+// and consumes the nonce. An empty rawMessage verifies against the message issued by GetNonce.
+func (s *AuthService) VerifySIWE(walletAddress, rawMessage, signature string) (*models.User, error) {
+	if !common.IsHexAddress(walletAddress) {
+		return nil, errors.New("invalid wallet address")
+	}
+	address := common.HexToAddress(walletAddress).Hex()
+
+	var user models.User
+	if err := db.DB.Where("wallet_address = ?", address).First(&user).Error; err != nil {
+		return nil, errors.New("user not found or nonce expired")
+	}
+	if user.Nonce == "" {
+		return nil, errors.New("nonce expired, request a new one")
+	}
+
+	var msg *SIWEMessage
+	if rawMessage == "" {
+		msg = s.BuildSIWEMessage(&user)
+		rawMessage = msg.String()
+	} else {
+		var err error
+		if msg, err = ParseSIWEMessage(rawMessage); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	switch {
+	case msg.Domain != s.cfg.SIWEDomain:
+		return nil, errors.New("SIWE domain mismatch")
+	case msg.URI != s.cfg.SIWEURI:
+		return nil, errors.New("SIWE URI mismatch")
+	case msg.Version != "1":
+		return nil, errors.New("unsupported SIWE version")
+	case msg.ChainID != s.cfg.SIWEChainID:
+		return nil, errors.New("SIWE chain ID mismatch")
+	case !strings.EqualFold(msg.Address, address):
+		return nil, errors.New("SIWE address mismatch")
+	case msg.Nonce != user.Nonce:
+		return nil, errors.New("invalid or reused nonce")
+	case msg.IssuedAt.After(now.Add(siweClockSkew)):
+		return nil, errors.New("SIWE message issued in the future")
+	case now.Sub(msg.IssuedAt) > siweNonceTTL:
+		return nil, errors.New("SIWE message is too old, request a new nonce")
+	case msg.ExpirationTime != nil && now.After(*msg.ExpirationTime):
+		return nil, errors.New("SIWE message has expired")
+	case msg.NotBefore != nil && now.Add(siweClockSkew).Before(*msg.NotBefore):
+		return nil, errors.New("SIWE message is not valid yet")
+	}
+
+	valid, err := s.VerifySignature(address, signature, rawMessage)
+	if err != nil || !valid {
+		return nil, errors.New("invalid signature")
+	}
+
+	// Single use: a captured signature cannot be replayed
+	res := db.DB.Model(&models.User{}).Where("id = ? AND nonce = ?", user.ID, user.Nonce).
+		Updates(map[string]interface{}{"nonce": "", "nonce_issued_at": nil})
+	if res.Error != nil || res.RowsAffected == 0 {
+		return nil, errors.New("invalid or reused nonce")
+	}
+	user.Nonce = ""
+	return &user, nil
+}
+
+// GenerateJWT creates a short-lived access token bound to a session
+func (s *AuthService) GenerateJWT(user *models.User, sessionID uint) (string, error) {
 	claims := middleware.Claims{
 		UserID:        user.ID,
 		WalletAddress: user.WalletAddress,
+		SessionID:     sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.cfg.AccessTokenTTL)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			Issuer:    "crypto-tower-defense",
 		},
@@ -137,8 +239,11 @@ func (s *AuthService) GenerateJWT(user *models.User) (string, error) {
 	return tokenString, nil
 }
 
-// UpdateLastLogin updates user's last login timestamp
-func (s *AuthService) UpdateLastLogin(userID uint) error {
+// UpdateLastLogin updates user's last login timestamp and the IP used by anti-cheat checks
+func (s *AuthService) UpdateLastLogin(userID uint, ipAddress string) error {
 	now := time.Now()
-	return db.DB.Model(&models.User{}).Where("id = ?", userID).Update("last_login_at", now).Error
+	return db.DB.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"last_login_at": now,
+		"last_known_ip": ipAddress,
+	}).Error
 }
@@ -11,7 +11,7 @@ import (
 
 // BattleEngine handles core battle mechanics
 type BattleEngine struct {
-	config *ConfigService
+	config Settings
 }
 
 // NewBattleEngine creates a new battle engine
@@ -1,31 +1,52 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
+	"math"
 	"math/rand"
 	"strings"
 	"time"
 
-	"github.com/lorengraff/crypto-tower-defense/internal/db"
 	"github.com/lorengraff/crypto-tower-defense/internal/models"
-	"gorm.io/gorm"
+	"github.com/lorengraff/crypto-tower-defense/internal/repository"
+	"github.com/lorengraff/crypto-tower-defense/pkg/logger"
+	"github.com/lorengraff/crypto-tower-defense/pkg/metrics"
+	"github.com/lorengraff/crypto-tower-defense/pkg/tracing"
 )
 
+// BattleSkills is the part of the skill system a battle turn uses (SkillActivationService)
+type BattleSkills interface {
+	ActivateSkill(req SkillActivationRequest) (*SkillActivationResult, error)
+	ReduceCooldowns(characterID uint)
+	RegenerateMana(characterID uint) error
+	GetUsableSkills(characterID uint, currentMana int) ([]models.Ability, error)
+}
+
+// BattleEffects is the part of the status effect system a battle turn uses (StatusEffectService)
+type BattleEffects interface {
+	ProcessTurnEffects(characterID uint) (int, []string, error)
+}
+
 type BattleService struct {
+	store         repository.Store
 	engine        *BattleEngine
 	ledger        *LedgerService
-	skillService  *SkillActivationService
-	statusService *StatusEffectService
+	skillService  BattleSkills
+	statusService BattleEffects
 }
 
-func NewBattleService() *BattleService {
+// NewBattleService creates the battle service
+func NewBattleService(store repository.Store, ledger *LedgerService, skills BattleSkills, effects BattleEffects) *BattleService {
 	return &BattleService{
+		store:         store,
 		engine:        NewBattleEngine(),
-		ledger:        NewLedgerService(),
-		skillService:  NewSkillActivationService(),
-		statusService: NewStatusEffectService(),
+		ledger:        ledger,
+		skillService:  skills,
+		statusService: effects,
 	}
 }
 
@@ -47,7 +68,7 @@ func (s *BattleService) CreatePvEBattle(player1ID uint, mode string) (*models.Ba
 		return nil, err
 	}
 
-	if err := db.DB.Create(&battle).Error; err != nil {
+	if err := s.store.Battles().Create(&battle); err != nil {
 		return nil, err
 	}
 
@@ -56,8 +77,12 @@ func (s *BattleService) CreatePvEBattle(player1ID uint, mode string) (*models.Ba
 
 // InitializeBattleState generates team snapshots for the battle participants
 func (s *BattleService) InitializeBattleState(battle *models.Battle) error {
+	return s.initializeBattleState(s.store, battle)
+}
+
+func (s *BattleService) initializeBattleState(st repository.Store, battle *models.Battle) error {
 	// 1. Snapshot Player 1
-	p1Team, err := s.GetTeamSnapshot(battle.Player1ID)
+	p1Team, err := s.teamSnapshot(st, battle.Player1ID)
 	if err != nil {
 		return fmt.Errorf("failed to get player 1 team: %w", err)
 	}
@@ -66,7 +91,7 @@ func (s *BattleService) InitializeBattleState(battle *models.Battle) error {
 
 	// Calculate Dynamic Stakes if Wager
 	if battle.BattleType == "wager" && battle.Status == "pending" { // Only calc if new
-		p1Stake, p2Stake, err := s.CalculateDynamicStakes(battle.Player1ID, battle.Player2ID, p1Team)
+		p1Stake, p2Stake, err := s.dynamicStakes(st, battle.Player1ID, battle.Player2ID, p1Team)
 		if err == nil {
 			battle.Player1Bet = p1Stake
 			battle.Player2Bet = p2Stake
@@ -79,7 +104,7 @@ func (s *BattleService) InitializeBattleState(battle *models.Battle) error {
 		p2Team = s.generateAITeam(battle.Player1ID, battle.BattleType)
 	} else {
 		// PvP / Wager
-		p2Team, err = s.GetTeamSnapshot(battle.Player2ID)
+		p2Team, err = s.teamSnapshot(st, battle.Player2ID)
 		if err != nil {
 			return fmt.Errorf("failed to get player 2 team: %w", err)
 		}
@@ -90,25 +115,48 @@ func (s *BattleService) InitializeBattleState(battle *models.Battle) error {
 	return nil
 }
 
-// Helper: Get Team Snapshot
+// GetTeamSnapshot returns the player's active team as battle participants
 func (s *BattleService) GetTeamSnapshot(userID uint) ([]models.BattleParticipant, error) {
-	// Fetch Active Team
-	var team models.Team
-	if err := db.DB.Preload("Members.Character").
-		Where("user_id = ? AND is_active = true", userID).
-		First(&team).Error; err != nil {
+	return s.teamSnapshot(s.store, userID)
+}
+
+func (s *BattleService) teamSnapshot(st repository.Store, userID uint) ([]models.BattleParticipant, error) {
+	// Fetch Active Team (borrowed characters included, lent ones left out)
+	team, err := controlledTeam(st, userID)
+	if err != nil || len(team) == 0 {
 		return nil, errors.New("no active team found")
 	}
 
+	for i := range team {
+		if err := CheckBattleReady(&team[i], s.engine.config); err != nil {
+			return nil, err
+		}
+	}
+
+	synergies := TeamSynergies(team)
 	var participants []models.BattleParticipant
-	for _, member := range team.Members {
-		if member.Character.ID != 0 {
-			participants = append(participants, *s.toParticipant(&member.Character))
+	for i := range team {
+		stats, err := s.combatStats(st, &team[i], synergies)
+		if err != nil {
+			return nil, err
 		}
+		p := s.toParticipant(&team[i], stats.Final)
+		// Gear and synergy HP extend the pool on top of the character's own health
+		p.CurrentHP = min(p.CurrentHP+stats.Final.HP-stats.Sheet().HP, p.MaxHP)
+		participants = append(participants, *p)
 	}
 	return participants, nil
 }
 
+// combatStats runs the stat pipeline for a character with its gear and team synergies
+func (s *BattleService) combatStats(st repository.Store, c *models.Character, synergies []models.TeamSynergy) (StatBreakdown, error) {
+	gear, err := st.Equipment().Gear(c.ID)
+	if err != nil {
+		return StatBreakdown{}, err
+	}
+	return ComputeStats(StatInput{Character: withRestedFatigue(c, s.engine.config), Gear: gear, Synergies: synergies}), nil
+}
+
 // Helper: Generate AI Team
 func (s *BattleService) generateAITeam(_ uint, _ string) []models.BattleParticipant {
 	// Simplified: Create 1 Dummy Enemy
@@ -128,11 +176,17 @@ func (s *BattleService) generateAITeam(_ uint, _ string) []models.BattleParticip
 
 // ProcessTurn executes a turn in a PvP battle
 // Uses BattleEngine to calculate damage and update state
-func (s *BattleService) ProcessTurn(battleID uint, userID uint, actionData map[string]interface{}) (*models.Battle, error) {
-	var battle models.Battle
-	if err := db.DB.Preload("Player1").Preload("Player2").First(&battle, battleID).Error; err != nil {
+func (s *BattleService) ProcessTurn(ctx context.Context, battleID uint, userID uint, actionData map[string]interface{}) (_ *models.Battle, err error) {
+	ctx, span := tracing.Start(ctx, "battle.process_turn", "battle_id", battleID, "user_id", userID)
+	defer func() { span.End(err) }()
+	conn := s.store.WithContext(ctx)
+	chars := conn.Characters()
+
+	found, err := conn.Battles().Get(battleID)
+	if err != nil {
 		return nil, errors.New("battle not found")
 	}
+	battle := *found
 
 	if battle.Status != "active" {
 		return nil, errors.New("battle is not active")
@@ -164,12 +218,21 @@ func (s *BattleService) ProcessTurn(battleID uint, userID uint, actionData map[s
 
 	// Fetch characters
 	var attacker, defender models.Character
-	if err := db.DB.First(&attacker, charID).Error; err != nil {
+	if c, err := chars.Get(charID); err == nil {
+		attacker = *c
+	} else {
 		return nil, errors.New("attacker not found")
 	}
-	if err := db.DB.First(&defender, targetID).Error; err != nil {
+	if c, err := chars.Get(targetID); err == nil {
+		defender = *c
+	} else {
 		return nil, errors.New("defender not found")
 	}
+	reloadAttacker := func() {
+		if c, err := chars.Get(charID); err == nil {
+			attacker = *c
+		}
+	}
 
 	// SECURITY: Verify ownership and state
 	if attacker.OwnerID != userID {
@@ -183,12 +246,12 @@ func (s *BattleService) ProcessTurn(battleID uint, userID uint, actionData map[s
 	// Only process effects if it's the start of their turn logic
 	dotDamage, expiredEffects, err := s.statusService.ProcessTurnEffects(attacker.ID)
 	if err != nil {
-		fmt.Printf("Error processing effects: %v\n", err)
+		logger.FromContext(ctx).Error("failed to process status effects", "battle_id", battleID, "character_id", attacker.ID, "error", err)
 	}
 
 	// Refresh attacker from DB after status effects (HP might have changed)
 	if dotDamage > 0 {
-		db.DB.First(&attacker, charID) // Reload
+		reloadAttacker()
 		if attacker.IsFainted {
 			// If died from Poison, turn ends immediately? Or prevent action?
 			return nil, errors.New("character fainted from status effects")
@@ -199,7 +262,7 @@ func (s *BattleService) ProcessTurn(battleID uint, userID uint, actionData map[s
 	// Regenerate Mana
 	s.skillService.RegenerateMana(attacker.ID)
 	// Reload attacker again to get fresh Mana/CDs
-	db.DB.First(&attacker, charID)
+	reloadAttacker()
 
 	var logMsg string
 
@@ -244,12 +307,14 @@ func (s *BattleService) ProcessTurn(battleID uint, userID uint, actionData map[s
 			// So WE must apply healing.
 			if targetID == attacker.ID {
 				// Reload attacker (ActivateSkill saved mana deduction)
-				db.DB.First(&attacker, charID)
+				reloadAttacker()
 				attacker.CurrentHP += result.Healing
 				if attacker.CurrentHP > attacker.BaseHP {
 					attacker.CurrentHP = attacker.BaseHP
 				}
-				db.DB.Save(&attacker)
+				if err := chars.Save(&attacker); err != nil {
+					return nil, err
+				}
 			} else {
 				defender.CurrentHP += result.Healing
 				if defender.CurrentHP > defender.BaseHP {
@@ -258,15 +323,25 @@ func (s *BattleService) ProcessTurn(battleID uint, userID uint, actionData map[s
 			}
 		}
 
-		db.DB.Save(&defender)
+		if err := chars.Save(&defender); err != nil {
+			return nil, err
+		}
 		logMsg = result.Message
 
 	case "attack":
 		// Basic Attack (Physical, No Mana, No CD)
 		// Use Engine or create dummy ability
 		// Adapting to simple physical hit
-		pAttacker := s.toParticipant(&attacker)
-		pDefender := s.toParticipant(&defender)
+		attackerStats, err := s.combatStats(conn, &attacker, nil)
+		if err != nil {
+			return nil, err
+		}
+		defenderStats, err := s.combatStats(conn, &defender, nil)
+		if err != nil {
+			return nil, err
+		}
+		pAttacker := s.toParticipant(&attacker, attackerStats.Final)
+		pDefender := s.toParticipant(&defender, defenderStats.Final)
 
 		// Basic Attack Ability
 		ability := models.Ability{Name: "Attack", Damage: 10, DamageType: "physical", Element: "Normal"}
@@ -281,7 +356,10 @@ func (s *BattleService) ProcessTurn(battleID uint, userID uint, actionData map[s
 		if pDefender.IsFainted {
 			defender.IsFainted = true
 		}
-		db.DB.Save(&defender) // Only save defender. Attacker not changed in basic attack (no mana)
+		// Only save defender. Attacker not changed in basic attack (no mana)
+		if err := chars.Save(&defender); err != nil {
+			return nil, err
+		}
 		logMsg = res.Message
 
 	case "item":
@@ -293,10 +371,10 @@ func (s *BattleService) ProcessTurn(battleID uint, userID uint, actionData map[s
 		itemID := uint(itemIDVal)
 
 		// 1. Verify Inventory
-		var inventory models.UserInventory
 		// Start Transaction for Item consumption
-		err := db.DB.Transaction(func(tx *gorm.DB) error {
-			if err := tx.Where("user_id = ? AND item_id = ?", userID, itemID).First(&inventory).Error; err != nil {
+		err := conn.Transaction(func(tx repository.Store) error {
+			inventory, err := tx.Inventory().Find(userID, itemID)
+			if err != nil {
 				return errors.New("item not owned or empty")
 			}
 			if inventory.Quantity <= 0 {
@@ -304,8 +382,8 @@ func (s *BattleService) ProcessTurn(battleID uint, userID uint, actionData map[s
 			}
 
 			// 2. Fetch Item Details
-			var shopItem models.ShopItem
-			if err := tx.First(&shopItem, itemID).Error; err != nil {
+			shopItem, err := tx.Inventory().ShopItem(itemID)
+			if err != nil {
 				return errors.New("item details not found")
 			}
 
@@ -324,20 +402,17 @@ func (s *BattleService) ProcessTurn(battleID uint, userID uint, actionData map[s
 			// 4. Consume Item
 			inventory.Quantity--
 			if inventory.Quantity == 0 {
-				if err := tx.Delete(&inventory).Error; err != nil {
+				if err := tx.Inventory().Delete(inventory); err != nil {
 					return err
 				}
 			} else {
-				if err := tx.Save(&inventory).Error; err != nil {
+				if err := tx.Inventory().Save(inventory); err != nil {
 					return err
 				}
 			}
 
 			// Save Character
-			if err := tx.Save(&attacker).Error; err != nil {
-				return err
-			}
-			return nil
+			return tx.Characters().Save(&attacker)
 		})
 		if err != nil {
 			return nil, err
@@ -358,10 +433,10 @@ func (s *BattleService) ProcessTurn(battleID uint, userID uint, actionData map[s
 
 	if defender.IsFainted {
 		// Check if team is wiped
-		var count int64
-		db.DB.Model(&models.Character{}).
-			Where("owner_id = ? AND is_fainted = false", defender.OwnerID).
-			Count(&count)
+		count, err := chars.CountAlive(defender.OwnerID)
+		if err != nil {
+			return nil, err
+		}
 
 		if count == 0 {
 			winnerID = attacker.OwnerID
@@ -370,8 +445,12 @@ func (s *BattleService) ProcessTurn(battleID uint, userID uint, actionData map[s
 	}
 
 	if gameEnded {
-		s.CompleteBattle(battle.ID, winnerID, "")
-		db.DB.First(&battle, battleID) // Reload
+		if err := s.CompleteBattle(ctx, battle.ID, winnerID, ""); err != nil {
+			return nil, err
+		}
+		if b, err := conn.Battles().Get(battleID); err == nil {
+			battle = *b // Reload
+		}
 	} else {
 		// Toggle Turn
 		if battle.CurrentTurnPlayerID == battle.Player1ID {
@@ -406,12 +485,14 @@ func (s *BattleService) ProcessTurn(battleID uint, userID uint, actionData map[s
 	battle.LastTurnData = string(stateBytes)
 
 	// Save
-	db.DB.Save(&battle)
+	if err := conn.Battles().Save(&battle); err != nil {
+		return nil, err
+	}
 
 	// --- AI TURN TRIGGER ---
 	if !gameEnded && battle.WinnerID == nil && strings.Contains(battle.BattleType, "PVE") && battle.CurrentTurnPlayerID == battle.Player2ID {
-		if err := s.executeAITurn(&battle); err != nil {
-			fmt.Printf("AI Execution Failed: %v\n", err)
+		if err := s.executeAITurn(ctx, &battle); err != nil {
+			logger.FromContext(ctx).Error("AI turn failed", "battle_id", battle.ID, "error", err)
 		}
 	}
 
@@ -419,15 +500,15 @@ func (s *BattleService) ProcessTurn(battleID uint, userID uint, actionData map[s
 }
 
 // executeAITurn handles AI logic for PvE
-func (s *BattleService) executeAITurn(battle *models.Battle) error {
+func (s *BattleService) executeAITurn(ctx context.Context, battle *models.Battle) error {
 	// 1. Identify AI Character (Player 2's active char)
 	// Simplified: Fetch Player 2's FIRST active character
 	// In real logic, we'd check PlayerStateP2 or a dedicated ActiveCharacter table
 	// For MVP, we assume Player 2 has one character active for now or fetch from DB.
-	var aiChar models.Character
+	chars := s.store.WithContext(ctx).Characters()
 	// Assuming Player 2 has characters.
 	// Find FIRST non-fainted character owned by Player 2
-	err := db.DB.Where("owner_id = ? AND is_fainted = false", battle.Player2ID).First(&aiChar).Error
+	aiChar, err := chars.FirstAlive(battle.Player2ID)
 	if err != nil {
 		// AI has no chars? AI signs of surrender/loss?
 		// CheckBattleEnd should handle it.
@@ -435,9 +516,8 @@ func (s *BattleService) executeAITurn(battle *models.Battle) error {
 	}
 
 	// 2. Identify Target (Player 1's active char)
-	var playerChar models.Character
 	// Pick one random? or First?
-	err = db.DB.Where("owner_id = ? AND is_fainted = false", battle.Player1ID).First(&playerChar).Error
+	playerChar, err := chars.FirstAlive(battle.Player1ID)
 	if err != nil {
 		return nil // Player 1 dead?
 	}
@@ -480,53 +560,56 @@ func (s *BattleService) executeAITurn(battle *models.Battle) error {
 	// And at end of AI turn, it sets P1. P1 is NOT "PVE" trigger (only P2 is AI).
 	// So recursion depth = 1. Safe.
 
-	_, err = s.ProcessTurn(battle.ID, battle.Player2ID, actionData)
+	_, err = s.ProcessTurn(ctx, battle.ID, battle.Player2ID, actionData)
 	return err
 }
 
-// Helper to adapt DB Character to BattleParticipant
-func (s *BattleService) toParticipant(c *models.Character) *models.BattleParticipant {
+// Helper to adapt DB Character to BattleParticipant, with stats from the stat pipeline
+func (s *BattleService) toParticipant(c *models.Character, stats Stats) *models.BattleParticipant {
 	return &models.BattleParticipant{
 		CharacterID:   c.ID,
 		CharacterName: c.Name,
 		Element:       c.Element, // NEW
-		MaxHP:         c.BaseHP,  // Simplified
+		MaxHP:         stats.HP,
 		CurrentHP:     c.CurrentHP,
 		MaxMana:       100, // Fixed for now
 		CurrentMana:   c.CurrentMana,
-		Attack:        c.CurrentAttack,
-		Defense:       c.CurrentDefense,
-		Speed:         c.CurrentSpeed,
+		Attack:        stats.Attack,
+		Defense:       stats.Defense,
+		Speed:         stats.Speed,
 		IsFainted:     c.IsFainted,
 		IsActive:      true,
 	}
 }
 
 // CompleteBattle handles victory with Anti-Cheat validation
-func (s *BattleService) CompleteBattle(battleID uint, winnerID uint, replayData string) error {
+func (s *BattleService) CompleteBattle(ctx context.Context, battleID uint, winnerID uint, replayData string) error {
+	log := logger.FromContext(ctx).With("battle_id", battleID)
+
 	// 1. Anti-Cheat: Validate Replay Data (Basic sanity checks for now)
 	if replayData != "" {
 		if err := s.ValidateReplay(battleID, winnerID, replayData); err != nil {
 			// Log security event
-			fmt.Printf("SECURTY ALERT: Battle %d failed validation: %v\n", battleID, err)
+			log.Warn("security alert: battle failed replay validation", "winner_id", winnerID, "error", err)
 			return fmt.Errorf("security check failed: %v", err)
 		}
 	} else {
 		// Log missing replay (Soft warning for legacy clients, Hard error for new GDevelop clients)
 		// For consistency, we require it for ranked/wager
-		var checkBattle models.Battle
-		if err := db.DB.First(&checkBattle, battleID).Error; err == nil {
+		if checkBattle, err := s.store.WithContext(ctx).Battles().Get(battleID); err == nil {
 			if checkBattle.BattleType == "wager" || checkBattle.BattleType == "ranked" {
 				// Strict mode for sensitive battles
 				// return errors.New("missing replay data") // Uncomment when client is ready
-				fmt.Printf("WARNING: Missing replay data for sensitive battle %d\n", battleID)
+				log.Warn("missing replay data for sensitive battle", "battle_type", checkBattle.BattleType)
 			}
 		}
 	}
 
-	return db.DB.Transaction(func(tx *gorm.DB) error {
-		var battle models.Battle
-		if err := tx.First(&battle, battleID).Error; err != nil {
+	var completed string // Battle type, set once this call actually completes the battle
+	err := s.store.WithContext(ctx).Transaction(func(tx repository.Store) error {
+		ledger := s.ledger.WithStore(tx)
+		battle, err := tx.Battles().Get(battleID)
+		if err != nil {
 			return err
 		}
 
@@ -546,7 +629,11 @@ func (s *BattleService) CompleteBattle(battleID uint, winnerID uint, replayData
 			// battle.ReplayLog = replayData // Assuming field exists or we add it
 		}
 
-		if err := tx.Save(&battle).Error; err != nil {
+		if err := tx.Battles().Save(battle); err != nil {
+			return err
+		}
+		completed = battle.BattleType
+		if err := s.wearTeams(tx, battle, winnerID); err != nil {
 			return err
 		}
 
@@ -565,9 +652,9 @@ func (s *BattleService) CompleteBattle(battleID uint, winnerID uint, replayData
 
 			// Dynamic Payout based on Difficulty/Risk
 			// Logic: Winner gets their own bet back + Opponent's bet (minus fee)
-			// Fee is 5% logic on winnings
+			// Fee is wager_tax_percent (5% default) of the winnings
 
-			feeRate := 0.05
+			feeRate := float64(s.engine.config.GetInt("wager_tax_percent", 5)) / 100
 			var winnerBet, loserBet int64
 
 			if winnerID == battle.Player1ID {
@@ -583,75 +670,92 @@ func (s *BattleService) CompleteBattle(battleID uint, winnerID uint, replayData
 			winnerPayout := winnerBet + winnings
 			treasuryAmount := fee
 
+			// Owners of borrowed characters get their share of the winnings, not of the stake
+			shares, shared, err := s.winnerShares(tx, ledger, winnerID, winnings)
+			if err != nil {
+				return err
+			}
+
 			// Transfer Escrow -> Winner & Treasury
-			escrowAcc, _ := s.ledger.GetOrCreateAccount(nil, models.AccountTypeEscrow, "GTK")
-			winnerAcc, _ := s.ledger.GetOrCreateAccount(&winnerID, models.AccountTypeWallet, "GTK")
-			treasuryAcc, _ := s.ledger.GetOrCreateAccount(nil, models.AccountTypeTreasury, "GTK")
+			escrowAcc, _ := ledger.GetOrCreateAccount(nil, models.AccountTypeEscrow, "GTK")
+			winnerAcc, _ := ledger.GetOrCreateAccount(&winnerID, models.AccountTypeWallet, "GTK")
+			treasuryAcc, _ := ledger.GetOrCreateAccount(nil, models.AccountTypeTreasury, "GTK")
 
 			entries := []models.LedgerEntry{
 				{AccountID: escrowAcc.ID, Amount: -pot, Type: "DEBIT"}, // Drain total pot
-				{AccountID: winnerAcc.ID, Amount: winnerPayout, Type: "CREDIT"},
+				{AccountID: winnerAcc.ID, Amount: winnerPayout - shared, Type: "CREDIT"},
 				{AccountID: treasuryAcc.ID, Amount: treasuryAmount, Type: "CREDIT"},
 			}
+			entries = append(entries, shares...)
 
 			desc := "Wager Win Payout (Risk Reward)"
-			if err := s.ledger.CreateTransactionWithTx(tx, models.TxTypeWagerWin, fmt.Sprintf("wager_win_%d", battleID), desc, entries); err != nil {
+			if err := ledger.CreateTransaction(models.TxTypeWagerWin, fmt.Sprintf("wager_win_%d", battleID), desc, entries); err != nil {
 				return err
 			}
 		} else if battle.BattleType == "ranked" {
 			// Rank Reward: 25 GTK
-			userAcc, _ := s.ledger.GetOrCreateAccount(&winnerID, models.AccountTypeWallet, "GTK")
-			rewardAcc, _ := s.ledger.GetOrCreateAccount(nil, models.AccountTypeReward, "GTK")
+			shares, shared, err := s.winnerShares(tx, ledger, winnerID, 25)
+			if err != nil {
+				return err
+			}
+			userAcc, _ := ledger.GetOrCreateAccount(&winnerID, models.AccountTypeWallet, "GTK")
+			rewardAcc, _ := ledger.GetOrCreateAccount(nil, models.AccountTypeReward, "GTK")
 
 			entries := []models.LedgerEntry{
 				{AccountID: rewardAcc.ID, Amount: -25, Type: "DEBIT"},
-				{AccountID: userAcc.ID, Amount: 25, Type: "CREDIT"},
+				{AccountID: userAcc.ID, Amount: 25 - shared, Type: "CREDIT"},
 			}
-			s.ledger.CreateTransactionWithTx(tx, models.TxTypeRankedReward, fmt.Sprintf("battle_%d", battleID), "Ranked Win", entries)
+			entries = append(entries, shares...)
+			ledger.CreateTransaction(models.TxTypeRankedReward, fmt.Sprintf("battle_%d", battleID), "Ranked Win", entries)
 		} else if strings.Contains(battle.BattleType, "PVE") {
 			// PvE Reward: Small Token + XP?
 			// For MVP: 10 GTK
-			userAcc, _ := s.ledger.GetOrCreateAccount(&winnerID, models.AccountTypeWallet, "GTK")
-			rewardAcc, _ := s.ledger.GetOrCreateAccount(nil, models.AccountTypeReward, "GTK")
+			shares, shared, err := s.winnerShares(tx, ledger, winnerID, 10)
+			if err != nil {
+				return err
+			}
+			userAcc, _ := ledger.GetOrCreateAccount(&winnerID, models.AccountTypeWallet, "GTK")
+			rewardAcc, _ := ledger.GetOrCreateAccount(nil, models.AccountTypeReward, "GTK")
 
 			entries := []models.LedgerEntry{
 				{AccountID: rewardAcc.ID, Amount: -10, Type: "DEBIT"},
-				{AccountID: userAcc.ID, Amount: 10, Type: "CREDIT"},
+				{AccountID: userAcc.ID, Amount: 10 - shared, Type: "CREDIT"},
 			}
-			s.ledger.CreateTransactionWithTx(tx, models.TxTypeReward, fmt.Sprintf("pve_win_%d", battleID), "PvE Victory Reward", entries)
+			entries = append(entries, shares...)
+			ledger.CreateTransaction(models.TxTypeReward, fmt.Sprintf("pve_win_%d", battleID), "PvE Victory Reward", entries)
 		}
 
 		// --- POST-BATTLE HOOKS: Stats, Elo, XP ---
 		// 1. Fetch Users
-		var winner, loser models.User
-		if err := tx.First(&winner, winnerID).Error; err != nil {
+		winner, err := tx.Users().Get(winnerID)
+		if err != nil {
 			return err
 		}
 		// Loser might be AI/System (0) or actual player?
 		// For PvP/Wager/Ranked, loser is real.
 		// For PvE, loser is System (ID 0? No, usually not stored as User).
 		// check if PvP
-		isPvP := battle.BattleType == "wager" || battle.BattleType == "ranked" || battle.BattleType == "pvp"
+		// Tournament matches are rated like ranked games; their prizes are paid by the tournament
+		rated := battle.BattleType == "ranked" || battle.BattleType == "wager" || battle.BattleType == "tournament"
+		isPvP := rated || battle.BattleType == "pvp"
 
 		loserID := battle.Player1ID
 		if winnerID == battle.Player1ID {
 			loserID = battle.Player2ID
 		}
 
+		loser := &models.User{}
 		if isPvP {
-			if err := tx.First(&loser, loserID).Error; err != nil {
+			if loser, err = tx.Users().Get(loserID); err != nil {
 				return err
 			}
+			eloGap := loser.ELO - winner.ELO
 
-			// 2. Update Elo (Ranked/Wager only)
-			if battle.BattleType == "ranked" || battle.BattleType == "wager" {
-				kFactor := 32
-				expectedWin := 1.0 / (1.0 + float64(loser.ELO-winner.ELO)/400.0)
-				// expectedLoss := 1.0 - expectedWin // unused
-
-				eloChange := int(float64(kFactor) * (1.0 - expectedWin))
-				winner.ELO += eloChange
-				loser.ELO -= eloChange
+			// 2. Update Elo (Ranked/Wager/Tournament only)
+			if rated {
+				change := eloChange(winner.ELO, loser.ELO)
+				winner.ELO += change
+				loser.ELO -= change
 				if loser.ELO < 0 {
 					loser.ELO = 0
 				} // No negative Elo
@@ -662,6 +766,10 @@ func (s *BattleService) CompleteBattle(battleID uint, winnerID uint, replayData
 			winner.CurrentWinStreak++
 			loser.PvPLosses++
 			loser.CurrentWinStreak = 0
+
+			if err := s.recordBattleAchievements(tx, battleID, winner, rated, eloGap); err != nil {
+				return err
+			}
 		}
 
 		// 4. Grant XP (Winner)
@@ -674,17 +782,109 @@ func (s *BattleService) CompleteBattle(battleID uint, winnerID uint, replayData
 			winner.Experience -= needed
 		}
 
-		if err := tx.Save(&winner).Error; err != nil {
+		if err := tx.Users().Save(winner); err != nil {
 			return err
 		}
 		if isPvP {
-			if err := tx.Save(&loser).Error; err != nil {
+			if err := tx.Users().Save(loser); err != nil {
 				return err
 			}
 		}
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+	if completed != "" {
+		metrics.BattlesCompleted.WithLabelValues(strings.ToLower(completed)).Inc()
+	}
+	return nil
+}
+
+// eloChange is the rating a winner takes from the loser: K = 32 times the winner's chance of
+// losing under the standard logistic Elo curve
+func eloChange(winnerELO, loserELO int) int {
+	expectedWin := 1 / (1 + math.Pow(10, float64(loserELO-winnerELO)/400))
+	return int(32 * (1 - expectedWin))
+}
+
+// wearTeams applies the durability and fatigue a finished battle costs the characters
+// that fought it: those in each side's snapshot, or the side's current team if the
+// battle was never snapshotted. The AI side of a PvE battle has nothing to wear.
+func (s *BattleService) wearTeams(tx repository.Store, battle *models.Battle, winnerID uint) error {
+	type side struct {
+		userID uint
+		state  string
+	}
+	sides := []side{{battle.Player1ID, battle.PlayerStateP1}}
+	if !strings.Contains(battle.BattleType, "PVE") {
+		sides = append(sides, side{battle.Player2ID, battle.PlayerStateP2})
+	}
+
+	for _, side := range sides {
+		var fighters []models.BattleParticipant
+		_ = json.Unmarshal([]byte(side.state), &fighters)
+		var ids []uint
+		for _, p := range fighters {
+			if p.CharacterID != 0 {
+				ids = append(ids, p.CharacterID)
+			}
+		}
+		if len(ids) == 0 {
+			team, err := controlledTeam(tx, side.userID)
+			if err != nil {
+				return err
+			}
+			for _, c := range team {
+				ids = append(ids, c.ID)
+			}
+		}
+		won := winnerID != 0 && side.userID == winnerID
+		if err := applyBattleWear(tx, s.engine.config, ids, battle.BattleType, won, battle.TurnNumber); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// winnerShares returns the rental owners' cut of what the winner earned with their active
+// team (see rentalShares)
+func (s *BattleService) winnerShares(tx repository.Store, ledger *LedgerService, winnerID uint, amount int64) ([]models.LedgerEntry, int64, error) {
+	team, err := controlledTeam(tx, winnerID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	return rentalShares(tx, ledger, winnerID, team, amount)
+}
+
+// recordBattleAchievements adds notable PvP wins to the history of the winner's active
+// team: rated wins over a player at least provenance_upset_elo_gap higher, and win
+// streak milestones
+func (s *BattleService) recordBattleAchievements(tx repository.Store, battleID uint, winner *models.User, rated bool, eloGap int) error {
+	upset := rated && eloGap >= s.engine.config.GetInt("provenance_upset_elo_gap", 200)
+	streak := winStreakMilestones[winner.CurrentWinStreak]
+	if !upset && !streak {
+		return nil
+	}
+	team, err := controlledTeam(tx, winner.ID)
+	if err != nil {
+		return err
+	}
+	if upset {
+		summary := fmt.Sprintf("Beat a player rated %d higher", eloGap)
+		if err := recordAchievement(tx, team, AchievementUpset, summary, map[string]interface{}{"battle_id": battleID, "elo_gap": eloGap}); err != nil {
+			return err
+		}
+	}
+	if streak {
+		summary := fmt.Sprintf("%d wins in a row", winner.CurrentWinStreak)
+		return recordAchievement(tx, team, AchievementWinStreak, summary, map[string]interface{}{"battle_id": battleID, "streak": winner.CurrentWinStreak})
+	}
+	return nil
 }
 
 // ValidateReplay performs basic anti-cheat checks
@@ -710,15 +910,15 @@ func (s *BattleService) ValidateReplay(battleID, winnerID uint, replayData strin
 func (s *BattleService) CheckTimeouts() error {
 	threshold := time.Now().Add(-30 * time.Second)
 
-	var staleBattles []models.Battle
 	// Find active battles updated before threshold
 	// Note: UpdatedAt is updated on every Save() which happens on every Turn.
-	if err := db.DB.Where("status = ? AND updated_at < ?", "active", threshold).Find(&staleBattles).Error; err != nil {
+	staleBattles, err := s.store.Battles().ListStale(threshold)
+	if err != nil {
 		return err
 	}
 
 	for _, battle := range staleBattles {
-		fmt.Printf("Battle %d timed out. Last update: %v\n", battle.ID, battle.UpdatedAt)
+		slog.Info("battle timed out", "battle_id", battle.ID, "last_update", battle.UpdatedAt)
 
 		// Auto-Surrender Current Turn Player
 		var winnerID uint
@@ -733,18 +933,28 @@ func (s *BattleService) CheckTimeouts() error {
 			winnerID = 0
 		}
 
-		if err := s.CompleteBattle(battle.ID, winnerID, ""); err != nil {
-			fmt.Printf("Failed to complete timed out battle %d: %v\n", battle.ID, err)
+		if err := s.CompleteBattle(context.Background(), battle.ID, winnerID, ""); err != nil {
+			slog.Error("failed to complete timed out battle", "battle_id", battle.ID, "error", err)
 		}
 	}
 
 	return nil
 }
 
-// CalculateDynamicStakes determines how much each player MUST risk
+// CalculateDynamicStakes determines how much each player MUST risk. A nil p1Team is fetched.
 func (s *BattleService) CalculateDynamicStakes(p1ID, p2ID uint, p1Team []models.BattleParticipant) (int64, int64, error) {
-	// 1. Get P2 Team
-	p2Team, err := s.GetTeamSnapshot(p2ID)
+	return s.dynamicStakes(s.store, p1ID, p2ID, p1Team)
+}
+
+func (s *BattleService) dynamicStakes(st repository.Store, p1ID, p2ID uint, p1Team []models.BattleParticipant) (int64, int64, error) {
+	// 1. Get Teams
+	if p1Team == nil {
+		var err error
+		if p1Team, err = s.teamSnapshot(st, p1ID); err != nil {
+			return 0, 0, err
+		}
+	}
+	p2Team, err := s.teamSnapshot(st, p2ID)
 	if err != nil {
 		return 0, 0, err
 	}
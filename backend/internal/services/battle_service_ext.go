@@ -2,10 +2,13 @@ package services
 
 import (
 	"errors"
+	"math"
+	"strings"
 	"time"
 
-	"github.com/lorengraff/crypto-tower-defense/internal/db"
 	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/internal/repository"
+	"github.com/lorengraff/crypto-tower-defense/pkg/metrics"
 )
 
 // --- Missing Methods for BattleHandler Compatibility ---
@@ -14,36 +17,30 @@ import (
 // FindMatch attempts to find an opponent for the given user based on ELO
 func (s *BattleService) FindMatch(userID uint, betAmount int64) (*models.User, error) {
 	// 1. Get User's ELO
-	var user models.User
-	if err := db.DB.First(&user, userID).Error; err != nil {
+	users := s.store.Users()
+	user, err := users.Get(userID)
+	if err != nil {
 		return nil, errors.New("user not found")
 	}
 
 	// 2. Search for opponent within ELO range (+/- 200)
-	var opponent models.User
-	err := db.DB.Where("id != ? AND elo_rating BETWEEN ? AND ?", userID, user.ELO-200, user.ELO+200).
-		Order("RANDOM()").
-		First(&opponent).Error
+	opponent, err := users.RandomOpponent(userID, user.ELO-200, user.ELO+200)
 
 	// 3. Fallback: If no close match, widen search (+/- 500)
 	if err != nil {
-		err = db.DB.Where("id != ? AND elo_rating BETWEEN ? AND ?", userID, user.ELO-500, user.ELO+500).
-			Order("RANDOM()").
-			First(&opponent).Error
+		opponent, err = users.RandomOpponent(userID, user.ELO-500, user.ELO+500)
 	}
 
 	// 4. Final Fallback: Any opponent
 	if err != nil {
-		err = db.DB.Where("id != ?", userID).
-			Order("RANDOM()").
-			First(&opponent).Error
+		opponent, err = users.RandomOpponent(userID, math.MinInt32, math.MaxInt32)
 	}
 
 	if err != nil {
 		return nil, errors.New("no opponent found")
 	}
 
-	return &opponent, nil
+	return opponent, nil
 }
 
 // CreatePvPBattle creates a battle between two players
@@ -65,7 +62,7 @@ func (s *BattleService) CreatePvPBattle(p1ID, p2ID uint, betAmount int64) (*mode
 		return nil, err
 	}
 
-	if err := db.DB.Create(battle).Error; err != nil {
+	if err := s.store.Battles().Create(battle); err != nil {
 		return nil, err
 	}
 	return battle, nil
@@ -78,45 +75,56 @@ func (s *BattleService) StartBattle(battleID uint) (*models.Battle, error) {
 
 // SurrenderBattle handles a player surrendering
 func (s *BattleService) SurrenderBattle(battleID, userID uint) error {
-	battle, err := s.GetBattleByID(battleID)
+	var battleType string
+	err := s.store.Transaction(func(tx repository.Store) error {
+		battle, err := tx.Battles().Get(battleID)
+		if err != nil {
+			return err
+		}
+		if battle.Status != "active" {
+			return errors.New("battle not active")
+		}
+
+		// Set winner to the other player
+		var winnerID uint
+		if battle.Player1ID == userID {
+			winnerID = battle.Player2ID
+		} else {
+			winnerID = battle.Player1ID
+		}
+
+		battle.WinnerID = &winnerID
+		battle.Status = "completed"
+		now := time.Now()
+		battle.EndedAt = &now
+		if err := tx.Battles().Save(battle); err != nil {
+			return err
+		}
+		battleType = battle.BattleType
+
+		// Giving up still costs the team what it fought; in PvE both sides carry the
+		// player's ID, so nobody on it won
+		wearWinner := winnerID
+		if strings.Contains(battle.BattleType, "PVE") {
+			wearWinner = 0
+		}
+		return s.wearTeams(tx, battle, wearWinner)
+	})
 	if err != nil {
 		return err
 	}
-	if battle.Status != "active" {
-		return errors.New("battle not active")
-	}
-
-	// Set winner to the other player
-	var winnerID uint
-	if battle.Player1ID == userID {
-		winnerID = battle.Player2ID
-	} else {
-		winnerID = battle.Player1ID
-	}
-
-	battle.WinnerID = &winnerID
-	battle.Status = "completed"
-	return db.DB.Save(battle).Error
+	metrics.BattlesCompleted.WithLabelValues(strings.ToLower(battleType)).Inc()
+	return nil
 }
 
 // GetBattleByID retrieves a battle by ID with preloads
 func (s *BattleService) GetBattleByID(id uint) (*models.Battle, error) {
-	var battle models.Battle
-	err := db.DB.Preload("Player1").Preload("Player2").First(&battle, id).Error
-	if err != nil {
-		return nil, err
-	}
-	return &battle, nil
+	return s.store.Battles().Get(id)
 }
 
 // GetBattleHistory retrieves completed battles for a user
 func (s *BattleService) GetBattleHistory(userID uint, limit int) ([]models.Battle, error) {
-	var battles []models.Battle
-	err := db.DB.Where("(player1_id = ? OR player2_id = ?) AND status = ?", userID, userID, "completed").
-		Order("created_at desc").
-		Limit(limit).
-		Find(&battles).Error
-	return battles, err
+	return s.store.Battles().History(userID, limit)
 }
 
 // RequestRematch creates a new battle with the same participants
@@ -0,0 +1,248 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/internal/repository"
+)
+
+func newTestBattleService(st repository.Store) (*BattleService, *LedgerService) {
+	ledger := NewLedgerService(st)
+	svc := NewBattleService(st, ledger, testSkills{}, testEffects{})
+	// Deterministic combat: no crits, no damage spread
+	svc.engine = &BattleEngine{config: testSettings{"battle_crit_chance": 0, "battle_randomness_factor": 0}}
+	return svc, ledger
+}
+
+// newTestPlayer creates a user with a one-character active team
+func newTestPlayer(t *testing.T, st *repository.MemoryStore, hp, attack int) (*models.User, *models.Character) {
+	t.Helper()
+	u := newTestUser(t, st, 1000)
+	c := newTestCharacter(t, st, u.ID, hp, attack)
+	st.SetActiveTeam(u.ID, c.ID)
+	return u, c
+}
+
+func TestCreatePvEBattleSnapshotsActiveTeam(t *testing.T) {
+	st := repository.NewMemoryStore()
+	svc, _ := newTestBattleService(st)
+	player, _ := newTestPlayer(t, st, 120, 50)
+
+	battle, err := svc.CreatePvEBattle(player.ID, "PVE_STORY")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(battle.PlayerStateP1, "Test Monster") {
+		t.Fatalf("player snapshot missing team: %s", battle.PlayerStateP1)
+	}
+	if !strings.Contains(battle.PlayerStateP2, "Goblin Scout") {
+		t.Fatalf("AI snapshot missing enemy: %s", battle.PlayerStateP2)
+	}
+	if _, err := svc.GetBattleByID(battle.ID); err != nil {
+		t.Fatalf("battle not stored: %v", err)
+	}
+
+	loner := newTestUser(t, st, 1000)
+	if _, err := svc.CreatePvEBattle(loner.ID, "PVE_STORY"); err == nil {
+		t.Fatal("battle created without an active team")
+	}
+}
+
+func TestDynamicStakesMakeStrongerTeamRiskMore(t *testing.T) {
+	st := repository.NewMemoryStore()
+	svc, _ := newTestBattleService(st)
+	strong, _ := newTestPlayer(t, st, 500, 200)
+	weak, _ := newTestPlayer(t, st, 100, 20)
+
+	strongStake, weakStake, err := svc.CalculateDynamicStakes(strong.ID, weak.ID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strongStake <= 100 || weakStake >= 100 {
+		t.Fatalf("stakes = %d/%d, want stronger team above and weaker below the 100 base", strongStake, weakStake)
+	}
+}
+
+func TestProcessTurnBasicAttackEndsBattle(t *testing.T) {
+	ctx := context.Background()
+	st := repository.NewMemoryStore()
+	svc, ledger := newTestBattleService(st)
+	p1, c1 := newTestPlayer(t, st, 100, 100)
+	p2, c2 := newTestPlayer(t, st, 1, 10)
+	p2.ELO = 1200 // Equal ratings move nothing; an upset does
+	if err := st.Users().Save(p2); err != nil {
+		t.Fatal(err)
+	}
+
+	battle := &models.Battle{BattleType: "ranked", Status: "active", Player1ID: p1.ID, Player2ID: p2.ID, CurrentTurnPlayerID: p1.ID, TurnNumber: 1}
+	if err := st.Battles().Create(battle); err != nil {
+		t.Fatal(err)
+	}
+	attack := map[string]interface{}{"action": "attack", "character_id": float64(c1.ID), "target_id": float64(c2.ID)}
+
+	if _, err := svc.ProcessTurn(ctx, battle.ID, p2.ID, attack); err == nil || err.Error() != "not your turn" {
+		t.Fatalf("out-of-turn action: err = %v", err)
+	}
+
+	result, err := svc.ProcessTurn(ctx, battle.ID, p1.ID, attack)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Status != "completed" || result.WinnerID == nil || *result.WinnerID != p1.ID {
+		t.Fatalf("battle = %s winner %v, want completed with player 1", result.Status, result.WinnerID)
+	}
+	if got := balance(t, ledger, &p1.ID, models.AccountTypeWallet); got != 25 {
+		t.Fatalf("ranked reward = %d, want 25", got)
+	}
+	winner, _ := st.Users().Get(p1.ID)
+	loser, _ := st.Users().Get(p2.ID)
+	if winner.ELO != 1024 || loser.ELO != 1176 || winner.PvPWins != 1 || loser.PvPLosses != 1 {
+		t.Fatalf("stats not updated: winner elo %d wins %d, loser elo %d losses %d", winner.ELO, winner.PvPWins, loser.ELO, loser.PvPLosses)
+	}
+}
+
+func TestEloChangeAcrossRatingGaps(t *testing.T) {
+	cases := []struct {
+		winner, loser, want int
+	}{
+		{1000, 1000, 16},
+		{1000, 1200, 24}, // Upset: 32 * (1 - 0.240)
+		{1000, 1400, 29},
+		{1000, 1800, 31},
+		{1400, 1000, 2}, // Favourite 400 up: 32 * (1 - 0.909)
+		{1500, 1000, 1},
+		{2000, 1000, 0},
+	}
+	for _, tc := range cases {
+		if got := eloChange(tc.winner, tc.loser); got != tc.want {
+			t.Errorf("eloChange(%d, %d) = %d, want %d", tc.winner, tc.loser, got, tc.want)
+		}
+	}
+}
+
+func TestWagerQueueLocksStakesAndPaysWinner(t *testing.T) {
+	ctx := context.Background()
+	st := repository.NewMemoryStore()
+	svc, ledger := newTestBattleService(st)
+	p1, _ := newTestPlayer(t, st, 300, 100)
+	p2, _ := newTestPlayer(t, st, 150, 50)
+	fund(t, ledger, p1.ID, 1000)
+	fund(t, ledger, p2.ID, 1000)
+
+	queued, err := svc.EnterWagerQueue(p1.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if queued.Matched {
+		t.Fatal("first player matched against an empty queue")
+	}
+	var inProgress *BattleInProgressError
+	if _, err := svc.EnterWagerQueue(p1.ID); !errors.As(err, &inProgress) || inProgress.Battle.ID != queued.BattleID {
+		t.Fatalf("re-entering the queue: err = %v", err)
+	}
+
+	match, err := svc.EnterWagerQueue(p2.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !match.Matched || match.BattleID != queued.BattleID {
+		t.Fatalf("second player not matched: %+v", match)
+	}
+	stake1, stake2 := match.EnemyStake, match.YourStake
+	if got := balance(t, ledger, nil, models.AccountTypeEscrow); got != stake1+stake2 {
+		t.Fatalf("escrow = %d, want %d", got, stake1+stake2)
+	}
+
+	if err := svc.CompleteBattle(ctx, match.BattleID, p2.ID, ""); err != nil {
+		t.Fatal(err)
+	}
+	fee := stake1 * 5 / 100
+	if got, want := balance(t, ledger, &p2.ID, models.AccountTypeWallet), 1000+stake1-fee; got != want {
+		t.Fatalf("winner balance = %d, want %d", got, want)
+	}
+	if got := balance(t, ledger, &p1.ID, models.AccountTypeWallet); got != 1000-stake1 {
+		t.Fatalf("loser balance = %d, want %d", got, 1000-stake1)
+	}
+	if got := balance(t, ledger, nil, models.AccountTypeEscrow); got != 0 {
+		t.Fatalf("escrow = %d after payout", got)
+	}
+	if got := balance(t, ledger, nil, models.AccountTypeTreasury); got != fee {
+		t.Fatalf("treasury = %d, want fee %d", got, fee)
+	}
+
+	// Completing twice must not pay twice
+	if err := svc.CompleteBattle(ctx, match.BattleID, p2.ID, ""); err != nil {
+		t.Fatal(err)
+	}
+	if got := balance(t, ledger, nil, models.AccountTypeTreasury); got != fee {
+		t.Fatalf("treasury = %d after repeated completion", got)
+	}
+}
+
+func TestWagerQueueRequiresMinimumBalance(t *testing.T) {
+	st := repository.NewMemoryStore()
+	svc, ledger := newTestBattleService(st)
+	player, _ := newTestPlayer(t, st, 100, 50)
+	fund(t, ledger, player.ID, WagerMinBalance-1)
+
+	if _, err := svc.EnterWagerQueue(player.ID); !errors.Is(err, ErrWagerBalanceTooLow) {
+		t.Fatalf("err = %v, want ErrWagerBalanceTooLow", err)
+	}
+}
+
+func TestCancelWagerSearch(t *testing.T) {
+	st := repository.NewMemoryStore()
+	svc, ledger := newTestBattleService(st)
+	player, _ := newTestPlayer(t, st, 100, 50)
+	fund(t, ledger, player.ID, 1000)
+
+	queued, err := svc.EnterWagerQueue(player.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.CancelWagerSearch(player.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.GetBattleByID(queued.BattleID); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("search still stored: err = %v", err)
+	}
+	if err := svc.CancelWagerSearch(player.ID); err == nil {
+		t.Fatal("cancelled a search that no longer exists")
+	}
+	if got := balance(t, ledger, &player.ID, models.AccountTypeWallet); got != 1000 {
+		t.Fatalf("balance = %d, want untouched 1000", got)
+	}
+}
+
+func TestTournamentMatchIsRated(t *testing.T) {
+	st := repository.NewMemoryStore()
+	svc, ledger := newTestBattleService(st)
+	p1, _ := newTestPlayer(t, st, 100, 50)
+	p2, _ := newTestPlayer(t, st, 100, 50)
+	p2.ELO = 1200
+	if err := st.Users().Save(p2); err != nil {
+		t.Fatal(err)
+	}
+
+	battle := &models.Battle{BattleType: "tournament", Status: "active", Player1ID: p1.ID, Player2ID: p2.ID}
+	if err := st.Battles().Create(battle); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.CompleteBattle(context.Background(), battle.ID, p1.ID, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	winner, _ := st.Users().Get(p1.ID)
+	loser, _ := st.Users().Get(p2.ID)
+	if winner.ELO != 1024 || loser.ELO != 1176 || winner.PvPWins != 1 || loser.PvPLosses != 1 {
+		t.Fatalf("winner elo %d wins %d, loser elo %d losses %d, want a rated result", winner.ELO, winner.PvPWins, loser.ELO, loser.PvPLosses)
+	}
+	// Prizes come from the tournament, not the match
+	if got := balance(t, ledger, &p1.ID, models.AccountTypeWallet); got != 0 {
+		t.Fatalf("match paid %d GTK, want 0", got)
+	}
+}
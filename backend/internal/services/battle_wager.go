@@ -0,0 +1,174 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/internal/repository"
+)
+
+// WagerMinBalance is the GTK a player must hold to enter the wager arena
+const WagerMinBalance = int64(500)
+
+// ErrWagerBalanceTooLow is returned when the player is below WagerMinBalance
+var ErrWagerBalanceTooLow = fmt.Errorf("insufficient balance. Need %d GTK to enter High Stakes", WagerMinBalance)
+
+// BattleInProgressError is returned when the player is already queued or fighting
+type BattleInProgressError struct {
+	Battle *models.Battle
+}
+
+func (e *BattleInProgressError) Error() string {
+	return "you are already in a battle or queue"
+}
+
+// WagerQueueResult describes the outcome of entering the wager queue
+type WagerQueueResult struct {
+	BattleID   uint
+	Matched    bool  // false: the player now waits in the queue as player 1
+	YourStake  int64 // Set when matched
+	EnemyStake int64
+}
+
+// EnterWagerQueue matches the player against the oldest open wager search, or opens a new one.
+// Stakes are only calculated and locked in escrow once both players are known.
+func (s *BattleService) EnterWagerQueue(userID uint) (*WagerQueueResult, error) {
+	// 1. SECURITY: Minimum Balance Validation (Proof of Solvency)
+	userAcc, err := s.ledger.GetOrCreateAccount(&userID, models.AccountTypeWallet, "GTK")
+	if err != nil {
+		return nil, err
+	}
+	if userAcc.Balance < WagerMinBalance {
+		return nil, ErrWagerBalanceTooLow
+	}
+
+	// 2. CHECK if user is already in a battle
+	if active, err := s.store.Battles().FindInProgress(userID); err == nil {
+		return nil, &BattleInProgressError{Battle: active}
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+
+	// 3. TEAM VALIDATION: stakes are derived from team CP
+	if _, err := s.GetTeamSnapshot(userID); err != nil {
+		return nil, err
+	}
+
+	result := &WagerQueueResult{}
+	err = s.store.Transaction(func(tx repository.Store) error {
+		ledger := s.ledger.WithStore(tx)
+
+		match, err := tx.Battles().LockOpenWager(userID)
+		if errors.Is(err, repository.ErrNotFound) {
+			// --- NO MATCH: CREATE SEARCH ---
+			// No funds locked yet; BetAmount/Stakes unknown until an opponent appears
+			search := models.Battle{
+				BattleType: "wager",
+				Status:     "SEARCHING",
+				Player1ID:  userID,
+			}
+			if err := tx.Battles().Create(&search); err != nil {
+				return err
+			}
+			result.BattleID = search.ID
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		// --- MATCH FOUND: P1 is the waiting player, P2 the current user ---
+		stake1, stake2, err := s.dynamicStakes(tx, match.Player1ID, userID, nil)
+		if err != nil {
+			return err
+		}
+
+		p1Acc, err := ledger.GetOrCreateAccount(&match.Player1ID, models.AccountTypeWallet, "GTK")
+		if err != nil {
+			return err
+		}
+		p2Acc, err := ledger.GetOrCreateAccount(&userID, models.AccountTypeWallet, "GTK")
+		if err != nil {
+			return err
+		}
+		if p1Acc.Balance < stake1 {
+			// P1 may have withdrawn while waiting; let the client retry
+			return errors.New("opponent insufficient funds")
+		}
+		if p2Acc.Balance < stake2 {
+			return fmt.Errorf("insufficient GTK balance for required stake: %d", stake2)
+		}
+
+		// Lock both stakes in escrow
+		escrowAcc, err := ledger.GetOrCreateAccount(nil, models.AccountTypeEscrow, "GTK")
+		if err != nil {
+			return err
+		}
+		locks := []struct {
+			acc   *models.LedgerAccount
+			stake int64
+			role  string
+			desc  string
+		}{{p1Acc, stake1, "p1", "Wager Lock P1"}, {p2Acc, stake2, "p2", "Wager Lock P2"}}
+		for _, l := range locks {
+			entries := []models.LedgerEntry{
+				{AccountID: l.acc.ID, Amount: -l.stake, Type: "DEBIT"},
+				{AccountID: escrowAcc.ID, Amount: l.stake, Type: "CREDIT"},
+			}
+			if err := ledger.CreateTransaction(models.TxTypeWagerEnter, fmt.Sprintf("wager_%d_%s", match.ID, l.role), l.desc, entries); err != nil {
+				return err
+			}
+		}
+
+		// Start Battle
+		match.Player2ID = userID
+		match.Status = "active"
+		match.Player1Bet = stake1
+		match.Player2Bet = stake2
+		if err := s.initializeBattleState(tx, match); err != nil {
+			return err
+		}
+		if err := tx.Battles().Save(match); err != nil {
+			return err
+		}
+
+		*result = WagerQueueResult{BattleID: match.ID, Matched: true, YourStake: stake2, EnemyStake: stake1}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CancelWagerSearch leaves the wager queue, refunding any legacy fixed bet held in escrow
+func (s *BattleService) CancelWagerSearch(userID uint) error {
+	return s.store.Transaction(func(tx repository.Store) error {
+		battle, err := tx.Battles().LockWagerSearch(userID)
+		if err != nil {
+			return errors.New("no active wager search found")
+		}
+
+		if battle.BetAmount > 0 {
+			ledger := s.ledger.WithStore(tx)
+			userAcc, err := ledger.GetOrCreateAccount(&userID, models.AccountTypeWallet, "GTK")
+			if err != nil {
+				return err
+			}
+			escrowAcc, err := ledger.GetOrCreateAccount(nil, models.AccountTypeEscrow, "GTK")
+			if err != nil {
+				return err
+			}
+			entries := []models.LedgerEntry{
+				{AccountID: escrowAcc.ID, Amount: -battle.BetAmount, Type: "DEBIT"},
+				{AccountID: userAcc.ID, Amount: battle.BetAmount, Type: "CREDIT"},
+			}
+			if err := ledger.CreateTransaction(models.TxTypeWagerRefund, fmt.Sprintf("refund_%d", battle.ID), "Wager Cancel Refund", entries); err != nil {
+				return err
+			}
+		}
+
+		return tx.Battles().Delete(battle)
+	})
+}
@@ -4,12 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/lorengraff/crypto-tower-defense/pkg/config"
+	"github.com/lorengraff/crypto-tower-defense/pkg/tracing"
 )
 
 type BlockchainService struct {
@@ -41,11 +43,13 @@ func NewBlockchainService(cfg *config.Config) (*BlockchainService, error) {
 }
 
 // VerifyTransaction checks if a transaction is valid, successful, and transferred the correct amount of GTK to the treasury
-func (s *BlockchainService) VerifyTransaction(txHashStr string, expectedAmount *big.Int) error {
+func (s *BlockchainService) VerifyTransaction(txHashStr string, expectedAmount *big.Int) (err error) {
 	txHash := common.HexToHash(txHashStr)
+	ctx, span := tracing.Start(context.Background(), "chain.verify_transaction", "tx_hash", txHashStr)
+	defer func() { span.End(err) }()
 
 	// 1. Get Transaction Receipt to check status
-	receipt, err := s.client.TransactionReceipt(context.Background(), txHash)
+	receipt, err := s.client.TransactionReceipt(ctx, txHash)
 	if err != nil {
 		if errors.Is(err, ethereum.NotFound) {
 			return errors.New("transaction not found on chain (yet)")
@@ -96,6 +100,6 @@ func (s *BlockchainService) VerifyTransaction(txHashStr string, expectedAmount *
 		return fmt.Errorf("no valid transfer of %s GTK to treasury found in this transaction", expectedAmount.String())
 	}
 
-	fmt.Printf("✅ Verified Tx %s: Transferred %s GTK\n", txHashStr, transferredAmount.String())
+	slog.Info("verified payment transaction", "tx_hash", txHashStr, "amount", transferredAmount.String())
 	return nil
 }
@@ -1,27 +1,35 @@
 package services
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
+	"math"
 	"math/big"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/lorengraff/crypto-tower-defense/internal/db"
 	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/internal/repository"
 	"gorm.io/gorm"
 )
 
 // BreedingService handles breeding operations with comprehensive security
 type BreedingService struct {
+	store      repository.Store
 	ledger     *LedgerService
-	config     *ConfigService
+	config     Settings
 	blockchain *BlockchainService
 }
 
-func NewBreedingService(bc *BlockchainService) *BreedingService {
+func NewBreedingService(store repository.Store, bc *BlockchainService, ledger *LedgerService, config Settings) *BreedingService {
 	return &BreedingService{
-		ledger:     NewLedgerService(),
-		config:     GetConfigService(),
+		store:      store,
+		ledger:     ledger,
+		config:     config,
 		blockchain: bc,
 	}
 }
@@ -45,6 +53,9 @@ func (s *BreedingService) StartBreeding(userID, parent1ID, parent2ID uint, txHas
 	if parent1.OwnerID != userID || parent2.OwnerID != userID {
 		return nil, errors.New("you don't own both parents")
 	}
+	if parent1.ControllerID != nil || parent2.ControllerID != nil {
+		return nil, errors.New("cannot breed rented characters")
+	}
 
 	// SECURITY CHECK 3: Check parents not in active battle
 	var activeBattle models.Battle
@@ -78,7 +89,11 @@ func (s *BreedingService) StartBreeding(userID, parent1ID, parent2ID uint, txHas
 		}
 	}
 
-	breedingCost := int64(s.config.GetInt("breeding_cost", 500))
+	// Parents that have bred often or are related cost more to breed
+	breedingCost, _, _, err := s.breedingCost(&parent1, &parent2)
+	if err != nil {
+		return nil, err
+	}
 
 	// SECURITY CHECK 6: Payment Verification
 	var user models.User
@@ -92,7 +107,7 @@ func (s *BreedingService) StartBreeding(userID, parent1ID, parent2ID uint, txHas
 	if txHash != "" {
 		// Verify Blockchain Transaction
 		if s.blockchain == nil {
-			fmt.Println("⚠️ WARNING: Blockchain verification skipped (Service nil)")
+			slog.Warn("blockchain verification skipped: service unavailable")
 		} else {
 			// Use generic VerifyTransaction (checks Recipient=Treasury and Amount)
 			// Assuming TOWER token
@@ -132,10 +147,6 @@ func (s *BreedingService) StartBreeding(userID, parent1ID, parent2ID uint, txHas
 	}()
 
 	// LEDGER INTEGRATION: Breeding Fee
-	// Create Service Instance if needed
-	if s.ledger == nil {
-		s.ledger = NewLedgerService()
-	}
 	// s.config is already initialized and used for breedingCost
 
 	// Get Accounts
@@ -171,36 +182,22 @@ func (s *BreedingService) StartBreeding(userID, parent1ID, parent2ID uint, txHas
 	// Calculate incubation time based on parent rarities
 	incubationHours := s.calculateIncubationTime(parent1, parent2)
 
-	// Inherit traits from parents for the egg
-	rarity := s.inheritRarity(parent1.Rarity, parent2.Rarity)
+	// Inherit the offspring now, so the egg shows its traits and care works on its stats
+	seed := newGeneSeed()
+	offspring := Breed(genomeOf(&parent1), genomeOf(&parent2), parent1.Rarity, parent2.Rarity, seed, geneticsConfig(s.config))
+	statsJSON, _ := json.Marshal(offspring.Stats)
 
-	// Inherit element (50/50 from either parent)
-	element := parent1.Element
-	if time.Now().Unix()%2 == 0 {
-		element = parent2.Element
-	}
-
-	// Inherit character type (50/50 from either parent)
-	charType := parent1.CharacterType
-	if time.Now().Unix()%3 == 0 {
-		charType = parent2.CharacterType
-	}
-
-	// Inherit class (70% from parents, 30% random)
-	class := parent1.Class
-	if time.Now().Unix()%10 > 7 {
-		class = parent2.Class
-	}
-
-	// Create egg with genetics and all required fields
 	egg := models.Egg{
 		UserID:                  userID,
 		Parent1ID:               &parent1ID,
 		Parent2ID:               &parent2ID,
-		Rarity:                  rarity,
-		Element:                 element,
-		CharacterType:           charType,
-		Class:                   class,
+		Rarity:                  offspring.Rarity,
+		Element:                 offspring.Genome.Element.Dominant,
+		CharacterType:           offspring.Genome.Type.Dominant,
+		Class:                   offspring.Genome.Class.Dominant,
+		PredeterminedStats:      string(statsJSON),
+		Genome:                  encodeGenome(offspring.Genome),
+		GeneSeed:                seed,
 		IncubationTime:          incubationHours,
 		EffectiveIncubationTime: incubationHours,
 		AcceleratorsApplied:     "[]", // Initialize with empty JSON array
@@ -217,10 +214,24 @@ func (s *BreedingService) StartBreeding(userID, parent1ID, parent2ID uint, txHas
 		Action:     "BREEDING",
 		EntityType: "egg",
 		EntityID:   &egg.ID,
-		NewValues:  fmt.Sprintf("parent1:%d,parent2:%d,cost:%d,tx_hash:%s", parent1ID, parent2ID, breedingCost, txHash),
+		NewValues: fmt.Sprintf("parent1:%d,parent2:%d,cost:%d,tx_hash:%s,mutations:%s",
+			parent1ID, parent2ID, breedingCost, txHash, strings.Join(offspring.Mutations, "|")),
 	}
 	tx.Create(&auditLog)
 
+	// Parents keep the genome they passed on and count the breeding
+	now := time.Now()
+	for _, p := range []*models.Character{&parent1, &parent2} {
+		updates := map[string]interface{}{"breed_count": gorm.Expr("breed_count + 1"), "last_bred_at": now}
+		if p.Genome == "" {
+			updates["genome"] = encodeGenome(genomeOf(p))
+		}
+		if err := tx.Model(p).Updates(updates).Error; err != nil {
+			tx.Rollback()
+			return nil, errors.New("failed to update parents")
+		}
+	}
+
 	// Create transaction record
 	desc := fmt.Sprintf("Breeding fee for parents %d and %d", parent1ID, parent2ID)
 	if txHash != "" {
@@ -315,6 +326,12 @@ func (s *BreedingService) HatchEgg(userID, eggID uint) (*models.Character, error
 	}
 
 	// Create new character with inherited traits
+	character, err := s.offspringCharacter(&egg)
+	if err != nil {
+		return nil, err
+	}
+	character.OwnerID = userID
+
 	tx := db.DB.Begin()
 	defer func() {
 		if r := recover(); r != nil {
@@ -322,9 +339,6 @@ func (s *BreedingService) HatchEgg(userID, eggID uint) (*models.Character, error
 		}
 	}()
 
-	character := s.generateOffspring(egg.Parent1, egg.Parent2)
-	character.OwnerID = userID
-
 	// Generate Visual Traits (AI Prompt) for the new offspring
 	// We need an instance of CharacterService to use its helper
 	charService := NewCharacterService()
@@ -334,6 +348,10 @@ func (s *BreedingService) HatchEgg(userID, eggID uint) (*models.Character, error
 		tx.Rollback()
 		return nil, errors.New("failed to create character")
 	}
+	if err := recordOrigin(repository.NewGormStore(tx), character, &egg); err != nil {
+		tx.Rollback()
+		return nil, errors.New("failed to record provenance")
+	}
 
 	// Mark egg as hatched
 	now := time.Now()
@@ -361,71 +379,235 @@ func (s *BreedingService) HatchEgg(userID, eggID uint) (*models.Character, error
 	return character, nil
 }
 
-// generateOffspring creates a new character from two parents
-func (s *BreedingService) generateOffspring(parent1, parent2 *models.Character) *models.Character {
-	// Inherit rarity (70% chance of parent's rarity, 30% chance of upgrade/downgrade)
-	rarity := s.inheritRarity(parent1.Rarity, parent2.Rarity)
+// offspringCharacter builds the character a bred egg hatches into, settling its care
+// windows first. Eggs bred before genetics inherit their genome now.
+func (s *BreedingService) offspringCharacter(egg *models.Egg) (*models.Character, error) {
+	if egg.Parent1 == nil || egg.Parent2 == nil {
+		return nil, errors.New("egg parents not found")
+	}
+	generation := max(egg.Parent1.Generation, egg.Parent2.Generation) + 1
+
+	var care []models.EggCare
+	if egg.Genome == "" {
+		// These eggs had no care windows to settle
+		offspring := Breed(genomeOf(egg.Parent1), genomeOf(egg.Parent2), egg.Parent1.Rarity, egg.Parent2.Rarity, newGeneSeed(), geneticsConfig(s.config))
+		statsJSON, _ := json.Marshal(offspring.Stats)
+		egg.Rarity = offspring.Rarity
+		egg.Element, egg.CharacterType, egg.Class = offspring.Genome.Element.Dominant, offspring.Genome.Type.Dominant, offspring.Genome.Class.Dominant
+		egg.PredeterminedStats = string(statsJSON)
+		egg.Genome = encodeGenome(offspring.Genome)
+	} else {
+		settled, settledCare, err := settleForHatch(s.store, egg.ID, s.config)
+		if err != nil {
+			return nil, err
+		}
+		*egg, care = *settled, settledCare
+	}
 
-	// Inherit character type (50/50 from either parent)
-	charType := parent1.CharacterType
-	if time.Now().Unix()%2 == 0 {
-		charType = parent2.CharacterType
+	var genome Genome
+	var stats map[string]int
+	if err := json.Unmarshal([]byte(egg.Genome), &genome); err != nil {
+		return nil, fmt.Errorf("egg %d: genome: %w", egg.ID, err)
 	}
+	if err := json.Unmarshal([]byte(egg.PredeterminedStats), &stats); err != nil {
+		return nil, fmt.Errorf("egg %d: predetermined stats: %w", egg.ID, err)
+	}
+
+	offspring := &models.Character{
+		Element:        egg.Element,
+		CharacterType:  egg.CharacterType,
+		Class:          egg.Class,
+		Rarity:         egg.Rarity,
+		PassiveAbility: genome.Passive.Dominant,
+		Level:          1,
+		BaseAttack:     stats["atk"],
+		BaseDefense:    stats["def"],
+		BaseHP:         stats["hp"],
+		BaseSpeed:      stats["spd"],
+		Generation:     generation,
+		Genome:         egg.Genome,
+	}
+	applyCareSlots(offspring, care)
+	applySheetStats(offspring, true)
+	return offspring, nil
+}
+
+// breedingCost is what breeding two parents costs: breeding_cost scaled by the pair's
+// family cost modifier, along with the modifier and the ancestors the parents share
+func (s *BreedingService) breedingCost(parent1, parent2 *models.Character) (int64, float64, []uint, error) {
+	depth := s.config.GetInt("breeding_lineage_depth", 3)
+	tree1, err := lineageTree(s.store, parent1, depth)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	tree2, err := lineageTree(s.store, parent2, depth)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	shared := sharedAncestors(tree1, tree2)
+	modifier := familyCostModifier(s.config, parent1) + familyCostModifier(s.config, parent2) - 1 +
+		s.config.GetFloat("breeding_kinship_cost", 0.5)*float64(len(shared))
+	cost := int64(math.Round(float64(s.config.GetInt("breeding_cost", 500)) * modifier))
+	return cost, modifier, shared, nil
+}
 
-	// Inherit class (70% from parents, 30% random)
-	class := parent1.Class
-	if time.Now().Unix()%10 > 7 {
-		classes := []string{"Warrior", "Mage", "Archer", "Tank", "Support"}
-		class = classes[time.Now().Unix()%int64(len(classes))]
+// BreedingPreview is what a breeding would cost and produce, shown before paying
+type BreedingPreview struct {
+	Cost               int64         `json:"cost"`
+	FamilyCostModifier float64       `json:"family_cost_modifier"`
+	SharedAncestors    []uint        `json:"shared_ancestors"`
+	IncubationHours    int           `json:"incubation_hours"`
+	Odds               OffspringOdds `json:"odds"`
+}
+
+// PreviewBreeding returns the cost and the offspring odds of breeding two of the
+// player's characters
+func (s *BreedingService) PreviewBreeding(userID, parent1ID, parent2ID uint) (*BreedingPreview, error) {
+	if parent1ID == parent2ID {
+		return nil, errors.New("cannot breed character with itself")
+	}
+	parent1, err := s.store.Characters().Get(parent1ID)
+	if err != nil {
+		return nil, errors.New("parent 1 not found")
+	}
+	parent2, err := s.store.Characters().Get(parent2ID)
+	if err != nil {
+		return nil, errors.New("parent 2 not found")
+	}
+	if parent1.OwnerID != userID || parent2.OwnerID != userID {
+		return nil, errors.New("you don't own both parents")
+	}
+	if parent1.ControllerID != nil || parent2.ControllerID != nil {
+		return nil, errors.New("cannot breed rented characters")
 	}
 
-	// Calculate base stats (average of parents + small random variation)
-	baseAttack := (parent1.BaseAttack + parent2.BaseAttack) / 2
-	baseDefense := (parent1.BaseDefense + parent2.BaseDefense) / 2
-	baseHP := (parent1.BaseHP + parent2.BaseHP) / 2
-	baseSpeed := (parent1.BaseSpeed + parent2.BaseSpeed) / 2
+	cost, modifier, shared, err := s.breedingCost(parent1, parent2)
+	if err != nil {
+		return nil, err
+	}
+	return &BreedingPreview{
+		Cost:               cost,
+		FamilyCostModifier: math.Round(modifier*100) / 100,
+		SharedAncestors:    shared,
+		IncubationHours:    s.calculateIncubationTime(*parent1, *parent2),
+		Odds:               BreedingOdds(genomeOf(parent1), genomeOf(parent2), parent1.Rarity, parent2.Rarity, geneticsConfig(s.config)),
+	}, nil
+}
 
-	return &models.Character{
-		CharacterType:  charType,
-		Class:          class,
-		Rarity:         rarity,
-		Level:          1,
-		Experience:     0,
-		BaseAttack:     baseAttack,
-		BaseDefense:    baseDefense,
-		BaseHP:         baseHP,
-		BaseSpeed:      baseSpeed,
-		CurrentAttack:  baseAttack,
-		CurrentDefense: baseDefense,
-		CurrentHP:      baseHP,
-		CurrentSpeed:   baseSpeed,
+// LineageNode is a character in a family tree
+type LineageNode struct {
+	CharacterID uint          `json:"character_id"`
+	Name        string        `json:"name"`
+	Rarity      string        `json:"rarity"`
+	Element     string        `json:"element"`
+	Class       string        `json:"class"`
+	Generation  int           `json:"generation"`
+	BreedCount  int           `json:"breed_count"`
+	Parents     []LineageNode `json:"parents,omitempty"` // Empty for minted characters
+}
+
+// ancestors lists every character above n in the tree, once each
+func (n LineageNode) ancestors() []uint {
+	seen := map[uint]bool{}
+	var walk func(LineageNode)
+	walk = func(n LineageNode) {
+		for _, p := range n.Parents {
+			seen[p.CharacterID] = true
+			walk(p)
+		}
+	}
+	walk(n)
+	out := make([]uint, 0, len(seen))
+	for id := range seen {
+		out = append(out, id)
 	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
 }
 
-// inheritRarity determines offspring rarity from parents
-func (s *BreedingService) inheritRarity(rarity1, rarity2 string) string {
-	rarityLevels := map[string]int{
-		"C": 1, "B": 2, "A": 3, "S": 4, "SS": 5, "SSS": 6,
+// Lineage is a character's family tree, up to breeding_lineage_depth generations back
+type Lineage struct {
+	Tree               LineageNode `json:"tree"`
+	Ancestors          []uint      `json:"ancestors"`
+	FamilyCostModifier float64     `json:"family_cost_modifier"` // What this character adds to the cost of breeding it
+}
+
+// GetLineage returns a character's family tree
+func (s *BreedingService) GetLineage(characterID uint) (*Lineage, error) {
+	c, err := s.store.Characters().Get(characterID)
+	if err != nil {
+		return nil, errors.New("character not found")
 	}
-	levelToRarity := map[int]string{
-		1: "C", 2: "B", 3: "A", 4: "S", 5: "SS", 6: "SSS",
+	tree, err := lineageTree(s.store, c, s.config.GetInt("breeding_lineage_depth", 3))
+	if err != nil {
+		return nil, err
 	}
+	return &Lineage{Tree: tree, Ancestors: tree.ancestors(), FamilyCostModifier: familyCostModifier(s.config, c)}, nil
+}
 
-	level1 := rarityLevels[rarity1]
-	level2 := rarityLevels[rarity2]
-	avgLevel := (level1 + level2) / 2
+// lineageTree follows the eggs characters hatched from up to depth generations.
+// Parents that no longer exist are left out.
+func lineageTree(store repository.Store, c *models.Character, depth int) (LineageNode, error) {
+	node := LineageNode{
+		CharacterID: c.ID,
+		Name:        c.Name,
+		Rarity:      c.Rarity,
+		Element:     c.Element,
+		Class:       c.Class,
+		Generation:  c.Generation,
+		BreedCount:  c.BreedCount,
+	}
+	if depth <= 0 {
+		return node, nil
+	}
+	egg, err := store.Eggs().HatchedInto(c.ID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return node, nil
+	}
+	if err != nil {
+		return node, err
+	}
+	for _, parentID := range []*uint{egg.Parent1ID, egg.Parent2ID} {
+		if parentID == nil {
+			continue
+		}
+		parent, err := store.Characters().Get(*parentID)
+		if errors.Is(err, repository.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return node, err
+		}
+		p, err := lineageTree(store, parent, depth-1)
+		if err != nil {
+			return node, err
+		}
+		node.Parents = append(node.Parents, p)
+	}
+	return node, nil
+}
 
-	// 70% chance of average, 20% chance of +1, 10% chance of -1
-	roll := time.Now().Unix() % 100
-	if roll < 70 {
-		return levelToRarity[avgLevel]
-	} else if roll < 90 && avgLevel < 6 {
-		return levelToRarity[avgLevel+1]
-	} else if avgLevel > 1 {
-		return levelToRarity[avgLevel-1]
+// sharedAncestors lists the characters in both family trees, counting each parent as
+// part of its own family so breeding with one's own ancestor counts too
+func sharedAncestors(a, b LineageNode) []uint {
+	inA := map[uint]bool{a.CharacterID: true}
+	for _, id := range a.ancestors() {
+		inA[id] = true
+	}
+	var shared []uint
+	for _, id := range append([]uint{b.CharacterID}, b.ancestors()...) {
+		if inA[id] {
+			shared = append(shared, id)
+		}
 	}
+	sort.Slice(shared, func(i, j int) bool { return shared[i] < shared[j] })
+	return shared
+}
 
-	return levelToRarity[avgLevel]
+// familyCostModifier is the breeding cost multiplier a character brings: each earlier
+// breeding adds breeding_breed_count_cost
+func familyCostModifier(config Settings, c *models.Character) float64 {
+	return 1 + config.GetFloat("breeding_breed_count_cost", 0.25)*float64(c.BreedCount)
 }
 
 // GetUserEggs returns all eggs owned by a user
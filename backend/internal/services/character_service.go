@@ -3,10 +3,12 @@ package services
 import (
 	"errors"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/lorengraff/crypto-tower-defense/internal/db"
 	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/internal/repository"
 )
 
 // CharacterService handles character-related business logic
@@ -28,24 +30,20 @@ func (s *CharacterService) CreateCharacter(ownerID uint, charType, element, rari
 	manaRegen := manaService.GetManaRegenRate(rarity, 1)
 
 	character := &models.Character{
-		OwnerID:        ownerID,
-		CharacterType:  charType,
-		Element:        element,
-		Rarity:         rarity,
-		Class:          class,
-		BaseAttack:     baseStats.Attack,
-		BaseDefense:    baseStats.Defense,
-		BaseHP:         baseStats.HP,
-		BaseSpeed:      baseStats.Speed,
-		CurrentAttack:  baseStats.Attack,
-		CurrentDefense: baseStats.Defense,
-		CurrentHP:      baseStats.HP,
-		CurrentSpeed:   baseStats.Speed,
-		Level:          1,
-		Experience:     0,
-		Durability:     100,
-		Fatigue:        0,
-		Abilities:      "[]", // Empty JSON array, will be populated based on type/element
+		OwnerID:       ownerID,
+		CharacterType: charType,
+		Element:       element,
+		Rarity:        rarity,
+		Class:         class,
+		BaseAttack:    baseStats.Attack,
+		BaseDefense:   baseStats.Defense,
+		BaseHP:        baseStats.HP,
+		BaseSpeed:     baseStats.Speed,
+		Level:         1,
+		Experience:    0,
+		Durability:    100,
+		Fatigue:       0,
+		Abilities:     "[]", // Empty JSON array, will be populated based on type/element
 
 		// Mana System - Calculated based on rarity
 		MaxMana:       maxMana,
@@ -53,12 +51,19 @@ func (s *CharacterService) CreateCharacter(ownerID uint, charType, element, rari
 		ManaRegenRate: manaRegen,
 	}
 
+	applySheetStats(character, true)
+
 	// Generate Visual Traits (Prompt for AI)
 	s.GenerateVisualTraits(character)
 
 	if err := db.DB.Create(character).Error; err != nil {
 		return nil, err
 	}
+	minted := models.CharacterProvenance{CharacterID: character.ID, Event: models.ProvenanceMinted, ToUserID: &ownerID,
+		Summary: fmt.Sprintf("Created as a %s %s %s", rarity, element, class)}
+	if err := recordProvenance(repository.NewGormStore(db.DB), minted, nil); err != nil {
+		slog.Warn("failed to record provenance", "character_id", character.ID, "error", err)
+	}
 
 	// Phase 10.2: Assign default moves based on element
 	s.AssignDefaultMoves(character)
@@ -221,11 +226,8 @@ func (s *CharacterService) HatchEgg(characterID, ownerID uint) (*models.Characte
 	character.BaseHP = int(float64(character.BaseHP) * bonusMultiplier)
 	character.BaseSpeed = int(float64(character.BaseSpeed) * bonusMultiplier)
 
-	// Update current stats
-	character.CurrentAttack = character.BaseAttack
-	character.CurrentDefense = character.BaseDefense
-	character.CurrentHP = character.BaseHP
-	character.CurrentSpeed = character.BaseSpeed
+	// The bonus is part of the base stats from here on; the pipeline does the rest
+	applySheetStats(&character, true)
 
 	// Mark as hatched
 	character.IsEgg = false
@@ -235,47 +237,17 @@ func (s *CharacterService) HatchEgg(characterID, ownerID uint) (*models.Characte
 	if err := db.DB.Save(&character).Error; err != nil {
 		return nil, err
 	}
-
-	return &character, nil
-}
-
-// UpdateDurabilityAndFatigue updates character condition after battle
-func (s *CharacterService) UpdateDurabilityAndFatigue(characterID uint, durabilityLoss, fatigueGain int) error {
-	var character models.Character
-	if err := db.DB.First(&character, characterID).Error; err != nil {
-		return err
-	}
-
-	character.Durability -= durabilityLoss
-	if character.Durability < 0 {
-		character.Durability = 0
-		character.IsDead = true
+	if err := recordHatch(repository.NewGormStore(db.DB), &character); err != nil {
+		slog.Warn("failed to record provenance", "character_id", character.ID, "error", err)
 	}
 
-	character.Fatigue += fatigueGain
-	if character.Fatigue > 100 {
-		character.Fatigue = 100
-	}
-
-	now := time.Now()
-	character.LastBattleAt = &now
-
-	return db.DB.Save(&character).Error
+	return &character, nil
 }
 
-// RecoverFatigue reduces fatigue (farming mechanic)
-func (s *CharacterService) RecoverFatigue(characterID uint, amount int) error {
-	var character models.Character
-	if err := db.DB.First(&character, characterID).Error; err != nil {
-		return err
-	}
-
-	character.Fatigue -= amount
-	if character.Fatigue < 0 {
-		character.Fatigue = 0
-	}
-
-	return db.DB.Save(&character).Error
+// BaseStats returns the level 1 attack, defense, HP and speed of a new character of rarity
+func (s *CharacterService) BaseStats(rarity string) (attack, defense, hp, speed int) {
+	stats := s.calculateBaseStats(rarity)
+	return stats.Attack, stats.Defense, stats.HP, stats.Speed
 }
 
 // calculateBaseStats returns rebalanced base stats (Phase 11 Match Update)
@@ -0,0 +1,283 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/internal/repository"
+)
+
+// CharacterCondition is what wear and tear has done to a character and what fixing it costs
+type CharacterCondition struct {
+	CharacterID    uint   `json:"character_id"`
+	Durability     int    `json:"durability"`
+	Fatigue        int    `json:"fatigue"`         // After rest since the last battle
+	FatiguePenalty int    `json:"fatigue_penalty"` // Percent off attack, defense and speed
+	IsDead         bool   `json:"is_dead"`
+	CanBeRevived   bool   `json:"can_be_revived"`
+	RevivesLeft    int    `json:"revives_left"`
+	RepairCost     int64  `json:"repair_cost"` // GTK to restore full durability
+	ReviveCost     int64  `json:"revive_cost"` // GTK for the next revive
+	Ready          bool   `json:"ready"`
+	Reason         string `json:"reason,omitempty"` // Why the character cannot fight
+}
+
+// ConditionService repairs and revives characters. Battles wear characters down through
+// applyBattleWear: every mode takes durability and adds fatigue by outcome and turns played,
+// fatigue wears off with rest and costs stats meanwhile (see ComputeStats), and a character
+// whose durability runs out dies. Revives get dearer each time and run out for good.
+type ConditionService struct {
+	store  repository.Store
+	ledger *LedgerService
+	config Settings
+}
+
+// NewConditionService creates the condition service
+func NewConditionService(store repository.Store, ledger *LedgerService, config Settings) *ConditionService {
+	return &ConditionService{
+		store:  store,
+		ledger: ledger,
+		config: config,
+	}
+}
+
+// Condition reports the state of a character its owner or controller looks after
+func (s *ConditionService) Condition(userID, characterID uint) (*CharacterCondition, error) {
+	c, err := s.store.Characters().Get(characterID)
+	if err != nil || (c.OwnerID != userID && c.Controller() != userID) {
+		return nil, errors.New("character not found")
+	}
+	fatigue := restedFatigue(c, s.config, time.Now())
+	cond := &CharacterCondition{
+		CharacterID:    c.ID,
+		Durability:     c.Durability,
+		Fatigue:        fatigue,
+		FatiguePenalty: fatiguePenalty(fatigue),
+		IsDead:         c.IsDead,
+		CanBeRevived:   c.CanBeRevived,
+		RevivesLeft:    max(s.config.GetInt("condition_max_revives", 3)-c.ReviveCount, 0),
+		RepairCost:     s.repairCost(c),
+		ReviveCost:     s.reviveCost(c),
+	}
+	if err := CheckBattleReady(c, s.config); err != nil {
+		cond.Reason = err.Error()
+	} else {
+		cond.Ready = true
+	}
+	return cond, nil
+}
+
+// Repair restores a living character's durability for condition_repair_cost_per_point GTK
+// per missing point. The owner or the player controlling it may pay.
+func (s *ConditionService) Repair(userID, characterID uint) (*models.Character, error) {
+	var repaired *models.Character
+	err := s.store.Transaction(func(tx repository.Store) error {
+		c, err := tx.Characters().Lock(characterID)
+		if err != nil || (c.OwnerID != userID && c.Controller() != userID) {
+			return errors.New("character not found")
+		}
+		if c.IsDead {
+			return errors.New("character is dead; revive it first")
+		}
+		cost := s.repairCost(c)
+		if cost == 0 {
+			return errors.New("character is already at full durability")
+		}
+		if err := s.charge(tx, userID, cost, models.TxTypeRepair, fmt.Sprintf("repair_%d_%d", c.ID, time.Now().Unix()),
+			fmt.Sprintf("Repair character #%d", c.ID)); err != nil {
+			return err
+		}
+		c.Durability = 100
+		repaired = c
+		return tx.Characters().Save(c)
+	})
+	return repaired, err
+}
+
+// Revive brings a dead character back at condition_revive_durability. The price doubles
+// with every revive and only condition_max_revives are allowed per character.
+func (s *ConditionService) Revive(userID, characterID uint) (*models.Character, error) {
+	var revived *models.Character
+	err := s.store.Transaction(func(tx repository.Store) error {
+		c, err := tx.Characters().Lock(characterID)
+		if err != nil || c.OwnerID != userID {
+			return errors.New("character not found")
+		}
+		cost := s.reviveCost(c)
+		if err := reviveCharacter(c, s.config); err != nil {
+			return err
+		}
+		if err := s.charge(tx, userID, cost, models.TxTypeRevive, fmt.Sprintf("revive_%d_%d", c.ID, c.ReviveCount),
+			fmt.Sprintf("Revive character #%d (%d)", c.ID, c.ReviveCount)); err != nil {
+			return err
+		}
+		if err := tx.Characters().Save(c); err != nil {
+			return err
+		}
+		revived = c
+		return recordRevive(tx, c)
+	})
+	return revived, err
+}
+
+func (s *ConditionService) repairCost(c *models.Character) int64 {
+	if c.IsDead {
+		return 0
+	}
+	return int64(max(100-c.Durability, 0) * s.config.GetInt("condition_repair_cost_per_point", 2))
+}
+
+func (s *ConditionService) reviveCost(c *models.Character) int64 {
+	return int64(s.config.GetInt("condition_revive_base_cost", 200)) << min(c.ReviveCount, 16)
+}
+
+// charge moves a repair or revive payment from the player's wallet to the sink
+func (s *ConditionService) charge(tx repository.Store, userID uint, cost int64, txType models.TransactionType, refID, desc string) error {
+	if cost <= 0 {
+		return nil
+	}
+	ledger := s.ledger.WithStore(tx)
+	userAcc, err := ledger.GetOrCreateAccount(&userID, models.AccountTypeWallet, "GTK")
+	if err != nil {
+		return err
+	}
+	if userAcc.Balance < cost {
+		return fmt.Errorf("insufficient GTK: %d needed", cost)
+	}
+	sinkAcc, err := ledger.GetOrCreateAccount(nil, models.AccountTypeSink, "GTK")
+	if err != nil {
+		return err
+	}
+	entries := []models.LedgerEntry{
+		{AccountID: userAcc.ID, Amount: -cost, Type: "DEBIT"},
+		{AccountID: sinkAcc.ID, Amount: cost, Type: "CREDIT"},
+	}
+	if err := ledger.CreateTransaction(txType, refID, desc, entries); err != nil {
+		return err
+	}
+	return tx.Users().AdjustLegacyTokens(userID, -cost)
+}
+
+// CheckBattleReady is the one check every battle mode runs on the characters it fields
+func CheckBattleReady(c *models.Character, config Settings) error {
+	if c.IsDead {
+		if !c.CanBeRevived {
+			return fmt.Errorf("character %d is dead for good", c.ID)
+		}
+		return fmt.Errorf("character %d is dead; revive it first", c.ID)
+	}
+	if minimum := config.GetInt("condition_min_durability", 10); c.Durability < minimum {
+		return fmt.Errorf("character %d needs repairs (durability %d, minimum %d)", c.ID, c.Durability, minimum)
+	}
+	if restedFatigue(c, config, time.Now()) >= config.GetInt("condition_exhausted_fatigue", 100) {
+		return fmt.Errorf("character %d is exhausted and needs rest", c.ID)
+	}
+	return nil
+}
+
+// restedFatigue is a character's fatigue after the rest it got since its last battle.
+// Only battles write Fatigue, so LastBattleAt is where recovery starts counting.
+func restedFatigue(c *models.Character, config Settings, now time.Time) int {
+	if c.LastBattleAt == nil || c.Fatigue <= 0 {
+		return max(c.Fatigue, 0)
+	}
+	recovered := int(now.Sub(*c.LastBattleAt).Hours() * float64(config.GetInt("condition_fatigue_recovery_per_hour", 5)))
+	return max(c.Fatigue-recovered, 0)
+}
+
+// withRestedFatigue returns a copy of c carrying its current fatigue, for the stat pipeline
+func withRestedFatigue(c *models.Character, config Settings) models.Character {
+	rested := *c
+	rested.Fatigue = restedFatigue(c, config, time.Now())
+	return rested
+}
+
+// battleWear is the durability a battle costs each fighter and the fatigue it adds: more for
+// a loss and for long fights, scaled by how hard the mode is
+func battleWear(config Settings, mode string, won bool, turns int) (durability, fatigue int) {
+	durability = config.GetInt("condition_durability_loss_loss", 6)
+	if won {
+		durability = config.GetInt("condition_durability_loss_win", 2)
+	}
+	fatigue = config.GetInt("condition_fatigue_per_battle", 5)
+	if per := config.GetInt("condition_turns_per_fatigue", 5); per > 0 {
+		fatigue += turns / per
+	}
+	if per := config.GetInt("condition_turns_per_durability", 15); per > 0 {
+		durability += turns / per
+	}
+	scale := wearScale(mode)
+	return durability * scale / 100, fatigue * scale / 100
+}
+
+// wearScale is how hard a battle mode is on a team, in percent
+func wearScale(mode string) int {
+	switch {
+	case strings.Contains(mode, "PVE"):
+		return 50
+	case mode == "raid":
+		return 150
+	}
+	return 100
+}
+
+// applyBattleWear settles a finished battle for the characters that fought it. Characters
+// are locked in ID order; one whose durability runs out dies, for good once its revives are
+// used up.
+func applyBattleWear(tx repository.Store, config Settings, characterIDs []uint, mode string, won bool, turns int) error {
+	ids := slices.Clone(characterIDs)
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+
+	durability, fatigue := battleWear(config, mode, won, turns)
+	now := time.Now()
+	for _, id := range ids {
+		c, err := tx.Characters().Lock(id)
+		if errors.Is(err, repository.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if c.IsDead {
+			continue
+		}
+		c.Fatigue = min(restedFatigue(c, config, now)+fatigue, 100)
+		c.LastBattleAt = &now
+		c.Durability -= durability
+		if c.Durability <= 0 {
+			c.Durability = 0
+			c.IsDead = true
+			c.CanBeRevived = c.ReviveCount < config.GetInt("condition_max_revives", 3)
+		}
+		if err := tx.Characters().Save(c); err != nil {
+			return err
+		}
+		if c.IsDead {
+			if err := recordDeath(tx, c); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// reviveCharacter brings a dead character back if it has a revive left; paying for it is
+// up to the caller (GTK or a revival item)
+func reviveCharacter(c *models.Character, config Settings) error {
+	if !c.IsDead {
+		return errors.New("character is not dead")
+	}
+	if !c.CanBeRevived || c.ReviveCount >= config.GetInt("condition_max_revives", 3) {
+		return errors.New("character has no revives left")
+	}
+	c.IsDead = false
+	c.ReviveCount++
+	c.Durability = config.GetInt("condition_revive_durability", 50)
+	c.CanBeRevived = c.ReviveCount < config.GetInt("condition_max_revives", 3)
+	return nil
+}
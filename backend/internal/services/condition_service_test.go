@@ -0,0 +1,267 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/internal/repository"
+)
+
+// setCondition writes a character's durability, fatigue and last battle time
+func setCondition(t *testing.T, st repository.Store, c *models.Character, durability, fatigue int, lastBattle time.Time) {
+	t.Helper()
+	c.Durability, c.Fatigue, c.LastBattleAt = durability, fatigue, &lastBattle
+	if err := st.Characters().Save(c); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBattleWearsBothTeamsByOutcomeAndTurns(t *testing.T) {
+	st := repository.NewMemoryStore()
+	svc, _ := newTestBattleService(st)
+	winner, wc := newTestPlayer(t, st, 100, 50)
+	loser, lc := newTestPlayer(t, st, 100, 50)
+
+	battle, err := svc.CreatePvPBattle(winner.ID, loser.ID, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	battle.TurnNumber = 30
+	if err := st.Battles().Save(battle); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.CompleteBattle(context.Background(), battle.ID, winner.ID, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// 30 turns add 2 durability and 6 fatigue on top of the 2/6 durability and 5 fatigue
+	w, _ := st.Characters().Get(wc.ID)
+	l, _ := st.Characters().Get(lc.ID)
+	if w.Durability != 96 || w.Fatigue != 11 {
+		t.Fatalf("winner durability %d fatigue %d, want 96 and 11", w.Durability, w.Fatigue)
+	}
+	if l.Durability != 92 || l.Fatigue != 11 {
+		t.Fatalf("loser durability %d fatigue %d, want 92 and 11", l.Durability, l.Fatigue)
+	}
+	if w.LastBattleAt == nil {
+		t.Fatal("last battle time not recorded")
+	}
+
+	// Completing again changes nothing
+	if err := svc.CompleteBattle(context.Background(), battle.ID, winner.ID, ""); err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := st.Characters().Get(wc.ID); again.Durability != 96 {
+		t.Fatalf("durability = %d after a repeated completion, want 96", again.Durability)
+	}
+}
+
+func TestSurrenderStillWearsTeam(t *testing.T) {
+	st := repository.NewMemoryStore()
+	svc, _ := newTestBattleService(st)
+	player, char := newTestPlayer(t, st, 100, 50)
+
+	battle, err := svc.CreatePvEBattle(player.ID, "PVE_STORY")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.SurrenderBattle(battle.ID, player.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// A lost PvE battle at half wear: 6 durability and 5 fatigue, halved
+	got, _ := st.Characters().Get(char.ID)
+	if got.Durability != 97 || got.Fatigue != 2 {
+		t.Fatalf("durability %d fatigue %d, want 97 and 2", got.Durability, got.Fatigue)
+	}
+	if err := svc.SurrenderBattle(battle.ID, player.ID); err == nil {
+		t.Fatal("surrendered a finished battle")
+	}
+}
+
+func TestGuildRaidAttackWearsTeam(t *testing.T) {
+	st := repository.NewMemoryStore()
+	svc, _ := newTestBattleService(st)
+	player, char := newTestPlayer(t, st, 100, 50)
+
+	team, err := svc.GetTeamSnapshot(player.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := wearGuildRaidTeam(st, testSettings{}, team); err != nil {
+		t.Fatal(err)
+	}
+
+	// A won raid turn at raid wear: 2 durability and 5 fatigue, times 1.5
+	got, _ := st.Characters().Get(char.ID)
+	if got.Durability != 97 || got.Fatigue != 7 {
+		t.Fatalf("durability %d fatigue %d, want 97 and 7", got.Durability, got.Fatigue)
+	}
+}
+
+func TestTournamentTimeoutWearsBothTeams(t *testing.T) {
+	st := repository.NewMemoryStore()
+	svc, _ := newTestBattleService(st)
+	winner, wc := newTestPlayer(t, st, 100, 50)
+	staller, sc := newTestPlayer(t, st, 100, 50)
+
+	battle, err := svc.CreatePvPBattle(winner.ID, staller.ID, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	battle.BattleType = "tournament"
+	battle.Status = "timeout"
+	battle.TurnNumber = 15
+
+	// What syncMatches does when the match deadline passes
+	if err := svc.wearTeams(st, battle, winner.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// 15 turns add 1 durability and 3 fatigue on top of the 2/6 durability and 5 fatigue
+	w, _ := st.Characters().Get(wc.ID)
+	s, _ := st.Characters().Get(sc.ID)
+	if w.Durability != 97 || w.Fatigue != 8 {
+		t.Fatalf("winner durability %d fatigue %d, want 97 and 8", w.Durability, w.Fatigue)
+	}
+	if s.Durability != 93 || s.Fatigue != 8 {
+		t.Fatalf("staller durability %d fatigue %d, want 93 and 8", s.Durability, s.Fatigue)
+	}
+}
+
+func TestFatigueRecoversAndCostsStats(t *testing.T) {
+	st := repository.NewMemoryStore()
+	svc, _ := newTestBattleService(st)
+	player, char := newTestPlayer(t, st, 100, 100)
+
+	fresh, err := svc.GetTeamSnapshot(player.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	setCondition(t, st, char, 100, 70, time.Now())
+	tired, err := svc.GetTeamSnapshot(player.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := fresh[0].Attack - fresh[0].Attack/10; tired[0].Attack != want || tired[0].MaxHP != fresh[0].MaxHP {
+		t.Fatalf("tired attack %d hp %d, want %d and unchanged hp %d", tired[0].Attack, tired[0].MaxHP, want, fresh[0].MaxHP)
+	}
+
+	// Ten hours of rest take off 50 fatigue, below any penalty
+	setCondition(t, st, char, 100, 70, time.Now().Add(-10*time.Hour))
+	rested, _ := svc.GetTeamSnapshot(player.ID)
+	if rested[0].Attack != fresh[0].Attack {
+		t.Fatalf("rested attack = %d, want %d", rested[0].Attack, fresh[0].Attack)
+	}
+
+	setCondition(t, st, char, 100, 100, time.Now())
+	if _, err := svc.GetTeamSnapshot(player.ID); err == nil {
+		t.Fatal("exhausted character fielded")
+	}
+	setCondition(t, st, char, 5, 0, time.Now())
+	if _, err := svc.CreatePvEBattle(player.ID, "PVE_STORY"); err == nil {
+		t.Fatal("worn-out character fielded")
+	}
+}
+
+func TestRepairBurnsGTK(t *testing.T) {
+	st := repository.NewMemoryStore()
+	ledger := NewLedgerService(st)
+	svc := NewConditionService(st, ledger, testSettings{})
+	owner := newTestUser(t, st, 0)
+	stranger := newTestUser(t, st, 0)
+	fund(t, ledger, owner.ID, 150)
+	char := newTestCharacter(t, st, owner.ID, 100, 50)
+	setCondition(t, st, char, 40, 0, time.Now())
+
+	cond, err := svc.Condition(owner.ID, char.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cond.RepairCost != 120 || !cond.Ready {
+		t.Fatalf("repair cost %d ready %v, want 120 and ready", cond.RepairCost, cond.Ready)
+	}
+	if _, err := svc.Repair(stranger.ID, char.ID); err == nil {
+		t.Fatal("a stranger repaired someone else's character")
+	}
+	repaired, err := svc.Repair(owner.ID, char.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repaired.Durability != 100 {
+		t.Fatalf("durability = %d, want 100", repaired.Durability)
+	}
+	if b := balance(t, ledger, &owner.ID, models.AccountTypeWallet); b != 30 {
+		t.Fatalf("wallet = %d, want 30", b)
+	}
+	if b := balance(t, ledger, nil, models.AccountTypeSink); b != 120 {
+		t.Fatalf("sink = %d, want the 120 burned", b)
+	}
+	if _, err := svc.Repair(owner.ID, char.ID); err == nil {
+		t.Fatal("repaired a character at full durability")
+	}
+}
+
+func TestRevivesGetDearerUntilDeathIsPermanent(t *testing.T) {
+	st := repository.NewMemoryStore()
+	ledger := NewLedgerService(st)
+	svc := NewConditionService(st, ledger, testSettings{})
+	owner := newTestUser(t, st, 0)
+	fund(t, ledger, owner.ID, 1400)
+	char := newTestCharacter(t, st, owner.ID, 100, 50)
+
+	kill := func() {
+		t.Helper()
+		got, _ := st.Characters().Get(char.ID)
+		setCondition(t, st, got, 1, 0, time.Now())
+		if err := applyBattleWear(st, testSettings{}, []uint{char.ID}, "pvp", false, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i, cost := range []int64{200, 400, 800} {
+		kill()
+		if got, _ := st.Characters().Get(char.ID); !got.IsDead || !got.CanBeRevived {
+			t.Fatalf("death %d: dead %v revivable %v, want a revivable death", i+1, got.IsDead, got.CanBeRevived)
+		}
+		before := balance(t, ledger, &owner.ID, models.AccountTypeWallet)
+		revived, err := svc.Revive(owner.ID, char.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if paid := before - balance(t, ledger, &owner.ID, models.AccountTypeWallet); paid != cost {
+			t.Fatalf("revive %d cost %d, want %d", i+1, paid, cost)
+		}
+		if revived.IsDead || revived.Durability != 50 || revived.ReviveCount != i+1 {
+			t.Fatalf("revive %d left %+v", i+1, revived)
+		}
+	}
+
+	kill()
+	got, _ := st.Characters().Get(char.ID)
+	if !got.IsDead || got.CanBeRevived {
+		t.Fatal("fourth death is not permanent")
+	}
+	if _, err := svc.Revive(owner.ID, char.ID); err == nil {
+		t.Fatal("revived a character with no revives left")
+	}
+	if err := CheckBattleReady(got, testSettings{}); err == nil {
+		t.Fatal("permanently dead character is battle-ready")
+	}
+
+	var deaths, revives int
+	for _, e := range provenanceEvents(t, st, char.ID) {
+		switch e.Event {
+		case models.ProvenanceDied:
+			deaths++
+		case models.ProvenanceRevived:
+			revives++
+		}
+	}
+	if deaths != 4 || revives != 3 {
+		t.Fatalf("provenance has %d deaths and %d revives, want 4 and 3", deaths, revives)
+	}
+}
@@ -9,6 +9,12 @@ import (
 	"github.com/lorengraff/crypto-tower-defense/internal/models"
 )
 
+// Settings reads typed tunables; ConfigService is the production implementation
+type Settings interface {
+	GetInt(key string, defaultVal int) int
+	GetFloat(key string, defaultVal float64) float64
+}
+
 // ConfigService manages dynamic system settings with caching
 type ConfigService struct {
 	cache      map[string]cachedSetting
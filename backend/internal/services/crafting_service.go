@@ -0,0 +1,372 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/internal/repository"
+	"github.com/lorengraff/crypto-tower-defense/pkg/logger"
+)
+
+// CraftTracker gates crafting behind its feature unlock and counts crafts toward missions (MissionService)
+type CraftTracker interface {
+	CheckFeatureUnlocked(userID uint, feature string) (bool, error)
+	OnCraft(userID uint) error
+}
+
+// salvageMultiplier scales a salvage yield (given for rarity C) by the salvaged item's rarity
+var salvageMultiplier = map[string]int{"C": 1, "B": 2, "A": 3, "S": 5, "SS": 8, "SSS": 12}
+
+// craftRarities is the order rarity weights are rolled in
+var craftRarities = []string{"C", "B", "A", "S", "SS", "SSS"}
+
+// CraftingService turns materials and GTK into items and salvages equipment back into materials
+type CraftingService struct {
+	store   repository.Store
+	ledger  *LedgerService
+	config  Settings
+	tracker CraftTracker
+}
+
+// NewCraftingService creates the crafting service
+func NewCraftingService(store repository.Store, ledger *LedgerService, config Settings, tracker CraftTracker) *CraftingService {
+	return &CraftingService{
+		store:   store,
+		ledger:  ledger,
+		config:  config,
+		tracker: tracker,
+	}
+}
+
+// Recipe is a crafting recipe with its JSON columns decoded
+type Recipe struct {
+	models.CraftingRecipe
+	RarityWeights map[string]int          `json:"rarity_weights"`
+	Materials     []models.MaterialAmount `json:"materials"`
+}
+
+func decodeRecipe(r models.CraftingRecipe) (*Recipe, error) {
+	out := &Recipe{CraftingRecipe: r}
+	if err := json.Unmarshal([]byte(r.RarityWeights), &out.RarityWeights); err != nil {
+		return nil, fmt.Errorf("recipe %d: rarity weights: %w", r.ID, err)
+	}
+	if err := json.Unmarshal([]byte(r.Materials), &out.Materials); err != nil {
+		return nil, fmt.Errorf("recipe %d: materials: %w", r.ID, err)
+	}
+	return out, nil
+}
+
+// ListRecipes returns the active recipes
+func (s *CraftingService) ListRecipes() ([]Recipe, error) {
+	recipes, err := s.store.Crafting().ListRecipes()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Recipe, 0, len(recipes))
+	for _, r := range recipes {
+		decoded, err := decodeRecipe(r)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *decoded)
+	}
+	return out, nil
+}
+
+// ListJobs returns a player's recent crafts, newest first
+func (s *CraftingService) ListJobs(userID uint) ([]models.CraftingJob, error) {
+	return s.store.Crafting().ListJobs(userID, 50)
+}
+
+// StartCraft takes a recipe's materials and GTK and queues the craft. Everything is taken
+// in one transaction, so a missing material or short wallet leaves the inventory untouched.
+func (s *CraftingService) StartCraft(userID, recipeID uint) (*models.CraftingJob, error) {
+	if err := s.checkUnlocked(userID); err != nil {
+		return nil, err
+	}
+
+	row, err := s.store.Crafting().Recipe(recipeID)
+	if err != nil {
+		return nil, err
+	}
+	if !row.IsActive {
+		return nil, errors.New("recipe is not available")
+	}
+	recipe, err := decodeRecipe(*row)
+	if err != nil {
+		return nil, err
+	}
+
+	job := models.CraftingJob{
+		UserID:      userID,
+		RecipeID:    recipe.ID,
+		Status:      models.CraftingStatusCrafting,
+		GTKCost:     recipe.GTKCost,
+		CompletesAt: time.Now().Add(time.Duration(recipe.CraftMinutes) * time.Minute),
+	}
+
+	err = s.store.Transaction(func(tx repository.Store) error {
+		active, err := tx.Crafting().CountActiveJobs(userID)
+		if err != nil {
+			return err
+		}
+		if slots := s.config.GetInt("crafting_queue_slots", 3); active >= int64(slots) {
+			return fmt.Errorf("crafting queue is full (%d slots)", slots)
+		}
+
+		for _, m := range recipe.Materials {
+			if err := consumeMaterial(tx, userID, m); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Crafting().CreateJob(&job); err != nil {
+			return err
+		}
+
+		if recipe.GTKCost > 0 {
+			ledger := s.ledger.WithStore(tx)
+			userAcc, err := ledger.GetOrCreateAccount(&userID, models.AccountTypeWallet, "GTK")
+			if err != nil {
+				return err
+			}
+			sinkAcc, err := ledger.GetOrCreateAccount(nil, models.AccountTypeSink, "GTK")
+			if err != nil {
+				return err
+			}
+			entries := []models.LedgerEntry{
+				{AccountID: userAcc.ID, Amount: -recipe.GTKCost, Type: "DEBIT"},
+				{AccountID: sinkAcc.ID, Amount: recipe.GTKCost, Type: "CREDIT"},
+			}
+			if err := ledger.CreateTransaction(models.TxTypeCraftFee, "craft_"+strconv.Itoa(int(job.ID)), "Craft "+recipe.Name, entries); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	job.Recipe = recipe.CraftingRecipe
+	return &job, nil
+}
+
+// ClaimCraft finishes a craft whose timer has run out: it rolls success and rarity and, on
+// success, creates the item. Claiming fires the craft mission hook either way.
+func (s *CraftingService) ClaimCraft(ctx context.Context, userID, jobID uint) (*models.CraftingJob, *models.Item, error) {
+	var job *models.CraftingJob
+	var item *models.Item
+
+	err := s.store.WithContext(ctx).Transaction(func(tx repository.Store) error {
+		var err error
+		job, err = tx.Crafting().LockJob(jobID)
+		if err != nil {
+			return err
+		}
+		if job.UserID != userID {
+			return errors.New("not your crafting job")
+		}
+		if job.Status != models.CraftingStatusCrafting {
+			return errors.New("craft already claimed")
+		}
+		if time.Now().Before(job.CompletesAt) {
+			return fmt.Errorf("craft is not ready until %s", job.CompletesAt.Format(time.RFC3339))
+		}
+
+		row, err := tx.Crafting().Recipe(job.RecipeID)
+		if err != nil {
+			return err
+		}
+		recipe, err := decodeRecipe(*row)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		job.ClaimedAt = &now
+		job.Status = models.CraftingStatusFailed
+		if rand.Float64() < recipe.SuccessChance {
+			job.Status = models.CraftingStatusSucceeded
+			job.ResultRarity = rollRarity(recipe.RarityWeights)
+			item = craftedItem(recipe, userID, job.ResultRarity)
+			if err := tx.Items().Create(item); err != nil {
+				return err
+			}
+			job.ResultItemID = &item.ID
+		}
+		job.Recipe = recipe.CraftingRecipe
+		return tx.Crafting().SaveJob(job)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.tracker.OnCraft(userID); err != nil {
+		logger.FromContext(ctx).Warn("craft mission progress not recorded", "user_id", userID, "job_id", jobID, "error", err)
+	}
+	return job, item, nil
+}
+
+// Salvage breaks an unequipped, unlisted piece of equipment into materials. The yield for
+// its type is scaled by its rarity and stacked onto the materials the player already has.
+func (s *CraftingService) Salvage(userID, itemID uint) ([]models.MaterialAmount, error) {
+	var yield []models.MaterialAmount
+
+	err := s.store.Transaction(func(tx repository.Store) error {
+		item, err := tx.Items().Lock(itemID)
+		if err != nil {
+			return err
+		}
+		if item.OwnerID != userID {
+			return errors.New("you don't own this item")
+		}
+		if item.IsEquipped || item.IsListed {
+			return errors.New("unequip or delist the item before salvaging it")
+		}
+
+		row, err := tx.Crafting().SalvageYield(item.ItemType)
+		if errors.Is(err, repository.ErrNotFound) {
+			return fmt.Errorf("%s items cannot be salvaged", item.ItemType)
+		}
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal([]byte(row.Materials), &yield); err != nil {
+			return fmt.Errorf("salvage yield %s: %w", row.ItemType, err)
+		}
+
+		if err := unsocketAll(tx, item.ID); err != nil {
+			return err
+		}
+
+		multiplier := max(salvageMultiplier[item.Rarity], 1)
+		for i := range yield {
+			yield[i].Quantity *= multiplier
+			if err := addMaterial(tx, userID, yield[i]); err != nil {
+				return err
+			}
+		}
+		return tx.Items().Delete(item.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return yield, nil
+}
+
+func (s *CraftingService) checkUnlocked(userID uint) error {
+	unlocked, err := s.tracker.CheckFeatureUnlocked(userID, "crafting")
+	if err != nil {
+		return err
+	}
+	if !unlocked {
+		return errors.New("crafting unlocks at level 10")
+	}
+	return nil
+}
+
+// consumeMaterial takes m.Quantity of a material from the player's stacks, oldest first,
+// deleting the stacks it empties
+func consumeMaterial(tx repository.Store, userID uint, m models.MaterialAmount) error {
+	stacks, err := tx.Items().LockMaterials(userID, m.Name)
+	if err != nil {
+		return err
+	}
+	have := 0
+	for _, st := range stacks {
+		have += st.Quantity
+	}
+	if have < m.Quantity {
+		return fmt.Errorf("not enough %s: have %d, need %d", m.Name, have, m.Quantity)
+	}
+
+	need := m.Quantity
+	for i := range stacks {
+		if need == 0 {
+			break
+		}
+		st := &stacks[i]
+		take := min(st.Quantity, need)
+		need -= take
+		st.Quantity -= take
+		if st.Quantity == 0 {
+			err = tx.Items().Delete(st.ID)
+		} else {
+			err = tx.Items().Save(st)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addMaterial stacks m onto the player's first stack of that material, or starts one
+func addMaterial(tx repository.Store, userID uint, m models.MaterialAmount) error {
+	stacks, err := tx.Items().LockMaterials(userID, m.Name)
+	if err != nil {
+		return err
+	}
+	if len(stacks) > 0 {
+		stacks[0].Quantity += m.Quantity
+		return tx.Items().Save(&stacks[0])
+	}
+	return tx.Items().Create(&models.Item{
+		OwnerID:            userID,
+		ItemType:           "MATERIAL",
+		Name:               m.Name,
+		Rarity:             "C",
+		Durability:         100,
+		IsCraftingMaterial: true,
+		IsStackable:        true,
+		Quantity:           m.Quantity,
+	})
+}
+
+// rollRarity picks a rarity with probability proportional to its weight
+func rollRarity(weights map[string]int) string {
+	total := 0
+	for _, r := range craftRarities {
+		total += max(weights[r], 0)
+	}
+	if total == 0 {
+		return "C"
+	}
+	n := rand.Intn(total)
+	for _, r := range craftRarities {
+		n -= max(weights[r], 0)
+		if n < 0 {
+			return r
+		}
+	}
+	return "C"
+}
+
+// craftedItem builds the item a successful craft produces
+func craftedItem(recipe *Recipe, ownerID uint, rarity string) *models.Item {
+	bonuses := calculateItemBonuses(recipe.ItemType, rarity)
+	item := &models.Item{
+		OwnerID:      ownerID,
+		ItemType:     recipe.ItemType,
+		Name:         recipe.ItemName,
+		Rarity:       rarity,
+		AttackBonus:  bonuses.Attack,
+		DefenseBonus: bonuses.Defense,
+		HPBonus:      bonuses.HP,
+		SpeedBonus:   bonuses.Speed,
+		Durability:   100,
+		Quantity:     1,
+	}
+	if recipe.ItemType == "CONSUMABLE" {
+		item.IsConsumable = true
+		item.ConsumeEffect = recipe.ConsumeEffect
+	}
+	return item
+}
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/internal/repository"
+)
+
+// testTracker is a CraftTracker stub that counts crafts
+type testTracker struct {
+	locked bool
+	crafts int
+}
+
+func (t *testTracker) CheckFeatureUnlocked(uint, string) (bool, error) { return !t.locked, nil }
+func (t *testTracker) OnCraft(uint) error                              { t.crafts++; return nil }
+
+func newTestCrafting(st *repository.MemoryStore) (*CraftingService, *LedgerService, *testTracker) {
+	ledger := NewLedgerService(st)
+	tracker := &testTracker{}
+	return NewCraftingService(st, ledger, testSettings{}, tracker), ledger, tracker
+}
+
+func putBladeRecipe(st *repository.MemoryStore) *models.CraftingRecipe {
+	return st.PutRecipe(models.CraftingRecipe{
+		Name:          "Forged Blade",
+		ItemType:      "WEAPON",
+		ItemName:      "Forged Blade",
+		GTKCost:       100,
+		SuccessChance: 1,
+		CraftMinutes:  30,
+		RarityWeights: `{"B": 1}`,
+		Materials:     `[{"name": "Iron Scrap", "quantity": 5}]`,
+		IsActive:      true,
+	})
+}
+
+func giveMaterial(t *testing.T, st repository.Store, ownerID uint, name string, quantity int) *models.Item {
+	t.Helper()
+	item := &models.Item{OwnerID: ownerID, ItemType: "MATERIAL", Name: name, Rarity: "C", IsCraftingMaterial: true, IsStackable: true, Quantity: quantity}
+	if err := st.Items().Create(item); err != nil {
+		t.Fatal(err)
+	}
+	return item
+}
+
+func materialCount(t *testing.T, st repository.Store, ownerID uint, name string) int {
+	t.Helper()
+	var total int
+	err := st.Transaction(func(tx repository.Store) error {
+		stacks, err := tx.Items().LockMaterials(ownerID, name)
+		for _, s := range stacks {
+			total += s.Quantity
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return total
+}
+
+func TestCraftConsumesMaterialsAndGTKThenClaimsItem(t *testing.T) {
+	st := repository.NewMemoryStore()
+	crafting, ledger, tracker := newTestCrafting(st)
+	recipe := putBladeRecipe(st)
+	user := newTestUser(t, st, 1000)
+	fund(t, ledger, user.ID, 250)
+	first := giveMaterial(t, st, user.ID, "Iron Scrap", 3)
+	giveMaterial(t, st, user.ID, "Iron Scrap", 4)
+
+	job, err := crafting.StartCraft(user.ID, recipe.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := materialCount(t, st, user.ID, "Iron Scrap"); got != 2 {
+		t.Fatalf("Iron Scrap left = %d, want 2", got)
+	}
+	if _, err := st.Items().Get(first.ID); err == nil {
+		t.Fatal("emptied stack was not deleted")
+	}
+	if got := balance(t, ledger, &user.ID, models.AccountTypeWallet); got != 150 {
+		t.Fatalf("wallet = %d, want 150", got)
+	}
+	if got := balance(t, ledger, nil, models.AccountTypeSink); got != 100 {
+		t.Fatalf("sink = %d, want 100", got)
+	}
+
+	if _, _, err := crafting.ClaimCraft(context.Background(), user.ID, job.ID); err == nil {
+		t.Fatal("claimed a craft before its timer ran out")
+	}
+	if tracker.crafts != 0 {
+		t.Fatalf("OnCraft fired %d times before a claim", tracker.crafts)
+	}
+
+	saved, _ := st.Crafting().LockJob(job.ID)
+	saved.CompletesAt = time.Now().Add(-time.Minute)
+	if err := st.Crafting().SaveJob(saved); err != nil {
+		t.Fatal(err)
+	}
+	claimed, item, err := crafting.ClaimCraft(context.Background(), user.ID, job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claimed.Status != models.CraftingStatusSucceeded || item == nil || item.Rarity != "B" || item.OwnerID != user.ID {
+		t.Fatalf("claim = %+v, item %+v", claimed, item)
+	}
+	if item.AttackBonus == 0 {
+		t.Fatalf("crafted weapon has no bonuses: %+v", item)
+	}
+	if tracker.crafts != 1 {
+		t.Fatalf("OnCraft fired %d times, want 1", tracker.crafts)
+	}
+	if _, _, err := crafting.ClaimCraft(context.Background(), user.ID, job.ID); err == nil {
+		t.Fatal("claimed the same craft twice")
+	}
+}
+
+func TestCraftRollsBackWhenShort(t *testing.T) {
+	st := repository.NewMemoryStore()
+	crafting, ledger, tracker := newTestCrafting(st)
+	recipe := putBladeRecipe(st)
+	user := newTestUser(t, st, 1000)
+
+	// Enough scrap but no GTK: the materials must come back
+	giveMaterial(t, st, user.ID, "Iron Scrap", 5)
+	if _, err := crafting.StartCraft(user.ID, recipe.ID); err == nil {
+		t.Fatal("crafted without GTK")
+	}
+	if got := materialCount(t, st, user.ID, "Iron Scrap"); got != 5 {
+		t.Fatalf("Iron Scrap = %d after a failed craft, want 5", got)
+	}
+
+	// Enough GTK but too little scrap: the wallet must be untouched
+	fund(t, ledger, user.ID, 100)
+	other := newTestUser(t, st, 1000)
+	giveMaterial(t, st, other.ID, "Iron Scrap", 10)
+	stack, _ := st.Items().LockMaterials(user.ID, "Iron Scrap")
+	stack[0].Quantity = 4
+	if err := st.Items().Save(&stack[0]); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := crafting.StartCraft(user.ID, recipe.ID); err == nil {
+		t.Fatal("crafted with 4 of 5 Iron Scrap")
+	}
+	if got := balance(t, ledger, &user.ID, models.AccountTypeWallet); got != 100 {
+		t.Fatalf("wallet = %d after a failed craft, want 100", got)
+	}
+
+	tracker.locked = true
+	giveMaterial(t, st, user.ID, "Iron Scrap", 1)
+	if _, err := crafting.StartCraft(user.ID, recipe.ID); err == nil {
+		t.Fatal("crafted before the crafting unlock")
+	}
+}
+
+func TestCraftQueueIsLimited(t *testing.T) {
+	st := repository.NewMemoryStore()
+	ledger := NewLedgerService(st)
+	crafting := NewCraftingService(st, ledger, testSettings{"crafting_queue_slots": 1}, &testTracker{})
+	recipe := putBladeRecipe(st)
+	user := newTestUser(t, st, 1000)
+	fund(t, ledger, user.ID, 500)
+	giveMaterial(t, st, user.ID, "Iron Scrap", 10)
+
+	if _, err := crafting.StartCraft(user.ID, recipe.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := crafting.StartCraft(user.ID, recipe.ID); err == nil {
+		t.Fatal("started a second craft with one queue slot")
+	}
+}
+
+func TestSalvageScalesByRarityAndStacks(t *testing.T) {
+	st := repository.NewMemoryStore()
+	crafting, _, _ := newTestCrafting(st)
+	st.PutSalvageYield(models.SalvageYield{
+		ItemType:  "WEAPON",
+		Materials: `[{"name": "Iron Scrap", "quantity": 3}, {"name": "Essence Shard", "quantity": 1}]`,
+	})
+	user := newTestUser(t, st, 1000)
+	scrap := giveMaterial(t, st, user.ID, "Iron Scrap", 2)
+
+	sword := &models.Item{OwnerID: user.ID, ItemType: "WEAPON", Name: "Sword", Rarity: "A", Quantity: 1}
+	equipped := &models.Item{OwnerID: user.ID, ItemType: "WEAPON", Name: "Axe", Rarity: "C", Quantity: 1, IsEquipped: true}
+	for _, it := range []*models.Item{sword, equipped} {
+		if err := st.Items().Create(it); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	yield, err := crafting.Salvage(user.ID, sword.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(yield) != 2 || yield[0].Quantity != 9 || yield[1].Quantity != 3 {
+		t.Fatalf("yield = %+v, want 9 Iron Scrap and 3 Essence Shard", yield)
+	}
+	if _, err := st.Items().Get(sword.ID); err == nil {
+		t.Fatal("salvaged item still exists")
+	}
+	if got, _ := st.Items().Get(scrap.ID); got.Quantity != 11 {
+		t.Fatalf("Iron Scrap stack = %d, want 11", got.Quantity)
+	}
+	if got := materialCount(t, st, user.ID, "Essence Shard"); got != 3 {
+		t.Fatalf("Essence Shard = %d, want 3", got)
+	}
+
+	if _, err := crafting.Salvage(user.ID, equipped.ID); err == nil {
+		t.Fatal("salvaged an equipped item")
+	}
+	other := newTestUser(t, st, 1000)
+	if _, err := crafting.Salvage(other.ID, equipped.ID); err == nil {
+		t.Fatal("salvaged someone else's item")
+	}
+}
@@ -17,9 +17,9 @@ type DailyQuestService struct {
 }
 
 // NewDailyQuestService creates a new daily quest service
-func NewDailyQuestService() *DailyQuestService {
+func NewDailyQuestService(ledger *LedgerService) *DailyQuestService {
 	return &DailyQuestService{
-		ledger: NewLedgerService(),
+		ledger: ledger,
 	}
 }
 
@@ -0,0 +1,337 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/internal/repository"
+)
+
+// EggFriends tells whether two players are friends (FriendService)
+type EggFriends interface {
+	AreFriends(a, b uint) bool
+}
+
+// careWindow is the stretch of incubation, as fractions of it, in which a care action
+// can be done, and the predetermined stats it affects
+type careWindow struct {
+	Action   string
+	From, To float64
+	Stats    []string
+}
+
+var careWindows = []careWindow{
+	{Action: models.EggCareCalibrate, From: 0, To: 1.0 / 3, Stats: []string{"atk", "spd"}},
+	{Action: models.EggCareNurture, From: 1.0 / 3, To: 2.0 / 3, Stats: []string{"hp"}},
+	{Action: models.EggCareStabilize, From: 2.0 / 3, To: 1, Stats: []string{"def"}},
+}
+
+// CareWindowStatus is one care window of an egg
+type CareWindowStatus struct {
+	Action   string          `json:"action"`
+	Stats    []string        `json:"stats"`
+	OpensAt  time.Time       `json:"opens_at"`
+	ClosesAt time.Time       `json:"closes_at"`
+	Open     bool            `json:"open"`
+	Care     *models.EggCare `json:"care,omitempty"` // Set once the window is done or missed
+}
+
+// EggCareService runs the care windows of incubating eggs. Each window takes one action,
+// paid in GTK or with an egg care consumable, by the owner or one of their friends. Care
+// raises the egg's predetermined stats; a window that closes unattended lowers them.
+type EggCareService struct {
+	store    repository.Store
+	ledger   *LedgerService
+	config   Settings
+	friends  EggFriends
+	notifier Notifier
+}
+
+// NewEggCareService creates the egg care service
+func NewEggCareService(store repository.Store, ledger *LedgerService, config Settings, friends EggFriends, notifier Notifier) *EggCareService {
+	return &EggCareService{
+		store:    store,
+		ledger:   ledger,
+		config:   config,
+		friends:  friends,
+		notifier: notifier,
+	}
+}
+
+// GetCare returns the care windows of an egg the player owns or a friend owns
+func (s *EggCareService) GetCare(userID, eggID uint) ([]CareWindowStatus, error) {
+	var out []CareWindowStatus
+	err := s.store.Transaction(func(tx repository.Store) error {
+		egg, err := s.lockCareable(tx, userID, eggID)
+		if err != nil {
+			return err
+		}
+		care, err := settleEggCare(tx, egg, time.Now(), s.config)
+		if err != nil {
+			return err
+		}
+		out = careStatus(egg, care, time.Now())
+		return nil
+	})
+	return out, err
+}
+
+// PerformCare does a care action on an egg while its window is open. A non-zero
+// shopItemID pays with that egg care consumable from the caller's inventory, otherwise
+// the caller pays egg_care_gtk_cost GTK.
+func (s *EggCareService) PerformCare(userID, eggID uint, action string, shopItemID uint) (*models.EggCare, error) {
+	var care *models.EggCare
+	var ownerID uint
+
+	err := s.store.Transaction(func(tx repository.Store) error {
+		egg, err := s.lockCareable(tx, userID, eggID)
+		if err != nil {
+			return err
+		}
+		ownerID = egg.UserID
+
+		now := time.Now()
+		settled, err := settleEggCare(tx, egg, now, s.config)
+		if err != nil {
+			return err
+		}
+
+		w, ok := findCareWindow(action)
+		if !ok {
+			return fmt.Errorf("unknown care action %q", action)
+		}
+		for _, c := range settled {
+			if c.Action == w.Action {
+				if c.Status == models.EggCareMissed {
+					return fmt.Errorf("the %s window has closed", w.Action)
+				}
+				return fmt.Errorf("%s was already done", w.Action)
+			}
+		}
+		opens, _ := careWindowTimes(egg, w)
+		if now.Before(opens) {
+			return fmt.Errorf("%s opens at %s", w.Action, opens.Format(time.RFC3339))
+		}
+
+		care = &models.EggCare{EggID: egg.ID, Action: w.Action, Status: models.EggCareDone, PerformedByID: &userID}
+		if shopItemID != 0 {
+			care.PaidWith, err = consumeCareItem(tx, userID, shopItemID)
+		} else {
+			care.PaidWith = "GTK"
+			err = s.chargeCare(tx, userID, egg.ID, w.Action)
+		}
+		if err != nil {
+			return err
+		}
+
+		return applyCare(tx, egg, care, w, s.config.GetFloat("egg_care_bonus", 0.05))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if userID != ownerID {
+		msg := fmt.Sprintf("A friend did the %s care for your egg", care.Action)
+		if err := s.notifier.CreateNotification(ownerID, "EGG_CARE", "Your egg was cared for", msg, care); err != nil {
+			slog.Warn("failed to send egg care notification", "user_id", ownerID, "egg_id", eggID, "error", err)
+		}
+	}
+	return care, nil
+}
+
+// lockCareable locks an incubating egg that userID may care for
+func (s *EggCareService) lockCareable(tx repository.Store, userID, eggID uint) (*models.Egg, error) {
+	egg, err := tx.Eggs().Lock(eggID)
+	if err != nil {
+		return nil, errors.New("egg not found")
+	}
+	if egg.UserID != userID && !s.friends.AreFriends(userID, egg.UserID) {
+		return nil, errors.New("only the owner and their friends can care for this egg")
+	}
+	if egg.HatchedAt != nil {
+		return nil, errors.New("egg already hatched")
+	}
+	if egg.IncubationStartedAt == nil {
+		return nil, errors.New("egg is not incubating")
+	}
+	if egg.PredeterminedStats == "" {
+		return nil, errors.New("this egg has no care windows")
+	}
+	return egg, nil
+}
+
+func (s *EggCareService) chargeCare(tx repository.Store, userID, eggID uint, action string) error {
+	cost := int64(s.config.GetInt("egg_care_gtk_cost", 150))
+	if cost <= 0 {
+		return nil
+	}
+	ledger := s.ledger.WithStore(tx)
+	userAcc, err := ledger.GetOrCreateAccount(&userID, models.AccountTypeWallet, "GTK")
+	if err != nil {
+		return err
+	}
+	sinkAcc, err := ledger.GetOrCreateAccount(nil, models.AccountTypeSink, "GTK")
+	if err != nil {
+		return err
+	}
+	entries := []models.LedgerEntry{
+		{AccountID: userAcc.ID, Amount: -cost, Type: "DEBIT"},
+		{AccountID: sinkAcc.ID, Amount: cost, Type: "CREDIT"},
+	}
+	refID := "egg_care_" + strconv.Itoa(int(eggID)) + "_" + action
+	return ledger.CreateTransaction(models.TxTypeEggCare, refID, "Egg care: "+action, entries)
+}
+
+// consumeCareItem takes one egg care consumable from the player's inventory and returns its name
+func consumeCareItem(tx repository.Store, userID, shopItemID uint) (string, error) {
+	item, err := tx.Inventory().ShopItem(shopItemID)
+	if err != nil || item.Category != "egg" || item.EffectType != "care" {
+		return "", errors.New("item is not an egg care consumable")
+	}
+	inv, err := tx.Inventory().Find(userID, shopItemID)
+	if err != nil || inv.Quantity <= 0 {
+		return "", fmt.Errorf("you don't have a %s", item.Name)
+	}
+	inv.Quantity--
+	if inv.Quantity == 0 {
+		err = tx.Inventory().Delete(inv)
+	} else {
+		err = tx.Inventory().Save(inv)
+	}
+	return item.Name, err
+}
+
+// settleEggCare records every window of egg that closed without care as missed, applying
+// the miss penalty, and returns all settled windows. egg must be locked. Eggs that are
+// not incubating or have no predetermined stats (bred eggs) have no windows.
+func settleEggCare(tx repository.Store, egg *models.Egg, now time.Time, config Settings) ([]models.EggCare, error) {
+	if egg.IncubationStartedAt == nil || egg.PredeterminedStats == "" {
+		return nil, nil
+	}
+	care, err := tx.Eggs().Care(egg.ID)
+	if err != nil {
+		return nil, err
+	}
+	done := map[string]bool{}
+	for _, c := range care {
+		done[c.Action] = true
+	}
+	for _, w := range careWindows {
+		if done[w.Action] {
+			continue
+		}
+		if _, closes := careWindowTimes(egg, w); now.Before(closes) {
+			continue
+		}
+		missed := models.EggCare{EggID: egg.ID, Action: w.Action, Status: models.EggCareMissed}
+		if err := applyCare(tx, egg, &missed, w, -config.GetFloat("egg_care_miss_penalty", 0.03)); err != nil {
+			return nil, err
+		}
+		care = append(care, missed)
+	}
+	return care, nil
+}
+
+// settleForHatch settles the care windows of an egg about to hatch and returns it as
+// settled, with its care
+func settleForHatch(store repository.Store, eggID uint, config Settings) (*models.Egg, []models.EggCare, error) {
+	var egg *models.Egg
+	var care []models.EggCare
+	err := store.Transaction(func(tx repository.Store) error {
+		var err error
+		if egg, err = tx.Eggs().Lock(eggID); err != nil {
+			return err
+		}
+		care, err = settleEggCare(tx, egg, time.Now(), config)
+		return err
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to settle egg care: %w", err)
+	}
+	return egg, care, nil
+}
+
+// applyCareSlots records on a hatched character which care its egg was given. The care
+// bonuses are already in the egg's stats.
+func applyCareSlots(c *models.Character, care []models.EggCare) {
+	for _, cr := range care {
+		done := cr.Status == models.EggCareDone
+		switch cr.Action {
+		case models.EggCareCalibrate:
+			c.CareSlotCalibrate = done
+		case models.EggCareNurture:
+			c.CareSlotNurture = done
+		case models.EggCareStabilize:
+			c.CareSlotStabilize = done
+		}
+	}
+}
+
+// applyCare changes the window's stats in egg.PredeterminedStats by pct and records c
+func applyCare(tx repository.Store, egg *models.Egg, c *models.EggCare, w careWindow, pct float64) error {
+	var stats map[string]int
+	if err := json.Unmarshal([]byte(egg.PredeterminedStats), &stats); err != nil {
+		return fmt.Errorf("egg %d: predetermined stats: %w", egg.ID, err)
+	}
+	// The change is stored as a percentage so the record doesn't reveal hidden stats
+	change := map[string]float64{}
+	for _, stat := range w.Stats {
+		v, ok := stats[stat]
+		if !ok {
+			continue
+		}
+		stats[stat] = max(int(math.Round(float64(v)*(1+pct))), 1)
+		change[stat] = math.Round(pct*1000) / 10
+	}
+	raw, _ := json.Marshal(stats)
+	egg.PredeterminedStats = string(raw)
+	if err := tx.Eggs().Save(egg); err != nil {
+		return err
+	}
+	raw, _ = json.Marshal(change)
+	c.StatChange = string(raw)
+	return tx.Eggs().AddCare(c)
+}
+
+// careWindowTimes is when w opens and closes for egg. Accelerators shorten the
+// incubation, and the windows with it.
+func careWindowTimes(egg *models.Egg, w careWindow) (time.Time, time.Time) {
+	hours := egg.EffectiveIncubationTime
+	if hours <= 0 {
+		hours = egg.IncubationTime
+	}
+	total := time.Duration(hours) * time.Hour
+	start := *egg.IncubationStartedAt
+	return start.Add(time.Duration(float64(total) * w.From)), start.Add(time.Duration(float64(total) * w.To))
+}
+
+func findCareWindow(action string) (careWindow, bool) {
+	for _, w := range careWindows {
+		if w.Action == action {
+			return w, true
+		}
+	}
+	return careWindow{}, false
+}
+
+func careStatus(egg *models.Egg, care []models.EggCare, now time.Time) []CareWindowStatus {
+	out := make([]CareWindowStatus, 0, len(careWindows))
+	for _, w := range careWindows {
+		opens, closes := careWindowTimes(egg, w)
+		st := CareWindowStatus{Action: w.Action, Stats: w.Stats, OpensAt: opens, ClosesAt: closes}
+		for i := range care {
+			if care[i].Action == w.Action {
+				st.Care = &care[i]
+			}
+		}
+		st.Open = st.Care == nil && !now.Before(opens) && now.Before(closes)
+		out = append(out, st)
+	}
+	return out
+}
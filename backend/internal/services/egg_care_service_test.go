@@ -0,0 +1,143 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/internal/repository"
+)
+
+// testFriends is an EggFriends stub; a pair is friends in either order
+type testFriends map[[2]uint]bool
+
+func (f testFriends) AreFriends(a, b uint) bool { return f[[2]uint{a, b}] || f[[2]uint{b, a}] }
+
+// newCaringEgg creates a 3 hour egg that started incubating `since` ago
+func newCaringEgg(t *testing.T, st repository.Store, ownerID uint, since time.Duration) *models.Egg {
+	t.Helper()
+	started := time.Now().Add(-since)
+	egg := &models.Egg{
+		UserID:                  ownerID,
+		IncubationTime:          3,
+		EffectiveIncubationTime: 3,
+		IncubationStartedAt:     &started,
+		PredeterminedStats:      `{"hp": 100, "atk": 50, "def": 40, "spd": 20}`,
+	}
+	if err := st.Eggs().Create(egg); err != nil {
+		t.Fatal(err)
+	}
+	return egg
+}
+
+func eggStats(t *testing.T, st repository.Store, eggID uint) map[string]int {
+	t.Helper()
+	egg, err := st.Eggs().Get(eggID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stats map[string]int
+	if err := json.Unmarshal([]byte(egg.PredeterminedStats), &stats); err != nil {
+		t.Fatal(err)
+	}
+	return stats
+}
+
+func TestPerformCareRaisesStatsAndCharges(t *testing.T) {
+	st := repository.NewMemoryStore()
+	ledger := NewLedgerService(st)
+	owner := newTestUser(t, st, 1000)
+	fund(t, ledger, owner.ID, 500)
+	egg := newCaringEgg(t, st, owner.ID, 30*time.Minute)
+	svc := NewEggCareService(st, ledger, testSettings{}, testFriends{}, &testNotifier{})
+
+	if _, err := svc.PerformCare(owner.ID, egg.ID, models.EggCareNurture, 0); err == nil {
+		t.Fatal("nurture done before its window opened")
+	}
+	care, err := svc.PerformCare(owner.ID, egg.ID, models.EggCareCalibrate, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if care.PaidWith != "GTK" {
+		t.Fatalf("paid with %q, want GTK", care.PaidWith)
+	}
+	if got := balance(t, ledger, &owner.ID, models.AccountTypeWallet); got != 350 {
+		t.Fatalf("wallet = %d, want 350", got)
+	}
+	stats := eggStats(t, st, egg.ID)
+	if stats["atk"] != 53 || stats["spd"] != 21 || stats["hp"] != 100 {
+		t.Fatalf("stats = %v, want atk 53, spd 21, hp untouched", stats)
+	}
+	if _, err := svc.PerformCare(owner.ID, egg.ID, models.EggCareCalibrate, 0); err == nil {
+		t.Fatal("calibrate done twice")
+	}
+}
+
+func TestFriendCareWithKitNotifiesOwner(t *testing.T) {
+	st := repository.NewMemoryStore()
+	ledger := NewLedgerService(st)
+	owner := newTestUser(t, st, 1000)
+	friend := newTestUser(t, st, 1000)
+	stranger := newTestUser(t, st, 1000)
+	egg := newCaringEgg(t, st, owner.ID, 90*time.Minute)
+	kit := st.PutShopItem(models.ShopItem{Name: "Care Kit", Category: "egg", EffectType: "care"})
+	if err := st.Inventory().Save(&models.UserInventory{UserID: friend.ID, ItemID: kit.ID, Quantity: 1}); err != nil {
+		t.Fatal(err)
+	}
+	notifier := &testNotifier{}
+	svc := NewEggCareService(st, ledger, testSettings{}, testFriends{{owner.ID, friend.ID}: true}, notifier)
+
+	if _, err := svc.PerformCare(stranger.ID, egg.ID, models.EggCareNurture, 0); err == nil {
+		t.Fatal("a stranger cared for the egg")
+	}
+	care, err := svc.PerformCare(friend.ID, egg.ID, models.EggCareNurture, kit.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if care.PaidWith != "Care Kit" || *care.PerformedByID != friend.ID {
+		t.Fatalf("care = %+v, want paid with the kit by the friend", care)
+	}
+	if _, err := st.Inventory().Find(friend.ID, kit.ID); err == nil {
+		t.Fatal("the kit was not consumed")
+	}
+	if len(notifier.sent) != 1 || notifier.sent[0] != "EGG_CARE" {
+		t.Fatalf("notifications = %v, want one EGG_CARE", notifier.sent)
+	}
+	if stats := eggStats(t, st, egg.ID); stats["hp"] != 105 {
+		t.Fatalf("hp = %d, want 105", stats["hp"])
+	}
+}
+
+func TestMissedCareWindowIsPenalized(t *testing.T) {
+	st := repository.NewMemoryStore()
+	owner := newTestUser(t, st, 1000)
+	egg := newCaringEgg(t, st, owner.ID, 90*time.Minute)
+	svc := NewEggCareService(st, NewLedgerService(st), testSettings{}, testFriends{}, &testNotifier{})
+
+	windows, err := svc.GetCare(owner.ID, egg.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if windows[0].Care == nil || windows[0].Care.Status != models.EggCareMissed {
+		t.Fatalf("calibrate = %+v, want missed", windows[0])
+	}
+	if !windows[1].Open || windows[2].Open {
+		t.Fatalf("open = %v/%v, want only nurture open", windows[1].Open, windows[2].Open)
+	}
+	stats := eggStats(t, st, egg.ID)
+	if stats["atk"] != 49 || stats["spd"] != 19 {
+		t.Fatalf("stats = %v, want atk 49, spd 19", stats)
+	}
+
+	// Settling again must not penalize twice
+	if _, err := svc.GetCare(owner.ID, egg.ID); err != nil {
+		t.Fatal(err)
+	}
+	if again := eggStats(t, st, egg.ID); again["atk"] != 49 {
+		t.Fatalf("atk = %d after a second look, want 49", again["atk"])
+	}
+	if _, err := svc.PerformCare(owner.ID, egg.ID, models.EggCareCalibrate, 0); err == nil {
+		t.Fatal("missed window was cared for")
+	}
+}
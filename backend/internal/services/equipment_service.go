@@ -0,0 +1,493 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/internal/repository"
+)
+
+// enhanceBonusPercent is how much each +1 adds to a piece's own bonuses
+const enhanceBonusPercent = 10
+
+// gearSlots maps equippable item types to their character slot
+var gearSlots = map[string]string{
+	"WEAPON":    models.SlotWeapon,
+	"ARMOR":     models.SlotArmor,
+	"ACCESSORY": models.SlotAccessory,
+}
+
+// gearProfile is the required level, enhancement cap and rune sockets of new equipment, by rarity
+var gearProfile = map[string]struct{ Level, MaxUpgrade, Sockets int }{
+	"C":   {Level: 1, MaxUpgrade: 5, Sockets: 1},
+	"B":   {Level: 5, MaxUpgrade: 6, Sockets: 1},
+	"A":   {Level: 10, MaxUpgrade: 7, Sockets: 2},
+	"S":   {Level: 20, MaxUpgrade: 8, Sockets: 2},
+	"SS":  {Level: 30, MaxUpgrade: 9, Sockets: 3},
+	"SSS": {Level: 40, MaxUpgrade: 10, Sockets: 3},
+}
+
+// PieceBonus is the bonus of one piece of equipment: its own bonuses scaled by its
+// enhancement level plus its socketed runes. Broken gear gives nothing.
+func PieceBonus(e models.Equipment) Stats {
+	if e.Item.IsBroken {
+		return Stats{}
+	}
+	pct := 100 + e.UpgradeLevel*enhanceBonusPercent
+	b := Stats{
+		Attack:  (e.Item.AttackBonus + e.BonusAttack) * pct / 100,
+		Defense: (e.Item.DefenseBonus + e.BonusDefense) * pct / 100,
+		HP:      (e.Item.HPBonus + e.BonusHP) * pct / 100,
+		Speed:   (e.Item.SpeedBonus + e.BonusSpeed) * pct / 100,
+	}
+	for _, r := range e.Runes {
+		b = b.add(Stats{Attack: r.RuneItem.AttackBonus, Defense: r.RuneItem.DefenseBonus, HP: r.RuneItem.HPBonus, Speed: r.RuneItem.SpeedBonus})
+	}
+	return b
+}
+
+// TotalGearBonus sums the bonuses of a character's equipment
+func TotalGearBonus(gear []models.Equipment) Stats {
+	var total Stats
+	for _, e := range gear {
+		total = total.add(PieceBonus(e))
+	}
+	return total
+}
+
+// Loadout is a character's equipment with the stats it adds up to
+type Loadout struct {
+	CharacterID uint               `json:"character_id"`
+	Gear        []models.Equipment `json:"gear"`
+	Bonus       Stats              `json:"bonus"`
+	Effective   Stats              `json:"effective_stats"` // Stats.Final
+	Stats       StatBreakdown      `json:"stats"`
+}
+
+// EnhanceResult reports one enhancement attempt
+type EnhanceResult struct {
+	Equipment *models.Equipment       `json:"equipment"`
+	Success   bool                    `json:"success"`
+	Chance    float64                 `json:"chance"`
+	GTKCost   int64                   `json:"gtk_cost"`
+	Materials []models.MaterialAmount `json:"materials"`
+}
+
+// EquipmentService manages gear slots, enhancement and rune sockets. Equipping never
+// touches a character's stat columns; the stat pipeline adds gear when stats are read.
+type EquipmentService struct {
+	store  repository.Store
+	ledger *LedgerService
+	config Settings
+}
+
+// NewEquipmentService creates the equipment service
+func NewEquipmentService(store repository.Store, ledger *LedgerService, config Settings) *EquipmentService {
+	return &EquipmentService{
+		store:  store,
+		ledger: ledger,
+		config: config,
+	}
+}
+
+// GetLoadout returns a character's equipment and its stats with the gear on. Team
+// synergies and battle effects are not part of it.
+func (s *EquipmentService) GetLoadout(userID, characterID uint) (*Loadout, error) {
+	char, err := s.store.Characters().Get(characterID)
+	if err != nil {
+		return nil, err
+	}
+	if char.OwnerID != userID {
+		return nil, errors.New("character not found")
+	}
+	gear, err := s.store.Equipment().Gear(characterID)
+	if err != nil {
+		return nil, err
+	}
+	stats := ComputeStats(StatInput{Character: withRestedFatigue(char, s.config), Gear: gear})
+	return &Loadout{
+		CharacterID: characterID,
+		Gear:        gear,
+		Bonus:       TotalGearBonus(gear),
+		Effective:   stats.Final,
+		Stats:       stats,
+	}, nil
+}
+
+// Equip puts an item into its slot on a character, replacing whatever was there.
+// The character must meet the item's level and class requirements.
+func (s *EquipmentService) Equip(userID, characterID, itemID uint) error {
+	return s.store.Transaction(func(tx repository.Store) error {
+		item, err := tx.Items().Lock(itemID)
+		if err != nil || item.OwnerID != userID {
+			return errors.New("item not found")
+		}
+		slot, ok := gearSlots[item.ItemType]
+		if !ok {
+			if item.ItemType == "RUNE" {
+				return errors.New("runes are socketed into gear, not equipped")
+			}
+			return errors.New("item is not equipment")
+		}
+		if item.IsListed {
+			return errors.New("item is listed on the marketplace")
+		}
+		if item.IsBroken {
+			return errors.New("item is broken")
+		}
+		if item.IsEquipped && item.EquippedByID != nil && *item.EquippedByID == characterID {
+			return errors.New("item already equipped")
+		}
+
+		char, err := tx.Characters().Get(characterID)
+		if err != nil || char.OwnerID != userID {
+			return errors.New("character not found")
+		}
+
+		gear, err := ensureEquipment(tx, item)
+		if err != nil {
+			return err
+		}
+		if char.Level < gear.RequiredLevel {
+			return fmt.Errorf("requires level %d", gear.RequiredLevel)
+		}
+		if gear.RequiredClass != "" && gear.RequiredClass != char.Class {
+			return fmt.Errorf("only %s characters can equip this", gear.RequiredClass)
+		}
+
+		// Moving gear between characters frees the old slot first
+		if item.IsEquipped && item.EquippedByID != nil {
+			if err := clearSlot(tx, *item.EquippedByID, gear.ID); err != nil {
+				return err
+			}
+		}
+
+		loadout, err := tx.Equipment().LockLoadout(characterID)
+		if errors.Is(err, repository.ErrNotFound) {
+			loadout, err = &models.CharacterEquipment{CharacterID: characterID}, nil
+		}
+		if err != nil {
+			return err
+		}
+		target := loadoutSlot(loadout, slot)
+		if *target != nil {
+			if err := releaseGear(tx, **target); err != nil {
+				return err
+			}
+		}
+		*target = &gear.ID
+		if err := tx.Equipment().SaveLoadout(loadout); err != nil {
+			return err
+		}
+
+		item.IsEquipped = true
+		item.EquippedByID = &characterID
+		return tx.Items().Save(item)
+	})
+}
+
+// Unequip takes an item off the character wearing it
+func (s *EquipmentService) Unequip(userID, itemID uint) error {
+	return s.store.Transaction(func(tx repository.Store) error {
+		item, err := tx.Items().Lock(itemID)
+		if err != nil || item.OwnerID != userID {
+			return errors.New("item not found")
+		}
+		if !item.IsEquipped || item.EquippedByID == nil {
+			return errors.New("item is not equipped")
+		}
+		if gear, err := tx.Equipment().LockForItem(item.ID); err == nil {
+			if err := clearSlot(tx, *item.EquippedByID, gear.ID); err != nil {
+				return err
+			}
+		} else if !errors.Is(err, repository.ErrNotFound) {
+			return err
+		}
+		item.IsEquipped = false
+		item.EquippedByID = nil
+		return tx.Items().Save(item)
+	})
+}
+
+// Enhance tries to raise a piece of gear by +1. The attempt costs GTK and the
+// materials its type salvages into, both scaled by the target level; the chance
+// of success drops with every level. A failed attempt keeps the current level.
+func (s *EquipmentService) Enhance(userID, itemID uint) (*EnhanceResult, error) {
+	var result *EnhanceResult
+
+	err := s.store.Transaction(func(tx repository.Store) error {
+		item, err := tx.Items().Lock(itemID)
+		if err != nil || item.OwnerID != userID {
+			return errors.New("item not found")
+		}
+		if _, ok := gearSlots[item.ItemType]; !ok {
+			return errors.New("only weapons, armor and accessories can be enhanced")
+		}
+		if item.IsListed {
+			return errors.New("item is listed on the marketplace")
+		}
+
+		gear, err := ensureEquipment(tx, item)
+		if err != nil {
+			return err
+		}
+		if gear.UpgradeLevel >= gear.MaxUpgradeLevel {
+			return fmt.Errorf("already at the maximum +%d", gear.MaxUpgradeLevel)
+		}
+
+		target := gear.UpgradeLevel + 1
+		result = &EnhanceResult{
+			GTKCost:   int64(s.config.GetInt("equipment_enhance_gtk_per_level", 100) * target),
+			Chance:    max(s.config.GetFloat("equipment_enhance_min_chance", 0.25), 1-float64(gear.UpgradeLevel)*s.config.GetFloat("equipment_enhance_chance_step", 0.12)),
+			Materials: []models.MaterialAmount{},
+		}
+
+		if y, err := tx.Crafting().SalvageYield(item.ItemType); err == nil {
+			if err := json.Unmarshal([]byte(y.Materials), &result.Materials); err != nil {
+				return fmt.Errorf("salvage yield %s: %w", y.ItemType, err)
+			}
+		} else if !errors.Is(err, repository.ErrNotFound) {
+			return err
+		}
+		for i := range result.Materials {
+			result.Materials[i].Quantity *= target
+			if err := consumeMaterial(tx, userID, result.Materials[i]); err != nil {
+				return err
+			}
+		}
+
+		if result.GTKCost > 0 {
+			ledger := s.ledger.WithStore(tx)
+			userAcc, err := ledger.GetOrCreateAccount(&userID, models.AccountTypeWallet, "GTK")
+			if err != nil {
+				return err
+			}
+			sinkAcc, err := ledger.GetOrCreateAccount(nil, models.AccountTypeSink, "GTK")
+			if err != nil {
+				return err
+			}
+			entries := []models.LedgerEntry{
+				{AccountID: userAcc.ID, Amount: -result.GTKCost, Type: "DEBIT"},
+				{AccountID: sinkAcc.ID, Amount: result.GTKCost, Type: "CREDIT"},
+			}
+			ref := "enhance_" + strconv.Itoa(int(item.ID)) + "_" + strconv.Itoa(target)
+			if err := ledger.CreateTransaction(models.TxTypeEnhanceFee, ref, fmt.Sprintf("Enhance %s to +%d", item.Name, target), entries); err != nil {
+				return err
+			}
+		}
+
+		if rand.Float64() < result.Chance {
+			result.Success = true
+			gear.UpgradeLevel = target
+			if err := tx.Equipment().Save(gear); err != nil {
+				return err
+			}
+		}
+		gear.Item = *item
+		result.Equipment = gear
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SocketRune sets a RUNE item into a free socket of a piece of gear. The rune counts
+// as equipped while socketed, so it cannot be listed or salvaged.
+func (s *EquipmentService) SocketRune(userID, gearItemID, runeItemID uint) (*models.Equipment, error) {
+	var gear *models.Equipment
+
+	err := s.store.Transaction(func(tx repository.Store) error {
+		item, err := tx.Items().Lock(gearItemID)
+		if err != nil || item.OwnerID != userID {
+			return errors.New("item not found")
+		}
+		if _, ok := gearSlots[item.ItemType]; !ok {
+			return errors.New("runes can only be socketed into weapons, armor and accessories")
+		}
+		runeItem, err := tx.Items().Lock(runeItemID)
+		if err != nil || runeItem.OwnerID != userID {
+			return errors.New("rune not found")
+		}
+		if runeItem.ItemType != "RUNE" {
+			return errors.New("item is not a rune")
+		}
+		if runeItem.IsEquipped || runeItem.IsListed {
+			return errors.New("rune is already in use")
+		}
+
+		gear, err = ensureEquipment(tx, item)
+		if err != nil {
+			return err
+		}
+		runes, err := tx.Equipment().Runes(gear.ID)
+		if err != nil {
+			return err
+		}
+		if len(runes) >= gear.RuneSockets {
+			return fmt.Errorf("all %d rune sockets are full", gear.RuneSockets)
+		}
+
+		if err := tx.Equipment().AddRune(&models.EquipmentRune{EquipmentID: gear.ID, RuneItemID: runeItem.ID}); err != nil {
+			return err
+		}
+		runeItem.IsEquipped = true
+		if err := tx.Items().Save(runeItem); err != nil {
+			return err
+		}
+
+		gear.Item = *item
+		gear.Runes, err = tx.Equipment().Runes(gear.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return gear, nil
+}
+
+// UnsocketRune takes a rune out of the gear it is socketed into
+func (s *EquipmentService) UnsocketRune(userID, runeItemID uint) error {
+	return s.store.Transaction(func(tx repository.Store) error {
+		runeItem, err := tx.Items().Lock(runeItemID)
+		if err != nil || runeItem.OwnerID != userID {
+			return errors.New("rune not found")
+		}
+		socket, err := tx.Equipment().RuneByItem(runeItem.ID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return errors.New("rune is not socketed")
+		}
+		if err != nil {
+			return err
+		}
+		if err := tx.Equipment().RemoveRune(socket.ID); err != nil {
+			return err
+		}
+		runeItem.IsEquipped = false
+		return tx.Items().Save(runeItem)
+	})
+}
+
+// ensureEquipment returns the equipment row of a gear item, creating it from the
+// item's rarity the first time the item is equipped, enhanced or socketed
+func ensureEquipment(tx repository.Store, item *models.Item) (*models.Equipment, error) {
+	gear, err := tx.Equipment().LockForItem(item.ID)
+	if !errors.Is(err, repository.ErrNotFound) {
+		return gear, err
+	}
+	profile, ok := gearProfile[item.Rarity]
+	if !ok {
+		profile = gearProfile["C"]
+	}
+	gear = &models.Equipment{
+		ItemID:          item.ID,
+		Slot:            gearSlots[item.ItemType],
+		RequiredLevel:   profile.Level,
+		MaxUpgradeLevel: profile.MaxUpgrade,
+		RuneSockets:     profile.Sockets,
+	}
+	if err := tx.Equipment().Create(gear); err != nil {
+		return nil, err
+	}
+	return gear, nil
+}
+
+// unsocketAll returns the runes socketed into a gear item to the inventory
+func unsocketAll(tx repository.Store, itemID uint) error {
+	gear, err := tx.Equipment().LockForItem(itemID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	runes, err := tx.Equipment().Runes(gear.ID)
+	if err != nil {
+		return err
+	}
+	for _, r := range runes {
+		if err := tx.Equipment().RemoveRune(r.ID); err != nil {
+			return err
+		}
+		runeItem := r.RuneItem
+		runeItem.IsEquipped = false
+		if err := tx.Items().Save(&runeItem); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadoutSlot points at the loadout column of a slot
+func loadoutSlot(l *models.CharacterEquipment, slot string) **uint {
+	switch slot {
+	case models.SlotWeapon:
+		return &l.WeaponID
+	case models.SlotArmor:
+		return &l.ArmorID
+	default:
+		return &l.AccessoryID
+	}
+}
+
+// clearSlot empties whichever slot of a character holds equipmentID
+func clearSlot(tx repository.Store, characterID, equipmentID uint) error {
+	loadout, err := tx.Equipment().LockLoadout(characterID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, slot := range []string{models.SlotWeapon, models.SlotArmor, models.SlotAccessory} {
+		if p := loadoutSlot(loadout, slot); *p != nil && **p == equipmentID {
+			*p = nil
+		}
+	}
+	return tx.Equipment().SaveLoadout(loadout)
+}
+
+// stripLoadout empties every slot of a character and unequips the items, which stay with their owner
+func stripLoadout(tx repository.Store, characterID uint) error {
+	loadout, err := tx.Equipment().LockLoadout(characterID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, slot := range []string{models.SlotWeapon, models.SlotArmor, models.SlotAccessory} {
+		p := loadoutSlot(loadout, slot)
+		if *p == nil {
+			continue
+		}
+		if err := releaseGear(tx, **p); err != nil {
+			return err
+		}
+		*p = nil
+	}
+	return tx.Equipment().SaveLoadout(loadout)
+}
+
+// releaseGear marks the item behind an equipment row as no longer equipped
+func releaseGear(tx repository.Store, equipmentID uint) error {
+	gear, err := tx.Equipment().Lock(equipmentID)
+	if err != nil {
+		return err
+	}
+	item, err := tx.Items().Lock(gear.ItemID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil // Salvaged or deleted since
+	}
+	if err != nil {
+		return err
+	}
+	item.IsEquipped = false
+	item.EquippedByID = nil
+	return tx.Items().Save(item)
+}
@@ -0,0 +1,198 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/internal/repository"
+)
+
+func newTestGear(t *testing.T, st repository.Store, ownerID uint, itemType, rarity string) *models.Item {
+	t.Helper()
+	bonuses := calculateItemBonuses(itemType, rarity)
+	item := &models.Item{
+		OwnerID:      ownerID,
+		ItemType:     itemType,
+		Name:         "Test " + itemType,
+		Rarity:       rarity,
+		AttackBonus:  bonuses.Attack,
+		DefenseBonus: bonuses.Defense,
+		HPBonus:      bonuses.HP,
+		SpeedBonus:   bonuses.Speed,
+		Quantity:     1,
+	}
+	if err := st.Items().Create(item); err != nil {
+		t.Fatal(err)
+	}
+	return item
+}
+
+func levelCharacter(t *testing.T, st repository.Store, c *models.Character, level int) {
+	t.Helper()
+	c.Level = level
+	if err := st.Characters().Save(c); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEquipKeepsOneItemPerSlotWithoutTouchingStats(t *testing.T) {
+	st := repository.NewMemoryStore()
+	gear := NewEquipmentService(st, NewLedgerService(st), testSettings{})
+	user := newTestUser(t, st, 1000)
+	char := newTestCharacter(t, st, user.ID, 100, 50)
+	levelCharacter(t, st, char, 1)
+
+	first := newTestGear(t, st, user.ID, "WEAPON", "C")
+	second := newTestGear(t, st, user.ID, "WEAPON", "C")
+	armor := newTestGear(t, st, user.ID, "ARMOR", "C")
+	for _, it := range []*models.Item{first, armor, second} {
+		if err := gear.Equip(user.ID, char.ID, it.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got, _ := st.Items().Get(first.ID); got.IsEquipped {
+		t.Fatal("replaced weapon is still marked equipped")
+	}
+	loadout, err := gear.GetLoadout(user.ID, char.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loadout.Gear) != 2 {
+		t.Fatalf("loadout has %d pieces, want weapon and armor", len(loadout.Gear))
+	}
+	if want := second.AttackBonus; loadout.Bonus.Attack != want || loadout.Bonus.HP != armor.HPBonus {
+		t.Fatalf("bonus = %+v, want attack %d, hp %d", loadout.Bonus, want, armor.HPBonus)
+	}
+	// 50 attack at level 1 (+5%) is 52
+	if loadout.Effective.Attack != 52+second.AttackBonus {
+		t.Fatalf("effective attack = %d, want %d", loadout.Effective.Attack, 52+second.AttackBonus)
+	}
+	if got, _ := st.Characters().Get(char.ID); got.CurrentAttack != 50 {
+		t.Fatalf("equipping changed CurrentAttack to %d", got.CurrentAttack)
+	}
+
+	if err := gear.Unequip(user.ID, armor.ID); err != nil {
+		t.Fatal(err)
+	}
+	if loadout, _ := gear.GetLoadout(user.ID, char.ID); len(loadout.Gear) != 1 {
+		t.Fatalf("loadout has %d pieces after unequipping armor, want 1", len(loadout.Gear))
+	}
+}
+
+func TestEquipEnforcesRequirements(t *testing.T) {
+	st := repository.NewMemoryStore()
+	gear := NewEquipmentService(st, NewLedgerService(st), testSettings{})
+	user := newTestUser(t, st, 1000)
+	char := newTestCharacter(t, st, user.ID, 100, 50)
+	char.Class = "Mage"
+	levelCharacter(t, st, char, 3)
+
+	if err := gear.Equip(user.ID, char.ID, newTestGear(t, st, user.ID, "WEAPON", "B").ID); err == nil {
+		t.Fatal("level 3 character equipped B-rank gear (level 5)")
+	}
+	if err := gear.Equip(user.ID, char.ID, newTestGear(t, st, user.ID, "RUNE", "C").ID); err == nil {
+		t.Fatal("equipped a rune into a slot")
+	}
+
+	axe := newTestGear(t, st, user.ID, "WEAPON", "C")
+	if err := st.Equipment().Create(&models.Equipment{ItemID: axe.ID, Slot: models.SlotWeapon, RequiredLevel: 1, RequiredClass: "Warrior"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := gear.Equip(user.ID, char.ID, axe.ID); err == nil {
+		t.Fatal("Mage equipped Warrior-only gear")
+	}
+
+	other := newTestUser(t, st, 1000)
+	if err := gear.Equip(other.ID, char.ID, newTestGear(t, st, other.ID, "WEAPON", "C").ID); err == nil {
+		t.Fatal("equipped gear onto someone else's character")
+	}
+}
+
+func TestEnhanceChargesAndRaisesBonus(t *testing.T) {
+	st := repository.NewMemoryStore()
+	ledger := NewLedgerService(st)
+	// +0 -> +1 always succeeds; every later attempt fails
+	gear := NewEquipmentService(st, ledger, testSettings{"equipment_enhance_chance_step": 1, "equipment_enhance_min_chance": 0})
+	st.PutSalvageYield(models.SalvageYield{ItemType: "WEAPON", Materials: `[{"name": "Iron Scrap", "quantity": 2}]`})
+	user := newTestUser(t, st, 1000)
+	fund(t, ledger, user.ID, 1000)
+	giveMaterial(t, st, user.ID, "Iron Scrap", 6)
+	sword := newTestGear(t, st, user.ID, "WEAPON", "C")
+
+	res, err := gear.Enhance(user.ID, sword.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Success || res.Equipment.UpgradeLevel != 1 || res.GTKCost != 100 {
+		t.Fatalf("first enhance = %+v", res)
+	}
+	if got := PieceBonus(*res.Equipment).Attack; got != sword.AttackBonus*110/100 {
+		t.Fatalf("+1 attack = %d, want %d", got, sword.AttackBonus*110/100)
+	}
+
+	res, err = gear.Enhance(user.ID, sword.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Success || res.Equipment.UpgradeLevel != 1 {
+		t.Fatalf("second enhance = %+v, want a failure at +1", res)
+	}
+	// Both attempts were paid for: 100 + 200 GTK, 2 + 4 Iron Scrap
+	if got := balance(t, ledger, &user.ID, models.AccountTypeWallet); got != 700 {
+		t.Fatalf("wallet = %d, want 700", got)
+	}
+	if got := materialCount(t, st, user.ID, "Iron Scrap"); got != 0 {
+		t.Fatalf("Iron Scrap = %d, want 0", got)
+	}
+	if _, err := gear.Enhance(user.ID, sword.ID); err == nil {
+		t.Fatal("enhanced without materials")
+	}
+	if got := balance(t, ledger, &user.ID, models.AccountTypeWallet); got != 700 {
+		t.Fatalf("wallet = %d after a rejected attempt, want 700", got)
+	}
+}
+
+func TestRunesSocketIntoGearAndCountInBattle(t *testing.T) {
+	st := repository.NewMemoryStore()
+	gear := NewEquipmentService(st, NewLedgerService(st), testSettings{})
+	battles, _ := newTestBattleService(st)
+	user := newTestUser(t, st, 1000)
+	char := newTestCharacter(t, st, user.ID, 100, 50)
+	levelCharacter(t, st, char, 1)
+	st.SetActiveTeam(user.ID, char.ID)
+
+	sword := newTestGear(t, st, user.ID, "WEAPON", "C") // One socket
+	rune1 := newTestGear(t, st, user.ID, "RUNE", "C")
+	rune2 := newTestGear(t, st, user.ID, "RUNE", "C")
+	if err := gear.Equip(user.ID, char.ID, sword.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gear.SocketRune(user.ID, sword.ID, rune1.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gear.SocketRune(user.ID, sword.ID, rune2.ID); err == nil {
+		t.Fatal("socketed a second rune into a one-socket weapon")
+	}
+	if got, _ := st.Items().Get(rune1.ID); !got.IsEquipped {
+		t.Fatal("socketed rune is not marked in use")
+	}
+
+	team, err := battles.GetTeamSnapshot(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 52 + sword.AttackBonus + rune1.AttackBonus; team[0].Attack != want {
+		t.Fatalf("battle attack = %d, want %d", team[0].Attack, want)
+	}
+	if want := 10 + rune1.DefenseBonus; team[0].Defense != want {
+		t.Fatalf("battle defense = %d, want %d", team[0].Defense, want)
+	}
+
+	if err := gear.UnsocketRune(user.ID, rune1.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gear.SocketRune(user.ID, sword.ID, rune2.ID); err != nil {
+		t.Fatalf("socket not freed: %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/internal/repository"
+)
+
+// testSettings serves every tunable at its default unless overridden
+type testSettings map[string]float64
+
+func (s testSettings) GetInt(key string, defaultVal int) int {
+	if v, ok := s[key]; ok {
+		return int(v)
+	}
+	return defaultVal
+}
+
+func (s testSettings) GetFloat(key string, defaultVal float64) float64 {
+	if v, ok := s[key]; ok {
+		return v
+	}
+	return defaultVal
+}
+
+// testNotifier records notifications instead of writing them
+type testNotifier struct {
+	sent []string // Notification types, in order
+}
+
+func (n *testNotifier) CreateNotification(userID uint, notifType, title, message string, data interface{}) error {
+	n.sent = append(n.sent, notifType)
+	return nil
+}
+
+// testSkills is a BattleSkills stub: no skills, no mana, no cooldowns
+type testSkills struct{}
+
+func (testSkills) ActivateSkill(SkillActivationRequest) (*SkillActivationResult, error) {
+	return &SkillActivationResult{Damage: 30, Message: "Test skill"}, nil
+}
+func (testSkills) ReduceCooldowns(uint)      {}
+func (testSkills) RegenerateMana(uint) error { return nil }
+func (testSkills) GetUsableSkills(uint, int) ([]models.Ability, error) {
+	return nil, nil
+}
+
+// testEffects is a BattleEffects stub without status effects
+type testEffects struct{}
+
+func (testEffects) ProcessTurnEffects(uint) (int, []string, error) { return 0, nil, nil }
+
+func newTestUser(t *testing.T, st repository.Store, elo int) *models.User {
+	t.Helper()
+	u := &models.User{ELO: elo, Level: 1}
+	if err := st.Users().Create(u); err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
+
+func newTestCharacter(t *testing.T, st repository.Store, ownerID uint, hp, attack int) *models.Character {
+	t.Helper()
+	c := &models.Character{
+		OwnerID:        ownerID,
+		Name:           "Test Monster",
+		Element:        "BEAST",
+		BaseHP:         hp,
+		BaseAttack:     attack,
+		BaseDefense:    10,
+		BaseSpeed:      10,
+		CurrentHP:      hp,
+		CurrentAttack:  attack,
+		CurrentDefense: 10,
+		CurrentSpeed:   10,
+		Durability:     100,
+	}
+	if err := st.Characters().Create(c); err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// fund credits a wallet from the reward pool
+func fund(t *testing.T, ledger *LedgerService, userID uint, amount int64) {
+	t.Helper()
+	wallet, err := ledger.GetOrCreateAccount(&userID, models.AccountTypeWallet, "GTK")
+	if err != nil {
+		t.Fatal(err)
+	}
+	reward, err := ledger.GetOrCreateAccount(nil, models.AccountTypeReward, "GTK")
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := []models.LedgerEntry{
+		{AccountID: reward.ID, Amount: -amount, Type: "DEBIT"},
+		{AccountID: wallet.ID, Amount: amount, Type: "CREDIT"},
+	}
+	if err := ledger.CreateTransaction(models.TxTypeReward, "test_fund", "Test funding", entries); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func balance(t *testing.T, ledger *LedgerService, userID *uint, accType models.AccountType) int64 {
+	t.Helper()
+	acc, err := ledger.GetOrCreateAccount(userID, accType, "GTK")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return acc.Balance
+}
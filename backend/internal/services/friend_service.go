@@ -0,0 +1,562 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/db"
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FriendService handles friend requests, blocking, presence and direct challenges
+type FriendService struct {
+	ledger        *LedgerService
+	battleService *BattleService
+	notifications *NotificationService
+	config        *ConfigService
+}
+
+func NewFriendService(ledger *LedgerService, battles *BattleService) *FriendService {
+	return &FriendService{
+		ledger:        ledger,
+		battleService: battles,
+		notifications: &NotificationService{},
+		config:        GetConfigService(),
+	}
+}
+
+// FriendEntry is a friend (or pending request) with presence info
+type FriendEntry struct {
+	FriendshipID  uint       `json:"friendship_id"`
+	UserID        uint       `json:"user_id"`
+	WalletAddress string     `json:"wallet_address"`
+	DisplayName   string     `json:"display_name"`
+	Level         int        `json:"level"`
+	ELO           int        `json:"elo"`
+	Status        string     `json:"status"`
+	Incoming      bool       `json:"incoming"` // Pending request sent to the caller
+	Online        bool       `json:"online"`
+	LastSeenAt    *time.Time `json:"last_seen_at"`
+	Since         time.Time  `json:"since"`
+}
+
+// onlineWindow is how recently a user must have hit the API to count as online
+const onlineWindow = 5 * time.Minute
+
+// IsOnline reports presence from the LastSeenAt heartbeat
+func IsOnline(lastSeen *time.Time) bool {
+	return lastSeen != nil && time.Since(*lastSeen) < onlineWindow
+}
+
+// SendFriendRequest creates a pending request. If the target already asked the caller, both become friends.
+func (s *FriendService) SendFriendRequest(userID, targetID uint) (*models.Friendship, error) {
+	if userID == targetID {
+		return nil, errors.New("cannot add yourself")
+	}
+	var target models.User
+	if err := db.DB.First(&target, targetID).Error; err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	var friendship models.Friendship
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		var existing []models.Friendship
+		tx.Where("(user_id = ? AND friend_id = ?) OR (user_id = ? AND friend_id = ?)", userID, targetID, targetID, userID).Find(&existing)
+
+		for _, f := range existing {
+			switch {
+			case f.Status == models.FriendshipBlocked:
+				return errors.New("cannot send a friend request to this user")
+			case f.Status == models.FriendshipAccepted:
+				return errors.New("already friends")
+			case f.UserID == userID:
+				return errors.New("friend request already sent")
+			default:
+				// Target already requested the caller: accept it
+				f.Status = models.FriendshipAccepted
+				friendship = f
+				return tx.Save(&friendship).Error
+			}
+		}
+
+		friendship = models.Friendship{UserID: userID, FriendID: targetID, Status: models.FriendshipPending}
+		return tx.Create(&friendship).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if friendship.Status == models.FriendshipAccepted {
+		s.notify(targetID, "FRIEND_ACCEPTED", "Friend request accepted", "You have a new friend", map[string]interface{}{"user_id": userID})
+	} else {
+		s.notify(targetID, "FRIEND_REQUEST", "New friend request", "Someone wants to be your friend", map[string]interface{}{"user_id": userID, "friendship_id": friendship.ID})
+	}
+	return &friendship, nil
+}
+
+// RespondFriendRequest accepts or declines a pending request addressed to the caller
+func (s *FriendService) RespondFriendRequest(userID, friendshipID uint, accept bool) error {
+	var f models.Friendship
+	if err := db.DB.First(&f, friendshipID).Error; err != nil {
+		return errors.New("friend request not found")
+	}
+	if f.FriendID != userID || f.Status != models.FriendshipPending {
+		return errors.New("friend request not found")
+	}
+
+	if !accept {
+		return db.DB.Delete(&f).Error
+	}
+	f.Status = models.FriendshipAccepted
+	if err := db.DB.Save(&f).Error; err != nil {
+		return err
+	}
+	s.notify(f.UserID, "FRIEND_ACCEPTED", "Friend request accepted", "You have a new friend", map[string]interface{}{"user_id": userID})
+	return nil
+}
+
+// RemoveFriend deletes a friendship or cancels an outgoing request. Blocks are left untouched.
+func (s *FriendService) RemoveFriend(userID, otherID uint) error {
+	res := db.DB.Where("status != ? AND ((user_id = ? AND friend_id = ?) OR (user_id = ? AND friend_id = ?))",
+		models.FriendshipBlocked, userID, otherID, otherID, userID).Delete(&models.Friendship{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errors.New("not friends")
+	}
+	return nil
+}
+
+// BlockUser removes any friendship in either direction and records a block owned by the caller
+func (s *FriendService) BlockUser(userID, targetID uint) error {
+	if userID == targetID {
+		return errors.New("cannot block yourself")
+	}
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("(user_id = ? AND friend_id = ?) OR (user_id = ? AND friend_id = ? AND status != ?)",
+			userID, targetID, targetID, userID, models.FriendshipBlocked).Delete(&models.Friendship{}).Error; err != nil {
+			return err
+		}
+		// Pending challenges between the two are closed and stakes returned
+		var open []models.Challenge
+		tx.Where("status = ? AND ((challenger_id = ? AND opponent_id = ?) OR (challenger_id = ? AND opponent_id = ?))",
+			models.ChallengePending, userID, targetID, targetID, userID).Find(&open)
+		for i := range open {
+			if err := s.closeChallenge(tx, &open[i], models.ChallengeCancelled); err != nil {
+				return err
+			}
+		}
+		return tx.Create(&models.Friendship{UserID: userID, FriendID: targetID, Status: models.FriendshipBlocked}).Error
+	})
+}
+
+// UnblockUser lifts a block the caller placed
+func (s *FriendService) UnblockUser(userID, targetID uint) error {
+	res := db.DB.Where("user_id = ? AND friend_id = ? AND status = ?", userID, targetID, models.FriendshipBlocked).Delete(&models.Friendship{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errors.New("user is not blocked")
+	}
+	return nil
+}
+
+// ListFriends returns accepted friends (online first), or pending/blocked entries when status is given
+func (s *FriendService) ListFriends(userID uint, status string) ([]FriendEntry, error) {
+	if status == "" {
+		status = models.FriendshipAccepted
+	}
+
+	query := db.DB.Preload("User").Preload("Friend").Where("status = ?", status)
+	if status == models.FriendshipBlocked {
+		query = query.Where("user_id = ?", userID)
+	} else {
+		query = query.Where("user_id = ? OR friend_id = ?", userID, userID)
+	}
+
+	var rows []models.Friendship
+	if err := query.Order("created_at DESC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	entries := make([]FriendEntry, 0, len(rows))
+	for _, f := range rows {
+		other := f.Friend
+		if f.FriendID == userID {
+			other = f.User
+		}
+		entries = append(entries, FriendEntry{
+			FriendshipID:  f.ID,
+			UserID:        other.ID,
+			WalletAddress: other.WalletAddress,
+			DisplayName:   other.DisplayName,
+			Level:         other.Level,
+			ELO:           other.ELO,
+			Status:        f.Status,
+			Incoming:      f.Status == models.FriendshipPending && f.FriendID == userID,
+			Online:        IsOnline(other.LastSeenAt),
+			LastSeenAt:    other.LastSeenAt,
+			Since:         f.CreatedAt,
+		})
+	}
+
+	// Online friends first, keep recency order otherwise
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Online && !entries[j].Online })
+	return entries, nil
+}
+
+// SearchUsers finds players by wallet address prefix or display name, skipping anyone who blocked the caller
+func (s *FriendService) SearchUsers(userID uint, query string, limit int) ([]FriendEntry, error) {
+	query = strings.TrimSpace(query)
+	if len(query) < 3 {
+		return nil, errors.New("search query must be at least 3 characters")
+	}
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	var users []models.User
+	err := db.DB.Where("id != ?", userID).
+		Where("LOWER(wallet_address) LIKE ? OR LOWER(display_name) LIKE ?",
+			strings.ToLower(query)+"%", "%"+strings.ToLower(query)+"%").
+		Where("id NOT IN (?)", db.DB.Model(&models.Friendship{}).Select("user_id").
+			Where("friend_id = ? AND status = ?", userID, models.FriendshipBlocked)).
+		Limit(limit).
+		Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// Attach existing relationship status
+	statusByUser := make(map[uint]string)
+	var rels []models.Friendship
+	db.DB.Where("user_id = ? OR friend_id = ?", userID, userID).Find(&rels)
+	for _, f := range rels {
+		if f.UserID == userID {
+			statusByUser[f.FriendID] = f.Status
+		} else if f.Status != models.FriendshipBlocked {
+			statusByUser[f.UserID] = f.Status
+		}
+	}
+
+	results := make([]FriendEntry, 0, len(users))
+	for _, u := range users {
+		results = append(results, FriendEntry{
+			UserID:        u.ID,
+			WalletAddress: u.WalletAddress,
+			DisplayName:   u.DisplayName,
+			Level:         u.Level,
+			ELO:           u.ELO,
+			Status:        statusByUser[u.ID],
+			Online:        IsOnline(u.LastSeenAt),
+			LastSeenAt:    u.LastSeenAt,
+		})
+	}
+	return results, nil
+}
+
+// SetDisplayName updates the caller's searchable display name
+func (s *FriendService) SetDisplayName(userID uint, name string) error {
+	name = strings.TrimSpace(name)
+	if len(name) < 3 || len(name) > 32 {
+		return errors.New("display name must be 3-32 characters")
+	}
+	var count int64
+	db.DB.Model(&models.User{}).Where("LOWER(display_name) = LOWER(?) AND id != ?", name, userID).Count(&count)
+	if count > 0 {
+		return errors.New("display name already taken")
+	}
+	return db.DB.Model(&models.User{}).Where("id = ?", userID).Update("display_name", name).Error
+}
+
+// AreFriends reports whether two users have an accepted friendship
+func (s *FriendService) AreFriends(a, b uint) bool {
+	var count int64
+	db.DB.Model(&models.Friendship{}).
+		Where("status = ? AND ((user_id = ? AND friend_id = ?) OR (user_id = ? AND friend_id = ?))", models.FriendshipAccepted, a, b, b, a).
+		Count(&count)
+	return count > 0
+}
+
+// --- Direct challenges ---
+
+// SendChallenge invites a friend to a PvP battle. A non-zero stake is locked in escrow right away.
+func (s *FriendService) SendChallenge(challengerID, opponentID uint, stake int64, message string) (*models.Challenge, error) {
+	if stake < 0 {
+		return nil, errors.New("stake cannot be negative")
+	}
+	if maxStake := int64(s.config.GetInt("challenge_max_stake", 10000)); stake > maxStake {
+		return nil, fmt.Errorf("stake cannot exceed %d GTK", maxStake)
+	}
+	if len(message) > 140 {
+		return nil, errors.New("message too long (max 140 characters)")
+	}
+	if !s.AreFriends(challengerID, opponentID) {
+		return nil, errors.New("you can only challenge friends")
+	}
+	if _, err := s.battleService.GetTeamSnapshot(challengerID); err != nil {
+		return nil, errors.New("an active team is required to challenge")
+	}
+
+	var pending int64
+	db.DB.Model(&models.Challenge{}).
+		Where("challenger_id = ? AND opponent_id = ? AND status = ?", challengerID, opponentID, models.ChallengePending).
+		Count(&pending)
+	if pending > 0 {
+		return nil, errors.New("you already have a pending challenge with this player")
+	}
+
+	ttl := time.Duration(s.config.GetInt("challenge_expiry_minutes", 30)) * time.Minute
+	challenge := models.Challenge{
+		ChallengerID: challengerID,
+		OpponentID:   opponentID,
+		Stake:        stake,
+		Status:       models.ChallengePending,
+		Message:      message,
+		ExpiresAt:    time.Now().Add(ttl),
+	}
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&challenge).Error; err != nil {
+			return err
+		}
+		if stake > 0 {
+			return s.lockStake(tx, challengerID, stake, fmt.Sprintf("challenge_%d_p1", challenge.ID))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notify(opponentID, "CHALLENGE", "New battle challenge",
+		fmt.Sprintf("You have been challenged to a battle (stake: %d GTK)", stake),
+		map[string]interface{}{"challenge_id": challenge.ID, "challenger_id": challengerID, "stake": stake, "expires_at": challenge.ExpiresAt})
+	return &challenge, nil
+}
+
+// AcceptChallenge locks the opponent's matching stake and starts the battle.
+// Staked challenges become "wager" battles so CompleteBattle settles escrow; friendly ones are plain "pvp".
+func (s *FriendService) AcceptChallenge(userID, challengeID uint) (*models.Battle, error) {
+	var battle models.Battle
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		var challenge models.Challenge
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&challenge, challengeID).Error; err != nil {
+			return errors.New("challenge not found")
+		}
+		if challenge.OpponentID != userID {
+			return errors.New("challenge not found")
+		}
+		if challenge.Status != models.ChallengePending {
+			return errors.New("challenge is no longer pending")
+		}
+		if time.Now().After(challenge.ExpiresAt) {
+			return errors.New("challenge has expired")
+		}
+
+		var active int64
+		tx.Model(&models.Battle{}).
+			Where("(player1_id IN ? OR player2_id IN ?) AND status IN ?",
+				[]uint{challenge.ChallengerID, userID}, []uint{challenge.ChallengerID, userID}, []string{"SEARCHING", "active"}).
+			Count(&active)
+		if active > 0 {
+			return errors.New("one of the players is already in a battle")
+		}
+
+		if challenge.Stake > 0 {
+			if err := s.lockStake(tx, userID, challenge.Stake, fmt.Sprintf("challenge_%d_p2", challenge.ID)); err != nil {
+				return err
+			}
+		}
+
+		battle = models.Battle{
+			BattleType:          "pvp",
+			Status:              "active",
+			Player1ID:           challenge.ChallengerID,
+			Player2ID:           userID,
+			Player1Bet:          challenge.Stake,
+			Player2Bet:          challenge.Stake,
+			CurrentTurnPlayerID: challenge.ChallengerID,
+			TurnNumber:          1,
+		}
+		if challenge.Stake > 0 {
+			battle.BattleType = "wager"
+		}
+		if err := s.battleService.InitializeBattleState(&battle); err != nil {
+			return err
+		}
+		if err := tx.Create(&battle).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		challenge.Status = models.ChallengeAccepted
+		challenge.BattleID = &battle.ID
+		challenge.RespondedAt = &now
+		return tx.Save(&challenge).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notify(battle.Player1ID, "CHALLENGE_ACCEPTED", "Challenge accepted", "Your challenge was accepted. The battle has started!",
+		map[string]interface{}{"challenge_id": challengeID, "battle_id": battle.ID})
+	return &battle, nil
+}
+
+// DeclineChallenge lets the opponent turn the challenge down; the challenger's stake is refunded
+func (s *FriendService) DeclineChallenge(userID, challengeID uint) error {
+	return s.respondChallenge(userID, challengeID, false)
+}
+
+// CancelChallenge lets the challenger withdraw a pending challenge; the stake is refunded
+func (s *FriendService) CancelChallenge(userID, challengeID uint) error {
+	return s.respondChallenge(userID, challengeID, true)
+}
+
+// ListChallenges returns the caller's incoming and outgoing challenges, newest first
+func (s *FriendService) ListChallenges(userID uint, status string) ([]models.Challenge, error) {
+	query := db.DB.Preload("Challenger").Preload("Opponent").
+		Where("challenger_id = ? OR opponent_id = ?", userID, userID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var challenges []models.Challenge
+	if err := query.Order("created_at DESC").Limit(50).Find(&challenges).Error; err != nil {
+		return nil, err
+	}
+	return challenges, nil
+}
+
+// ExpireChallenges closes pending challenges past their deadline and refunds stakes
+func (s *FriendService) ExpireChallenges() error {
+	var expired []models.Challenge
+	if err := db.DB.Where("status = ? AND expires_at <= ?", models.ChallengePending, time.Now()).Find(&expired).Error; err != nil {
+		return err
+	}
+	for _, c := range expired {
+		err := db.DB.Transaction(func(tx *gorm.DB) error {
+			var challenge models.Challenge
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&challenge, c.ID).Error; err != nil {
+				return err
+			}
+			if challenge.Status != models.ChallengePending {
+				return nil
+			}
+			return s.closeChallenge(tx, &challenge, models.ChallengeExpired)
+		})
+		if err != nil {
+			slog.Error("failed to expire challenge", "challenge_id", c.ID, "error", err)
+		}
+	}
+	return nil
+}
+
+// StartScheduler expires stale challenges on a fixed interval in the background
+func (s *FriendService) StartScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := s.ExpireChallenges(); err != nil {
+				slog.Error("challenge scheduler failed", "error", err)
+			}
+		}
+	}()
+}
+
+func (s *FriendService) respondChallenge(userID, challengeID uint, asChallenger bool) error {
+	var notifyID uint
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		var challenge models.Challenge
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&challenge, challengeID).Error; err != nil {
+			return errors.New("challenge not found")
+		}
+		if (asChallenger && challenge.ChallengerID != userID) || (!asChallenger && challenge.OpponentID != userID) {
+			return errors.New("challenge not found")
+		}
+		if challenge.Status != models.ChallengePending {
+			return errors.New("challenge is no longer pending")
+		}
+
+		status := models.ChallengeDeclined
+		notifyID = challenge.ChallengerID
+		if asChallenger {
+			status = models.ChallengeCancelled
+			notifyID = challenge.OpponentID
+		}
+		return s.closeChallenge(tx, &challenge, status)
+	})
+	if err != nil {
+		return err
+	}
+
+	if asChallenger {
+		s.notify(notifyID, "CHALLENGE_CANCELLED", "Challenge cancelled", "A challenge you received was withdrawn", map[string]interface{}{"challenge_id": challengeID})
+	} else {
+		s.notify(notifyID, "CHALLENGE_DECLINED", "Challenge declined", "Your challenge was declined", map[string]interface{}{"challenge_id": challengeID})
+	}
+	return nil
+}
+
+// closeChallenge sets a terminal status and refunds the challenger's escrowed stake
+func (s *FriendService) closeChallenge(tx *gorm.DB, challenge *models.Challenge, status string) error {
+	if challenge.Stake > 0 {
+		escrowAcc, err := s.ledger.GetOrCreateAccount(nil, models.AccountTypeEscrow, "GTK")
+		if err != nil {
+			return err
+		}
+		userAcc, err := s.ledger.GetOrCreateAccount(&challenge.ChallengerID, models.AccountTypeWallet, "GTK")
+		if err != nil {
+			return err
+		}
+		entries := []models.LedgerEntry{
+			{AccountID: escrowAcc.ID, Amount: -challenge.Stake, Type: "DEBIT"},
+			{AccountID: userAcc.ID, Amount: challenge.Stake, Type: "CREDIT"},
+		}
+		if err := s.ledger.CreateTransactionWithTx(tx, models.TxTypeWagerRefund, fmt.Sprintf("challenge_%d_refund", challenge.ID),
+			"Challenge Stake Refund", entries); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	challenge.Status = status
+	challenge.RespondedAt = &now
+	return tx.Save(challenge).Error
+}
+
+// lockStake moves a player's stake from wallet to escrow
+func (s *FriendService) lockStake(tx *gorm.DB, userID uint, stake int64, refID string) error {
+	userAcc, err := s.ledger.GetOrCreateAccount(&userID, models.AccountTypeWallet, "GTK")
+	if err != nil {
+		return err
+	}
+	if userAcc.Balance < stake {
+		return fmt.Errorf("insufficient GTK balance for stake of %d", stake)
+	}
+	escrowAcc, err := s.ledger.GetOrCreateAccount(nil, models.AccountTypeEscrow, "GTK")
+	if err != nil {
+		return err
+	}
+	entries := []models.LedgerEntry{
+		{AccountID: userAcc.ID, Amount: -stake, Type: "DEBIT"},
+		{AccountID: escrowAcc.ID, Amount: stake, Type: "CREDIT"},
+	}
+	return s.ledger.CreateTransactionWithTx(tx, models.TxTypeWagerEnter, refID, "Challenge Stake Lock", entries)
+}
+
+// notify is best-effort: a failed notification never rolls back the social action
+func (s *FriendService) notify(userID uint, notifType, title, message string, data interface{}) {
+	if err := s.notifications.CreateNotification(userID, notifType, title, message, data); err != nil {
+		slog.Warn("failed to send social notification", "user_id", userID, "type", notifType, "error", err)
+	}
+}
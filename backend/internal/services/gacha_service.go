@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
 	"math"
 	"math/big"
 	"strings"
@@ -12,6 +13,8 @@ import (
 
 	"github.com/lorengraff/crypto-tower-defense/internal/db"
 	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/internal/repository"
+	"github.com/lorengraff/crypto-tower-defense/pkg/metrics"
 	"gorm.io/gorm"
 )
 
@@ -23,10 +26,10 @@ type GachaService struct {
 }
 
 // NewGachaService creates a new gacha service
-func NewGachaService(bc *BlockchainService) *GachaService {
+func NewGachaService(bc *BlockchainService, ledger *LedgerService) *GachaService {
 	return &GachaService{
 		nameGenerator: NewNameGeneratorService(),
-		ledger:        NewLedgerService(),
+		ledger:        ledger,
 		config:        GetConfigService(),
 		blockchain:    bc,
 	}
@@ -78,7 +81,7 @@ func (s *GachaService) MintEgg(userID uint, towerAmount int64, txHash string) (*
 				return nil, fmt.Errorf("blockchain verification failed: %v", err)
 			}
 		} else {
-			fmt.Println("⚠️ WARNING: Blockchain verification skipped (Service nil)")
+			slog.Warn("blockchain verification skipped: service unavailable")
 		}
 	}
 
@@ -101,22 +104,8 @@ func (s *GachaService) MintEgg(userID uint, towerAmount int64, txHash string) (*
 	// SECURITY CHECK 5: Check if first mint
 	isFirstMint := !false && towerAmount == 0
 
-	// Calculate rarity based on investment
-	rarity := s.rollRarity(towerAmount, isFirstMint)
-
-	// Roll character traits
-	charType := s.rollCharacterType()
-	element := s.rollElement()
-	class := s.rollClass()
-
-	// Calculate base stats (based on rarity)
-	baseStats := s.calculateBaseStats(rarity)
-
-	// Roll abilities (based on class + rarity)
-	abilities := s.rollAbilities(class, rarity)
-
-	// Calculate incubation time (in hours)
-	incubationTime := s.getIncubationTime(rarity)
+	// Roll rarity (based on investment) and traits
+	egg := s.rollEgg(userID, towerAmount, isFirstMint)
 
 	// BEGIN ATOMIC TRANSACTION
 	tx := db.DB.Begin()
@@ -160,25 +149,6 @@ func (s *GachaService) MintEgg(userID uint, towerAmount int64, txHash string) (*
 		}
 	}
 
-	// Serialize stats and abilities to JSON
-	statsJSON, _ := json.Marshal(baseStats)
-	abilitiesJSON, _ := json.Marshal(abilities)
-
-	// Create egg with pre-determined traits
-	egg := models.Egg{
-		UserID:                  userID,
-		Rarity:                  rarity,
-		Element:                 element,
-		CharacterType:           charType,
-		Class:                   class,
-		IncubationTime:          incubationTime,
-		MintCost:                towerAmount,
-		PredeterminedStats:      string(statsJSON),
-		PredeterminedAbilities:  string(abilitiesJSON),
-		EffectiveIncubationTime: incubationTime,
-		AcceleratorsApplied:     "[]", // Initialize with empty JSON array
-	}
-
 	if err := tx.Create(&egg).Error; err != nil {
 		tx.Rollback()
 		return nil, errors.New("failed to create egg")
@@ -206,7 +176,7 @@ func (s *GachaService) MintEgg(userID uint, towerAmount int64, txHash string) (*
 		BalanceBefore:    balanceBefore,
 		BalanceAfter:     balanceAfter,
 		BlockchainTxHash: blockchainHashPtr,
-		Description:      fmt.Sprintf("Minted %s egg (%s %s %s)", rarity, element, charType, class),
+		Description:      fmt.Sprintf("Minted %s egg (%s %s %s)", egg.Rarity, egg.Element, egg.CharacterType, egg.Class),
 	}
 	if err := tx.Create(&transaction).Error; err != nil {
 		tx.Rollback()
@@ -219,7 +189,7 @@ func (s *GachaService) MintEgg(userID uint, towerAmount int64, txHash string) (*
 		Action:     "EGG_MINT",
 		EntityType: "egg",
 		EntityID:   &egg.ID,
-		NewValues:  fmt.Sprintf("rarity:%s,cost:%d,type:%s", rarity, towerAmount, charType),
+		NewValues:  fmt.Sprintf("rarity:%s,cost:%d,type:%s", egg.Rarity, towerAmount, egg.CharacterType),
 	}
 	if err := tx.Create(&auditLog).Error; err != nil {
 		tx.Rollback()
@@ -229,10 +199,80 @@ func (s *GachaService) MintEgg(userID uint, towerAmount int64, txHash string) (*
 	if err := tx.Commit().Error; err != nil {
 		return nil, errors.New("transaction failed")
 	}
+	metrics.GachaMints.WithLabelValues(egg.Rarity).Inc()
+
+	return &egg, nil
+}
+
+// rollEgg rolls the rarity and pre-determined traits of a newly minted egg
+func (s *GachaService) rollEgg(userID uint, towerAmount int64, isFirstMint bool) models.Egg {
+	rarity := s.rollRarity(towerAmount, isFirstMint)
+
+	// Roll character traits
+	charType := s.rollCharacterType()
+	element := s.rollElement()
+	class := s.rollClass()
+
+	// Base stats depend on rarity, abilities on class + rarity
+	statsJSON, _ := json.Marshal(s.calculateBaseStats(rarity))
+	abilitiesJSON, _ := json.Marshal(s.rollAbilities(class, rarity))
+
+	// Incubation time (in hours)
+	incubationTime := s.getIncubationTime(rarity)
+
+	return models.Egg{
+		UserID:                  userID,
+		Rarity:                  rarity,
+		Element:                 element,
+		CharacterType:           charType,
+		Class:                   class,
+		IncubationTime:          incubationTime,
+		MintCost:                towerAmount,
+		PredeterminedStats:      string(statsJSON),
+		PredeterminedAbilities:  string(abilitiesJSON),
+		EffectiveIncubationTime: incubationTime,
+		AcceleratorsApplied:     "[]", // Initialize with empty JSON array
+	}
+}
 
+// RedeemEggTicket spends an Egg Ticket from the login calendar on a free mint. The ticket
+// is the limit, so the free-mint cooldown and the daily mint limit do not apply.
+func (s *GachaService) RedeemEggTicket(userID, itemID uint) (*models.Egg, error) {
+	egg := s.rollEgg(userID, 0, true)
+	err := repository.NewGormStore(db.DB).Transaction(func(tx repository.Store) error {
+		return redeemEggTicket(tx, userID, itemID, &egg)
+	})
+	if err != nil {
+		return nil, err
+	}
+	metrics.GachaMints.WithLabelValues(egg.Rarity).Inc()
 	return &egg, nil
 }
 
+// redeemEggTicket takes one Egg Ticket from the player's inventory and stores egg as theirs
+func redeemEggTicket(tx repository.Store, userID, shopItemID uint, egg *models.Egg) error {
+	item, err := tx.Inventory().ShopItem(shopItemID)
+	if err != nil || item.Category != "egg" || item.EffectType != "egg_ticket" {
+		return errors.New("item is not an egg ticket")
+	}
+	inv, err := tx.Inventory().Find(userID, shopItemID)
+	if err != nil || inv.Quantity <= 0 {
+		return fmt.Errorf("you don't have a %s", item.Name)
+	}
+	inv.Quantity--
+	if inv.Quantity == 0 {
+		err = tx.Inventory().Delete(inv)
+	} else {
+		err = tx.Inventory().Save(inv)
+	}
+	if err != nil {
+		return err
+	}
+	egg.UserID = userID
+	egg.MintCost = 0
+	return tx.Eggs().Create(egg)
+}
+
 // GetUserEggs returns all eggs owned by user
 func (s *GachaService) GetUserEggs(userID uint) ([]models.Egg, error) {
 	var eggs []models.Egg
@@ -297,6 +337,16 @@ func (s *GachaService) HatchEgg(userID, eggID uint) (*models.Character, error) {
 		return nil, fmt.Errorf("egg needs %v more to hatch", remaining.Round(time.Minute))
 	}
 
+	// Care windows nobody attended cost the egg its penalty before it hatches
+	if s.config == nil {
+		s.config = GetConfigService()
+	}
+	settled, care, err := settleForHatch(repository.NewGormStore(db.DB), egg.ID, s.config)
+	if err != nil {
+		return nil, err
+	}
+	egg = *settled
+
 	// Parse predetermined stats
 	var stats map[string]int
 	json.Unmarshal([]byte(egg.PredeterminedStats), &stats)
@@ -335,19 +385,21 @@ func (s *GachaService) HatchEgg(userID, eggID uint) (*models.Character, error) {
 		BaseDefense: stats["def"],
 		BaseSpeed:   stats["spd"],
 
-		CurrentHP:      stats["hp"],
-		CurrentAttack:  stats["atk"],
-		CurrentDefense: stats["def"],
-		CurrentSpeed:   stats["spd"],
-
 		// Abilities
 		UnlockedAbilities: string(abilitiesJSON),
 	}
+	character.Genome = encodeGenome(founderGenome(&character, newGeneRand(newGeneSeed())))
+	applyCareSlots(&character, care)
+	applySheetStats(&character, true)
 
 	if err := tx.Create(&character).Error; err != nil {
 		tx.Rollback()
 		return nil, errors.New("failed to create character")
 	}
+	if err := recordOrigin(repository.NewGormStore(tx), &character, &egg); err != nil {
+		tx.Rollback()
+		return nil, errors.New("failed to record provenance")
+	}
 
 	// Update egg
 	now := time.Now()
@@ -524,67 +576,65 @@ func (s *GachaService) secureRandom(min, max int64) int64 {
 
 // rollCharacterType randomly selects character type
 func (s *GachaService) rollCharacterType() string {
-	types := []string{"BEAST", "DRAGON", "BIRD", "INSECT", "AQUATIC", "MINERAL", "SPIRIT", "AVIAN", "PLANT", "MACHINE"}
-	index := s.secureRandom(0, int64(len(types)))
-	return types[index]
+	index := s.secureRandom(0, int64(len(characterTypes)))
+	return characterTypes[index]
 }
 
 // rollElement randomly selects element
 func (s *GachaService) rollElement() string {
-	elements := []string{"Fire", "Water", "Earth", "Air", "Light", "Dark", "Electric", "Ice"}
-	index := s.secureRandom(0, int64(len(elements)))
-	return elements[index]
+	index := s.secureRandom(0, int64(len(characterElements)))
+	return characterElements[index]
 }
 
 // rollClass randomly selects class
 func (s *GachaService) rollClass() string {
-	classes := []string{"Warrior", "Mage", "Archer", "Tank", "Support", "Rogue", "Paladin", "Berserker"}
-	index := s.secureRandom(0, int64(len(classes)))
-	return classes[index]
+	index := s.secureRandom(0, int64(len(characterClasses)))
+	return characterClasses[index]
+}
+
+// rarityBaseStats are the base stats a character of each rarity is rolled around
+var rarityBaseStats = map[string]map[string]int{
+	"C": {
+		"hp":  100,
+		"atk": 20,
+		"def": 15,
+		"spd": 10,
+	},
+	"B": {
+		"hp":  150,
+		"atk": 30,
+		"def": 25,
+		"spd": 15,
+	},
+	"A": {
+		"hp":  200,
+		"atk": 45,
+		"def": 35,
+		"spd": 20,
+	},
+	"S": {
+		"hp":  300,
+		"atk": 65,
+		"def": 50,
+		"spd": 30,
+	},
+	"SS": {
+		"hp":  450,
+		"atk": 95,
+		"def": 75,
+		"spd": 45,
+	},
+	"SSS": {
+		"hp":  600,
+		"atk": 130,
+		"def": 100,
+		"spd": 60,
+	},
 }
 
 // calculateBaseStats generates base stats based on rarity
 func (s *GachaService) calculateBaseStats(rarity string) map[string]int {
-	baseValues := map[string]map[string]int{
-		"C": {
-			"hp":  100,
-			"atk": 20,
-			"def": 15,
-			"spd": 10,
-		},
-		"B": {
-			"hp":  150,
-			"atk": 30,
-			"def": 25,
-			"spd": 15,
-		},
-		"A": {
-			"hp":  200,
-			"atk": 45,
-			"def": 35,
-			"spd": 20,
-		},
-		"S": {
-			"hp":  300,
-			"atk": 65,
-			"def": 50,
-			"spd": 30,
-		},
-		"SS": {
-			"hp":  450,
-			"atk": 95,
-			"def": 75,
-			"spd": 45,
-		},
-		"SSS": {
-			"hp":  600,
-			"atk": 130,
-			"def": 100,
-			"spd": 60,
-		},
-	}
-
-	base := baseValues[rarity]
+	base := rarityBaseStats[rarity]
 
 	// Add random variation (±10%)
 	variation := func(val int) int {
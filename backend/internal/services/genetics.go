@@ -0,0 +1,356 @@
+package services
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"time"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+)
+
+// The trait pools minting rolls from and mutations draw from
+var (
+	characterElements = []string{"Fire", "Water", "Earth", "Air", "Light", "Dark", "Electric", "Ice"}
+	characterTypes    = []string{"BEAST", "DRAGON", "BIRD", "INSECT", "AQUATIC", "MINERAL", "SPIRIT", "AVIAN", "PLANT", "MACHINE"}
+	characterClasses  = []string{"Warrior", "Mage", "Archer", "Tank", "Support", "Rogue", "Paladin", "Berserker"}
+	characterPassives = []string{"Berserker", "Mana Surge", "Fortify", "Precision", "Regeneration"}
+)
+
+// rarityOrder lists rarities from lowest to highest
+var rarityOrder = []string{"C", "B", "A", "S", "SS", "SSS"}
+
+// Stat potentials are percentages of the rarity's base stats (see rarityBaseStats)
+const (
+	minPotential = 50
+	maxPotential = 150
+)
+
+// geneStream separates genetics draws from anything else seeded with the same number
+const geneStream = 0x67656e6f6d65
+
+// Gene is one locus: the dominant allele is expressed, the recessive one is hidden but
+// can be passed on
+type Gene[T string | int] struct {
+	Dominant  T `json:"d"`
+	Recessive T `json:"r"`
+}
+
+// TraitGene carries a trait (element, type, class, passive)
+type TraitGene = Gene[string]
+
+// StatGene carries a stat potential
+type StatGene = Gene[int]
+
+// Genome is everything a character passes on to its offspring
+type Genome struct {
+	Element TraitGene `json:"element"`
+	Type    TraitGene `json:"type"`
+	Class   TraitGene `json:"class"`
+	Passive TraitGene `json:"passive"`
+	HP      StatGene  `json:"hp"`
+	Attack  StatGene  `json:"atk"`
+	Defense StatGene  `json:"def"`
+	Speed   StatGene  `json:"spd"`
+}
+
+// locus names a gene of a genome; trait loci also carry the pool mutations draw from
+type locus[T string | int] struct {
+	Name string
+	Gene *Gene[T]
+	Pool []string
+}
+
+// traitLoci lists the trait loci of g in a fixed order
+func (g *Genome) traitLoci() []locus[string] {
+	return []locus[string]{
+		{"element", &g.Element, characterElements},
+		{"type", &g.Type, characterTypes},
+		{"class", &g.Class, characterClasses},
+		{"passive", &g.Passive, characterPassives},
+	}
+}
+
+// statLoci lists the stat loci of g, named by their PredeterminedStats key
+func (g *Genome) statLoci() []locus[int] {
+	return []locus[int]{
+		{Name: "hp", Gene: &g.HP},
+		{Name: "atk", Gene: &g.Attack},
+		{Name: "def", Gene: &g.Defense},
+		{Name: "spd", Gene: &g.Speed},
+	}
+}
+
+// GeneticsConfig holds the breeding tunables
+type GeneticsConfig struct {
+	MutationChance float64 // Per locus
+	MutationSwing  int     // Largest change a stat mutation makes to a potential
+}
+
+func geneticsConfig(config Settings) GeneticsConfig {
+	return GeneticsConfig{
+		MutationChance: config.GetFloat("breeding_mutation_chance", 0.05),
+		MutationSwing:  config.GetInt("breeding_mutation_stat_swing", 10),
+	}
+}
+
+// Offspring is the outcome of one breeding
+type Offspring struct {
+	Genome    Genome
+	Rarity    string
+	Stats     map[string]int // Base stats, in PredeterminedStats form
+	Mutations []string       // Loci that mutated
+}
+
+// newGeneRand returns the generator a breeding inherits with
+func newGeneRand(seed int64) *rand.Rand {
+	return rand.New(rand.NewPCG(uint64(seed), geneStream))
+}
+
+// newGeneSeed draws a seed for a breeding that players can't predict
+func newGeneSeed() int64 {
+	var b [8]byte
+	if _, err := crand.Read(b[:]); err != nil {
+		return time.Now().UnixNano()
+	}
+	return int64(binary.LittleEndian.Uint64(b[:]))
+}
+
+// genomeOf returns a character's genome. Characters minted before genetics don't have
+// one stored; theirs is derived from what they express, with recessives seeded by
+// their ID so it is the same every time.
+func genomeOf(c *models.Character) Genome {
+	var g Genome
+	if c.Genome != "" && json.Unmarshal([]byte(c.Genome), &g) == nil {
+		return g
+	}
+	return founderGenome(c, newGeneRand(int64(c.ID)))
+}
+
+// founderGenome builds the genome of a character without parents: the dominant genes
+// are what it expresses and the recessive ones are drawn from rng
+func founderGenome(c *models.Character, rng *rand.Rand) Genome {
+	passive := c.PassiveAbility
+	if passive == "" {
+		passive = GetPassiveAbility(c.Class).Name
+	}
+	g := Genome{
+		Element: TraitGene{Dominant: c.Element},
+		Type:    TraitGene{Dominant: c.CharacterType},
+		Class:   TraitGene{Dominant: c.Class},
+		Passive: TraitGene{Dominant: passive},
+	}
+	for _, l := range g.traitLoci() {
+		l.Gene.Recessive = l.Pool[rng.IntN(len(l.Pool))]
+	}
+
+	base := rarityBaseStats[rarityLabel(c.Rarity)]
+	values := map[string]int{"hp": c.BaseHP, "atk": c.BaseAttack, "def": c.BaseDefense, "spd": c.BaseSpeed}
+	for _, l := range g.statLoci() {
+		potential := minPotential
+		if base[l.Name] > 0 {
+			potential = clampPotential(values[l.Name] * 100 / base[l.Name])
+		}
+		l.Gene.Dominant = potential
+		l.Gene.Recessive = clampPotential(potential + rng.IntN(21) - 10)
+	}
+	return g
+}
+
+// encodeGenome serializes a genome for the genome columns
+func encodeGenome(g Genome) string {
+	raw, _ := json.Marshal(g)
+	return string(raw)
+}
+
+// Breed inherits an offspring from two parents' genomes and rarities. Each parent passes
+// on its dominant or its recessive allele with equal chance; a dominant allele wins over
+// a recessive one and ties are a coin flip. Every locus may then mutate. The same seed
+// always gives the same offspring.
+func Breed(p1, p2 Genome, rarity1, rarity2 string, seed int64, cfg GeneticsConfig) Offspring {
+	rng := newGeneRand(seed)
+	var child Genome
+	var mutations []string
+
+	childLoci := child.traitLoci()
+	for i, l := range p1.traitLoci() {
+		*childLoci[i].Gene = inheritGene(rng, *l.Gene, *p2.traitLoci()[i].Gene)
+		if rng.Float64() < cfg.MutationChance {
+			mutated := l.Pool[rng.IntN(len(l.Pool))]
+			if mutated != childLoci[i].Gene.Dominant {
+				childLoci[i].Gene.Dominant = mutated
+				mutations = append(mutations, l.Name)
+			}
+		}
+	}
+
+	childStats := child.statLoci()
+	for i, l := range p1.statLoci() {
+		*childStats[i].Gene = inheritGene(rng, *l.Gene, *p2.statLoci()[i].Gene)
+		if rng.Float64() < cfg.MutationChance && cfg.MutationSwing > 0 {
+			swing := rng.IntN(2*cfg.MutationSwing+1) - cfg.MutationSwing
+			if mutated := clampPotential(childStats[i].Gene.Dominant + swing); mutated != childStats[i].Gene.Dominant {
+				childStats[i].Gene.Dominant = mutated
+				mutations = append(mutations, l.Name)
+			}
+		}
+	}
+
+	rarity := drawRarity(rng, rarityOdds(rarity1, rarity2))
+	return Offspring{Genome: child, Rarity: rarity, Stats: expressedStats(child, rarity), Mutations: mutations}
+}
+
+// inheritGene draws one allele from each parent and decides which is expressed
+func inheritGene[T string | int](rng *rand.Rand, a, b Gene[T]) Gene[T] {
+	aDom, bDom := rng.IntN(2) == 0, rng.IntN(2) == 0
+	fromA, fromB := a.Recessive, b.Recessive
+	if aDom {
+		fromA = a.Dominant
+	}
+	if bDom {
+		fromB = b.Dominant
+	}
+	if aDom == bDom && rng.IntN(2) == 0 || !aDom && bDom {
+		return Gene[T]{Dominant: fromB, Recessive: fromA}
+	}
+	return Gene[T]{Dominant: fromA, Recessive: fromB}
+}
+
+// geneOdds is the chance of each trait being expressed by the offspring. A parent's
+// dominant allele is expressed 3/8 of the time (dominant over the other's recessive,
+// or winning a tie between dominants) and its recessive one 1/8 (winning a tie
+// between recessives); a mutation replaces it with any value of the pool.
+func geneOdds(a, b TraitGene, pool []string, mutation float64) map[string]float64 {
+	odds := map[string]float64{}
+	odds[a.Dominant] += 3.0 / 8 * (1 - mutation)
+	odds[b.Dominant] += 3.0 / 8 * (1 - mutation)
+	odds[a.Recessive] += 1.0 / 8 * (1 - mutation)
+	odds[b.Recessive] += 1.0 / 8 * (1 - mutation)
+	for _, v := range pool {
+		odds[v] += mutation / float64(len(pool))
+	}
+	return odds
+}
+
+// rarityOdds is the chance of each offspring rarity: the parents' average rarity 70% of
+// the time, one above it 20% and one below it 10%. Rarities past either end fold into
+// the one the roll would otherwise have fallen to.
+func rarityOdds(rarity1, rarity2 string) map[string]float64 {
+	avg := (rarityIndex(rarity1) + rarityIndex(rarity2)) / 2
+	odds := map[string]float64{rarityOrder[avg]: 0.7}
+	up, down := avg+1, avg-1
+	switch {
+	case up >= len(rarityOrder):
+		odds[rarityOrder[down]] += 0.3
+	case down < 0:
+		odds[rarityOrder[up]] += 0.2
+		odds[rarityOrder[avg]] += 0.1
+	default:
+		odds[rarityOrder[up]] += 0.2
+		odds[rarityOrder[down]] += 0.1
+	}
+	return odds
+}
+
+// drawRarity draws from rarity odds, walking rarities in order so a seed always lands
+// on the same one
+func drawRarity(rng *rand.Rand, odds map[string]float64) string {
+	roll := rng.Float64()
+	last := ""
+	for _, r := range rarityOrder {
+		p, ok := odds[r]
+		if !ok {
+			continue
+		}
+		if roll < p {
+			return r
+		}
+		roll -= p
+		last = r
+	}
+	return last
+}
+
+func rarityIndex(rarity string) int {
+	for i, r := range rarityOrder {
+		if r == rarity {
+			return i
+		}
+	}
+	return 0
+}
+
+// expressedStats turns the dominant stat potentials into base stats for a rarity
+func expressedStats(g Genome, rarity string) map[string]int {
+	base := rarityBaseStats[rarityLabel(rarity)]
+	stats := map[string]int{}
+	for _, l := range g.statLoci() {
+		stats[l.Name] = max(base[l.Name]*l.Gene.Dominant/100, 1)
+	}
+	return stats
+}
+
+func clampPotential(p int) int {
+	return min(max(p, minPotential), maxPotential)
+}
+
+// StatRange is the range a base stat of the offspring can fall in
+type StatRange struct {
+	MinPotential int `json:"min_potential"`
+	MaxPotential int `json:"max_potential"`
+	Min          int `json:"min"`
+	Max          int `json:"max"`
+}
+
+// OffspringOdds is what a breeding can produce
+type OffspringOdds struct {
+	Rarity         map[string]float64   `json:"rarity"`
+	Element        map[string]float64   `json:"element"`
+	Type           map[string]float64   `json:"type"`
+	Class          map[string]float64   `json:"class"`
+	Passive        map[string]float64   `json:"passive"`
+	Stats          map[string]StatRange `json:"stats"`
+	MutationChance float64              `json:"mutation_chance"`
+}
+
+// BreedingOdds computes the exact trait odds and the stat ranges of what Breed can
+// return for two parents
+func BreedingOdds(p1, p2 Genome, rarity1, rarity2 string, cfg GeneticsConfig) OffspringOdds {
+	out := OffspringOdds{Rarity: rarityOdds(rarity1, rarity2), Stats: map[string]StatRange{}, MutationChance: cfg.MutationChance}
+	traits := []*map[string]float64{&out.Element, &out.Type, &out.Class, &out.Passive}
+	for i, l := range p1.traitLoci() {
+		*traits[i] = geneOdds(*l.Gene, *p2.traitLoci()[i].Gene, l.Pool, cfg.MutationChance)
+	}
+
+	// The lowest and highest rarity the offspring can have bound the base stats
+	var rarities []string
+	for r := range out.Rarity {
+		rarities = append(rarities, r)
+	}
+	sort.Slice(rarities, func(i, j int) bool { return rarityIndex(rarities[i]) < rarityIndex(rarities[j]) })
+	low, high := rarityBaseStats[rarities[0]], rarityBaseStats[rarities[len(rarities)-1]]
+
+	for i, l := range p1.statLoci() {
+		o := *p2.statLoci()[i].Gene
+		lo := min(l.Gene.Dominant, l.Gene.Recessive, o.Dominant, o.Recessive)
+		hi := max(l.Gene.Dominant, l.Gene.Recessive, o.Dominant, o.Recessive)
+		if cfg.MutationChance > 0 {
+			lo, hi = clampPotential(lo-cfg.MutationSwing), clampPotential(hi+cfg.MutationSwing)
+		}
+		out.Stats[l.Name] = StatRange{
+			MinPotential: lo,
+			MaxPotential: hi,
+			Min:          max(low[l.Name]*lo/100, 1),
+			Max:          max(high[l.Name]*hi/100, 1),
+		}
+	}
+	for _, m := range []map[string]float64{out.Rarity, out.Element, out.Type, out.Class, out.Passive} {
+		for k, v := range m {
+			m[k] = math.Round(v*10000) / 10000
+		}
+	}
+	return out
+}
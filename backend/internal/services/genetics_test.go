@@ -0,0 +1,117 @@
+package services
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/internal/repository"
+)
+
+func testGenomes() (Genome, Genome) {
+	p1 := Genome{
+		Element: TraitGene{Dominant: "Fire", Recessive: "Ice"},
+		Type:    TraitGene{Dominant: "DRAGON", Recessive: "BEAST"},
+		Class:   TraitGene{Dominant: "Mage", Recessive: "Rogue"},
+		Passive: TraitGene{Dominant: "Mana Surge", Recessive: "Fortify"},
+		HP:      StatGene{Dominant: 120, Recessive: 80},
+		Attack:  StatGene{Dominant: 100, Recessive: 100},
+		Defense: StatGene{Dominant: 90, Recessive: 110},
+		Speed:   StatGene{Dominant: 100, Recessive: 60},
+	}
+	p2 := p1
+	p2.Class = TraitGene{Dominant: "Tank", Recessive: "Paladin"}
+	p2.HP = StatGene{Dominant: 70, Recessive: 130}
+	return p1, p2
+}
+
+func TestBreedFollowsItsOdds(t *testing.T) {
+	p1, p2 := testGenomes()
+	cfg := GeneticsConfig{MutationChance: 0.05, MutationSwing: 10}
+
+	if a, b := Breed(p1, p2, "A", "A", 42, cfg), Breed(p1, p2, "A", "A", 42, cfg); !reflect.DeepEqual(a, b) {
+		t.Fatalf("same seed bred %+v and %+v", a, b)
+	}
+
+	odds := BreedingOdds(p1, p2, "A", "A", cfg)
+	const n = 20000
+	seen := map[string]float64{}
+	for seed := int64(0); seed < n; seed++ {
+		child := Breed(p1, p2, "A", "A", seed, cfg)
+		seen[child.Genome.Class.Dominant]++
+		hp := child.Stats["hp"]
+		if r := odds.Stats["hp"]; hp < r.Min || hp > r.Max {
+			t.Fatalf("seed %d: hp %d outside preview range %d-%d", seed, hp, r.Min, r.Max)
+		}
+	}
+	for class, p := range odds.Class {
+		if got := seen[class] / n; math.Abs(got-p) > 0.015 {
+			t.Fatalf("class %s expressed %.3f of the time, odds say %.3f", class, got, p)
+		}
+	}
+
+	// 3/8 dominant, 1/8 recessive, minus the mutated share, plus a share of the mutations
+	if want := 3.0/8*0.95 + 0.05/8; math.Abs(odds.Class["Mage"]-want) > 0.0001 {
+		t.Fatalf("Mage odds = %.4f, want %.4f", odds.Class["Mage"], want)
+	}
+	if r := odds.Stats["hp"]; r.MinPotential != 60 || r.MaxPotential != 140 {
+		t.Fatalf("hp potentials = %d-%d, want 60-140", r.MinPotential, r.MaxPotential)
+	}
+}
+
+func TestRarityOddsFoldAtTheEnds(t *testing.T) {
+	if odds := rarityOdds("C", "B"); math.Abs(odds["C"]-0.8) > 1e-9 || math.Abs(odds["B"]-0.2) > 1e-9 {
+		t.Fatalf("C x B odds = %v, want C 0.8, B 0.2", odds)
+	}
+	if odds := rarityOdds("SSS", "SSS"); math.Abs(odds["SSS"]-0.7) > 1e-9 || math.Abs(odds["SS"]-0.3) > 1e-9 {
+		t.Fatalf("SSS x SSS odds = %v, want SSS 0.7, SS 0.3", odds)
+	}
+}
+
+func TestSiblingBreedingCostsKinship(t *testing.T) {
+	st := repository.NewMemoryStore()
+	owner := newTestUser(t, st, 1000)
+	mother := newTestCharacter(t, st, owner.ID, 100, 20)
+	father := newTestCharacter(t, st, owner.ID, 100, 20)
+	father.BreedCount = 2
+	if err := st.Characters().Save(father); err != nil {
+		t.Fatal(err)
+	}
+
+	var siblings []*models.Character
+	for range 2 {
+		child := newTestCharacter(t, st, owner.ID, 100, 20)
+		if err := st.Eggs().Create(&models.Egg{UserID: owner.ID, Parent1ID: &mother.ID, Parent2ID: &father.ID, CharacterID: &child.ID}); err != nil {
+			t.Fatal(err)
+		}
+		siblings = append(siblings, child)
+	}
+	svc := NewBreedingService(st, nil, nil, testSettings{})
+
+	lineage, err := svc.GetLineage(siblings[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(lineage.Ancestors, []uint{mother.ID, father.ID}) || len(lineage.Tree.Parents) != 2 {
+		t.Fatalf("lineage = %+v, want both parents", lineage)
+	}
+
+	preview, err := svc.PreviewBreeding(owner.ID, siblings[0].ID, siblings[1].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Two shared parents at 0.5 each
+	if preview.FamilyCostModifier != 2 || preview.Cost != 1000 {
+		t.Fatalf("modifier %.2f, cost %d; want 2, 1000", preview.FamilyCostModifier, preview.Cost)
+	}
+
+	// Breeding with a parent shares that parent; the father also brings his two breedings
+	preview, err = svc.PreviewBreeding(owner.ID, siblings[0].ID, father.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if preview.FamilyCostModifier != 2 || !reflect.DeepEqual(preview.SharedAncestors, []uint{father.ID}) {
+		t.Fatalf("modifier %.2f, shared %v; want 2, [%d]", preview.FamilyCostModifier, preview.SharedAncestors, father.ID)
+	}
+}
@@ -0,0 +1,288 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"time"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/db"
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/internal/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GuildRaidAttackResult is returned after a member hits the guild boss
+type GuildRaidAttackResult struct {
+	Damage      int64             `json:"damage"`
+	RemainingHP int64             `json:"remaining_hp"`
+	Defeated    bool              `json:"defeated"`
+	NextAttack  time.Time         `json:"next_attack_at"`
+	Raid        *models.GuildRaid `json:"raid"`
+}
+
+// StartGuildRaid opens a multi-day raid against a boss. Officers and the leader can start one; only one can be active.
+func (s *GuildService) StartGuildRaid(actorID, bossID uint) (*models.GuildRaid, error) {
+	actor, err := s.requireRole(actorID, models.GuildRoleOfficer)
+	if err != nil {
+		return nil, err
+	}
+
+	var boss models.RaidBoss
+	if err := db.DB.First(&boss, bossID).Error; err != nil {
+		return nil, errors.New("raid boss not found")
+	}
+	if boss.TotalHP <= 0 {
+		return nil, errors.New("raid boss has no HP configured")
+	}
+
+	var active int64
+	db.DB.Model(&models.GuildRaid{}).Where("guild_id = ? AND status = ?", actor.GuildID, "ACTIVE").Count(&active)
+	if active > 0 {
+		return nil, errors.New("guild already has an active raid")
+	}
+
+	duration := time.Duration(s.config.GetInt("guild_raid_duration_hours", 72)) * time.Hour
+	raid := models.GuildRaid{
+		GuildID:     actor.GuildID,
+		RaidBossID:  boss.ID,
+		StartedBy:   actorID,
+		TotalHP:     boss.TotalHP,
+		RemainingHP: boss.TotalHP,
+		RewardPool:  int64(s.config.GetInt("guild_raid_reward_pool", 5000)),
+		Status:      "ACTIVE",
+		EndsAt:      time.Now().Add(duration),
+	}
+
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&raid).Error; err != nil {
+			return err
+		}
+		return s.postSystemMessage(tx, actor.GuildID, actorID, fmt.Sprintf("started a guild raid against %s", boss.Name))
+	})
+	if err != nil {
+		return nil, err
+	}
+	raid.RaidBoss = boss
+	return &raid, nil
+}
+
+// GetActiveGuildRaid returns the caller's guild raid with contributions sorted by damage
+func (s *GuildService) GetActiveGuildRaid(userID uint) (*models.GuildRaid, error) {
+	member, err := s.getMembership(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var raid models.GuildRaid
+	if err := db.DB.Preload("RaidBoss").
+		Preload("Contributions", func(tx *gorm.DB) *gorm.DB { return tx.Order("damage DESC") }).
+		Where("guild_id = ?", member.GuildID).
+		Order("created_at DESC").
+		First(&raid).Error; err != nil {
+		return nil, errors.New("no guild raid found")
+	}
+	return &raid, nil
+}
+
+// AttackGuildRaid deals one hit with the member's active team. Each member can attack once per cooldown window.
+func (s *GuildService) AttackGuildRaid(userID uint) (*GuildRaidAttackResult, error) {
+	member, err := s.getMembership(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	team, err := s.battleService.GetTeamSnapshot(userID)
+	if err != nil || len(team) == 0 {
+		return nil, errors.New("an active team is required to attack")
+	}
+
+	cooldown := time.Duration(s.config.GetInt("guild_raid_attack_cooldown_hours", 8)) * time.Hour
+	result := &GuildRaidAttackResult{}
+
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		var raid models.GuildRaid
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("RaidBoss").
+			Where("guild_id = ? AND status = ?", member.GuildID, "ACTIVE").
+			First(&raid).Error; err != nil {
+			return errors.New("no active guild raid")
+		}
+
+		now := time.Now()
+		if now.After(raid.EndsAt) {
+			return errors.New("guild raid has ended")
+		}
+
+		var contrib models.GuildRaidContribution
+		if err := tx.Where("guild_raid_id = ? AND user_id = ?", raid.ID, userID).First(&contrib).Error; err != nil {
+			contrib = models.GuildRaidContribution{GuildRaidID: raid.ID, UserID: userID}
+		}
+		if contrib.LastAttackAt != nil && now.Before(contrib.LastAttackAt.Add(cooldown)) {
+			return fmt.Errorf("next attack available at %s", contrib.LastAttackAt.Add(cooldown).Format(time.RFC3339))
+		}
+
+		damage := guildRaidDamage(team, raid.RaidBoss.BaseDefense)
+		if damage > raid.RemainingHP {
+			damage = raid.RemainingHP
+		}
+
+		raid.RemainingHP -= damage
+		contrib.Damage += damage
+		contrib.Attacks++
+		contrib.LastAttackAt = &now
+		if err := tx.Save(&contrib).Error; err != nil {
+			return err
+		}
+		if err := wearGuildRaidTeam(repository.NewGormStore(tx), s.config, team); err != nil {
+			return err
+		}
+
+		if raid.RemainingHP <= 0 {
+			raid.Status = "DEFEATED"
+			if err := s.settleGuildRaid(tx, &raid); err != nil {
+				return err
+			}
+			if err := s.postSystemMessage(tx, raid.GuildID, userID, fmt.Sprintf("landed the final blow on %s", raid.RaidBoss.Name)); err != nil {
+				return err
+			}
+		} else if err := tx.Save(&raid).Error; err != nil {
+			return err
+		}
+
+		result.Damage = damage
+		result.RemainingHP = raid.RemainingHP
+		result.Defeated = raid.Status == "DEFEATED"
+		result.NextAttack = now.Add(cooldown)
+		result.Raid = &raid
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ProcessExpiredGuildRaids settles raids whose time ran out. Expired raids pay out the share of the pool
+// matching the fraction of HP removed.
+func (s *GuildService) ProcessExpiredGuildRaids() error {
+	var expired []models.GuildRaid
+	if err := db.DB.Where("status = ? AND ends_at <= ?", "ACTIVE", time.Now()).Find(&expired).Error; err != nil {
+		return err
+	}
+
+	for _, r := range expired {
+		err := db.DB.Transaction(func(tx *gorm.DB) error {
+			var raid models.GuildRaid
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&raid, r.ID).Error; err != nil {
+				return err
+			}
+			if raid.Status != "ACTIVE" {
+				return nil
+			}
+			raid.Status = "EXPIRED"
+			return s.settleGuildRaid(tx, &raid)
+		})
+		if err != nil {
+			slog.Error("failed to settle guild raid", "guild_raid_id", r.ID, "error", err)
+		}
+	}
+	return nil
+}
+
+// StartScheduler settles expired guild raids on a fixed interval in the background
+func (s *GuildService) StartScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := s.ProcessExpiredGuildRaids(); err != nil {
+				slog.Error("guild raid scheduler failed", "error", err)
+			}
+		}
+	}()
+}
+
+// settleGuildRaid splits the reward pool by damage contribution and pays it from the REWARD account
+func (s *GuildService) settleGuildRaid(tx *gorm.DB, raid *models.GuildRaid) error {
+	now := time.Now()
+	raid.SettledAt = &now
+
+	var contribs []models.GuildRaidContribution
+	if err := tx.Where("guild_raid_id = ? AND damage > 0", raid.ID).Find(&contribs).Error; err != nil {
+		return err
+	}
+
+	var totalDamage int64
+	for _, c := range contribs {
+		totalDamage += c.Damage
+	}
+
+	pool := raid.RewardPool
+	if raid.Status != "DEFEATED" && raid.TotalHP > 0 {
+		pool = raid.RewardPool * totalDamage / raid.TotalHP
+	}
+
+	if pool > 0 && totalDamage > 0 {
+		rewardAcc, err := s.ledger.GetOrCreateAccount(nil, models.AccountTypeReward, "GTK")
+		if err != nil {
+			return err
+		}
+
+		var paid int64
+		var entries []models.LedgerEntry
+		for i := range contribs {
+			share := pool * contribs[i].Damage / totalDamage
+			if share <= 0 {
+				continue
+			}
+			acc, err := s.ledger.GetOrCreateAccount(&contribs[i].UserID, models.AccountTypeWallet, "GTK")
+			if err != nil {
+				return err
+			}
+			entries = append(entries, models.LedgerEntry{AccountID: acc.ID, Amount: share, Type: "CREDIT"})
+			contribs[i].Reward = share
+			paid += share
+			if err := tx.Model(&contribs[i]).Update("reward", share).Error; err != nil {
+				return err
+			}
+		}
+
+		if paid > 0 {
+			entries = append(entries, models.LedgerEntry{AccountID: rewardAcc.ID, Amount: -paid, Type: "DEBIT"})
+			if err := s.ledger.CreateTransactionWithTx(tx, models.TxTypeRaidReward, fmt.Sprintf("guild_raid_%d", raid.ID),
+				"Guild Raid Rewards", entries); err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Save(raid).Error
+}
+
+// guildRaidDamage turns a team snapshot into one hit against the boss (±10% variance)
+func guildRaidDamage(team []models.BattleParticipant, bossDefense int) int64 {
+	var total int64
+	for _, p := range team {
+		hit := int64(p.Attack*3 + p.Speed - bossDefense)
+		if hit < 1 {
+			hit = 1
+		}
+		total += hit
+	}
+	variance := 0.9 + rand.Float64()*0.2
+	return int64(float64(total) * variance)
+}
+
+// wearGuildRaidTeam applies raid wear to the characters that attacked. An attack is one
+// exchange the boss does not answer, so it wears like a single-turn raid win.
+func wearGuildRaidTeam(tx repository.Store, config Settings, team []models.BattleParticipant) error {
+	var ids []uint
+	for _, p := range team {
+		if p.CharacterID != 0 {
+			ids = append(ids, p.CharacterID)
+		}
+	}
+	return applyBattleWear(tx, config, ids, "raid", true, 1)
+}
@@ -0,0 +1,442 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/db"
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"gorm.io/gorm"
+)
+
+// GuildService handles guild membership, invites, chat, treasury and guild raids
+type GuildService struct {
+	ledger        *LedgerService
+	battleService *BattleService
+	config        *ConfigService
+}
+
+func NewGuildService(ledger *LedgerService, battles *BattleService) *GuildService {
+	return &GuildService{
+		ledger:        ledger,
+		battleService: battles,
+		config:        GetConfigService(),
+	}
+}
+
+// GuildLeaderboardEntry is one row of a guild-level leaderboard
+type GuildLeaderboardEntry struct {
+	GuildID     uint    `json:"guild_id"`
+	Name        string  `json:"name"`
+	Tag         string  `json:"tag"`
+	MemberCount int     `json:"member_count"`
+	Score       float64 `json:"score"`
+}
+
+const guildInviteTTL = 72 * time.Hour
+
+// CreateGuild creates a guild with the caller as leader and opens its treasury account
+func (s *GuildService) CreateGuild(userID uint, name, tag, description string) (*models.Guild, error) {
+	name = strings.TrimSpace(name)
+	tag = strings.ToUpper(strings.TrimSpace(tag))
+	if len(name) < 3 || len(name) > 50 {
+		return nil, errors.New("guild name must be 3-50 characters")
+	}
+	if len(tag) < 2 || len(tag) > 5 {
+		return nil, errors.New("guild tag must be 2-5 characters")
+	}
+
+	if _, err := s.getMembership(userID); err == nil {
+		return nil, errors.New("you are already in a guild")
+	}
+
+	var guild models.Guild
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		tx.Model(&models.Guild{}).Where("LOWER(name) = LOWER(?) OR tag = ?", name, tag).Count(&count)
+		if count > 0 {
+			return errors.New("guild name or tag already taken")
+		}
+
+		guild = models.Guild{
+			Name:                name,
+			Tag:                 tag,
+			Description:         description,
+			LeaderID:            userID,
+			MaxMembers:          s.config.GetInt("guild_max_members", 30),
+			WithdrawalApprovals: 2,
+		}
+		if err := tx.Create(&guild).Error; err != nil {
+			return err
+		}
+
+		leader := models.GuildMember{
+			GuildID:  guild.ID,
+			UserID:   userID,
+			Role:     models.GuildRoleLeader,
+			JoinedAt: time.Now(),
+		}
+		return tx.Create(&leader).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.ledger.GetOrCreateGuildAccount(guild.ID, "GTK"); err != nil {
+		return nil, err
+	}
+
+	return &guild, nil
+}
+
+// GetGuild returns a guild with its members and treasury balance
+func (s *GuildService) GetGuild(guildID uint) (*models.Guild, int64, error) {
+	var guild models.Guild
+	if err := db.DB.Preload("Members", func(tx *gorm.DB) *gorm.DB {
+		return tx.Order("joined_at ASC")
+	}).Preload("Members.User").First(&guild, guildID).Error; err != nil {
+		return nil, 0, errors.New("guild not found")
+	}
+
+	treasury, err := s.ledger.GetOrCreateGuildAccount(guild.ID, "GTK")
+	if err != nil {
+		return nil, 0, err
+	}
+	return &guild, treasury.Balance, nil
+}
+
+// GetMyGuild returns the guild the user belongs to
+func (s *GuildService) GetMyGuild(userID uint) (*models.Guild, int64, error) {
+	member, err := s.getMembership(userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.GetGuild(member.GuildID)
+}
+
+// InviteMember sends an invite. Officers and the leader can invite.
+func (s *GuildService) InviteMember(inviterID, inviteeID uint) (*models.GuildInvite, error) {
+	inviter, err := s.requireRole(inviterID, models.GuildRoleOfficer)
+	if err != nil {
+		return nil, err
+	}
+	if inviterID == inviteeID {
+		return nil, errors.New("cannot invite yourself")
+	}
+
+	var invitee models.User
+	if err := db.DB.First(&invitee, inviteeID).Error; err != nil {
+		return nil, errors.New("user not found")
+	}
+	if _, err := s.getMembership(inviteeID); err == nil {
+		return nil, errors.New("user is already in a guild")
+	}
+
+	var pending int64
+	db.DB.Model(&models.GuildInvite{}).
+		Where("guild_id = ? AND invitee_id = ? AND status = ? AND expires_at > ?", inviter.GuildID, inviteeID, "PENDING", time.Now()).
+		Count(&pending)
+	if pending > 0 {
+		return nil, errors.New("invite already pending")
+	}
+
+	invite := models.GuildInvite{
+		GuildID:   inviter.GuildID,
+		InviterID: inviterID,
+		InviteeID: inviteeID,
+		Status:    "PENDING",
+		ExpiresAt: time.Now().Add(guildInviteTTL),
+	}
+	if err := db.DB.Create(&invite).Error; err != nil {
+		return nil, err
+	}
+	return &invite, nil
+}
+
+// GetPendingInvites lists invites waiting for the user
+func (s *GuildService) GetPendingInvites(userID uint) ([]models.GuildInvite, error) {
+	var invites []models.GuildInvite
+	if err := db.DB.Preload("Guild").
+		Where("invitee_id = ? AND status = ? AND expires_at > ?", userID, "PENDING", time.Now()).
+		Order("created_at DESC").Find(&invites).Error; err != nil {
+		return nil, err
+	}
+	return invites, nil
+}
+
+// RespondInvite accepts or declines an invite
+func (s *GuildService) RespondInvite(inviteID, userID uint, accept bool) error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		var invite models.GuildInvite
+		if err := tx.Set("gorm:query_option", "FOR UPDATE").First(&invite, inviteID).Error; err != nil {
+			return errors.New("invite not found")
+		}
+		if invite.InviteeID != userID {
+			return errors.New("not your invite")
+		}
+		if invite.Status != "PENDING" {
+			return errors.New("invite already answered")
+		}
+
+		now := time.Now()
+		invite.RespondedAt = &now
+		if now.After(invite.ExpiresAt) {
+			invite.Status = "EXPIRED"
+			tx.Save(&invite)
+			return errors.New("invite expired")
+		}
+
+		if !accept {
+			invite.Status = "DECLINED"
+			return tx.Save(&invite).Error
+		}
+
+		var existing models.GuildMember
+		if err := tx.Where("user_id = ?", userID).First(&existing).Error; err == nil {
+			return errors.New("you are already in a guild")
+		}
+
+		var guild models.Guild
+		if err := tx.First(&guild, invite.GuildID).Error; err != nil {
+			return errors.New("guild no longer exists")
+		}
+		var memberCount int64
+		tx.Model(&models.GuildMember{}).Where("guild_id = ?", guild.ID).Count(&memberCount)
+		if int(memberCount) >= guild.MaxMembers {
+			return errors.New("guild is full")
+		}
+
+		invite.Status = "ACCEPTED"
+		if err := tx.Save(&invite).Error; err != nil {
+			return err
+		}
+
+		member := models.GuildMember{
+			GuildID:  guild.ID,
+			UserID:   userID,
+			Role:     models.GuildRoleMember,
+			JoinedAt: now,
+		}
+		if err := tx.Create(&member).Error; err != nil {
+			return err
+		}
+		return s.postSystemMessage(tx, guild.ID, userID, "joined the guild")
+	})
+}
+
+// LeaveGuild removes the user from their guild. A leader must hand over leadership first,
+// unless they are the last member, in which case the guild is disbanded.
+func (s *GuildService) LeaveGuild(userID uint) error {
+	member, err := s.getMembership(userID)
+	if err != nil {
+		return err
+	}
+
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		if member.Role == models.GuildRoleLeader {
+			var count int64
+			tx.Model(&models.GuildMember{}).Where("guild_id = ?", member.GuildID).Count(&count)
+			if count > 1 {
+				return errors.New("transfer leadership before leaving")
+			}
+
+			treasury, err := s.ledger.GetOrCreateGuildAccount(member.GuildID, "GTK")
+			if err != nil {
+				return err
+			}
+			if treasury.Balance > 0 {
+				return errors.New("withdraw the treasury before disbanding")
+			}
+			if err := tx.Delete(&models.Guild{}, member.GuildID).Error; err != nil {
+				return err
+			}
+		} else if err := s.postSystemMessage(tx, member.GuildID, userID, "left the guild"); err != nil {
+			return err
+		}
+
+		return tx.Delete(member).Error
+	})
+}
+
+// KickMember removes a member. Officers can kick members; only the leader can kick officers.
+func (s *GuildService) KickMember(actorID, targetID uint) error {
+	actor, err := s.requireRole(actorID, models.GuildRoleOfficer)
+	if err != nil {
+		return err
+	}
+	target, err := s.getMembership(targetID)
+	if err != nil || target.GuildID != actor.GuildID {
+		return errors.New("user is not in your guild")
+	}
+	if guildRoleRank(target.Role) >= guildRoleRank(actor.Role) {
+		return errors.New("cannot kick a member of equal or higher rank")
+	}
+
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(target).Error; err != nil {
+			return err
+		}
+		return s.postSystemMessage(tx, actor.GuildID, targetID, "was removed from the guild")
+	})
+}
+
+// SetMemberRole changes a member's role. Promoting someone to LEADER hands over leadership.
+func (s *GuildService) SetMemberRole(actorID, targetID uint, role string) error {
+	if role != models.GuildRoleLeader && role != models.GuildRoleOfficer && role != models.GuildRoleMember {
+		return errors.New("invalid role")
+	}
+	actor, err := s.requireRole(actorID, models.GuildRoleLeader)
+	if err != nil {
+		return err
+	}
+	target, err := s.getMembership(targetID)
+	if err != nil || target.GuildID != actor.GuildID {
+		return errors.New("user is not in your guild")
+	}
+	if actorID == targetID {
+		return errors.New("cannot change your own role")
+	}
+
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		if role == models.GuildRoleLeader {
+			if err := tx.Model(actor).Update("role", models.GuildRoleOfficer).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&models.Guild{}).Where("id = ?", actor.GuildID).Update("leader_id", targetID).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(target).Update("role", role).Error; err != nil {
+			return err
+		}
+		return s.postSystemMessage(tx, actor.GuildID, targetID, "is now "+strings.ToLower(role))
+	})
+}
+
+// PostMessage appends a line to the guild chat log
+func (s *GuildService) PostMessage(userID uint, content string) (*models.GuildChatMessage, error) {
+	member, err := s.getMembership(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil, errors.New("message is empty")
+	}
+	if len(content) > 500 {
+		return nil, errors.New("message too long (max 500 characters)")
+	}
+
+	msg := models.GuildChatMessage{
+		GuildID: member.GuildID,
+		UserID:  userID,
+		Content: content,
+	}
+	if err := db.DB.Create(&msg).Error; err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+// GetMessages returns the chat log, newest first. beforeID pages back through history.
+func (s *GuildService) GetMessages(userID uint, beforeID uint, limit int) ([]models.GuildChatMessage, error) {
+	member, err := s.getMembership(userID)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+
+	query := db.DB.Where("guild_id = ?", member.GuildID)
+	if beforeID > 0 {
+		query = query.Where("id < ?", beforeID)
+	}
+
+	var messages []models.GuildChatMessage
+	if err := query.Order("id DESC").Limit(limit).Find(&messages).Error; err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// GetGuildLeaderboard ranks guilds by category: elo (average member rating), wins (total PvP wins),
+// raid_damage (lifetime guild raid damage) or treasury (GTK balance)
+func (s *GuildService) GetGuildLeaderboard(category string, limit int) ([]GuildLeaderboardEntry, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+
+	var scoreExpr string
+	switch category {
+	case "", "elo":
+		scoreExpr = "COALESCE(AVG(u.elo_rating), 0)"
+	case "wins":
+		scoreExpr = "COALESCE(SUM(u.pvp_wins), 0)"
+	case "raid_damage":
+		scoreExpr = "COALESCE((SELECT SUM(c.damage) FROM guild_raid_contributions c JOIN guild_raids r ON r.id = c.guild_raid_id WHERE r.guild_id = g.id), 0)"
+	case "treasury":
+		scoreExpr = "COALESCE((SELECT la.balance FROM ledger_accounts la WHERE la.guild_id = g.id AND la.type = 'GUILD_TREASURY' AND la.currency = 'GTK' LIMIT 1), 0)"
+	default:
+		return nil, fmt.Errorf("unknown leaderboard category: %s", category)
+	}
+
+	var entries []GuildLeaderboardEntry
+	err := db.DB.Table("guilds g").
+		Select("g.id AS guild_id, g.name, g.tag, COUNT(m.id) AS member_count, " + scoreExpr + " AS score").
+		Joins("JOIN guild_members m ON m.guild_id = g.id").
+		Joins("JOIN users u ON u.id = m.user_id").
+		Where("g.deleted_at IS NULL").
+		Group("g.id, g.name, g.tag").
+		Order("score DESC").
+		Limit(limit).
+		Scan(&entries).Error
+	return entries, err
+}
+
+// --- helpers ---
+
+func (s *GuildService) getMembership(userID uint) (*models.GuildMember, error) {
+	var member models.GuildMember
+	if err := db.DB.Where("user_id = ?", userID).First(&member).Error; err != nil {
+		return nil, errors.New("you are not in a guild")
+	}
+	return &member, nil
+}
+
+// requireRole returns the caller's membership if their role is at least minRole
+func (s *GuildService) requireRole(userID uint, minRole string) (*models.GuildMember, error) {
+	member, err := s.getMembership(userID)
+	if err != nil {
+		return nil, err
+	}
+	if guildRoleRank(member.Role) < guildRoleRank(minRole) {
+		return nil, fmt.Errorf("requires guild role %s", minRole)
+	}
+	return member, nil
+}
+
+func guildRoleRank(role string) int {
+	switch role {
+	case models.GuildRoleLeader:
+		return 3
+	case models.GuildRoleOfficer:
+		return 2
+	case models.GuildRoleMember:
+		return 1
+	}
+	return 0
+}
+
+func (s *GuildService) postSystemMessage(tx *gorm.DB, guildID, userID uint, text string) error {
+	msg := models.GuildChatMessage{
+		GuildID:  guildID,
+		UserID:   userID,
+		Content:  text,
+		IsSystem: true,
+	}
+	return tx.Create(&msg).Error
+}
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/db"
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DepositToTreasury moves GTK from the member's wallet into the guild treasury
+func (s *GuildService) DepositToTreasury(userID uint, amount int64) error {
+	if amount <= 0 {
+		return errors.New("amount must be positive")
+	}
+	member, err := s.getMembership(userID)
+	if err != nil {
+		return err
+	}
+
+	userAcc, err := s.ledger.GetOrCreateAccount(&userID, models.AccountTypeWallet, "GTK")
+	if err != nil {
+		return err
+	}
+	treasury, err := s.ledger.GetOrCreateGuildAccount(member.GuildID, "GTK")
+	if err != nil {
+		return err
+	}
+
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		entries := []models.LedgerEntry{
+			{AccountID: userAcc.ID, Amount: -amount, Type: "DEBIT"},
+			{AccountID: treasury.ID, Amount: amount, Type: "CREDIT"},
+		}
+		if err := s.ledger.CreateTransactionWithTx(tx, models.TxTypeGuildDeposit, fmt.Sprintf("guild_%d_deposit_%d", member.GuildID, userID),
+			"Guild Treasury Deposit", entries); err != nil {
+			return err
+		}
+		if err := tx.Model(member).Update("contribution", gorm.Expr("contribution + ?", amount)).Error; err != nil {
+			return err
+		}
+		return s.postSystemMessage(tx, member.GuildID, userID, fmt.Sprintf("deposited %d GTK to the treasury", amount))
+	})
+}
+
+// RequestWithdrawal opens a treasury payout to a guild member. Officers and the leader can request.
+// Leader requests up to the guild's auto limit execute immediately; everything else waits for approvals.
+func (s *GuildService) RequestWithdrawal(requesterID, recipientID uint, amount int64, reason string) (*models.GuildWithdrawalRequest, error) {
+	if amount <= 0 {
+		return nil, errors.New("amount must be positive")
+	}
+	requester, err := s.requireRole(requesterID, models.GuildRoleOfficer)
+	if err != nil {
+		return nil, err
+	}
+	recipient, err := s.getMembership(recipientID)
+	if err != nil || recipient.GuildID != requester.GuildID {
+		return nil, errors.New("recipient must be a member of your guild")
+	}
+
+	var guild models.Guild
+	if err := db.DB.First(&guild, requester.GuildID).Error; err != nil {
+		return nil, errors.New("guild not found")
+	}
+
+	req := models.GuildWithdrawalRequest{
+		GuildID:     guild.ID,
+		RequesterID: requesterID,
+		RecipientID: recipientID,
+		Amount:      amount,
+		Reason:      reason,
+		Status:      "PENDING",
+		Approvals:   "[]",
+	}
+
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&req).Error; err != nil {
+			return err
+		}
+		// Above the leader's auto limit a request always waits for at least one other signer
+		if requester.Role == models.GuildRoleLeader && amount <= guild.WithdrawalAutoLimit {
+			return s.executeWithdrawal(tx, &req)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+// ApproveWithdrawal records an officer/leader approval and executes the payout once the policy is met
+func (s *GuildService) ApproveWithdrawal(requestID, approverID uint) (*models.GuildWithdrawalRequest, error) {
+	approver, err := s.requireRole(approverID, models.GuildRoleOfficer)
+	if err != nil {
+		return nil, err
+	}
+
+	var req models.GuildWithdrawalRequest
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&req, requestID).Error; err != nil {
+			return errors.New("withdrawal request not found")
+		}
+		if req.GuildID != approver.GuildID {
+			return errors.New("request belongs to another guild")
+		}
+		if req.Status != "PENDING" {
+			return errors.New("request is no longer pending")
+		}
+		if req.RequesterID == approverID {
+			return errors.New("requester cannot approve their own withdrawal")
+		}
+
+		var approvals []uint
+		json.Unmarshal([]byte(req.Approvals), &approvals)
+		for _, id := range approvals {
+			if id == approverID {
+				return errors.New("already approved")
+			}
+		}
+		approvals = append(approvals, approverID)
+		raw, _ := json.Marshal(approvals)
+		req.Approvals = string(raw)
+
+		var guild models.Guild
+		if err := tx.First(&guild, req.GuildID).Error; err != nil {
+			return err
+		}
+		required, err := s.requiredApprovals(tx, &guild, req.RequesterID)
+		if err != nil {
+			return err
+		}
+		if len(approvals) >= required {
+			return s.executeWithdrawal(tx, &req)
+		}
+		return tx.Save(&req).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+// RejectWithdrawal closes a pending request. The leader or the original requester can reject.
+func (s *GuildService) RejectWithdrawal(requestID, actorID uint) error {
+	actor, err := s.getMembership(actorID)
+	if err != nil {
+		return err
+	}
+
+	var req models.GuildWithdrawalRequest
+	if err := db.DB.First(&req, requestID).Error; err != nil {
+		return errors.New("withdrawal request not found")
+	}
+	if req.GuildID != actor.GuildID {
+		return errors.New("request belongs to another guild")
+	}
+	if actor.Role != models.GuildRoleLeader && req.RequesterID != actorID {
+		return errors.New("only the leader or requester can reject")
+	}
+	if req.Status != "PENDING" {
+		return errors.New("request is no longer pending")
+	}
+
+	req.Status = "REJECTED"
+	return db.DB.Save(&req).Error
+}
+
+// ListWithdrawals returns the guild's withdrawal requests, newest first
+func (s *GuildService) ListWithdrawals(userID uint, status string) ([]models.GuildWithdrawalRequest, error) {
+	member, err := s.getMembership(userID)
+	if err != nil {
+		return nil, err
+	}
+	query := db.DB.Where("guild_id = ?", member.GuildID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var requests []models.GuildWithdrawalRequest
+	if err := query.Order("created_at DESC").Limit(100).Find(&requests).Error; err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// UpdateTreasuryPolicy lets the leader set the approval count and the auto-approve limit
+func (s *GuildService) UpdateTreasuryPolicy(leaderID uint, approvals int, autoLimit int64) error {
+	leader, err := s.requireRole(leaderID, models.GuildRoleLeader)
+	if err != nil {
+		return err
+	}
+	if approvals < 1 || approvals > 5 {
+		return errors.New("approvals must be between 1 and 5")
+	}
+	if autoLimit < 0 {
+		return errors.New("auto limit cannot be negative")
+	}
+
+	return db.DB.Model(&models.Guild{}).Where("id = ?", leader.GuildID).Updates(map[string]interface{}{
+		"withdrawal_approvals":  approvals,
+		"withdrawal_auto_limit": autoLimit,
+	}).Error
+}
+
+// requiredApprovals is the number of approvals a withdrawal needs (see approvalsNeeded)
+func (s *GuildService) requiredApprovals(tx *gorm.DB, guild *models.Guild, requesterID uint) (int, error) {
+	var eligible int64
+	if err := tx.Model(&models.GuildMember{}).
+		Where("guild_id = ? AND role IN ? AND user_id <> ?", guild.ID, []string{models.GuildRoleLeader, models.GuildRoleOfficer}, requesterID).
+		Count(&eligible).Error; err != nil {
+		return 0, err
+	}
+	return approvalsNeeded(guild.WithdrawalApprovals, int(eligible)), nil
+}
+
+// approvalsNeeded caps the guild's approval policy at the officers and leader other than the
+// requester, so a small guild's policy can be met, but never below one: a leader who demotes or
+// kicks every officer cannot clear the treasury alone.
+func approvalsNeeded(policy, eligible int) int {
+	return max(min(policy, eligible), 1)
+}
+
+// executeWithdrawal pays out from the guild treasury inside the caller's transaction
+func (s *GuildService) executeWithdrawal(tx *gorm.DB, req *models.GuildWithdrawalRequest) error {
+	treasury, err := s.ledger.GetOrCreateGuildAccount(req.GuildID, "GTK")
+	if err != nil {
+		return err
+	}
+	recipientAcc, err := s.ledger.GetOrCreateAccount(&req.RecipientID, models.AccountTypeWallet, "GTK")
+	if err != nil {
+		return err
+	}
+
+	entries := []models.LedgerEntry{
+		{AccountID: treasury.ID, Amount: -req.Amount, Type: "DEBIT"},
+		{AccountID: recipientAcc.ID, Amount: req.Amount, Type: "CREDIT"},
+	}
+	if err := s.ledger.CreateTransactionWithTx(tx, models.TxTypeGuildWithdraw, fmt.Sprintf("guild_withdrawal_%d", req.ID),
+		fmt.Sprintf("Guild Treasury Withdrawal: %s", req.Reason), entries); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	req.Status = "EXECUTED"
+	req.ExecutedAt = &now
+	if err := tx.Save(req).Error; err != nil {
+		return err
+	}
+	return s.postSystemMessage(tx, req.GuildID, req.RecipientID, fmt.Sprintf("received %d GTK from the treasury", req.Amount))
+}
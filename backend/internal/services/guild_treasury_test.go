@@ -0,0 +1,21 @@
+package services
+
+import "testing"
+
+func TestWithdrawalApprovalsNeeded(t *testing.T) {
+	cases := []struct {
+		name             string
+		policy, eligible int
+		want             int
+	}{
+		{"enough officers", 2, 4, 2},
+		{"fewer officers than the policy", 3, 1, 1},
+		{"leader alone after demoting every officer", 2, 0, 1},
+		{"officer requesting with only the leader left", 5, 1, 1},
+	}
+	for _, tc := range cases {
+		if got := approvalsNeeded(tc.policy, tc.eligible); got != tc.want {
+			t.Fatalf("%s: approvals needed = %d, want %d", tc.name, got, tc.want)
+		}
+	}
+}
@@ -2,10 +2,10 @@ package services
 
 import (
 	"errors"
-	"fmt"
 
 	"github.com/lorengraff/crypto-tower-defense/internal/db"
 	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/internal/repository"
 )
 
 // ItemService handles item-related business logic
@@ -19,7 +19,7 @@ func NewItemService() *ItemService {
 // CreateItem creates a new item
 func (s *ItemService) CreateItem(ownerID uint, itemType, name, rarity string, isConsumable, isCraftingMaterial, isStackable bool) (*models.Item, error) {
 	// Calculate stat bonuses based on rarity and type
-	bonuses := s.calculateItemBonuses(itemType, rarity)
+	bonuses := calculateItemBonuses(itemType, rarity)
 
 	item := &models.Item{
 		OwnerID:            ownerID,
@@ -69,118 +69,6 @@ func (s *ItemService) GetItemByID(itemID, ownerID uint) (*models.Item, error) {
 	return &item, nil
 }
 
-// EquipItem equips an item to a character
-func (s *ItemService) EquipItem(itemID, characterID, ownerID uint) error {
-	// Get item
-	var item models.Item
-	if err := db.DB.Where("id = ? AND owner_id = ?", itemID, ownerID).First(&item).Error; err != nil {
-		return errors.New("item not found")
-	}
-
-	// Get character
-	var character models.Character
-	if err := db.DB.Where("id = ? AND owner_id = ?", characterID, ownerID).First(&character).Error; err != nil {
-		return errors.New("character not found")
-	}
-
-	// Check if item is equipment
-	if item.ItemType != "WEAPON" && item.ItemType != "ARMOR" && item.ItemType != "ACCESSORY" && item.ItemType != "RUNE" {
-		return errors.New("item is not equipment")
-	}
-
-	// Check if already equipped
-	if item.IsEquipped {
-		return errors.New("item already equipped")
-	}
-
-	// Update item
-	item.IsEquipped = true
-	item.EquippedByID = &characterID
-
-	// Apply item effects
-	character.CurrentAttack += item.AttackBonus
-	character.CurrentDefense += item.DefenseBonus
-	character.CurrentHP += item.HPBonus
-	character.CurrentSpeed += item.SpeedBonus
-
-	// Save changes
-	if err := db.DB.Save(character).Error; err != nil {
-		return fmt.Errorf("failed to update character stats: %w", err)
-	}
-
-	// Also save the item's equipped status
-	if err := db.DB.Save(item).Error; err != nil {
-		return fmt.Errorf("failed to update item equipped status: %w", err)
-	}
-
-	return nil
-}
-
-// UnequipItem removes an item from a character
-func (s *ItemService) UnequipItem(userID, itemID uint) error {
-	// Find the item
-	var item models.Item
-	if err := db.DB.First(&item, itemID).Error; err != nil {
-		return errors.New("item not found")
-	}
-
-	if item.OwnerID != userID {
-		return errors.New("unauthorized")
-	}
-
-	if !item.IsEquipped || item.EquippedByID == nil {
-		return errors.New("item is not equipped")
-	}
-
-	characterID := *item.EquippedByID
-
-	var character models.Character
-	if err := db.DB.Preload("EquippedItems").First(&character, characterID).Error; err != nil {
-		return errors.New("character not found")
-	}
-
-	// Remove item (simplified association removal)
-	item.IsEquipped = false
-	item.EquippedByID = nil
-	// Update character stats
-	character.CurrentAttack -= item.AttackBonus
-	character.CurrentDefense -= item.DefenseBonus
-	character.CurrentHP -= item.HPBonus
-	character.CurrentSpeed -= item.SpeedBonus
-
-	// Ensure stats don't go below base
-	if character.CurrentAttack < character.BaseAttack {
-		character.CurrentAttack = character.BaseAttack
-	}
-	if character.CurrentDefense < character.BaseDefense {
-		character.CurrentDefense = character.BaseDefense
-	}
-	if character.CurrentHP < character.BaseHP {
-		character.CurrentHP = character.BaseHP
-	}
-	if character.CurrentSpeed < character.BaseSpeed {
-		character.CurrentSpeed = character.BaseSpeed
-	}
-
-	// Update item
-	item.IsEquipped = false
-	item.EquippedByID = nil
-
-	// Save both in transaction
-	tx := db.DB.Begin()
-	if err := tx.Save(&item).Error; err != nil {
-		tx.Rollback()
-		return err
-	}
-	if err := tx.Save(&character).Error; err != nil {
-		tx.Rollback()
-		return err
-	}
-	tx.Commit()
-
-	return nil
-}
-
 // UseConsumable uses a consumable item
 func (s *ItemService) UseConsumable(itemID, targetID, ownerID uint) error {
 	// Get item
@@ -206,11 +94,10 @@ func (s *ItemService) UseConsumable(itemID, targetID, ownerID uint) error {
 	// Apply effect based on consume effect type
 	switch item.ConsumeEffect {
 	case "REVIVE":
-		if !character.IsDead {
-			return errors.New("character is not dead")
+		// Same limit and count as a paid revive; the item pays for it
+		if err := reviveCharacter(&character, GetConfigService()); err != nil {
+			return err
 		}
-		character.IsDead = false
-		character.Durability = 50 // Revive to 50% durability
 
 	case "REDUCE_FATIGUE":
 		character.Fatigue -= 50
@@ -222,8 +109,10 @@ func (s *ItemService) UseConsumable(itemID, targetID, ownerID uint) error {
 		character.Experience += 1000
 
 	case "REPAIR":
+		if character.IsDead {
+			return errors.New("character is dead; revive it first")
+		}
 		character.Durability = 100
-		character.IsDead = false
 
 	default:
 		return errors.New("unknown consumable effect")
@@ -251,6 +140,12 @@ func (s *ItemService) UseConsumable(itemID, targetID, ownerID uint) error {
 			return err
 		}
 	}
+	if item.ConsumeEffect == "REVIVE" {
+		if err := recordRevive(repository.NewGormStore(tx), &character); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
 
 	tx.Commit()
 	return nil
@@ -276,7 +171,7 @@ func (s *ItemService) UpdateDurability(itemID uint, durabilityLoss int) error {
 }
 
 // calculateItemBonuses returns stat bonuses based on type and rarity
-func (s *ItemService) calculateItemBonuses(itemType, rarity string) struct{ Attack, Defense, HP, Speed int } {
+func calculateItemBonuses(itemType, rarity string) struct{ Attack, Defense, HP, Speed int } {
 	bonuses := struct{ Attack, Defense, HP, Speed int }{}
 
 	// Rarity multiplier
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/db"
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	ledgerVolumeDesc = prometheus.NewDesc(
+		"ctd_ledger_volume_total",
+		"GTK moved through the ledger (sum of credits) by transaction type.",
+		[]string{"type"}, nil,
+	)
+	wagerEscrowDesc = prometheus.NewDesc(
+		"ctd_wager_escrow_outstanding",
+		"GTK currently held in escrow for unsettled wagers.",
+		nil, nil,
+	)
+)
+
+// LedgerCollector exposes ledger-derived metrics on /metrics.
+// Values are aggregated in SQL and cached for ttl so frequent scrapes don't hit the ledger tables each time.
+type LedgerCollector struct {
+	ttl time.Duration
+
+	mu          sync.Mutex
+	refreshedAt time.Time
+	volume      map[string]float64
+	escrow      float64
+}
+
+// NewLedgerCollector creates a collector caching its aggregates for ttl
+func NewLedgerCollector(ttl time.Duration) *LedgerCollector {
+	return &LedgerCollector{ttl: ttl}
+}
+
+// Describe implements prometheus.Collector
+func (c *LedgerCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- ledgerVolumeDesc
+	ch <- wagerEscrowDesc
+}
+
+// Collect implements prometheus.Collector
+func (c *LedgerCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if time.Since(c.refreshedAt) > c.ttl {
+		if err := c.refresh(); err != nil {
+			// Serve the last good values rather than failing the whole scrape
+			slog.Warn("failed to refresh ledger metrics", "error", err)
+		} else {
+			c.refreshedAt = time.Now()
+		}
+	}
+
+	for txType, amount := range c.volume {
+		ch <- prometheus.MustNewConstMetric(ledgerVolumeDesc, prometheus.CounterValue, amount, txType)
+	}
+	ch <- prometheus.MustNewConstMetric(wagerEscrowDesc, prometheus.GaugeValue, c.escrow)
+}
+
+func (c *LedgerCollector) refresh() error {
+	var rows []struct {
+		Type   string
+		Volume int64
+	}
+	if err := db.DB.Table("ledger_entries le").
+		Select("lt.type AS type, COALESCE(SUM(le.amount), 0) AS volume").
+		Joins("JOIN ledger_transactions lt ON lt.id = le.transaction_id").
+		Where("le.amount > 0").
+		Group("lt.type").
+		Scan(&rows).Error; err != nil {
+		return err
+	}
+
+	// Net of every wager posting on the escrow account: entries lock funds, wins and refunds release them
+	var escrow int64
+	if err := db.DB.Table("ledger_entries le").
+		Select("COALESCE(SUM(le.amount), 0)").
+		Joins("JOIN ledger_transactions lt ON lt.id = le.transaction_id").
+		Joins("JOIN ledger_accounts la ON la.id = le.account_id").
+		Where("la.type = ? AND lt.type LIKE ?", models.AccountTypeEscrow, "WAGER_%").
+		Scan(&escrow).Error; err != nil {
+		return err
+	}
+
+	c.volume = make(map[string]float64, len(rows))
+	for _, r := range rows {
+		c.volume[r.Type] = float64(r.Volume)
+	}
+	c.escrow = float64(escrow)
+	return nil
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/internal/repository"
+)
+
+func TestLedgerPostsBalancedTransaction(t *testing.T) {
+	st := repository.NewMemoryStore()
+	ledger := NewLedgerService(st)
+	alice, bob := newTestUser(t, st, 1000), newTestUser(t, st, 1000)
+	fund(t, ledger, alice.ID, 300)
+
+	if err := ledger.TransferFunds(&alice.ID, &bob.ID, 120, models.TxTypeAdminAdj, "t1"); err != nil {
+		t.Fatal(err)
+	}
+	if got := balance(t, ledger, &alice.ID, models.AccountTypeWallet); got != 180 {
+		t.Fatalf("alice balance = %d, want 180", got)
+	}
+	if got := balance(t, ledger, &bob.ID, models.AccountTypeWallet); got != 120 {
+		t.Fatalf("bob balance = %d, want 120", got)
+	}
+	// The reward pool may go negative: it is where GTK enters circulation
+	if got := balance(t, ledger, nil, models.AccountTypeReward); got != -300 {
+		t.Fatalf("reward pool = %d, want -300", got)
+	}
+}
+
+func TestLedgerRejectsUnbalancedTransaction(t *testing.T) {
+	st := repository.NewMemoryStore()
+	ledger := NewLedgerService(st)
+	user := newTestUser(t, st, 1000)
+	wallet, _ := ledger.GetOrCreateAccount(&user.ID, models.AccountTypeWallet, "GTK")
+
+	err := ledger.CreateTransaction(models.TxTypeAdminAdj, "bad", "Mint from nothing", []models.LedgerEntry{
+		{AccountID: wallet.ID, Amount: 50, Type: "CREDIT"},
+	})
+	if err == nil {
+		t.Fatal("unbalanced transaction was accepted")
+	}
+	if got := balance(t, ledger, &user.ID, models.AccountTypeWallet); got != 0 {
+		t.Fatalf("balance = %d after rejected transaction", got)
+	}
+}
+
+func TestLedgerInsufficientFundsRollsBack(t *testing.T) {
+	st := repository.NewMemoryStore()
+	ledger := NewLedgerService(st)
+	alice, bob := newTestUser(t, st, 1000), newTestUser(t, st, 1000)
+	fund(t, ledger, alice.ID, 50)
+	bobWallet, _ := ledger.GetOrCreateAccount(&bob.ID, models.AccountTypeWallet, "GTK")
+	aliceWallet, _ := ledger.GetOrCreateAccount(&alice.ID, models.AccountTypeWallet, "GTK")
+
+	// Bob's credit is posted before Alice's debit fails; the whole posting must disappear
+	err := ledger.CreateTransaction(models.TxTypeAdminAdj, "overdraw", "Overdraw", []models.LedgerEntry{
+		{AccountID: bobWallet.ID, Amount: 80, Type: "CREDIT"},
+		{AccountID: aliceWallet.ID, Amount: -80, Type: "DEBIT"},
+	})
+	if err == nil {
+		t.Fatal("overdraft was accepted")
+	}
+	if got := balance(t, ledger, &bob.ID, models.AccountTypeWallet); got != 0 {
+		t.Fatalf("bob balance = %d, want rollback to 0", got)
+	}
+	if got := balance(t, ledger, &alice.ID, models.AccountTypeWallet); got != 50 {
+		t.Fatalf("alice balance = %d, want 50", got)
+	}
+}
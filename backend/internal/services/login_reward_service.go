@@ -0,0 +1,377 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	// Embedded zone database, so player timezones resolve on hosts without one
+	_ "time/tzdata"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/internal/repository"
+)
+
+var errAlreadyClaimed = errors.New("login reward already claimed today")
+
+// LoginCalendar is a player's view of the login calendar
+type LoginCalendar struct {
+	Timezone       string              `json:"timezone"`
+	Today          string              `json:"today"`  // Date in the player's timezone
+	Streak         int                 `json:"streak"` // Consecutive days claimed; 0 once it lapsed
+	ClaimedToday   bool                `json:"claimed_today"`
+	NextDay        int                 `json:"next_day"`        // Calendar day the next claim pays
+	GraceAvailable bool                `json:"grace_available"` // A missed day would not break the streak
+	Days           []LoginCalendarSlot `json:"days"`
+}
+
+// LoginCalendarSlot is one calendar day and whether the current cycle has claimed it
+type LoginCalendarSlot struct {
+	models.LoginCalendarDay
+	Claimed bool `json:"claimed"`
+}
+
+// loginRewardItem is one entry of LoginReward.RewardItems
+type loginRewardItem struct {
+	ItemID   uint   `json:"item_id"`
+	Name     string `json:"name"`
+	Quantity int    `json:"quantity"`
+}
+
+// LoginRewardService pays the daily login calendar. Days are counted in the player's
+// timezone; claiming on consecutive days builds a streak that walks the calendar and starts
+// over after login_calendar_length days. One missed day per cycle is forgiven.
+type LoginRewardService struct {
+	store    repository.Store
+	ledger   *LedgerService
+	config   Settings
+	notifier Notifier
+	admin    *AdminService
+}
+
+// NewLoginRewardService creates the login reward service; admin records calendar edits in the audit log
+func NewLoginRewardService(store repository.Store, ledger *LedgerService, config Settings, notifier Notifier, admin *AdminService) *LoginRewardService {
+	return &LoginRewardService{
+		store:    store,
+		ledger:   ledger,
+		config:   config,
+		notifier: notifier,
+		admin:    admin,
+	}
+}
+
+// Calendar returns the calendar with the player's streak and what they claimed this cycle
+func (s *LoginRewardService) Calendar(userID uint) (*LoginCalendar, error) {
+	user, err := s.store.Users().Get(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	days, err := s.store.LoginRewards().Calendar()
+	if err != nil {
+		return nil, err
+	}
+	last, err := s.lastClaim(s.store, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	cycle := s.cycleLength()
+	view := &LoginCalendar{Timezone: userLocation(user).String(), Today: localDate(user, time.Now())}
+	var claimedThrough int
+	streak, graceUsed, err := nextLoginDay(last, view.Today, cycle)
+	switch {
+	case errors.Is(err, errAlreadyClaimed):
+		view.Streak = last.Streak
+		view.ClaimedToday = true
+		view.NextDay = last.DayNumber%cycle + 1
+		view.GraceAvailable = !last.GraceUsed || view.NextDay == 1
+		claimedThrough = last.DayNumber
+	case err != nil:
+		return nil, err
+	default:
+		view.Streak = streak - 1
+		view.NextDay = cycleDay(streak, cycle)
+		view.GraceAvailable = !graceUsed
+		claimedThrough = view.NextDay - 1
+	}
+
+	for _, d := range days {
+		if d.DayNumber > cycle {
+			continue
+		}
+		view.Days = append(view.Days, LoginCalendarSlot{LoginCalendarDay: d, Claimed: d.DayNumber <= claimedThrough})
+	}
+	return view, nil
+}
+
+// Claim pays today's calendar reward. The (user, date) unique index stops a second device
+// claiming the same day even if both requests get past the streak check.
+func (s *LoginRewardService) Claim(userID uint) (*models.LoginReward, error) {
+	var claim *models.LoginReward
+	var milestone bool
+	err := s.store.Transaction(func(tx repository.Store) error {
+		user, err := tx.Users().Lock(userID)
+		if err != nil {
+			return errors.New("user not found")
+		}
+		today := localDate(user, time.Now())
+		last, err := s.lastClaim(tx, userID)
+		if err != nil {
+			return err
+		}
+		streak, graceUsed, err := nextLoginDay(last, today, s.cycleLength())
+		if err != nil {
+			return err
+		}
+		day, err := tx.LoginRewards().CalendarDay(cycleDay(streak, s.cycleLength()))
+		if errors.Is(err, repository.ErrNotFound) {
+			return fmt.Errorf("login calendar has no reward for day %d", cycleDay(streak, s.cycleLength()))
+		}
+		if err != nil {
+			return err
+		}
+
+		claim = &models.LoginReward{
+			UserID:       userID,
+			ClaimDate:    today,
+			DayNumber:    day.DayNumber,
+			Streak:       streak,
+			GraceUsed:    graceUsed,
+			ClaimedAt:    time.Now(),
+			RewardTokens: int(day.RewardGTK),
+			RewardItems:  "[]",
+		}
+		if day.ItemID != nil && day.ItemQuantity > 0 {
+			name := ""
+			if day.Item != nil {
+				name = day.Item.Name
+			}
+			items, _ := json.Marshal([]loginRewardItem{{ItemID: *day.ItemID, Name: name, Quantity: day.ItemQuantity}})
+			claim.RewardItems = string(items)
+		}
+		if err := tx.LoginRewards().CreateClaim(claim); err != nil {
+			if errors.Is(err, repository.ErrDuplicate) {
+				return errAlreadyClaimed
+			}
+			return err
+		}
+		milestone = day.IsMilestone
+		return s.pay(tx, userID, claim, day)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if milestone {
+		s.notify(userID, "LOGIN_MILESTONE", "Login Milestone",
+			fmt.Sprintf("Day %d of your login calendar reached with a %d-day streak!", claim.DayNumber, claim.Streak),
+			map[string]interface{}{"day_number": claim.DayNumber, "streak": claim.Streak})
+	}
+	return claim, nil
+}
+
+// pay credits a claim's GTK from the reward pool and adds its item to the inventory
+func (s *LoginRewardService) pay(tx repository.Store, userID uint, claim *models.LoginReward, day *models.LoginCalendarDay) error {
+	if day.RewardGTK > 0 {
+		ledger := s.ledger.WithStore(tx)
+		rewardAcc, err := ledger.GetOrCreateAccount(nil, models.AccountTypeReward, "GTK")
+		if err != nil {
+			return err
+		}
+		userAcc, err := ledger.GetOrCreateAccount(&userID, models.AccountTypeWallet, "GTK")
+		if err != nil {
+			return err
+		}
+		entries := []models.LedgerEntry{
+			{AccountID: rewardAcc.ID, Amount: -day.RewardGTK, Type: "DEBIT"},
+			{AccountID: userAcc.ID, Amount: day.RewardGTK, Type: "CREDIT"},
+		}
+		refID := fmt.Sprintf("login_%d_%s", userID, claim.ClaimDate)
+		if err := ledger.CreateTransaction(models.TxTypeLoginReward, refID, fmt.Sprintf("Login calendar day %d", day.DayNumber), entries); err != nil {
+			return err
+		}
+		if err := tx.Users().AdjustLegacyTokens(userID, day.RewardGTK); err != nil {
+			return err
+		}
+	}
+
+	if day.ItemID == nil || day.ItemQuantity <= 0 {
+		return nil
+	}
+	inv, err := tx.Inventory().Find(userID, *day.ItemID)
+	if errors.Is(err, repository.ErrNotFound) {
+		inv = &models.UserInventory{UserID: userID, ItemID: *day.ItemID, AcquiredAt: time.Now()}
+	} else if err != nil {
+		return err
+	}
+	inv.Quantity += day.ItemQuantity
+	return tx.Inventory().Save(inv)
+}
+
+// SetTimezone changes the IANA timezone a player's login days follow. Changes are limited to
+// one per login_timezone_change_days so hopping zones cannot squeeze in extra claims.
+func (s *LoginRewardService) SetTimezone(userID uint, name string) (*models.User, error) {
+	loc, err := time.LoadLocation(name)
+	if err != nil || name == "" || name == "Local" {
+		return nil, fmt.Errorf("unknown timezone %q", name)
+	}
+	var updated *models.User
+	err = s.store.Transaction(func(tx repository.Store) error {
+		user, err := tx.Users().Lock(userID)
+		if err != nil {
+			return errors.New("user not found")
+		}
+		if user.Timezone == loc.String() {
+			updated = user
+			return nil
+		}
+		wait := time.Duration(s.config.GetInt("login_timezone_change_days", 7)) * 24 * time.Hour
+		if user.TimezoneChangedAt != nil && time.Since(*user.TimezoneChangedAt) < wait {
+			return fmt.Errorf("timezone can be changed again after %s", user.TimezoneChangedAt.Add(wait).Format(time.RFC3339))
+		}
+		now := time.Now()
+		user.Timezone = loc.String()
+		user.TimezoneChangedAt = &now
+		updated = user
+		return tx.Users().Save(user)
+	})
+	return updated, err
+}
+
+// AdminCalendar returns every configured calendar day
+func (s *LoginRewardService) AdminCalendar() ([]models.LoginCalendarDay, error) {
+	return s.store.LoginRewards().Calendar()
+}
+
+// SetCalendarDay sets what a calendar day pays. Seeding leaves admin-edited days alone.
+func (s *LoginRewardService) SetCalendarDay(day *models.LoginCalendarDay, adminID uint) (*models.LoginCalendarDay, error) {
+	if day.DayNumber < 1 || day.DayNumber > s.cycleLength() {
+		return nil, fmt.Errorf("day must be between 1 and %d", s.cycleLength())
+	}
+	if day.RewardGTK < 0 || day.ItemQuantity < 0 {
+		return nil, errors.New("rewards cannot be negative")
+	}
+	if day.ItemID == nil {
+		day.ItemQuantity = 0
+	} else if day.ItemQuantity == 0 {
+		day.ItemID = nil
+	}
+	if day.RewardGTK == 0 && day.ItemID == nil {
+		return nil, errors.New("a calendar day must pay GTK or an item")
+	}
+
+	var old string
+	err := s.store.Transaction(func(tx repository.Store) error {
+		if day.ItemID != nil {
+			if _, err := tx.Inventory().ShopItem(*day.ItemID); err != nil {
+				return fmt.Errorf("shop item %d not found", *day.ItemID)
+			}
+		}
+		if existing, err := tx.LoginRewards().CalendarDay(day.DayNumber); err == nil {
+			old = describeCalendarDay(existing)
+		}
+		day.ID = 0
+		day.Item = nil
+		day.UpdatedBy = adminID
+		return tx.LoginRewards().SaveCalendarDay(day)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if s.admin != nil {
+		s.admin.CreateAuditLog(adminID, "UPDATE_LOGIN_CALENDAR", strconv.Itoa(day.DayNumber), old, describeCalendarDay(day))
+	}
+	return s.store.LoginRewards().CalendarDay(day.DayNumber)
+}
+
+// lastClaim returns a player's latest claim, or nil if they never claimed
+func (s *LoginRewardService) lastClaim(st repository.Store, userID uint) (*models.LoginReward, error) {
+	last, err := st.LoginRewards().LastClaim(userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, nil
+	}
+	return last, err
+}
+
+func (s *LoginRewardService) cycleLength() int {
+	return max(s.config.GetInt("login_calendar_length", 28), 1)
+}
+
+// notify sends a best-effort login reward notification
+func (s *LoginRewardService) notify(userID uint, notifType, title, message string, data interface{}) {
+	if err := s.notifier.CreateNotification(userID, notifType, title, message, data); err != nil {
+		slog.Warn("failed to send login reward notification", "user_id", userID, "type", notifType, "error", err)
+	}
+}
+
+// nextLoginDay works out the streak a claim on today (YYYY-MM-DD) continues after last,
+// and whether the cycle's grace day is spent once it is made. A claim the day after the last
+// one extends the streak; so does one two days after while the grace day is unspent. Any
+// longer gap starts over at day 1, and every new cycle gets its grace day back.
+func nextLoginDay(last *models.LoginReward, today string, cycle int) (streak int, graceUsed bool, err error) {
+	if last == nil {
+		return 1, false, nil
+	}
+	gap, err := daysBetween(last.ClaimDate, today)
+	if err != nil {
+		return 0, false, err
+	}
+	switch {
+	case gap <= 0:
+		return 0, false, errAlreadyClaimed
+	case gap == 1:
+		streak, graceUsed = last.Streak+1, last.GraceUsed
+	case gap == 2 && !last.GraceUsed:
+		streak, graceUsed = last.Streak+1, true
+	default:
+		return 1, false, nil
+	}
+	if cycleDay(streak, cycle) == 1 && gap == 1 {
+		graceUsed = false
+	}
+	return streak, graceUsed, nil
+}
+
+// cycleDay is the calendar day the streak-th consecutive claim pays
+func cycleDay(streak, cycle int) int {
+	return (streak-1)%cycle + 1
+}
+
+// daysBetween counts the calendar days from one YYYY-MM-DD date to another
+func daysBetween(from, to string) (int, error) {
+	a, err := time.Parse(time.DateOnly, from)
+	if err != nil {
+		return 0, err
+	}
+	b, err := time.Parse(time.DateOnly, to)
+	if err != nil {
+		return 0, err
+	}
+	return int(b.Sub(a).Hours() / 24), nil
+}
+
+// localDate is the player's calendar date at now
+func localDate(user *models.User, now time.Time) string {
+	return now.In(userLocation(user)).Format(time.DateOnly)
+}
+
+// userLocation is the timezone a player's login days follow; an unknown one counts as UTC
+func userLocation(user *models.User) *time.Location {
+	loc, err := time.LoadLocation(user.Timezone)
+	if err != nil || user.Timezone == "" {
+		return time.UTC
+	}
+	return loc
+}
+
+func describeCalendarDay(d *models.LoginCalendarDay) string {
+	item := "none"
+	if d.ItemID != nil {
+		item = fmt.Sprintf("%dx item %d", d.ItemQuantity, *d.ItemID)
+	}
+	return fmt.Sprintf("%d GTK, %s, milestone %v", d.RewardGTK, item, d.IsMilestone)
+}
@@ -0,0 +1,228 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/internal/repository"
+)
+
+// newTestCalendar configures a calendar paying 10 GTK times the day number, with day 1 a milestone
+func newTestCalendar(t *testing.T, st repository.Store, days int) {
+	t.Helper()
+	for d := 1; d <= days; d++ {
+		day := &models.LoginCalendarDay{DayNumber: d, RewardGTK: int64(10 * d), IsMilestone: d == 1}
+		if err := st.LoginRewards().SaveCalendarDay(day); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// claimedDaysAgo records a past claim for a player, as if made n days ago in UTC
+func claimedDaysAgo(t *testing.T, st repository.Store, userID uint, n, streak, day int, graceUsed bool) {
+	t.Helper()
+	claim := &models.LoginReward{
+		UserID:    userID,
+		ClaimDate: time.Now().UTC().AddDate(0, 0, -n).Format(time.DateOnly),
+		Streak:    streak,
+		DayNumber: day,
+		GraceUsed: graceUsed,
+	}
+	if err := st.LoginRewards().CreateClaim(claim); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoginClaimPaysOnceADay(t *testing.T) {
+	st := repository.NewMemoryStore()
+	ledger := NewLedgerService(st)
+	notifier := &testNotifier{}
+	svc := NewLoginRewardService(st, ledger, testSettings{}, notifier, nil)
+	user := newTestUser(t, st, 0)
+	ticket := st.PutShopItem(models.ShopItem{Name: "Egg Ticket", Category: "egg"})
+
+	if _, err := svc.Claim(user.ID); err == nil {
+		t.Fatal("claimed from an empty calendar")
+	}
+	day := &models.LoginCalendarDay{DayNumber: 1, RewardGTK: 50, ItemID: &ticket.ID, ItemQuantity: 2, IsMilestone: true}
+	if _, err := svc.SetCalendarDay(day, 9); err != nil {
+		t.Fatal(err)
+	}
+
+	claim, err := svc.Claim(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claim.DayNumber != 1 || claim.Streak != 1 || claim.RewardTokens != 50 {
+		t.Fatalf("claim = day %d streak %d tokens %d, want day 1 streak 1 and 50", claim.DayNumber, claim.Streak, claim.RewardTokens)
+	}
+	if b := balance(t, ledger, &user.ID, models.AccountTypeWallet); b != 50 {
+		t.Fatalf("wallet = %d, want 50", b)
+	}
+	if inv, err := st.Inventory().Find(user.ID, ticket.ID); err != nil || inv.Quantity != 2 {
+		t.Fatalf("inventory = %+v (%v), want 2 egg tickets", inv, err)
+	}
+	if len(notifier.sent) != 1 || notifier.sent[0] != "LOGIN_MILESTONE" {
+		t.Fatalf("notifications = %v, want one milestone", notifier.sent)
+	}
+
+	// A second device claiming the same day gets nothing
+	if _, err := svc.Claim(user.ID); err == nil {
+		t.Fatal("claimed twice in one day")
+	}
+	if b := balance(t, ledger, &user.ID, models.AccountTypeWallet); b != 50 {
+		t.Fatalf("wallet = %d after a second claim, want 50", b)
+	}
+	if err := st.LoginRewards().CreateClaim(&models.LoginReward{UserID: user.ID, ClaimDate: claim.ClaimDate}); !errors.Is(err, repository.ErrDuplicate) {
+		t.Fatalf("duplicate claim row: %v, want ErrDuplicate", err)
+	}
+
+	view, err := svc.Calendar(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !view.ClaimedToday || view.Streak != 1 || view.NextDay != 2 || !view.Days[0].Claimed {
+		t.Fatalf("calendar = %+v, want day 1 claimed today", view)
+	}
+}
+
+func TestMilestoneEggTicketMintsAnEgg(t *testing.T) {
+	st := repository.NewMemoryStore()
+	svc := NewLoginRewardService(st, NewLedgerService(st), testSettings{}, &testNotifier{}, nil)
+	user := newTestUser(t, st, 0)
+	ticket := st.PutShopItem(models.ShopItem{Name: "Egg Ticket", Category: "egg", EffectType: "egg_ticket"})
+	kit := st.PutShopItem(models.ShopItem{Name: "Care Kit", Category: "egg", EffectType: "care"})
+	day := &models.LoginCalendarDay{DayNumber: 1, ItemID: &ticket.ID, ItemQuantity: 1, IsMilestone: true}
+	if _, err := svc.SetCalendarDay(day, 9); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Claim(user.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := redeemEggTicket(st, user.ID, kit.ID, &models.Egg{Rarity: "C"}); err == nil {
+		t.Fatal("redeemed a care kit as an egg ticket")
+	}
+	egg := &models.Egg{Rarity: "C", MintCost: 500}
+	if err := redeemEggTicket(st, user.ID, ticket.ID, egg); err != nil {
+		t.Fatal(err)
+	}
+	stored, err := st.Eggs().Get(egg.ID)
+	if err != nil || stored.UserID != user.ID || stored.MintCost != 0 {
+		t.Fatalf("egg = %+v (%v), want a free egg owned by the player", stored, err)
+	}
+	if _, err := st.Inventory().Find(user.ID, ticket.ID); err == nil {
+		t.Fatal("the ticket was not consumed")
+	}
+	if err := redeemEggTicket(st, user.ID, ticket.ID, &models.Egg{Rarity: "C"}); err == nil {
+		t.Fatal("redeemed a ticket the player no longer has")
+	}
+}
+
+func TestLoginStreakGraceDayAndCycle(t *testing.T) {
+	st := repository.NewMemoryStore()
+	ledger := NewLedgerService(st)
+	svc := NewLoginRewardService(st, ledger, testSettings{"login_calendar_length": 3}, &testNotifier{}, nil)
+	newTestCalendar(t, st, 3)
+
+	cases := []struct {
+		name                 string
+		daysAgo, streak, day int
+		graceUsed            bool
+		wantStreak, wantDay  int
+		wantGrace            bool
+	}{
+		{"yesterday continues", 1, 1, 1, false, 2, 2, false},
+		{"one missed day uses the grace day", 2, 2, 2, false, 3, 3, true},
+		{"a second missed day resets", 2, 2, 2, true, 1, 1, false},
+		{"two missed days reset", 3, 2, 2, false, 1, 1, false},
+		{"a new cycle restores the grace day", 1, 3, 3, true, 4, 1, false},
+	}
+	for _, tc := range cases {
+		user := newTestUser(t, st, 0)
+		claimedDaysAgo(t, st, user.ID, tc.daysAgo, tc.streak, tc.day, tc.graceUsed)
+
+		claim, err := svc.Claim(user.ID)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if claim.Streak != tc.wantStreak || claim.DayNumber != tc.wantDay || claim.GraceUsed != tc.wantGrace {
+			t.Fatalf("%s: streak %d day %d grace %v, want %d, %d and %v", tc.name,
+				claim.Streak, claim.DayNumber, claim.GraceUsed, tc.wantStreak, tc.wantDay, tc.wantGrace)
+		}
+		if b := balance(t, ledger, &user.ID, models.AccountTypeWallet); b != int64(10*tc.wantDay) {
+			t.Fatalf("%s: wallet = %d, want %d", tc.name, b, 10*tc.wantDay)
+		}
+	}
+}
+
+func TestLoginDaysFollowPlayerTimezone(t *testing.T) {
+	st := repository.NewMemoryStore()
+	svc := NewLoginRewardService(st, NewLedgerService(st), testSettings{}, &testNotifier{}, nil)
+	user := newTestUser(t, st, 0)
+
+	// Noon UTC is already tomorrow at UTC+14 and still today at UTC-11
+	noon := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	if got := localDate(&models.User{Timezone: "Pacific/Kiritimati"}, noon); got != "2026-03-11" {
+		t.Fatalf("date at UTC+14 = %s, want 2026-03-11", got)
+	}
+	if got := localDate(&models.User{Timezone: "Pacific/Pago_Pago"}, noon); got != "2026-03-10" {
+		t.Fatalf("date at UTC-11 = %s, want 2026-03-10", got)
+	}
+	if got := localDate(&models.User{Timezone: "Mars/Olympus"}, noon); got != "2026-03-10" {
+		t.Fatalf("date in an unknown timezone = %s, want the UTC date", got)
+	}
+
+	if _, err := svc.SetTimezone(user.ID, "Mars/Olympus"); err == nil {
+		t.Fatal("set an unknown timezone")
+	}
+	updated, err := svc.SetTimezone(user.ID, "Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Timezone != "Asia/Tokyo" {
+		t.Fatalf("timezone = %q, want Asia/Tokyo", updated.Timezone)
+	}
+	if _, err := svc.SetTimezone(user.ID, "Europe/Paris"); err == nil {
+		t.Fatal("changed timezone twice within the cooldown")
+	}
+	view, err := svc.Calendar(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Now().In(time.FixedZone("JST", 9*3600)).Format(time.DateOnly); view.Today != want {
+		t.Fatalf("calendar today = %s, want the Tokyo date %s", view.Today, want)
+	}
+}
+
+func TestAdminCalendarEdits(t *testing.T) {
+	st := repository.NewMemoryStore()
+	svc := NewLoginRewardService(st, NewLedgerService(st), testSettings{}, &testNotifier{}, nil)
+	missing := uint(99)
+
+	for _, bad := range []models.LoginCalendarDay{
+		{DayNumber: 0, RewardGTK: 10},
+		{DayNumber: 29, RewardGTK: 10},
+		{DayNumber: 1, RewardGTK: -5},
+		{DayNumber: 1},
+		{DayNumber: 1, ItemID: &missing, ItemQuantity: 1},
+	} {
+		if _, err := svc.SetCalendarDay(&bad, 9); err == nil {
+			t.Fatalf("accepted calendar day %+v", bad)
+		}
+	}
+
+	if _, err := svc.SetCalendarDay(&models.LoginCalendarDay{DayNumber: 5, RewardGTK: 10}, 9); err != nil {
+		t.Fatal(err)
+	}
+	saved, err := svc.SetCalendarDay(&models.LoginCalendarDay{DayNumber: 5, RewardGTK: 25}, 9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	days, _ := svc.AdminCalendar()
+	if len(days) != 1 || saved.RewardGTK != 25 || saved.UpdatedBy != 9 {
+		t.Fatalf("calendar = %+v, want day 5 replaced at 25 GTK by admin 9", days)
+	}
+}
@@ -44,7 +44,6 @@ func (s *LootService) GenerateLoot(missionID uint, performanceGrade string) ([]m
 				ItemID:   entry.Item.ID,
 				ItemName: entry.Item.Name,
 				Quantity: quantity,
-				Rarity:   entry.Item.Rarity,
 			})
 		}
 	}
@@ -0,0 +1,201 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/internal/repository"
+)
+
+// CreateAuction opens an English auction. The reserve price stays hidden; if bidding ends below it the asset returns to the seller.
+func (s *MarketplaceService) CreateAuction(userID uint, ref AssetRef, startingBid, reservePrice int64, durationHours int) (*models.MarketplaceListing, error) {
+	if startingBid <= 0 {
+		return nil, errors.New("starting bid must be positive")
+	}
+	if reservePrice < 0 {
+		return nil, errors.New("reserve price cannot be negative")
+	}
+	maxHours := s.config.GetInt("marketplace_auction_max_hours", 168)
+	if durationHours < 1 || durationHours > maxHours {
+		return nil, fmt.Errorf("auction duration must be between 1 and %d hours", maxHours)
+	}
+
+	listing := models.MarketplaceListing{
+		SellerID:     userID,
+		AssetType:    ref.AssetType,
+		ListingType:  models.ListingTypeAuction,
+		Price:        startingBid,
+		ReservePrice: reservePrice,
+		Status:       "ACTIVE",
+		ExpiresAt:    time.Now().Add(time.Duration(durationHours) * time.Hour),
+	}
+	switch ref.AssetType {
+	case "character":
+		listing.CharacterID = &ref.AssetID
+	case "item", "equipment":
+		listing.ItemID = &ref.AssetID
+	default:
+		return nil, errors.New("invalid item type")
+	}
+
+	err := s.store.Transaction(func(tx repository.Store) error {
+		if err := s.lockAssetTx(tx, userID, ref); err != nil {
+			return err
+		}
+		return tx.Listings().Create(&listing)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &listing, nil
+}
+
+// PlaceBid locks the bid in escrow and refunds the previous highest bidder.
+// Bids in the final minutes push the end time back (anti-sniping).
+func (s *MarketplaceService) PlaceBid(bidderID, listingID uint, amount int64) (*models.MarketplaceListing, error) {
+	var listing *models.MarketplaceListing
+	var outbidID *uint
+
+	err := s.store.Transaction(func(tx repository.Store) error {
+		var err error
+		if listing, err = tx.Listings().Lock(listingID); err != nil {
+			return errors.New("listing not found")
+		}
+		if listing.ListingType != models.ListingTypeAuction {
+			return errors.New("listing is not an auction")
+		}
+		if listing.Status != "ACTIVE" {
+			return errors.New("auction is no longer active")
+		}
+		now := time.Now()
+		if now.After(listing.ExpiresAt) {
+			return errors.New("auction has ended")
+		}
+		if listing.SellerID == bidderID {
+			return errors.New("cannot bid on your own auction")
+		}
+
+		minBid := listing.Price
+		if listing.BidCount > 0 {
+			increment := listing.CurrentBid * int64(s.config.GetInt("marketplace_min_bid_increment_percent", 5)) / 100
+			if increment < 1 {
+				increment = 1
+			}
+			minBid = listing.CurrentBid + increment
+		}
+		if amount < minBid {
+			return fmt.Errorf("bid must be at least %d", minBid)
+		}
+
+		// Release the previous highest bid before locking the new one (covers raising your own bid)
+		if listing.HighestBidderID != nil {
+			prev := *listing.HighestBidderID
+			if err := s.refundFundsTx(tx, prev, listing.CurrentBid, fmt.Sprintf("auction_%d_outbid_%d", listing.ID, listing.BidCount),
+				fmt.Sprintf("Outbid refund: Auction #%d", listing.ID)); err != nil {
+				return err
+			}
+			if err := tx.Listings().SetBidStatus(listing.ID, "ACTIVE", "OUTBID"); err != nil {
+				return err
+			}
+			if prev != bidderID {
+				outbidID = &prev
+			}
+		}
+
+		if err := s.lockFundsTx(tx, bidderID, amount, fmt.Sprintf("auction_%d_bid_%d", listing.ID, listing.BidCount+1),
+			fmt.Sprintf("Bid escrow: Auction #%d", listing.ID)); err != nil {
+			return err
+		}
+		if err := tx.Listings().CreateBid(&models.MarketplaceBid{ListingID: listing.ID, BidderID: bidderID, Amount: amount, Status: "ACTIVE"}); err != nil {
+			return err
+		}
+
+		listing.CurrentBid = amount
+		listing.HighestBidderID = &bidderID
+		listing.BidCount++
+
+		window := time.Duration(s.config.GetInt("marketplace_auction_snipe_window_minutes", 5)) * time.Minute
+		extension := time.Duration(s.config.GetInt("marketplace_auction_extension_minutes", 5)) * time.Minute
+		if listing.ExpiresAt.Sub(now) < window {
+			listing.ExpiresAt = now.Add(extension)
+		}
+		return tx.Listings().Save(listing)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if outbidID != nil {
+		s.notify(*outbidID, "AUCTION_OUTBID", "You have been outbid",
+			fmt.Sprintf("Someone bid %d on auction #%d. Your bid was refunded.", amount, listing.ID),
+			map[string]interface{}{"listing_id": listing.ID, "current_bid": amount})
+	}
+	return listing, nil
+}
+
+// GetBids returns the bid history of an auction, highest first
+func (s *MarketplaceService) GetBids(listingID uint) ([]models.MarketplaceBid, error) {
+	return s.store.Listings().Bids(listingID, 100)
+}
+
+// settleAuctionTx closes an ended auction: sells to the highest bidder if the reserve is met, otherwise refunds and returns the asset
+func (s *MarketplaceService) settleAuctionTx(tx repository.Store, listing *models.MarketplaceListing) error {
+	ref := listingAssetRef(listing)
+
+	if listing.HighestBidderID == nil || listing.CurrentBid < listing.ReservePrice {
+		if listing.HighestBidderID != nil {
+			if err := s.refundFundsTx(tx, *listing.HighestBidderID, listing.CurrentBid, fmt.Sprintf("auction_%d_reserve_refund", listing.ID),
+				fmt.Sprintf("Reserve not met: Auction #%d", listing.ID)); err != nil {
+				return err
+			}
+			if err := tx.Listings().SetBidStatus(listing.ID, "ACTIVE", "REFUNDED"); err != nil {
+				return err
+			}
+		}
+		if err := s.releaseAssetTx(tx, ref); err != nil {
+			return err
+		}
+		listing.Status = "EXPIRED"
+		return tx.Listings().Save(listing)
+	}
+
+	winnerID := *listing.HighestBidderID
+	if err := s.moveAssetTx(tx, ref, listing.SellerID, winnerID); err != nil {
+		return err
+	}
+	if err := s.payoutFromEscrowTx(tx, listing.SellerID, listing.CurrentBid, fmt.Sprintf("auction_%d_sale", listing.ID),
+		fmt.Sprintf("Auction sale: Listing #%d", listing.ID)); err != nil {
+		return err
+	}
+	if err := tx.Listings().SetBidStatus(listing.ID, "ACTIVE", "WON"); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	listing.Status = "SOLD"
+	listing.BuyerID = &winnerID
+	listing.SoldAt = &now
+	if err := tx.Listings().Save(listing); err != nil {
+		return err
+	}
+
+	return tx.Listings().CreateTrade(&models.TradeHistory{
+		ListingID: &listing.ID,
+		SellerID:  listing.SellerID,
+		BuyerID:   winnerID,
+		ItemType:  listing.AssetType,
+		ItemID:    ref.AssetID,
+		Price:     listing.CurrentBid,
+		Currency:  "GTK",
+	})
+}
+
+// notify sends a best-effort marketplace notification
+func (s *MarketplaceService) notify(userID uint, notifType, title, message string, data interface{}) {
+	if err := s.notifier.CreateNotification(userID, notifType, title, message, data); err != nil {
+		log.Printf("Failed to notify user %d (%s): %v", userID, notifType, err)
+	}
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/internal/repository"
+)
+
+const maxBundleSize = 10
+
+// CreateBundleListing lists several characters/items for one price. The bundle sells (or expires) atomically.
+func (s *MarketplaceService) CreateBundleListing(userID uint, assets []AssetRef, price int64) (*models.MarketplaceListing, error) {
+	if price <= 0 {
+		return nil, errors.New("price must be positive")
+	}
+	if len(assets) < 2 || len(assets) > maxBundleSize {
+		return nil, fmt.Errorf("a bundle needs between 2 and %d assets", maxBundleSize)
+	}
+
+	seen := make(map[string]bool, len(assets))
+	for _, a := range assets {
+		key := fmt.Sprintf("%s:%d", a.AssetType, a.AssetID)
+		if a.AssetType == "equipment" {
+			key = fmt.Sprintf("item:%d", a.AssetID)
+		}
+		if seen[key] {
+			return nil, errors.New("bundle contains the same asset twice")
+		}
+		seen[key] = true
+	}
+
+	listing := models.MarketplaceListing{
+		SellerID:    userID,
+		AssetType:   "bundle",
+		ListingType: models.ListingTypeBundle,
+		Price:       price,
+		Status:      "ACTIVE",
+		ExpiresAt:   time.Now().Add(s.listingDuration()),
+	}
+
+	err := s.store.Transaction(func(tx repository.Store) error {
+		if err := tx.Listings().Create(&listing); err != nil {
+			return err
+		}
+		for _, a := range assets {
+			if err := s.lockAssetTx(tx, userID, a); err != nil {
+				return err
+			}
+			item := models.MarketplaceAsset{ListingID: listing.ID, AssetType: a.AssetType, AssetID: a.AssetID}
+			if err := tx.Listings().AddBundleAsset(&item); err != nil {
+				return err
+			}
+			listing.BundleItems = append(listing.BundleItems, item)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &listing, nil
+}
+
+// moveBundle transfers every asset in a bundle; any asset the seller no longer owns aborts the sale
+func (s *MarketplaceService) moveBundle(tx repository.Store, listingID, fromUserID, toUserID uint) error {
+	assets, err := tx.Listings().BundleAssets(listingID)
+	if err != nil {
+		return err
+	}
+	if len(assets) == 0 {
+		return errors.New("bundle is empty")
+	}
+	for _, a := range assets {
+		if err := s.moveAssetTx(tx, AssetRef{AssetType: a.AssetType, AssetID: a.AssetID}, fromUserID, toUserID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// releaseBundle clears the listed flag on every asset of a cancelled or expired bundle
+func (s *MarketplaceService) releaseBundle(tx repository.Store, listingID uint) error {
+	assets, err := tx.Listings().BundleAssets(listingID)
+	if err != nil {
+		return err
+	}
+	for _, a := range assets {
+		if err := s.releaseAssetTx(tx, AssetRef{AssetType: a.AssetType, AssetID: a.AssetID}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
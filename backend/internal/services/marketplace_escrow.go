@@ -0,0 +1,172 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/internal/repository"
+)
+
+// AssetRef identifies a character or item in auction, offer and bundle requests
+type AssetRef struct {
+	AssetType string `json:"asset_type" binding:"required"` // character, item, equipment
+	AssetID   uint   `json:"asset_id" binding:"required"`
+}
+
+// assetRepo maps a marketplace asset type to its repository
+func assetRepo(st repository.Store, assetType string) (repository.AssetRepository, error) {
+	switch assetType {
+	case "character":
+		return st.Characters(), nil
+	case "item", "equipment":
+		return st.Items(), nil
+	}
+	return nil, fmt.Errorf("unsupported asset type: %s", assetType)
+}
+
+// lockAssetTx marks an owned, unlisted asset as listed so it cannot be sold twice.
+// Assets still cooling off after a player trade cannot be listed.
+func (s *MarketplaceService) lockAssetTx(tx repository.Store, ownerID uint, ref AssetRef) error {
+	assets, err := assetRepo(tx, ref.AssetType)
+	if err != nil {
+		return err
+	}
+	if err := checkTradeCooloff(tx, s.config, ref); err != nil {
+		return err
+	}
+	err = assets.MarkListed(ref.AssetID, ownerID, time.Now())
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%s %d is not yours, already listed or equipped", ref.AssetType, ref.AssetID)
+	}
+	return err
+}
+
+// releaseAssetTx clears the listed flag when a listing ends without a sale
+func (s *MarketplaceService) releaseAssetTx(tx repository.Store, ref AssetRef) error {
+	assets, err := assetRepo(tx, ref.AssetType)
+	if err != nil {
+		return err
+	}
+	return assets.SetListed(ref.AssetID, false)
+}
+
+// moveAssetTx hands an asset from seller to buyer, failing if the seller no longer owns it
+func (s *MarketplaceService) moveAssetTx(tx repository.Store, ref AssetRef, fromUserID, toUserID uint) error {
+	assets, err := assetRepo(tx, ref.AssetType)
+	if err != nil {
+		return err
+	}
+	err = assets.Transfer(ref.AssetID, fromUserID, toUserID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%s %d is no longer owned by the seller", ref.AssetType, ref.AssetID)
+	}
+	if err != nil {
+		return err
+	}
+	if ref.AssetType == "character" {
+		return recordTransfer(tx, ref.AssetID, fromUserID, toUserID, ProvenanceViaMarket)
+	}
+	return moveRunesTx(tx, ref.AssetID, fromUserID, toUserID)
+}
+
+// moveRunesTx hands the runes socketed into a piece of gear over with it
+func moveRunesTx(tx repository.Store, itemID, fromUserID, toUserID uint) error {
+	gear, err := tx.Equipment().LockForItem(itemID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	runes, err := tx.Equipment().Runes(gear.ID)
+	if err != nil {
+		return err
+	}
+	for _, r := range runes {
+		if err := tx.Items().Transfer(r.RuneItemID, fromUserID, toUserID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lockFundsTx moves GTK from a player's wallet into ESCROW
+func (s *MarketplaceService) lockFundsTx(tx repository.Store, userID uint, amount int64, refID, desc string) error {
+	ledger := s.ledger.WithStore(tx)
+	userAcc, err := ledger.GetOrCreateAccount(&userID, models.AccountTypeWallet, "GTK")
+	if err != nil {
+		return err
+	}
+	if userAcc.Balance < amount {
+		return errors.New("insufficient GTK balance")
+	}
+	escrowAcc, err := ledger.GetOrCreateAccount(nil, models.AccountTypeEscrow, "GTK")
+	if err != nil {
+		return err
+	}
+	entries := []models.LedgerEntry{
+		{AccountID: userAcc.ID, Amount: -amount, Type: "DEBIT"},
+		{AccountID: escrowAcc.ID, Amount: amount, Type: "CREDIT"},
+	}
+	return ledger.CreateTransaction(models.TxTypeMarketEscrow, refID, desc, entries)
+}
+
+// refundFundsTx returns escrowed GTK to a player's wallet
+func (s *MarketplaceService) refundFundsTx(tx repository.Store, userID uint, amount int64, refID, desc string) error {
+	ledger := s.ledger.WithStore(tx)
+	userAcc, err := ledger.GetOrCreateAccount(&userID, models.AccountTypeWallet, "GTK")
+	if err != nil {
+		return err
+	}
+	escrowAcc, err := ledger.GetOrCreateAccount(nil, models.AccountTypeEscrow, "GTK")
+	if err != nil {
+		return err
+	}
+	entries := []models.LedgerEntry{
+		{AccountID: escrowAcc.ID, Amount: -amount, Type: "DEBIT"},
+		{AccountID: userAcc.ID, Amount: amount, Type: "CREDIT"},
+	}
+	return ledger.CreateTransaction(models.TxTypeMarketRefund, refID, desc, entries)
+}
+
+// payoutFromEscrowTx pays the seller from ESCROW, keeping the marketplace fee in the TREASURY
+func (s *MarketplaceService) payoutFromEscrowTx(tx repository.Store, sellerID uint, amount int64, refID, desc string) error {
+	ledger := s.ledger.WithStore(tx)
+	fee := amount * int64(s.config.GetInt("marketplace_fee_percent", 3)) / 100
+	sellerAcc, err := ledger.GetOrCreateAccount(&sellerID, models.AccountTypeWallet, "GTK")
+	if err != nil {
+		return err
+	}
+	escrowAcc, err := ledger.GetOrCreateAccount(nil, models.AccountTypeEscrow, "GTK")
+	if err != nil {
+		return err
+	}
+	treasuryAcc, err := ledger.GetOrCreateAccount(nil, models.AccountTypeTreasury, "GTK")
+	if err != nil {
+		return err
+	}
+	entries := []models.LedgerEntry{
+		{AccountID: escrowAcc.ID, Amount: -amount, Type: "DEBIT"},
+		{AccountID: sellerAcc.ID, Amount: amount - fee, Type: "CREDIT"},
+		{AccountID: treasuryAcc.ID, Amount: fee, Type: "CREDIT"},
+	}
+	return ledger.CreateTransaction(models.TxTypeMarketSell, refID, desc, entries)
+}
+
+// listingAssetRef returns the single asset of a FIXED or AUCTION listing
+func listingAssetRef(listing *models.MarketplaceListing) AssetRef {
+	ref := AssetRef{AssetType: listing.AssetType}
+	if listing.CharacterID != nil {
+		ref.AssetID = *listing.CharacterID
+	} else if listing.ItemID != nil {
+		ref.AssetID = *listing.ItemID
+	}
+	return ref
+}
+
+// listingDuration is how long fixed-price and bundle listings stay up
+func (s *MarketplaceService) listingDuration() time.Duration {
+	return time.Duration(s.config.GetInt("marketplace_listing_days", 7)) * 24 * time.Hour
+}
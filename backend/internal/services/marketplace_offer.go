@@ -0,0 +1,237 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/internal/repository"
+)
+
+// MakeOffer bids on any character or item another player owns, listed or not. The amount is locked in escrow.
+func (s *MarketplaceService) MakeOffer(buyerID uint, ref AssetRef, amount int64, message string, expiresHours int) (*models.MarketplaceOffer, error) {
+	if amount <= 0 {
+		return nil, errors.New("offer amount must be positive")
+	}
+	if len(message) > 140 {
+		return nil, errors.New("message too long (max 140 characters)")
+	}
+	maxHours := s.config.GetInt("marketplace_offer_max_hours", 168)
+	if expiresHours == 0 {
+		expiresHours = s.config.GetInt("marketplace_offer_default_hours", 48)
+	}
+	if expiresHours < 1 || expiresHours > maxHours {
+		return nil, fmt.Errorf("offer expiry must be between 1 and %d hours", maxHours)
+	}
+
+	ownerID, err := s.assetOwner(ref)
+	if err != nil {
+		return nil, err
+	}
+	if ownerID == buyerID {
+		return nil, errors.New("cannot make an offer on your own asset")
+	}
+
+	pending, err := s.store.Listings().CountPendingOffers(buyerID, ref.AssetType, ref.AssetID)
+	if err != nil {
+		return nil, err
+	}
+	if pending > 0 {
+		return nil, errors.New("you already have a pending offer on this asset")
+	}
+
+	offer := models.MarketplaceOffer{
+		BuyerID:   buyerID,
+		SellerID:  ownerID,
+		AssetType: ref.AssetType,
+		AssetID:   ref.AssetID,
+		Amount:    amount,
+		Message:   message,
+		Status:    "PENDING",
+		ExpiresAt: time.Now().Add(time.Duration(expiresHours) * time.Hour),
+	}
+
+	err = s.store.Transaction(func(tx repository.Store) error {
+		if err := tx.Listings().CreateOffer(&offer); err != nil {
+			return err
+		}
+		return s.lockFundsTx(tx, buyerID, amount, fmt.Sprintf("offer_%d", offer.ID), fmt.Sprintf("Offer escrow: %s #%d", ref.AssetType, ref.AssetID))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notify(ownerID, "MARKET_OFFER", "New offer received",
+		fmt.Sprintf("You received an offer of %d GTK for your %s", amount, ref.AssetType),
+		map[string]interface{}{"offer_id": offer.ID, "asset_type": ref.AssetType, "asset_id": ref.AssetID, "amount": amount})
+	return &offer, nil
+}
+
+// AcceptOffer sells the asset to the buyer. An active fixed-price listing for the asset is cancelled;
+// assets in an auction or bundle must be delisted first. Competing offers on the asset are refunded.
+func (s *MarketplaceService) AcceptOffer(sellerID, offerID uint) (*models.MarketplaceOffer, error) {
+	var offer *models.MarketplaceOffer
+
+	err := s.store.Transaction(func(tx repository.Store) error {
+		var err error
+		if offer, err = tx.Listings().LockOffer(offerID); err != nil {
+			return errors.New("offer not found")
+		}
+		if offer.SellerID != sellerID {
+			return errors.New("offer not found")
+		}
+		if offer.Status != "PENDING" {
+			return errors.New("offer is no longer pending")
+		}
+		if time.Now().After(offer.ExpiresAt) {
+			return errors.New("offer has expired")
+		}
+		ref := AssetRef{AssetType: offer.AssetType, AssetID: offer.AssetID}
+		if err := checkTradeCooloff(tx, s.config, ref); err != nil {
+			return err
+		}
+
+		if err := s.delistForOfferTx(tx, sellerID, ref); err != nil {
+			return err
+		}
+		if err := s.moveAssetTx(tx, ref, sellerID, offer.BuyerID); err != nil {
+			return err
+		}
+		if err := s.payoutFromEscrowTx(tx, sellerID, offer.Amount, fmt.Sprintf("offer_%d_sale", offer.ID),
+			fmt.Sprintf("Offer accepted: %s #%d", ref.AssetType, ref.AssetID)); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		offer.Status = "ACCEPTED"
+		offer.RespondedAt = &now
+		if err := tx.Listings().SaveOffer(offer); err != nil {
+			return err
+		}
+
+		if err := tx.Listings().CreateTrade(&models.TradeHistory{
+			SellerID: sellerID,
+			BuyerID:  offer.BuyerID,
+			ItemType: offer.AssetType,
+			ItemID:   offer.AssetID,
+			Price:    offer.Amount,
+			Currency: "GTK",
+		}); err != nil {
+			return err
+		}
+
+		// The asset changed hands: every other pending offer on it is void
+		others, err := tx.Listings().LockPendingOffers(offer.AssetType, offer.AssetID, offer.ID)
+		if err != nil {
+			return err
+		}
+		for i := range others {
+			if err := s.closeOfferTx(tx, &others[i], "CANCELLED"); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notify(offer.BuyerID, "MARKET_OFFER_ACCEPTED", "Offer accepted",
+		fmt.Sprintf("Your offer of %d GTK was accepted", offer.Amount),
+		map[string]interface{}{"offer_id": offer.ID, "asset_type": offer.AssetType, "asset_id": offer.AssetID})
+	return offer, nil
+}
+
+// RejectOffer lets the owner turn an offer down; the buyer is refunded
+func (s *MarketplaceService) RejectOffer(sellerID, offerID uint) error {
+	return s.respondOffer(offerID, func(o *models.MarketplaceOffer) bool { return o.SellerID == sellerID }, "REJECTED")
+}
+
+// CancelOffer lets the buyer withdraw an offer; the escrowed amount is refunded
+func (s *MarketplaceService) CancelOffer(buyerID, offerID uint) error {
+	return s.respondOffer(offerID, func(o *models.MarketplaceOffer) bool { return o.BuyerID == buyerID }, "CANCELLED")
+}
+
+// ListOffers returns offers the user received (direction=received) or made (direction=sent)
+func (s *MarketplaceService) ListOffers(userID uint, direction, status string) ([]models.MarketplaceOffer, error) {
+	return s.store.Listings().ListOffers(userID, direction == "sent", status)
+}
+
+func (s *MarketplaceService) respondOffer(offerID uint, allowed func(*models.MarketplaceOffer) bool, status string) error {
+	return s.store.Transaction(func(tx repository.Store) error {
+		offer, err := tx.Listings().LockOffer(offerID)
+		if err != nil {
+			return errors.New("offer not found")
+		}
+		if !allowed(offer) {
+			return errors.New("offer not found")
+		}
+		if offer.Status != "PENDING" {
+			return errors.New("offer is no longer pending")
+		}
+		return s.closeOfferTx(tx, offer, status)
+	})
+}
+
+// closeOfferTx refunds the buyer and sets a terminal status
+func (s *MarketplaceService) closeOfferTx(tx repository.Store, offer *models.MarketplaceOffer, status string) error {
+	if err := s.refundFundsTx(tx, offer.BuyerID, offer.Amount, fmt.Sprintf("offer_%d_refund", offer.ID),
+		fmt.Sprintf("Offer %s: %s #%d", status, offer.AssetType, offer.AssetID)); err != nil {
+		return err
+	}
+	now := time.Now()
+	offer.Status = status
+	offer.RespondedAt = &now
+	return tx.Listings().SaveOffer(offer)
+}
+
+// delistForOfferTx cancels a fixed-price listing of the asset so the offer sale can go through
+func (s *MarketplaceService) delistForOfferTx(tx repository.Store, sellerID uint, ref AssetRef) error {
+	listings, err := tx.Listings().ActiveForAsset(ref.AssetType, ref.AssetID)
+	if err != nil {
+		return err
+	}
+	for i := range listings {
+		if listings[i].SellerID != sellerID {
+			continue
+		}
+		if listings[i].ListingType == models.ListingTypeAuction {
+			return errors.New("asset is in an active auction")
+		}
+		listings[i].Status = "CANCELLED"
+		if err := tx.Listings().Save(&listings[i]); err != nil {
+			return err
+		}
+	}
+
+	inBundle, err := tx.Listings().InActiveBundle(sameAssetTypes(ref.AssetType), ref.AssetID)
+	if err != nil {
+		return err
+	}
+	if inBundle {
+		return errors.New("asset is part of an active bundle; cancel the bundle first")
+	}
+	return nil
+}
+
+// assetOwner returns the current owner of a character or item
+func (s *MarketplaceService) assetOwner(ref AssetRef) (uint, error) {
+	assets, err := assetRepo(s.store, ref.AssetType)
+	if err != nil {
+		return 0, err
+	}
+	ownerID, err := assets.Owner(ref.AssetID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return 0, fmt.Errorf("%s not found", ref.AssetType)
+	}
+	return ownerID, err
+}
+
+// sameAssetTypes treats "item" and "equipment" as the same table
+func sameAssetTypes(assetType string) []string {
+	if assetType == "character" {
+		return []string{"character"}
+	}
+	return []string{"item", "equipment"}
+}
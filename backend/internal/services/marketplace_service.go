@@ -3,24 +3,33 @@ package services
 import (
 	"errors"
 	"fmt"
+	"log/slog"
 	"time"
 
-	"github.com/lorengraff/crypto-tower-defense/internal/db"
 	"github.com/lorengraff/crypto-tower-defense/internal/models"
-	"gorm.io/gorm"
-	"gorm.io/gorm/clause"
+	"github.com/lorengraff/crypto-tower-defense/internal/repository"
 )
 
+// Notifier delivers in-app notifications (NotificationService)
+type Notifier interface {
+	CreateNotification(userID uint, notifType, title, message string, data interface{}) error
+}
+
 // MarketplaceService handles marketplace operations
 type MarketplaceService struct {
-	ledger *LedgerService // Ledger Integration
+	store    repository.Store
+	ledger   *LedgerService // Ledger Integration
+	config   Settings
+	notifier Notifier
 }
 
-// NewMarketplaceService creates a new service (helper internal)
-// Ideally this should be a proper constructor called in main.go, but for now we follow the pattern
-func (s *MarketplaceService) init() {
-	if s.ledger == nil {
-		s.ledger = NewLedgerService()
+// NewMarketplaceService creates the marketplace service (fixed price, auctions, offers, bundles)
+func NewMarketplaceService(store repository.Store, ledger *LedgerService, config Settings, notifier Notifier) *MarketplaceService {
+	return &MarketplaceService{
+		store:    store,
+		ledger:   ledger,
+		config:   config,
+		notifier: notifier,
 	}
 }
 
@@ -31,27 +40,13 @@ func (s *MarketplaceService) CreateListing(userID uint, itemType string, itemID
 		return nil, err
 	}
 
-	// Check for existing active listing to prevent duplicates
-	var existingListing models.MarketplaceListing
-	duplicateQuery := db.DB.Where("status = ?", "ACTIVE")
-
-	switch itemType {
-	case "character":
-		duplicateQuery = duplicateQuery.Where("character_id = ?", itemID)
-	case "item", "equipment":
-		duplicateQuery = duplicateQuery.Where("item_id = ?", itemID)
-	}
-
-	if err := duplicateQuery.First(&existingListing).Error; err == nil {
-		return nil, errors.New("this item is already listed on the marketplace")
-	}
-
-	// Create listing
 	listing := models.MarketplaceListing{
-		SellerID:  userID,
-		AssetType: itemType,
-		Price:     int64(price),
-		Status:    "ACTIVE",
+		SellerID:    userID,
+		AssetType:   itemType,
+		ListingType: models.ListingTypeFixed,
+		Price:       int64(price),
+		Status:      "ACTIVE",
+		ExpiresAt:   time.Now().Add(s.listingDuration()),
 	}
 
 	// Set appropriate ID field based on type
@@ -64,22 +59,39 @@ func (s *MarketplaceService) CreateListing(userID uint, itemType string, itemID
 		return nil, errors.New("invalid item type")
 	}
 
-	if err := db.DB.Create(&listing).Error; err != nil {
-		return nil, err
-	}
+	err := s.store.Transaction(func(tx repository.Store) error {
+		// Check for existing active listing to prevent duplicates
+		existing, err := tx.Listings().ActiveForAsset(itemType, itemID)
+		if err != nil {
+			return err
+		}
+		if len(existing) > 0 {
+			return errors.New("this item is already listed on the marketplace")
+		}
 
-	// Mark item as listed (prevent double listing)
-	s.markItemAsListed(itemType, itemID, true)
+		ref := AssetRef{AssetType: itemType, AssetID: itemID}
+		if err := checkTradeCooloff(tx, s.config, ref); err != nil {
+			return err
+		}
 
+		// Mark item as listed (prevent double listing)
+		if err := s.lockAssetTx(tx, userID, ref); err != nil {
+			return errors.New("this item is already listed on the marketplace")
+		}
+		return tx.Listings().Create(&listing)
+	})
+	if err != nil {
+		return nil, err
+	}
 	return &listing, nil
 }
 
 // BuyListing purchases an item from marketplace
 func (s *MarketplaceService) BuyListing(buyerID, listingID uint) error {
-	return db.DB.Transaction(func(tx *gorm.DB) error {
-		var listing models.MarketplaceListing
+	return s.store.Transaction(func(tx repository.Store) error {
 		// 1. Lock listing row to prevent double-buy race conditions
-		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&listing, listingID).Error; err != nil {
+		listing, err := tx.Listings().Lock(listingID)
+		if err != nil {
 			return err
 		}
 
@@ -91,9 +103,17 @@ func (s *MarketplaceService) BuyListing(buyerID, listingID uint) error {
 			return errors.New("cannot buy your own listing")
 		}
 
+		if listing.ListingType == models.ListingTypeAuction {
+			return errors.New("auction listings must be bid on")
+		}
+
+		if time.Now().After(listing.ExpiresAt) {
+			return errors.New("listing has expired")
+		}
+
 		// 2. Check buyer funds
-		var buyer models.User
-		if err := tx.First(&buyer, buyerID).Error; err != nil {
+		buyer, err := tx.Users().Get(buyerID)
+		if err != nil {
 			return err
 		}
 
@@ -106,20 +126,16 @@ func (s *MarketplaceService) BuyListing(buyerID, listingID uint) error {
 		feeAmount := int64(float64(listing.Price) * feePercent)
 		sellerAmount := listing.Price - feeAmount
 
-		if s.ledger == nil {
-			s.ledger = NewLedgerService()
-		}
-
-		// Fetch accounts using global DB (Locking not strictly needed for fetch, only updates)
-		buyerAcc, err := s.ledger.GetOrCreateAccount(&buyerID, models.AccountTypeWallet, "GTK")
+		ledger := s.ledger.WithStore(tx)
+		buyerAcc, err := ledger.GetOrCreateAccount(&buyerID, models.AccountTypeWallet, "GTK")
 		if err != nil {
 			return err
 		}
-		sellerAcc, err := s.ledger.GetOrCreateAccount(&listing.SellerID, models.AccountTypeWallet, "GTK")
+		sellerAcc, err := ledger.GetOrCreateAccount(&listing.SellerID, models.AccountTypeWallet, "GTK")
 		if err != nil {
 			return err
 		}
-		treasuryAcc, err := s.ledger.GetOrCreateAccount(nil, models.AccountTypeTreasury, "GTK")
+		treasuryAcc, err := ledger.GetOrCreateAccount(nil, models.AccountTypeTreasury, "GTK")
 		if err != nil {
 			return err
 		}
@@ -130,39 +146,31 @@ func (s *MarketplaceService) BuyListing(buyerID, listingID uint) error {
 			{AccountID: treasuryAcc.ID, Amount: feeAmount, Type: "CREDIT"},
 		}
 
-		// Use the transaction-aware ledger method
-		if err := s.ledger.CreateTransactionWithTx(tx, models.TxTypeMarketBuy, fmt.Sprintf("market_buy_%d", listing.ID), fmt.Sprintf("Market purchase: Listing #%d", listing.ID), entries); err != nil {
+		if err := ledger.CreateTransaction(models.TxTypeMarketBuy, fmt.Sprintf("market_buy_%d", listing.ID), fmt.Sprintf("Market purchase: Listing #%d", listing.ID), entries); err != nil {
 			return fmt.Errorf("ledger transaction failed: %v", err)
 		}
 
 		// 4. Legacy Balance Updates (DB Sync - keep in same tx)
-		if err := tx.Exec("UPDATE users SET tokens = tokens - ? WHERE id = ?", listing.Price, buyerID).Error; err != nil {
+		if err := tx.Users().AdjustLegacyTokens(buyerID, -listing.Price); err != nil {
 			return err
 		}
-		if err := tx.Exec("UPDATE users SET tokens = tokens + ? WHERE id = ?", sellerAmount, listing.SellerID).Error; err != nil {
+		if err := tx.Users().AdjustLegacyTokens(listing.SellerID, sellerAmount); err != nil {
 			return err
 		}
 
 		// 5. Transfer Ownership & Update Status
-		itemID := uint(0)
-		if listing.CharacterID != nil {
-			itemID = *listing.CharacterID
-		} else if listing.ItemID != nil {
-			itemID = *listing.ItemID
-		}
-
-		// Update Ownership via TX
-		switch listing.AssetType {
-		case "character":
-			if err := tx.Exec("UPDATE characters SET owner_id = ?, is_listed = false WHERE id = ?", buyerID, itemID).Error; err != nil {
+		itemID := listingAssetRef(listing).AssetID
+		switch {
+		case listing.ListingType == models.ListingTypeBundle:
+			if err := s.moveBundle(tx, listing.ID, listing.SellerID, buyerID); err != nil {
 				return err
 			}
-		case "equipment", "item":
-			if err := tx.Exec("UPDATE items SET owner_id = ?, is_listed = false WHERE id = ?", buyerID, itemID).Error; err != nil {
+		case listing.AssetType == "egg":
+			if err := tx.Eggs().Transfer(itemID, listing.SellerID, buyerID); err != nil {
 				return err
 			}
-		case "egg":
-			if err := tx.Exec("UPDATE eggs SET user_id = ? WHERE id = ?", buyerID, itemID).Error; err != nil {
+		default:
+			if err := s.moveAssetTx(tx, AssetRef{AssetType: listing.AssetType, AssetID: itemID}, listing.SellerID, buyerID); err != nil {
 				return err
 			}
 		}
@@ -172,12 +180,12 @@ func (s *MarketplaceService) BuyListing(buyerID, listingID uint) error {
 		listing.Status = "SOLD"
 		listing.BuyerID = &buyerID
 		listing.SoldAt = &now
-		if err := tx.Save(&listing).Error; err != nil {
+		if err := tx.Listings().Save(listing); err != nil {
 			return err
 		}
 
 		// 6. Create Trade History
-		history := models.TradeHistory{
+		return tx.Listings().CreateTrade(&models.TradeHistory{
 			ListingID: &listing.ID,
 			SellerID:  listing.SellerID,
 			BuyerID:   buyerID,
@@ -185,113 +193,82 @@ func (s *MarketplaceService) BuyListing(buyerID, listingID uint) error {
 			ItemID:    itemID,
 			Price:     listing.Price,
 			Currency:  "TOWER",
-		}
-		if err := tx.Create(&history).Error; err != nil {
-			return err
-		}
-
-		return nil
+		})
 	})
 }
 
 // CancelListing cancels an active listing
 func (s *MarketplaceService) CancelListing(userID, listingID uint) error {
-	var listing models.MarketplaceListing
-	if err := db.DB.First(&listing, listingID).Error; err != nil {
-		return err
-	}
-
-	if listing.SellerID != userID {
-		return errors.New("not your listing")
-	}
+	return s.store.Transaction(func(tx repository.Store) error {
+		listing, err := tx.Listings().Lock(listingID)
+		if err != nil {
+			return err
+		}
 
-	if listing.Status != "ACTIVE" {
-		return errors.New("listing is not active")
-	}
+		if listing.SellerID != userID {
+			return errors.New("not your listing")
+		}
 
-	listing.Status = "CANCELLED"
-	db.DB.Save(&listing)
+		if listing.Status != "ACTIVE" {
+			return errors.New("listing is not active")
+		}
 
-	// Get item ID
-	var itemID uint
-	if listing.CharacterID != nil {
-		itemID = *listing.CharacterID
-	} else if listing.ItemID != nil {
-		itemID = *listing.ItemID
-	}
+		if listing.ListingType == models.ListingTypeAuction && listing.BidCount > 0 {
+			return errors.New("cannot cancel an auction that has bids")
+		}
 
-	// Unmark item as listed
-	s.markItemAsListed(listing.AssetType, itemID, false)
+		listing.Status = "CANCELLED"
+		if err := tx.Listings().Save(listing); err != nil {
+			return err
+		}
 
-	return nil
+		if listing.ListingType == models.ListingTypeBundle {
+			return s.releaseBundle(tx, listing.ID)
+		}
+		// Unmark item as listed
+		if ref := listingAssetRef(listing); ref.AssetID != 0 {
+			return s.releaseAssetTx(tx, ref)
+		}
+		return nil
+	})
 }
 
 // GetActiveListings returns all active marketplace listings with full character data
-func (s *MarketplaceService) GetActiveListings(itemType string, limit, offset int) ([]models.MarketplaceListing, error) {
-	var listings []models.MarketplaceListing
-	query := db.DB.Preload("Character", func(db *gorm.DB) *gorm.DB {
-		return db.Unscoped()
-	}).Preload("Item").Preload("Seller", func(db *gorm.DB) *gorm.DB {
-		return db.Unscoped()
-	}).Where("status = ?", "ACTIVE")
-
-	// Simple query: get all active listings
+func (s *MarketplaceService) GetActiveListings(itemType, listingType string, limit, offset int) ([]models.MarketplaceListing, error) {
 	// No hidden filtering, no deduplication. Shows exactly what is in the DB.
-	if itemType != "" {
-		query = query.Where("asset_type = ?", itemType)
-	}
-
-	if err := query.Limit(limit).Offset(offset).Order("created_at DESC").Find(&listings).Error; err != nil {
-		return nil, err
-	}
-
-	// MANUAL RECOVERY for Soft-Deleted Data (Fix for 'Unknown' assets)
-	for i := range listings {
-		// Recover Character
-		if (listings[i].AssetType == "character" || listings[i].AssetType == "Character") && listings[i].Character == nil && listings[i].CharacterID != nil {
-			var char models.Character
-			if err := db.DB.Unscoped().First(&char, *listings[i].CharacterID).Error; err == nil {
-				listings[i].Character = &char
-				fmt.Printf("♻️ RECOVERED character for Listing %d: %s\n", listings[i].ID, char.Name)
-			} else {
-				fmt.Printf("❌ FAILED to recover character %d: %v\n", *listings[i].CharacterID, err)
-			}
-		}
-
-		// Recover Seller
-		if listings[i].Seller.ID == 0 && listings[i].SellerID > 0 {
-			var seller models.User
-			if err := db.DB.Unscoped().First(&seller, listings[i].SellerID).Error; err == nil {
-				listings[i].Seller = seller
-			}
-		}
-	}
-
-	return listings, nil
+	return s.store.Listings().ListActive(repository.ListingFilter{
+		AssetType:   itemType,
+		ListingType: listingType,
+		Limit:       limit,
+		Offset:      offset,
+	})
 }
 
 // verifyOwnership checks if user owns the item
 func (s *MarketplaceService) verifyOwnership(userID uint, itemType string, itemID uint) error {
 	switch itemType {
 	case "character":
-		var char models.Character
-		if err := db.DB.First(&char, itemID).Error; err != nil {
+		char, err := s.store.Characters().Get(itemID)
+		if err != nil {
 			return err
 		}
 		if char.OwnerID != userID {
 			return errors.New("you don't own this character")
 		}
+		if char.ControllerID != nil {
+			return errors.New("cannot list a rented character")
+		}
 	case "equipment", "item":
-		var item models.Item
-		if err := db.DB.First(&item, itemID).Error; err != nil {
+		item, err := s.store.Items().Get(itemID)
+		if err != nil {
 			return err
 		}
 		if item.OwnerID != userID {
 			return errors.New("you don't own this item")
 		}
 	case "egg":
-		var egg models.Egg
-		if err := db.DB.First(&egg, itemID).Error; err != nil {
+		egg, err := s.store.Eggs().Get(itemID)
+		if err != nil {
 			return err
 		}
 		if egg.UserID != userID {
@@ -301,31 +278,80 @@ func (s *MarketplaceService) verifyOwnership(userID uint, itemType string, itemI
 	return nil
 }
 
-// transferOwnership transfers item to new owner
-func (s *MarketplaceService) transferOwnership(itemType string, itemID, fromUserID, toUserID uint) {
-	// Verify ownership before transfer (security check)
-	if err := s.verifyOwnership(fromUserID, itemType, itemID); err != nil {
-		// Log error but don't fail the transaction (already verified earlier)
-		// This is a double-check for security
-		return
+// ProcessExpired closes everything past its deadline: fixed and bundle listings return their assets,
+// auctions settle (or refund when the reserve is unmet) and pending offers release their escrow
+func (s *MarketplaceService) ProcessExpired() error {
+	now := time.Now()
+
+	listings, err := s.store.Listings().ListExpired(now)
+	if err != nil {
+		return err
 	}
+	for _, l := range listings {
+		err := s.store.Transaction(func(tx repository.Store) error {
+			listing, err := tx.Listings().Lock(l.ID)
+			if err != nil {
+				return err
+			}
+			// Anti-sniping may have pushed the end back since the scan
+			if listing.Status != "ACTIVE" || listing.ExpiresAt.After(time.Now()) {
+				return nil
+			}
 
-	switch itemType {
-	case "character":
-		db.DB.Exec("UPDATE characters SET owner_id = ? WHERE id = ? AND owner_id = ?", toUserID, itemID, fromUserID)
-	case "equipment", "item":
-		db.DB.Exec("UPDATE items SET owner_id = ? WHERE id = ? AND owner_id = ?", toUserID, itemID, fromUserID)
-	case "egg":
-		db.DB.Exec("UPDATE eggs SET user_id = ? WHERE id = ? AND user_id = ?", toUserID, itemID, fromUserID)
+			switch listing.ListingType {
+			case models.ListingTypeAuction:
+				return s.settleAuctionTx(tx, listing)
+			case models.ListingTypeBundle:
+				if err := s.releaseBundle(tx, listing.ID); err != nil {
+					return err
+				}
+			default:
+				ref := listingAssetRef(listing)
+				if ref.AssetID != 0 {
+					if err := s.releaseAssetTx(tx, ref); err != nil {
+						return err
+					}
+				}
+			}
+			listing.Status = "EXPIRED"
+			return tx.Listings().Save(listing)
+		})
+		if err != nil {
+			slog.Error("failed to expire listing", "listing_id", l.ID, "error", err)
+		}
 	}
-}
 
-// markItemAsListed marks item as listed/unlisted
-func (s *MarketplaceService) markItemAsListed(itemType string, itemID uint, listed bool) {
-	switch itemType {
-	case "character":
-		db.DB.Exec("UPDATE characters SET is_listed = ? WHERE id = ?", listed, itemID)
-	case "equipment", "item":
-		db.DB.Exec("UPDATE items SET is_listed = ? WHERE id = ?", listed, itemID)
+	offers, err := s.store.Listings().ListExpiredOffers(now)
+	if err != nil {
+		return err
+	}
+	for _, o := range offers {
+		err := s.store.Transaction(func(tx repository.Store) error {
+			offer, err := tx.Listings().LockOffer(o.ID)
+			if err != nil {
+				return err
+			}
+			if offer.Status != "PENDING" {
+				return nil
+			}
+			return s.closeOfferTx(tx, offer, "EXPIRED")
+		})
+		if err != nil {
+			slog.Error("failed to expire offer", "offer_id", o.ID, "error", err)
+		}
 	}
+	return nil
+}
+
+// StartScheduler runs ProcessExpired on a fixed interval in the background
+func (s *MarketplaceService) StartScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := s.ProcessExpired(); err != nil {
+				slog.Error("marketplace expiry failed", "error", err)
+			}
+		}
+	}()
 }
@@ -0,0 +1,262 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/internal/repository"
+)
+
+func newTestMarketplace(st repository.Store) (*MarketplaceService, *LedgerService, *testNotifier) {
+	ledger := NewLedgerService(st)
+	notifier := &testNotifier{}
+	return NewMarketplaceService(st, ledger, testSettings{}, notifier), ledger, notifier
+}
+
+// newTestTrader creates a funded user (ledger wallet and legacy GTK balance)
+func newTestTrader(t *testing.T, st repository.Store, ledger *LedgerService, gtk int64) *models.User {
+	t.Helper()
+	u := newTestUser(t, st, 1000)
+	if gtk > 0 {
+		u.GTKBalance = gtk
+		if err := st.Users().Save(u); err != nil {
+			t.Fatal(err)
+		}
+		fund(t, ledger, u.ID, gtk)
+	}
+	return u
+}
+
+// expire moves a listing's deadline into the past
+func expire(t *testing.T, st repository.Store, listingID uint) {
+	t.Helper()
+	l, err := st.Listings().Get(listingID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.ExpiresAt = time.Now().Add(-time.Minute)
+	if err := st.Listings().Save(l); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuyFixedListingTransfersAssetAndPaysSeller(t *testing.T) {
+	st := repository.NewMemoryStore()
+	market, ledger, _ := newTestMarketplace(st)
+	seller := newTestTrader(t, st, ledger, 0)
+	buyer := newTestTrader(t, st, ledger, 1000)
+	char := newTestCharacter(t, st, seller.ID, 100, 50)
+
+	listing, err := market.CreateListing(seller.ID, "character", char.ID, 200, "GTK")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := market.CreateListing(seller.ID, "character", char.ID, 300, "GTK"); err == nil {
+		t.Fatal("listed the same character twice")
+	}
+	if err := market.BuyListing(seller.ID, listing.ID); err == nil {
+		t.Fatal("seller bought their own listing")
+	}
+
+	if err := market.BuyListing(buyer.ID, listing.ID); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := st.Characters().Get(char.ID)
+	if got.OwnerID != buyer.ID || got.IsListed {
+		t.Fatalf("character owner %d listed %v, want buyer and unlisted", got.OwnerID, got.IsListed)
+	}
+	if b := balance(t, ledger, &buyer.ID, models.AccountTypeWallet); b != 800 {
+		t.Fatalf("buyer balance = %d, want 800", b)
+	}
+	if b := balance(t, ledger, &seller.ID, models.AccountTypeWallet); b != 190 {
+		t.Fatalf("seller balance = %d, want 190 after the 5%% fee", b)
+	}
+	if b := balance(t, ledger, nil, models.AccountTypeTreasury); b != 10 {
+		t.Fatalf("treasury = %d, want 10", b)
+	}
+	if err := market.BuyListing(buyer.ID, listing.ID); err == nil {
+		t.Fatal("sold listing was bought again")
+	}
+}
+
+func TestCancelListingReleasesAsset(t *testing.T) {
+	st := repository.NewMemoryStore()
+	market, ledger, _ := newTestMarketplace(st)
+	seller := newTestTrader(t, st, ledger, 0)
+	other := newTestTrader(t, st, ledger, 0)
+	char := newTestCharacter(t, st, seller.ID, 100, 50)
+
+	listing, err := market.CreateListing(seller.ID, "character", char.ID, 200, "GTK")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := market.CancelListing(other.ID, listing.ID); err == nil {
+		t.Fatal("cancelled someone else's listing")
+	}
+	if err := market.CancelListing(seller.ID, listing.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := st.Characters().Get(char.ID); got.IsListed {
+		t.Fatal("character still flagged as listed")
+	}
+	if _, err := market.CreateListing(seller.ID, "character", char.ID, 250, "GTK"); err != nil {
+		t.Fatalf("relisting after cancel: %v", err)
+	}
+}
+
+func TestAuctionRefundsOutbidAndSettlesOnExpiry(t *testing.T) {
+	st := repository.NewMemoryStore()
+	market, ledger, notifier := newTestMarketplace(st)
+	seller := newTestTrader(t, st, ledger, 0)
+	alice := newTestTrader(t, st, ledger, 500)
+	bob := newTestTrader(t, st, ledger, 500)
+	char := newTestCharacter(t, st, seller.ID, 100, 50)
+
+	auction, err := market.CreateAuction(seller.ID, AssetRef{AssetType: "character", AssetID: char.ID}, 100, 150, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := market.PlaceBid(alice.ID, auction.ID, 120); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := market.PlaceBid(bob.ID, auction.ID, 121); err == nil {
+		t.Fatal("accepted a bid below the minimum increment")
+	}
+	if _, err := market.PlaceBid(bob.ID, auction.ID, 200); err != nil {
+		t.Fatal(err)
+	}
+	if b := balance(t, ledger, &alice.ID, models.AccountTypeWallet); b != 500 {
+		t.Fatalf("outbid bidder balance = %d, want full refund", b)
+	}
+	if len(notifier.sent) != 1 || notifier.sent[0] != "AUCTION_OUTBID" {
+		t.Fatalf("notifications = %v", notifier.sent)
+	}
+
+	expire(t, st, auction.ID)
+	if err := market.ProcessExpired(); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := st.Characters().Get(char.ID); got.OwnerID != bob.ID {
+		t.Fatalf("character owner = %d, want winning bidder", got.OwnerID)
+	}
+	// Default marketplace_fee_percent is 3
+	if b := balance(t, ledger, &seller.ID, models.AccountTypeWallet); b != 194 {
+		t.Fatalf("seller balance = %d, want 194", b)
+	}
+	if b := balance(t, ledger, nil, models.AccountTypeEscrow); b != 0 {
+		t.Fatalf("escrow = %d after settlement", b)
+	}
+	if l, _ := st.Listings().Get(auction.ID); l.Status != "SOLD" {
+		t.Fatalf("auction status = %s", l.Status)
+	}
+}
+
+func TestAuctionBelowReserveReturnsAsset(t *testing.T) {
+	st := repository.NewMemoryStore()
+	market, ledger, _ := newTestMarketplace(st)
+	seller := newTestTrader(t, st, ledger, 0)
+	bidder := newTestTrader(t, st, ledger, 500)
+	char := newTestCharacter(t, st, seller.ID, 100, 50)
+
+	auction, err := market.CreateAuction(seller.ID, AssetRef{AssetType: "character", AssetID: char.ID}, 100, 300, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := market.PlaceBid(bidder.ID, auction.ID, 150); err != nil {
+		t.Fatal(err)
+	}
+	expire(t, st, auction.ID)
+	if err := market.ProcessExpired(); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := st.Characters().Get(char.ID)
+	if got.OwnerID != seller.ID || got.IsListed {
+		t.Fatalf("character owner %d listed %v, want returned to seller", got.OwnerID, got.IsListed)
+	}
+	if b := balance(t, ledger, &bidder.ID, models.AccountTypeWallet); b != 500 {
+		t.Fatalf("bidder balance = %d, want refund", b)
+	}
+}
+
+func TestAcceptOfferDelistsAndRefundsCompetingOffers(t *testing.T) {
+	st := repository.NewMemoryStore()
+	market, ledger, _ := newTestMarketplace(st)
+	seller := newTestTrader(t, st, ledger, 0)
+	alice := newTestTrader(t, st, ledger, 500)
+	bob := newTestTrader(t, st, ledger, 500)
+	char := newTestCharacter(t, st, seller.ID, 100, 50)
+	ref := AssetRef{AssetType: "character", AssetID: char.ID}
+
+	listing, err := market.CreateListing(seller.ID, "character", char.ID, 1000, "GTK")
+	if err != nil {
+		t.Fatal(err)
+	}
+	winning, err := market.MakeOffer(alice.ID, ref, 300, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	losing, err := market.MakeOffer(bob.ID, ref, 250, "please", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := market.MakeOffer(bob.ID, ref, 260, "", 0); err == nil {
+		t.Fatal("second pending offer on the same asset was accepted")
+	}
+	if b := balance(t, ledger, &bob.ID, models.AccountTypeWallet); b != 250 {
+		t.Fatalf("offer not escrowed: bob balance = %d", b)
+	}
+
+	if _, err := market.AcceptOffer(bob.ID, winning.ID); err == nil {
+		t.Fatal("non-owner accepted an offer")
+	}
+	if _, err := market.AcceptOffer(seller.ID, winning.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := st.Characters().Get(char.ID); got.OwnerID != alice.ID {
+		t.Fatalf("character owner = %d, want alice", got.OwnerID)
+	}
+	if l, _ := st.Listings().Get(listing.ID); l.Status != "CANCELLED" {
+		t.Fatalf("fixed listing status = %s, want CANCELLED", l.Status)
+	}
+	if b := balance(t, ledger, &bob.ID, models.AccountTypeWallet); b != 500 {
+		t.Fatalf("competing offer not refunded: bob balance = %d", b)
+	}
+	if o, _ := st.Listings().LockOffer(losing.ID); o.Status != "CANCELLED" {
+		t.Fatalf("competing offer status = %s", o.Status)
+	}
+	if b := balance(t, ledger, &seller.ID, models.AccountTypeWallet); b != 291 {
+		t.Fatalf("seller balance = %d, want 291", b)
+	}
+}
+
+func TestBundleSellsAllAssetsAtomically(t *testing.T) {
+	st := repository.NewMemoryStore()
+	market, ledger, _ := newTestMarketplace(st)
+	seller := newTestTrader(t, st, ledger, 0)
+	buyer := newTestTrader(t, st, ledger, 1000)
+	sword := &models.Item{OwnerID: seller.ID}
+	shield := &models.Item{OwnerID: seller.ID}
+	for _, item := range []*models.Item{sword, shield} {
+		if err := st.Items().Create(item); err != nil {
+			t.Fatal(err)
+		}
+	}
+	assets := []AssetRef{{AssetType: "item", AssetID: sword.ID}, {AssetType: "equipment", AssetID: shield.ID}}
+
+	if _, err := market.CreateBundleListing(seller.ID, []AssetRef{assets[0], assets[0]}, 100); err == nil {
+		t.Fatal("bundle with a duplicate asset was accepted")
+	}
+	bundle, err := market.CreateBundleListing(seller.ID, assets, 400)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := market.BuyListing(buyer.ID, bundle.ID); err != nil {
+		t.Fatal(err)
+	}
+	for _, ref := range assets {
+		if got, _ := st.Items().Get(ref.AssetID); got.OwnerID != buyer.ID || got.IsListed {
+			t.Fatalf("item %d owner %d listed %v, want buyer and unlisted", ref.AssetID, got.OwnerID, got.IsListed)
+		}
+	}
+}
@@ -262,44 +262,22 @@ func (s *MissionService) CompleteMission(userID, missionID uint) (*models.Missio
 			"total_missions_completed": gorm.Expr("total_missions_completed + 1"),
 		})
 
-	// Unlock feature if applicable
-	if progress.Mission.UnlockFeature != "" {
-		var user models.User
-		tx.First(&user, userID)
-
-		var unlocked []string
-		json.Unmarshal([]byte(""), &unlocked)
-		unlocked = append(unlocked, progress.Mission.UnlockFeature)
-		unlockedJSON, _ := json.Marshal(unlocked)
-
-		tx.Model(&models.User{}).
-			Where("id = ?", userID).
-			Update("unlocked_features", string(unlockedJSON))
-	}
-
 	tx.Commit()
 	return &rewards, nil
 }
 
-// CheckFeatureUnlocked checks if a feature is unlocked for user
+// CheckFeatureUnlocked checks if a feature is unlocked for user: the mission that
+// unlocks it has been completed
 func (s *MissionService) CheckFeatureUnlocked(userID uint, feature string) (bool, error) {
-	var user models.User
-	if err := s.db.First(&user, userID).Error; err != nil {
+	var count int64
+	err := s.db.Model(&models.UserMissionProgress{}).
+		Joins("JOIN missions ON missions.id = user_mission_progress.mission_id").
+		Where("user_mission_progress.user_id = ? AND user_mission_progress.status = 'completed' AND missions.unlock_feature = ?", userID, feature).
+		Count(&count).Error
+	if err != nil {
 		return false, err
 	}
-
-	var unlocked []string
-	if err := json.Unmarshal([]byte(""), &unlocked); err != nil {
-		return false, nil // Default to locked if can't parse
-	}
-
-	for _, f := range unlocked {
-		if f == feature {
-			return true, nil
-		}
-	}
-
-	return false, nil
+	return count > 0, nil
 }
 
 // Event hooks for automatic progress tracking
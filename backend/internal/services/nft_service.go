@@ -12,6 +12,7 @@ import (
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/lorengraff/crypto-tower-defense/internal/db"
 	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/internal/repository"
 	"github.com/lorengraff/crypto-tower-defense/pkg/config"
 	"gorm.io/gorm"
 )
@@ -123,6 +124,10 @@ func (s *NFTService) MintFirstCharacter(userID uint, characterID uint) error {
 		tx.Rollback()
 		return errors.New("failed to update character")
 	}
+	if err := recordNFTMint(repository.NewGormStore(tx), &character); err != nil {
+		tx.Rollback()
+		return errors.New("failed to record provenance")
+	}
 
 	// Mark user as having minted first character
 	// user.HasMintedFirstChar = true // Field removed
@@ -230,6 +235,10 @@ func (s *NFTService) MintCharacter(userID uint, characterID uint) error {
 		tx.Rollback()
 		return errors.New("failed to update character")
 	}
+	if err := recordNFTMint(repository.NewGormStore(tx), &character); err != nil {
+		tx.Rollback()
+		return errors.New("failed to record provenance")
+	}
 
 	// Create audit log
 	auditLog := models.AuditLog{
@@ -264,28 +273,13 @@ func (s *NFTService) MintCharacter(userID uint, characterID uint) error {
 	return nil
 }
 
-// PrepareMetadata creates NFT metadata for a character
-func (s *NFTService) PrepareMetadata(character *models.Character) map[string]interface{} {
-	attributes := []map[string]interface{}{
-		{"trait_type": "Rarity", "value": character.Rarity},
-		{"trait_type": "Class", "value": character.Class},
-		{"trait_type": "Element", "value": character.Element},
-		{"trait_type": "Level", "value": character.Level},
-		{"trait_type": "Attack", "value": character.CurrentAttack, "display_type": "number"},
-		{"trait_type": "Defense", "value": character.CurrentDefense, "display_type": "number"},
-		{"trait_type": "HP", "value": character.CurrentHP, "display_type": "number"},
-		{"trait_type": "Speed", "value": character.CurrentSpeed, "display_type": "number"},
-	}
-
-	metadata := map[string]interface{}{
-		"name":         fmt.Sprintf("%s #%d", character.CharacterType, character.ID),
-		"description":  fmt.Sprintf("A %s %s character from Crypto Tower Defense", character.Rarity, character.Class),
-		"image":        fmt.Sprintf("ipfs://placeholder/images/%s.png", character.CharacterType),
-		"attributes":   attributes,
-		"external_url": fmt.Sprintf("https://cryptotowerdefense.com/character/%d", character.ID),
+// PrepareMetadata creates NFT metadata for a character, its provenance included
+func (s *NFTService) PrepareMetadata(character *models.Character) (map[string]interface{}, error) {
+	history, err := repository.NewGormStore(db.DB).Provenance().List(character.ID)
+	if err != nil {
+		return nil, err
 	}
-
-	return metadata
+	return CharacterMetadata(character, history), nil
 }
 
 // VerifyNFTOwnership verifies on-chain ownership of an NFT
@@ -1,11 +1,12 @@
 package services
 
 import (
-	"errors"
 	"fmt"
+	"log/slog"
 
 	"github.com/lorengraff/crypto-tower-defense/internal/db"
 	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/internal/repository"
 	"github.com/lorengraff/crypto-tower-defense/pkg/formulas"
 )
 
@@ -30,6 +31,7 @@ func (s *ProgressionService) GainExperience(characterID uint, xpGained int, sour
 	}
 
 	// Add XP
+	fromLevel, fromStage := character.Level, character.EvolutionStage
 	character.TotalXP += xpGained
 
 	// Calculate new level based on total XP
@@ -37,38 +39,9 @@ func (s *ProgressionService) GainExperience(characterID uint, xpGained int, sour
 	leveledUp := newLevel > character.Level
 
 	if leveledUp {
-		// Update level
+		// Level, rarity, evolution and stats all follow from the new level
 		oldLevel := character.Level
-		character.Level = newLevel
-
-		// Check for rarity upgrade
-		newRarity := formulas.GetRarityForLevel(newLevel)
-		rarityUpgraded := newRarity != character.Rarity
-		if rarityUpgraded {
-			character.Rarity = newRarity
-		}
-
-		// Check for evolution
-		newEvolutionStage := formulas.GetEvolutionStage(newLevel)
-		evolved := newEvolutionStage > character.EvolutionStage
-		if evolved {
-			character.EvolutionStage = newEvolutionStage
-		}
-
-		// Recalculate all stats
-		att, def, hp, spd := formulas.RecalculateAllStats(
-			int(character.BaseAttack),
-			int(character.BaseDefense),
-			int(character.BaseHP),
-			int(character.BaseSpeed),
-			character.Level,
-			character.Rarity,
-			character.EvolutionStage,
-		)
-		character.CurrentAttack = att
-		character.CurrentDefense = def
-		character.CurrentHP = hp
-		character.CurrentSpeed = spd
+		setLevel(&character, newLevel)
 
 		// SKILL SYSTEM INTEGRATION: Update mana scaling using ManaService
 		manaService := NewManaService()
@@ -80,18 +53,18 @@ func (s *ProgressionService) GainExperience(characterID uint, xpGained int, sour
 		abilityService := NewAbilityService()
 		newAbilities, err := abilityService.AutoLearnAbilities(characterID, newLevel)
 		if err == nil && len(newAbilities) > 0 {
-			fmt.Printf("Character %d learned %d new abilities!\n", characterID, len(newAbilities))
+			slog.Info("character learned abilities", "character_id", characterID, "count", len(newAbilities))
 		}
 
 		// SKILL SYSTEM INTEGRATION: Unlock skill slots
 		skillInitService := NewSkillInitializationService()
 		if err := skillInitService.CheckAndUnlockSlots(characterID, newLevel); err != nil {
-			fmt.Printf("Warning: Failed to unlock skill slots: %v\n", err)
+			slog.Warn("failed to unlock skill slots", "character_id", characterID, "error", err)
 		}
 
 		// Log the level up for auditing
-		fmt.Printf("Character %d leveled up: %d → %d (Rarity: %s, Evolution: %d, Mana: %d/%d)\n",
-			characterID, oldLevel, newLevel, character.Rarity, character.EvolutionStage, character.CurrentMana, character.MaxMana)
+		slog.Info("character leveled up", "character_id", characterID, "from", oldLevel, "to", newLevel,
+			"rarity", character.Rarity, "evolution", character.EvolutionStage, "max_mana", character.MaxMana)
 	}
 
 	// Update current level XP for progress bar
@@ -102,41 +75,70 @@ func (s *ProgressionService) GainExperience(characterID uint, xpGained int, sour
 	if err := db.DB.Save(&character).Error; err != nil {
 		return nil, err
 	}
+	if err := recordGrowth(repository.NewGormStore(db.DB), &character, fromLevel, fromStage); err != nil {
+		slog.Warn("failed to record provenance", "character_id", characterID, "error", err)
+	}
 
 	return &character, nil
 }
 
-// ValidateCharacterIntegrity checks if character stats are valid
-func (s *ProgressionService) ValidateCharacterIntegrity(characterID uint) error {
+// IntegrityReport lists where a character's stored progression disagrees with what its
+// total XP and base stats produce
+type IntegrityReport struct {
+	CharacterID uint          `json:"character_id"`
+	Problems    []string      `json:"problems"`
+	Repaired    bool          `json:"repaired"`
+	Stats       StatBreakdown `json:"stats"`
+}
+
+// Valid reports whether no problems were found
+func (r *IntegrityReport) Valid() bool {
+	return len(r.Problems) == 0
+}
+
+// ValidateCharacterIntegrity recomputes a character's level from its total XP and its
+// stats from the stat pipeline and reports every stored value that differs. With repair,
+// the recomputed values are saved.
+func (s *ProgressionService) ValidateCharacterIntegrity(characterID uint, repair bool) (*IntegrityReport, error) {
 	var character models.Character
 	if err := db.DB.First(&character, characterID).Error; err != nil {
-		return err
-	}
-
-	// Validate level matches total XP
-	expectedLevel := formulas.GetLevelFromXP(character.TotalXP)
-	if character.Level != expectedLevel {
-		return fmt.Errorf("level mismatch: expected %d, got %d", expectedLevel, character.Level)
+		return nil, err
 	}
 
-	// Validate rarity matches level
-	expectedRarity := formulas.GetRarityForLevel(character.Level)
-	if character.Rarity != expectedRarity {
-		return fmt.Errorf("rarity mismatch: expected %s, got %s", expectedRarity, character.Rarity)
+	report := checkIntegrity(&character)
+	if repair && !report.Valid() {
+		if err := db.DB.Save(&character).Error; err != nil {
+			return nil, err
+		}
+		report.Repaired = true
+		slog.Warn("character integrity repaired", "character_id", characterID, "problems", report.Problems)
 	}
+	return report, nil
+}
 
-	// Validate evolution stage
-	expectedEvolution := formulas.GetEvolutionStage(character.Level)
-	if character.EvolutionStage != expectedEvolution {
-		return fmt.Errorf("evolution mismatch: expected %d, got %d", expectedEvolution, character.EvolutionStage)
+// checkIntegrity compares c with its recomputed progression and leaves the recomputed
+// values in c
+func checkIntegrity(c *models.Character) *IntegrityReport {
+	stored := *c
+	setLevel(c, formulas.GetLevelFromXP(c.TotalXP))
+	c.Experience = c.TotalXP - formulas.GetXPForLevel(c.Level)
+
+	report := &IntegrityReport{CharacterID: c.ID, Stats: ComputeStats(StatInput{Character: *c})}
+	mismatch := func(field string, want, got any) {
+		if want != got {
+			report.Problems = append(report.Problems, fmt.Sprintf("%s mismatch: expected %v, got %v", field, want, got))
+		}
 	}
-
-	// Validate stats
-	if !formulas.ValidateStats(character.BaseAttack, character.CurrentAttack, character.Level, character.Rarity, character.EvolutionStage) {
-		return errors.New("attack stat tampering detected")
+	mismatch("level", c.Level, stored.Level)
+	mismatch("rarity", c.Rarity, stored.Rarity)
+	mismatch("evolution", c.EvolutionStage, stored.EvolutionStage)
+	mismatch("attack", c.CurrentAttack, stored.CurrentAttack)
+	mismatch("defense", c.CurrentDefense, stored.CurrentDefense)
+	mismatch("speed", c.CurrentSpeed, stored.CurrentSpeed)
+	if stored.CurrentHP > c.CurrentHP {
+		report.Problems = append(report.Problems, fmt.Sprintf("hp above maximum: max %d, got %d", c.CurrentHP, stored.CurrentHP))
 	}
-
-	return nil
+	return report
 }
 
 // GetProgressionInfo returns progression details for a character
@@ -163,38 +165,8 @@ func (s *ProgressionService) GetProgressionInfo(characterID uint) (map[string]in
 
 // RecalculateStats forcefully recalculates character stats (admin function)
 func (s *ProgressionService) RecalculateStats(characterID uint) error {
-	var character models.Character
-	if err := db.DB.First(&character, characterID).Error; err != nil {
-		return err
-	}
-
-	// Recalculate level from total XP
-	character.Level = formulas.GetLevelFromXP(character.TotalXP)
-
-	// Update rarity
-	character.Rarity = formulas.GetRarityForLevel(character.Level)
-
-	// Update evolution
-	character.EvolutionStage = formulas.GetEvolutionStage(character.Level)
-
-	// Recalculate all stats
-	// Assuming formulas.RecalculateAllStats expects int for base stats and returns int
-	// and character.Base/Current stats are int64
-	att, def, hp, spd := formulas.RecalculateAllStats(
-		int(character.BaseAttack),
-		int(character.BaseDefense),
-		int(character.BaseHP),
-		int(character.BaseSpeed),
-		character.Level,
-		character.Rarity,
-		character.EvolutionStage,
-	)
-	character.CurrentAttack = att
-	character.CurrentDefense = def
-	character.CurrentHP = hp
-	character.CurrentSpeed = spd
-
-	return db.DB.Save(&character).Error
+	_, err := s.ValidateCharacterIntegrity(characterID, true)
+	return err
 }
 
 // GetBaseManaForRarity returns base mana for a rarity
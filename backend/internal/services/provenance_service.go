@@ -0,0 +1,211 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/internal/repository"
+)
+
+// Transfer channels recorded in Data.via of TRANSFERRED entries
+const (
+	ProvenanceViaMarket = "market"
+	ProvenanceViaTrade  = "trade"
+)
+
+// Notable battle results recorded as ACHIEVEMENT entries
+const (
+	AchievementUpset     = "UPSET_VICTORY" // Beat a clearly higher rated player
+	AchievementWinStreak = "WIN_STREAK"    // Part of a team on a milestone win streak
+	AchievementRaidSRank = "RAID_S_RANK"   // Cleared a raid mission with an S grade
+)
+
+// winStreakMilestones are the win streaks worth recording
+var winStreakMilestones = map[int]bool{5: true, 10: true, 25: true, 50: true, 100: true}
+
+// ProvenanceService serves character histories
+type ProvenanceService struct {
+	store repository.Store
+}
+
+// NewProvenanceService creates the provenance service
+func NewProvenanceService(store repository.Store) *ProvenanceService {
+	return &ProvenanceService{store: store}
+}
+
+// Timeline returns a character's history, oldest first
+func (s *ProvenanceService) Timeline(characterID uint) ([]models.CharacterProvenance, error) {
+	if _, err := s.store.Characters().Get(characterID); err != nil {
+		return nil, errors.New("character not found")
+	}
+	return s.store.Provenance().List(characterID)
+}
+
+// Metadata returns a character's NFT metadata, history included
+func (s *ProvenanceService) Metadata(characterID uint) (map[string]interface{}, error) {
+	c, err := s.store.Characters().Get(characterID)
+	if err != nil {
+		return nil, errors.New("character not found")
+	}
+	history, err := s.store.Provenance().List(characterID)
+	if err != nil {
+		return nil, err
+	}
+	return CharacterMetadata(c, history), nil
+}
+
+// CharacterMetadata builds ERC-721 metadata for a character. The history goes in as
+// summary attributes and as the full provenance timeline.
+func CharacterMetadata(c *models.Character, history []models.CharacterProvenance) map[string]interface{} {
+	origin := "Minted"
+	owners := map[uint]bool{}
+	for _, e := range history {
+		if e.Event == models.ProvenanceBred {
+			origin = "Bred"
+		}
+		if e.ToUserID != nil {
+			owners[*e.ToUserID] = true
+		}
+	}
+
+	attributes := []map[string]interface{}{
+		{"trait_type": "Rarity", "value": c.Rarity},
+		{"trait_type": "Class", "value": c.Class},
+		{"trait_type": "Element", "value": c.Element},
+		{"trait_type": "Level", "value": c.Level},
+		{"trait_type": "Attack", "value": c.CurrentAttack, "display_type": "number"},
+		{"trait_type": "Defense", "value": c.CurrentDefense, "display_type": "number"},
+		{"trait_type": "HP", "value": c.CurrentHP, "display_type": "number"},
+		{"trait_type": "Speed", "value": c.CurrentSpeed, "display_type": "number"},
+		{"trait_type": "Origin", "value": origin},
+		{"trait_type": "Generation", "value": c.Generation, "display_type": "number"},
+		{"trait_type": "Owners", "value": max(len(owners), 1), "display_type": "number"},
+	}
+
+	timeline := make([]map[string]interface{}, 0, len(history))
+	for _, e := range history {
+		timeline = append(timeline, map[string]interface{}{
+			"event":   e.Event,
+			"date":    e.CreatedAt.Unix(),
+			"summary": e.Summary,
+		})
+	}
+
+	return map[string]interface{}{
+		"name":         fmt.Sprintf("%s #%d", c.CharacterType, c.ID),
+		"description":  fmt.Sprintf("A %s %s character from Crypto Tower Defense", c.Rarity, c.Class),
+		"image":        fmt.Sprintf("ipfs://placeholder/images/%s.png", c.CharacterType),
+		"attributes":   attributes,
+		"provenance":   timeline,
+		"external_url": fmt.Sprintf("https://cryptotowerdefense.com/character/%d", c.ID),
+	}
+}
+
+// recordProvenance appends an entry to a character's history; data, if any, is stored
+// as the entry's JSON details
+func recordProvenance(tx repository.Store, e models.CharacterProvenance, data map[string]interface{}) error {
+	// Entries are immutable: never share user IDs with a character that may change owner
+	e.FromUserID, e.ToUserID = copyID(e.FromUserID), copyID(e.ToUserID)
+	if data != nil {
+		raw, _ := json.Marshal(data)
+		e.Data = string(raw)
+	}
+	return tx.Provenance().Add(&e)
+}
+
+// recordOrigin records where a freshly hatched character came from: minted from a
+// gacha egg or bred from the egg's parents, then hatched to its owner
+func recordOrigin(tx repository.Store, c *models.Character, egg *models.Egg) error {
+	origin := models.CharacterProvenance{CharacterID: c.ID, Event: models.ProvenanceMinted, ToUserID: &c.OwnerID,
+		Summary: fmt.Sprintf("Minted as a %s %s %s", c.Rarity, c.Element, c.Class)}
+	data := map[string]interface{}{"egg_id": egg.ID, "mint_cost": egg.MintCost}
+	if egg.Parent1ID != nil && egg.Parent2ID != nil {
+		origin.Event = models.ProvenanceBred
+		origin.Summary = fmt.Sprintf("Bred from #%d and #%d (generation %d)", *egg.Parent1ID, *egg.Parent2ID, c.Generation)
+		data = map[string]interface{}{"egg_id": egg.ID, "parent1_id": *egg.Parent1ID, "parent2_id": *egg.Parent2ID, "generation": c.Generation}
+	}
+	if err := recordProvenance(tx, origin, data); err != nil {
+		return err
+	}
+	return recordHatch(tx, c)
+}
+
+// recordHatch records a character leaving its egg
+func recordHatch(tx repository.Store, c *models.Character) error {
+	return recordProvenance(tx, models.CharacterProvenance{CharacterID: c.ID, Event: models.ProvenanceHatched, ToUserID: &c.OwnerID,
+		Summary: fmt.Sprintf("Hatched at rarity %s", c.Rarity)}, nil)
+}
+
+// recordTransfer records a change of owner
+func recordTransfer(tx repository.Store, characterID, fromUserID, toUserID uint, via string) error {
+	return recordProvenance(tx, models.CharacterProvenance{CharacterID: characterID, Event: models.ProvenanceTransferred,
+		FromUserID: &fromUserID, ToUserID: &toUserID, Summary: fmt.Sprintf("Transferred by %s", via)},
+		map[string]interface{}{"via": via})
+}
+
+// recordNFTMint records a character being minted on-chain to its owner
+func recordNFTMint(tx repository.Store, c *models.Character) error {
+	return recordProvenance(tx, models.CharacterProvenance{CharacterID: c.ID, Event: models.ProvenanceNFTMinted, ToUserID: &c.OwnerID,
+		Summary: fmt.Sprintf("Minted on-chain as token %d", *c.OnChainTokenID)},
+		map[string]interface{}{"token_id": *c.OnChainTokenID, "tx_hash": c.MintTxHash})
+}
+
+// recordGrowth records the level-up and evolution a character went through since it
+// was at fromLevel and fromStage, one entry each however many levels were gained
+func recordGrowth(tx repository.Store, c *models.Character, fromLevel, fromStage int) error {
+	if c.Level > fromLevel {
+		err := recordProvenance(tx, models.CharacterProvenance{CharacterID: c.ID, Event: models.ProvenanceLevelUp, ToUserID: &c.OwnerID,
+			Summary: fmt.Sprintf("Reached level %d", c.Level)},
+			map[string]interface{}{"from": fromLevel, "to": c.Level})
+		if err != nil {
+			return err
+		}
+	}
+	if c.EvolutionStage > fromStage {
+		return recordProvenance(tx, models.CharacterProvenance{CharacterID: c.ID, Event: models.ProvenanceEvolved, ToUserID: &c.OwnerID,
+			Summary: fmt.Sprintf("Evolved to stage %d", c.EvolutionStage)},
+			map[string]interface{}{"from": fromStage, "to": c.EvolutionStage})
+	}
+	return nil
+}
+
+// recordAchievement records a notable battle result for every character of a team
+func recordAchievement(tx repository.Store, team []models.Character, achievement, summary string, data map[string]interface{}) error {
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	data["achievement"] = achievement
+	for _, c := range team {
+		e := models.CharacterProvenance{CharacterID: c.ID, Event: models.ProvenanceAchievement, ToUserID: &c.OwnerID, Summary: summary}
+		if err := recordProvenance(tx, e, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordDeath records a character's durability running out
+func recordDeath(tx repository.Store, c *models.Character) error {
+	summary := "Fell in battle"
+	if !c.CanBeRevived {
+		summary = "Fell in battle for good"
+	}
+	return recordProvenance(tx, models.CharacterProvenance{CharacterID: c.ID, Event: models.ProvenanceDied, ToUserID: &c.OwnerID,
+		Summary: summary}, map[string]interface{}{"permanent": !c.CanBeRevived, "revive_count": c.ReviveCount})
+}
+
+// recordRevive records a dead character being brought back
+func recordRevive(tx repository.Store, c *models.Character) error {
+	return recordProvenance(tx, models.CharacterProvenance{CharacterID: c.ID, Event: models.ProvenanceRevived, ToUserID: &c.OwnerID,
+		Summary: fmt.Sprintf("Revived (%d)", c.ReviveCount)}, map[string]interface{}{"revive_count": c.ReviveCount})
+}
+
+func copyID(id *uint) *uint {
+	if id == nil {
+		return nil
+	}
+	v := *id
+	return &v
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/internal/repository"
+)
+
+func provenanceEvents(t *testing.T, st repository.Store, characterID uint) []models.CharacterProvenance {
+	t.Helper()
+	history, err := NewProvenanceService(st).Timeline(characterID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return history
+}
+
+func TestMarketSaleRecordsTransfer(t *testing.T) {
+	st := repository.NewMemoryStore()
+	market, ledger, _ := newTestMarketplace(st)
+	seller := newTestTrader(t, st, ledger, 0)
+	buyer := newTestTrader(t, st, ledger, 1000)
+	char := newTestCharacter(t, st, seller.ID, 100, 50)
+
+	listing, err := market.CreateListing(seller.ID, "character", char.ID, 200, "GTK")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := market.BuyListing(buyer.ID, listing.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	history := provenanceEvents(t, st, char.ID)
+	if len(history) != 1 {
+		t.Fatalf("history = %+v, want one transfer", history)
+	}
+	e := history[0]
+	if e.Event != models.ProvenanceTransferred || *e.FromUserID != seller.ID || *e.ToUserID != buyer.ID {
+		t.Fatalf("entry = %+v, want a transfer from seller to buyer", e)
+	}
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(e.Data), &data); err != nil || data["via"] != ProvenanceViaMarket {
+		t.Fatalf("data = %s, want via market", e.Data)
+	}
+}
+
+func TestMetadataSummarizesHistory(t *testing.T) {
+	st := repository.NewMemoryStore()
+	first := newTestUser(t, st, 1000)
+	second := newTestUser(t, st, 1000)
+	c := newTestCharacter(t, st, first.ID, 100, 50)
+	p1, p2 := uint(90), uint(91)
+	egg := &models.Egg{UserID: first.ID, Parent1ID: &p1, Parent2ID: &p2}
+
+	if err := recordOrigin(st, c, egg); err != nil {
+		t.Fatal(err)
+	}
+	if err := recordTransfer(st, c.ID, first.ID, second.ID, ProvenanceViaMarket); err != nil {
+		t.Fatal(err)
+	}
+	c.OwnerID, c.Level, c.EvolutionStage = second.ID, 12, 2
+	if err := recordGrowth(st, c, 9, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := recordGrowth(st, c, 12, 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.Characters().Save(c); err != nil {
+		t.Fatal(err)
+	}
+
+	history := provenanceEvents(t, st, c.ID)
+	want := []string{models.ProvenanceBred, models.ProvenanceHatched, models.ProvenanceTransferred, models.ProvenanceLevelUp, models.ProvenanceEvolved}
+	if len(history) != len(want) {
+		t.Fatalf("history has %d entries, want %v", len(history), want)
+	}
+	for i, e := range history {
+		if e.Event != want[i] {
+			t.Fatalf("entry %d = %s, want %s", i, e.Event, want[i])
+		}
+	}
+
+	meta, err := NewProvenanceService(st).Metadata(c.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	attrs := map[string]interface{}{}
+	for _, a := range meta["attributes"].([]map[string]interface{}) {
+		attrs[a["trait_type"].(string)] = a["value"]
+	}
+	if attrs["Origin"] != "Bred" || attrs["Owners"] != 2 {
+		t.Fatalf("origin %v, owners %v; want Bred, 2", attrs["Origin"], attrs["Owners"])
+	}
+	if timeline := meta["provenance"].([]map[string]interface{}); len(timeline) != len(want) {
+		t.Fatalf("metadata timeline has %d entries, want %d", len(timeline), len(want))
+	}
+}
+
+func TestRatedUpsetIsRecordedForWinningTeam(t *testing.T) {
+	st := repository.NewMemoryStore()
+	svc, ledger := newTestBattleService(st)
+	favourite := newTestUser(t, st, 1300)
+	st.SetActiveTeam(favourite.ID, newTestCharacter(t, st, favourite.ID, 150, 50).ID)
+	underdog, hero := newTestPlayer(t, st, 300, 100)
+	fund(t, ledger, favourite.ID, 1000)
+	fund(t, ledger, underdog.ID, 1000)
+
+	if _, err := svc.EnterWagerQueue(favourite.ID); err != nil {
+		t.Fatal(err)
+	}
+	match, err := svc.EnterWagerQueue(underdog.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.CompleteBattle(context.Background(), match.BattleID, underdog.ID, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	history := provenanceEvents(t, st, hero.ID)
+	if len(history) != 1 || history[0].Event != models.ProvenanceAchievement {
+		t.Fatalf("history = %+v, want one achievement", history)
+	}
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(history[0].Data), &data); err != nil || data["achievement"] != AchievementUpset {
+		t.Fatalf("data = %s, want an upset", history[0].Data)
+	}
+}
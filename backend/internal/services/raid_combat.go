@@ -156,6 +156,7 @@ func (s *RaidService) ExecutePlayerTurn(sessionID, characterID uint, moveSlot in
 		s.calculateRewards(&session)
 		s.distributeExpToTeam(&session, session.XPEarned)
 		s.updateCampaignProgress(session.UserID, session.Mission.IslandID, session.Mission.Sequence)
+		s.wearTeam(&session, true)
 	} else {
 		// 10. Advance turn (only if battle continues)
 		s.advanceTurn(&session)
@@ -209,6 +210,7 @@ func (s *RaidService) ExecuteEnemyTurn(sessionID uint) (*models.RaidSession, *Ba
 		now := time.Now()
 		session.CompletedAt = &now
 		db.DB.Save(&session)
+		s.wearTeam(&session, false)
 
 		return &session, &BattleResult{
 			Message: "All characters fainted! Defeat!",
@@ -270,6 +272,7 @@ func (s *RaidService) ExecuteEnemyTurn(sessionID uint) (*models.RaidSession, *Ba
 		session.Status = "FAILED"
 		now := time.Now()
 		session.CompletedAt = &now
+		s.wearTeam(&session, false)
 	} else {
 		// Advance turn
 		s.advanceTurn(&session)
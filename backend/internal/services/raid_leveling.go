@@ -1,10 +1,14 @@
 package services
 
 import (
-	"math"
+	"fmt"
+	"log/slog"
 
 	"github.com/lorengraff/crypto-tower-defense/internal/db"
 	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/internal/repository"
+	"github.com/lorengraff/crypto-tower-defense/pkg/formulas"
+	"gorm.io/gorm"
 )
 
 // XP & Leveling System (Phase 10.1)
@@ -33,92 +37,56 @@ func (s *RaidService) distributeExpToTeam(session *models.RaidSession, totalXP i
 			continue
 		}
 
-		// Add XP
-		char.Experience += xpPerMember
-
-		// Check for level ups (can level up multiple times if enough XP)
-		leveledUp := false
-		for char.Experience >= s.getExpForNextLevel(char.Level) {
-			s.levelUpCharacter(&char)
-			leveledUp = true
+		// Raid XP counts toward the same total as every other source; the level,
+		// rarity, evolution and stats it reaches come from the stat pipeline
+		fromLevel, fromStage := char.Level, char.EvolutionStage
+		char.TotalXP += xpPerMember
+		if level := formulas.GetLevelFromXP(char.TotalXP); level > char.Level {
+			setLevel(&char, level)
 		}
+		char.Experience = char.TotalXP - formulas.GetXPForLevel(char.Level)
 
 		// Save character
-		db.DB.Save(&char)
-
-		// Log level up (optional: could add to battle log)
-		if leveledUp {
-			// Could emit event or add to session log
-			// For now, just save the updated character
+		if err := db.DB.Save(&char).Error; err != nil {
+			continue
+		}
+		if err := recordGrowth(repository.NewGormStore(db.DB), &char, fromLevel, fromStage); err != nil {
+			slog.Warn("failed to record provenance", "character_id", char.ID, "error", err)
 		}
 	}
 }
 
-// getExpForNextLevel calculates XP required for next level (Pokemon Medium-Fast curve)
-func (s *RaidService) getExpForNextLevel(currentLevel int) int {
-	// Medium-Fast growth: Level³
-	// Level 1→2: 8 XP
-	// Level 5→6: 216 XP
-	// Level 10→11: 1331 XP
-	nextLevel := currentLevel + 1
-	return int(math.Pow(float64(nextLevel), 3))
-}
-
-// levelUpCharacter increases level and stats
-func (s *RaidService) levelUpCharacter(char *models.Character) {
-	// Deduct XP for level up
-	char.Experience -= s.getExpForNextLevel(char.Level)
-	char.Level++
-
-	// Stat increases (balanced progression)
-	// HP: +5 base + bonus every 5 levels
-	hpGain := 5
-	if char.Level%5 == 0 {
-		hpGain += char.Level / 5
+// recordSRank adds an S-rank clear to the history of the active team members
+func (s *RaidService) recordSRank(session *models.RaidSession) {
+	var team []models.Character
+	for _, m := range session.Team.Members {
+		if m.IsBackup {
+			continue
+		}
+		var char models.Character
+		if err := db.DB.First(&char, m.CharacterID).Error; err == nil {
+			team = append(team, char)
+		}
 	}
-	char.CurrentHP += hpGain // Increase current HP (also heals on level up)
-
-	// Attack: +2 base + bonus every 10 levels
-	atkGain := 2
-	if char.Level%10 == 0 {
-		atkGain += char.Level / 10
+	summary := fmt.Sprintf("S rank clear of %s", session.Mission.Name)
+	data := map[string]interface{}{"raid_session_id": session.ID, "mission_id": session.MissionID}
+	if err := recordAchievement(repository.NewGormStore(db.DB), team, AchievementRaidSRank, summary, data); err != nil {
+		slog.Warn("failed to record provenance", "raid_session_id", session.ID, "error", err)
 	}
-	char.CurrentAttack += atkGain
+}
 
-	// Defense: +2 base + bonus every 10 levels
-	defGain := 2
-	if char.Level%10 == 0 {
-		defGain += char.Level / 10
+// wearTeam applies the durability and fatigue a finished raid costs the session's active
+// team members. Raids are hard on a team, so they wear more than a regular battle.
+func (s *RaidService) wearTeam(session *models.RaidSession, won bool) {
+	var ids []uint
+	err := db.DB.Model(&models.TeamMember{}).Where("team_id = ? AND is_backup = ?", session.TeamID, false).
+		Pluck("character_id", &ids).Error
+	if err == nil {
+		err = db.DB.Transaction(func(tx *gorm.DB) error {
+			return applyBattleWear(repository.NewGormStore(tx), s.config, ids, "raid", won, session.TurnCount)
+		})
 	}
-	char.CurrentDefense += defGain
-
-	// Speed: +1 every level
-	char.CurrentSpeed += 1
-
-	// Special stats (if they exist in your model)
-	// char.SpecialAttack += 1
-	// char.SpecialDefense += 1
-
-	// Check for evolution at key levels (16, 32, 48)
-	if char.Level == 16 || char.Level == 32 || char.Level == 48 {
-		s.checkEvolution(char)
+	if err != nil {
+		slog.Warn("failed to apply raid wear", "raid_session_id", session.ID, "error", err)
 	}
 }
-
-// checkEvolution checks if character should evolve
-func (s *RaidService) checkEvolution(char *models.Character) {
-	// Evolution logic
-	// For now, just a placeholder
-	// In full implementation:
-	// - Check if character has an evolution form
-	// - Update CharacterType, stats, potentially moves
-	// - Update image/appearance
-
-	// Example:
-	// if char.CharacterType == "BASIC_FIRE" && char.Level >= 16 {
-	//     char.CharacterType = "EVOLVED_FIRE"
-	//     char.MaxHP += 20
-	//     char.CurrentAttack += 10
-	//     // etc.
-	// }
-}
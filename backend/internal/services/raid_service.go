@@ -4,11 +4,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
 	"math/rand"
 	"time"
 
 	"github.com/lorengraff/crypto-tower-defense/internal/db"
 	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/internal/repository"
 	"gorm.io/gorm"
 )
 
@@ -16,6 +18,7 @@ import (
 type RaidService struct {
 	skillService *SkillActivationService
 	ledger       *LedgerService
+	config       Settings
 }
 
 // RaidSessionWithSprites contains raid session data with character sprites loaded
@@ -26,10 +29,11 @@ type RaidSessionWithSprites struct {
 }
 
 // NewRaidService creates a new raid service
-func NewRaidService() *RaidService {
+func NewRaidService(ledger *LedgerService) *RaidService {
 	return &RaidService{
 		skillService: NewSkillActivationService(),
-		ledger:       NewLedgerService(),
+		ledger:       ledger,
+		config:       GetConfigService(),
 	}
 }
 
@@ -123,7 +127,16 @@ func (s *RaidService) calculatePerformanceGrade(session *models.RaidSession) str
 	return "D"
 }
 
-func (s *RaidService) getGradeMultiplier(grade string) float64 {
+// RaidReward is the GTK and XP a cleared raid mission pays at a performance grade
+func RaidReward(sequence int, grade string) (tokens, xp int) {
+	baseTokens := 50 + (sequence * 25) // Scales with mission
+	baseXP := 100 + (sequence * 50)
+
+	multiplier := raidGradeMultiplier(grade)
+	return int(float64(baseTokens) * multiplier), int(float64(baseXP) * multiplier)
+}
+
+func raidGradeMultiplier(grade string) float64 {
 	switch grade {
 	case "S":
 		return 3.0 // 3x rewards!
@@ -373,26 +386,22 @@ func (s *RaidService) ProcessTurn(sessionID uint) (*models.RaidSession, string,
 			// logMsg := fmt.Sprintf("VICTORY! Performance: %s Rank!", grade)
 
 			// Distribute Rewards with multipliers
-			baseTokens := 50 + (session.Mission.Sequence * 25) // Scales with mission
-			baseXP := 100 + (session.Mission.Sequence * 50)
-
-			multiplier := s.getGradeMultiplier(grade)
-			finalTokens := int(float64(baseTokens) * multiplier)
-			finalXP := int(float64(baseXP) * multiplier)
+			finalTokens, finalXP := RaidReward(session.Mission.Sequence, grade)
 
 			session.TokensEarned = finalTokens
 			session.XPEarned = finalXP
+			s.wearTeam(&session, true)
 
-			// Give rewards to user
-			var user models.User
-			if err := db.DB.First(&user, session.UserID).Error; err == nil {
-				user.GTKBalance += int64(finalTokens)
-				user.Experience += finalXP
-				db.DB.Save(&user)
+			// Give rewards to user (owners of borrowed team members take their share)
+			if err := s.payMissionReward(&session, int64(finalTokens), finalXP); err != nil {
+				slog.Warn("failed to pay raid reward", "session_id", session.ID, "error", err)
 			}
 
 			// ✅ PHASE 10.1: Distribute XP to team characters
 			s.distributeExpToTeam(&session, finalXP)
+			if grade == "S" {
+				s.recordSRank(&session)
+			}
 
 			// Update Progress
 			s.updateCampaignProgress(session.UserID, session.Mission.IslandID, session.Mission.Sequence)
@@ -404,6 +413,7 @@ func (s *RaidService) ProcessTurn(sessionID uint) (*models.RaidSession, string,
 			now := time.Now()
 			session.CompletedAt = &now
 			logMsg = "Mission Complete!"
+			s.wearTeam(&session, true)
 
 			s.distributeRewards(session.UserID, 1)
 			s.updateCampaignProgress(session.UserID, session.Mission.IslandID, session.Mission.Sequence)
@@ -457,6 +467,7 @@ func (s *RaidService) ProcessTurn(sessionID uint) (*models.RaidSession, string,
 			session.CurrentTeamHP = 0
 			session.Status = "FAILED"
 			logMsg = "DEFEAT! Your team was wiped out."
+			s.wearTeam(&session, false)
 		}
 	}
 
@@ -490,10 +501,6 @@ func (s *RaidService) distributeRewards(userID uint, difficulty int) (RaidReward
 
 	// 2. LEDGER INTEGRATION: Reward Tokens
 	// Debit: Reward Pool (Inflation), Credit: User Wallet
-	if s.ledger == nil {
-		s.ledger = NewLedgerService()
-	}
-
 	rewardAcc, _ := s.ledger.GetOrCreateAccount(nil, models.AccountTypeReward, "GTK")
 	userAcc, _ := s.ledger.GetOrCreateAccount(&userID, models.AccountTypeWallet, "GTK")
 
@@ -524,6 +531,54 @@ func (s *RaidService) distributeRewards(userID uint, difficulty int) (RaidReward
 	return rewards, err
 }
 
+// payMissionReward credits a cleared mission's tokens and XP to the player. The tokens go
+// through the ledger so the owners of borrowed team members get their rental share; what
+// the player keeps is mirrored into the legacy balance.
+func (s *RaidService) payMissionReward(session *models.RaidSession, tokens int64, xp int) error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		st := repository.NewGormStore(tx)
+		ledger := s.ledger.WithStore(st)
+
+		var team []models.Character
+		for _, m := range session.Team.Members {
+			if !m.IsBackup {
+				team = append(team, m.Character)
+			}
+		}
+		shares, shared, err := rentalShares(st, ledger, session.UserID, team, tokens)
+		if err != nil {
+			return err
+		}
+		if tokens > 0 {
+			rewardAcc, err := ledger.GetOrCreateAccount(nil, models.AccountTypeReward, "GTK")
+			if err != nil {
+				return err
+			}
+			userAcc, err := ledger.GetOrCreateAccount(&session.UserID, models.AccountTypeWallet, "GTK")
+			if err != nil {
+				return err
+			}
+			entries := []models.LedgerEntry{
+				{AccountID: rewardAcc.ID, Amount: -tokens, Type: "DEBIT"},
+				{AccountID: userAcc.ID, Amount: tokens - shared, Type: "CREDIT"},
+			}
+			entries = append(entries, shares...)
+			if err := ledger.CreateTransaction(models.TxTypeRaidReward, fmt.Sprintf("raid_session_%d", session.ID),
+				fmt.Sprintf("Raid Mission Reward (%s Rank)", session.PerformanceGrade), entries); err != nil {
+				return err
+			}
+		}
+
+		var user models.User
+		if err := tx.First(&user, session.UserID).Error; err != nil {
+			return err
+		}
+		user.GTKBalance += tokens - shared
+		user.Experience += xp
+		return tx.Save(&user).Error
+	})
+}
+
 func (s *RaidService) GetTeamIfValid(userID, teamID uint) (*models.Team, error) {
 	var team models.Team
 	// Corrected line: Ensure Preload is correctly chained before First
@@ -537,7 +592,13 @@ func (s *RaidService) GetTeamIfValid(userID, teamID uint) (*models.Team, error)
 	// Check if team has at least 1 active member
 	activeCount := 0
 	for _, m := range team.Members {
+		if m.Character.Controller() != userID {
+			return nil, fmt.Errorf("character %d is rented out", m.CharacterID)
+		}
 		if !m.IsBackup {
+			if err := CheckBattleReady(&m.Character, s.config); err != nil {
+				return nil, err
+			}
 			activeCount++
 		}
 	}
@@ -568,8 +629,12 @@ func (s *RaidService) AbandonSession(sessionID, userID uint) error {
 	session.Status = "ABANDONED"
 	now := time.Now()
 	session.CompletedAt = &now
-
-	return db.DB.Save(&session).Error
+	if err := db.DB.Save(&session).Error; err != nil {
+		return err
+	}
+	// Running away is a loss as far as the team's condition goes
+	s.wearTeam(&session, false)
+	return nil
 }
 
 // GetRaidSessionWithSprites retrieves a raid session with team character sprites preloaded
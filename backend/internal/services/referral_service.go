@@ -0,0 +1,517 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/db"
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/pkg/metrics"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReferralService handles referral codes, attribution at first login and milestone payouts
+type ReferralService struct {
+	ledger    *LedgerService
+	config    *ConfigService
+	admin     *AdminService
+	antiCheat *AntiCheatService
+}
+
+func NewReferralService(ledger *LedgerService, admin *AdminService) *ReferralService {
+	s := &ReferralService{
+		ledger: ledger,
+		config: GetConfigService(),
+		admin:  admin,
+	}
+	if sqlDB, err := db.DB.DB(); err == nil {
+		s.antiCheat = NewAntiCheatService(sqlDB)
+	}
+	return s
+}
+
+// ReferralMilestone is one reward step, configured via the referral_milestones setting
+type ReferralMilestone struct {
+	Type      string `json:"type"`      // level, ranked_games
+	Threshold int    `json:"threshold"` // Level reached / ranked games completed by the referred player
+	Reward    int64  `json:"reward"`    // GTK paid to the referrer
+}
+
+// Key identifies the milestone in ReferralReward rows (e.g. level_10)
+func (m ReferralMilestone) Key() string {
+	return fmt.Sprintf("%s_%d", m.Type, m.Threshold)
+}
+
+// ReferralProgress is one referred player as shown on the referrer's dashboard
+type ReferralProgress struct {
+	ReferralID        uint                    `json:"referral_id"`
+	UserID            uint                    `json:"user_id"`
+	DisplayName       string                  `json:"display_name"`
+	WalletAddress     string                  `json:"wallet_address"`
+	Level             int                     `json:"level"`
+	RankedGames       int64                   `json:"ranked_games"`
+	Status            string                  `json:"status"`
+	JoinedAt          time.Time               `json:"joined_at"`
+	Rewards           []models.ReferralReward `json:"rewards"`
+	NextMilestone     *ReferralMilestone      `json:"next_milestone,omitempty"`
+	AllMilestonesPaid bool                    `json:"all_milestones_paid"`
+}
+
+// ReferralDashboard summarises a player's referrals and payouts
+type ReferralDashboard struct {
+	Code          string              `json:"code"`
+	TotalReferred int                 `json:"total_referred"`
+	PendingReward int64               `json:"pending_reward"` // Claimable now
+	HeldReward    int64               `json:"held_reward"`    // Blocked by anti-abuse review
+	PaidReward    int64               `json:"paid_reward"`
+	Milestones    []ReferralMilestone `json:"milestones"`
+	Referrals     []ReferralProgress  `json:"referrals"`
+}
+
+const referralCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+var defaultReferralMilestones = []ReferralMilestone{
+	{Type: "level", Threshold: 5, Reward: 100},
+	{Type: "ranked_games", Threshold: 10, Reward: 250},
+	{Type: "level", Threshold: 20, Reward: 500},
+}
+
+// GetOrCreateReferralCode returns the user's code, generating one on first use
+func (s *ReferralService) GetOrCreateReferralCode(userID uint) (string, error) {
+	var user models.User
+	if err := db.DB.First(&user, userID).Error; err != nil {
+		return "", errors.New("user not found")
+	}
+	if user.ReferralCode != nil && *user.ReferralCode != "" {
+		return *user.ReferralCode, nil
+	}
+
+	for attempt := 0; attempt < 5; attempt++ {
+		code, err := generateReferralCode(8)
+		if err != nil {
+			return "", err
+		}
+		res := db.DB.Model(&models.User{}).Where("id = ? AND referral_code IS NULL", userID).Update("referral_code", code)
+		if res.Error == nil && res.RowsAffected == 1 {
+			return code, nil
+		}
+		if res.Error == nil {
+			// Another request set it concurrently
+			db.DB.First(&user, userID)
+			if user.ReferralCode != nil {
+				return *user.ReferralCode, nil
+			}
+		}
+	}
+	return "", errors.New("failed to generate referral code")
+}
+
+// AttributeReferral links a new player to the owner of the code. Called once, on the first /auth/verify.
+// Self-referrals are rejected; same-IP or connected-wallet pairs are recorded but flagged, which holds their rewards.
+func (s *ReferralService) AttributeReferral(referredID uint, code string) (*models.Referral, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return nil, errors.New("referral code required")
+	}
+
+	var referrer models.User
+	if err := db.DB.Where("referral_code = ?", code).First(&referrer).Error; err != nil {
+		return nil, errors.New("invalid referral code")
+	}
+	if referrer.ID == referredID {
+		return nil, errors.New("cannot use your own referral code")
+	}
+
+	var existing int64
+	db.DB.Model(&models.Referral{}).Where("referred_id = ?", referredID).Count(&existing)
+	if existing > 0 {
+		return nil, errors.New("account already referred")
+	}
+
+	referral := models.Referral{
+		ReferrerID: referrer.ID,
+		ReferredID: referredID,
+		Status:     models.ReferralActive,
+	}
+	if reason := s.abuseReason(referrer.ID, referredID); reason != "" {
+		referral.Status = models.ReferralFlagged
+		referral.FlagReason = reason
+	}
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&referral).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.User{}).Where("id = ?", referredID).Update("referred_by", referrer.ID).Error; err != nil {
+			return err
+		}
+		if referral.Status == models.ReferralFlagged {
+			return s.flagReferral(tx, &referral)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &referral, nil
+}
+
+// EvaluateMilestones records rewards for every milestone the referrer's players have reached
+func (s *ReferralService) EvaluateMilestones(referrerID uint) error {
+	milestones := s.milestones()
+
+	var referrals []models.Referral
+	if err := db.DB.Preload("Referred").
+		Where("referrer_id = ? AND reward_claimed = ?", referrerID, false).
+		Find(&referrals).Error; err != nil {
+		return err
+	}
+
+	for _, ref := range referrals {
+		rankedGames := s.rankedGames(ref.ReferredID)
+
+		var recorded []models.ReferralReward
+		db.DB.Where("referral_id = ?", ref.ID).Find(&recorded)
+		done := make(map[string]bool, len(recorded))
+		for _, r := range recorded {
+			done[r.Milestone] = true
+		}
+
+		for _, m := range milestones {
+			if done[m.Key()] || !milestoneReached(m, ref.Referred.Level, rankedGames) {
+				continue
+			}
+			status := models.ReferralRewardPending
+			if ref.Status == models.ReferralFlagged {
+				status = models.ReferralRewardHeld
+			}
+			reward := models.ReferralReward{
+				ReferralID: ref.ID,
+				ReferrerID: referrerID,
+				Milestone:  m.Key(),
+				Amount:     m.Reward,
+				Status:     status,
+			}
+			if err := db.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&reward).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ClaimRewards pays all pending milestone rewards to the referrer's wallet from the REWARD account.
+// The anti-abuse checks are re-run first, since IPs and wallet history change after sign-up.
+func (s *ReferralService) ClaimRewards(referrerID uint) (int64, error) {
+	if err := s.EvaluateMilestones(referrerID); err != nil {
+		return 0, err
+	}
+	s.recheckReferrals(referrerID)
+
+	userAcc, err := s.ledger.GetOrCreateAccount(&referrerID, models.AccountTypeWallet, "GTK")
+	if err != nil {
+		return 0, err
+	}
+	rewardAcc, err := s.ledger.GetOrCreateAccount(nil, models.AccountTypeReward, "GTK")
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		var pending []models.ReferralReward
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("referrer_id = ? AND status = ?", referrerID, models.ReferralRewardPending).
+			Find(&pending).Error; err != nil {
+			return err
+		}
+		if len(pending) == 0 {
+			return errors.New("no referral rewards to claim")
+		}
+
+		if daily := int64(s.config.GetInt("referral_daily_reward_cap", 2500)); daily > 0 {
+			var paidToday int64
+			tx.Model(&models.ReferralReward{}).
+				Where("referrer_id = ? AND status = ? AND paid_at >= ?", referrerID, models.ReferralRewardPaid, time.Now().Truncate(24*time.Hour)).
+				Select("COALESCE(SUM(amount), 0)").Scan(&paidToday)
+			if paidToday >= daily {
+				return fmt.Errorf("daily referral payout cap of %d GTK reached", daily)
+			}
+			// Pay in order until the cap is hit; the rest stays pending for tomorrow
+			var capped []models.ReferralReward
+			for _, r := range pending {
+				if paidToday+total+r.Amount > daily {
+					break
+				}
+				capped = append(capped, r)
+				total += r.Amount
+			}
+			pending = capped
+			if len(pending) == 0 {
+				return fmt.Errorf("daily referral payout cap of %d GTK reached", daily)
+			}
+		} else {
+			for _, r := range pending {
+				total += r.Amount
+			}
+		}
+
+		ids := make([]uint, len(pending))
+		for i, r := range pending {
+			ids[i] = r.ID
+		}
+
+		entries := []models.LedgerEntry{
+			{AccountID: rewardAcc.ID, Amount: -total, Type: "DEBIT"},
+			{AccountID: userAcc.ID, Amount: total, Type: "CREDIT"},
+		}
+		if err := s.ledger.CreateTransactionWithTx(tx, models.TxTypeReferralReward, fmt.Sprintf("referral_%d_%d", referrerID, ids[0]),
+			fmt.Sprintf("Referral Rewards (%d milestones)", len(ids)), entries); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if err := tx.Model(&models.ReferralReward{}).Where("id IN ?", ids).
+			Updates(map[string]interface{}{"status": models.ReferralRewardPaid, "paid_at": now}).Error; err != nil {
+			return err
+		}
+		return s.markCompletedReferrals(tx, referrerID)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// GetDashboard returns the referrer's code, referred players with milestone progress, and reward totals
+func (s *ReferralService) GetDashboard(userID uint) (*ReferralDashboard, error) {
+	code, err := s.GetOrCreateReferralCode(userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.EvaluateMilestones(userID); err != nil {
+		return nil, err
+	}
+
+	milestones := s.milestones()
+	dash := &ReferralDashboard{Code: code, Milestones: milestones}
+
+	var referrals []models.Referral
+	if err := db.DB.Preload("Referred").Where("referrer_id = ?", userID).Order("created_at DESC").Find(&referrals).Error; err != nil {
+		return nil, err
+	}
+
+	var rewards []models.ReferralReward
+	db.DB.Where("referrer_id = ?", userID).Order("created_at ASC").Find(&rewards)
+	byReferral := make(map[uint][]models.ReferralReward)
+	for _, r := range rewards {
+		byReferral[r.ReferralID] = append(byReferral[r.ReferralID], r)
+		switch r.Status {
+		case models.ReferralRewardPending:
+			dash.PendingReward += r.Amount
+		case models.ReferralRewardHeld:
+			dash.HeldReward += r.Amount
+		case models.ReferralRewardPaid:
+			dash.PaidReward += r.Amount
+		}
+	}
+
+	dash.TotalReferred = len(referrals)
+	for _, ref := range referrals {
+		rankedGames := s.rankedGames(ref.ReferredID)
+		progress := ReferralProgress{
+			ReferralID:        ref.ID,
+			UserID:            ref.ReferredID,
+			DisplayName:       ref.Referred.DisplayName,
+			WalletAddress:     ref.Referred.WalletAddress,
+			Level:             ref.Referred.Level,
+			RankedGames:       rankedGames,
+			Status:            ref.Status,
+			JoinedAt:          ref.CreatedAt,
+			Rewards:           byReferral[ref.ID],
+			AllMilestonesPaid: ref.RewardClaimed,
+		}
+		for i, m := range milestones {
+			if !milestoneReached(m, ref.Referred.Level, rankedGames) {
+				progress.NextMilestone = &milestones[i]
+				break
+			}
+		}
+		dash.Referrals = append(dash.Referrals, progress)
+	}
+	return dash, nil
+}
+
+// ReviewReferral resolves a flagged referral. Approving releases held rewards for claiming; rejecting voids them.
+func (s *ReferralService) ReviewReferral(referralID, adminID uint, approve bool, notes string) error {
+	var referral models.Referral
+	if err := db.DB.First(&referral, referralID).Error; err != nil {
+		return errors.New("referral not found")
+	}
+	if referral.Status != models.ReferralFlagged {
+		return errors.New("referral is not flagged")
+	}
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if approve {
+			if err := tx.Model(&referral).Update("status", models.ReferralApproved).Error; err != nil {
+				return err
+			}
+			return tx.Model(&models.ReferralReward{}).
+				Where("referral_id = ? AND status = ?", referral.ID, models.ReferralRewardHeld).
+				Update("status", models.ReferralRewardPending).Error
+		}
+		// Rejected: held rewards are dropped and the referral is closed out so no new milestones accrue
+		if err := tx.Where("referral_id = ? AND status = ?", referral.ID, models.ReferralRewardHeld).
+			Delete(&models.ReferralReward{}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&referral).Update("reward_claimed", true).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	action := "REJECT_REFERRAL"
+	if approve {
+		action = "APPROVE_REFERRAL"
+	}
+	s.admin.CreateAuditLog(adminID, action, fmt.Sprintf("%d", referralID), referral.FlagReason, notes)
+	return nil
+}
+
+// ListFlaggedReferrals returns referrals waiting for admin review
+func (s *ReferralService) ListFlaggedReferrals() ([]models.Referral, error) {
+	var referrals []models.Referral
+	err := db.DB.Preload("Referrer").Preload("Referred").
+		Where("status = ? AND reward_claimed = ?", models.ReferralFlagged, false).
+		Order("created_at DESC").Limit(100).Find(&referrals).Error
+	return referrals, err
+}
+
+// abuseReason runs the anti-cheat collusion heuristics on a referrer/referred pair
+func (s *ReferralService) abuseReason(referrerID, referredID uint) string {
+	if s.antiCheat == nil {
+		return ""
+	}
+	if s.antiCheat.sameIP(int(referrerID), int(referredID)) {
+		return "same_ip_address"
+	}
+	if s.antiCheat.connectedWallets(int(referrerID), int(referredID)) {
+		return "connected_wallets"
+	}
+	return ""
+}
+
+// recheckReferrals flags active referrals that now trip the abuse checks and holds their pending rewards
+func (s *ReferralService) recheckReferrals(referrerID uint) {
+	var active []models.Referral
+	db.DB.Where("referrer_id = ? AND status = ? AND reward_claimed = ?", referrerID, models.ReferralActive, false).Find(&active)
+
+	for i := range active {
+		reason := s.abuseReason(referrerID, active[i].ReferredID)
+		if reason == "" {
+			continue
+		}
+		active[i].Status = models.ReferralFlagged
+		active[i].FlagReason = reason
+		err := db.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Save(&active[i]).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&models.ReferralReward{}).
+				Where("referral_id = ? AND status = ?", active[i].ID, models.ReferralRewardPending).
+				Update("status", models.ReferralRewardHeld).Error; err != nil {
+				return err
+			}
+			return s.flagReferral(tx, &active[i])
+		})
+		if err != nil {
+			slog.Error("failed to flag referral", "referral_id", active[i].ID, "error", err)
+		}
+	}
+}
+
+// flagReferral raises an anti-cheat flag on the referrer so admins can review and release held rewards
+func (s *ReferralService) flagReferral(tx *gorm.DB, referral *models.Referral) error {
+	details, _ := json.Marshal(map[string]interface{}{
+		"reason":      referral.FlagReason,
+		"referral_id": referral.ID,
+		"referrer_id": referral.ReferrerID,
+		"referred_id": referral.ReferredID,
+	})
+	flag := models.AntiCheatFlag{
+		UserID:   referral.ReferrerID,
+		FlagType: "referral_abuse",
+		Severity: "medium",
+		Details:  string(details),
+		Status:   "pending",
+	}
+	if err := tx.Create(&flag).Error; err != nil {
+		return err
+	}
+	metrics.AntiCheatFlags.WithLabelValues(flag.Severity).Inc()
+	return nil
+}
+
+// markCompletedReferrals sets RewardClaimed once every configured milestone is paid
+func (s *ReferralService) markCompletedReferrals(tx *gorm.DB, referrerID uint) error {
+	total := len(s.milestones())
+	return tx.Model(&models.Referral{}).
+		Where("referrer_id = ? AND reward_claimed = ?", referrerID, false).
+		Where("(SELECT COUNT(*) FROM referral_rewards rr WHERE rr.referral_id = referrals.id AND rr.status = ?) >= ?", models.ReferralRewardPaid, total).
+		Update("reward_claimed", true).Error
+}
+
+func (s *ReferralService) milestones() []ReferralMilestone {
+	return ParseReferralMilestones(s.config.GetValue("referral_milestones", ""))
+}
+
+// ParseReferralMilestones reads the referral_milestones setting, falling back
+// to the defaults when it is empty or invalid
+func ParseReferralMilestones(raw string) []ReferralMilestone {
+	if raw == "" {
+		return defaultReferralMilestones
+	}
+	var milestones []ReferralMilestone
+	if err := json.Unmarshal([]byte(raw), &milestones); err != nil || len(milestones) == 0 {
+		slog.Warn("invalid referral_milestones setting, using defaults", "error", err)
+		return defaultReferralMilestones
+	}
+	return milestones
+}
+
+func (s *ReferralService) rankedGames(userID uint) int64 {
+	var count int64
+	db.DB.Model(&models.Battle{}).
+		Where("battle_type = ? AND status = ? AND (player1_id = ? OR player2_id = ?)", "ranked", "completed", userID, userID).
+		Count(&count)
+	return count
+}
+
+func milestoneReached(m ReferralMilestone, level int, rankedGames int64) bool {
+	switch m.Type {
+	case "level":
+		return level >= m.Threshold
+	case "ranked_games":
+		return rankedGames >= int64(m.Threshold)
+	}
+	return false
+}
+
+func generateReferralCode(length int) (string, error) {
+	buf := make([]byte, length)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	for i := range buf {
+		buf[i] = referralCodeAlphabet[int(buf[i])%len(referralCodeAlphabet)]
+	}
+	return string(buf), nil
+}
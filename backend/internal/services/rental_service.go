@@ -0,0 +1,359 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/internal/repository"
+)
+
+// RentalTerms are what an owner asks of whoever borrows a character
+type RentalTerms struct {
+	BorrowerID    *uint `json:"borrower_id"`   // Offer to one player; nil lets anyone accept
+	Fee           int64 `json:"fee"`           // GTK paid up front
+	SharePercent  int   `json:"share_percent"` // Owner's cut of what the character earns
+	DurationHours int   `json:"duration_hours" binding:"required"`
+}
+
+// RentalService lends characters for a fixed term. The borrower controls a rented character
+// (Character.ControllerID) and fields it in raids, PvE and ranked; the owner keeps ownership,
+// so neither side can list, breed or transfer it until it comes back.
+type RentalService struct {
+	store    repository.Store
+	ledger   *LedgerService
+	config   Settings
+	notifier Notifier
+}
+
+// NewRentalService creates the rental service
+func NewRentalService(store repository.Store, ledger *LedgerService, config Settings, notifier Notifier) *RentalService {
+	return &RentalService{
+		store:    store,
+		ledger:   ledger,
+		config:   config,
+		notifier: notifier,
+	}
+}
+
+// OfferRental puts an owned character up for rent on terms
+func (s *RentalService) OfferRental(ownerID, characterID uint, terms RentalTerms) (*models.Rental, error) {
+	minHours := s.config.GetInt("rental_min_hours", 1)
+	maxHours := s.config.GetInt("rental_max_hours", 720)
+	if terms.DurationHours < minHours || terms.DurationHours > maxHours {
+		return nil, fmt.Errorf("rental duration must be between %d and %d hours", minHours, maxHours)
+	}
+	if terms.Fee < 0 {
+		return nil, errors.New("fee cannot be negative")
+	}
+	if maxShare := s.config.GetInt("rental_max_share_percent", 90); terms.SharePercent < 0 || terms.SharePercent > maxShare {
+		return nil, fmt.Errorf("revenue share must be between 0 and %d percent", maxShare)
+	}
+	if terms.BorrowerID != nil && *terms.BorrowerID == ownerID {
+		return nil, errors.New("cannot rent a character to yourself")
+	}
+
+	rental := models.Rental{
+		CharacterID:   characterID,
+		OwnerID:       ownerID,
+		BorrowerID:    terms.BorrowerID,
+		Fee:           terms.Fee,
+		SharePercent:  terms.SharePercent,
+		DurationHours: terms.DurationHours,
+		Status:        models.RentalStatusOffered,
+	}
+	err := s.store.Transaction(func(tx repository.Store) error {
+		c, err := tx.Characters().Get(characterID)
+		if err != nil || c.OwnerID != ownerID {
+			return errors.New("you don't own this character")
+		}
+		if err := rentable(c); err != nil {
+			return err
+		}
+		if _, err := tx.Rentals().Open(characterID); err == nil {
+			return errors.New("character already has an open rental")
+		} else if !errors.Is(err, repository.ErrNotFound) {
+			return err
+		}
+		return tx.Rentals().Create(&rental)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &rental, nil
+}
+
+// rentable checks a character can change hands for a while
+func rentable(c *models.Character) error {
+	switch {
+	case c.ControllerID != nil:
+		return errors.New("character is already rented out")
+	case c.IsListed:
+		return errors.New("cannot rent out a listed character")
+	case c.IsDead:
+		return errors.New("cannot rent out a dead character")
+	}
+	return nil
+}
+
+// CancelOffer withdraws an offer nobody has accepted yet
+func (s *RentalService) CancelOffer(ownerID, rentalID uint) error {
+	return s.store.Transaction(func(tx repository.Store) error {
+		rental, err := tx.Rentals().Lock(rentalID)
+		if err != nil || rental.OwnerID != ownerID {
+			return errors.New("rental not found")
+		}
+		if rental.Status != models.RentalStatusOffered {
+			return errors.New("rental is no longer an open offer")
+		}
+		now := time.Now()
+		rental.Status = models.RentalStatusCancelled
+		rental.EndedAt = &now
+		return tx.Rentals().Save(rental)
+	})
+}
+
+// AcceptRental takes an offer: the borrower pays the fee to the owner and controls the
+// character until the term ends. The character leaves the owner's teams.
+func (s *RentalService) AcceptRental(borrowerID, rentalID uint) (*models.Rental, error) {
+	var rental *models.Rental
+	err := s.store.Transaction(func(tx repository.Store) error {
+		var err error
+		if rental, err = tx.Rentals().Lock(rentalID); err != nil {
+			return errors.New("rental not found")
+		}
+		if rental.Status != models.RentalStatusOffered {
+			return errors.New("rental is no longer an open offer")
+		}
+		if rental.OwnerID == borrowerID {
+			return errors.New("cannot rent your own character")
+		}
+		if rental.BorrowerID != nil && *rental.BorrowerID != borrowerID {
+			return errors.New("rental not found")
+		}
+
+		c, err := tx.Characters().Get(rental.CharacterID)
+		if err != nil || c.OwnerID != rental.OwnerID {
+			return errors.New("character is no longer available")
+		}
+		if err := rentable(c); err != nil {
+			return err
+		}
+
+		if rental.Fee > 0 {
+			ledger := s.ledger.WithStore(tx)
+			borrowerAcc, err := ledger.GetOrCreateAccount(&borrowerID, models.AccountTypeWallet, "GTK")
+			if err != nil {
+				return err
+			}
+			if borrowerAcc.Balance < rental.Fee {
+				return errors.New("insufficient GTK balance")
+			}
+			ownerAcc, err := ledger.GetOrCreateAccount(&rental.OwnerID, models.AccountTypeWallet, "GTK")
+			if err != nil {
+				return err
+			}
+			entries := []models.LedgerEntry{
+				{AccountID: borrowerAcc.ID, Amount: -rental.Fee, Type: "DEBIT"},
+				{AccountID: ownerAcc.ID, Amount: rental.Fee, Type: "CREDIT"},
+			}
+			if err := ledger.CreateTransaction(models.TxTypeRentalFee, fmt.Sprintf("rental_%d_fee", rental.ID),
+				fmt.Sprintf("Rental fee: character #%d", c.ID), entries); err != nil {
+				return err
+			}
+		}
+
+		c.ControllerID = &borrowerID
+		if err := tx.Characters().Save(c); err != nil {
+			return err
+		}
+		if err := tx.Characters().RemoveFromTeams(c.ID, rental.OwnerID); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		ends := now.Add(time.Duration(rental.DurationHours) * time.Hour)
+		rental.BorrowerID = &borrowerID
+		rental.Status = models.RentalStatusActive
+		rental.StartedAt, rental.EndsAt = &now, &ends
+		rental.OwnerEarnings += rental.Fee
+		return tx.Rentals().Save(rental)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notify(rental.OwnerID, "RENTAL_STARTED", "Character Rented",
+		fmt.Sprintf("Your character #%d is rented out until %s", rental.CharacterID, rental.EndsAt.Format(time.RFC1123)),
+		map[string]interface{}{"rental_id": rental.ID, "character_id": rental.CharacterID})
+	return rental, nil
+}
+
+// ReturnRental lets the borrower hand a character back before the term ends. The fee is
+// not refunded.
+func (s *RentalService) ReturnRental(borrowerID, rentalID uint) error {
+	var rental *models.Rental
+	err := s.store.Transaction(func(tx repository.Store) error {
+		var err error
+		if rental, err = tx.Rentals().Lock(rentalID); err != nil {
+			return errors.New("rental not found")
+		}
+		if rental.BorrowerID == nil || *rental.BorrowerID != borrowerID {
+			return errors.New("rental not found")
+		}
+		if rental.Status != models.RentalStatusActive {
+			return errors.New("rental is not active")
+		}
+		return endRentalTx(tx, rental)
+	})
+	if err != nil {
+		return err
+	}
+	s.notify(rental.OwnerID, "RENTAL_ENDED", "Character Returned",
+		fmt.Sprintf("Your character #%d was returned early", rental.CharacterID),
+		map[string]interface{}{"rental_id": rental.ID, "character_id": rental.CharacterID})
+	return nil
+}
+
+// endRentalTx gives control back to the owner and takes the character out of the
+// borrower's teams
+func endRentalTx(tx repository.Store, rental *models.Rental) error {
+	c, err := tx.Characters().Get(rental.CharacterID)
+	if err != nil {
+		return err
+	}
+	c.ControllerID = nil
+	if err := tx.Characters().Save(c); err != nil {
+		return err
+	}
+	if err := tx.Characters().RemoveFromTeams(c.ID, *rental.BorrowerID); err != nil {
+		return err
+	}
+	now := time.Now()
+	rental.Status = models.RentalStatusEnded
+	rental.EndedAt = &now
+	return tx.Rentals().Save(rental)
+}
+
+// ListOffers returns the offers a player can accept
+func (s *RentalService) ListOffers(userID uint) ([]models.Rental, error) {
+	return s.store.Rentals().ListOffers(userID)
+}
+
+// ListRentals returns the rentals a player lent out or borrowed
+func (s *RentalService) ListRentals(userID uint) ([]models.Rental, error) {
+	return s.store.Rentals().ListForUser(userID)
+}
+
+// ProcessExpired returns every character whose rental term is over
+func (s *RentalService) ProcessExpired() error {
+	due, err := s.store.Rentals().ListDue(time.Now())
+	if err != nil {
+		return err
+	}
+	for _, r := range due {
+		var ended *models.Rental
+		err := s.store.Transaction(func(tx repository.Store) error {
+			rental, err := tx.Rentals().Lock(r.ID)
+			if err != nil {
+				return err
+			}
+			// Returned early since the scan
+			if rental.Status != models.RentalStatusActive {
+				return nil
+			}
+			ended = rental
+			return endRentalTx(tx, rental)
+		})
+		if err != nil {
+			slog.Error("failed to end rental", "rental_id", r.ID, "error", err)
+			continue
+		}
+		if ended != nil {
+			data := map[string]interface{}{"rental_id": ended.ID, "character_id": ended.CharacterID}
+			s.notify(ended.OwnerID, "RENTAL_ENDED", "Character Returned",
+				fmt.Sprintf("The rental of your character #%d ended; it earned you %d GTK", ended.CharacterID, ended.OwnerEarnings), data)
+			s.notify(*ended.BorrowerID, "RENTAL_ENDED", "Rental Over",
+				fmt.Sprintf("Your rental of character #%d ended and it went back to its owner", ended.CharacterID), data)
+		}
+	}
+	return nil
+}
+
+// StartScheduler runs ProcessExpired on a fixed interval in the background
+func (s *RentalService) StartScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := s.ProcessExpired(); err != nil {
+				slog.Error("rental expiry failed", "error", err)
+			}
+		}
+	}()
+}
+
+// notify sends a best-effort rental notification
+func (s *RentalService) notify(userID uint, notifType, title, message string, data interface{}) {
+	if err := s.notifier.CreateNotification(userID, notifType, title, message, data); err != nil {
+		slog.Warn("failed to send rental notification", "user_id", userID, "type", notifType, "error", err)
+	}
+}
+
+// controlledTeam returns the members of a player's active team they actually control:
+// characters they borrowed count, characters they lent out do not
+func controlledTeam(st repository.Store, userID uint) ([]models.Character, error) {
+	team, err := st.Characters().ActiveTeam(userID)
+	if err != nil {
+		return nil, err
+	}
+	controlled := team[:0]
+	for _, c := range team {
+		if c.Controller() == userID {
+			controlled = append(controlled, c)
+		}
+	}
+	return controlled, nil
+}
+
+// rentalShares splits what earnerID made with team between them and the owners of the
+// characters they borrowed: every team member holds an equal slice of amount, and a rented
+// member hands its rental's SharePercent of that slice to its owner. It returns the owners'
+// ledger credits and their total, which the caller takes off the earner's credit.
+func rentalShares(tx repository.Store, ledger *LedgerService, earnerID uint, team []models.Character, amount int64) ([]models.LedgerEntry, int64, error) {
+	if amount <= 0 || len(team) == 0 {
+		return nil, 0, nil
+	}
+	var entries []models.LedgerEntry
+	var total int64
+	for _, c := range team {
+		if c.ControllerID == nil || *c.ControllerID != earnerID {
+			continue
+		}
+		rental, err := tx.Rentals().Open(c.ID)
+		if errors.Is(err, repository.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		cut := amount * int64(rental.SharePercent) / 100 / int64(len(team))
+		if rental.Status != models.RentalStatusActive || cut <= 0 {
+			continue
+		}
+		ownerAcc, err := ledger.GetOrCreateAccount(&rental.OwnerID, models.AccountTypeWallet, "GTK")
+		if err != nil {
+			return nil, 0, err
+		}
+		entries = append(entries, models.LedgerEntry{AccountID: ownerAcc.ID, Amount: cut, Type: "CREDIT"})
+		total += cut
+
+		rental.OwnerEarnings += cut
+		if err := tx.Rentals().Save(rental); err != nil {
+			return nil, 0, err
+		}
+	}
+	return entries, total, nil
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/internal/repository"
+)
+
+// newTestRental lends a fresh character of owner to borrower on the given terms
+func newTestRental(t *testing.T, st *repository.MemoryStore, ledger *LedgerService, owner, borrower *models.User, terms RentalTerms) (*RentalService, *models.Rental, *models.Character) {
+	t.Helper()
+	svc := NewRentalService(st, ledger, testSettings{}, &testNotifier{})
+	char := newTestCharacter(t, st, owner.ID, 100, 50)
+	st.SetActiveTeam(owner.ID, char.ID)
+
+	offer, err := svc.OfferRental(owner.ID, char.ID, terms)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rental, err := svc.AcceptRental(borrower.ID, offer.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return svc, rental, char
+}
+
+func TestRentalHandsControlButNotOwnership(t *testing.T) {
+	st := repository.NewMemoryStore()
+	market, ledger, _ := newTestMarketplace(st)
+	owner := newTestTrader(t, st, ledger, 0)
+	borrower := newTestTrader(t, st, ledger, 500)
+	stranger := newTestUser(t, st, 1000)
+
+	svc := NewRentalService(st, ledger, testSettings{}, &testNotifier{})
+	char := newTestCharacter(t, st, owner.ID, 100, 50)
+	st.SetActiveTeam(owner.ID, char.ID)
+	offer, err := svc.OfferRental(owner.ID, char.ID, RentalTerms{BorrowerID: &borrower.ID, Fee: 200, DurationHours: 24})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.AcceptRental(stranger.ID, offer.ID); err == nil {
+		t.Fatal("a stranger took an offer made to someone else")
+	}
+	if _, err := svc.AcceptRental(borrower.ID, offer.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	got, _ := st.Characters().Get(char.ID)
+	if got.OwnerID != owner.ID || got.Controller() != borrower.ID {
+		t.Fatalf("owner %d controller %d, want owner kept and borrower in control", got.OwnerID, got.Controller())
+	}
+	if b := balance(t, ledger, &owner.ID, models.AccountTypeWallet); b != 200 {
+		t.Fatalf("owner wallet = %d, want the 200 fee", b)
+	}
+
+	// Neither side can sell, breed or re-rent it
+	for _, userID := range []uint{owner.ID, borrower.ID} {
+		if _, err := market.CreateListing(userID, "character", char.ID, 100, "GTK"); err == nil {
+			t.Fatalf("user %d listed a rented character", userID)
+		}
+	}
+	if _, err := market.CreateAuction(owner.ID, AssetRef{AssetType: "character", AssetID: char.ID}, 100, 0, 24); err == nil {
+		t.Fatal("owner auctioned a rented character")
+	}
+	mate := newTestCharacter(t, st, owner.ID, 100, 50)
+	if _, err := NewBreedingService(st, nil, ledger, testSettings{}).PreviewBreeding(owner.ID, char.ID, mate.ID); err == nil {
+		t.Fatal("owner bred a rented character")
+	}
+	if _, err := svc.OfferRental(owner.ID, char.ID, RentalTerms{DurationHours: 24}); err == nil {
+		t.Fatal("rented character offered again")
+	}
+
+	// The character fights for the borrower, not the owner
+	battles, _ := newTestBattleService(st)
+	if _, err := battles.GetTeamSnapshot(owner.ID); err == nil {
+		t.Fatal("owner still fields a lent character")
+	}
+	st.SetActiveTeam(borrower.ID, char.ID)
+	if team, err := battles.GetTeamSnapshot(borrower.ID); err != nil || len(team) != 1 {
+		t.Fatalf("borrower team = %v (%v), want the rented character", team, err)
+	}
+}
+
+func TestRentalShareSplitsEarnings(t *testing.T) {
+	st := repository.NewMemoryStore()
+	battles, ledger := newTestBattleService(st)
+	owner := newTestUser(t, st, 1000)
+	borrower, own := newTestPlayer(t, st, 300, 100)
+	fund(t, ledger, borrower.ID, 100)
+	_, rental, char := newTestRental(t, st, ledger, owner, borrower, RentalTerms{SharePercent: 50, DurationHours: 24})
+	st.SetActiveTeam(borrower.ID, own.ID, char.ID)
+
+	battle, err := battles.CreatePvEBattle(borrower.ID, "PVE_STORY")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := battles.CompleteBattle(context.Background(), battle.ID, borrower.ID, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// 10 GTK over two characters; the rented one hands half of its 5 to the owner
+	if b := balance(t, ledger, &owner.ID, models.AccountTypeWallet); b != 2 {
+		t.Fatalf("owner wallet = %d, want 2", b)
+	}
+	if b := balance(t, ledger, &borrower.ID, models.AccountTypeWallet); b != 108 {
+		t.Fatalf("borrower wallet = %d, want 108", b)
+	}
+	if got, _ := st.Rentals().Get(rental.ID); got.OwnerEarnings != 2 {
+		t.Fatalf("owner earnings = %d, want 2", got.OwnerEarnings)
+	}
+}
+
+func TestExpiredRentalReturnsCharacter(t *testing.T) {
+	st := repository.NewMemoryStore()
+	ledger := NewLedgerService(st)
+	owner := newTestUser(t, st, 1000)
+	borrower := newTestUser(t, st, 1000)
+	svc, rental, char := newTestRental(t, st, ledger, owner, borrower, RentalTerms{DurationHours: 1})
+	st.SetActiveTeam(borrower.ID, char.ID)
+
+	if err := svc.ProcessExpired(); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := st.Characters().Get(char.ID); got.ControllerID == nil {
+		t.Fatal("character returned before its term ended")
+	}
+
+	past := time.Now().Add(-time.Minute)
+	rental.EndsAt = &past
+	if err := st.Rentals().Save(rental); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.ProcessExpired(); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := st.Characters().Get(char.ID)
+	if got.ControllerID != nil || got.Controller() != owner.ID {
+		t.Fatalf("controller = %d, want the owner back in control", got.Controller())
+	}
+	if r, _ := st.Rentals().Get(rental.ID); r.Status != models.RentalStatusEnded {
+		t.Fatalf("status = %s, want ENDED", r.Status)
+	}
+	if team, _ := st.Characters().ActiveTeam(borrower.ID); len(team) != 0 {
+		t.Fatalf("borrower team still holds %d characters", len(team))
+	}
+	if err := svc.ReturnRental(borrower.ID, rental.ID); err == nil {
+		t.Fatal("ended rental returned again")
+	}
+}
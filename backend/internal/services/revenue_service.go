@@ -22,11 +22,11 @@ type RevenueService struct {
 // NewRevenueService creates a new revenue service
 // Note: LedgerService is now required, but for backward compat in main.go we might inject it later or change constructor
 // For now, we instantiate a new one if not passed (or update constructor call in main.go)
-func NewRevenueService(database *sql.DB) *RevenueService {
+func NewRevenueService(database *sql.DB, ledger *LedgerService) *RevenueService {
 	return &RevenueService{
 		db:     database,
 		gdb:    db.DB,
-		ledger: NewLedgerService(), // Connect to Ledger
+		ledger: ledger, // Connect to Ledger
 	}
 }
 
@@ -37,16 +37,7 @@ func (s *RevenueService) DistributeGTKRevenue(source string, amount float64) err
 		return fmt.Errorf("invalid amount: %f", amount)
 	}
 
-	dist := models.RevenueDistribution{
-		Source:         source,
-		TotalAmount:    amount,
-		GrowthFund:     amount * 0.10,
-		SecurityFund:   amount * 0.10,
-		Operations:     amount * 0.05,
-		RewardsPool:    amount * 0.30,
-		DevTeam:        amount * 0.20,
-		TowerLiquidity: amount * 0.25,
-	}
+	dist := SplitRevenue(source, amount)
 
 	// Ledger Integration:
 	// Debit: Input Source (e.g., User Fees collected, or System Mint)
@@ -72,6 +63,20 @@ func (s *RevenueService) DistributeGTKRevenue(source string, amount float64) err
 	return err
 }
 
+// SplitRevenue divides amount across the six revenue buckets
+func SplitRevenue(source string, amount float64) models.RevenueDistribution {
+	return models.RevenueDistribution{
+		Source:         source,
+		TotalAmount:    amount,
+		GrowthFund:     amount * 0.10,
+		SecurityFund:   amount * 0.10,
+		Operations:     amount * 0.05,
+		RewardsPool:    amount * 0.30,
+		DevTeam:        amount * 0.20,
+		TowerLiquidity: amount * 0.25,
+	}
+}
+
 // ProcessTransaction executes a secure transaction between users using Ledger
 // Replaces old direct balance modification
 func (s *RevenueService) ProcessTransaction(ctx context.Context, fromUserID, toUserID uint, amount int64, reason string) error {
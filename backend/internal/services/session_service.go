@@ -0,0 +1,169 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/db"
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Session revocation reasons
+const (
+	SessionRevokedLogout     = "LOGOUT"
+	SessionRevokedLogoutAll  = "LOGOUT_ALL"
+	SessionRevokedBanned     = "BANNED"
+	SessionRevokedTokenReuse = "TOKEN_REUSE"
+)
+
+// TokenPair is returned on login and on every refresh
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"` // Access token lifetime in seconds
+	SessionID    uint   `json:"session_id"`
+}
+
+// CreateSession opens a server-side session for a verified user and issues the first token pair
+func (s *AuthService) CreateSession(user *models.User, userAgent, ipAddress string) (*TokenPair, error) {
+	refresh, hash, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session := models.UserSession{
+		UserID:           user.ID,
+		RefreshTokenHash: hash,
+		UserAgent:        truncate(userAgent, 255),
+		IPAddress:        ipAddress,
+		LastUsedAt:       now,
+		ExpiresAt:        now.Add(s.cfg.RefreshTokenTTL),
+	}
+	if err := db.DB.Create(&session).Error; err != nil {
+		return nil, err
+	}
+	return s.issuePair(user, session.ID, refresh)
+}
+
+// RefreshSession rotates the refresh token and issues a new access token.
+// Presenting an already-rotated token revokes the session: someone else holds a copy.
+func (s *AuthService) RefreshSession(refreshToken, userAgent, ipAddress string) (*TokenPair, error) {
+	hash := hashRefreshToken(refreshToken)
+	var (
+		session models.UserSession
+		user    models.User
+		next    string
+	)
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("refresh_token_hash = ?", hash).First(&session).Error; err != nil {
+			return errRefreshNotFound
+		}
+		if session.RevokedAt != nil {
+			return errors.New("session has been revoked")
+		}
+		if time.Now().After(session.ExpiresAt) {
+			return errors.New("session has expired, please sign in again")
+		}
+
+		if err := tx.First(&user, session.UserID).Error; err != nil {
+			return errors.New("user not found")
+		}
+		if user.IsBanned || user.Status == "BANNED" {
+			return errors.New("account suspended")
+		}
+
+		var newHash string
+		var err error
+		if next, newHash, err = newRefreshToken(); err != nil {
+			return err
+		}
+		return tx.Model(&session).Updates(map[string]interface{}{
+			"refresh_token_hash": newHash,
+			"prev_token_hash":    hash,
+			"last_used_at":       time.Now(),
+			"user_agent":         truncate(userAgent, 255),
+			"ip_address":         ipAddress,
+		}).Error
+	})
+	if errors.Is(err, errRefreshNotFound) {
+		// Reuse of a rotated-out token: revoke the session it belonged to (outside the rolled-back transaction)
+		res := db.DB.Model(&models.UserSession{}).
+			Where("prev_token_hash = ? AND revoked_at IS NULL", hash).
+			Updates(map[string]interface{}{"revoked_at": time.Now(), "revoked_by": SessionRevokedTokenReuse})
+		if res.Error == nil && res.RowsAffected > 0 {
+			return nil, errors.New("refresh token reuse detected; session revoked")
+		}
+		return nil, errors.New("invalid refresh token")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.issuePair(&user, session.ID, next)
+}
+
+// ListSessions returns the user's active sessions, most recently used first
+func (s *AuthService) ListSessions(userID uint) ([]models.UserSession, error) {
+	var sessions []models.UserSession
+	err := db.DB.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_used_at DESC").Find(&sessions).Error
+	return sessions, err
+}
+
+// RevokeSession logs out a single session owned by the user
+func (s *AuthService) RevokeSession(userID, sessionID uint) error {
+	res := db.DB.Model(&models.UserSession{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", sessionID, userID).
+		Updates(map[string]interface{}{"revoked_at": time.Now(), "revoked_by": SessionRevokedLogout})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errors.New("session not found")
+	}
+	return nil
+}
+
+// RevokeUserSessions revokes every active session of a user (logout everywhere, bans).
+// AuthMiddleware checks the session on each request, so access tokens stop working immediately.
+func RevokeUserSessions(userID uint, reason string) error {
+	return db.DB.Model(&models.UserSession{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Updates(map[string]interface{}{"revoked_at": time.Now(), "revoked_by": reason}).Error
+}
+
+var errRefreshNotFound = errors.New("refresh token not found")
+
+func (s *AuthService) issuePair(user *models.User, sessionID uint, refresh string) (*TokenPair, error) {
+	access, err := s.GenerateJWT(user, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenPair{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		ExpiresIn:    int(s.cfg.AccessTokenTTL.Seconds()),
+		SessionID:    sessionID,
+	}, nil
+}
+
+// newRefreshToken returns an opaque token and the hash stored server-side
+func newRefreshToken() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	token := hex.EncodeToString(buf)
+	return token, hashRefreshToken(token), nil
+}
+
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"errors"
 	"fmt"
+	"log/slog"
 	"math/big"
 	"time"
 
@@ -18,9 +19,9 @@ type ShopService struct {
 }
 
 // NewShopService creates a new shop service
-func NewShopService(bc *BlockchainService) *ShopService {
+func NewShopService(bc *BlockchainService, ledger *LedgerService) *ShopService {
 	return &ShopService{
-		ledger:     NewLedgerService(),
+		ledger:     ledger,
 		blockchain: bc,
 	}
 }
@@ -74,7 +75,7 @@ func (s *ShopService) BuyItem(userID, itemID uint, quantity int, txHash string)
 		}
 	} else {
 		// Log warning if blockchain service not active (DEV MODE)
-		fmt.Println("⚠️ WARNING: Blockchain verification skipped (Service nil)")
+		slog.Warn("blockchain verification skipped: service unavailable")
 	}
 
 	// Get user
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const siweHeaderSuffix = " wants you to sign in with your Ethereum account:"
+
+// SIWEMessage is an EIP-4361 Sign-In With Ethereum message
+type SIWEMessage struct {
+	Domain         string
+	Address        string
+	Statement      string
+	URI            string
+	Version        string
+	ChainID        int
+	Nonce          string
+	IssuedAt       time.Time
+	ExpirationTime *time.Time
+	NotBefore      *time.Time
+}
+
+// String renders the message in the exact EIP-4361 layout the wallet signs
+func (m *SIWEMessage) String() string {
+	var b strings.Builder
+	b.WriteString(m.Domain + siweHeaderSuffix + "\n")
+	b.WriteString(m.Address + "\n\n")
+	if m.Statement != "" {
+		b.WriteString(m.Statement + "\n\n")
+	}
+	b.WriteString("URI: " + m.URI + "\n")
+	b.WriteString("Version: " + m.Version + "\n")
+	b.WriteString("Chain ID: " + strconv.Itoa(m.ChainID) + "\n")
+	b.WriteString("Nonce: " + m.Nonce + "\n")
+	b.WriteString("Issued At: " + m.IssuedAt.UTC().Format(time.RFC3339))
+	if m.ExpirationTime != nil {
+		b.WriteString("\nExpiration Time: " + m.ExpirationTime.UTC().Format(time.RFC3339))
+	}
+	if m.NotBefore != nil {
+		b.WriteString("\nNot Before: " + m.NotBefore.UTC().Format(time.RFC3339))
+	}
+	return b.String()
+}
+
+// ParseSIWEMessage parses an EIP-4361 message. Request ID and Resources are accepted but ignored.
+func ParseSIWEMessage(raw string) (*SIWEMessage, error) {
+	lines := strings.Split(strings.ReplaceAll(raw, "\r\n", "\n"), "\n")
+	if len(lines) < 8 || !strings.HasSuffix(lines[0], siweHeaderSuffix) {
+		return nil, errors.New("not a Sign-In With Ethereum message")
+	}
+
+	m := &SIWEMessage{
+		Domain:  strings.TrimSuffix(lines[0], siweHeaderSuffix),
+		Address: lines[1],
+	}
+	if lines[2] != "" {
+		return nil, errors.New("malformed SIWE message: expected blank line after address")
+	}
+
+	i := 3
+	if !strings.HasPrefix(lines[i], "URI: ") {
+		m.Statement = lines[i]
+		i++
+		if i >= len(lines) || lines[i] != "" {
+			return nil, errors.New("malformed SIWE message: expected blank line after statement")
+		}
+		i++
+	}
+
+	for ; i < len(lines); i++ {
+		key, value, ok := strings.Cut(lines[i], ": ")
+		if !ok {
+			// "Resources:" has no inline value and is followed by "- uri" lines
+			continue
+		}
+		var err error
+		switch key {
+		case "URI":
+			m.URI = value
+		case "Version":
+			m.Version = value
+		case "Chain ID":
+			m.ChainID, err = strconv.Atoi(value)
+		case "Nonce":
+			m.Nonce = value
+		case "Issued At":
+			m.IssuedAt, err = time.Parse(time.RFC3339, value)
+		case "Expiration Time":
+			var t time.Time
+			t, err = time.Parse(time.RFC3339, value)
+			m.ExpirationTime = &t
+		case "Not Before":
+			var t time.Time
+			t, err = time.Parse(time.RFC3339, value)
+			m.NotBefore = &t
+		}
+		if err != nil {
+			return nil, fmt.Errorf("malformed SIWE field %q: %w", key, err)
+		}
+	}
+
+	if m.URI == "" || m.Version == "" || m.Nonce == "" || m.IssuedAt.IsZero() {
+		return nil, errors.New("SIWE message is missing required fields")
+	}
+	return m, nil
+}
@@ -0,0 +1,311 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image/png"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/db"
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/pkg/tracing"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// spriteStaleAfter is how long a job may sit in processing without a progress update
+// before another worker assumes its runner died and reclaims it
+const spriteStaleAfter = 10 * time.Minute
+
+// SpriteJobService runs the sprite generation queue: it claims pending jobs, renders every
+// animation through a SpriteProvider, writes the sheets and publishes them on the character
+type SpriteJobService struct {
+	provider  SpriteProvider
+	outputDir string
+	baseURL   string
+	config    *ConfigService
+}
+
+// NewSpriteJobService creates the sprite worker. Sheets are written under outputDir and
+// served from baseURL (e.g. /sprites/42/idle.png).
+func NewSpriteJobService(provider SpriteProvider, outputDir, baseURL string) *SpriteJobService {
+	return &SpriteJobService{
+		provider:  provider,
+		outputDir: outputDir,
+		baseURL:   strings.TrimSuffix(baseURL, "/"),
+		config:    GetConfigService(),
+	}
+}
+
+// Enqueue queues sprite generation for a character unless a job is already pending or running
+func (s *SpriteJobService) Enqueue(characterID uint) (*models.SpriteGenerationJob, error) {
+	var job models.SpriteGenerationJob
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		var char models.Character
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&char, characterID).Error; err != nil {
+			return errors.New("character not found")
+		}
+
+		err := tx.Where("character_id = ? AND status IN ?", characterID, []string{models.SpriteJobPending, models.SpriteJobProcessing}).
+			First(&job).Error
+		if err == nil {
+			// Already queued; make sure the character points at it
+			return tx.Model(&char).Update("sprite_gen_job_id", job.ID).Error
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		job = models.SpriteGenerationJob{
+			CharacterID: characterID,
+			Status:      models.SpriteJobPending,
+			Provider:    s.provider.Name(),
+			MaxRetries:  s.config.GetInt("sprite_job_max_retries", 3),
+		}
+		if err := tx.Create(&job).Error; err != nil {
+			return err
+		}
+		return tx.Model(&char).Updates(map[string]interface{}{
+			"sprite_gen_status": models.SpriteJobPending,
+			"sprite_gen_job_id": job.ID,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// GetJob returns the character's latest sprite job, scoped to the owner
+func (s *SpriteJobService) GetJob(characterID, userID uint) (*models.SpriteGenerationJob, *models.Character, error) {
+	var char models.Character
+	if err := db.DB.Where("id = ? AND owner_id = ?", characterID, userID).First(&char).Error; err != nil {
+		return nil, nil, errors.New("character not found")
+	}
+	var job models.SpriteGenerationJob
+	if err := db.DB.Where("character_id = ?", characterID).Order("id DESC").First(&job).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &char, nil
+		}
+		return nil, nil, err
+	}
+	return &job, &char, nil
+}
+
+// StartScheduler runs ProcessBatch on an interval
+func (s *SpriteJobService) StartScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := s.ProcessBatch(context.Background()); err != nil {
+				slog.Error("sprite worker error", "error", err)
+			}
+		}
+	}()
+}
+
+// ProcessBatch queues characters still waiting for art, then claims and runs up to
+// sprite_job_batch_size jobs. Safe to run from several instances at once.
+func (s *SpriteJobService) ProcessBatch(ctx context.Context) error {
+	if err := s.enqueueMissing(); err != nil {
+		return err
+	}
+
+	jobs, err := s.claim(s.config.GetInt("sprite_job_batch_size", 5))
+	if err != nil {
+		return err
+	}
+	for i := range jobs {
+		s.process(ctx, &jobs[i])
+	}
+	return nil
+}
+
+// enqueueMissing creates jobs for characters marked pending that were never queued
+// (characters created before the worker existed, or by paths that don't enqueue)
+func (s *SpriteJobService) enqueueMissing() error {
+	var ids []uint
+	if err := db.DB.Model(&models.Character{}).
+		Where("sprite_gen_status = ? AND sprite_gen_job_id IS NULL", models.SpriteJobPending).
+		Order("id ASC").Limit(s.config.GetInt("sprite_job_batch_size", 5)).
+		Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if _, err := s.Enqueue(id); err != nil {
+			slog.Warn("failed to queue sprite job", "character_id", id, "error", err)
+		}
+	}
+	return nil
+}
+
+// claim atomically moves due jobs to processing. SKIP LOCKED lets concurrent workers
+// take disjoint batches instead of blocking on each other's rows. Reclaiming a stale job
+// counts as a retry, since the worker that held it died mid-attempt.
+func (s *SpriteJobService) claim(limit int) ([]models.SpriteGenerationJob, error) {
+	var jobs []models.SpriteGenerationJob
+	err := db.DB.Raw(`
+		UPDATE sprite_generation_jobs
+		SET status = ?, updated_at = NOW(),
+		    retry_count = retry_count + CASE WHEN status = ? THEN 1 ELSE 0 END
+		WHERE id IN (
+			SELECT id FROM sprite_generation_jobs
+			WHERE (status = ? AND (next_run_at IS NULL OR next_run_at <= NOW()))
+			   OR (status = ? AND updated_at < ?)
+			ORDER BY created_at ASC
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		models.SpriteJobProcessing, models.SpriteJobProcessing,
+		models.SpriteJobPending,
+		models.SpriteJobProcessing, time.Now().Add(-spriteStaleAfter),
+		limit,
+	).Scan(&jobs).Error
+	return jobs, err
+}
+
+// process renders every animation of a claimed job; failures go back to the queue with backoff
+func (s *SpriteJobService) process(ctx context.Context, job *models.SpriteGenerationJob) {
+	ctx, span := tracing.Start(ctx, "sprite.job", "job_id", job.ID, "character_id", job.CharacterID)
+	log := slog.With("job_id", job.ID, "character_id", job.CharacterID)
+
+	// Reclaimed after its last allowed attempt died with the worker
+	if job.RetryCount > job.MaxRetries {
+		err := errors.New("worker stopped during every attempt")
+		span.End(err)
+		log.Warn("sprite job out of retries", "attempts", job.RetryCount)
+		if ferr := s.fail(job, err); ferr != nil {
+			log.Error("failed to record sprite job failure", "error", ferr)
+		}
+		return
+	}
+
+	sheets, err := s.render(ctx, job)
+	if err == nil {
+		err = s.complete(job, sheets)
+	}
+	span.End(err)
+
+	if err != nil {
+		log.Warn("sprite job failed", "attempt", job.RetryCount+1, "error", err)
+		if rerr := s.retry(job, err); rerr != nil {
+			log.Error("failed to record sprite job failure", "error", rerr)
+		}
+		return
+	}
+	log.Info("sprite job completed", "provider", s.provider.Name())
+}
+
+// render generates and writes one sheet per animation, reporting progress after each.
+// It returns the public URL per character column (sprite_idle, sprite_walk, ...).
+func (s *SpriteJobService) render(ctx context.Context, job *models.SpriteGenerationJob) (map[string]interface{}, error) {
+	var char models.Character
+	if err := db.DB.First(&char, job.CharacterID).Error; err != nil {
+		return nil, fmt.Errorf("character not found: %w", err)
+	}
+	db.DB.Model(&char).Update("sprite_gen_status", models.SpriteJobProcessing)
+
+	frameSize := s.config.GetInt("sprite_frame_size", 128)
+	dir := filepath.Join(s.outputDir, fmt.Sprint(char.ID))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	specs := models.GetAnimationSpecs()
+	sheets := make(map[string]interface{}, len(specs))
+	for i, spec := range specs {
+		sheet, err := s.provider.GenerateSheet(ctx, &char, spec, frameSize)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", spec.Type, err)
+		}
+
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, sheet); err != nil {
+			return nil, fmt.Errorf("%s: %w", spec.Type, err)
+		}
+		// Write then rename so clients never fetch a half-written sheet
+		name := string(spec.Type) + ".png"
+		tmp := filepath.Join(dir, "."+name+".tmp")
+		if err := os.WriteFile(tmp, buf.Bytes(), 0o644); err != nil {
+			return nil, err
+		}
+		if err := os.Rename(tmp, filepath.Join(dir, name)); err != nil {
+			return nil, err
+		}
+		sheets["sprite_"+string(spec.Type)] = fmt.Sprintf("%s/%d/%s?v=%d", s.baseURL, char.ID, name, job.ID)
+
+		// The last 5% is the publish step in complete()
+		progress := (i + 1) * 95 / len(specs)
+		db.DB.Model(job).Updates(map[string]interface{}{"progress": progress, "updated_at": time.Now()})
+	}
+	return sheets, nil
+}
+
+// complete publishes the sheets on the character and closes the job
+func (s *SpriteJobService) complete(job *models.SpriteGenerationJob, sheets map[string]interface{}) error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		sheets["sprite_gen_status"] = models.SpriteJobCompleted
+		sheets["sprite_gen_job_id"] = job.ID
+		if err := tx.Model(&models.Character{}).Where("id = ?", job.CharacterID).Updates(sheets).Error; err != nil {
+			return err
+		}
+		now := time.Now()
+		return tx.Model(job).Updates(map[string]interface{}{
+			"status":       models.SpriteJobCompleted,
+			"progress":     100,
+			"error_msg":    "",
+			"completed_at": now,
+		}).Error
+	})
+}
+
+// retry re-queues the job with exponential backoff, or fails it once its MaxRetries
+// retries (MaxRetries+1 attempts in all) are used up
+func (s *SpriteJobService) retry(job *models.SpriteGenerationJob, cause error) error {
+	retries := job.RetryCount + 1
+	base := time.Duration(s.config.GetInt("sprite_job_backoff_seconds", 30)) * time.Second
+	next, ok := spriteRetryAt(time.Now(), retries, job.MaxRetries, base)
+	if !ok {
+		job.RetryCount = retries
+		return s.fail(job, cause)
+	}
+	return db.DB.Model(job).Updates(map[string]interface{}{
+		"status":      models.SpriteJobPending,
+		"retry_count": retries,
+		"progress":    0,
+		"error_msg":   cause.Error(),
+		"next_run_at": next,
+	}).Error
+}
+
+// fail closes the job and marks the character's art as failed
+func (s *SpriteJobService) fail(job *models.SpriteGenerationJob, cause error) error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(job).Updates(map[string]interface{}{
+			"status":      models.SpriteJobFailed,
+			"retry_count": job.RetryCount,
+			"error_msg":   cause.Error(),
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Character{}).Where("id = ?", job.CharacterID).
+			Update("sprite_gen_status", models.SpriteJobFailed).Error
+	})
+}
+
+// spriteRetryAt is when a job that failed for the retries-th time runs again, the backoff
+// doubling each time. ok is false once the job has already had maxRetries retries.
+func spriteRetryAt(now time.Time, retries, maxRetries int, base time.Duration) (at time.Time, ok bool) {
+	if retries > maxRetries {
+		return time.Time{}, false
+	}
+	return now.Add(base << (retries - 1)), true
+}
@@ -0,0 +1,27 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSpriteRetryBackoffAndLimit(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		retries, maxRetries int
+		wantOK              bool
+		wantDelay           time.Duration
+	}{
+		{1, 3, true, 30 * time.Second},
+		{2, 3, true, time.Minute},
+		{3, 3, true, 2 * time.Minute}, // The fourth and last attempt
+		{4, 3, false, 0},
+		{1, 0, false, 0}, // No retries: the first failure is final
+	}
+	for _, tc := range cases {
+		at, ok := spriteRetryAt(now, tc.retries, tc.maxRetries, 30*time.Second)
+		if ok != tc.wantOK || (ok && at.Sub(now) != tc.wantDelay) {
+			t.Errorf("retry %d of %d: at +%v ok %v, want +%v ok %v", tc.retries, tc.maxRetries, at.Sub(now), ok, tc.wantDelay, tc.wantOK)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"image"
+	"image/color"
+	"math"
+	"strings"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/internal/sprites"
+)
+
+// SpriteProvider renders one animation of a character as a horizontal sprite sheet
+// (spec.FrameCount frames of frameSize x frameSize, left to right)
+type SpriteProvider interface {
+	Name() string
+	GenerateSheet(ctx context.Context, char *models.Character, spec models.AnimationSpec, frameSize int) (image.Image, error)
+}
+
+// spriteProviders maps SPRITE_PROVIDER values to constructors
+var spriteProviders = map[string]func() SpriteProvider{
+	"layered":    func() SpriteProvider { return NewLayeredSpriteProvider(sprites.New()) },
+	"procedural": func() SpriteProvider { return NewProceduralSpriteProvider() },
+}
+
+// NewSpriteProvider returns the named provider, falling back to the layered compositor
+func NewSpriteProvider(name string) (SpriteProvider, error) {
+	if build, ok := spriteProviders[name]; ok {
+		return build(), nil
+	}
+	return NewLayeredSpriteProvider(sprites.New()), fmt.Errorf("unknown sprite provider %q, using layered", name)
+}
+
+// LayeredSpriteProvider renders characters from layered PNG parts (see internal/sprites)
+type LayeredSpriteProvider struct {
+	compositor *sprites.Compositor
+}
+
+// NewLayeredSpriteProvider wraps a compositor; use sprites.NewFromFS for custom art
+func NewLayeredSpriteProvider(compositor *sprites.Compositor) *LayeredSpriteProvider {
+	return &LayeredSpriteProvider{compositor: compositor}
+}
+
+// Name identifies the provider on the job row
+func (p *LayeredSpriteProvider) Name() string {
+	return "layered"
+}
+
+// GenerateSheet composites the character's parts for every frame of the animation
+func (p *LayeredSpriteProvider) GenerateSheet(ctx context.Context, char *models.Character, spec models.AnimationSpec, frameSize int) (image.Image, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return p.compositor.RenderSheet(sprites.Traits{
+		CharacterType: char.CharacterType,
+		Element:       char.Element,
+		Class:         char.Class,
+		Rarity:        char.Rarity,
+	}, spec, frameSize)
+}
+
+// elementPalettes holds base, shade and accent colors per element
+var elementPalettes = map[string][3]color.RGBA{
+	"FIRE":     {{0xE0, 0x4A, 0x1F, 0xFF}, {0x8C, 0x1E, 0x0B, 0xFF}, {0xFF, 0xB3, 0x2E, 0xFF}},
+	"WATER":    {{0x2A, 0x7F, 0xD4, 0xFF}, {0x12, 0x3F, 0x7A, 0xFF}, {0x6F, 0xE3, 0xE0, 0xFF}},
+	"GRASS":    {{0x4C, 0xA6, 0x3A, 0xFF}, {0x2A, 0x5E, 0x22, 0xFF}, {0x9A, 0x6B, 0x3C, 0xFF}},
+	"ELECTRIC": {{0xF2, 0xCF, 0x2C, 0xFF}, {0x8E, 0x6D, 0x0E, 0xFF}, {0x9B, 0x5C, 0xE6, 0xFF}},
+	"ICE":      {{0x9E, 0xE6, 0xF2, 0xFF}, {0x4E, 0x8F, 0xA8, 0xFF}, {0xFF, 0xFF, 0xFF, 0xFF}},
+	"DRAGON":   {{0x6B, 0x3F, 0xC4, 0xFF}, {0x34, 0x1C, 0x6E, 0xFF}, {0xE8, 0xC1, 0x4A, 0xFF}},
+}
+
+// rarityOutlines colors the silhouette outline so higher ranks read at a glance
+var rarityOutlines = map[string]color.RGBA{
+	"C":   {0x30, 0x30, 0x30, 0xFF},
+	"B":   {0x2E, 0x7D, 0x32, 0xFF},
+	"A":   {0x15, 0x65, 0xC0, 0xFF},
+	"S":   {0x8E, 0x24, 0xAA, 0xFF},
+	"SS":  {0xF9, 0xA8, 0x25, 0xFF},
+	"SSS": {0xD8, 0x1B, 0x60, 0xFF},
+}
+
+// ProceduralSpriteProvider draws a stick-and-capsule figure over the OpenPose keypoints of each frame.
+// Output is fully determined by the character, so re-running a job reproduces the same sheets.
+type ProceduralSpriteProvider struct{}
+
+// NewProceduralSpriteProvider creates the stick-figure provider (useful to preview poses)
+func NewProceduralSpriteProvider() *ProceduralSpriteProvider {
+	return &ProceduralSpriteProvider{}
+}
+
+// Name identifies the provider on the job row
+func (p *ProceduralSpriteProvider) Name() string {
+	return "procedural"
+}
+
+// GenerateSheet renders every pose template of the animation
+func (p *ProceduralSpriteProvider) GenerateSheet(ctx context.Context, char *models.Character, spec models.AnimationSpec, frameSize int) (image.Image, error) {
+	poses := models.GetPoseTemplates()[spec.Type]
+	if len(poses) == 0 {
+		return nil, fmt.Errorf("no pose templates for animation %s", spec.Type)
+	}
+
+	palette, ok := elementPalettes[strings.ToUpper(char.Element)]
+	if !ok {
+		palette = elementPalettes["FIRE"]
+	}
+	outline, ok := rarityOutlines[char.Rarity]
+	if !ok {
+		outline = rarityOutlines["C"]
+	}
+
+	// Per-character build: stockier or leaner limbs, seeded by identity not by randomness
+	h := fnv.New32a()
+	fmt.Fprintf(h, "%d:%s:%s:%s", char.ID, char.CharacterType, char.Class, char.Element)
+	seed := h.Sum32()
+	thickness := 0.028 + float64(seed%8)*0.002
+	headRadius := 0.045 + float64((seed>>3)%5)*0.003
+
+	sheet := image.NewRGBA(image.Rect(0, 0, frameSize*spec.FrameCount, frameSize))
+	for i := 0; i < spec.FrameCount; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		pose := poses[i%len(poses)]
+		drawFigure(sheet, i*frameSize, frameSize, pose.Keypoints(), palette, outline, thickness, headRadius)
+	}
+	return sheet, nil
+}
+
+// figureBones lists keypoint pairs drawn as limbs, back limbs first so front limbs overlap them
+var figureBones = [][2]int{
+	{models.KeypointLShoulder, models.KeypointLElbow}, {models.KeypointLElbow, models.KeypointLWrist},
+	{models.KeypointLHip, models.KeypointLKnee}, {models.KeypointLKnee, models.KeypointLAnkle},
+	{models.KeypointNeck, models.KeypointRHip}, {models.KeypointNeck, models.KeypointLHip},
+	{models.KeypointRShoulder, models.KeypointLShoulder}, {models.KeypointRHip, models.KeypointLHip},
+	{models.KeypointRHip, models.KeypointRKnee}, {models.KeypointRKnee, models.KeypointRAnkle},
+	{models.KeypointRShoulder, models.KeypointRElbow}, {models.KeypointRElbow, models.KeypointRWrist},
+}
+
+func drawFigure(img *image.RGBA, offsetX, size int, kp []models.Keypoint, palette [3]color.RGBA, outline color.RGBA, thickness, headRadius float64) {
+	px := func(k models.Keypoint) (float64, float64) {
+		return float64(offsetX) + k.X*float64(size), k.Y * float64(size)
+	}
+	s := float64(size)
+	clip := image.Rect(offsetX, 0, offsetX+size, size)
+
+	// Two passes: a slightly larger outline silhouette, then the fill
+	for pass, grow := range []float64{1.5, 0} {
+		for i, bone := range figureBones {
+			ax, ay := px(kp[bone[0]])
+			bx, by := px(kp[bone[1]])
+			c := palette[0]
+			if i < 4 {
+				c = palette[1] // Far-side limbs are shaded
+			}
+			if pass == 0 {
+				c = outline
+			}
+			fillCapsule(img, clip, ax, ay, bx, by, thickness*s+grow, c)
+		}
+		hx, hy := px(kp[models.KeypointNose])
+		head := palette[0]
+		if pass == 0 {
+			head = outline
+		}
+		fillCapsule(img, clip, hx-0.015*s, hy, hx-0.015*s, hy, headRadius*s+grow, head)
+	}
+
+	// Accent: eye and hands
+	ex, ey := px(kp[models.KeypointLEye])
+	fillCapsule(img, clip, ex, ey, ex, ey, math.Max(1, 0.008*s), palette[2])
+	for _, hand := range []int{models.KeypointRWrist, models.KeypointLWrist} {
+		wx, wy := px(kp[hand])
+		fillCapsule(img, clip, wx, wy, wx, wy, thickness*s*0.8, palette[2])
+	}
+}
+
+// fillCapsule paints every pixel within radius of segment a-b (a circle when a == b)
+func fillCapsule(img *image.RGBA, clip image.Rectangle, ax, ay, bx, by, radius float64, c color.RGBA) {
+	bounds := image.Rect(
+		int(math.Floor(math.Min(ax, bx)-radius)), int(math.Floor(math.Min(ay, by)-radius)),
+		int(math.Ceil(math.Max(ax, bx)+radius))+1, int(math.Ceil(math.Max(ay, by)+radius))+1,
+	).Intersect(clip)
+
+	dx, dy := bx-ax, by-ay
+	lenSq := dx*dx + dy*dy
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			cx, cy := float64(x)+0.5, float64(y)+0.5
+			t := 0.0
+			if lenSq > 0 {
+				t = math.Max(0, math.Min(1, ((cx-ax)*dx+(cy-ay)*dy)/lenSq))
+			}
+			qx, qy := ax+t*dx-cx, ay+t*dy-cy
+			if qx*qx+qy*qy <= radius*radius {
+				img.SetRGBA(x, y, c)
+			}
+		}
+	}
+}
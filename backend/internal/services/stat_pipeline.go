@@ -0,0 +1,204 @@
+package services
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/pkg/formulas"
+)
+
+// Stat pipeline stages, in the order they apply
+const (
+	StatStageBase      = "base"
+	StatStageLevel     = "level"
+	StatStageGear      = "gear"
+	StatStageSynergy   = "synergy"
+	StatStageCondition = "condition"
+	StatStageBuff      = "buff"
+)
+
+// effectStats maps the status effects that change a stat to the stat they change
+var effectStats = map[string]string{
+	"AMPED":   "ATTACK",
+	"FEEBLE":  "ATTACK",
+	"BULKED":  "DEFENSE",
+	"FRAGILE": "DEFENSE",
+	"HASTE":   "SPEED",
+	"SLOW":    "SPEED",
+}
+
+// Stats is a set of combat stats, or a change to one
+type Stats struct {
+	Attack  int `json:"attack"`
+	Defense int `json:"defense"`
+	HP      int `json:"hp"`
+	Speed   int `json:"speed"`
+}
+
+func (s Stats) add(o Stats) Stats {
+	return Stats{Attack: s.Attack + o.Attack, Defense: s.Defense + o.Defense, HP: s.HP + o.HP, Speed: s.Speed + o.Speed}
+}
+
+func (s Stats) sub(o Stats) Stats {
+	return Stats{Attack: s.Attack - o.Attack, Defense: s.Defense - o.Defense, HP: s.HP - o.HP, Speed: s.Speed - o.Speed}
+}
+
+// StatInput is everything the stat pipeline reads. Only Character is required; a stage
+// whose input is empty is left out of the breakdown.
+type StatInput struct {
+	Character models.Character
+	Gear      []models.Equipment    // Equipped pieces with their item and runes loaded
+	Synergies []models.TeamSynergy  // Active synergies of the character's team
+	Effects   []models.StatusEffect // Active buffs and debuffs
+}
+
+// StatStep is one line of a breakdown: what a stage added and the running total after it
+type StatStep struct {
+	Stage  string `json:"stage"`
+	Reason string `json:"reason"`
+	Change Stats  `json:"change"`
+	Total  Stats  `json:"total"`
+}
+
+// StatBreakdown explains how a character's final stats were reached
+type StatBreakdown struct {
+	Steps []StatStep `json:"steps"`
+	Final Stats      `json:"final"`
+}
+
+func (b *StatBreakdown) step(stage, reason string, change Stats) {
+	b.Final = b.Final.add(change)
+	b.Steps = append(b.Steps, StatStep{Stage: stage, Reason: reason, Change: change, Total: b.Final})
+}
+
+// Sheet is the character's own stats: the total after the base and level stages,
+// before anything equipment, the team or effects add
+func (b StatBreakdown) Sheet() Stats {
+	var sheet Stats
+	for _, s := range b.Steps {
+		if s.Stage == StatStageBase || s.Stage == StatStageLevel {
+			sheet = s.Total
+		}
+	}
+	return sheet
+}
+
+// ComputeStats runs the stat pipeline: base stats, then level, rarity and evolution,
+// then gear, team synergies, the fatigue penalty and finally temporary effects. It reads nothing but its
+// input, so the same character always produces the same breakdown.
+func ComputeStats(in StatInput) StatBreakdown {
+	c := in.Character
+	var b StatBreakdown
+
+	b.step(StatStageBase, "rolled stats", Stats{Attack: c.BaseAttack, Defense: c.BaseDefense, HP: c.BaseHP, Speed: c.BaseSpeed})
+
+	leveled := Stats{
+		Attack:  formulas.CalculateStat(c.BaseAttack, c.Level, c.Rarity, c.EvolutionStage),
+		Defense: formulas.CalculateStat(c.BaseDefense, c.Level, c.Rarity, c.EvolutionStage),
+		HP:      formulas.CalculateStat(c.BaseHP, c.Level, c.Rarity, c.EvolutionStage),
+		Speed:   formulas.CalculateStat(c.BaseSpeed, c.Level, c.Rarity, c.EvolutionStage),
+	}
+	b.step(StatStageLevel, fmt.Sprintf("level %d (+%d%%), rarity %s (x%.1f), evolution %d (x%.1f)",
+		c.Level, c.Level*5, rarityLabel(c.Rarity), formulas.GetRarityMultiplier(c.Rarity),
+		c.EvolutionStage, formulas.GetEvolutionBonus(c.EvolutionStage)), leveled.sub(b.Final))
+
+	for _, e := range in.Gear {
+		if bonus := PieceBonus(e); bonus != (Stats{}) {
+			b.step(StatStageGear, fmt.Sprintf("%s +%d", e.Item.Name, e.UpgradeLevel), bonus)
+		}
+	}
+
+	// Synergies and effects are percentages of what the stage started from, so their
+	// order within a stage doesn't matter
+	before := b.Final
+	for _, syn := range in.Synergies {
+		if syn.BonusStat != "ALL_STATS" {
+			continue // Resistance and damage synergies apply in combat, not to stats
+		}
+		b.step(StatStageSynergy, syn.Name, percentOf(before, syn.BonusValue))
+	}
+
+	if pct := fatiguePenalty(c.Fatigue); pct > 0 {
+		loss := percentOf(b.Final, -float64(pct)/100)
+		loss.HP = 0 // Tired characters hit softer and move slower, but don't lose health
+		b.step(StatStageCondition, fmt.Sprintf("fatigue %d (-%d%%)", c.Fatigue, pct), loss)
+	}
+
+	before = b.Final
+	for _, eff := range in.Effects {
+		stat, ok := effectStats[eff.EffectName]
+		if !ok || eff.StatModifier == 0 {
+			continue
+		}
+		mod := eff.StatModifier * float64(max(eff.Stacks, 1))
+		var change Stats
+		switch stat {
+		case "ATTACK":
+			change.Attack = int(math.Floor(float64(before.Attack) * mod))
+		case "DEFENSE":
+			change.Defense = int(math.Floor(float64(before.Defense) * mod))
+		case "SPEED":
+			change.Speed = int(math.Floor(float64(before.Speed) * mod))
+		}
+		b.step(StatStageBuff, fmt.Sprintf("%s x%d", eff.EffectName, max(eff.Stacks, 1)), change)
+	}
+
+	b.Final = Stats{Attack: max(b.Final.Attack, 0), Defense: max(b.Final.Defense, 0), HP: max(b.Final.HP, 1), Speed: max(b.Final.Speed, 0)}
+	return b
+}
+
+// fatiguePenalty is the percentage a character at this fatigue loses off attack,
+// defense and speed
+func fatiguePenalty(fatigue int) int {
+	switch {
+	case fatigue >= 80:
+		return 20
+	case fatigue >= 60:
+		return 10
+	case fatigue >= 40:
+		return 5
+	}
+	return 0
+}
+
+func percentOf(s Stats, pct float64) Stats {
+	return Stats{
+		Attack:  int(math.Floor(float64(s.Attack) * pct)),
+		Defense: int(math.Floor(float64(s.Defense) * pct)),
+		HP:      int(math.Floor(float64(s.HP) * pct)),
+		Speed:   int(math.Floor(float64(s.Speed) * pct)),
+	}
+}
+
+func rarityLabel(rarity string) string {
+	if rarity == "" {
+		return "C"
+	}
+	return rarity
+}
+
+// applySheetStats writes the character's own stats (see StatBreakdown.Sheet) into its
+// Current* columns, the only place they are written. CurrentHP doubles as health, so it
+// is only capped at the new maximum unless heal tops it up.
+func applySheetStats(c *models.Character, heal bool) StatBreakdown {
+	b := ComputeStats(StatInput{Character: *c})
+	sheet := b.Sheet()
+	c.CurrentAttack = sheet.Attack
+	c.CurrentDefense = sheet.Defense
+	c.CurrentSpeed = sheet.Speed
+	if heal || c.CurrentHP > sheet.HP {
+		c.CurrentHP = sheet.HP
+	}
+	return b
+}
+
+// setLevel moves a character to a level, along with the rarity and evolution stage that
+// level brings, and refreshes its stats. Reaching a new level restores full health.
+func setLevel(c *models.Character, level int) {
+	heal := level > c.Level
+	c.Level = level
+	c.Rarity = formulas.GetRarityForLevel(level)
+	c.EvolutionStage = formulas.GetEvolutionStage(level)
+	applySheetStats(c, heal)
+}
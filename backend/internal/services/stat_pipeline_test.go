@@ -0,0 +1,72 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/pkg/formulas"
+)
+
+func TestComputeStatsAppliesStagesInOrder(t *testing.T) {
+	char := models.Character{BaseAttack: 100, BaseDefense: 50, BaseHP: 200, BaseSpeed: 20, Level: 10, Rarity: "B"}
+	sword := models.Equipment{Item: models.Item{Name: "Sword", AttackBonus: 20}}
+
+	b := ComputeStats(StatInput{
+		Character: char,
+		Gear:      []models.Equipment{sword},
+		Synergies: []models.TeamSynergy{
+			{Name: "Tribal Unity", BonusStat: "ALL_STATS", BonusValue: 0.15},
+			{Name: "Elemental Harmony", BonusStat: "RESISTANCE", BonusValue: 0.2},
+		},
+		Effects: []models.StatusEffect{{EffectName: "AMPED", StatModifier: 0.3, Stacks: 1}},
+	})
+
+	stages := []string{StatStageBase, StatStageLevel, StatStageGear, StatStageSynergy, StatStageBuff}
+	if len(b.Steps) != len(stages) {
+		t.Fatalf("breakdown has %d steps, want %d: %+v", len(b.Steps), len(stages), b.Steps)
+	}
+	for i, stage := range stages {
+		if b.Steps[i].Stage != stage {
+			t.Fatalf("step %d is %q, want %q", i, b.Steps[i].Stage, stage)
+		}
+	}
+
+	// 100 x1.6 (B) x1.5 (level 10) = 240, +20 gear, +15% synergy, +30% Amped
+	if sheet := b.Sheet(); sheet.Attack != 240 {
+		t.Fatalf("sheet attack = %d, want 240", sheet.Attack)
+	}
+	if b.Final.Attack != 260+39+89 {
+		t.Fatalf("final attack = %d, want %d", b.Final.Attack, 260+39+89)
+	}
+	if b.Final.Defense != 120+18 {
+		t.Fatalf("final defense = %d, want %d (Amped must not touch it)", b.Final.Defense, 120+18)
+	}
+	if again := ComputeStats(StatInput{Character: char, Gear: []models.Equipment{sword}}); again.Final.Attack != 260 {
+		t.Fatalf("attack without synergies and effects = %d, want 260", again.Final.Attack)
+	}
+}
+
+func TestCheckIntegrityRepairsTamperedCharacter(t *testing.T) {
+	char := models.Character{BaseAttack: 30, BaseDefense: 20, BaseHP: 100, BaseSpeed: 10, TotalXP: formulas.GetXPForLevel(21)}
+	setLevel(&char, formulas.GetLevelFromXP(char.TotalXP))
+	if report := checkIntegrity(&char); !report.Valid() {
+		t.Fatalf("clean character reported %v", report.Problems)
+	}
+
+	want := char
+	char.Level = 40
+	char.Rarity = "A"
+	char.CurrentAttack = 9999
+	char.CurrentHP = 5000
+	report := checkIntegrity(&char)
+	if len(report.Problems) != 4 {
+		t.Fatalf("problems = %v, want level, rarity, attack and hp", report.Problems)
+	}
+	if char.Level != 21 || char.Rarity != "B" || char.CurrentAttack != want.CurrentAttack || char.CurrentHP != want.CurrentHP {
+		t.Fatalf("repaired = level %d %s, attack %d, hp %d; want level 21 B, attack %d, hp %d",
+			char.Level, char.Rarity, char.CurrentAttack, char.CurrentHP, want.CurrentAttack, want.CurrentHP)
+	}
+	if report.Stats.Final.Attack != want.CurrentAttack {
+		t.Fatalf("report stats attack = %d, want %d", report.Stats.Final.Attack, want.CurrentAttack)
+	}
+}
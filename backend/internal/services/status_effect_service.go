@@ -139,19 +139,8 @@ func (s *StatusEffectService) GetStatModifier(characterID uint, statType string)
 
 	totalModifier := 0.0
 	for _, effect := range effects {
-		switch statType {
-		case "ATTACK":
-			if effect.EffectName == "AMPED" || effect.EffectName == "FEEBLE" {
-				totalModifier += effect.StatModifier * float64(effect.Stacks)
-			}
-		case "DEFENSE":
-			if effect.EffectName == "BULKED" || effect.EffectName == "FRAGILE" {
-				totalModifier += effect.StatModifier * float64(effect.Stacks)
-			}
-		case "SPEED":
-			if effect.EffectName == "HASTE" || effect.EffectName == "SLOW" {
-				totalModifier += effect.StatModifier * float64(effect.Stacks)
-			}
+		if effectStats[effect.EffectName] == statType {
+			totalModifier += effect.StatModifier * float64(effect.Stacks)
 		}
 	}
 
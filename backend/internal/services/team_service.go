@@ -49,7 +49,8 @@ func (s *TeamService) AddMember(teamID, characterID uint, slot int, isBackup boo
 	if err := db.DB.First(&character, characterID).Error; err != nil {
 		return errors.New("character not found")
 	}
-	if character.OwnerID != team.UserID {
+	// Borrowed characters can join the borrower's teams; lent out ones cannot join the owner's
+	if character.Controller() != team.UserID {
 		return errors.New("character does not belong to user")
 	}
 
@@ -137,10 +138,18 @@ func (s *TeamService) calculateTeamStats(team *models.Team) {
 	}
 
 	// Calculate Synergies
-	team.Synergies = s.calculateSynergies(team)
+	var active []models.Character
+	for _, member := range team.Members {
+		if !member.IsBackup {
+			active = append(active, member.Character)
+		}
+	}
+	team.Synergies = TeamSynergies(active)
 }
 
-func (s *TeamService) calculateSynergies(team *models.Team) []models.TeamSynergy {
+// TeamSynergies returns the synergies a set of active team members unlocks. The stat
+// pipeline applies the ones that raise stats.
+func TeamSynergies(members []models.Character) []models.TeamSynergy {
 	synergies := []models.TeamSynergy{}
 
 	// Count types and elements
@@ -148,18 +157,15 @@ func (s *TeamService) calculateSynergies(team *models.Team) []models.TeamSynergy
 	elementCounts := make(map[string]int)
 	classCounts := make(map[string]int)
 
-	for _, member := range team.Members {
-		if !member.IsBackup {
-			char := member.Character
-			typeCounts[char.CharacterType]++
-			elementCounts[char.Element]++
-			classCounts[char.Class]++
-		}
+	for _, char := range members {
+		typeCounts[char.CharacterType]++
+		elementCounts[char.Element]++
+		classCounts[char.Class]++
 	}
 
 	// Mono-Type Synergy (3 same type)
 	for t, count := range typeCounts {
-		if count >= 3 {
+		if t != "" && count >= 3 {
 			synergies = append(synergies, models.TeamSynergy{
 				ID:          "MONO_TYPE_" + t,
 				Name:        "Tribal Unity (" + t + ")",
@@ -175,7 +181,7 @@ func (s *TeamService) calculateSynergies(team *models.Team) []models.TeamSynergy
 
 	// Elemental Harmony (3 same element)
 	for e, count := range elementCounts {
-		if count >= 3 {
+		if e != "" && count >= 3 {
 			synergies = append(synergies, models.TeamSynergy{
 				ID:          "ELEMENTAL_HARMONY_" + e,
 				Name:        "Elemental Harmony (" + e + ")",
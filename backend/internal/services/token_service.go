@@ -248,8 +248,8 @@ func (s *TokenService) WithdrawTower(userID uint, amount int64, walletAddress st
 
 	// SECURITY CHECK 4: Check for pending withdrawals
 	var pendingCount int64
-	db.DB.Model(&models.Transaction{}).
-		Where("user_id = ? AND transaction_type = 'WITHDRAWAL' AND blockchain_tx_hash IS NULL", userID).
+	db.DB.Model(&models.Withdrawal{}).
+		Where("user_id = ? AND status IN ?", userID, []string{models.WithdrawalPendingApproval, models.WithdrawalApproved, models.WithdrawalSending, models.WithdrawalBroadcast}).
 		Count(&pendingCount)
 
 	if pendingCount > 0 {
@@ -303,6 +303,24 @@ func (s *TokenService) WithdrawTower(userID uint, amount int64, walletAddress st
 		return errors.New("failed to create withdrawal request")
 	}
 
+	// Queue for the withdrawal worker; large amounts wait for two admin approvals
+	withdrawal := models.Withdrawal{
+		UserID:        userID,
+		TransactionID: transaction.ID,
+		ToAddress:     walletAddress,
+		Amount:        amount,
+		Status:        models.WithdrawalApproved,
+		TxHashes:      "[]",
+	}
+	if amount > int64(GetConfigService().GetInt("withdrawal_approval_threshold", 5000)) {
+		withdrawal.Status = models.WithdrawalPendingApproval
+		withdrawal.RequiredApprovals = 2
+	}
+	if err := tx.Create(&withdrawal).Error; err != nil {
+		tx.Rollback()
+		return errors.New("failed to queue withdrawal")
+	}
+
 	// Audit log
 	auditLog := models.AuditLog{
 		UserID:     &userID,
@@ -317,9 +335,6 @@ func (s *TokenService) WithdrawTower(userID uint, amount int64, walletAddress st
 		return errors.New("transaction failed")
 	}
 
-	// TODO: Queue blockchain transaction for processing
-	// This would be handled by a separate worker service
-
 	return nil
 }
 
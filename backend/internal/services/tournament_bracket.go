@@ -0,0 +1,302 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+)
+
+// Bracket labels stored on TournamentMatch.Bracket
+const (
+	bracketMain       = "MAIN"
+	bracketWinners    = "WINNERS"
+	bracketLosers     = "LOSERS"
+	bracketGrandFinal = "GRAND_FINAL"
+	bracketSwiss      = "SWISS"
+)
+
+// tournamentPairing is one match of a round before it is persisted.
+// Player2 == 0 means Player1 receives a bye.
+type tournamentPairing struct {
+	Player1 uint
+	Player2 uint
+	Bracket string
+}
+
+// seedParticipants orders players by rating (highest first) and assigns Seed 1..N.
+// Ties keep registration order so seeding is stable.
+func seedParticipants(ps []models.TournamentParticipant) {
+	sort.SliceStable(ps, func(i, j int) bool {
+		if ps[i].Rating != ps[j].Rating {
+			return ps[i].Rating > ps[j].Rating
+		}
+		return ps[i].CreatedAt.Before(ps[j].CreatedAt)
+	})
+	for i := range ps {
+		ps[i].Seed = i + 1
+	}
+}
+
+// bracketOrder returns the standard seed order for a power-of-two bracket,
+// e.g. size 8 -> [1 8 4 5 2 7 3 6], so top seeds only meet late.
+func bracketOrder(size int) []int {
+	order := []int{1}
+	for len(order) < size {
+		n := len(order) * 2
+		next := make([]int, 0, n)
+		for _, s := range order {
+			next = append(next, s, n+1-s)
+		}
+		order = next
+	}
+	return order
+}
+
+// nextPowerOfTwo returns the smallest power of two >= n
+func nextPowerOfTwo(n int) int {
+	size := 1
+	for size < n {
+		size *= 2
+	}
+	return size
+}
+
+// pairFirstEliminationRound seeds a fresh bracket. Missing slots become byes for the top seeds.
+// ps must be sorted by Seed.
+func pairFirstEliminationRound(ps []models.TournamentParticipant, bracket string) []tournamentPairing {
+	size := nextPowerOfTwo(len(ps))
+	order := bracketOrder(size)
+
+	var pairings []tournamentPairing
+	for i := 0; i < len(order); i += 2 {
+		a, b := order[i], order[i+1]
+		var p1, p2 uint
+		if a <= len(ps) {
+			p1 = ps[a-1].UserID
+		}
+		if b <= len(ps) {
+			p2 = ps[b-1].UserID
+		}
+		if p1 == 0 {
+			p1, p2 = p2, 0
+		}
+		if p1 == 0 {
+			continue
+		}
+		pairings = append(pairings, tournamentPairing{Player1: p1, Player2: p2, Bracket: bracket})
+	}
+	return pairings
+}
+
+// pairAdjacentWinners pairs the winners of the previous round in match order (1v2, 3v4, ...).
+func pairAdjacentWinners(winners []uint, bracket string) []tournamentPairing {
+	var pairings []tournamentPairing
+	for i := 0; i < len(winners); i += 2 {
+		p := tournamentPairing{Player1: winners[i], Bracket: bracket}
+		if i+1 < len(winners) {
+			p.Player2 = winners[i+1]
+		}
+		pairings = append(pairings, p)
+	}
+	return pairings
+}
+
+// pairReseeded pairs a pool top-vs-bottom (1 v N, 2 v N-1). An odd pool gives the top seed a bye.
+// pool must be sorted by Seed.
+func pairReseeded(pool []models.TournamentParticipant, bracket string) []tournamentPairing {
+	var pairings []tournamentPairing
+	lo, hi := 0, len(pool)-1
+	if len(pool)%2 == 1 {
+		pairings = append(pairings, tournamentPairing{Player1: pool[0].UserID, Bracket: bracket})
+		lo = 1
+	}
+	for lo < hi {
+		pairings = append(pairings, tournamentPairing{Player1: pool[lo].UserID, Player2: pool[hi].UserID, Bracket: bracket})
+		lo++
+		hi--
+	}
+	return pairings
+}
+
+// pairDoubleEliminationRound builds a round-based double elimination round.
+// Unbeaten players meet in the winners bracket, one-loss players in the losers bracket.
+// When exactly one player is left on each side they meet in the grand final; if the
+// unbeaten player loses it, both have one loss and the rule naturally plays a reset match.
+func pairDoubleEliminationRound(active []models.TournamentParticipant) []tournamentPairing {
+	var winners, losers []models.TournamentParticipant
+	for _, p := range active {
+		if p.Losses == 0 {
+			winners = append(winners, p)
+		} else {
+			losers = append(losers, p)
+		}
+	}
+
+	if len(active) == 2 {
+		return []tournamentPairing{{Player1: active[0].UserID, Player2: active[1].UserID, Bracket: bracketGrandFinal}}
+	}
+
+	var pairings []tournamentPairing
+	// A lone side waits for the other side to catch up instead of taking a bye every round
+	if len(winners) > 1 {
+		pairings = append(pairings, pairReseeded(winners, bracketWinners)...)
+	}
+	if len(losers) > 1 {
+		pairings = append(pairings, pairReseeded(losers, bracketLosers)...)
+	}
+	return pairings
+}
+
+// pairSwissRound pairs players with equal scores while avoiding rematches.
+// The lowest ranked player without a previous bye sits out when the field is odd.
+func pairSwissRound(ps []models.TournamentParticipant, played map[uint]map[uint]bool) []tournamentPairing {
+	standings := make([]models.TournamentParticipant, len(ps))
+	copy(standings, ps)
+	sort.SliceStable(standings, func(i, j int) bool {
+		if standings[i].Points != standings[j].Points {
+			return standings[i].Points > standings[j].Points
+		}
+		return standings[i].Seed < standings[j].Seed
+	})
+
+	var pairings []tournamentPairing
+	if len(standings)%2 == 1 {
+		byeIdx := len(standings) - 1
+		for i := len(standings) - 1; i >= 0; i-- {
+			if !standings[i].HadBye {
+				byeIdx = i
+				break
+			}
+		}
+		pairings = append(pairings, tournamentPairing{Player1: standings[byeIdx].UserID, Bracket: bracketSwiss})
+		standings = append(standings[:byeIdx], standings[byeIdx+1:]...)
+	}
+
+	paired := make(map[uint]bool, len(standings))
+	for i, a := range standings {
+		if paired[a.UserID] {
+			continue
+		}
+		opponent := -1
+		for j := i + 1; j < len(standings); j++ {
+			b := standings[j]
+			if paired[b.UserID] {
+				continue
+			}
+			if opponent == -1 {
+				opponent = j // Fallback: accept a rematch if nothing else is left
+			}
+			if !played[a.UserID][b.UserID] {
+				opponent = j
+				break
+			}
+		}
+		if opponent == -1 {
+			continue
+		}
+		b := standings[opponent]
+		paired[a.UserID] = true
+		paired[b.UserID] = true
+		pairings = append(pairings, tournamentPairing{Player1: a.UserID, Player2: b.UserID, Bracket: bracketSwiss})
+	}
+	return pairings
+}
+
+// swissRoundCount returns the configured number of Swiss rounds, or ceil(log2(players))
+func swissRoundCount(configured, players int) int {
+	if configured > 0 {
+		return configured
+	}
+	if players < 2 {
+		return 1
+	}
+	return int(math.Ceil(math.Log2(float64(players))))
+}
+
+// swissStandings orders players by points, then Buchholz (sum of opponents' points), then seed
+func swissStandings(ps []models.TournamentParticipant, matches []models.TournamentMatch) []models.TournamentParticipant {
+	points := make(map[uint]int, len(ps))
+	for _, p := range ps {
+		points[p.UserID] = p.Points
+	}
+	buchholz := make(map[uint]int, len(ps))
+	for _, m := range matches {
+		if m.Player1ID == nil || m.Player2ID == nil {
+			continue
+		}
+		buchholz[*m.Player1ID] += points[*m.Player2ID]
+		buchholz[*m.Player2ID] += points[*m.Player1ID]
+	}
+
+	ranked := make([]models.TournamentParticipant, len(ps))
+	copy(ranked, ps)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		if buchholz[a.UserID] != buchholz[b.UserID] {
+			return buchholz[a.UserID] > buchholz[b.UserID]
+		}
+		return a.Seed < b.Seed
+	})
+	return ranked
+}
+
+// eliminationStandings puts the champion first, then everyone else by how late they were knocked out
+func eliminationStandings(ps []models.TournamentParticipant, championID uint) []models.TournamentParticipant {
+	ranked := make([]models.TournamentParticipant, len(ps))
+	copy(ranked, ps)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if a.UserID == championID || b.UserID == championID {
+			return a.UserID == championID
+		}
+		if a.EliminatedRound != b.EliminatedRound {
+			return a.EliminatedRound > b.EliminatedRound
+		}
+		return a.Seed < b.Seed
+	})
+	return ranked
+}
+
+// parsePayoutTable decodes a JSON percentage table like [50,30,20]
+func parsePayoutTable(raw string) ([]int, error) {
+	var table []int
+	if err := json.Unmarshal([]byte(raw), &table); err != nil {
+		return nil, errors.New("payout table must be a JSON array of percentages")
+	}
+	if len(table) == 0 {
+		return nil, errors.New("payout table is empty")
+	}
+	total := 0
+	for _, pct := range table {
+		if pct < 0 {
+			return nil, errors.New("payout percentages cannot be negative")
+		}
+		total += pct
+	}
+	if total > 100 {
+		return nil, errors.New("payout table exceeds 100%")
+	}
+	return table, nil
+}
+
+// splitPrizePool returns the amount for each paid placement and the remainder
+// (rounding dust plus any unallocated percentage) that goes to the treasury.
+func splitPrizePool(pool int64, table []int, placements int) ([]int64, int64) {
+	paid := len(table)
+	if placements < paid {
+		paid = placements
+	}
+	amounts := make([]int64, paid)
+	var distributed int64
+	for i := 0; i < paid; i++ {
+		amounts[i] = pool * int64(table[i]) / 100
+		distributed += amounts[i]
+	}
+	return amounts, pool - distributed
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+)
+
+// entrant is a double elimination player whose user ID matches their seed
+func entrant(seed, losses int) models.TournamentParticipant {
+	return models.TournamentParticipant{UserID: uint(seed), Seed: seed, Losses: losses}
+}
+
+func TestPairAdjacentWinners(t *testing.T) {
+	cases := []struct {
+		name    string
+		winners []uint
+		want    []tournamentPairing
+	}{
+		{"no winners", nil, nil},
+		{"final", []uint{4, 9}, []tournamentPairing{{4, 9, bracketMain}}},
+		{"in match order", []uint{1, 8, 4, 5}, []tournamentPairing{{1, 8, bracketMain}, {4, 5, bracketMain}}},
+		{"odd winner out gets a bye", []uint{3, 6, 2}, []tournamentPairing{{3, 6, bracketMain}, {2, 0, bracketMain}}},
+	}
+	for _, tc := range cases {
+		if got := pairAdjacentWinners(tc.winners, bracketMain); !slices.Equal(got, tc.want) {
+			t.Errorf("%s: pairings = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestPairDoubleEliminationRound(t *testing.T) {
+	cases := []struct {
+		name   string
+		active []models.TournamentParticipant
+		want   []tournamentPairing
+	}{
+		{
+			"unbeaten field reseeds top against bottom",
+			[]models.TournamentParticipant{entrant(1, 0), entrant(2, 0), entrant(3, 0), entrant(4, 0)},
+			[]tournamentPairing{{1, 4, bracketWinners}, {2, 3, bracketWinners}},
+		},
+		{
+			"both brackets play",
+			[]models.TournamentParticipant{entrant(1, 0), entrant(2, 0), entrant(3, 1), entrant(4, 1)},
+			[]tournamentPairing{{1, 2, bracketWinners}, {3, 4, bracketLosers}},
+		},
+		{
+			"odd bracket gives the top seed a bye",
+			[]models.TournamentParticipant{entrant(1, 0), entrant(2, 0), entrant(3, 0), entrant(4, 1), entrant(5, 1)},
+			[]tournamentPairing{{1, 0, bracketWinners}, {2, 3, bracketWinners}, {4, 5, bracketLosers}},
+		},
+		{
+			"lone unbeaten player waits for the losers bracket",
+			[]models.TournamentParticipant{entrant(1, 0), entrant(2, 1), entrant(3, 1)},
+			[]tournamentPairing{{2, 3, bracketLosers}},
+		},
+		{
+			"last two meet in the grand final",
+			[]models.TournamentParticipant{entrant(1, 0), entrant(2, 1)},
+			[]tournamentPairing{{1, 2, bracketGrandFinal}},
+		},
+		{
+			"grand final reset after the unbeaten player loses",
+			[]models.TournamentParticipant{entrant(1, 1), entrant(2, 1)},
+			[]tournamentPairing{{1, 2, bracketGrandFinal}},
+		},
+	}
+	for _, tc := range cases {
+		if got := pairDoubleEliminationRound(tc.active); !slices.Equal(got, tc.want) {
+			t.Errorf("%s: pairings = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestSplitPrizePool(t *testing.T) {
+	cases := []struct {
+		name          string
+		pool          int64
+		table         []int
+		placements    int
+		want          []int64
+		wantRemainder int64
+	}{
+		{"full table", 1000, []int{50, 30, 20}, 8, []int64{500, 300, 200}, 0},
+		{"unallocated percentage goes to the treasury", 1000, []int{60, 30}, 8, []int64{600, 300}, 100},
+		{"fewer players than paid places", 1000, []int{50, 30, 20}, 2, []int64{500, 300}, 200},
+		{"rounding dust", 101, []int{50, 30, 20}, 3, []int64{50, 30, 20}, 1},
+		{"empty pool", 0, []int{100}, 4, []int64{0}, 0},
+	}
+	for _, tc := range cases {
+		got, remainder := splitPrizePool(tc.pool, tc.table, tc.placements)
+		if !slices.Equal(got, tc.want) || remainder != tc.wantRemainder {
+			t.Errorf("%s: split = %v + %d, want %v + %d", tc.name, got, remainder, tc.want, tc.wantRemainder)
+		}
+		var total int64
+		for _, a := range got {
+			total += a
+		}
+		if total+remainder != tc.pool {
+			t.Errorf("%s: paid %d + %d of a %d pool", tc.name, total, remainder, tc.pool)
+		}
+	}
+}
@@ -0,0 +1,762 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/db"
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/internal/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TournamentService runs registration, brackets, match scheduling and prize payouts
+type TournamentService struct {
+	ledger        *LedgerService
+	battleService *BattleService
+	admin         *AdminService
+}
+
+func NewTournamentService(ledger *LedgerService, battles *BattleService, admin *AdminService) *TournamentService {
+	return &TournamentService{
+		ledger:        ledger,
+		battleService: battles,
+		admin:         admin,
+	}
+}
+
+// CreateTournamentRequest holds the admin-provided tournament settings
+type CreateTournamentRequest struct {
+	Name                 string    `json:"name" binding:"required"`
+	Description          string    `json:"description"`
+	Format               string    `json:"format" binding:"required"`
+	EntryFee             int64     `json:"entry_fee"`
+	MinPlayers           int       `json:"min_players"`
+	MaxPlayers           int       `json:"max_players"`
+	RegistrationOpensAt  time.Time `json:"registration_opens_at"`
+	RegistrationClosesAt time.Time `json:"registration_closes_at" binding:"required"`
+	MatchTimeoutMinutes  int       `json:"match_timeout_minutes"`
+	SwissRounds          int       `json:"swiss_rounds"`
+	PayoutTable          string    `json:"payout_table"` // JSON array, defaults to [50,30,20]
+}
+
+// CreateTournament validates settings and opens registration
+func (s *TournamentService) CreateTournament(req CreateTournamentRequest, adminID uint) (*models.Tournament, error) {
+	switch req.Format {
+	case models.TournamentFormatSingleElim, models.TournamentFormatDoubleElim, models.TournamentFormatSwiss:
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", req.Format)
+	}
+
+	if req.EntryFee < 0 {
+		return nil, errors.New("entry fee cannot be negative")
+	}
+	if req.MinPlayers == 0 {
+		req.MinPlayers = 4
+	}
+	if req.MaxPlayers == 0 {
+		req.MaxPlayers = 16
+	}
+	if req.MinPlayers < 2 || req.MaxPlayers < req.MinPlayers {
+		return nil, errors.New("invalid player limits")
+	}
+	if req.MatchTimeoutMinutes == 0 {
+		req.MatchTimeoutMinutes = 30
+	}
+	if req.RegistrationOpensAt.IsZero() {
+		req.RegistrationOpensAt = time.Now()
+	}
+	if !req.RegistrationClosesAt.After(req.RegistrationOpensAt) {
+		return nil, errors.New("registration must close after it opens")
+	}
+	if req.PayoutTable == "" {
+		req.PayoutTable = "[50,30,20]"
+	}
+	if _, err := parsePayoutTable(req.PayoutTable); err != nil {
+		return nil, err
+	}
+
+	tournament := models.Tournament{
+		Name:                 req.Name,
+		Description:          req.Description,
+		Format:               req.Format,
+		Status:               models.TournamentStatusRegistration,
+		EntryFee:             req.EntryFee,
+		MinPlayers:           req.MinPlayers,
+		MaxPlayers:           req.MaxPlayers,
+		RegistrationOpensAt:  req.RegistrationOpensAt,
+		RegistrationClosesAt: req.RegistrationClosesAt,
+		MatchTimeoutMinutes:  req.MatchTimeoutMinutes,
+		SwissRounds:          req.SwissRounds,
+		PayoutTable:          req.PayoutTable,
+		CreatedBy:            adminID,
+	}
+	if err := db.DB.Create(&tournament).Error; err != nil {
+		return nil, err
+	}
+
+	s.admin.CreateAuditLog(adminID, "CREATE_TOURNAMENT", strconv.Itoa(int(tournament.ID)), "", tournament.Name)
+	return &tournament, nil
+}
+
+// ListTournaments returns tournaments, optionally filtered by status
+func (s *TournamentService) ListTournaments(status string) ([]models.Tournament, error) {
+	var tournaments []models.Tournament
+	query := db.DB.Order("registration_closes_at DESC")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Find(&tournaments).Error; err != nil {
+		return nil, err
+	}
+	return tournaments, nil
+}
+
+// GetTournament returns a tournament with its participants and bracket
+func (s *TournamentService) GetTournament(tournamentID uint) (*models.Tournament, error) {
+	var tournament models.Tournament
+	if err := db.DB.
+		Preload("Participants", func(tx *gorm.DB) *gorm.DB { return tx.Order("seed ASC, created_at ASC") }).
+		Preload("Matches", func(tx *gorm.DB) *gorm.DB { return tx.Order("round ASC, match_number ASC") }).
+		First(&tournament, tournamentID).Error; err != nil {
+		return nil, errors.New("tournament not found")
+	}
+	return &tournament, nil
+}
+
+// Register enters a player and moves the entry fee into escrow
+func (s *TournamentService) Register(tournamentID, userID uint) (*models.TournamentParticipant, error) {
+	// Teams are required to be scheduled into battles later
+	if _, err := s.battleService.GetTeamSnapshot(userID); err != nil {
+		return nil, errors.New("an active team is required to register")
+	}
+
+	var participant models.TournamentParticipant
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		var tournament models.Tournament
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&tournament, tournamentID).Error; err != nil {
+			return errors.New("tournament not found")
+		}
+
+		now := time.Now()
+		if tournament.Status != models.TournamentStatusRegistration {
+			return errors.New("registration is closed")
+		}
+		if now.Before(tournament.RegistrationOpensAt) || now.After(tournament.RegistrationClosesAt) {
+			return errors.New("registration window is not open")
+		}
+
+		var count int64
+		tx.Model(&models.TournamentParticipant{}).Where("tournament_id = ?", tournamentID).Count(&count)
+		if int(count) >= tournament.MaxPlayers {
+			return errors.New("tournament is full")
+		}
+
+		var existing models.TournamentParticipant
+		if err := tx.Where("tournament_id = ? AND user_id = ?", tournamentID, userID).First(&existing).Error; err == nil {
+			return errors.New("already registered")
+		}
+
+		var user models.User
+		if err := tx.First(&user, userID).Error; err != nil {
+			return errors.New("user not found")
+		}
+
+		if tournament.EntryFee > 0 {
+			if err := s.moveEscrow(tx, userID, tournament.EntryFee, true, models.TxTypeTournamentEntry,
+				fmt.Sprintf("tournament_%d_entry_%d", tournamentID, userID), "Tournament Entry Fee"); err != nil {
+				return err
+			}
+			tournament.PrizePool += tournament.EntryFee
+			if err := tx.Save(&tournament).Error; err != nil {
+				return err
+			}
+		}
+
+		participant = models.TournamentParticipant{
+			TournamentID: tournamentID,
+			UserID:       userID,
+			Rating:       user.ELO,
+			EntryPaid:    tournament.EntryFee,
+		}
+		return tx.Create(&participant).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &participant, nil
+}
+
+// Unregister withdraws a player during registration and refunds the entry fee
+func (s *TournamentService) Unregister(tournamentID, userID uint) error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		var tournament models.Tournament
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&tournament, tournamentID).Error; err != nil {
+			return errors.New("tournament not found")
+		}
+		if tournament.Status != models.TournamentStatusRegistration {
+			return errors.New("cannot leave a tournament after it started")
+		}
+
+		var participant models.TournamentParticipant
+		if err := tx.Where("tournament_id = ? AND user_id = ?", tournamentID, userID).First(&participant).Error; err != nil {
+			return errors.New("not registered")
+		}
+
+		if participant.EntryPaid > 0 {
+			if err := s.moveEscrow(tx, userID, participant.EntryPaid, false, models.TxTypeTournamentRefund,
+				fmt.Sprintf("tournament_%d_refund_%d", tournamentID, userID), "Tournament Withdrawal Refund"); err != nil {
+				return err
+			}
+			tournament.PrizePool -= participant.EntryPaid
+			if err := tx.Save(&tournament).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Delete(&participant).Error
+	})
+}
+
+// StartTournament closes registration, seeds the field from rating and schedules round 1.
+// Tournaments below MinPlayers are cancelled and refunded instead.
+func (s *TournamentService) StartTournament(tournamentID uint) error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		var tournament models.Tournament
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&tournament, tournamentID).Error; err != nil {
+			return errors.New("tournament not found")
+		}
+		if tournament.Status != models.TournamentStatusRegistration {
+			return errors.New("tournament already started")
+		}
+
+		var participants []models.TournamentParticipant
+		if err := tx.Preload("User").Where("tournament_id = ?", tournamentID).Find(&participants).Error; err != nil {
+			return err
+		}
+
+		if len(participants) < tournament.MinPlayers {
+			return s.cancelWithTx(tx, &tournament, "not enough players")
+		}
+
+		// Seed from current rating, not the registration snapshot
+		for i := range participants {
+			participants[i].Rating = participants[i].User.ELO
+		}
+		seedParticipants(participants)
+		for i := range participants {
+			if err := tx.Model(&participants[i]).Updates(map[string]interface{}{
+				"seed":   participants[i].Seed,
+				"rating": participants[i].Rating,
+			}).Error; err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		tournament.Status = models.TournamentStatusInProgress
+		tournament.StartedAt = &now
+		tournament.CurrentRound = 1
+
+		var pairings []tournamentPairing
+		switch tournament.Format {
+		case models.TournamentFormatSingleElim:
+			pairings = pairFirstEliminationRound(participants, bracketMain)
+		case models.TournamentFormatDoubleElim:
+			pairings = pairFirstEliminationRound(participants, bracketWinners)
+		case models.TournamentFormatSwiss:
+			pairings = pairSwissRound(participants, nil)
+		}
+
+		if err := tx.Save(&tournament).Error; err != nil {
+			return err
+		}
+		return s.scheduleRound(tx, &tournament, pairings)
+	})
+}
+
+// CancelTournament refunds every entry fee and stops all pending matches
+func (s *TournamentService) CancelTournament(tournamentID, adminID uint, reason string) error {
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		var tournament models.Tournament
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&tournament, tournamentID).Error; err != nil {
+			return errors.New("tournament not found")
+		}
+		if tournament.Status == models.TournamentStatusCompleted || tournament.Status == models.TournamentStatusCancelled {
+			return errors.New("tournament already finished")
+		}
+		return s.cancelWithTx(tx, &tournament, reason)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.admin.CreateAuditLog(adminID, "CANCEL_TOURNAMENT", strconv.Itoa(int(tournamentID)), "", reason)
+	return nil
+}
+
+func (s *TournamentService) cancelWithTx(tx *gorm.DB, tournament *models.Tournament, reason string) error {
+	var participants []models.TournamentParticipant
+	if err := tx.Where("tournament_id = ?", tournament.ID).Find(&participants).Error; err != nil {
+		return err
+	}
+
+	for _, p := range participants {
+		if p.EntryPaid <= 0 {
+			continue
+		}
+		if err := s.moveEscrow(tx, p.UserID, p.EntryPaid, false, models.TxTypeTournamentRefund,
+			fmt.Sprintf("tournament_%d_refund_%d", tournament.ID, p.UserID), "Tournament Cancelled: "+reason); err != nil {
+			return err
+		}
+	}
+
+	// Close any battles still running for this tournament
+	var battleIDs []uint
+	tx.Model(&models.TournamentMatch{}).
+		Where("tournament_id = ? AND status = ? AND battle_id IS NOT NULL", tournament.ID, models.TournamentMatchScheduled).
+		Pluck("battle_id", &battleIDs)
+	if len(battleIDs) > 0 {
+		if err := tx.Model(&models.Battle{}).Where("id IN ? AND status = ?", battleIDs, "active").
+			Update("status", "cancelled").Error; err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	tournament.Status = models.TournamentStatusCancelled
+	tournament.PrizePool = 0
+	tournament.CompletedAt = &now
+	return tx.Save(tournament).Error
+}
+
+// scheduleRound persists pairings and creates a Battle row for every real match.
+// Byes are decided immediately; a player without an active team forfeits by walkover.
+func (s *TournamentService) scheduleRound(tx *gorm.DB, tournament *models.Tournament, pairings []tournamentPairing) error {
+	now := time.Now()
+	deadline := now.Add(time.Duration(tournament.MatchTimeoutMinutes) * time.Minute)
+
+	for i, p := range pairings {
+		p1 := p.Player1
+		match := models.TournamentMatch{
+			TournamentID: tournament.ID,
+			Round:        tournament.CurrentRound,
+			Bracket:      p.Bracket,
+			MatchNumber:  i + 1,
+			Player1ID:    &p1,
+			Status:       models.TournamentMatchPending,
+		}
+
+		// Byes and walkovers for a missing team are decided without a battle, so nobody's
+		// team wears
+		if p.Player2 == 0 {
+			if err := tx.Create(&match).Error; err != nil {
+				return err
+			}
+			if err := s.recordResult(tx, tournament, &match, p.Player1, models.TournamentMatchBye); err != nil {
+				return err
+			}
+			continue
+		}
+
+		p2 := p.Player2
+		match.Player2ID = &p2
+		if err := tx.Create(&match).Error; err != nil {
+			return err
+		}
+
+		_, err1 := s.battleService.GetTeamSnapshot(p.Player1)
+		_, err2 := s.battleService.GetTeamSnapshot(p.Player2)
+		if err1 != nil || err2 != nil {
+			winner := p.Player1
+			if err1 != nil && err2 == nil {
+				winner = p.Player2
+			}
+			if err := s.recordResult(tx, tournament, &match, winner, models.TournamentMatchWalkover); err != nil {
+				return err
+			}
+			continue
+		}
+
+		battle := models.Battle{
+			BattleType:          "tournament",
+			Status:              "active",
+			Player1ID:           p.Player1,
+			Player2ID:           p.Player2,
+			CurrentTurnPlayerID: p.Player1,
+			TurnNumber:          1,
+		}
+		if err := s.battleService.InitializeBattleState(&battle); err != nil {
+			return err
+		}
+		if err := tx.Create(&battle).Error; err != nil {
+			return err
+		}
+
+		match.BattleID = &battle.ID
+		match.Status = models.TournamentMatchScheduled
+		match.DeadlineAt = &deadline
+		if err := tx.Save(&match).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// recordResult closes a match and updates both players' standings
+func (s *TournamentService) recordResult(tx *gorm.DB, tournament *models.Tournament, match *models.TournamentMatch, winnerID uint, status string) error {
+	now := time.Now()
+	match.WinnerID = &winnerID
+	match.Status = status
+	match.CompletedAt = &now
+	if err := tx.Save(match).Error; err != nil {
+		return err
+	}
+
+	winnerUpdates := map[string]interface{}{
+		"wins":   gorm.Expr("wins + 1"),
+		"points": gorm.Expr("points + 1"),
+	}
+	if status == models.TournamentMatchBye {
+		winnerUpdates["had_bye"] = true
+	}
+	if err := tx.Model(&models.TournamentParticipant{}).
+		Where("tournament_id = ? AND user_id = ?", tournament.ID, winnerID).
+		Updates(winnerUpdates).Error; err != nil {
+		return err
+	}
+
+	if match.Player2ID == nil {
+		return nil
+	}
+	loserID := *match.Player1ID
+	if loserID == winnerID {
+		loserID = *match.Player2ID
+	}
+
+	var loser models.TournamentParticipant
+	if err := tx.Where("tournament_id = ? AND user_id = ?", tournament.ID, loserID).First(&loser).Error; err != nil {
+		return err
+	}
+	loser.Losses++
+	switch tournament.Format {
+	case models.TournamentFormatSingleElim:
+		loser.Eliminated = true
+	case models.TournamentFormatDoubleElim:
+		loser.Eliminated = loser.Losses >= 2
+	}
+	if loser.Eliminated {
+		loser.EliminatedRound = match.Round
+	}
+	return tx.Save(&loser).Error
+}
+
+// ProcessTournaments is the scheduler tick: it starts tournaments whose registration closed,
+// collects battle results, hands out walkovers on timeout and advances rounds.
+func (s *TournamentService) ProcessTournaments() error {
+	now := time.Now()
+
+	var closing []models.Tournament
+	if err := db.DB.Where("status = ? AND registration_closes_at <= ?", models.TournamentStatusRegistration, now).
+		Find(&closing).Error; err != nil {
+		return err
+	}
+	for _, t := range closing {
+		if err := s.StartTournament(t.ID); err != nil {
+			slog.Error("failed to start tournament", "tournament_id", t.ID, "error", err)
+		}
+	}
+
+	var running []models.Tournament
+	if err := db.DB.Where("status = ?", models.TournamentStatusInProgress).Find(&running).Error; err != nil {
+		return err
+	}
+	for _, t := range running {
+		if err := s.syncMatches(t.ID); err != nil {
+			slog.Error("failed to sync tournament matches", "tournament_id", t.ID, "error", err)
+		}
+	}
+	return nil
+}
+
+// AdvanceTournament forces a sync/advance pass for one tournament (admin "run" action)
+func (s *TournamentService) AdvanceTournament(tournamentID uint) (*models.Tournament, error) {
+	if err := s.syncMatches(tournamentID); err != nil {
+		return nil, err
+	}
+	return s.GetTournament(tournamentID)
+}
+
+// StartScheduler runs ProcessTournaments on a fixed interval in the background
+func (s *TournamentService) StartScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := s.ProcessTournaments(); err != nil {
+				slog.Error("tournament scheduler failed", "error", err)
+			}
+		}
+	}()
+}
+
+// syncMatches records finished battles and timeouts for the current round, then advances if it is complete
+func (s *TournamentService) syncMatches(tournamentID uint) error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		var tournament models.Tournament
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&tournament, tournamentID).Error; err != nil {
+			return errors.New("tournament not found")
+		}
+		if tournament.Status != models.TournamentStatusInProgress {
+			return nil
+		}
+
+		var matches []models.TournamentMatch
+		if err := tx.Where("tournament_id = ? AND round = ?", tournament.ID, tournament.CurrentRound).
+			Order("match_number ASC").Find(&matches).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for i := range matches {
+			m := &matches[i]
+			if m.Status != models.TournamentMatchScheduled || m.BattleID == nil {
+				continue
+			}
+
+			var battle models.Battle
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&battle, *m.BattleID).Error; err != nil {
+				return err
+			}
+
+			if battle.Status == "completed" && battle.WinnerID != nil && *battle.WinnerID != 0 {
+				if err := s.recordResult(tx, &tournament, m, *battle.WinnerID, models.TournamentMatchCompleted); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if m.DeadlineAt != nil && now.After(*m.DeadlineAt) {
+				// The player holding the turn is the one stalling
+				winnerID := battle.Player1ID
+				if battle.CurrentTurnPlayerID == battle.Player1ID {
+					winnerID = battle.Player2ID
+				}
+				battle.Status = "timeout"
+				battle.WinnerID = &winnerID
+				battle.EndedAt = &now
+				if err := tx.Save(&battle).Error; err != nil {
+					return err
+				}
+				// Both teams fought until the deadline; CompleteBattle never sees this battle
+				if err := s.battleService.wearTeams(repository.NewGormStore(tx), &battle, winnerID); err != nil {
+					return err
+				}
+				if err := s.recordResult(tx, &tournament, m, winnerID, models.TournamentMatchWalkover); err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, m := range matches {
+			if !m.IsDecided() {
+				return nil // Round still in progress
+			}
+		}
+		return s.advanceRound(tx, &tournament, matches)
+	})
+}
+
+// advanceRound pairs the next round, or finishes the tournament when a champion is known
+func (s *TournamentService) advanceRound(tx *gorm.DB, tournament *models.Tournament, finished []models.TournamentMatch) error {
+	var participants []models.TournamentParticipant
+	if err := tx.Where("tournament_id = ?", tournament.ID).Order("seed ASC").Find(&participants).Error; err != nil {
+		return err
+	}
+
+	var pairings []tournamentPairing
+	switch tournament.Format {
+	case models.TournamentFormatSingleElim:
+		var winners []uint
+		for _, m := range finished {
+			winners = append(winners, *m.WinnerID)
+		}
+		switch len(winners) {
+		case 0:
+			return fmt.Errorf("tournament %d: round %d finished without a winner", tournament.ID, tournament.CurrentRound)
+		case 1:
+			return s.finishTournament(tx, tournament, participants, winners[0])
+		}
+		pairings = pairAdjacentWinners(winners, bracketMain)
+
+	case models.TournamentFormatDoubleElim:
+		var active []models.TournamentParticipant
+		for _, p := range participants {
+			if !p.Eliminated {
+				active = append(active, p)
+			}
+		}
+		switch len(active) {
+		case 0:
+			return fmt.Errorf("tournament %d: every player is eliminated", tournament.ID)
+		case 1:
+			return s.finishTournament(tx, tournament, participants, active[0].UserID)
+		}
+		pairings = pairDoubleEliminationRound(active)
+
+	case models.TournamentFormatSwiss:
+		var all []models.TournamentMatch
+		if err := tx.Where("tournament_id = ?", tournament.ID).Find(&all).Error; err != nil {
+			return err
+		}
+		if tournament.CurrentRound >= swissRoundCount(tournament.SwissRounds, len(participants)) {
+			standings := swissStandings(participants, all)
+			return s.finishTournament(tx, tournament, standings, standings[0].UserID)
+		}
+		played := make(map[uint]map[uint]bool)
+		for _, m := range all {
+			if m.Player1ID == nil || m.Player2ID == nil {
+				continue
+			}
+			a, b := *m.Player1ID, *m.Player2ID
+			if played[a] == nil {
+				played[a] = make(map[uint]bool)
+			}
+			if played[b] == nil {
+				played[b] = make(map[uint]bool)
+			}
+			played[a][b] = true
+			played[b][a] = true
+		}
+		pairings = pairSwissRound(participants, played)
+	}
+
+	tournament.CurrentRound++
+	if err := tx.Save(tournament).Error; err != nil {
+		return err
+	}
+	return s.scheduleRound(tx, tournament, pairings)
+}
+
+// finishTournament assigns placements and splits the escrowed prize pool through the ledger
+func (s *TournamentService) finishTournament(tx *gorm.DB, tournament *models.Tournament, participants []models.TournamentParticipant, championID uint) error {
+	standings := participants
+	if tournament.Format != models.TournamentFormatSwiss {
+		standings = eliminationStandings(participants, championID)
+	}
+
+	table, err := parsePayoutTable(tournament.PayoutTable)
+	if err != nil {
+		return err
+	}
+	amounts, remainder := splitPrizePool(tournament.PrizePool, table, len(standings))
+
+	if tournament.PrizePool > 0 {
+		escrowAcc, err := s.ledger.GetOrCreateAccount(nil, models.AccountTypeEscrow, "GTK")
+		if err != nil {
+			return err
+		}
+		entries := []models.LedgerEntry{
+			{AccountID: escrowAcc.ID, Amount: -tournament.PrizePool, Type: "DEBIT"},
+		}
+		for i, amount := range amounts {
+			if amount <= 0 {
+				continue
+			}
+			acc, err := s.ledger.GetOrCreateAccount(&standings[i].UserID, models.AccountTypeWallet, "GTK")
+			if err != nil {
+				return err
+			}
+			entries = append(entries, models.LedgerEntry{AccountID: acc.ID, Amount: amount, Type: "CREDIT"})
+		}
+		if remainder > 0 {
+			treasuryAcc, err := s.ledger.GetOrCreateAccount(nil, models.AccountTypeTreasury, "GTK")
+			if err != nil {
+				return err
+			}
+			entries = append(entries, models.LedgerEntry{AccountID: treasuryAcc.ID, Amount: remainder, Type: "CREDIT"})
+		}
+
+		if err := s.ledger.CreateTransactionWithTx(tx, models.TxTypeTournamentPrize, fmt.Sprintf("tournament_%d_payout", tournament.ID),
+			fmt.Sprintf("Tournament Prizes: %s", tournament.Name), entries); err != nil {
+			return err
+		}
+	}
+
+	for i, p := range standings {
+		updates := map[string]interface{}{"final_placement": i + 1}
+		if i < len(amounts) {
+			updates["prize_awarded"] = amounts[i]
+		}
+		if err := tx.Model(&models.TournamentParticipant{}).Where("id = ?", p.ID).Updates(updates).Error; err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	tournament.Status = models.TournamentStatusCompleted
+	tournament.WinnerID = &championID
+	tournament.CompletedAt = &now
+	return tx.Save(tournament).Error
+}
+
+// moveEscrow moves GTK between a player's wallet and the shared ESCROW account.
+// toEscrow=true locks funds (entry), false releases them back (refund).
+func (s *TournamentService) moveEscrow(tx *gorm.DB, userID uint, amount int64, toEscrow bool, txType models.TransactionType, refID, desc string) error {
+	userAcc, err := s.ledger.GetOrCreateAccount(&userID, models.AccountTypeWallet, "GTK")
+	if err != nil {
+		return err
+	}
+	escrowAcc, err := s.ledger.GetOrCreateAccount(nil, models.AccountTypeEscrow, "GTK")
+	if err != nil {
+		return err
+	}
+
+	from, to := userAcc.ID, escrowAcc.ID
+	if !toEscrow {
+		from, to = escrowAcc.ID, userAcc.ID
+	}
+	entries := []models.LedgerEntry{
+		{AccountID: from, Amount: -amount, Type: "DEBIT"},
+		{AccountID: to, Amount: amount, Type: "CREDIT"},
+	}
+	return s.ledger.CreateTransactionWithTx(tx, txType, refID, desc, entries)
+}
+
+// GetStandings returns the current standings for a tournament
+func (s *TournamentService) GetStandings(tournamentID uint) ([]models.TournamentParticipant, error) {
+	var tournament models.Tournament
+	if err := db.DB.First(&tournament, tournamentID).Error; err != nil {
+		return nil, errors.New("tournament not found")
+	}
+
+	var participants []models.TournamentParticipant
+	if err := db.DB.Where("tournament_id = ?", tournamentID).Order("seed ASC").Find(&participants).Error; err != nil {
+		return nil, err
+	}
+
+	if tournament.Status == models.TournamentStatusCompleted {
+		sort.SliceStable(participants, func(i, j int) bool {
+			return participants[i].FinalPlacement < participants[j].FinalPlacement
+		})
+		return participants, nil
+	}
+
+	if tournament.Format == models.TournamentFormatSwiss {
+		var matches []models.TournamentMatch
+		db.DB.Where("tournament_id = ?", tournamentID).Find(&matches)
+		return swissStandings(participants, matches), nil
+	}
+
+	var championID uint
+	if tournament.WinnerID != nil {
+		championID = *tournament.WinnerID
+	}
+	return eliminationStandings(participants, championID), nil
+}
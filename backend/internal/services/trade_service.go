@@ -0,0 +1,464 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/internal/repository"
+)
+
+// TradeOffer is what one side puts into a trade window
+type TradeOffer struct {
+	Assets []AssetRef `json:"assets"` // character, item (equipment) or egg
+	GTK    int64      `json:"gtk"`
+}
+
+// TradeWatcher reviews settled trades for real-money trading (AntiCheatService)
+type TradeWatcher interface {
+	ReviewTrade(trade *models.Trade)
+}
+
+// TradeService runs direct player-to-player trades. Both sides fill in an offer, any change
+// clears both confirmations, and once both confirm the same offers everything changes hands
+// in one transaction. Received assets cannot be traded or listed again during a cooling-off
+// period, which keeps traded goods from being flipped straight onto the marketplace.
+type TradeService struct {
+	store    repository.Store
+	ledger   *LedgerService
+	config   Settings
+	notifier Notifier
+	watcher  TradeWatcher
+}
+
+// NewTradeService creates the trade service; watcher may be nil
+func NewTradeService(store repository.Store, ledger *LedgerService, config Settings, notifier Notifier, watcher TradeWatcher) *TradeService {
+	return &TradeService{
+		store:    store,
+		ledger:   ledger,
+		config:   config,
+		notifier: notifier,
+		watcher:  watcher,
+	}
+}
+
+// OpenTrade opens an empty trade window between two players
+func (s *TradeService) OpenTrade(initiatorID, counterpartyID uint) (*models.Trade, error) {
+	if initiatorID == counterpartyID {
+		return nil, errors.New("cannot trade with yourself")
+	}
+	if _, err := s.store.Users().Get(counterpartyID); err != nil {
+		return nil, errors.New("player not found")
+	}
+
+	trade := models.Trade{
+		InitiatorID:    initiatorID,
+		CounterpartyID: counterpartyID,
+		Status:         models.TradeStatusOpen,
+		ExpiresAt:      time.Now().Add(time.Duration(s.config.GetInt("trade_window_minutes", 30)) * time.Minute),
+	}
+	if err := s.store.Trades().Create(&trade); err != nil {
+		return nil, err
+	}
+
+	s.notify(counterpartyID, "TRADE_REQUEST", "Trade Request",
+		fmt.Sprintf("Player #%d wants to trade with you", initiatorID), map[string]interface{}{"trade_id": trade.ID})
+	return &trade, nil
+}
+
+// SetOffer replaces what userID puts into a trade. Both confirmations are cleared so nobody
+// ends up bound to an offer they did not see.
+func (s *TradeService) SetOffer(userID, tradeID uint, offer TradeOffer) (*models.Trade, error) {
+	if offer.GTK < 0 {
+		return nil, errors.New("GTK amount cannot be negative")
+	}
+	if maxAssets := s.config.GetInt("trade_max_assets", 10); len(offer.Assets) > maxAssets {
+		return nil, fmt.Errorf("a side can offer at most %d assets", maxAssets)
+	}
+
+	var other uint
+	err := s.store.Transaction(func(tx repository.Store) error {
+		trade, err := s.lockOpen(tx, userID, tradeID)
+		if err != nil {
+			return err
+		}
+
+		seen := map[AssetRef]bool{}
+		assets := make([]models.TradeAsset, 0, len(offer.Assets))
+		for _, ref := range offer.Assets {
+			ref.AssetType = tradeAssetType(ref.AssetType)
+			if seen[ref] {
+				return fmt.Errorf("%s %d is offered twice", ref.AssetType, ref.AssetID)
+			}
+			seen[ref] = true
+			if err := s.checkAssetTx(tx, userID, ref); err != nil {
+				return err
+			}
+			assets = append(assets, models.TradeAsset{AssetType: ref.AssetType, AssetID: ref.AssetID})
+		}
+		if offer.GTK > 0 {
+			wallet, err := s.ledger.WithStore(tx).GetOrCreateAccount(&userID, models.AccountTypeWallet, "GTK")
+			if err != nil {
+				return err
+			}
+			if wallet.Balance < offer.GTK+s.fee(offer.GTK) {
+				return errors.New("insufficient GTK balance for this offer and its fee")
+			}
+		}
+
+		if err := tx.Trades().SetAssets(trade.ID, userID, assets); err != nil {
+			return err
+		}
+		if userID == trade.InitiatorID {
+			trade.InitiatorGTK, other = offer.GTK, trade.CounterpartyID
+		} else {
+			trade.CounterpartyGTK, other = offer.GTK, trade.InitiatorID
+		}
+		trade.InitiatorConfirmed, trade.CounterpartyConfirmed = false, false
+		return tx.Trades().Save(trade)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notify(other, "TRADE_UPDATED", "Trade Updated",
+		fmt.Sprintf("Trade #%d changed; review it before confirming", tradeID), map[string]interface{}{"trade_id": tradeID})
+	return s.store.Trades().Get(tradeID)
+}
+
+// Confirm accepts the current offers for userID. The second confirmation settles the trade.
+func (s *TradeService) Confirm(userID, tradeID uint) (*models.Trade, error) {
+	var trade *models.Trade
+	err := s.store.Transaction(func(tx repository.Store) error {
+		var err error
+		if trade, err = s.lockOpen(tx, userID, tradeID); err != nil {
+			return err
+		}
+		if userID == trade.InitiatorID {
+			trade.InitiatorConfirmed = true
+		} else {
+			trade.CounterpartyConfirmed = true
+		}
+		if trade.InitiatorConfirmed && trade.CounterpartyConfirmed {
+			if err := s.settleTx(tx, trade); err != nil {
+				return err
+			}
+		}
+		return tx.Trades().Save(trade)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	data := map[string]interface{}{"trade_id": trade.ID}
+	if trade.Status != models.TradeStatusCompleted {
+		s.notify(s.otherSide(trade, userID), "TRADE_CONFIRMED", "Trade Confirmed",
+			fmt.Sprintf("Player #%d confirmed trade #%d", userID, trade.ID), data)
+		return trade, nil
+	}
+	if s.watcher != nil {
+		s.watcher.ReviewTrade(trade)
+	}
+	for _, id := range []uint{trade.InitiatorID, trade.CounterpartyID} {
+		s.notify(id, "TRADE_COMPLETED", "Trade Completed", fmt.Sprintf("Trade #%d went through", trade.ID), data)
+	}
+	return trade, nil
+}
+
+// settleTx swaps everything in a confirmed trade. Every asset is locked and re-checked first,
+// in a fixed order so two trades sharing assets cannot deadlock; the GTK legs and both fees
+// go through one ledger transaction that always holds both wallets.
+func (s *TradeService) settleTx(tx repository.Store, trade *models.Trade) error {
+	assets := trade.Assets
+	sort.Slice(assets, func(i, j int) bool {
+		if assets[i].AssetType != assets[j].AssetType {
+			return assets[i].AssetType < assets[j].AssetType
+		}
+		return assets[i].AssetID < assets[j].AssetID
+	})
+	if len(assets) == 0 && trade.InitiatorGTK == 0 && trade.CounterpartyGTK == 0 {
+		return errors.New("trade is empty")
+	}
+	for _, a := range assets {
+		if err := s.checkAssetTx(tx, a.FromUserID, AssetRef{AssetType: a.AssetType, AssetID: a.AssetID}); err != nil {
+			return err
+		}
+	}
+
+	initiatorFee, counterpartyFee := s.fee(trade.InitiatorGTK), s.fee(trade.CounterpartyGTK)
+	initiatorNet := trade.CounterpartyGTK - trade.InitiatorGTK - initiatorFee
+	counterpartyNet := trade.InitiatorGTK - trade.CounterpartyGTK - counterpartyFee
+
+	ledger := s.ledger.WithStore(tx)
+	initiatorAcc, err := ledger.GetOrCreateAccount(&trade.InitiatorID, models.AccountTypeWallet, "GTK")
+	if err != nil {
+		return err
+	}
+	counterpartyAcc, err := ledger.GetOrCreateAccount(&trade.CounterpartyID, models.AccountTypeWallet, "GTK")
+	if err != nil {
+		return err
+	}
+	treasuryAcc, err := ledger.GetOrCreateAccount(nil, models.AccountTypeTreasury, "GTK")
+	if err != nil {
+		return err
+	}
+	if initiatorAcc.Balance+initiatorNet < 0 || counterpartyAcc.Balance+counterpartyNet < 0 {
+		return errors.New("a side cannot cover its GTK and the trade fee")
+	}
+	entries := []models.LedgerEntry{
+		{AccountID: initiatorAcc.ID, Amount: initiatorNet, Type: entryType(initiatorNet)},
+		{AccountID: counterpartyAcc.ID, Amount: counterpartyNet, Type: entryType(counterpartyNet)},
+		{AccountID: treasuryAcc.ID, Amount: initiatorFee + counterpartyFee, Type: "CREDIT"},
+	}
+	if err := ledger.CreateTransaction(models.TxTypeTrade, fmt.Sprintf("trade_%d", trade.ID),
+		fmt.Sprintf("Player trade #%d", trade.ID), entries); err != nil {
+		return fmt.Errorf("ledger transaction failed: %v", err)
+	}
+	if err := tx.Users().AdjustLegacyTokens(trade.InitiatorID, initiatorNet); err != nil {
+		return err
+	}
+	if err := tx.Users().AdjustLegacyTokens(trade.CounterpartyID, counterpartyNet); err != nil {
+		return err
+	}
+
+	for _, a := range assets {
+		to := s.otherSide(trade, a.FromUserID)
+		if err := s.moveAssetTx(tx, a, to); err != nil {
+			return err
+		}
+		if err := tx.Listings().CreateTrade(&models.TradeHistory{TradeID: &trade.ID, SellerID: a.FromUserID, BuyerID: to,
+			ItemType: a.AssetType, ItemID: a.AssetID, Currency: "TRADE"}); err != nil {
+			return err
+		}
+	}
+	legs := []struct {
+		from, to uint
+		amount   int64
+	}{
+		{trade.InitiatorID, trade.CounterpartyID, trade.InitiatorGTK},
+		{trade.CounterpartyID, trade.InitiatorID, trade.CounterpartyGTK},
+	}
+	for _, leg := range legs {
+		if leg.amount == 0 {
+			continue
+		}
+		if err := tx.Listings().CreateTrade(&models.TradeHistory{TradeID: &trade.ID, SellerID: leg.from, BuyerID: leg.to,
+			ItemType: "gtk", Price: leg.amount, Currency: "GTK"}); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	trade.Status = models.TradeStatusCompleted
+	trade.CompletedAt = &now
+	trade.Fee = initiatorFee + counterpartyFee
+	return nil
+}
+
+// moveAssetTx hands one traded asset over; characters leave the sender's teams and gear
+func (s *TradeService) moveAssetTx(tx repository.Store, a models.TradeAsset, toUserID uint) error {
+	switch a.AssetType {
+	case "character":
+		// The gear belongs to the sender and stays behind
+		if err := stripLoadout(tx, a.AssetID); err != nil {
+			return err
+		}
+		if err := tx.Characters().Transfer(a.AssetID, a.FromUserID, toUserID); err != nil {
+			return err
+		}
+		if err := tx.Characters().RemoveFromTeams(a.AssetID, a.FromUserID); err != nil {
+			return err
+		}
+		return recordTransfer(tx, a.AssetID, a.FromUserID, toUserID, ProvenanceViaTrade)
+	case "item":
+		if err := tx.Items().Transfer(a.AssetID, a.FromUserID, toUserID); err != nil {
+			return err
+		}
+		return moveRunesTx(tx, a.AssetID, a.FromUserID, toUserID)
+	case "egg":
+		return tx.Eggs().Transfer(a.AssetID, a.FromUserID, toUserID)
+	}
+	return fmt.Errorf("unsupported asset type: %s", a.AssetType)
+}
+
+// checkAssetTx locks an asset and checks userID may trade it away right now
+func (s *TradeService) checkAssetTx(tx repository.Store, userID uint, ref AssetRef) error {
+	switch ref.AssetType {
+	case "character":
+		c, err := tx.Characters().Lock(ref.AssetID)
+		if err != nil || c.OwnerID != userID {
+			return fmt.Errorf("you don't own character %d", ref.AssetID)
+		}
+		switch {
+		case c.ControllerID != nil:
+			return fmt.Errorf("character %d is rented out", ref.AssetID)
+		case c.IsListed:
+			return fmt.Errorf("character %d is listed on the marketplace", ref.AssetID)
+		}
+	case "item":
+		item, err := tx.Items().Lock(ref.AssetID)
+		if err != nil || item.OwnerID != userID {
+			return fmt.Errorf("you don't own item %d", ref.AssetID)
+		}
+		switch {
+		case item.IsEquipped:
+			return fmt.Errorf("item %d is equipped", ref.AssetID)
+		case item.IsListed:
+			return fmt.Errorf("item %d is listed on the marketplace", ref.AssetID)
+		}
+	case "egg":
+		egg, err := tx.Eggs().Lock(ref.AssetID)
+		if err != nil || egg.UserID != userID {
+			return fmt.Errorf("you don't own egg %d", ref.AssetID)
+		}
+		if egg.HatchedAt != nil || egg.IncubationStartedAt != nil {
+			return fmt.Errorf("egg %d is already incubating", ref.AssetID)
+		}
+	default:
+		return fmt.Errorf("unsupported asset type: %s", ref.AssetType)
+	}
+	return checkTradeCooloff(tx, s.config, ref)
+}
+
+// lockOpen locks a trade userID is part of and checks it can still change
+func (s *TradeService) lockOpen(tx repository.Store, userID, tradeID uint) (*models.Trade, error) {
+	trade, err := tx.Trades().Lock(tradeID)
+	if err != nil || (trade.InitiatorID != userID && trade.CounterpartyID != userID) {
+		return nil, errors.New("trade not found")
+	}
+	if trade.Status != models.TradeStatusOpen {
+		return nil, errors.New("trade is no longer open")
+	}
+	if time.Now().After(trade.ExpiresAt) {
+		return nil, errors.New("trade has expired")
+	}
+	return trade, nil
+}
+
+// Cancel closes an open trade; either side may walk away until it settles
+func (s *TradeService) Cancel(userID, tradeID uint) error {
+	var trade *models.Trade
+	err := s.store.Transaction(func(tx repository.Store) error {
+		var err error
+		if trade, err = s.lockOpen(tx, userID, tradeID); err != nil {
+			return err
+		}
+		trade.Status = models.TradeStatusCancelled
+		return tx.Trades().Save(trade)
+	})
+	if err != nil {
+		return err
+	}
+	s.notify(s.otherSide(trade, userID), "TRADE_CANCELLED", "Trade Cancelled",
+		fmt.Sprintf("Player #%d cancelled trade #%d", userID, trade.ID), map[string]interface{}{"trade_id": trade.ID})
+	return nil
+}
+
+// GetTrade returns a trade userID is part of
+func (s *TradeService) GetTrade(userID, tradeID uint) (*models.Trade, error) {
+	trade, err := s.store.Trades().Get(tradeID)
+	if err != nil || (trade.InitiatorID != userID && trade.CounterpartyID != userID) {
+		return nil, errors.New("trade not found")
+	}
+	return trade, nil
+}
+
+// ListTrades returns a player's trades, optionally in one status
+func (s *TradeService) ListTrades(userID uint, status string) ([]models.Trade, error) {
+	return s.store.Trades().ListForUser(userID, status)
+}
+
+// ProcessExpired closes trade windows nobody finished in time
+func (s *TradeService) ProcessExpired() error {
+	expired, err := s.store.Trades().ListExpired(time.Now())
+	if err != nil {
+		return err
+	}
+	for _, t := range expired {
+		err := s.store.Transaction(func(tx repository.Store) error {
+			trade, err := tx.Trades().Lock(t.ID)
+			if err != nil {
+				return err
+			}
+			// Settled or cancelled since the scan
+			if trade.Status != models.TradeStatusOpen {
+				return nil
+			}
+			trade.Status = models.TradeStatusExpired
+			return tx.Trades().Save(trade)
+		})
+		if err != nil {
+			slog.Error("failed to expire trade", "trade_id", t.ID, "error", err)
+		}
+	}
+	return nil
+}
+
+// StartScheduler runs ProcessExpired on a fixed interval in the background
+func (s *TradeService) StartScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := s.ProcessExpired(); err != nil {
+				slog.Error("trade expiry failed", "error", err)
+			}
+		}
+	}()
+}
+
+// fee is what one side pays the treasury for sending gtk: a flat part plus a cut of the GTK
+func (s *TradeService) fee(gtk int64) int64 {
+	return int64(s.config.GetInt("trade_fee_flat", 5)) + gtk*int64(s.config.GetInt("trade_fee_percent", 5))/100
+}
+
+func (s *TradeService) otherSide(trade *models.Trade, userID uint) uint {
+	if userID == trade.InitiatorID {
+		return trade.CounterpartyID
+	}
+	return trade.InitiatorID
+}
+
+// notify sends a best-effort trade notification
+func (s *TradeService) notify(userID uint, notifType, title, message string, data interface{}) {
+	if err := s.notifier.CreateNotification(userID, notifType, title, message, data); err != nil {
+		slog.Warn("failed to send trade notification", "user_id", userID, "type", notifType, "error", err)
+	}
+}
+
+// tradeAssetType folds the marketplace's "equipment" alias into "item"
+func tradeAssetType(assetType string) string {
+	if assetType == "equipment" {
+		return "item"
+	}
+	return assetType
+}
+
+// checkTradeCooloff rejects assets that arrived through a player trade less than
+// trade_cooloff_hours ago, whether they are about to be traded again or listed
+func checkTradeCooloff(tx repository.Store, config Settings, ref AssetRef) error {
+	hours := config.GetInt("trade_cooloff_hours", 24)
+	if hours <= 0 {
+		return nil
+	}
+	assetType := tradeAssetType(ref.AssetType)
+	traded, err := tx.Listings().TradedSince(assetType, ref.AssetID, time.Now().Add(-time.Duration(hours)*time.Hour))
+	if err != nil {
+		return err
+	}
+	if traded {
+		return fmt.Errorf("%s %d was traded recently and is locked for %d hours", assetType, ref.AssetID, hours)
+	}
+	return nil
+}
+
+// entryType labels a ledger entry by its sign
+func entryType(amount int64) string {
+	if amount < 0 {
+		return "DEBIT"
+	}
+	return "CREDIT"
+}
@@ -0,0 +1,269 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/internal/repository"
+)
+
+// testWatcher records the trades handed to the RMT review
+type testWatcher struct {
+	reviewed []models.Trade
+}
+
+func (w *testWatcher) ReviewTrade(trade *models.Trade) { w.reviewed = append(w.reviewed, *trade) }
+
+func newTestTrades(st repository.Store) (*TradeService, *LedgerService, *testWatcher) {
+	ledger := NewLedgerService(st)
+	watcher := &testWatcher{}
+	return NewTradeService(st, ledger, testSettings{}, &testNotifier{}, watcher), ledger, watcher
+}
+
+// openTestTrade opens a trade between a and b with both offers filled in
+func openTestTrade(t *testing.T, svc *TradeService, a, b uint, offerA, offerB TradeOffer) *models.Trade {
+	t.Helper()
+	trade, err := svc.OpenTrade(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.SetOffer(a, trade.ID, offerA); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.SetOffer(b, trade.ID, offerB); err != nil {
+		t.Fatal(err)
+	}
+	return trade
+}
+
+func TestTradeSwapsBothSidesAtomically(t *testing.T) {
+	st := repository.NewMemoryStore()
+	svc, ledger, _ := newTestTrades(st)
+	alice := newTestTrader(t, st, ledger, 500)
+	bob := newTestTrader(t, st, ledger, 50)
+	char := newTestCharacter(t, st, alice.ID, 100, 50)
+	st.SetActiveTeam(alice.ID, char.ID)
+	sword := newTestGear(t, st, bob.ID, "weapon", "RARE")
+	egg := &models.Egg{UserID: bob.ID, Rarity: "COMMON", IncubationTime: 24}
+	if err := st.Eggs().Create(egg); err != nil {
+		t.Fatal(err)
+	}
+
+	trade := openTestTrade(t, svc, alice.ID, bob.ID,
+		TradeOffer{Assets: []AssetRef{{AssetType: "character", AssetID: char.ID}}, GTK: 100},
+		TradeOffer{Assets: []AssetRef{{AssetType: "equipment", AssetID: sword.ID}, {AssetType: "egg", AssetID: egg.ID}}})
+
+	// Bob lists the sword behind Alice's back: settling fails and nothing moves
+	if _, err := svc.Confirm(alice.ID, trade.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.Items().SetListed(sword.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Confirm(bob.ID, trade.ID); err == nil {
+		t.Fatal("trade settled with a listed item")
+	}
+	if got, _ := st.Characters().Get(char.ID); got.OwnerID != alice.ID {
+		t.Fatal("character moved in a failed trade")
+	}
+	if b := balance(t, ledger, &alice.ID, models.AccountTypeWallet); b != 500 {
+		t.Fatalf("alice wallet = %d after a failed trade, want 500", b)
+	}
+
+	if err := st.Items().SetListed(sword.ID, false); err != nil {
+		t.Fatal(err)
+	}
+	done, err := svc.Confirm(bob.ID, trade.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if done.Status != models.TradeStatusCompleted || done.Fee != 15 {
+		t.Fatalf("status %s fee %d, want COMPLETED with 15 in fees", done.Status, done.Fee)
+	}
+
+	if got, _ := st.Characters().Get(char.ID); got.OwnerID != bob.ID {
+		t.Fatalf("character owner = %d, want bob", got.OwnerID)
+	}
+	if got, _ := st.Items().Get(sword.ID); got.OwnerID != alice.ID {
+		t.Fatalf("sword owner = %d, want alice", got.OwnerID)
+	}
+	if got, _ := st.Eggs().Get(egg.ID); got.UserID != alice.ID {
+		t.Fatalf("egg owner = %d, want alice", got.UserID)
+	}
+	if team, _ := st.Characters().ActiveTeam(alice.ID); len(team) != 0 {
+		t.Fatal("traded character is still on alice's team")
+	}
+
+	// Alice pays 100 plus 5 + 5%; Bob pays the flat 5
+	if b := balance(t, ledger, &alice.ID, models.AccountTypeWallet); b != 390 {
+		t.Fatalf("alice wallet = %d, want 390", b)
+	}
+	if b := balance(t, ledger, &bob.ID, models.AccountTypeWallet); b != 145 {
+		t.Fatalf("bob wallet = %d, want 145", b)
+	}
+	if b := balance(t, ledger, nil, models.AccountTypeTreasury); b != 15 {
+		t.Fatalf("treasury = %d, want 15", b)
+	}
+
+	since := time.Now().Add(-time.Minute)
+	for _, ref := range []AssetRef{{"character", char.ID}, {"item", sword.ID}, {"egg", egg.ID}} {
+		if traded, _ := st.Listings().TradedSince(ref.AssetType, ref.AssetID, since); !traded {
+			t.Fatalf("%s %d missing from trade history", ref.AssetType, ref.AssetID)
+		}
+	}
+	history := provenanceEvents(t, st, char.ID)
+	if len(history) != 1 || *history[0].ToUserID != bob.ID {
+		t.Fatalf("provenance = %+v, want one transfer to bob", history)
+	}
+}
+
+func TestTradedCharacterLeavesGearBehind(t *testing.T) {
+	st := repository.NewMemoryStore()
+	svc, ledger, _ := newTestTrades(st)
+	gear := NewEquipmentService(st, ledger, testSettings{})
+	alice := newTestTrader(t, st, ledger, 100)
+	bob := newTestTrader(t, st, ledger, 100)
+	char := newTestCharacter(t, st, alice.ID, 100, 50)
+	levelCharacter(t, st, char, 1)
+	sword := newTestGear(t, st, alice.ID, "WEAPON", "C")
+	armor := newTestGear(t, st, alice.ID, "ARMOR", "C")
+	for _, it := range []*models.Item{sword, armor} {
+		if err := gear.Equip(alice.ID, char.ID, it.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	trade := openTestTrade(t, svc, alice.ID, bob.ID,
+		TradeOffer{Assets: []AssetRef{{AssetType: "character", AssetID: char.ID}}}, TradeOffer{GTK: 20})
+	if _, err := svc.Confirm(alice.ID, trade.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Confirm(bob.ID, trade.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, it := range []*models.Item{sword, armor} {
+		got, _ := st.Items().Get(it.ID)
+		if got.OwnerID != alice.ID || got.IsEquipped || got.EquippedByID != nil {
+			t.Fatalf("%s: owner %d equipped %v by %v, want alice's and unequipped", got.Name, got.OwnerID, got.IsEquipped, got.EquippedByID)
+		}
+	}
+	loadout, err := gear.GetLoadout(bob.ID, char.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loadout.Gear) != 0 {
+		t.Fatalf("traded character arrived wearing %d pieces", len(loadout.Gear))
+	}
+	// Alice can put her sword straight on another character
+	other := newTestCharacter(t, st, alice.ID, 100, 50)
+	levelCharacter(t, st, other, 1)
+	if err := gear.Equip(alice.ID, other.ID, sword.ID); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTradeOfferChangeClearsConfirmations(t *testing.T) {
+	st := repository.NewMemoryStore()
+	svc, ledger, _ := newTestTrades(st)
+	alice := newTestTrader(t, st, ledger, 100)
+	bob := newTestTrader(t, st, ledger, 100)
+	char := newTestCharacter(t, st, alice.ID, 100, 50)
+
+	trade := openTestTrade(t, svc, alice.ID, bob.ID,
+		TradeOffer{Assets: []AssetRef{{AssetType: "character", AssetID: char.ID}}}, TradeOffer{GTK: 50})
+	if _, err := svc.Confirm(alice.ID, trade.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// Bob lowers his price after Alice confirmed
+	changed, err := svc.SetOffer(bob.ID, trade.ID, TradeOffer{GTK: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed.InitiatorConfirmed || changed.CounterpartyConfirmed {
+		t.Fatal("confirmations survived an offer change")
+	}
+	if got, _ := svc.Confirm(bob.ID, trade.ID); got.Status != models.TradeStatusOpen {
+		t.Fatalf("status = %s after one confirmation, want OPEN", got.Status)
+	}
+	if got, _ := st.Characters().Get(char.ID); got.OwnerID != alice.ID {
+		t.Fatal("character moved before alice confirmed the new offer")
+	}
+
+	if err := svc.Cancel(alice.ID, trade.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Confirm(alice.ID, trade.ID); err == nil {
+		t.Fatal("cancelled trade confirmed")
+	}
+}
+
+func TestTradedAssetsCoolOff(t *testing.T) {
+	st := repository.NewMemoryStore()
+	svc, ledger, _ := newTestTrades(st)
+	market := NewMarketplaceService(st, ledger, testSettings{}, &testNotifier{})
+	alice := newTestTrader(t, st, ledger, 100)
+	bob := newTestTrader(t, st, ledger, 100)
+	carol := newTestTrader(t, st, ledger, 100)
+	char := newTestCharacter(t, st, alice.ID, 100, 50)
+
+	trade := openTestTrade(t, svc, alice.ID, bob.ID,
+		TradeOffer{Assets: []AssetRef{{AssetType: "character", AssetID: char.ID}}}, TradeOffer{GTK: 20})
+	if _, err := svc.Confirm(alice.ID, trade.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Confirm(bob.ID, trade.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := market.CreateListing(bob.ID, "character", char.ID, 500, "GTK"); err == nil {
+		t.Fatal("freshly traded character listed on the marketplace")
+	}
+	if _, err := market.CreateAuction(bob.ID, AssetRef{AssetType: "character", AssetID: char.ID}, 500, 0, 24); err == nil {
+		t.Fatal("freshly traded character auctioned")
+	}
+	next, err := svc.OpenTrade(bob.ID, carol.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.SetOffer(bob.ID, next.ID, TradeOffer{Assets: []AssetRef{{AssetType: "character", AssetID: char.ID}}}); err == nil {
+		t.Fatal("freshly traded character traded on")
+	}
+
+	// Without a cooling-off period it can move on straight away
+	relaxed := NewMarketplaceService(st, ledger, testSettings{"trade_cooloff_hours": 0}, &testNotifier{})
+	if _, err := relaxed.CreateListing(bob.ID, "character", char.ID, 500, "GTK"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOneSidedTradeIsReviewed(t *testing.T) {
+	st := repository.NewMemoryStore()
+	svc, ledger, watcher := newTestTrades(st)
+	alice := newTestTrader(t, st, ledger, 1000)
+	bob := newTestTrader(t, st, ledger, 10)
+
+	trade := openTestTrade(t, svc, alice.ID, bob.ID, TradeOffer{GTK: 800}, TradeOffer{})
+	if _, err := svc.Confirm(bob.ID, trade.ID); err != nil {
+		t.Fatal(err)
+	}
+	if len(watcher.reviewed) != 0 {
+		t.Fatal("open trade sent for review")
+	}
+	if _, err := svc.Confirm(alice.ID, trade.ID); err != nil {
+		t.Fatal(err)
+	}
+	if len(watcher.reviewed) != 1 {
+		t.Fatalf("%d trades reviewed, want 1", len(watcher.reviewed))
+	}
+	got := watcher.reviewed[0]
+	if got.ID != trade.ID || got.Status != models.TradeStatusCompleted || got.InitiatorGTK != 800 || got.CounterpartyGTK != 0 {
+		t.Fatalf("reviewed %+v, want the completed one-sided trade", got)
+	}
+	// Bob only paid the flat fee, so his wallet shows the gift
+	if b := balance(t, ledger, &bob.ID, models.AccountTypeWallet); b != 805 {
+		t.Fatalf("bob wallet = %d, want 805", b)
+	}
+}
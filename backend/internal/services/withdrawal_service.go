@@ -0,0 +1,379 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/lorengraff/crypto-tower-defense/internal/blockchain"
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/internal/repository"
+)
+
+// WithdrawalChain signs, sends and tracks TOWER transfers; *blockchain.TransferManager implements it
+type WithdrawalChain interface {
+	PendingNonce(ctx context.Context) (uint64, error)
+	Sign(ctx context.Context, nonce uint64, to common.Address, amount *big.Int) (*types.Transaction, error)
+	Broadcast(ctx context.Context, tx *types.Transaction) error
+	Replace(ctx context.Context, nonce uint64, to common.Address, amount, prevGasPrice *big.Int, bumpPercent int) (*types.Transaction, error)
+	Status(ctx context.Context, txHash common.Hash) (*types.Receipt, uint64, error)
+	ChainID() *big.Int
+}
+
+// WithdrawalService is the worker that pays out queued TOWER withdrawals on-chain
+type WithdrawalService struct {
+	store    repository.Store
+	chain    WithdrawalChain
+	config   Settings
+	notifier Notifier
+	admin    *AdminService
+}
+
+// NewWithdrawalService creates the withdrawal worker. chain may be nil when the blockchain client
+// is unavailable; withdrawals then stay queued and admin approvals still work.
+func NewWithdrawalService(store repository.Store, chain WithdrawalChain, config Settings, notifier Notifier, admin *AdminService) *WithdrawalService {
+	return &WithdrawalService{
+		store:    store,
+		chain:    chain,
+		config:   config,
+		notifier: notifier,
+		admin:    admin,
+	}
+}
+
+// ProcessBatch tracks sent transfers (confirm, bump or fail) then sends the next batch of approved withdrawals
+func (s *WithdrawalService) ProcessBatch(ctx context.Context) error {
+	if s.chain == nil {
+		return nil
+	}
+	if err := s.trackSent(ctx); err != nil {
+		return err
+	}
+	return s.sendApproved(ctx)
+}
+
+// StartScheduler runs ProcessBatch on an interval
+func (s *WithdrawalService) StartScheduler(interval time.Duration) {
+	if s.chain == nil {
+		slog.Warn("withdrawal worker disabled: no blockchain signer")
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			ctx, cancel := context.WithTimeout(context.Background(), interval)
+			if err := s.ProcessBatch(ctx); err != nil {
+				slog.Error("withdrawal worker failed", "error", err)
+			}
+			cancel()
+		}
+	}()
+}
+
+// sendApproved pays up to withdrawal_batch_size approved withdrawals with consecutive nonces.
+// Rows are claimed, signed and recorded as SENDING in one transaction before anything is
+// broadcast, so a crash or a second worker can never send the same withdrawal twice: a
+// SENDING row is only ever followed up by its recorded hashes and nonce (see trackSent).
+func (s *WithdrawalService) sendApproved(ctx context.Context) error {
+	pending, err := s.chain.PendingNonce(ctx)
+	if err != nil {
+		return err
+	}
+
+	var signed []*types.Transaction
+	var sending []models.Withdrawal
+	err = s.store.WithContext(ctx).Transaction(func(tx repository.Store) error {
+		queue, err := tx.Withdrawals().ClaimApproved(s.config.GetInt("withdrawal_batch_size", 20))
+		if err != nil || len(queue) == 0 {
+			return err
+		}
+		nonce, err := tx.Withdrawals().NextNonce(pending)
+		if err != nil {
+			return err
+		}
+
+		for i := range queue {
+			w := &queue[i]
+			transfer, err := s.chain.Sign(ctx, nonce, common.HexToAddress(w.ToAddress), blockchain.TowerToWei(w.Amount))
+			if err != nil {
+				return err
+			}
+			hash := transfer.Hash().Hex()
+			raw, _ := json.Marshal([]string{hash})
+			now, reserved := time.Now(), nonce
+			w.Status = models.WithdrawalSending
+			w.Nonce = &reserved
+			w.TxHash = &hash
+			w.TxHashes = string(raw)
+			w.GasPriceWei = transfer.GasPrice().String()
+			w.Attempts++
+			w.BroadcastAt = &now
+			w.LastError = ""
+			if err := tx.Withdrawals().Save(w); err != nil {
+				return err
+			}
+			signed = append(signed, transfer)
+			nonce++
+		}
+		sending = queue
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for i, transfer := range signed {
+		w := &sending[i]
+		if err := s.chain.Broadcast(ctx, transfer); err != nil {
+			// Left SENDING: trackSent finds the transfer if the node took it after all, or
+			// replaces it at the same nonce once it is stuck
+			slog.Warn("withdrawal broadcast failed", "withdrawal_id", w.ID, "nonce", transfer.Nonce(), "error", err)
+			w.LastError = truncate(err.Error(), 255)
+		} else {
+			w.Status = models.WithdrawalBroadcast
+		}
+		if err := s.store.WithContext(ctx).Withdrawals().Save(w); err != nil {
+			slog.Error("failed to record withdrawal broadcast", "withdrawal_id", w.ID, "tx_hash", transfer.Hash().Hex(), "error", err)
+		}
+	}
+	return nil
+}
+
+// trackSent checks every sending or broadcast withdrawal: confirm after enough blocks, refund on
+// revert, and replace the transfer at the same nonce with more gas when it is stuck
+func (s *WithdrawalService) trackSent(ctx context.Context) error {
+	inflight, err := s.store.WithContext(ctx).Withdrawals().ListByStatus(models.WithdrawalSending, models.WithdrawalBroadcast)
+	if err != nil {
+		return err
+	}
+
+	required := uint64(s.config.GetInt("withdrawal_confirmations", 12))
+	stuckAfter := time.Duration(s.config.GetInt("withdrawal_stuck_minutes", 10)) * time.Minute
+	bump := s.config.GetInt("withdrawal_gas_bump_percent", 15)
+
+	for i := range inflight {
+		w := &inflight[i]
+		receipt, confirmations, err := s.findReceipt(ctx, w)
+		if err != nil {
+			slog.Warn("withdrawal status check failed", "withdrawal_id", w.ID, "error", err)
+			continue
+		}
+
+		switch {
+		case receipt != nil && receipt.Status != types.ReceiptStatusSuccessful:
+			if err := s.fail(ctx, w.ID, models.WithdrawalFailed, "transfer reverted on-chain: "+receipt.TxHash.Hex()); err != nil {
+				slog.Error("failed to refund withdrawal", "withdrawal_id", w.ID, "error", err)
+			}
+		case receipt != nil:
+			if confirmations >= required {
+				if err := s.confirm(ctx, w.ID, receipt.TxHash.Hex()); err != nil {
+					slog.Error("failed to confirm withdrawal", "withdrawal_id", w.ID, "error", err)
+				}
+			}
+		case w.BroadcastAt != nil && time.Since(*w.BroadcastAt) > stuckAfter:
+			s.bumpGas(ctx, w, bump)
+		}
+	}
+	return nil
+}
+
+// findReceipt looks for a receipt of any hash sent for the withdrawal's nonce (original or replacement)
+func (s *WithdrawalService) findReceipt(ctx context.Context, w *models.Withdrawal) (*types.Receipt, uint64, error) {
+	var hashes []string
+	json.Unmarshal([]byte(w.TxHashes), &hashes)
+	for i := len(hashes) - 1; i >= 0; i-- {
+		receipt, confirmations, err := s.chain.Status(ctx, common.HexToHash(hashes[i]))
+		if err != nil {
+			return nil, 0, err
+		}
+		if receipt != nil {
+			return receipt, confirmations, nil
+		}
+	}
+	return nil, 0, nil
+}
+
+// bumpGas replaces a stuck transfer with the same nonce and a higher gas price. Only one
+// transfer per nonce can ever be mined, so this cannot pay twice.
+func (s *WithdrawalService) bumpGas(ctx context.Context, w *models.Withdrawal, bumpPercent int) {
+	if w.Nonce == nil {
+		return
+	}
+	prev, ok := new(big.Int).SetString(w.GasPriceWei, 10)
+	if !ok {
+		return
+	}
+	withdrawals := s.store.WithContext(ctx).Withdrawals()
+
+	tx, err := s.chain.Replace(ctx, *w.Nonce, common.HexToAddress(w.ToAddress), blockchain.TowerToWei(w.Amount), prev, bumpPercent)
+	if err != nil {
+		if errors.Is(err, blockchain.ErrGasPriceCap) {
+			slog.Warn("withdrawal stuck: gas price cap reached", "withdrawal_id", w.ID, "nonce", *w.Nonce)
+		} else {
+			slog.Warn("withdrawal gas bump failed", "withdrawal_id", w.ID, "error", err)
+		}
+		w.LastError = truncate(err.Error(), 255)
+		withdrawals.Save(w)
+		return
+	}
+
+	var hashes []string
+	json.Unmarshal([]byte(w.TxHashes), &hashes)
+	hash := tx.Hash().Hex()
+	raw, _ := json.Marshal(append(hashes, hash))
+	now := time.Now()
+	w.Status = models.WithdrawalBroadcast
+	w.TxHash = &hash
+	w.TxHashes = string(raw)
+	w.GasPriceWei = tx.GasPrice().String()
+	w.Attempts++
+	w.BroadcastAt = &now
+	if err := withdrawals.Save(w); err != nil {
+		slog.Error("failed to record withdrawal replacement", "withdrawal_id", w.ID, "tx_hash", hash, "error", err)
+		return
+	}
+	slog.Info("withdrawal replaced", "withdrawal_id", w.ID, "nonce", *w.Nonce, "tx_hash", hash, "gas_price_wei", tx.GasPrice().String())
+}
+
+// confirm marks the withdrawal and its Transaction as on-chain with the final hash
+func (s *WithdrawalService) confirm(ctx context.Context, withdrawalID uint, finalHash string) error {
+	var w *models.Withdrawal
+	err := s.store.WithContext(ctx).Transaction(func(tx repository.Store) error {
+		var err error
+		if w, err = tx.Withdrawals().Lock(withdrawalID); err != nil {
+			return err
+		}
+		now := time.Now()
+		w.Status = models.WithdrawalConfirmed
+		w.TxHash = &finalHash
+		w.ConfirmedAt = &now
+		if err := tx.Withdrawals().Save(w); err != nil {
+			return err
+		}
+		return tx.Withdrawals().MarkOnChain(w.TransactionID, finalHash, int(s.chain.ChainID().Int64()))
+	})
+	if err != nil {
+		return err
+	}
+
+	s.notifier.CreateNotification(w.UserID, "WITHDRAWAL_CONFIRMED", "Withdrawal complete",
+		fmt.Sprintf("%d TOWER arrived at %s", w.Amount, w.ToAddress),
+		map[string]interface{}{"withdrawal_id": w.ID, "tx_hash": finalHash})
+	return nil
+}
+
+// fail closes a withdrawal and refunds the TOWER debited by WithdrawTower
+func (s *WithdrawalService) fail(ctx context.Context, withdrawalID uint, status, reason string) error {
+	var w *models.Withdrawal
+	err := s.store.WithContext(ctx).Transaction(func(tx repository.Store) error {
+		var err error
+		if w, err = tx.Withdrawals().Lock(withdrawalID); err != nil {
+			return err
+		}
+		if w.Status == models.WithdrawalConfirmed || w.Status == models.WithdrawalFailed || w.Status == models.WithdrawalRejected {
+			return errors.New("withdrawal already closed")
+		}
+		w.Status = status
+		w.LastError = truncate(reason, 255)
+		if err := tx.Withdrawals().Refund(w, reason); err != nil {
+			return err
+		}
+		return tx.Withdrawals().Save(w)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.notifier.CreateNotification(w.UserID, "WITHDRAWAL_REFUNDED", "Withdrawal refunded",
+		fmt.Sprintf("Your withdrawal of %d TOWER could not be completed and was refunded", w.Amount),
+		map[string]interface{}{"withdrawal_id": w.ID, "reason": reason})
+	return nil
+}
+
+// Approve records an admin approval. Withdrawals above the threshold need two different admins.
+func (s *WithdrawalService) Approve(withdrawalID, adminID uint) (*models.Withdrawal, error) {
+	var w *models.Withdrawal
+	err := s.store.Transaction(func(tx repository.Store) error {
+		var err error
+		if w, err = tx.Withdrawals().Lock(withdrawalID); err != nil {
+			return errors.New("withdrawal not found")
+		}
+		if w.Status != models.WithdrawalPendingApproval {
+			return errors.New("withdrawal is not awaiting approval")
+		}
+		if w.UserID == adminID {
+			return errors.New("cannot approve your own withdrawal")
+		}
+
+		if w.FirstApproverID == nil {
+			w.FirstApproverID = &adminID
+		} else {
+			if *w.FirstApproverID == adminID {
+				return errors.New("a second admin must approve this withdrawal")
+			}
+			w.SecondApproverID = &adminID
+		}
+
+		approvals := 1
+		if w.SecondApproverID != nil {
+			approvals = 2
+		}
+		if approvals >= w.RequiredApprovals {
+			w.Status = models.WithdrawalApproved
+		}
+		return tx.Withdrawals().Save(w)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if s.admin != nil {
+		s.admin.CreateAuditLog(adminID, "APPROVE_WITHDRAWAL", fmt.Sprintf("%d", w.ID), "", w.Status)
+	}
+	return w, nil
+}
+
+// Reject closes a withdrawal awaiting approval and refunds the player
+func (s *WithdrawalService) Reject(withdrawalID, adminID uint, reason string) error {
+	w, err := s.store.Withdrawals().Get(withdrawalID)
+	if err != nil {
+		return errors.New("withdrawal not found")
+	}
+	if w.Status != models.WithdrawalPendingApproval {
+		return errors.New("only withdrawals awaiting approval can be rejected")
+	}
+	if reason == "" {
+		reason = "rejected by admin"
+	}
+	if err := s.fail(context.Background(), w.ID, models.WithdrawalRejected, reason); err != nil {
+		return err
+	}
+	if s.admin != nil {
+		s.admin.CreateAuditLog(adminID, "REJECT_WITHDRAWAL", fmt.Sprintf("%d", w.ID), w.Status, models.WithdrawalRejected)
+	}
+	return nil
+}
+
+// ListWithdrawals returns withdrawals by status (admin view)
+func (s *WithdrawalService) ListWithdrawals(status string, limit int) ([]models.Withdrawal, error) {
+	return s.store.Withdrawals().List(status, limit)
+}
+
+// GetUserWithdrawals returns a player's recent withdrawals
+func (s *WithdrawalService) GetUserWithdrawals(userID uint) ([]models.Withdrawal, error) {
+	return s.store.Withdrawals().ListForUser(userID, 50)
+}
+
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max]
+}
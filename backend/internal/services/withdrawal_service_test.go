@@ -0,0 +1,230 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/lorengraff/crypto-tower-defense/internal/blockchain"
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+	"github.com/lorengraff/crypto-tower-defense/internal/repository"
+)
+
+// newTestHotWallet funds a hot wallet on a simulated chain. The chain has no TOWER bytecode,
+// so transfers go to a code-less token address and always succeed.
+func newTestHotWallet(t *testing.T) (*blockchain.TransferManager, *simulated.Backend) {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	sim := simulated.NewBackend(types.GenesisAlloc{from: {Balance: new(big.Int).Mul(big.NewInt(1000), big.NewInt(1e18))}})
+	t.Cleanup(func() { sim.Close() })
+
+	chainID, err := sim.Client().ChainID(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := common.HexToAddress("0x00000000000000000000000000000000000070E5")
+	m, err := blockchain.NewTransferManager(sim.Client(), chainID, key, token, 100000, big.NewInt(100e9))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m, sim
+}
+
+// newTestWithdrawal queues a withdrawal of amount TOWER in the given status
+func newTestWithdrawal(t *testing.T, st repository.Store, userID uint, amount int64, status string) *models.Withdrawal {
+	t.Helper()
+	w := &models.Withdrawal{
+		UserID:    userID,
+		ToAddress: "0x1111111111111111111111111111111111111111",
+		Amount:    amount,
+		Status:    status,
+	}
+	if err := st.Withdrawals().Create(w); err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+// lostBroadcast is a chain whose Broadcast reports a failure; with deliver set the node
+// took the transfer anyway, as when the RPC call times out after the node accepted it
+type lostBroadcast struct {
+	WithdrawalChain
+	deliver bool
+}
+
+func (c lostBroadcast) Broadcast(ctx context.Context, tx *types.Transaction) error {
+	if c.deliver {
+		c.WithdrawalChain.Broadcast(ctx, tx)
+	}
+	return errors.New("connection reset")
+}
+
+func withdrawalState(t *testing.T, st repository.Store, id uint) *models.Withdrawal {
+	t.Helper()
+	w, err := st.Withdrawals().Get(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+func pendingNonce(t *testing.T, chain WithdrawalChain) uint64 {
+	t.Helper()
+	n, err := chain.PendingNonce(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestWithdrawalWorkerPaysEachWithdrawalOnce(t *testing.T) {
+	ctx := context.Background()
+	st := repository.NewMemoryStore()
+	chain, sim := newTestHotWallet(t)
+	notifier := &testNotifier{}
+	svc := NewWithdrawalService(st, chain, testSettings{"withdrawal_confirmations": 1}, notifier, nil)
+	user := newTestUser(t, st, 0)
+	first := newTestWithdrawal(t, st, user.ID, 100, models.WithdrawalApproved)
+	second := newTestWithdrawal(t, st, user.ID, 250, models.WithdrawalApproved)
+	waiting := newTestWithdrawal(t, st, user.ID, 50, models.WithdrawalPendingApproval)
+
+	if err := svc.ProcessBatch(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for i, w := range []*models.Withdrawal{first, second} {
+		got := withdrawalState(t, st, w.ID)
+		if got.Status != models.WithdrawalBroadcast || got.Nonce == nil || *got.Nonce != uint64(i) {
+			t.Fatalf("withdrawal %d: status %s nonce %v, want BROADCAST at nonce %d", w.ID, got.Status, got.Nonce, i)
+		}
+	}
+	if got := withdrawalState(t, st, waiting.ID); got.Status != models.WithdrawalPendingApproval {
+		t.Fatalf("unapproved withdrawal went to %s", got.Status)
+	}
+
+	// Nothing is mined yet; another tick must not send the same withdrawals again
+	if err := svc.ProcessBatch(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if n := pendingNonce(t, chain); n != 2 {
+		t.Fatalf("pending nonce = %d after a second tick, want 2", n)
+	}
+
+	sim.Commit()
+	if err := svc.ProcessBatch(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for _, w := range []*models.Withdrawal{first, second} {
+		got := withdrawalState(t, st, w.ID)
+		if got.Status != models.WithdrawalConfirmed || got.ConfirmedAt == nil {
+			t.Fatalf("withdrawal %d: status %s, want CONFIRMED", w.ID, got.Status)
+		}
+	}
+	if len(notifier.sent) != 2 || notifier.sent[0] != "WITHDRAWAL_CONFIRMED" {
+		t.Fatalf("notifications = %v, want two confirmations", notifier.sent)
+	}
+}
+
+func TestWithdrawalStuckSendingIsReconciledNotResent(t *testing.T) {
+	ctx := context.Background()
+	st := repository.NewMemoryStore()
+	chain, sim := newTestHotWallet(t)
+	settings := testSettings{"withdrawal_confirmations": 1}
+	user := newTestUser(t, st, 0)
+
+	// The node took the transfer but the worker never heard back
+	delivered := newTestWithdrawal(t, st, user.ID, 100, models.WithdrawalApproved)
+	if err := NewWithdrawalService(st, lostBroadcast{chain, true}, settings, &testNotifier{}, nil).ProcessBatch(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// The node never saw this one
+	dropped := newTestWithdrawal(t, st, user.ID, 200, models.WithdrawalApproved)
+	if err := NewWithdrawalService(st, lostBroadcast{chain, false}, settings, &testNotifier{}, nil).ProcessBatch(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for _, w := range []*models.Withdrawal{delivered, dropped} {
+		if got := withdrawalState(t, st, w.ID); got.Status != models.WithdrawalSending || got.LastError == "" {
+			t.Fatalf("withdrawal %d: status %s error %q, want SENDING with the broadcast error", w.ID, got.Status, got.LastError)
+		}
+	}
+
+	// A new withdrawal skips the nonce reserved by the dropped transfer
+	later := newTestWithdrawal(t, st, user.ID, 300, models.WithdrawalApproved)
+	svc := NewWithdrawalService(st, chain, settings, &testNotifier{}, nil)
+	if err := svc.ProcessBatch(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := withdrawalState(t, st, later.ID); got.Nonce == nil || *got.Nonce != 2 {
+		t.Fatalf("later withdrawal nonce = %v, want 2", got.Nonce)
+	}
+
+	sim.Commit()
+	if err := svc.ProcessBatch(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := withdrawalState(t, st, delivered.ID); got.Status != models.WithdrawalConfirmed || got.Attempts != 1 {
+		t.Fatalf("delivered withdrawal: status %s attempts %d, want CONFIRMED from its first transfer", got.Status, got.Attempts)
+	}
+	// Nonce 1 is missing, so the transfer at nonce 2 cannot be mined yet
+	if got := withdrawalState(t, st, dropped.ID); got.Status != models.WithdrawalSending {
+		t.Fatalf("dropped withdrawal: status %s, want SENDING until it is stuck", got.Status)
+	}
+
+	// Once stuck, the dropped transfer is replaced at its own nonce, which unblocks the later one
+	settings["withdrawal_stuck_minutes"] = 0
+	if err := svc.ProcessBatch(ctx); err != nil {
+		t.Fatal(err)
+	}
+	got := withdrawalState(t, st, dropped.ID)
+	var hashes []string
+	json.Unmarshal([]byte(got.TxHashes), &hashes)
+	if got.Status != models.WithdrawalBroadcast || *got.Nonce != 1 || len(hashes) != 2 {
+		t.Fatalf("dropped withdrawal: status %s nonce %d hashes %v, want a replacement at nonce 1", got.Status, *got.Nonce, hashes)
+	}
+
+	sim.Commit()
+	if err := svc.ProcessBatch(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for _, w := range []*models.Withdrawal{dropped, later} {
+		if got := withdrawalState(t, st, w.ID); got.Status != models.WithdrawalConfirmed {
+			t.Fatalf("withdrawal %d: status %s, want CONFIRMED", w.ID, got.Status)
+		}
+	}
+	if n := pendingNonce(t, chain); n != 3 {
+		t.Fatalf("pending nonce = %d, want 3 transfers for 3 withdrawals", n)
+	}
+}
+
+func TestRejectedWithdrawalIsRefunded(t *testing.T) {
+	st := repository.NewMemoryStore()
+	notifier := &testNotifier{}
+	svc := NewWithdrawalService(st, nil, testSettings{}, notifier, nil)
+	user := newTestUser(t, st, 0)
+	w := newTestWithdrawal(t, st, user.ID, 400, models.WithdrawalPendingApproval)
+
+	if err := svc.Reject(w.ID, 9, ""); err != nil {
+		t.Fatal(err)
+	}
+	if got := withdrawalState(t, st, w.ID); got.Status != models.WithdrawalRejected {
+		t.Fatalf("status = %s, want REJECTED", got.Status)
+	}
+	if u, _ := st.Users().Get(user.ID); u.TOWERBalance != 400 {
+		t.Fatalf("TOWER balance = %d, want the 400 refunded", u.TOWERBalance)
+	}
+	if err := svc.Reject(w.ID, 9, ""); err == nil {
+		t.Fatal("rejected a closed withdrawal twice")
+	}
+	if len(notifier.sent) != 1 || notifier.sent[0] != "WITHDRAWAL_REFUNDED" {
+		t.Fatalf("notifications = %v, want one refund", notifier.sent)
+	}
+}
@@ -0,0 +1,222 @@
+// Package sprites renders character sprite sheets offline by compositing layered PNG parts.
+//
+// Parts live in parts/<layer>/<key>.png on a shared PartSize canvas. Grayscale layers are
+// palette-swapped per element, then every layer is moved by keyframed transforms to
+// produce each frame. Artists can replace the embedded parts without code changes
+// (see NewFromFS); cmd/gen-sprite-parts regenerates the built-in set.
+package sprites
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"io/fs"
+	"math"
+	"path"
+	"sync"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+)
+
+//go:embed parts
+var embeddedParts embed.FS
+
+// Compositor assembles sprite sheets from a parts filesystem. It is safe for concurrent use.
+type Compositor struct {
+	parts fs.FS
+
+	mu    sync.Mutex
+	cache map[string]image.Image // Decoded (and palette-swapped) parts by path+element
+}
+
+// New returns a compositor over the built-in parts
+func New() *Compositor {
+	sub, err := fs.Sub(embeddedParts, "parts")
+	if err != nil {
+		panic(err) // The embed directive guarantees the directory exists
+	}
+	return NewFromFS(sub)
+}
+
+// NewFromFS returns a compositor over a custom parts tree (e.g. os.DirFS("art/parts"))
+func NewFromFS(parts fs.FS) *Compositor {
+	return &Compositor{parts: parts, cache: make(map[string]image.Image)}
+}
+
+// RenderSheet draws spec.FrameCount frames of frameSize x frameSize side by side
+func (c *Compositor) RenderSheet(t Traits, spec models.AnimationSpec, frameSize int) (*image.NRGBA, error) {
+	if frameSize <= 0 || spec.FrameCount <= 0 {
+		return nil, fmt.Errorf("invalid sheet size %dx%d frames", frameSize, spec.FrameCount)
+	}
+
+	layers, err := c.resolve(t)
+	if err != nil {
+		return nil, err
+	}
+	tracks := animationTracks[spec.Type]
+
+	sheet := image.NewNRGBA(image.Rect(0, 0, frameSize*spec.FrameCount, frameSize))
+	frame := image.NewNRGBA(image.Rect(0, 0, PartSize, PartSize))
+	scratch := image.NewNRGBA(frame.Bounds())
+	for i := 0; i < spec.FrameCount; i++ {
+		at := animationTime(spec, i)
+		root := tracks[TrackRoot].Sample(at).matrix(HipPivot)
+
+		draw.Draw(frame, frame.Bounds(), image.Transparent, image.Point{}, draw.Src)
+		for _, l := range layers {
+			m := tracks[l.Track].Sample(at).matrix(l.Pivot)
+			if l.Rooted {
+				m = root.mul(m)
+			}
+			draw.Draw(scratch, scratch.Bounds(), image.Transparent, image.Point{}, draw.Src)
+			warp(scratch, l.img, m)
+			draw.Draw(frame, frame.Bounds(), scratch, image.Point{}, draw.Over)
+		}
+
+		// Nearest-neighbour upscale keeps the pixel-art edges crisp
+		cell := image.Rect(i*frameSize, 0, (i+1)*frameSize, frameSize)
+		scaleNearest(sheet, cell, frame)
+	}
+	return sheet, nil
+}
+
+type resolvedLayer struct {
+	Layer
+	img image.Image
+}
+
+// resolve loads the part of every layer for these traits; layers without a matching or
+// default part (e.g. no aura below rank S) are skipped
+func (c *Compositor) resolve(t Traits) ([]resolvedLayer, error) {
+	palette := PaletteFor(t.Element)
+	var out []resolvedLayer
+	for _, l := range Layers {
+		img, err := c.part(l, l.Key(t), t.Element, palette)
+		if err != nil {
+			return nil, err
+		}
+		if img != nil {
+			out = append(out, resolvedLayer{Layer: l, img: img})
+		}
+	}
+	if len(out) == 0 {
+		return nil, errors.New("no sprite parts found")
+	}
+	return out, nil
+}
+
+func (c *Compositor) part(l Layer, key, element string, palette Palette) (image.Image, error) {
+	for _, name := range []string{key, "default"} {
+		if name == "" {
+			continue
+		}
+		file := path.Join(l.Name, name+".png")
+		cacheKey := file
+		if l.Recolor {
+			cacheKey += "@" + element
+		}
+
+		c.mu.Lock()
+		img, ok := c.cache[cacheKey]
+		c.mu.Unlock()
+		if ok {
+			return img, nil
+		}
+
+		f, err := c.parts.Open(file)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		decoded, err := png.Decode(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("sprite part %s: %w", file, err)
+		}
+		if decoded.Bounds().Dx() != PartSize || decoded.Bounds().Dy() != PartSize {
+			return nil, fmt.Errorf("sprite part %s must be %dx%d", file, PartSize, PartSize)
+		}
+		img = decoded
+		if l.Recolor {
+			img = palette.Swap(decoded)
+		}
+
+		c.mu.Lock()
+		c.cache[cacheKey] = img
+		c.mu.Unlock()
+		return img, nil
+	}
+	return nil, nil
+}
+
+// affine maps (x, y) to (a*x + b*y + c, d*x + e*y + f)
+type affine struct{ a, b, c, d, e, f float64 }
+
+func (m affine) mul(n affine) affine {
+	return affine{
+		a: m.a*n.a + m.b*n.d, b: m.a*n.b + m.b*n.e, c: m.a*n.c + m.b*n.f + m.c,
+		d: m.d*n.a + m.e*n.d, e: m.d*n.b + m.e*n.e, f: m.d*n.c + m.e*n.f + m.f,
+	}
+}
+
+func (m affine) invert() (affine, bool) {
+	det := m.a*m.e - m.b*m.d
+	if math.Abs(det) < 1e-9 {
+		return affine{}, false
+	}
+	return affine{
+		a: m.e / det, b: -m.b / det, c: (m.b*m.f - m.e*m.c) / det,
+		d: -m.d / det, e: m.a / det, f: (m.d*m.c - m.a*m.f) / det,
+	}, true
+}
+
+// matrix builds translate(pivot+D) * rotate * scale * translate(-pivot)
+func (t Transform) matrix(pivot image.Point) affine {
+	r := t.Rotate * math.Pi / 180
+	cos, sin := math.Cos(r), math.Sin(r)
+	px, py := float64(pivot.X), float64(pivot.Y)
+	a, b := cos*t.ScaleX, -sin*t.ScaleY
+	d, e := sin*t.ScaleX, cos*t.ScaleY
+	return affine{
+		a: a, b: b, c: px + t.DX - a*px - b*py,
+		d: d, e: e, f: py + t.DY - d*px - e*py,
+	}
+}
+
+// warp draws src into dst through m, sampling the nearest source pixel
+func warp(dst *image.NRGBA, src image.Image, m affine) {
+	inv, ok := m.invert()
+	if !ok {
+		return // Collapsed to nothing (scale 0)
+	}
+	sb := src.Bounds()
+	db := dst.Bounds()
+	for y := db.Min.Y; y < db.Max.Y; y++ {
+		for x := db.Min.X; x < db.Max.X; x++ {
+			fx, fy := float64(x)+0.5, float64(y)+0.5
+			sx := int(math.Floor(inv.a*fx + inv.b*fy + inv.c))
+			sy := int(math.Floor(inv.d*fx + inv.e*fy + inv.f))
+			if !image.Pt(sx, sy).In(sb) {
+				continue
+			}
+			dst.Set(x, y, src.At(sx, sy))
+		}
+	}
+}
+
+// scaleNearest fills cell in dst with src resized by nearest-neighbour sampling
+func scaleNearest(dst *image.NRGBA, cell image.Rectangle, src *image.NRGBA) {
+	sb := src.Bounds()
+	for y := cell.Min.Y; y < cell.Max.Y; y++ {
+		sy := sb.Min.Y + (y-cell.Min.Y)*sb.Dy()/cell.Dy()
+		for x := cell.Min.X; x < cell.Max.X; x++ {
+			sx := sb.Min.X + (x-cell.Min.X)*sb.Dx()/cell.Dx()
+			dst.SetNRGBA(x, y, src.NRGBAAt(sx, sy))
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package sprites
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+	"testing/fstest"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+)
+
+var testTraits = Traits{CharacterType: "DRAGON", Element: "Fire", Class: "Warrior", Rarity: "SS"}
+
+// pngFile encodes img as a parts filesystem entry
+func pngFile(t *testing.T, img image.Image) *fstest.MapFile {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return &fstest.MapFile{Data: buf.Bytes()}
+}
+
+// opaque reports whether any pixel of r in img is visible
+func opaque(img *image.NRGBA, r image.Rectangle) bool {
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if img.NRGBAAt(x, y).A != 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func TestPaletteSwapMapsLuminanceOntoRamp(t *testing.T) {
+	fire := PaletteFor("FIRE")
+	if PaletteFor("fire") != fire {
+		t.Fatal("element lookup is case sensitive")
+	}
+	if PaletteFor("Plasma") != neutralPalette {
+		t.Fatal("unknown element did not fall back to the neutral ramp")
+	}
+
+	// One pixel per ramp step, the last half transparent, then a hole
+	src := image.NewNRGBA(image.Rect(0, 0, 5, 1))
+	for x, luma := range []uint8{0, 85, 170, 255} {
+		a := uint8(0xFF)
+		if x == 3 {
+			a = 0x80
+		}
+		src.SetNRGBA(x, 0, color.NRGBA{luma, luma, luma, a})
+	}
+
+	got := fire.Swap(src)
+	if got.Bounds() != src.Bounds() {
+		t.Fatalf("swapped bounds %v, want %v", got.Bounds(), src.Bounds())
+	}
+	for x, step := range fire {
+		want := color.NRGBA{step.R, step.G, step.B, 0xFF}
+		if x == 3 {
+			want.A = 0x80
+		}
+		if c := got.NRGBAAt(x, 0); c != want {
+			t.Errorf("pixel %d = %v, want ramp step %v", x, c, want)
+		}
+	}
+	if c := got.NRGBAAt(4, 0); c.A != 0 {
+		t.Errorf("transparent pixel became %v", c)
+	}
+
+	// Halfway between two steps blends them
+	mid := fire.At(42)
+	if mid.R <= fire[0].R || mid.R >= fire[1].R {
+		t.Errorf("luma 42 = %v, want between %v and %v", mid, fire[0], fire[1])
+	}
+}
+
+func TestRenderSheetLaysFramesSideBySide(t *testing.T) {
+	c := New()
+	for _, spec := range models.GetAnimationSpecs() {
+		sheet, err := c.RenderSheet(testTraits, spec, 32)
+		if err != nil {
+			t.Fatalf("%s: %v", spec.Type, err)
+		}
+		if want := image.Rect(0, 0, 32*spec.FrameCount, 32); sheet.Bounds() != want {
+			t.Fatalf("%s: sheet %v, want %v", spec.Type, sheet.Bounds(), want)
+		}
+		moved := false
+		for i := 0; i < spec.FrameCount; i++ {
+			cell := image.Rect(i*32, 0, (i+1)*32, 32)
+			if !opaque(sheet, cell) {
+				t.Fatalf("%s: frame %d is empty", spec.Type, i)
+			}
+			if i > 0 && !bytes.Equal(sheet.SubImage(cell).(*image.NRGBA).Pix, sheet.SubImage(image.Rect(0, 0, 32, 32)).(*image.NRGBA).Pix) {
+				moved = true
+			}
+		}
+		if !moved {
+			t.Errorf("%s: every frame is identical", spec.Type)
+		}
+	}
+
+	if _, err := c.RenderSheet(testTraits, models.AnimationSpec{Type: models.SpriteIdle, FrameCount: 0}, 32); err == nil {
+		t.Fatal("rendered a sheet without frames")
+	}
+}
+
+func TestRenderSheetUpscalesPartPixels(t *testing.T) {
+	// A single 64x64 part whose left half is black and right half white
+	part := image.NewNRGBA(image.Rect(0, 0, PartSize, PartSize))
+	for y := 0; y < PartSize; y++ {
+		for x := 0; x < PartSize; x++ {
+			v := uint8(0)
+			if x >= PartSize/2 {
+				v = 0xFF
+			}
+			part.SetNRGBA(x, y, color.NRGBA{v, v, v, 0xFF})
+		}
+	}
+	c := NewFromFS(fstest.MapFS{"shadow/default.png": pngFile(t, part)})
+
+	// A one-frame sheet samples t=0, where the shadow track is at rest
+	sheet, err := c.RenderSheet(testTraits, models.AnimationSpec{Type: models.SpriteHit, FrameCount: 1}, 2*PartSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, x := range []int{0, PartSize - 1} {
+		if got := sheet.NRGBAAt(x, PartSize); got.R != 0 || got.A != 0xFF {
+			t.Errorf("pixel %d = %v, want black", x, got)
+		}
+	}
+	for _, x := range []int{PartSize, 2*PartSize - 1} {
+		if got := sheet.NRGBAAt(x, PartSize); got.R != 0xFF {
+			t.Errorf("pixel %d = %v, want white", x, got)
+		}
+	}
+}
+
+func TestPartsMustShareTheCanvas(t *testing.T) {
+	small := image.NewNRGBA(image.Rect(0, 0, 16, 16))
+	c := NewFromFS(fstest.MapFS{"body/default.png": pngFile(t, small)})
+	if _, err := c.RenderSheet(testTraits, models.GetAnimationSpecs()[0], 32); err == nil {
+		t.Fatal("rendered a part that is not PartSize square")
+	}
+	if _, err := NewFromFS(fstest.MapFS{}).RenderSheet(testTraits, models.GetAnimationSpecs()[0], 32); err == nil {
+		t.Fatal("rendered without any parts")
+	}
+}
+
+func TestAnimationTimeAndSampling(t *testing.T) {
+	loop := models.AnimationSpec{FrameCount: 8, IsLoop: true}
+	if got := animationTime(loop, 7); got != 7.0/8 {
+		t.Errorf("last loop frame at t=%v, want 7/8 so it never repeats frame 0", got)
+	}
+	once := models.AnimationSpec{FrameCount: 10}
+	if got := animationTime(once, 9); got != 1 {
+		t.Errorf("last one-shot frame at t=%v, want 1", got)
+	}
+
+	track := Track{kf(0, 0, 0, 0), kf(0.5, 4, -2, 90), kf(1, 0, 0, 0)}
+	if got := track.Sample(0.25); got.DX != 2 || got.DY != -1 || got.Rotate != 45 || got.ScaleX != 1 {
+		t.Errorf("sample at 0.25 = %+v, want halfway to the second keyframe", got)
+	}
+	if got := (Track{}).Sample(0.3); got != identity {
+		t.Errorf("empty track = %+v, want the identity", got)
+	}
+}
@@ -0,0 +1,152 @@
+package sprites
+
+import (
+	"math"
+
+	"github.com/lorengraff/crypto-tower-defense/internal/models"
+)
+
+// Transform moves a layer around its pivot: translate (part pixels), rotate (degrees, clockwise) and scale
+type Transform struct {
+	DX, DY float64
+	Rotate float64
+	ScaleX float64
+	ScaleY float64
+}
+
+// identity is the rest pose
+var identity = Transform{ScaleX: 1, ScaleY: 1}
+
+// Keyframe pins a transform at normalized animation time T (0-1)
+type Keyframe struct {
+	T float64
+	Transform
+}
+
+// Track is a keyframed transform channel. Layers subscribe to a track by name (see layers.go).
+type Track []Keyframe
+
+// Sample linearly interpolates the track at t; an empty track is the identity
+func (tr Track) Sample(t float64) Transform {
+	if len(tr) == 0 {
+		return identity
+	}
+	if t <= tr[0].T {
+		return tr[0].Transform
+	}
+	for i := 1; i < len(tr); i++ {
+		if t <= tr[i].T {
+			a, b := tr[i-1], tr[i]
+			f := (t - a.T) / (b.T - a.T)
+			lerp := func(x, y float64) float64 { return x + (y-x)*f }
+			return Transform{
+				DX:     lerp(a.DX, b.DX),
+				DY:     lerp(a.DY, b.DY),
+				Rotate: lerp(a.Rotate, b.Rotate),
+				ScaleX: lerp(a.ScaleX, b.ScaleX),
+				ScaleY: lerp(a.ScaleY, b.ScaleY),
+			}
+		}
+	}
+	return tr[len(tr)-1].Transform
+}
+
+// kf builds a keyframe with unit scale
+func kf(t, dx, dy, rot float64) Keyframe {
+	return Keyframe{T: t, Transform: Transform{DX: dx, DY: dy, Rotate: rot, ScaleX: 1, ScaleY: 1}}
+}
+
+// kfs builds a keyframe with uniform scale
+func kfs(t, dx, dy, rot, scale float64) Keyframe {
+	return Keyframe{T: t, Transform: Transform{DX: dx, DY: dy, Rotate: rot, ScaleX: scale, ScaleY: scale}}
+}
+
+// Track names
+const (
+	TrackRoot   = "root"   // Whole figure, pivot at the hips
+	TrackHead   = "head"   // Relative to root, pivot at the neck
+	TrackWeapon = "weapon" // Relative to root, pivot at the hand
+	TrackLegs   = "legs"   // Relative to root, pivot at the hips
+	TrackBack   = "back"   // Wings/tails, relative to root
+	TrackAura   = "aura"   // Relative to root, pivot at body center
+	TrackShadow = "shadow" // Independent of root so the shadow stays on the ground
+)
+
+// animationTracks holds the keyframes per animation. Looping animations end on their first keyframe.
+var animationTracks = map[models.SpriteAnimationType]map[string]Track{
+	models.SpriteIdle: {
+		TrackRoot:   {kf(0, 0, 0, 0), kf(0.5, 0, -1, 0), kf(1, 0, 0, 0)},
+		TrackHead:   {kf(0, 0, 0, 0), kf(0.5, 0, 0, 3), kf(1, 0, 0, 0)},
+		TrackWeapon: {kf(0, 0, 0, 0), kf(0.5, 0, 0, 4), kf(1, 0, 0, 0)},
+		TrackBack:   {kf(0, 0, 0, 0), kf(0.5, 0, 0, -5), kf(1, 0, 0, 0)},
+		TrackAura:   {kfs(0, 0, 0, 0, 1), kfs(0.5, 0, 0, 0, 1.06), kfs(1, 0, 0, 0, 1)},
+	},
+	models.SpriteWalk: {
+		TrackRoot:   {kf(0, 0, 0, 0), kf(0.25, 0, -1.5, 2), kf(0.5, 0, 0, 0), kf(0.75, 0, -1.5, 2), kf(1, 0, 0, 0)},
+		TrackLegs:   {kf(0, 0, 0, -8), kf(0.25, 0, 0, 0), kf(0.5, 0, 0, 8), kf(0.75, 0, 0, 0), kf(1, 0, 0, -8)},
+		TrackWeapon: {kf(0, 0, 0, 8), kf(0.5, 0, 0, -8), kf(1, 0, 0, 8)},
+		TrackBack:   {kf(0, 0, 0, 0), kf(0.25, 0, 0, -8), kf(0.5, 0, 0, 0), kf(0.75, 0, 0, -8), kf(1, 0, 0, 0)},
+		TrackShadow: {kfs(0, 0, 0, 0, 1), kfs(0.25, 0, 0, 0, 0.92), kfs(0.5, 0, 0, 0, 1), kfs(0.75, 0, 0, 0, 0.92), kfs(1, 0, 0, 0, 1)},
+	},
+	models.SpriteRun: {
+		TrackRoot:   {kf(0, 1, 0, 10), kf(0.25, 1, -3, 12), kf(0.5, 1, 0, 10), kf(0.75, 1, -3, 12), kf(1, 1, 0, 10)},
+		TrackLegs:   {kf(0, 0, 0, -16), kf(0.25, 0, 0, 0), kf(0.5, 0, 0, 16), kf(0.75, 0, 0, 0), kf(1, 0, 0, -16)},
+		TrackWeapon: {kf(0, 0, 0, 20), kf(0.5, 0, 0, -10), kf(1, 0, 0, 20)},
+		TrackBack:   {kf(0, 0, 0, -10), kf(0.5, 0, 0, -20), kf(1, 0, 0, -10)},
+		TrackShadow: {kfs(0, 1, 0, 0, 1), kfs(0.25, 1, 0, 0, 0.85), kfs(0.5, 1, 0, 0, 1), kfs(0.75, 1, 0, 0, 0.85), kfs(1, 1, 0, 0, 1)},
+	},
+	models.SpriteAttack: {
+		// 2 frames anticipation, 5 strike, 3 recovery
+		TrackRoot:   {kf(0, 0, 0, 0), kf(0.2, -2, 0, -6), kf(0.5, 4, 0, 8), kf(0.7, 3, 0, 5), kf(1, 0, 0, 0)},
+		TrackWeapon: {kf(0, 0, 0, 0), kf(0.2, 0, 0, -70), kf(0.45, 0, 0, 95), kf(0.7, 0, 0, 80), kf(1, 0, 0, 0)},
+		TrackHead:   {kf(0, 0, 0, 0), kf(0.2, 0, 0, -5), kf(0.5, 0, 0, 6), kf(1, 0, 0, 0)},
+		TrackShadow: {kf(0, 0, 0, 0), kf(0.2, -2, 0, 0), kf(0.5, 4, 0, 0), kf(1, 0, 0, 0)},
+	},
+	models.SpriteSkill: {
+		TrackRoot:   {kfs(0, 0, 0, 0, 1), kfs(0.6, 0, -2, -4, 1.04), kfs(0.75, 2, 0, 6, 1.02), kfs(1, 0, 0, 0, 1)},
+		TrackWeapon: {kf(0, 0, 0, 0), kf(0.6, 0, -2, -45), kf(0.75, 0, 0, 60), kf(1, 0, 0, 0)},
+		TrackAura:   {kfs(0, 0, 0, 0, 1), kfs(0.6, 0, 0, 0, 1.45), kfs(0.75, 0, 0, 0, 1.7), kfs(1, 0, 0, 0, 1)},
+		TrackBack:   {kf(0, 0, 0, 0), kf(0.6, 0, 0, -25), kf(1, 0, 0, 0)},
+	},
+	models.SpriteHit: {
+		TrackRoot:   {kf(0, 0, 0, 0), kf(0.2, -5, 0, -12), kf(0.5, -3, 0, -6), kf(1, 0, 0, 0)},
+		TrackHead:   {kf(0, 0, 0, 0), kf(0.2, 0, 0, -15), kf(1, 0, 0, 0)},
+		TrackWeapon: {kf(0, 0, 0, 0), kf(0.2, 0, 0, -30), kf(1, 0, 0, 0)},
+		TrackShadow: {kf(0, 0, 0, 0), kf(0.2, -4, 0, 0), kf(1, 0, 0, 0)},
+	},
+	models.SpriteBlock: {
+		TrackRoot:   {kf(0, 0, 1, -3), kf(0.4, -1, 1, -5), kf(0.6, -2, 1, -6), kf(1, 0, 1, -3)},
+		TrackWeapon: {kf(0, 0, -4, -75), kf(0.4, -1, -4, -78), kf(0.6, -2, -4, -72), kf(1, 0, -4, -75)},
+		TrackLegs:   {kf(0, 0, 0, 0), kf(0.5, 0, 0, -4), kf(1, 0, 0, 0)},
+	},
+	models.SpriteDodge: {
+		TrackRoot:   {kf(0, 0, 0, 0), kf(0.2, -3, 1, -6), kf(0.5, -14, -4, -18), kf(0.8, -6, 0, -6), kf(1, 0, 0, 0)},
+		TrackLegs:   {kf(0, 0, 0, 0), kf(0.5, 0, 0, 20), kf(1, 0, 0, 0)},
+		TrackShadow: {kfs(0, 0, 0, 0, 1), kfs(0.5, -14, 0, 0, 0.7), kfs(1, 0, 0, 0, 1)},
+	},
+	models.SpriteDeath: {
+		TrackRoot:   {kf(0, 0, 0, 0), kf(0.2, -1, 1, -6), kf(0.5, -2, 6, -40), kf(0.8, -3, 12, -90), kf(1, -3, 12, -90)},
+		TrackHead:   {kf(0, 0, 0, 0), kf(0.2, 0, 0, 10), kf(0.8, 0, 0, 20), kf(1, 0, 0, 20)},
+		TrackWeapon: {kf(0, 0, 0, 0), kf(0.5, 2, 2, 60), kf(0.8, 4, 4, 110), kf(1, 4, 4, 110)},
+		TrackAura:   {kfs(0, 0, 0, 0, 1), kfs(0.8, 0, 0, 0, 0.01), kfs(1, 0, 0, 0, 0.01)},
+		TrackShadow: {kfs(0, 0, 0, 0, 1), kfs(0.8, -4, 0, 0, 1.4), kfs(1, -4, 0, 0, 1.4)},
+	},
+	models.SpriteVictory: {
+		TrackRoot:   {kf(0, 0, 0, 0), kf(0.3, 0, -3, 0), kf(0.6, 0, 0, 0), kf(0.8, 0, -2, 0), kf(1, 0, 0, 0)},
+		TrackWeapon: {kf(0, 0, -2, -140), kf(0.3, 0, -4, -160), kf(0.6, 0, -2, -140), kf(0.8, 0, -3, -150), kf(1, 0, -2, -140)},
+		TrackHead:   {kf(0, 0, 0, -6), kf(0.5, 0, 0, -10), kf(1, 0, 0, -6)},
+		TrackAura:   {kfs(0, 0, 0, 0, 1.1), kfs(0.5, 0, 0, 0, 1.25), kfs(1, 0, 0, 0, 1.1)},
+	},
+}
+
+// animationTime maps a frame to its normalized time. Loops never sample t=1 (it equals t=0);
+// one-shots end exactly on their last keyframe.
+func animationTime(spec models.AnimationSpec, frame int) float64 {
+	if spec.FrameCount <= 1 {
+		return 0
+	}
+	if spec.IsLoop {
+		return float64(frame) / float64(spec.FrameCount)
+	}
+	return math.Min(1, float64(frame)/float64(spec.FrameCount-1))
+}
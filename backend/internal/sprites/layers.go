@@ -0,0 +1,55 @@
+package sprites
+
+import (
+	"image"
+	"strings"
+)
+
+// PartSize is the width and height of every part PNG. All parts share one canvas so that
+// stacking them unmodified yields the rest pose; anchors below are in part pixels.
+const PartSize = 64
+
+// Anchors in the part canvas (figure faces right, ground at y=58)
+var (
+	HipPivot    = image.Pt(32, 42)
+	NeckPivot   = image.Pt(32, 28)
+	HandPivot   = image.Pt(40, 38)
+	BodyCenter  = image.Pt(32, 36)
+	GroundPivot = image.Pt(32, 58)
+)
+
+// Traits selects the parts for a character
+type Traits struct {
+	CharacterType string // BEAST, DRAGON, BIRD, ...
+	Element       string // Palette
+	Class         string // Warrior, Mage, ...
+	Rarity        string // C .. SSS
+}
+
+// Layer is one slot of the figure, drawn back to front
+type Layer struct {
+	Name    string                // Directory under parts/
+	Key     func(t Traits) string // Part file (without .png); falls back to default.png
+	Recolor bool                  // Palette-swap with the element ramp
+	Track   string                // Keyframe track applied around Pivot
+	Pivot   image.Point
+	Rooted  bool // Also follows the root track
+}
+
+func byType(t Traits) string   { return strings.ToLower(t.CharacterType) }
+func byClass(t Traits) string  { return strings.ToLower(t.Class) }
+func byRarity(t Traits) string { return strings.ToLower(t.Rarity) }
+func always(Traits) string     { return "default" }
+
+// Layers is the figure's draw order
+var Layers = []Layer{
+	{Name: "shadow", Key: always, Track: TrackShadow, Pivot: GroundPivot},
+	{Name: "aura", Key: byRarity, Recolor: true, Track: TrackAura, Pivot: BodyCenter, Rooted: true},
+	{Name: "back", Key: byType, Recolor: true, Track: TrackBack, Pivot: BodyCenter, Rooted: true},
+	{Name: "legs", Key: always, Recolor: true, Track: TrackLegs, Pivot: HipPivot, Rooted: true},
+	{Name: "body", Key: byType, Recolor: true, Pivot: HipPivot, Rooted: true},
+	{Name: "outfit", Key: byClass, Pivot: HipPivot, Rooted: true},
+	{Name: "head", Key: byType, Recolor: true, Track: TrackHead, Pivot: NeckPivot, Rooted: true},
+	{Name: "weapon", Key: byClass, Track: TrackWeapon, Pivot: HandPivot, Rooted: true},
+	{Name: "trim", Key: byRarity, Track: TrackHead, Pivot: NeckPivot, Rooted: true},
+}
@@ -0,0 +1,67 @@
+package sprites
+
+import (
+	"image"
+	"image/color"
+	"strings"
+)
+
+// Palette is a four-step color ramp (darkest to lightest). Grayscale parts are
+// palette-swapped by mapping their luminance onto the ramp.
+type Palette [4]color.RGBA
+
+// elementPalettes covers both element spellings in use (gacha rolls "Fire", older seeds "FIRE")
+var elementPalettes = map[string]Palette{
+	"FIRE":     {{0x5A, 0x12, 0x08, 0xFF}, {0xA8, 0x2A, 0x10, 0xFF}, {0xE8, 0x5D, 0x1F, 0xFF}, {0xFF, 0xC2, 0x4B, 0xFF}},
+	"WATER":    {{0x0E, 0x2A, 0x5C, 0xFF}, {0x1C, 0x5C, 0xA8, 0xFF}, {0x3A, 0x9A, 0xE0, 0xFF}, {0x9E, 0xEC, 0xF4, 0xFF}},
+	"EARTH":    {{0x3B, 0x25, 0x12, 0xFF}, {0x6E, 0x48, 0x24, 0xFF}, {0xA3, 0x74, 0x3E, 0xFF}, {0xD9, 0xB3, 0x7A, 0xFF}},
+	"GRASS":    {{0x16, 0x3A, 0x14, 0xFF}, {0x2E, 0x6E, 0x26, 0xFF}, {0x5C, 0xAA, 0x3E, 0xFF}, {0xB6, 0xE3, 0x7C, 0xFF}},
+	"AIR":      {{0x3A, 0x4E, 0x5E, 0xFF}, {0x74, 0x94, 0xA8, 0xFF}, {0xB4, 0xD2, 0xDE, 0xFF}, {0xF0, 0xFA, 0xFF, 0xFF}},
+	"LIGHT":    {{0x6A, 0x55, 0x1C, 0xFF}, {0xB8, 0x9A, 0x3A, 0xFF}, {0xEE, 0xD6, 0x72, 0xFF}, {0xFF, 0xFB, 0xE0, 0xFF}},
+	"DARK":     {{0x0E, 0x08, 0x18, 0xFF}, {0x2E, 0x1C, 0x4A, 0xFF}, {0x5A, 0x3A, 0x86, 0xFF}, {0x9C, 0x7C, 0xC8, 0xFF}},
+	"ELECTRIC": {{0x4A, 0x36, 0x06, 0xFF}, {0x9C, 0x78, 0x0C, 0xFF}, {0xF2, 0xCF, 0x2C, 0xFF}, {0xFF, 0xF6, 0xB0, 0xFF}},
+	"ICE":      {{0x1E, 0x46, 0x5A, 0xFF}, {0x4E, 0x8F, 0xA8, 0xFF}, {0x9E, 0xE0, 0xF0, 0xFF}, {0xF4, 0xFF, 0xFF, 0xFF}},
+	"DRAGON":   {{0x1E, 0x10, 0x40, 0xFF}, {0x44, 0x28, 0x8C, 0xFF}, {0x7A, 0x52, 0xD0, 0xFF}, {0xE8, 0xC1, 0x4A, 0xFF}},
+}
+
+// neutralPalette is used for unknown elements so the sprite still renders
+var neutralPalette = Palette{{0x28, 0x28, 0x2E, 0xFF}, {0x5E, 0x5E, 0x68, 0xFF}, {0x9A, 0x9A, 0xA6, 0xFF}, {0xE2, 0xE2, 0xEA, 0xFF}}
+
+// PaletteFor returns the element's ramp
+func PaletteFor(element string) Palette {
+	if p, ok := elementPalettes[strings.ToUpper(element)]; ok {
+		return p
+	}
+	return neutralPalette
+}
+
+// At returns the ramp color for a luminance (0-255), interpolating between steps
+func (p Palette) At(luma uint8) color.RGBA {
+	pos := float64(luma) / 255 * float64(len(p)-1)
+	i := int(pos)
+	if i >= len(p)-1 {
+		return p[len(p)-1]
+	}
+	f := pos - float64(i)
+	lerp := func(a, b uint8) uint8 { return uint8(float64(a) + (float64(b)-float64(a))*f + 0.5) }
+	return color.RGBA{lerp(p[i].R, p[i+1].R), lerp(p[i].G, p[i+1].G), lerp(p[i].B, p[i+1].B), 0xFF}
+}
+
+// Swap recolors a grayscale part with the palette, keeping its alpha
+func (p Palette) Swap(src image.Image) *image.NRGBA {
+	b := src.Bounds()
+	dst := image.NewNRGBA(b)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := color.NRGBAModel.Convert(src.At(x, y)).(color.NRGBA)
+			if c.A == 0 {
+				continue
+			}
+			// Parts are authored in gray, so any channel is the luminance; average guards against tinted edits
+			luma := uint8((uint16(c.R) + uint16(c.G) + uint16(c.B)) / 3)
+			swapped := p.At(luma)
+			dst.SetNRGBA(x, y, color.NRGBA{swapped.R, swapped.G, swapped.B, c.A})
+		}
+	}
+	return dst
+}
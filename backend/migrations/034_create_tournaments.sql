@@ -0,0 +1,88 @@
+-- Migration: Tournament and bracket subsystem
+-- Description: Tournaments with registration windows, escrowed entry fees,
+-- single/double elimination and Swiss formats, and per-round matches linked to battles
+
+CREATE TABLE IF NOT EXISTS tournaments (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    deleted_at TIMESTAMP,
+
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    format VARCHAR(30) NOT NULL CHECK (format IN ('SINGLE_ELIMINATION', 'DOUBLE_ELIMINATION', 'SWISS')),
+    status VARCHAR(20) NOT NULL DEFAULT 'REGISTRATION' CHECK (status IN ('REGISTRATION', 'IN_PROGRESS', 'COMPLETED', 'CANCELLED')),
+
+    entry_fee BIGINT NOT NULL DEFAULT 0,
+    min_players INT NOT NULL DEFAULT 4,
+    max_players INT NOT NULL DEFAULT 16,
+    registration_opens_at TIMESTAMP,
+    registration_closes_at TIMESTAMP NOT NULL,
+
+    match_timeout_minutes INT NOT NULL DEFAULT 30,
+    swiss_rounds INT DEFAULT 0,
+    current_round INT DEFAULT 0,
+
+    prize_pool BIGINT NOT NULL DEFAULT 0,
+    payout_table TEXT NOT NULL DEFAULT '[50,30,20]',
+
+    winner_id INT REFERENCES users(id) ON DELETE SET NULL,
+    started_at TIMESTAMP,
+    completed_at TIMESTAMP,
+    created_by INT
+);
+
+CREATE INDEX IF NOT EXISTS idx_tournaments_status ON tournaments(status);
+CREATE INDEX IF NOT EXISTS idx_tournaments_registration_closes ON tournaments(registration_closes_at);
+CREATE INDEX IF NOT EXISTS idx_tournaments_deleted_at ON tournaments(deleted_at);
+
+CREATE TABLE IF NOT EXISTS tournament_participants (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    tournament_id INT NOT NULL REFERENCES tournaments(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+
+    seed INT DEFAULT 0,
+    rating INT DEFAULT 0,
+    entry_paid BIGINT DEFAULT 0,
+    wins INT DEFAULT 0,
+    losses INT DEFAULT 0,
+    points INT DEFAULT 0,
+    had_bye BOOLEAN DEFAULT FALSE,
+    eliminated BOOLEAN DEFAULT FALSE,
+    eliminated_round INT DEFAULT 0,
+    final_placement INT DEFAULT 0,
+    prize_awarded BIGINT DEFAULT 0,
+
+    CONSTRAINT idx_tournament_user UNIQUE (tournament_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_tournament_participants_user ON tournament_participants(user_id);
+
+CREATE TABLE IF NOT EXISTS tournament_matches (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    tournament_id INT NOT NULL REFERENCES tournaments(id) ON DELETE CASCADE,
+
+    round INT NOT NULL,
+    bracket VARCHAR(20) DEFAULT 'MAIN',
+    match_number INT NOT NULL,
+
+    player1_id INT REFERENCES users(id) ON DELETE SET NULL,
+    player2_id INT REFERENCES users(id) ON DELETE SET NULL,
+    battle_id INT REFERENCES battles(id) ON DELETE SET NULL,
+    winner_id INT REFERENCES users(id) ON DELETE SET NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'SCHEDULED', 'COMPLETED', 'WALKOVER', 'BYE')),
+
+    deadline_at TIMESTAMP,
+    completed_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_tournament_matches_tournament ON tournament_matches(tournament_id, round);
+CREATE INDEX IF NOT EXISTS idx_tournament_matches_battle ON tournament_matches(battle_id);
+CREATE INDEX IF NOT EXISTS idx_tournament_matches_status ON tournament_matches(status);
+
+COMMENT ON COLUMN tournaments.payout_table IS 'JSON array of prize pool percentages by final placement; remainder goes to TREASURY';
+COMMENT ON COLUMN tournament_matches.status IS 'PENDING, SCHEDULED (battle created), COMPLETED, WALKOVER (timeout/no team), BYE';
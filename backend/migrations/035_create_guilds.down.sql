@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS guild_raid_contributions;
+DROP TABLE IF EXISTS guild_raids;
+DROP TABLE IF EXISTS guild_withdrawal_requests;
+DROP TABLE IF EXISTS guild_chat_messages;
+DROP TABLE IF EXISTS guild_invites;
+DROP TABLE IF EXISTS guild_members;
+DROP TABLE IF EXISTS guilds;
+DROP INDEX IF EXISTS idx_ledger_accounts_guild_id;
+ALTER TABLE ledger_accounts DROP COLUMN IF EXISTS guild_id;
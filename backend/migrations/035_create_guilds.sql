@@ -0,0 +1,111 @@
+-- Migration: Guilds with shared treasury and guild raids
+-- Description: Guild membership/invites/chat, guild-owned ledger accounts with
+-- withdrawal approvals, and cooperative multi-day guild raids
+
+-- Guild treasuries are ledger accounts owned by a guild instead of a user
+ALTER TABLE ledger_accounts ADD COLUMN IF NOT EXISTS guild_id INT;
+CREATE INDEX IF NOT EXISTS idx_ledger_accounts_guild_id ON ledger_accounts(guild_id);
+
+CREATE TABLE IF NOT EXISTS guilds (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    deleted_at TIMESTAMP,
+
+    name VARCHAR(50) NOT NULL UNIQUE,
+    tag VARCHAR(5) NOT NULL UNIQUE,
+    description VARCHAR(255),
+    leader_id INT NOT NULL REFERENCES users(id),
+    max_members INT DEFAULT 30,
+
+    withdrawal_approvals INT DEFAULT 2,
+    withdrawal_auto_limit BIGINT DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_guilds_leader ON guilds(leader_id);
+CREATE INDEX IF NOT EXISTS idx_guilds_deleted_at ON guilds(deleted_at);
+
+CREATE TABLE IF NOT EXISTS guild_members (
+    id SERIAL PRIMARY KEY,
+    guild_id INT NOT NULL REFERENCES guilds(id) ON DELETE CASCADE,
+    user_id INT NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL DEFAULT 'MEMBER' CHECK (role IN ('LEADER', 'OFFICER', 'MEMBER')),
+    joined_at TIMESTAMP DEFAULT NOW(),
+    contribution BIGINT DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_guild_members_guild ON guild_members(guild_id);
+
+CREATE TABLE IF NOT EXISTS guild_invites (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT NOW(),
+    guild_id INT NOT NULL REFERENCES guilds(id) ON DELETE CASCADE,
+    inviter_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    invitee_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'ACCEPTED', 'DECLINED', 'EXPIRED')),
+    expires_at TIMESTAMP NOT NULL,
+    responded_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_guild_invites_invitee ON guild_invites(invitee_id, status);
+
+CREATE TABLE IF NOT EXISTS guild_chat_messages (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT NOW(),
+    guild_id INT NOT NULL REFERENCES guilds(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    content TEXT NOT NULL,
+    is_system BOOLEAN DEFAULT FALSE
+);
+
+CREATE INDEX IF NOT EXISTS idx_guild_chat_guild ON guild_chat_messages(guild_id, id DESC);
+
+CREATE TABLE IF NOT EXISTS guild_withdrawal_requests (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    guild_id INT NOT NULL REFERENCES guilds(id) ON DELETE CASCADE,
+    requester_id INT NOT NULL REFERENCES users(id),
+    recipient_id INT NOT NULL REFERENCES users(id),
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    reason VARCHAR(255),
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'EXECUTED', 'REJECTED')),
+    approvals TEXT DEFAULT '[]',
+    executed_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_guild_withdrawals_guild ON guild_withdrawal_requests(guild_id, status);
+
+CREATE TABLE IF NOT EXISTS guild_raids (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    guild_id INT NOT NULL REFERENCES guilds(id) ON DELETE CASCADE,
+    raid_boss_id INT NOT NULL REFERENCES raid_bosses(id),
+    started_by INT REFERENCES users(id) ON DELETE SET NULL,
+
+    total_hp BIGINT NOT NULL,
+    remaining_hp BIGINT NOT NULL,
+    reward_pool BIGINT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'ACTIVE' CHECK (status IN ('ACTIVE', 'DEFEATED', 'EXPIRED')),
+
+    ends_at TIMESTAMP NOT NULL,
+    settled_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_guild_raids_guild ON guild_raids(guild_id, status);
+CREATE INDEX IF NOT EXISTS idx_guild_raids_ends_at ON guild_raids(ends_at);
+
+CREATE TABLE IF NOT EXISTS guild_raid_contributions (
+    id SERIAL PRIMARY KEY,
+    guild_raid_id INT NOT NULL REFERENCES guild_raids(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    damage BIGINT DEFAULT 0,
+    attacks INT DEFAULT 0,
+    last_attack_at TIMESTAMP,
+    reward BIGINT DEFAULT 0,
+
+    CONSTRAINT idx_guild_raid_user UNIQUE (guild_raid_id, user_id)
+);
+
+COMMENT ON COLUMN guild_raids.reward_pool IS 'GTK split by damage share; expired raids pay pool * damage dealt / total HP';
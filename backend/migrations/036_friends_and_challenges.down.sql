@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS challenges;
+DROP INDEX IF EXISTS idx_users_display_name;
+ALTER TABLE users DROP COLUMN IF EXISTS last_seen_at;
+ALTER TABLE users DROP COLUMN IF EXISTS display_name;
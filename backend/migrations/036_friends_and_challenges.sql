@@ -0,0 +1,27 @@
+-- Migration: Friends, presence and direct challenges
+-- Description: Display names and last-seen heartbeat on users, plus friend-to-friend
+-- PvP challenges with optional escrowed stakes
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS display_name VARCHAR(32);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS last_seen_at TIMESTAMP;
+CREATE INDEX IF NOT EXISTS idx_users_display_name ON users(LOWER(display_name));
+
+CREATE TABLE IF NOT EXISTS challenges (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    challenger_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    opponent_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    stake BIGINT DEFAULT 0 CHECK (stake >= 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'ACCEPTED', 'DECLINED', 'CANCELLED', 'EXPIRED')),
+    message VARCHAR(140),
+    battle_id INT REFERENCES battles(id) ON DELETE SET NULL,
+    expires_at TIMESTAMP NOT NULL,
+    responded_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_challenges_challenger ON challenges(challenger_id, status);
+CREATE INDEX IF NOT EXISTS idx_challenges_opponent ON challenges(opponent_id, status);
+CREATE INDEX IF NOT EXISTS idx_challenges_expires ON challenges(expires_at) WHERE status = 'PENDING';
+
+COMMENT ON COLUMN challenges.stake IS 'GTK each player puts in escrow; staked challenges are played as wager battles';
@@ -0,0 +1,7 @@
+-- users.referral_code and users.referred_by belong to 001_initial_schema and stay
+DROP TABLE IF EXISTS referral_rewards;
+DROP INDEX IF EXISTS idx_referrals_referrer;
+DROP INDEX IF EXISTS idx_referrals_referred;
+ALTER TABLE referrals DROP COLUMN IF EXISTS flag_reason;
+ALTER TABLE referrals DROP COLUMN IF EXISTS status;
+DROP INDEX IF EXISTS idx_users_referral_code;
@@ -0,0 +1,29 @@
+-- Migration: Referral program
+-- Description: Referral attribution status, anti-abuse flags and per-milestone
+-- reward tracking paid from the REWARD ledger account
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS referral_code VARCHAR(20);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS referred_by INT REFERENCES users(id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_referral_code ON users(referral_code);
+
+ALTER TABLE referrals ADD COLUMN IF NOT EXISTS status VARCHAR(20) DEFAULT 'ACTIVE';
+ALTER TABLE referrals ADD COLUMN IF NOT EXISTS flag_reason VARCHAR(100);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_referrals_referred ON referrals(referred_id);
+CREATE INDEX IF NOT EXISTS idx_referrals_referrer ON referrals(referrer_id);
+
+CREATE TABLE IF NOT EXISTS referral_rewards (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT NOW(),
+    referral_id INT NOT NULL REFERENCES referrals(id) ON DELETE CASCADE,
+    referrer_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    milestone VARCHAR(50) NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount >= 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'HELD', 'PAID')),
+    paid_at TIMESTAMP,
+
+    CONSTRAINT idx_referral_milestone UNIQUE (referral_id, milestone)
+);
+
+CREATE INDEX IF NOT EXISTS idx_referral_rewards_referrer ON referral_rewards(referrer_id, status);
+
+COMMENT ON COLUMN referral_rewards.status IS 'HELD = referral flagged by same-IP / connected-wallet checks, pending admin review';
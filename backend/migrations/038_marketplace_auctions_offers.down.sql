@@ -0,0 +1,12 @@
+-- Expiry dates given to legacy listings are kept
+DROP TABLE IF EXISTS marketplace_offers;
+DROP TABLE IF EXISTS marketplace_bids;
+DROP TABLE IF EXISTS marketplace_assets;
+DROP INDEX IF EXISTS idx_marketplace_listings_expiry;
+DROP INDEX IF EXISTS idx_marketplace_listings_type;
+ALTER TABLE marketplace_listings
+    DROP COLUMN IF EXISTS bid_count,
+    DROP COLUMN IF EXISTS highest_bidder_id,
+    DROP COLUMN IF EXISTS current_bid,
+    DROP COLUMN IF EXISTS reserve_price,
+    DROP COLUMN IF EXISTS listing_type;
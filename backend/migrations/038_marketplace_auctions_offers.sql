@@ -0,0 +1,58 @@
+-- Migration: Marketplace auctions, best offers and bundles
+-- Description: Auction/bundle listing types, bid history, direct offers with
+-- escrowed funds, and a hard expiry on every active listing
+
+ALTER TABLE marketplace_listings ADD COLUMN IF NOT EXISTS listing_type VARCHAR(20) NOT NULL DEFAULT 'FIXED';
+ALTER TABLE marketplace_listings ADD COLUMN IF NOT EXISTS reserve_price BIGINT DEFAULT 0;
+ALTER TABLE marketplace_listings ADD COLUMN IF NOT EXISTS current_bid BIGINT DEFAULT 0;
+ALTER TABLE marketplace_listings ADD COLUMN IF NOT EXISTS highest_bidder_id INT REFERENCES users(id);
+ALTER TABLE marketplace_listings ADD COLUMN IF NOT EXISTS bid_count INT DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_marketplace_listings_type ON marketplace_listings(listing_type);
+CREATE INDEX IF NOT EXISTS idx_marketplace_listings_expiry ON marketplace_listings(status, expires_at);
+
+-- Legacy listings were created without an expiry; give them the default window
+UPDATE marketplace_listings
+SET expires_at = NOW() + INTERVAL '7 days'
+WHERE status = 'ACTIVE' AND (expires_at IS NULL OR expires_at < '1971-01-01');
+
+CREATE TABLE IF NOT EXISTS marketplace_assets (
+    id SERIAL PRIMARY KEY,
+    listing_id INT NOT NULL REFERENCES marketplace_listings(id) ON DELETE CASCADE,
+    asset_type VARCHAR(20) NOT NULL,
+    asset_id INT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_marketplace_assets_listing ON marketplace_assets(listing_id);
+CREATE INDEX IF NOT EXISTS idx_marketplace_assets_asset ON marketplace_assets(asset_type, asset_id);
+
+CREATE TABLE IF NOT EXISTS marketplace_bids (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT NOW(),
+    listing_id INT NOT NULL REFERENCES marketplace_listings(id) ON DELETE CASCADE,
+    bidder_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'ACTIVE' CHECK (status IN ('ACTIVE', 'OUTBID', 'WON', 'REFUNDED'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_marketplace_bids_listing ON marketplace_bids(listing_id, amount DESC);
+
+CREATE TABLE IF NOT EXISTS marketplace_offers (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    buyer_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    seller_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    asset_type VARCHAR(20) NOT NULL,
+    asset_id INT NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    message VARCHAR(140),
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'ACCEPTED', 'REJECTED', 'CANCELLED', 'EXPIRED')),
+    expires_at TIMESTAMP NOT NULL,
+    responded_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_marketplace_offers_seller ON marketplace_offers(seller_id, status);
+CREATE INDEX IF NOT EXISTS idx_marketplace_offers_buyer ON marketplace_offers(buyer_id, status);
+CREATE INDEX IF NOT EXISTS idx_marketplace_offers_asset ON marketplace_offers(asset_type, asset_id, status);
+
+COMMENT ON COLUMN marketplace_listings.reserve_price IS 'Hidden auction minimum; below it the asset returns to the seller and the top bid is refunded';
@@ -0,0 +1,44 @@
+-- Migration: On-chain withdrawal worker
+-- Description: Tracks each TOWER withdrawal through approval, broadcast (nonce,
+-- gas bumps) and confirmation; failed or rejected withdrawals are refunded
+
+CREATE TABLE IF NOT EXISTS withdrawals (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    transaction_id INT NOT NULL UNIQUE REFERENCES transactions(id),
+    to_address VARCHAR(42) NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    status VARCHAR(20) NOT NULL CHECK (status IN ('PENDING_APPROVAL', 'APPROVED', 'BROADCAST', 'CONFIRMED', 'FAILED', 'REJECTED')),
+
+    required_approvals INT DEFAULT 0,
+    first_approver_id INT REFERENCES users(id),
+    second_approver_id INT REFERENCES users(id),
+
+    nonce BIGINT,
+    tx_hash VARCHAR(66),
+    tx_hashes TEXT DEFAULT '[]',
+    gas_price_wei VARCHAR(40),
+    attempts INT DEFAULT 0,
+    broadcast_at TIMESTAMP,
+    confirmed_at TIMESTAMP,
+    last_error VARCHAR(255),
+
+    CONSTRAINT chk_distinct_approvers CHECK (second_approver_id IS NULL OR second_approver_id <> first_approver_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_withdrawals_user ON withdrawals(user_id);
+CREATE INDEX IF NOT EXISTS idx_withdrawals_status ON withdrawals(status);
+
+-- Legacy withdrawals queued before the worker existed go straight to the broadcast queue
+INSERT INTO withdrawals (user_id, transaction_id, to_address, amount, status)
+SELECT t.user_id, t.id, t.metadata::json->>'wallet', ABS(t.amount), 'APPROVED'
+FROM transactions t
+WHERE t.transaction_type = 'WITHDRAWAL'
+  AND t.token_type = 'TOWER'
+  AND t.blockchain_tx_hash IS NULL
+  AND t.metadata IS NOT NULL
+  AND NOT EXISTS (SELECT 1 FROM withdrawals w WHERE w.transaction_id = t.id);
+
+COMMENT ON COLUMN withdrawals.tx_hashes IS 'Every hash broadcast for this nonce, so a mined original is found after a gas bump';
@@ -0,0 +1,26 @@
+-- Migration: Sign-In With Ethereum sessions
+-- Description: Server-side sessions backing rotating refresh tokens; access
+-- tokens carry the session id so revocation (logout, bans) is immediate
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS nonce_issued_at TIMESTAMP;
+
+CREATE TABLE IF NOT EXISTS user_sessions (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    refresh_token_hash VARCHAR(64) NOT NULL UNIQUE,
+    prev_token_hash VARCHAR(64),
+    user_agent VARCHAR(255),
+    ip_address VARCHAR(45),
+    last_used_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP,
+    revoked_by VARCHAR(30)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_sessions_user ON user_sessions(user_id);
+CREATE INDEX IF NOT EXISTS idx_user_sessions_prev ON user_sessions(prev_token_hash);
+CREATE INDEX IF NOT EXISTS idx_user_sessions_active ON user_sessions(user_id, expires_at) WHERE revoked_at IS NULL;
+
+COMMENT ON COLUMN user_sessions.prev_token_hash IS 'Presenting the previous (rotated) refresh token revokes the session as stolen';
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_sprite_jobs_claim;
+ALTER TABLE sprite_generation_jobs DROP COLUMN IF EXISTS next_run_at;
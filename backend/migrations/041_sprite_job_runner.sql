@@ -0,0 +1,7 @@
+-- Migration: Sprite job runner
+-- Description: Backoff scheduling for sprite generation retries; the worker claims
+-- due jobs with FOR UPDATE SKIP LOCKED ordered by created_at
+
+ALTER TABLE sprite_generation_jobs ADD COLUMN IF NOT EXISTS next_run_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_sprite_jobs_claim ON sprite_generation_jobs(status, next_run_at, created_at);
@@ -0,0 +1,3 @@
+-- The duplicate listings cancelled by the up script stay cancelled
+DROP INDEX IF EXISTS idx_unique_active_item_listing;
+DROP INDEX IF EXISTS idx_unique_active_character_listing;
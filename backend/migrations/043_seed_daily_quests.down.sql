@@ -0,0 +1,5 @@
+-- The seeded quests stay: players' progress rows reference them
+DROP INDEX IF EXISTS idx_marketplace_listings_seller;
+DROP INDEX IF EXISTS idx_marketplace_listings_status;
+DROP INDEX IF EXISTS idx_daily_quest_progress_quest;
+DROP INDEX IF EXISTS idx_daily_quest_progress_user_date;
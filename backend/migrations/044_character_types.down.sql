@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_characters_character_type;
+ALTER TABLE characters DROP COLUMN IF EXISTS character_type;
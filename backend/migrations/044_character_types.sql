@@ -0,0 +1,7 @@
+-- Character archetype column (replaces cmd/migrate-character-types)
+ALTER TABLE characters ADD COLUMN IF NOT EXISTS character_type VARCHAR(20) DEFAULT 'BEAST';
+
+UPDATE characters SET character_type = 'BEAST'
+WHERE character_type IS NULL OR character_type = '';
+
+CREATE INDEX IF NOT EXISTS idx_characters_character_type ON characters(character_type);
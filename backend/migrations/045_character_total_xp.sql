@@ -0,0 +1,7 @@
+-- Lifetime XP used for level calculation (replaces cmd/migrate-totalxp)
+ALTER TABLE characters ADD COLUMN IF NOT EXISTS total_xp INTEGER NOT NULL DEFAULT 0;
+
+-- Characters levelled before the column existed get the XP their level implies
+UPDATE characters
+SET total_xp = FLOOR(100 * POWER(level, 2.5))
+WHERE total_xp = 0 AND level > 1;
@@ -0,0 +1,17 @@
+-- Columns that 001_initial_schema and 002_skill_system_phase1 already created stay
+DROP INDEX IF EXISTS idx_char_ability;
+ALTER TABLE character_abilities DROP COLUMN IF EXISTS times_used;
+
+ALTER TABLE abilities
+    DROP COLUMN IF EXISTS ability_type,
+    DROP COLUMN IF EXISTS damage,
+    DROP COLUMN IF EXISTS category,
+    DROP COLUMN IF EXISTS accuracy,
+    DROP COLUMN IF EXISTS priority,
+    DROP COLUMN IF EXISTS base_heal,
+    DROP COLUMN IF EXISTS duration_secs,
+    DROP COLUMN IF EXISTS applies_buff,
+    DROP COLUMN IF EXISTS applies_debuff,
+    DROP COLUMN IF EXISTS animation_name,
+    DROP COLUMN IF EXISTS sound_effect,
+    DROP COLUMN IF EXISTS element_bonuses;
@@ -0,0 +1,29 @@
+-- Bring abilities and character_abilities in line with models.Ability and
+-- models.CharacterAbility (replaces cmd/migrate-abilities)
+ALTER TABLE abilities
+    ADD COLUMN IF NOT EXISTS ability_type VARCHAR(20) NOT NULL DEFAULT 'ACTIVE',
+    ADD COLUMN IF NOT EXISTS damage INTEGER DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS category VARCHAR(20),
+    ADD COLUMN IF NOT EXISTS accuracy INTEGER DEFAULT 100,
+    ADD COLUMN IF NOT EXISTS priority INTEGER DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS max_pp INTEGER DEFAULT 10,
+    ADD COLUMN IF NOT EXISTS base_heal INTEGER DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS duration_secs INTEGER DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS applies_buff VARCHAR(50),
+    ADD COLUMN IF NOT EXISTS applies_debuff VARCHAR(50),
+    ADD COLUMN IF NOT EXISTS animation_name VARCHAR(50),
+    ADD COLUMN IF NOT EXISTS sound_effect VARCHAR(100),
+    ADD COLUMN IF NOT EXISTS element_bonuses JSONB,
+    ADD COLUMN IF NOT EXISTS is_ultimate BOOLEAN DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS synergy_tags TEXT[],
+    ADD COLUMN IF NOT EXISTS required_element TEXT[],
+    ADD COLUMN IF NOT EXISTS required_class TEXT[],
+    ADD COLUMN IF NOT EXISTS damage_type VARCHAR(20) DEFAULT 'physical',
+    ADD COLUMN IF NOT EXISTS aoe_radius INTEGER DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS status_effect_chance INTEGER DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS buff_duration INTEGER DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS debuff_duration INTEGER DEFAULT 0;
+
+ALTER TABLE character_abilities ADD COLUMN IF NOT EXISTS times_used INTEGER DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_char_ability ON character_abilities(character_id, ability_id);
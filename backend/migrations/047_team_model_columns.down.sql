@@ -0,0 +1,21 @@
+-- team_members.position is left as it was; slots are not copied back
+DROP INDEX IF EXISTS idx_team_members_deleted_at;
+DROP INDEX IF EXISTS idx_team_members_character_id;
+DROP INDEX IF EXISTS idx_team_members_team_id;
+ALTER TABLE team_members
+    DROP COLUMN IF EXISTS is_backup,
+    DROP COLUMN IF EXISTS slot,
+    DROP COLUMN IF EXISTS deleted_at,
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS created_at;
+
+DROP INDEX IF EXISTS idx_teams_deleted_at;
+DROP INDEX IF EXISTS idx_teams_is_active;
+DROP INDEX IF EXISTS idx_teams_user_id;
+ALTER TABLE teams ALTER COLUMN name DROP DEFAULT;
+ALTER TABLE teams
+    DROP COLUMN IF EXISTS avg_level,
+    DROP COLUMN IF EXISTS total_power,
+    DROP COLUMN IF EXISTS is_active,
+    DROP COLUMN IF EXISTS deleted_at,
+    DROP COLUMN IF EXISTS updated_at;
@@ -0,0 +1,38 @@
+-- Bring teams and team_members in line with models.Team and models.TeamMember
+-- (replaces cmd/migrate-teams)
+ALTER TABLE teams
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP DEFAULT NOW(),
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS is_active BOOLEAN DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS total_power INTEGER DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS avg_level INTEGER DEFAULT 0;
+
+UPDATE teams SET name = 'My Team' WHERE name IS NULL;
+ALTER TABLE teams ALTER COLUMN name SET DEFAULT 'My Team';
+
+CREATE INDEX IF NOT EXISTS idx_teams_user_id ON teams(user_id);
+CREATE INDEX IF NOT EXISTS idx_teams_is_active ON teams(is_active);
+CREATE INDEX IF NOT EXISTS idx_teams_deleted_at ON teams(deleted_at);
+
+ALTER TABLE team_members
+    ADD COLUMN IF NOT EXISTS created_at TIMESTAMP DEFAULT NOW(),
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP DEFAULT NOW(),
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS slot INTEGER,
+    ADD COLUMN IF NOT EXISTS is_backup BOOLEAN DEFAULT FALSE;
+
+-- The original schema numbered positions 1-5; slots are 0-based (0-2 active, 3-5 backup)
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns
+               WHERE table_name = 'team_members' AND column_name = 'position') THEN
+        UPDATE team_members SET slot = position - 1 WHERE slot IS NULL AND position IS NOT NULL;
+    END IF;
+END $$;
+
+UPDATE team_members SET slot = 0 WHERE slot IS NULL;
+ALTER TABLE team_members ALTER COLUMN slot SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_team_members_team_id ON team_members(team_id);
+CREATE INDEX IF NOT EXISTS idx_team_members_character_id ON team_members(character_id);
+CREATE INDEX IF NOT EXISTS idx_team_members_deleted_at ON team_members(deleted_at);
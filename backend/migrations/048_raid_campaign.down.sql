@@ -0,0 +1,62 @@
+-- Put the campaign tables back in their 001_initial_schema shape. Campaign
+-- progress and raid sessions are lost; the seeded islands are not carried over.
+DROP TABLE IF EXISTS raid_sessions, user_campaign_progress, island_missions, raid_bosses, islands CASCADE;
+
+CREATE TABLE islands (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    difficulty VARCHAR(20),
+    required_level INTEGER DEFAULT 1,
+    reward_gtk DECIMAL(20,2) DEFAULT 0,
+    reward_xp INTEGER DEFAULT 0,
+    island_order INTEGER DEFAULT 0,
+    is_active BOOLEAN DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE raid_bosses (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    max_hp INTEGER NOT NULL,
+    attack INTEGER DEFAULT 10,
+    defense INTEGER DEFAULT 5,
+    speed INTEGER DEFAULT 5,
+    element VARCHAR(20),
+    abilities JSONB,
+    loot_table JSONB,
+    difficulty INTEGER DEFAULT 1,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE raid_sessions (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    island_id INTEGER REFERENCES islands(id),
+    boss_id INTEGER REFERENCES raid_bosses(id),
+    status VARCHAR(20) DEFAULT 'active',
+    current_turn INTEGER DEFAULT 1,
+    player_hp INTEGER,
+    boss_hp INTEGER,
+    started_at TIMESTAMP DEFAULT NOW(),
+    completed_at TIMESTAMP
+);
+
+CREATE INDEX idx_raid_sessions_user ON raid_sessions(user_id);
+
+-- Restore the references dropped together with the campaign tables
+DO $$
+BEGIN
+    IF to_regclass('battle_rewards') IS NOT NULL THEN
+        ALTER TABLE battle_rewards ADD CONSTRAINT battle_rewards_raid_session_id_fkey
+            FOREIGN KEY (raid_session_id) REFERENCES raid_sessions(id) NOT VALID;
+    END IF;
+    IF to_regclass('battle_replays') IS NOT NULL THEN
+        ALTER TABLE battle_replays ADD CONSTRAINT battle_replays_raid_session_id_fkey
+            FOREIGN KEY (raid_session_id) REFERENCES raid_sessions(id) NOT VALID;
+    END IF;
+    IF to_regclass('guild_raids') IS NOT NULL THEN
+        ALTER TABLE guild_raids ADD CONSTRAINT guild_raids_raid_boss_id_fkey
+            FOREIGN KEY (raid_boss_id) REFERENCES raid_bosses(id) NOT VALID;
+    END IF;
+END $$;
@@ -0,0 +1,162 @@
+-- Island campaign tables and seed data (replaces cmd/migrate-raids)
+
+-- 001_initial_schema created islands, raid_bosses and raid_sessions in a shape the
+-- raid service never used. Replace them while they are still in that shape; the
+-- foreign keys that point at them are restored below.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns
+               WHERE table_name = 'islands' AND column_name = 'island_order') THEN
+        DROP TABLE IF EXISTS raid_sessions, raid_bosses, islands CASCADE;
+    END IF;
+END $$;
+
+CREATE TABLE IF NOT EXISTS islands (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    name VARCHAR(50) NOT NULL UNIQUE,
+    description VARCHAR(255),
+    difficulty BIGINT NOT NULL CHECK (difficulty > 0),
+    min_level_req BIGINT DEFAULT 1,
+    image_url VARCHAR(255)
+);
+CREATE INDEX IF NOT EXISTS idx_islands_deleted_at ON islands(deleted_at);
+
+CREATE TABLE IF NOT EXISTS raid_bosses (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    island_id BIGINT NOT NULL REFERENCES islands(id),
+    name VARCHAR(50) NOT NULL,
+    element VARCHAR(20) NOT NULL,
+    character_type VARCHAR(20) NOT NULL,
+    total_hp BIGINT NOT NULL,
+    base_attack BIGINT NOT NULL,
+    base_defense BIGINT NOT NULL,
+    speed BIGINT NOT NULL,
+    rewards_pool TEXT,
+    image_url VARCHAR(255)
+);
+CREATE INDEX IF NOT EXISTS idx_raid_bosses_island_id ON raid_bosses(island_id);
+CREATE INDEX IF NOT EXISTS idx_raid_bosses_deleted_at ON raid_bosses(deleted_at);
+
+CREATE TABLE IF NOT EXISTS island_missions (
+    id BIGSERIAL PRIMARY KEY,
+    island_id BIGINT NOT NULL REFERENCES islands(id),
+    sequence BIGINT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    description VARCHAR(255),
+    enemy_name VARCHAR(100) NOT NULL,
+    enemy_type VARCHAR(20) DEFAULT 'NORMAL',
+    enemy_hp BIGINT NOT NULL,
+    enemy_atk BIGINT NOT NULL,
+    enemy_def BIGINT NOT NULL,
+    enemy_speed BIGINT NOT NULL,
+    enemy_image VARCHAR(255),
+    rewards_pool TEXT
+);
+CREATE INDEX IF NOT EXISTS idx_island_missions_island_id ON island_missions(island_id);
+
+CREATE TABLE IF NOT EXISTS user_campaign_progress (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    island_id BIGINT NOT NULL,
+    highest_sequence BIGINT DEFAULT 0,
+    updated_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_user_campaign_progress_user_id ON user_campaign_progress(user_id);
+CREATE INDEX IF NOT EXISTS idx_user_campaign_progress_island_id ON user_campaign_progress(island_id);
+
+CREATE TABLE IF NOT EXISTS raid_sessions (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    completed_at TIMESTAMPTZ,
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    mission_id BIGINT NOT NULL REFERENCES island_missions(id),
+    boss_id BIGINT REFERENCES raid_bosses(id),
+    team_id BIGINT,
+    status VARCHAR(20) DEFAULT 'IN_PROGRESS',
+    active_character_id BIGINT,
+    character_states TEXT,
+    turn_queue TEXT,
+    current_turn_index BIGINT DEFAULT 0,
+    current_team_hp BIGINT NOT NULL,
+    initial_team_hp BIGINT,
+    current_boss_hp BIGINT NOT NULL,
+    total_hp BIGINT,
+    current_stage BIGINT DEFAULT 1,
+    total_stages BIGINT DEFAULT 1,
+    turn_count BIGINT DEFAULT 0,
+    damage_dealt BIGINT,
+    total_damage_taken BIGINT,
+    tokens_earned BIGINT DEFAULT 0,
+    xp_earned BIGINT DEFAULT 0,
+    performance_grade TEXT,
+    rewards_claimed BOOLEAN DEFAULT FALSE,
+    active_status_effects TEXT,
+    expires_at TIMESTAMPTZ,
+    battle_seed VARCHAR(64),
+    is_verified BOOLEAN DEFAULT FALSE
+);
+CREATE INDEX IF NOT EXISTS idx_raid_sessions_user_id ON raid_sessions(user_id);
+CREATE INDEX IF NOT EXISTS idx_raid_sessions_mission_id ON raid_sessions(mission_id);
+CREATE INDEX IF NOT EXISTS idx_raid_sessions_boss_id ON raid_sessions(boss_id);
+CREATE INDEX IF NOT EXISTS idx_raid_sessions_status ON raid_sessions(status);
+
+-- Restore the references dropped together with the legacy tables
+DO $$
+BEGIN
+    IF to_regclass('battle_rewards') IS NOT NULL AND NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'battle_rewards_raid_session_id_fkey') THEN
+        ALTER TABLE battle_rewards ADD CONSTRAINT battle_rewards_raid_session_id_fkey
+            FOREIGN KEY (raid_session_id) REFERENCES raid_sessions(id) NOT VALID;
+    END IF;
+    IF to_regclass('battle_replays') IS NOT NULL AND NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'battle_replays_raid_session_id_fkey') THEN
+        ALTER TABLE battle_replays ADD CONSTRAINT battle_replays_raid_session_id_fkey
+            FOREIGN KEY (raid_session_id) REFERENCES raid_sessions(id) NOT VALID;
+    END IF;
+    IF to_regclass('guild_raids') IS NOT NULL AND NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'guild_raids_raid_boss_id_fkey') THEN
+        ALTER TABLE guild_raids ADD CONSTRAINT guild_raids_raid_boss_id_fkey
+            FOREIGN KEY (raid_boss_id) REFERENCES raid_bosses(id) NOT VALID;
+    END IF;
+END $$;
+
+-- Seed: three islands, each with a guardian boss and a five-stage mission chain.
+-- Tuned for a team of four doing ~60 damage per turn (rebalance v3); stage 5 is the boss.
+INSERT INTO islands (created_at, updated_at, name, description, difficulty, min_level_req, image_url) VALUES
+(NOW(), NOW(), 'Volcanic Wasteland', 'A scorching realm of fire and ash.', 1, 1, 'img/islands/volcanic.jpg'),
+(NOW(), NOW(), 'Frozen Spire', 'An icy mountain peak shrouded in blizzards.', 2, 25, 'img/islands/frozen.jpg'),
+(NOW(), NOW(), 'Emerald Jungle', 'Dense foliage hides lethal predators.', 3, 50, 'img/islands/jungle.jpg')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO raid_bosses (created_at, updated_at, island_id, name, element, character_type, total_hp, base_attack, base_defense, speed, image_url)
+SELECT NOW(), NOW(), i.id, i.name || ' Guardian', 'FIRE', 'DRAGON',
+       1500 * i.difficulty, 45 * i.difficulty, 30 * i.difficulty, 80, ''
+FROM islands i
+WHERE i.name IN ('Volcanic Wasteland', 'Frozen Spire', 'Emerald Jungle')
+  AND NOT EXISTS (SELECT 1 FROM raid_bosses b WHERE b.island_id = i.id);
+
+INSERT INTO island_missions (island_id, sequence, name, description, enemy_name, enemy_type,
+                             enemy_hp, enemy_atk, enemy_def, enemy_speed, enemy_image, rewards_pool)
+SELECT i.id,
+       s.n,
+       CASE WHEN s.n = 5 THEN i.name || ' - BOSS BATTLE' ELSE i.name || ' - Mission ' || s.n END,
+       'Stage ' || s.n || ' of the expedition.',
+       CASE WHEN s.n = 5 THEN i.name || ' Guardian' ELSE 'Minion ' || s.n END,
+       CASE i.name WHEN 'Volcanic Wasteland' THEN 'FIRE' WHEN 'Frozen Spire' THEN 'ICE' ELSE 'GRASS' END,
+       CASE WHEN s.n = 5 THEN 800 ELSE 200 + (s.n - 1) * 50 END * i.difficulty,
+       CASE WHEN s.n = 5 THEN 70 ELSE 25 + (s.n - 1) * 5 END * i.difficulty,
+       CASE WHEN s.n = 5 THEN 25 ELSE 5 + (s.n - 1) * 3 END * i.difficulty,
+       10 * s.n,
+       '',
+       format('{"tokens": %s, "xp": %s}', 20 + s.n * 10, 50 + s.n * 20)
+FROM islands i
+CROSS JOIN generate_series(1, 5) AS s(n)
+WHERE i.name IN ('Volcanic Wasteland', 'Frozen Spire', 'Emerald Jungle')
+  AND NOT EXISTS (SELECT 1 FROM island_missions m WHERE m.island_id = i.id);
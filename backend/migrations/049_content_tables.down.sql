@@ -0,0 +1,47 @@
+-- Loot tables point back at story missions and owned items, so the rows the
+-- content loader created no longer make sense; like the up script, drop them.
+DROP INDEX IF EXISTS idx_loot_tables_name;
+DELETE FROM loot_entries;
+DELETE FROM loot_tables;
+
+ALTER TABLE loot_tables DROP CONSTRAINT IF EXISTS loot_tables_mission_id_fkey;
+ALTER TABLE loot_tables
+    ADD CONSTRAINT loot_tables_mission_id_fkey FOREIGN KEY (mission_id) REFERENCES missions(id);
+
+ALTER TABLE loot_entries DROP CONSTRAINT IF EXISTS loot_entries_item_id_fkey;
+ALTER TABLE loot_entries
+    ADD CONSTRAINT loot_entries_item_id_fkey FOREIGN KEY (item_id) REFERENCES items(id);
+
+DROP TABLE IF EXISTS ability_learnings;
+DROP TABLE IF EXISTS quest_templates;
+DROP TABLE IF EXISTS story_fragments;
+
+DROP INDEX IF EXISTS idx_story_dialogues_mission_level;
+ALTER TABLE story_dialogues
+    DROP COLUMN IF EXISTS sort_order,
+    DROP COLUMN IF EXISTS audio_file,
+    DROP COLUMN IF EXISTS "character",
+    DROP COLUMN IF EXISTS dialogue_type,
+    DROP COLUMN IF EXISTS mission_level;
+
+-- Missions loaded from content/ have no title; give them their name back
+DROP INDEX IF EXISTS idx_missions_level;
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns
+               WHERE table_name = 'missions' AND column_name = 'title') THEN
+        UPDATE missions SET title = COALESCE(name, 'Mission ' || id) WHERE title IS NULL;
+        ALTER TABLE missions ALTER COLUMN title SET NOT NULL;
+    END IF;
+END $$;
+ALTER TABLE missions
+    DROP COLUMN IF EXISTS rewards,
+    DROP COLUMN IF EXISTS objectives,
+    DROP COLUMN IF EXISTS unlock_feature,
+    DROP COLUMN IF EXISTS story,
+    DROP COLUMN IF EXISTS name,
+    DROP COLUMN IF EXISTS level;
+
+ALTER TABLE system_settings
+    DROP COLUMN IF EXISTS updated_by,
+    DROP COLUMN IF EXISTS type;
@@ -0,0 +1,100 @@
+-- Bring the tables loaded from content/ (see internal/seed) in line with their
+-- models. Abilities are covered by 046, islands and island missions by 048.
+
+-- system_settings: typed values, and the admin who last changed one (0 = never,
+-- the content loader only overwrites those)
+ALTER TABLE system_settings
+    ADD COLUMN IF NOT EXISTS type VARCHAR(20),
+    ADD COLUMN IF NOT EXISTS updated_by INTEGER NOT NULL DEFAULT 0;
+
+-- missions: the story campaign, keyed by level
+ALTER TABLE missions
+    ADD COLUMN IF NOT EXISTS level INTEGER,
+    ADD COLUMN IF NOT EXISTS name VARCHAR(100),
+    ADD COLUMN IF NOT EXISTS story TEXT,
+    ADD COLUMN IF NOT EXISTS unlock_feature VARCHAR(50),
+    ADD COLUMN IF NOT EXISTS objectives JSONB,
+    ADD COLUMN IF NOT EXISTS rewards JSONB;
+
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns
+               WHERE table_name = 'missions' AND column_name = 'title') THEN
+        ALTER TABLE missions ALTER COLUMN title DROP NOT NULL;
+    END IF;
+END $$;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_missions_level ON missions(level);
+
+-- story_dialogues: mission briefings and debriefs
+ALTER TABLE story_dialogues
+    ADD COLUMN IF NOT EXISTS mission_level INTEGER,
+    ADD COLUMN IF NOT EXISTS dialogue_type VARCHAR(20),
+    ADD COLUMN IF NOT EXISTS "character" VARCHAR(50),
+    ADD COLUMN IF NOT EXISTS audio_file VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS sort_order INTEGER DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_story_dialogues_mission_level ON story_dialogues(mission_level);
+
+CREATE TABLE IF NOT EXISTS story_fragments (
+    id SERIAL PRIMARY KEY,
+    fragment_id VARCHAR(50) NOT NULL UNIQUE,
+    title VARCHAR(100) NOT NULL,
+    content TEXT NOT NULL,
+    fragment_type VARCHAR(30),
+    unlock_level INTEGER NOT NULL,
+    unlock_condition JSONB,
+    rarity VARCHAR(10),
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS quest_templates (
+    id SERIAL PRIMARY KEY,
+    type VARCHAR(20) NOT NULL,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    description TEXT NOT NULL,
+    base_target INTEGER NOT NULL,
+    scale_factor DECIMAL(5,2) DEFAULT 0.1,
+    difficulty VARCHAR(20) NOT NULL,
+    action_type VARCHAR(50) NOT NULL,
+    reward_gtk INTEGER DEFAULT 0,
+    reward_tower INTEGER DEFAULT 0,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_quest_templates_type ON quest_templates(type);
+
+CREATE TABLE IF NOT EXISTS ability_learnings (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    deleted_at TIMESTAMP,
+    ability_id INTEGER NOT NULL REFERENCES abilities(id) ON DELETE CASCADE,
+    min_rank VARCHAR(3) NOT NULL,
+    learn_level INTEGER NOT NULL,
+    is_starting BOOLEAN DEFAULT FALSE,
+    is_ultimate BOOLEAN DEFAULT FALSE,
+    prerequisite_ability_id INTEGER REFERENCES abilities(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_ability_learnings_ability_id ON ability_learnings(ability_id);
+CREATE INDEX IF NOT EXISTS idx_ability_learnings_min_rank ON ability_learnings(min_rank);
+CREATE INDEX IF NOT EXISTS idx_ability_learnings_learn_level ON ability_learnings(learn_level);
+CREATE INDEX IF NOT EXISTS idx_ability_learnings_deleted_at ON ability_learnings(deleted_at);
+
+-- Loot tables drop shop items from island missions (the raid session's
+-- mission). 016 pointed them at the story missions and at owned item
+-- instances, so its generated rows are meaningless; content/ recreates them.
+DELETE FROM loot_entries;
+DELETE FROM loot_tables;
+
+ALTER TABLE loot_entries DROP CONSTRAINT IF EXISTS loot_entries_item_id_fkey;
+ALTER TABLE loot_entries
+    ADD CONSTRAINT loot_entries_item_id_fkey FOREIGN KEY (item_id) REFERENCES shop_items(id) ON DELETE CASCADE;
+
+ALTER TABLE loot_tables DROP CONSTRAINT IF EXISTS loot_tables_mission_id_fkey;
+ALTER TABLE loot_tables
+    ADD CONSTRAINT loot_tables_mission_id_fkey FOREIGN KEY (mission_id) REFERENCES island_missions(id) ON DELETE CASCADE;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_loot_tables_name ON loot_tables(name);
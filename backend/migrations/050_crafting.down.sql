@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_items_materials;
+DROP TABLE IF EXISTS crafting_jobs;
+DROP TABLE IF EXISTS salvage_yields;
+DROP TABLE IF EXISTS crafting_recipes;
@@ -0,0 +1,45 @@
+-- Migration: Crafting and salvage
+-- Description: Recipes and salvage yields are loaded from content/ (recipes.yaml);
+-- crafting_jobs is the per-player crafting queue
+
+CREATE TABLE IF NOT EXISTS crafting_recipes (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    name VARCHAR(50) NOT NULL UNIQUE,
+    description TEXT,
+    item_type VARCHAR(20) NOT NULL CHECK (item_type IN ('WEAPON', 'ARMOR', 'ACCESSORY', 'RUNE', 'CONSUMABLE')),
+    item_name VARCHAR(50) NOT NULL,
+    consume_effect VARCHAR(50),
+    gtk_cost BIGINT NOT NULL DEFAULT 0 CHECK (gtk_cost >= 0),
+    success_chance DOUBLE PRECISION NOT NULL CHECK (success_chance > 0 AND success_chance <= 1),
+    craft_minutes INT NOT NULL DEFAULT 0 CHECK (craft_minutes >= 0),
+    rarity_weights JSONB NOT NULL DEFAULT '{}',
+    materials JSONB NOT NULL DEFAULT '[]',
+    is_active BOOLEAN DEFAULT TRUE
+);
+
+CREATE TABLE IF NOT EXISTS salvage_yields (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    item_type VARCHAR(20) NOT NULL UNIQUE,
+    materials JSONB NOT NULL DEFAULT '[]'
+);
+
+CREATE TABLE IF NOT EXISTS crafting_jobs (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    recipe_id INT NOT NULL REFERENCES crafting_recipes(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'CRAFTING' CHECK (status IN ('CRAFTING', 'SUCCEEDED', 'FAILED')),
+    gtk_cost BIGINT NOT NULL DEFAULT 0,
+    completes_at TIMESTAMP NOT NULL,
+    claimed_at TIMESTAMP,
+    result_item_id INT REFERENCES items(id) ON DELETE SET NULL,
+    result_rarity VARCHAR(5)
+);
+
+CREATE INDEX IF NOT EXISTS idx_crafting_jobs_user ON crafting_jobs(user_id, status);
+CREATE INDEX IF NOT EXISTS idx_items_materials ON items(owner_id, name) WHERE item_type = 'MATERIAL';
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS equipment_runes;
+DROP INDEX IF EXISTS idx_equipment_item_unique;
+ALTER TABLE equipment DROP COLUMN IF EXISTS rune_sockets;
@@ -0,0 +1,17 @@
+-- Migration: Equipment slots, enhancement and rune sockets
+-- Description: equipment rows now describe one owned WEAPON/ARMOR/ACCESSORY item
+-- (created the first time it is equipped, enhanced or socketed); runes socket into them
+
+ALTER TABLE equipment
+    ADD COLUMN IF NOT EXISTS rune_sockets INT DEFAULT 0;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_equipment_item_unique ON equipment(item_id);
+
+CREATE TABLE IF NOT EXISTS equipment_runes (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT NOW(),
+    equipment_id INT NOT NULL REFERENCES equipment(id) ON DELETE CASCADE,
+    rune_item_id INT NOT NULL UNIQUE REFERENCES items(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_equipment_runes_equipment ON equipment_runes(equipment_id);
@@ -0,0 +1,15 @@
+-- Migration: Egg care
+-- Description: one row per care window of an egg (calibrate, nurture, stabilize),
+-- recording whether it was performed, by whom, and what it did to the predetermined stats
+
+CREATE TABLE IF NOT EXISTS egg_cares (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT NOW(),
+    egg_id INT NOT NULL REFERENCES eggs(id) ON DELETE CASCADE,
+    action VARCHAR(20) NOT NULL,
+    status VARCHAR(10) NOT NULL,
+    performed_by_id INT REFERENCES users(id) ON DELETE SET NULL,
+    paid_with VARCHAR(50),
+    stat_change JSONB,
+    CONSTRAINT idx_egg_care_action UNIQUE (egg_id, action)
+);
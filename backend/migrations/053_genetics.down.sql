@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_eggs_character;
+
+ALTER TABLE eggs
+    DROP COLUMN IF EXISTS gene_seed,
+    DROP COLUMN IF EXISTS genome;
+
+ALTER TABLE characters
+    DROP COLUMN IF EXISTS generation,
+    DROP COLUMN IF EXISTS genome;
@@ -0,0 +1,14 @@
+-- Migration: Breeding genetics
+-- Description: characters and bred eggs carry a genome of dominant and recessive genes;
+-- eggs keep the seed their genome was inherited with, characters their generation
+
+ALTER TABLE characters
+    ADD COLUMN IF NOT EXISTS genome JSONB,
+    ADD COLUMN IF NOT EXISTS generation INT DEFAULT 0;
+
+ALTER TABLE eggs
+    ADD COLUMN IF NOT EXISTS genome JSONB,
+    ADD COLUMN IF NOT EXISTS gene_seed BIGINT DEFAULT 0;
+
+-- Lineage walks from a character to the egg it hatched from
+CREATE INDEX IF NOT EXISTS idx_eggs_character ON eggs(character_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS character_provenances;
+DROP FUNCTION IF EXISTS character_provenance_append_only();
@@ -0,0 +1,29 @@
+-- Migration: Character provenance
+-- Description: append-only history of every character (origin, hatching, ownership
+-- transfers, NFT minting, level-ups, evolutions and notable battle results)
+
+CREATE TABLE IF NOT EXISTS character_provenances (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT NOW(),
+    character_id INT NOT NULL REFERENCES characters(id),
+    event VARCHAR(20) NOT NULL,
+    from_user_id INT REFERENCES users(id),
+    to_user_id INT REFERENCES users(id),
+    summary VARCHAR(255),
+    data JSONB
+);
+
+CREATE INDEX IF NOT EXISTS idx_character_provenances_character ON character_provenances(character_id, id);
+CREATE INDEX IF NOT EXISTS idx_character_provenances_created_at ON character_provenances(created_at);
+
+-- History is never rewritten
+CREATE OR REPLACE FUNCTION character_provenance_append_only() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'character provenance is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_character_provenance_append_only ON character_provenances;
+CREATE TRIGGER trg_character_provenance_append_only
+    BEFORE UPDATE OR DELETE ON character_provenances
+    FOR EACH ROW EXECUTE FUNCTION character_provenance_append_only();
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS rentals;
+DROP INDEX IF EXISTS idx_characters_controller;
+ALTER TABLE characters DROP COLUMN IF EXISTS controller_id;
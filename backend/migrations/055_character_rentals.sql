@@ -0,0 +1,34 @@
+-- Migration: Character rentals
+-- Description: owners lend characters to other players for a fixed term; the borrower
+-- controls the character (characters.controller_id) while ownership stays put
+
+ALTER TABLE characters
+    ADD COLUMN IF NOT EXISTS controller_id INT REFERENCES users(id);
+
+CREATE INDEX IF NOT EXISTS idx_characters_controller ON characters(controller_id);
+
+CREATE TABLE IF NOT EXISTS rentals (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    character_id INT NOT NULL REFERENCES characters(id),
+    owner_id INT NOT NULL REFERENCES users(id),
+    borrower_id INT REFERENCES users(id),
+    fee BIGINT NOT NULL DEFAULT 0,
+    share_percent INT NOT NULL DEFAULT 0,
+    duration_hours INT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'OFFERED',
+    started_at TIMESTAMP,
+    ends_at TIMESTAMP,
+    ended_at TIMESTAMP,
+    owner_earnings BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_rentals_character ON rentals(character_id);
+CREATE INDEX IF NOT EXISTS idx_rentals_owner ON rentals(owner_id);
+CREATE INDEX IF NOT EXISTS idx_rentals_borrower ON rentals(borrower_id);
+CREATE INDEX IF NOT EXISTS idx_rentals_due ON rentals(status, ends_at);
+
+-- A character is on at most one open offer or active rental
+CREATE UNIQUE INDEX IF NOT EXISTS idx_rentals_open_character ON rentals(character_id)
+    WHERE status IN ('OFFERED', 'ACTIVE');
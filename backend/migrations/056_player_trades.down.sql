@@ -0,0 +1,6 @@
+DELETE FROM anti_cheat_flags WHERE battle_id IS NULL;
+ALTER TABLE anti_cheat_flags ALTER COLUMN battle_id SET NOT NULL;
+DROP INDEX IF EXISTS idx_trade_history_trade_asset;
+ALTER TABLE trade_history DROP COLUMN IF EXISTS trade_id;
+DROP TABLE IF EXISTS trade_assets;
+DROP TABLE IF EXISTS trades;
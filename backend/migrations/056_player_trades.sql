@@ -0,0 +1,43 @@
+-- Migration: Direct player trades
+-- Description: two-sided trade windows with their offered assets; completed trades are
+-- recorded in trade_history (trade_id) and may raise anti-cheat flags without a battle
+
+CREATE TABLE IF NOT EXISTS trades (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    initiator_id INT NOT NULL REFERENCES users(id),
+    counterparty_id INT NOT NULL REFERENCES users(id),
+    initiator_gtk BIGINT NOT NULL DEFAULT 0,
+    counterparty_gtk BIGINT NOT NULL DEFAULT 0,
+    initiator_confirmed BOOLEAN DEFAULT false,
+    counterparty_confirmed BOOLEAN DEFAULT false,
+    status VARCHAR(20) NOT NULL DEFAULT 'OPEN',
+    fee BIGINT NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP NOT NULL,
+    completed_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_trades_initiator ON trades(initiator_id);
+CREATE INDEX IF NOT EXISTS idx_trades_counterparty ON trades(counterparty_id);
+CREATE INDEX IF NOT EXISTS idx_trades_status ON trades(status);
+
+CREATE TABLE IF NOT EXISTS trade_assets (
+    id SERIAL PRIMARY KEY,
+    trade_id INT NOT NULL REFERENCES trades(id) ON DELETE CASCADE,
+    from_user_id INT NOT NULL REFERENCES users(id),
+    asset_type VARCHAR(20) NOT NULL,
+    asset_id INT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_trade_assets_trade ON trade_assets(trade_id);
+
+ALTER TABLE trade_history
+    ADD COLUMN IF NOT EXISTS trade_id INT REFERENCES trades(id);
+
+-- Cooling-off lookups: has this asset changed hands in a trade recently?
+CREATE INDEX IF NOT EXISTS idx_trade_history_trade_asset ON trade_history(item_type, item_id, completed_at)
+    WHERE trade_id IS NOT NULL;
+
+-- Trade reviews raise flags that belong to no battle
+ALTER TABLE anti_cheat_flags ALTER COLUMN battle_id DROP NOT NULL;
@@ -0,0 +1,5 @@
+-- Migration: Character condition lifecycle
+-- Description: counts the revives a character has used; dead characters whose revives
+-- are used up stay dead for good
+
+ALTER TABLE characters ADD COLUMN IF NOT EXISTS revive_count INT NOT NULL DEFAULT 0;
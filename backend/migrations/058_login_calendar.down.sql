@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_login_rewards_user_date;
+CREATE INDEX IF NOT EXISTS idx_login_rewards_user ON login_rewards(user_id);
+ALTER TABLE login_rewards
+    DROP COLUMN IF EXISTS grace_used,
+    DROP COLUMN IF EXISTS streak,
+    DROP COLUMN IF EXISTS claim_date;
+ALTER TABLE users
+    DROP COLUMN IF EXISTS timezone_changed_at,
+    DROP COLUMN IF EXISTS timezone;
+DROP TABLE IF EXISTS login_calendar_days;
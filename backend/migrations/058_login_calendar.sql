@@ -0,0 +1,35 @@
+-- Migration: Daily login calendar
+-- Description: an admin-configurable calendar of daily login rewards; claims are counted
+-- in the player's timezone and at most one is allowed per player and local day
+
+CREATE TABLE IF NOT EXISTS login_calendar_days (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    day_number INT NOT NULL,
+    reward_gtk BIGINT NOT NULL DEFAULT 0,
+    item_id INT REFERENCES shop_items(id),
+    item_quantity INT NOT NULL DEFAULT 0,
+    is_milestone BOOLEAN DEFAULT false,
+    updated_by INT DEFAULT 0
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_login_calendar_days_day_number ON login_calendar_days(day_number);
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    ADD COLUMN IF NOT EXISTS timezone_changed_at TIMESTAMP;
+
+ALTER TABLE login_rewards
+    ADD COLUMN IF NOT EXISTS claim_date VARCHAR(10),
+    ADD COLUMN IF NOT EXISTS streak INT NOT NULL DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS grace_used BOOLEAN DEFAULT false;
+
+-- Earlier claims were stamped in server time; keep the latest per day
+DELETE FROM login_rewards a USING login_rewards b
+    WHERE a.user_id = b.user_id AND a.claimed_at::date = b.claimed_at::date AND a.id < b.id;
+UPDATE login_rewards SET claim_date = to_char(claimed_at, 'YYYY-MM-DD') WHERE claim_date IS NULL;
+ALTER TABLE login_rewards ALTER COLUMN claim_date SET NOT NULL;
+
+DROP INDEX IF EXISTS idx_login_rewards_user;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_login_rewards_user_date ON login_rewards(user_id, claim_date);
@@ -0,0 +1,9 @@
+// Package migrations embeds the versioned SQL schema migrations applied by
+// internal/migrate. Files are named NNN_name.sql, with an optional
+// NNN_name.down.sql that reverses them.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
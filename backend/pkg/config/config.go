@@ -12,6 +12,8 @@ type Config struct {
 	// Server
 	Port        string
 	Environment string
+	// Internal listener for the Prometheus scrape endpoint, kept off the public router
+	MetricsAddr string
 
 	// Database
 	DBHost     string
@@ -20,12 +22,25 @@ type Config struct {
 	DBPassword string
 	DBName     string
 
+	// Apply pending SQL migrations at startup instead of only reporting them
+	MigrateOnStart bool
+
+	// Content profile seeded from backend/content: dev, test or prod
+	ContentProfile string
+
 	// Redis
 	RedisHost     string
 	RedisPassword string
 
 	// JWT
-	JWTSecret string
+	JWTSecret       string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+
+	// Sign-In With Ethereum (EIP-4361)
+	SIWEDomain  string
+	SIWEURI     string
+	SIWEChainID int
 
 	// Blockchain
 	OpBNBTestnetRPC    string
@@ -39,6 +54,11 @@ type Config struct {
 	ItemNFTAddress      string
 	DeployerAddress     string
 
+	// Sprite generation
+	SpriteProvider  string
+	SpriteOutputDir string
+	SpriteBaseURL   string
+
 	// CORS
 	AllowedOrigins []string
 
@@ -60,6 +80,7 @@ func Load() (*Config, error) {
 		// Server
 		Port:        getEnv("PORT", "8080"),
 		Environment: getEnv("ENVIRONMENT", "development"),
+		MetricsAddr: getEnv("METRICS_ADDR", "127.0.0.1:9090"),
 
 		// Database
 		DBHost:     getEnv("DB_HOST", "localhost"),
@@ -68,12 +89,22 @@ func Load() (*Config, error) {
 		DBPassword: getEnv("DB_PASSWORD", "postgres"),
 		DBName:     getEnv("DB_NAME", "tower_defense_dev"),
 
+		MigrateOnStart: getEnv("MIGRATE_ON_START", "false") == "true",
+		ContentProfile: getEnv("CONTENT_PROFILE", ""),
+
 		// Redis
 		RedisHost:     getEnv("REDIS_HOST", "localhost:6379"),
 		RedisPassword: getEnv("REDIS_PASSWORD", ""),
 
 		// JWT
-		JWTSecret: getEnv("JWT_SECRET", ""),
+		JWTSecret:       getEnv("JWT_SECRET", ""),
+		AccessTokenTTL:  time.Duration(getEnvAsInt("ACCESS_TOKEN_TTL_MINUTES", 15)) * time.Minute,
+		RefreshTokenTTL: time.Duration(getEnvAsInt("REFRESH_TOKEN_TTL_DAYS", 30)) * 24 * time.Hour,
+
+		// Sign-In With Ethereum
+		SIWEDomain:  getEnv("SIWE_DOMAIN", "localhost:3000"),
+		SIWEURI:     getEnv("SIWE_URI", "http://localhost:3000"),
+		SIWEChainID: getEnvAsInt("SIWE_CHAIN_ID", 5611), // opBNB testnet
 
 		// Blockchain
 		OpBNBTestnetRPC:    getEnv("OPBNB_TESTNET_RPC", "https://opbnb-testnet-rpc.bnbchain.org"),
@@ -87,6 +118,11 @@ func Load() (*Config, error) {
 		ItemNFTAddress:      getEnv("ITEM_NFT_ADDRESS", "0x8467806e70FbE05Ca5e17f5d316C09F5bD2391bC"),
 		DeployerAddress:     getEnv("DEPLOYER_ADDRESS", "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"),
 
+		// Sprite generation
+		SpriteProvider:  getEnv("SPRITE_PROVIDER", "layered"),
+		SpriteOutputDir: getEnv("SPRITE_OUTPUT_DIR", "./static/sprites"),
+		SpriteBaseURL:   getEnv("SPRITE_BASE_URL", "/sprites"),
+
 		// CORS
 		AllowedOrigins: []string{"http://localhost:3000", "http://localhost:8080"},
 
@@ -100,9 +136,26 @@ func Load() (*Config, error) {
 		MaxTowerFromPvPDaily: getEnvAsInt("MAX_TOWER_FROM_PVP_DAILY", 5000),
 	}
 
+	if cfg.ContentProfile == "" {
+		cfg.ContentProfile = defaultContentProfile(cfg.Environment)
+	}
+
 	return cfg, nil
 }
 
+// defaultContentProfile maps ENVIRONMENT to a content profile when
+// CONTENT_PROFILE is unset
+func defaultContentProfile(environment string) string {
+	switch environment {
+	case "production":
+		return "prod"
+	case "test":
+		return "test"
+	default:
+		return "dev"
+	}
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -1,30 +1,89 @@
 package logger
 
 import (
+	"context"
 	"log"
+	"log/slog"
 	"os"
+	"strings"
 )
 
+// Legacy loggers kept for older call sites; they write through the slog default handler
 var (
 	InfoLogger    *log.Logger
 	WarningLogger *log.Logger
 	ErrorLogger   *log.Logger
 )
 
+type requestIDKey struct{}
+
+// RequestIDKey is the gin context key holding the request ID (see middleware.RequestID)
+const RequestIDKey = "request_id"
+
+// Init installs the slog default handler: JSON in production, text otherwise.
+// LOG_LEVEL selects debug, info (default), warn or error. The standard log package
+// is routed through the same handler, so existing log.Printf calls become structured records.
 func Init() {
-	InfoLogger = log.New(os.Stdout, "INFO: ", log.Ldate|log.Ltime|log.Lshortfile)
-	WarningLogger = log.New(os.Stdout, "WARNING: ", log.Ldate|log.Ltime|log.Lshortfile)
-	ErrorLogger = log.New(os.Stderr, "ERROR: ", log.Ldate|log.Ltime|log.Lshortfile)
+	level := slog.LevelInfo
+	switch strings.ToLower(os.Getenv("LOG_LEVEL")) {
+	case "debug":
+		level = slog.LevelDebug
+	case "warn", "warning":
+		level = slog.LevelWarn
+	case "error":
+		level = slog.LevelError
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	if os.Getenv("ENVIRONMENT") == "production" {
+		handler = slog.NewJSONHandler(os.Stdout, opts)
+	} else {
+		handler = slog.NewTextHandler(os.Stdout, opts)
+	}
+	slog.SetDefault(slog.New(handler))
+
+	InfoLogger = slog.NewLogLogger(handler, slog.LevelInfo)
+	WarningLogger = slog.NewLogLogger(handler, slog.LevelWarn)
+	ErrorLogger = slog.NewLogLogger(handler, slog.LevelError)
 }
 
 func Info(message string) {
-	InfoLogger.Println(message)
+	slog.Info(message)
 }
 
 func Warning(message string) {
-	WarningLogger.Println(message)
+	slog.Warn(message)
 }
 
 func Error(message string) {
-	ErrorLogger.Println(message)
+	slog.Error(message)
+}
+
+// WithRequestID returns a context carrying the request ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID extracts the request ID from a request context or a *gin.Context
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if id, ok := ctx.Value(requestIDKey{}).(string); ok {
+		return id
+	}
+	// *gin.Context resolves string keys from its own key store
+	if id, ok := ctx.Value(RequestIDKey).(string); ok {
+		return id
+	}
+	return ""
+}
+
+// FromContext returns the default logger tagged with the request ID, if any
+func FromContext(ctx context.Context) *slog.Logger {
+	if id := RequestID(ctx); id != "" {
+		return slog.Default().With(slog.String("request_id", id))
+	}
+	return slog.Default()
 }
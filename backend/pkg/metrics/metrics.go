@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry holds every metric served on /metrics
+var Registry = prometheus.NewRegistry()
+
+var (
+	// BattlesStarted counts battles created, by battle_type (pvp, ranked, wager, PVE_ISLAND, ...)
+	BattlesStarted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ctd_battles_started_total",
+		Help: "Battles started by mode.",
+	}, []string{"mode"})
+
+	// BattlesCompleted counts battles that reached a result, by battle_type
+	BattlesCompleted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ctd_battles_completed_total",
+		Help: "Battles completed by mode.",
+	}, []string{"mode"})
+
+	// GachaMints counts eggs minted, by rolled rarity
+	GachaMints = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ctd_gacha_mints_total",
+		Help: "Gacha eggs minted by rarity.",
+	}, []string{"rarity"})
+
+	// AntiCheatFlags counts anti-cheat flags raised, by severity
+	AntiCheatFlags = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ctd_anticheat_flags_total",
+		Help: "Anti-cheat flags raised by severity.",
+	}, []string{"severity"})
+
+	// HTTPRequests counts API requests by route template, method and status
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ctd_http_requests_total",
+		Help: "HTTP requests by route, method and status.",
+	}, []string{"route", "method", "status"})
+
+	// HTTPDuration observes API latency by route template
+	HTTPDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ctd_http_request_duration_seconds",
+		Help:    "HTTP request latency by route.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route", "method"})
+
+	// SpanDuration observes traced DB and chain calls (see pkg/tracing)
+	SpanDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ctd_span_duration_seconds",
+		Help:    "Duration of traced DB and chain operations.",
+		Buckets: []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+	}, []string{"span", "status"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		BattlesStarted,
+		BattlesCompleted,
+		GachaMints,
+		AntiCheatFlags,
+		HTTPRequests,
+		HTTPDuration,
+		SpanDuration,
+	)
+}
+
+// MustRegister adds collectors that need DB access (e.g. ledger volume) from outside this package
+func MustRegister(cs ...prometheus.Collector) {
+	Registry.MustRegister(cs...)
+}
+
+// Handler serves the registry in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
@@ -0,0 +1,94 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"time"
+
+	"github.com/lorengraff/crypto-tower-defense/pkg/logger"
+	"github.com/lorengraff/crypto-tower-defense/pkg/metrics"
+)
+
+// SlowThreshold is the duration above which a finished span is logged at WARN
+var SlowThreshold = 500 * time.Millisecond
+
+type spanKey struct{}
+
+// Span is a lightweight OpenTelemetry-style span: a named, timed operation with attributes,
+// tied to the request ID (trace ID) and to its parent span.
+// Finished spans feed ctd_span_duration_seconds and are logged at DEBUG (WARN when slow or failed).
+type Span struct {
+	Name     string
+	TraceID  string
+	SpanID   string
+	ParentID string
+	start    time.Time
+	attrs    []any
+	ended    bool
+}
+
+// Start opens a span as a child of any span already in ctx
+func Start(ctx context.Context, name string, attrs ...any) (context.Context, *Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	span := &Span{
+		Name:    name,
+		TraceID: logger.RequestID(ctx),
+		SpanID:  newSpanID(),
+		start:   time.Now(),
+		attrs:   attrs,
+	}
+	if parent, ok := ctx.Value(spanKey{}).(*Span); ok {
+		span.ParentID = parent.SpanID
+		if span.TraceID == "" {
+			span.TraceID = parent.TraceID
+		}
+	}
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// SetAttributes adds key/value attributes to the span
+func (s *Span) SetAttributes(attrs ...any) {
+	s.attrs = append(s.attrs, attrs...)
+}
+
+// End finishes the span, recording err as the span status
+func (s *Span) End(err error) {
+	if s == nil || s.ended {
+		return
+	}
+	s.ended = true
+	elapsed := time.Since(s.start)
+
+	status := "ok"
+	if err != nil {
+		status = "error"
+	}
+	metrics.SpanDuration.WithLabelValues(s.Name, status).Observe(elapsed.Seconds())
+
+	level := slog.LevelDebug
+	if err != nil || elapsed > SlowThreshold {
+		level = slog.LevelWarn
+	}
+	args := append([]any{
+		slog.String("span", s.Name),
+		slog.String("trace_id", s.TraceID),
+		slog.String("span_id", s.SpanID),
+		slog.String("parent_id", s.ParentID),
+		slog.Duration("duration", elapsed),
+		slog.String("status", status),
+	}, s.attrs...)
+	if err != nil {
+		args = append(args, slog.String("error", err.Error()))
+	}
+	slog.Default().Log(context.Background(), level, "span", args...)
+}
+
+func newSpanID() string {
+	buf := make([]byte, 8)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -1,7 +1,8 @@
 #!/bin/bash
-echo "🔄 Running all migrations..."
-for file in migrations/*.sql; do
-    echo "Executing: $file"
-    PGPASSWORD=postgres psql -h localhost -U postgres -d crypto_tower_defense -f "$file" 2>&1 | grep -v "already exists" | grep -v "duplicate"
-done
-echo "✅ All migrations executed"
+# Applies pending migrations from backend/migrations through the embedded runner.
+# Extra arguments are passed through, e.g. ./run_migrations.sh -dry-run up
+cd "$(dirname "$0")"
+if [ $# -eq 0 ]; then
+    set -- up
+fi
+go run ./cmd/migrate "$@"
@@ -31,11 +31,11 @@ if [ "$choice" == "1" ]; then
         echo -e "\033[0;34m✨ Creating database...\033[0m"
         createdb tower_defense_dev
         
-        echo -e "\033[0;34m📜 Importing Golden Schema...\033[0m"
-        psql -d tower_defense_dev < backend/migrations/001_initial_schema.sql
+        echo -e "\033[0;34m📜 Applying migrations...\033[0m"
+        cd backend && go run ./cmd/migrate up
         
         echo -e "\033[0;34m🌱 Seeding data...\033[0m"
-        cd backend && go run cmd/seed/main.go
+        go run ./cmd/seed
         
         echo ""
         echo -e "\033[0;32m✅ Reset Complete! System is now consistent.\033[0m"
@@ -46,7 +46,7 @@ if [ "$choice" == "1" ]; then
 elif [ "$choice" == "2" ]; then
     echo ""
     echo -e "\033[0;34m🌱 Running Seeders (Idempotent Check)...\033[0m"
-    cd backend && go run cmd/seed/main.go
+    cd backend && go run ./cmd/seed
     echo ""
     echo -e "\033[0;32m✅ Verification Complete!\033[0m"
 else
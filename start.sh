@@ -96,6 +96,6 @@ echo "   ./stop.sh"
 echo ""
 echo -e "${YELLOW}� Database:${NC}"
 echo "   Already configured in backend/.env"
-echo "   Migrations: psql -d tower_defense_dev < backend/migrations/001_initial_schema.sql"
-echo "   Seed data:  cd backend && go run cmd/seed/main.go"
+echo "   Migrations: cd backend && go run ./cmd/migrate up"
+echo "   Seed data:  cd backend && go run ./cmd/seed"
 echo ""
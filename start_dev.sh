@@ -18,7 +18,7 @@ if ! command -v go &> /dev/null; then
     echo "❌ Go is not installed!"
     exit 1
 fi
-go run cmd/seeder/main.go
+go run ./cmd/seed -backfill-moves
 cd ..
 
 # 3. Start Backend (in background)